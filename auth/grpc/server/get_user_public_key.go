@@ -2,27 +2,35 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"connectrpc.com/connect"
-	"github.com/atomic-blend/backend/shared/models"
 	userv1 "github.com/atomic-blend/backend/grpc/gen/user/v1"
+	"github.com/atomic-blend/backend/shared/models"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // GetUserPublicKey is the gRPC method to retrieve user public key
 func (userGrpcServer *UserGrpcServer) GetUserPublicKey(ctx context.Context, req *connect.Request[userv1.GetUserPublicKeyRequest]) (*connect.Response[userv1.GetUserPublicKeyResponse], error) {
-	user := &models.UserEntity{}
-	
+	var user *models.UserEntity
+	var err error
+
 	if req.Msg.Id != "" {
 		// Call the repository method to get user public key by ID
-		user, _ = userGrpcServer.userRepo.GetByID(ctx, req.Msg.Id)
+		user, err = userGrpcServer.userRepo.GetByID(ctx, req.Msg.Id)
 	} else if req.Msg.Email != "" {
 		// Call the repository method to get user public key by email
-		user, _ = userGrpcServer.userRepo.GetByEmail(ctx, req.Msg.Email)
+		user, err = userGrpcServer.userRepo.GetByEmail(ctx, req.Msg.Email)
 	} else {
 		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("either id or email must be provided"))
 	}
-	
+
+	// only a missing user is not found, callers retry the other failures instead of giving up on the user
+	if err != nil && !isUserNotFound(err) {
+		return nil, connect.NewError(connect.CodeUnavailable, fmt.Errorf("failed to get user public key: %w", err))
+	}
+
 	if user == nil || user.KeySet == nil || user.KeySet.PublicKey == nil {
 		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("user not found or public key not set"))
 	}
@@ -34,3 +42,8 @@ func (userGrpcServer *UserGrpcServer) GetUserPublicKey(ctx context.Context, req
 
 	return connect.NewResponse(resp), nil
 }
+
+// isUserNotFound reports whether a user repository error means that there is no such user
+func isUserNotFound(err error) bool {
+	return errors.Is(err, mongo.ErrNoDocuments) || err.Error() == "user not found" || err.Error() == "invalid ID format"
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/atomic-blend/backend/auth/tests/mocks"
+	userv1 "github.com/atomic-blend/backend/grpc/gen/user/v1"
+	"github.com/atomic-blend/backend/shared/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestGetUserPublicKey(t *testing.T) {
+	lookup := func(userRepo *mocks.MockUserRepository, msg *userv1.GetUserPublicKeyRequest) (*connect.Response[userv1.GetUserPublicKeyResponse], error) {
+		return NewUserGrpcServer(userRepo).GetUserPublicKey(context.Background(), connect.NewRequest(msg))
+	}
+
+	t.Run("Returns the public key of the user", func(t *testing.T) {
+		userRepo := &mocks.MockUserRepository{}
+		userID := primitive.NewObjectID()
+		publicKey := "age1public"
+		userRepo.On("GetByEmail", mock.Anything, "alice@example.com").
+			Return(&models.UserEntity{ID: &userID, KeySet: &models.EncryptionKey{PublicKey: &publicKey}}, nil)
+
+		resp, err := lookup(userRepo, &userv1.GetUserPublicKeyRequest{Email: "alice@example.com"})
+
+		require.NoError(t, err)
+		assert.Equal(t, userID.Hex(), resp.Msg.UserId)
+		assert.Equal(t, publicKey, resp.Msg.PublicKey)
+	})
+
+	t.Run("Unknown email is not found", func(t *testing.T) {
+		userRepo := &mocks.MockUserRepository{}
+		userRepo.On("GetByEmail", mock.Anything, "bob@example.com").Return(nil, mongo.ErrNoDocuments)
+
+		_, err := lookup(userRepo, &userv1.GetUserPublicKeyRequest{Email: "bob@example.com"})
+
+		assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	})
+
+	t.Run("Unknown ID is not found", func(t *testing.T) {
+		userRepo := &mocks.MockUserRepository{}
+		userID := primitive.NewObjectID().Hex()
+		userRepo.On("GetByID", mock.Anything, userID).Return(nil, errors.New("user not found"))
+
+		_, err := lookup(userRepo, &userv1.GetUserPublicKeyRequest{Id: userID})
+
+		assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	})
+
+	t.Run("Failed lookup is unavailable", func(t *testing.T) {
+		userRepo := &mocks.MockUserRepository{}
+		userRepo.On("GetByEmail", mock.Anything, "alice@example.com").Return(nil, context.DeadlineExceeded)
+
+		_, err := lookup(userRepo, &userv1.GetUserPublicKeyRequest{Email: "alice@example.com"})
+
+		assert.Equal(t, connect.CodeUnavailable, connect.CodeOf(err))
+	})
+}
@@ -203,13 +203,17 @@ func processSendMailMessage(message *amqppackage.Delivery, rawMail models.RawMai
 		sendEmailID = "" // Set empty string to indicate no tracking
 	}
 
+	// mails redirected by the mail filter of a user are sent on behalf of the user, the sender of the envelope
+	sender, _ := parsedMessage["sender"].(string)
+
 	// if !isRetry && retryCount > MaxRetries {
 	// 	handlePermanentFailure(message.Body, errors.New("retry_limit_reached"), sendEmailID, retryCount) // No error, just a retry limit reached
 	// 	return nil
 	// }
 
+	// redirected mails are not sent to their To header, their retries are sent to the recipients left
 	recipientsToSend := []any{}
-	if !isRetry && message.Headers["recipients"] != nil {
+	if (!isRetry || sender != "") && message.Headers["recipients"] != nil {
 		// split the recipients by comma
 		parsedRetry := strings.SplitSeq(message.Headers["recipients"].(string), ",")
 		for recipient := range parsedRetry {
@@ -217,15 +221,16 @@ func processSendMailMessage(message *amqppackage.Delivery, rawMail models.RawMai
 		}
 	}
 
-	recipientsToRetry, err := mailsender.SendEmail(rawMail, recipientsToSend)
+	recipientsToRetry, err := mailsender.SendEmailAs(rawMail, sender, recipientsToSend)
 	if err != nil && retryCount < MaxRetries {
 		retryCount++
 		handleTemporaryFailure(message, message.Body, err.Error(), retryCount, recipientsToRetry)
 		return nil
 	} else if err != nil {
-		// Extract From address safely
-		fromAddress := ""
-		if fromHeader, exists := rawMail.Headers["From"]; exists {
+		// Extract From address safely, redirected mails bounce to the user who redirected them
+		// and never to the From header of the original sender
+		fromAddress := sender
+		if fromHeader, exists := rawMail.Headers["From"]; exists && sender == "" {
 			if fromStr, ok := fromHeader.(string); ok {
 				fromAddress = fromStr
 			}
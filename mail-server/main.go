@@ -2,10 +2,13 @@ package main
 
 import (
 	"os"
+	"strconv"
 	"time"
 
 	smtpserver "github.com/atomic-blend/backend/mail-server/smtp-server"
 	"github.com/atomic-blend/backend/mail-server/utils/amqp"
+	userclient "github.com/atomic-blend/backend/shared/grpc/user"
+	rspamdservice "github.com/atomic-blend/backend/shared/services/rspamd"
 	"github.com/emersion/go-smtp"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog"
@@ -38,8 +41,24 @@ func main() {
 	// start the grpc server
 	go startGRPCServer()
 
+	// maximum size of the received messages, in bytes
+	maxMessageBytes := int64(smtpserver.DefaultMaxMessageBytes)
+	if value := os.Getenv("MAIL_MAX_MESSAGE_BYTES"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			log.Fatal().Str("value", value).Msg("Invalid MAIL_MAX_MESSAGE_BYTES")
+		}
+		maxMessageBytes = parsed
+	}
+
+	// the recipients are validated with the user service while receiving mails
+	userClient, err := userclient.NewUserClient()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create user client")
+	}
+
 	// instanciate the smtp backend
-	be := &smtpserver.Backend{}
+	be := smtpserver.NewBackend(userClient, rspamdservice.NewRspamdService(), maxMessageBytes)
 
 	// create the smtp server
 	s := smtp.NewServer(be)
@@ -48,7 +67,7 @@ func main() {
 	s.Domain = host
 	s.WriteTimeout = 10 * time.Second
 	s.ReadTimeout = 10 * time.Second
-	s.MaxMessageBytes = 1024 * 1024
+	s.MaxMessageBytes = maxMessageBytes
 	s.MaxRecipients = 50
 	s.AllowInsecureAuth = true
 
@@ -1,8 +1,13 @@
 package smtpserver
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io"
+
+	"connectrpc.com/connect"
+	userv1 "github.com/atomic-blend/backend/grpc/gen/user/v1"
 )
 
 // MockReader is a mock io.Reader that can simulate errors
@@ -45,3 +50,32 @@ func (m *MockAMQP) GetPublishedMessages() []map[string]interface{} {
 func (m *MockAMQP) ClearPublishedMessages() {
 	m.publishedMessages = nil
 }
+
+// MockUserClient is a mock user client knowing a fixed set of addresses
+type MockUserClient struct {
+	users map[string]string
+	err   error
+	// slow makes the lookups wait for their context to be done
+	slow bool
+}
+
+// GetUserDevices returns no devices
+func (m *MockUserClient) GetUserDevices(ctx context.Context, req *connect.Request[userv1.GetUserDevicesRequest]) (*connect.Response[userv1.GetUserDevicesResponse], error) {
+	return connect.NewResponse(&userv1.GetUserDevicesResponse{}), nil
+}
+
+// GetUserPublicKey returns the user of a known address, or a not found error
+func (m *MockUserClient) GetUserPublicKey(ctx context.Context, req *connect.Request[userv1.GetUserPublicKeyRequest]) (*connect.Response[userv1.GetUserPublicKeyResponse], error) {
+	if m.slow {
+		<-ctx.Done()
+		return nil, connect.NewError(connect.CodeDeadlineExceeded, ctx.Err())
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	userID, ok := m.users[req.Msg.Email]
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("user not found or public key not set"))
+	}
+	return connect.NewResponse(&userv1.GetUserPublicKeyResponse{UserId: userID, PublicKey: "age1test"}), nil
+}
@@ -2,20 +2,79 @@ package smtpserver
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"io"
 	"net"
+	"strings"
 	"time"
 
+	"connectrpc.com/connect"
+	userv1 "github.com/atomic-blend/backend/grpc/gen/user/v1"
 	"github.com/atomic-blend/backend/mail-server/utils/amqp"
+	userclient "github.com/atomic-blend/backend/shared/grpc/user"
+	rspamdinterfaces "github.com/atomic-blend/backend/shared/services/rspamd/interfaces"
 	"github.com/emersion/go-sasl"
 	"github.com/emersion/go-smtp"
 	"github.com/rs/zerolog/log"
 )
 
+// DefaultMaxMessageBytes is the maximum size of a received message when none is configured
+const DefaultMaxMessageBytes = 25 * 1024 * 1024
+
+// recipientLookupTimeout is how long the user service is given to look a recipient up
+const recipientLookupTimeout = 5 * time.Second
+
+var (
+	// errMailboxUnavailable is returned for recipients without a mailbox on this server
+	errMailboxUnavailable = &smtp.SMTPError{
+		Code:         550,
+		EnhancedCode: smtp.EnhancedCode{5, 1, 1},
+		Message:      "Mailbox unavailable",
+	}
+	// errRecipientLookupFailed is returned when recipients cannot be looked up, the sender retrying later
+	errRecipientLookupFailed = &smtp.SMTPError{
+		Code:         451,
+		EnhancedCode: smtp.EnhancedCode{4, 4, 3},
+		Message:      "Temporary failure looking up the recipient, try again later",
+	}
+	// errMessageTooLarge is returned for messages exceeding the maximum message size
+	errMessageTooLarge = &smtp.SMTPError{
+		Code:         552,
+		EnhancedCode: smtp.EnhancedCode{5, 3, 4},
+		Message:      "Message exceeds the maximum message size",
+	}
+	// errNoRecipients is returned for messages without any accepted recipient
+	errNoRecipients = &smtp.SMTPError{
+		Code:         554,
+		EnhancedCode: smtp.EnhancedCode{5, 5, 1},
+		Message:      "No valid recipients",
+	}
+)
+
 // The Backend implements SMTP server methods.
-type Backend struct{}
+type Backend struct {
+	userClient      userclient.Interface
+	rspamdService   rspamdinterfaces.RspamdServiceInterface
+	maxMessageBytes int64
+	// lookupTimeout is how long the user service is given to look a recipient up
+	lookupTimeout time.Duration
+}
+
+// NewBackend creates a backend validating the recipients with the user service and
+// checking the messages with rspamd before accepting them
+func NewBackend(userClient userclient.Interface, rspamdService rspamdinterfaces.RspamdServiceInterface, maxMessageBytes int64) *Backend {
+	if maxMessageBytes <= 0 {
+		maxMessageBytes = DefaultMaxMessageBytes
+	}
+	return &Backend{
+		userClient:      userClient,
+		rspamdService:   rspamdService,
+		maxMessageBytes: maxMessageBytes,
+		lookupTimeout:   recipientLookupTimeout,
+	}
+}
 
 // NewSession is called after client greeting (EHLO, HELO).
 func (bkd *Backend) NewSession(c *smtp.Conn) (smtp.Session, error) {
@@ -32,6 +91,7 @@ func (bkd *Backend) NewSession(c *smtp.Conn) (smtp.Session, error) {
 	queueID := generateQueueID()
 
 	return &Session{
+		backend:  bkd,
 		clientIP: clientIP,
 		hostname: hostname,
 		queueID:  queueID,
@@ -40,6 +100,7 @@ func (bkd *Backend) NewSession(c *smtp.Conn) (smtp.Session, error) {
 
 // A Session is returned after successful login.
 type Session struct {
+	backend  *Backend
 	auth     bool
 	clientIP string
 	// HELO / hostname
@@ -70,6 +131,11 @@ func (s *Session) Mail(from string, opts *smtp.MailOptions) error {
 	// if !s.auth {
 	// 	return smtp.ErrAuthRequired
 	// }
+	// reject the messages announced larger than allowed before they are transferred
+	if opts != nil && opts.Size > s.backend.maxMessageBytes {
+		log.Info().Int64("size", opts.Size).Msgf("Rejecting mail from %s, message too large", from)
+		return errMessageTooLarge
+	}
 	s.from = from
 	log.Info().Msgf("Mail from: %s", from)
 	return nil
@@ -80,15 +146,63 @@ func (s *Session) Rcpt(to string, opts *smtp.RcptOptions) error {
 	// if !s.auth {
 	// 	return smtp.ErrAuthRequired
 	// }
+	// only accept the recipients having a mailbox, so that unknown addresses are rejected
+	// by the sending server instead of bouncing after the message was accepted
+	// a slow lookup fails like an unavailable user service, the sender retrying later
+	to = normalizeAddress(to)
+	ctx, cancel := context.WithTimeout(context.Background(), s.backend.lookupTimeout)
+	defer cancel()
+	_, err := s.backend.userClient.GetUserPublicKey(ctx, &connect.Request[userv1.GetUserPublicKeyRequest]{
+		Msg: &userv1.GetUserPublicKeyRequest{
+			Email: to,
+		},
+	})
+	if err != nil {
+		switch connect.CodeOf(err) {
+		case connect.CodeNotFound:
+			log.Info().Msgf("Rejecting rcpt to: %s, unknown recipient", to)
+			return errMailboxUnavailable
+		default:
+			log.Error().Err(err).Msgf("Failed to look up rcpt to: %s", to)
+			return errRecipientLookupFailed
+		}
+	}
+
 	s.rcpts = append(s.rcpts, to)
 	log.Info().Msgf("Rcpt to: %s", to)
 	return nil
 }
 
+// normalizeAddress lowercases the domain of an address, domains being case-insensitive and the
+// addresses of the users being stored with the lowercase domains of the accounts
+func normalizeAddress(address string) string {
+	address = strings.TrimSpace(address)
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return address
+	}
+	return address[:at] + strings.ToLower(address[at:])
+}
+
 // Data is the handler for the DATA command.
 func (s *Session) Data(r io.Reader) error {
+	if len(s.rcpts) == 0 {
+		return errNoRecipients
+	}
+
 	var buf bytes.Buffer
-	if _, err := io.Copy(&buf, r); err != nil {
+	// read one byte more than allowed to tell a message of the maximum size from a larger one
+	n, err := io.Copy(&buf, io.LimitReader(r, s.backend.maxMessageBytes+1))
+	if err != nil {
+		return err
+	}
+	if n > s.backend.maxMessageBytes {
+		log.Info().Str("queue_id", s.queueID).Msg("Rejecting message, message too large")
+		return errMessageTooLarge
+	}
+
+	// check the message before accepting it, so that spam is refused with a SMTP reply
+	if err := s.checkSpam(buf.Bytes()); err != nil {
 		return err
 	}
 
@@ -2,12 +2,19 @@ package smtpserver
 
 import (
 	"encoding/hex"
+	"errors"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"connectrpc.com/connect"
+	rspamdservice "github.com/atomic-blend/backend/shared/services/rspamd"
+	rspamdclient "github.com/atomic-blend/backend/shared/services/rspamd/client"
 	"github.com/emersion/go-sasl"
+	"github.com/emersion/go-smtp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func init() {
@@ -91,11 +98,44 @@ func TestSession_Mail(t *testing.T) {
 	})
 }
 
+func newTestBackend() (*Backend, *rspamdservice.MockRspamdService) {
+	userClient := &MockUserClient{users: map[string]string{
+		"recipient@example.com": "user-1",
+		"another@example.com":   "user-2",
+		"test@example.com":      "user-3",
+	}}
+	rspamdService := &rspamdservice.MockRspamdService{}
+	return NewBackend(userClient, rspamdService, 1024), rspamdService
+}
+
+func TestSession_MailSize(t *testing.T) {
+	backend, _ := newTestBackend()
+
+	t.Run("accepts a message announced within the limit", func(t *testing.T) {
+		session := &Session{backend: backend}
+
+		err := session.Mail("sender@example.com", &smtp.MailOptions{Size: 1024})
+		assert.NoError(t, err)
+		assert.Equal(t, "sender@example.com", session.from)
+	})
+
+	t.Run("rejects a message announced too large", func(t *testing.T) {
+		session := &Session{backend: backend}
+
+		err := session.Mail("sender@example.com", &smtp.MailOptions{Size: 1025})
+		assert.Equal(t, errMessageTooLarge, err)
+		assert.Empty(t, session.from)
+	})
+}
+
 func TestSession_Rcpt(t *testing.T) {
+	backend, _ := newTestBackend()
+
 	t.Run("successful rcpt to with authentication", func(t *testing.T) {
 		session := &Session{
-			auth: true,
-			user: "username",
+			backend: backend,
+			auth:    true,
+			user:    "username",
 		}
 
 		err := session.Rcpt("recipient@example.com", nil)
@@ -112,7 +152,8 @@ func TestSession_Rcpt(t *testing.T) {
 
 	t.Run("rcpt to without authentication", func(t *testing.T) {
 		session := &Session{
-			auth: false,
+			backend: backend,
+			auth:    false,
 		}
 
 		err := session.Rcpt("recipient@example.com", nil)
@@ -121,54 +162,65 @@ func TestSession_Rcpt(t *testing.T) {
 		assert.Equal(t, "recipient@example.com", session.rcpts[0])
 	})
 
-	t.Run("rcpt to works without any session setup", func(t *testing.T) {
-		session := &Session{}
+	t.Run("unknown recipient is rejected", func(t *testing.T) {
+		session := &Session{backend: backend}
+
+		err := session.Rcpt("unknown@example.com", nil)
+		var smtpErr *smtp.SMTPError
+		assert.True(t, errors.As(err, &smtpErr))
+		assert.Equal(t, 550, smtpErr.Code)
+		assert.Empty(t, session.rcpts)
 
-		err := session.Rcpt("test@example.com", nil)
+		// the known recipients of the session are still accepted
+		err = session.Rcpt("test@example.com", nil)
 		assert.NoError(t, err)
-		assert.Len(t, session.rcpts, 1)
-		assert.Equal(t, "test@example.com", session.rcpts[0])
+		assert.Equal(t, []string{"test@example.com"}, session.rcpts)
 	})
-}
 
-func TestSession_Data(t *testing.T) {
-	t.Run("data processing with read error", func(t *testing.T) {
-		session := &Session{
-			auth: true,
-		}
+	t.Run("domain of the recipient is case-insensitive", func(t *testing.T) {
+		session := &Session{backend: backend}
 
-		// Create a reader that will cause an error
-		errorReader := &MockReader{shouldError: true}
+		err := session.Rcpt("test@Example.COM", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"test@example.com"}, session.rcpts)
+	})
 
-		err := session.Data(errorReader)
-		assert.Error(t, err)
+	t.Run("invalid lookup is a temporary error", func(t *testing.T) {
+		session := &Session{backend: NewBackend(&MockUserClient{err: connect.NewError(connect.CodeInvalidArgument, errors.New("invalid"))}, nil, 0)}
+
+		err := session.Rcpt("recipient@example.com", nil)
+		var smtpErr *smtp.SMTPError
+		assert.True(t, errors.As(err, &smtpErr))
+		assert.Equal(t, 451, smtpErr.Code)
 	})
 
-	t.Run("data processing without recipients causes panic", func(t *testing.T) {
-		// Create session without recipients (authentication is no longer required)
-		session := &Session{
-			auth:     false, // Authentication is no longer required
-			user:     "",    // No user needed
-			clientIP: "192.168.1.1",
-			hostname: "test.example.com",
-			queueID:  "test-queue-id",
-			from:     "sender@example.com",
-			rcpts:    []string{}, // Empty recipients
-		}
+	t.Run("lookup failure is a temporary error", func(t *testing.T) {
+		session := &Session{backend: NewBackend(&MockUserClient{err: connect.NewError(connect.CodeUnavailable, errors.New("unavailable"))}, nil, 0)}
 
-		// Create test data
-		testData := "Test email content\r\n"
-		reader := strings.NewReader(testData)
+		err := session.Rcpt("recipient@example.com", nil)
+		var smtpErr *smtp.SMTPError
+		assert.True(t, errors.As(err, &smtpErr))
+		assert.Equal(t, 451, smtpErr.Code)
+		assert.Empty(t, session.rcpts)
+	})
 
-		// This should panic because s.rcpts[0] is accessed without bounds checking
-		assert.Panics(t, func() {
-			session.Data(reader)
-		})
+	t.Run("slow lookup is a temporary error", func(t *testing.T) {
+		slowBackend := NewBackend(&MockUserClient{slow: true}, nil, 0)
+		slowBackend.lookupTimeout = 10 * time.Millisecond
+		session := &Session{backend: slowBackend}
+
+		err := session.Rcpt("recipient@example.com", nil)
+		var smtpErr *smtp.SMTPError
+		assert.True(t, errors.As(err, &smtpErr))
+		assert.Equal(t, 451, smtpErr.Code)
+		assert.Empty(t, session.rcpts)
 	})
+}
 
-	t.Run("data processing with valid recipients", func(t *testing.T) {
-		// Create session with recipients (authentication is no longer required)
-		session := &Session{
+func TestSession_Data(t *testing.T) {
+	newSession := func(backend *Backend) *Session {
+		return &Session{
+			backend:  backend,
 			auth:     false, // Authentication is no longer required
 			user:     "",    // No user needed since auth is not required
 			clientIP: "192.168.1.1",
@@ -177,14 +229,99 @@ func TestSession_Data(t *testing.T) {
 			from:     "sender@example.com",
 			rcpts:    []string{"recipient@example.com"},
 		}
+	}
 
-		// Create test data
-		testData := "Test email content\r\n"
-		reader := strings.NewReader(testData)
+	t.Run("data processing with read error", func(t *testing.T) {
+		backend, _ := newTestBackend()
+		session := newSession(backend)
+
+		// Create a reader that will cause an error
+		errorReader := &MockReader{shouldError: true}
+
+		err := session.Data(errorReader)
+		assert.Error(t, err)
+	})
+
+	t.Run("data processing without recipients is rejected", func(t *testing.T) {
+		backend, rspamdService := newTestBackend()
+		session := newSession(backend)
+		session.rcpts = []string{}
+
+		err := session.Data(strings.NewReader("Test email content\r\n"))
+		assert.Equal(t, errNoRecipients, err)
+		rspamdService.AssertNotCalled(t, "CheckMessage", mock.Anything)
+	})
+
+	t.Run("data processing with valid recipients", func(t *testing.T) {
+		backend, rspamdService := newTestBackend()
+		rspamdService.On("CheckMessage", mock.MatchedBy(func(req *rspamdclient.CheckRequest) bool {
+			return string(req.Message) == "Test email content\r\n" &&
+				req.From == "sender@example.com" &&
+				req.IP == "192.168.1.1" &&
+				req.DeliverTo == "recipient@example.com"
+		})).Return(&rspamdclient.CheckResponse{Action: "no action"}, nil)
+		session := newSession(backend)
 
-		// This should not panic with valid recipients in test environment
 		// The AMQP publishing is skipped in test environment
-		err := session.Data(reader)
+		err := session.Data(strings.NewReader("Test email content\r\n"))
+		assert.NoError(t, err)
+		rspamdService.AssertExpectations(t)
+	})
+
+	t.Run("message too large is rejected", func(t *testing.T) {
+		backend, rspamdService := newTestBackend()
+		session := newSession(backend)
+
+		err := session.Data(strings.NewReader(strings.Repeat("a", 1025)))
+		assert.Equal(t, errMessageTooLarge, err)
+		rspamdService.AssertNotCalled(t, "CheckMessage", mock.Anything)
+	})
+
+	t.Run("message of the maximum size is accepted", func(t *testing.T) {
+		backend, rspamdService := newTestBackend()
+		rspamdService.On("CheckMessage", mock.Anything).Return(&rspamdclient.CheckResponse{Action: "no action"}, nil)
+		session := newSession(backend)
+
+		err := session.Data(strings.NewReader(strings.Repeat("a", 1024)))
+		assert.NoError(t, err)
+	})
+
+	t.Run("rspamd verdicts", func(t *testing.T) {
+		tests := []struct {
+			action string
+			code   int
+		}{
+			{"reject", 550},
+			{"soft reject", 450},
+			{"greylist", 451},
+			{"add header", 0},
+			{"rewrite subject", 0},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.action, func(t *testing.T) {
+				backend, rspamdService := newTestBackend()
+				rspamdService.On("CheckMessage", mock.Anything).Return(&rspamdclient.CheckResponse{Action: tt.action}, nil)
+				session := newSession(backend)
+
+				err := session.Data(strings.NewReader("Test email content\r\n"))
+				if tt.code == 0 {
+					assert.NoError(t, err)
+					return
+				}
+				var smtpErr *smtp.SMTPError
+				assert.True(t, errors.As(err, &smtpErr))
+				assert.Equal(t, tt.code, smtpErr.Code)
+			})
+		}
+	})
+
+	t.Run("message is accepted when rspamd is unavailable", func(t *testing.T) {
+		backend, rspamdService := newTestBackend()
+		rspamdService.On("CheckMessage", mock.Anything).Return(nil, errors.New("connection refused"))
+		session := newSession(backend)
+
+		err := session.Data(strings.NewReader("Test email content\r\n"))
 		assert.NoError(t, err)
 	})
 }
@@ -0,0 +1,66 @@
+package smtpserver
+
+import (
+	rspamdclient "github.com/atomic-blend/backend/shared/services/rspamd/client"
+	"github.com/emersion/go-smtp"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// errRejectedAsSpam is returned for the messages rspamd rejects
+	errRejectedAsSpam = &smtp.SMTPError{
+		Code:         550,
+		EnhancedCode: smtp.EnhancedCode{5, 7, 1},
+		Message:      "Message rejected as spam",
+	}
+	// errSoftRejected is returned for the messages rspamd temporarily rejects, e.g. when rate limited
+	errSoftRejected = &smtp.SMTPError{
+		Code:         450,
+		EnhancedCode: smtp.EnhancedCode{4, 7, 1},
+		Message:      "Message temporarily rejected, try again later",
+	}
+	// errGreylisted is returned for the messages rspamd greylists, which are accepted when sent again later
+	errGreylisted = &smtp.SMTPError{
+		Code:         451,
+		EnhancedCode: smtp.EnhancedCode{4, 7, 1},
+		Message:      "Greylisted, try again later",
+	}
+)
+
+// checkSpam checks the message with rspamd and returns the SMTP error rejecting it, if any.
+// Messages are accepted when rspamd cannot be reached, the mail worker checking them again.
+func (s *Session) checkSpam(message []byte) error {
+	checkResponse, err := s.backend.rspamdService.CheckMessage(&rspamdclient.CheckRequest{
+		Message:   message,
+		IP:        s.clientIP,
+		Helo:      s.hostname,
+		Hostname:  s.hostname,
+		From:      s.from,
+		Rcpt:      s.rcpts,
+		QueueID:   s.queueID,
+		User:      s.user,
+		DeliverTo: s.rcpts[0],
+	})
+	if err != nil {
+		log.Error().Err(err).Str("queue_id", s.queueID).Msg("Failed to check message with Rspamd, accepting it")
+		return nil
+	}
+
+	log.Info().
+		Str("queue_id", s.queueID).
+		Str("action", checkResponse.Action).
+		Float64("score", checkResponse.Score).
+		Float64("required_score", checkResponse.RequiredScore).
+		Msg("Rspamd check completed")
+
+	switch checkResponse.Action {
+	case "reject":
+		return errRejectedAsSpam
+	case "soft reject":
+		return errSoftRejected
+	case "greylist":
+		return errGreylisted
+	default:
+		return nil
+	}
+}
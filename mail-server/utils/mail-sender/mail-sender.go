@@ -23,10 +23,17 @@ import (
 // Optionally, The email is sent to the given recipients instead of the To header
 // The email is sent with the given subject, text content, and html content
 func SendEmail(mail models.RawMail, recipients []any) ([]string, error) {
-	log.Info().Interface("To", mail.Headers["To"]).Interface("From", mail.Headers["From"]).Msg("Sending email")
+	return SendEmailAs(mail, "", recipients)
+}
+
+// SendEmailAs sends an email like SendEmail, with the given envelope sender instead of the From header
+// when it is not empty. The email is then signed with DKIM for the domain of the sender, so that the mails
+// redirected on behalf of a user pass SPF and DKIM whatever the domain of their From header
+func SendEmailAs(mail models.RawMail, sender string, recipients []any) ([]string, error) {
+	log.Info().Interface("To", mail.Headers["To"]).Interface("From", mail.Headers["From"]).Str("sender", sender).Msg("Sending email")
 
 	// Sign the email with DKIM first
-	signedEmail, err := signEmailWithDKIM(mail)
+	signedEmail, err := signEmailWithDKIM(mail, sender)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to process email for sending")
 		return []string{}, err
@@ -92,6 +99,9 @@ func SendEmail(mail models.RawMail, recipients []any) ([]string, error) {
 		log.Info().Str("recipient", recipient).Str("domain", domain).Str("mx_host", mxRecords[0].Host).Msg("Resolved MX record")
 
 		from, ok := mail.Headers["From"].(string)
+		if sender != "" {
+			from, ok = sender, true
+		}
 		if !ok {
 			log.Error().Str("recipient", recipient).Msg("From header not found")
 			recipientsToRetry = append(recipientsToRetry, recipient)
@@ -145,8 +155,9 @@ func SendEmail(mail models.RawMail, recipients []any) ([]string, error) {
 	return nil, nil
 }
 
-// signEmailWithDKIM signs the email with DKIM if a private key is available
-func signEmailWithDKIM(rawMail models.RawMail) (string, error) {
+// signEmailWithDKIM signs the email with DKIM if a private key is available,
+// for the domain of the sender or of the From header when there is no sender
+func signEmailWithDKIM(rawMail models.RawMail, sender string) (string, error) {
 	// Convert mail to proper message format once
 	msg, err := rawMail.ToMessageEntity()
 	if err != nil {
@@ -161,6 +172,9 @@ func signEmailWithDKIM(rawMail models.RawMail) (string, error) {
 
 	// Get the From domain for DKIM signing
 	fromHeader, ok := rawMail.Headers["From"].(string)
+	if sender != "" {
+		fromHeader, ok = sender, true
+	}
 	if !ok {
 		return "", fmt.Errorf("from_header_missing")
 	}
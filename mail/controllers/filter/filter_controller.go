@@ -0,0 +1,48 @@
+// Package filter is a package that contains the mail filter controller
+package filter
+
+import (
+	"github.com/atomic-blend/backend/mail/repositories"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MaxScriptSize is the maximum size in bytes of a mail filter script
+const MaxScriptSize = 64 * 1024
+
+// Controller handles mail filter related operations
+type Controller struct {
+	mailFilterRepo repositories.MailFilterRepositoryInterface
+}
+
+// NewFilterController creates a new mail filter controller instance
+func NewFilterController(mailFilterRepo repositories.MailFilterRepositoryInterface) *Controller {
+	return &Controller{
+		mailFilterRepo: mailFilterRepo,
+	}
+}
+
+// SetupRoutes sets up the mail filter routes
+func SetupRoutes(router *gin.Engine, database *mongo.Database) {
+	mailFilterRepo := repositories.NewMailFilterRepository(database)
+	filterController := NewFilterController(mailFilterRepo)
+	setupFilterRoutes(router, filterController)
+}
+
+// SetupRoutesWithMock sets up the mail filter routes with a mock repository for testing
+func SetupRoutesWithMock(router *gin.Engine, mailFilterRepo repositories.MailFilterRepositoryInterface) {
+	filterController := NewFilterController(mailFilterRepo)
+	setupFilterRoutes(router, filterController)
+}
+
+// setupFilterRoutes sets up the routes for mail filter controller
+func setupFilterRoutes(router *gin.Engine, filterController *Controller) {
+	filterRoutes := router.Group("/mail/filter")
+	auth.RequireAuth(filterRoutes)
+	{
+		filterRoutes.GET("", filterController.GetFilter)
+		filterRoutes.PUT("", filterController.UpdateFilter)
+	}
+}
@@ -0,0 +1,142 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/mail/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func setupRouter(mockRepo *mocks.MockMailFilterRepository, userID *primitive.ObjectID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	controller := NewFilterController(mockRepo)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID != nil {
+			c.Set("authUser", &auth.UserAuthInfo{UserID: *userID})
+		}
+		c.Next()
+	})
+	router.GET("/mail/filter", controller.GetFilter)
+	router.PUT("/mail/filter", controller.UpdateFilter)
+	return router
+}
+
+func request(router *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req, _ := http.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetFilter(t *testing.T) {
+	t.Run("returns the filter of the user", func(t *testing.T) {
+		mockRepo := &mocks.MockMailFilterRepository{}
+		userID := primitive.NewObjectID()
+		mockRepo.On("GetByUserID", mock.Anything, userID).Return(&models.MailFilter{UserID: userID, Script: "keep;", Enabled: true}, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodGet, "/mail/filter", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.MailFilter
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "keep;", response.Script)
+		assert.True(t, response.Enabled)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("returns an empty filter when the user has none", func(t *testing.T) {
+		mockRepo := &mocks.MockMailFilterRepository{}
+		userID := primitive.NewObjectID()
+		mockRepo.On("GetByUserID", mock.Anything, userID).Return(nil, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodGet, "/mail/filter", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.MailFilter
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Empty(t, response.Script)
+		assert.False(t, response.Enabled)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := &mocks.MockMailFilterRepository{}
+		userID := primitive.NewObjectID()
+		mockRepo.On("GetByUserID", mock.Anything, userID).Return(nil, errors.New("database error"))
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodGet, "/mail/filter", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		w := request(setupRouter(&mocks.MockMailFilterRepository{}, nil), http.MethodGet, "/mail/filter", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestUpdateFilter(t *testing.T) {
+	t.Run("saves a valid script", func(t *testing.T) {
+		mockRepo := &mocks.MockMailFilterRepository{}
+		userID := primitive.NewObjectID()
+		script := "require \"fileinto\";\nfileinto \"Archive\";"
+		mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(filter *models.MailFilter) bool {
+			return filter.UserID == userID && filter.Script == script && filter.Enabled
+		})).Return(&models.MailFilter{UserID: userID, Script: script, Enabled: true}, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPut, "/mail/filter", UpdateFilterRequest{Script: script, Enabled: true})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects an invalid script with its line", func(t *testing.T) {
+		mockRepo := &mocks.MockMailFilterRepository{}
+		userID := primitive.NewObjectID()
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPut, "/mail/filter", UpdateFilterRequest{Script: "keep;\nfileinto \"Archive\";", Enabled: true})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "line 2")
+		mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a script too large", func(t *testing.T) {
+		mockRepo := &mocks.MockMailFilterRepository{}
+		userID := primitive.NewObjectID()
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPut, "/mail/filter", UpdateFilterRequest{Script: "#" + strings.Repeat("a", MaxScriptSize), Enabled: true})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := &mocks.MockMailFilterRepository{}
+		userID := primitive.NewObjectID()
+		mockRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPut, "/mail/filter", UpdateFilterRequest{Script: "keep;"})
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,39 @@
+package filter
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFilter retrieves the mail filter of the authenticated user
+// @Summary Get mail filter
+// @Description Get the Sieve script filtering the incoming mails of the authenticated user, an empty script when none is set
+// @Tags MailFilter
+// @Produce json
+// @Success 200 {object} models.MailFilter
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/filter [get]
+func (c *Controller) GetFilter(ctx *gin.Context) {
+	// Get authenticated user from context
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	filter, err := c.mailFilterRepo.GetByUserID(ctx, authUser.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if filter == nil {
+		filter = &models.MailFilter{UserID: authUser.UserID}
+	}
+
+	ctx.JSON(http.StatusOK, filter)
+}
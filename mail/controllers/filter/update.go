@@ -0,0 +1,67 @@
+package filter
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/mail/utils/sieve"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateFilterRequest is the body of a mail filter update
+type UpdateFilterRequest struct {
+	Script  string `json:"script"`
+	Enabled bool   `json:"enabled"`
+}
+
+// UpdateFilter replaces the mail filter of the authenticated user
+// @Summary Update mail filter
+// @Description Replace the Sieve script filtering the incoming mails of the authenticated user. The script is validated and rejected with the line of the first error.
+// @Tags MailFilter
+// @Accept json
+// @Produce json
+// @Param filter body UpdateFilterRequest true "Filter"
+// @Success 200 {object} models.MailFilter
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/filter [put]
+func (c *Controller) UpdateFilter(ctx *gin.Context) {
+	// Get authenticated user from context
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var req UpdateFilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(req.Script) > MaxScriptSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Script exceeds %d bytes", MaxScriptSize)})
+		return
+	}
+
+	if _, err := sieve.Parse(req.Script); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid script: " + err.Error()})
+		return
+	}
+
+	filter, err := c.mailFilterRepo.Upsert(ctx, &models.MailFilter{
+		UserID:  authUser.UserID,
+		Script:  req.Script,
+		Enabled: req.Enabled,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, filter)
+}
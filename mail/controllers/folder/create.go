@@ -0,0 +1,53 @@
+package folder
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateFolder creates a mail folder
+// @Summary Create mail folder
+// @Description Create a mail folder, optionally inside another folder of the user
+// @Tags MailFolder
+// @Accept json
+// @Produce json
+// @Param folder body models.Folder true "Folder"
+// @Success 201 {object} models.Folder
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/folders [post]
+func (c *Controller) CreateFolder(ctx *gin.Context) {
+	// Get authenticated user from context
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var folder models.Folder
+	if err := ctx.ShouldBindJSON(&folder); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if folder.ParentID != nil && c.getOwnedFolder(ctx, authUser.UserID, *folder.ParentID) == nil {
+		return
+	}
+
+	folder.ID = nil
+	folder.UserID = authUser.UserID
+
+	createdFolder, err := c.folderRepo.Create(ctx, &folder)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, createdFolder)
+}
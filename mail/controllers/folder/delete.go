@@ -0,0 +1,47 @@
+package folder
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeleteFolder deletes a mail folder
+// @Summary Delete mail folder
+// @Description Delete a mail folder of the authenticated user. Its mails move back to the inbox and its subfolders to its parent.
+// @Tags MailFolder
+// @Param id path string true "Folder ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/folders/{id} [delete]
+func (c *Controller) DeleteFolder(ctx *gin.Context) {
+	// Get authenticated user from context
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	folderID, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return
+	}
+
+	if c.getOwnedFolder(ctx, authUser.UserID, folderID) == nil {
+		return
+	}
+
+	if err := c.folderRepo.Delete(ctx, folderID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
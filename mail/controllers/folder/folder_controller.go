@@ -0,0 +1,47 @@
+// Package folder is a package that contains the mail folder controller
+package folder
+
+import (
+	"github.com/atomic-blend/backend/mail/repositories"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Controller handles mail folder related operations
+type Controller struct {
+	folderRepo repositories.FolderRepositoryInterface
+}
+
+// NewFolderController creates a new mail folder controller instance
+func NewFolderController(folderRepo repositories.FolderRepositoryInterface) *Controller {
+	return &Controller{
+		folderRepo: folderRepo,
+	}
+}
+
+// SetupRoutes sets up the mail folder routes
+func SetupRoutes(router *gin.Engine, database *mongo.Database) {
+	folderRepo := repositories.NewFolderRepository(database)
+	folderController := NewFolderController(folderRepo)
+	setupFolderRoutes(router, folderController)
+}
+
+// SetupRoutesWithMock sets up the mail folder routes with a mock repository for testing
+func SetupRoutesWithMock(router *gin.Engine, folderRepo repositories.FolderRepositoryInterface) {
+	folderController := NewFolderController(folderRepo)
+	setupFolderRoutes(router, folderController)
+}
+
+// setupFolderRoutes sets up the routes for mail folder controller
+func setupFolderRoutes(router *gin.Engine, folderController *Controller) {
+	folderRoutes := router.Group("/mail/folders")
+	auth.RequireAuth(folderRoutes)
+	{
+		folderRoutes.GET("", folderController.GetAllFolders)
+		folderRoutes.POST("", folderController.CreateFolder)
+		folderRoutes.PUT("/:id", folderController.UpdateFolder)
+		folderRoutes.DELETE("/:id", folderController.DeleteFolder)
+	}
+}
@@ -0,0 +1,230 @@
+package folder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/mail/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func setupRouter(mockRepo *mocks.MockFolderRepository, userID *primitive.ObjectID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	controller := NewFolderController(mockRepo)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID != nil {
+			c.Set("authUser", &auth.UserAuthInfo{UserID: *userID})
+		}
+		c.Next()
+	})
+	router.GET("/mail/folders", controller.GetAllFolders)
+	router.POST("/mail/folders", controller.CreateFolder)
+	router.PUT("/mail/folders/:id", controller.UpdateFolder)
+	router.DELETE("/mail/folders/:id", controller.DeleteFolder)
+	return router
+}
+
+func createTestFolder(userID primitive.ObjectID, parentID *primitive.ObjectID) *models.Folder {
+	id := primitive.NewObjectID()
+	return &models.Folder{ID: &id, Name: "Receipts", UserID: userID, ParentID: parentID}
+}
+
+func request(router *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req, _ := http.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetAllFolders(t *testing.T) {
+	t.Run("lists the folders of the user", func(t *testing.T) {
+		mockRepo := &mocks.MockFolderRepository{}
+		userID := primitive.NewObjectID()
+		folders := []*models.Folder{createTestFolder(userID, nil)}
+		mockRepo.On("GetAll", mock.Anything, userID).Return(folders, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodGet, "/mail/folders", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []models.Folder
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("no folders is an empty list", func(t *testing.T) {
+		mockRepo := &mocks.MockFolderRepository{}
+		userID := primitive.NewObjectID()
+		mockRepo.On("GetAll", mock.Anything, userID).Return(nil, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodGet, "/mail/folders", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, "[]", w.Body.String())
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		w := request(setupRouter(&mocks.MockFolderRepository{}, nil), http.MethodGet, "/mail/folders", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestCreateFolder(t *testing.T) {
+	t.Run("creates the folder for the user", func(t *testing.T) {
+		mockRepo := &mocks.MockFolderRepository{}
+		userID := primitive.NewObjectID()
+		parent := createTestFolder(userID, nil)
+
+		var created *models.Folder
+		mockRepo.On("GetByID", mock.Anything, *parent.ID).Return(parent, nil)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Folder")).
+			Run(func(args mock.Arguments) { created = args.Get(1).(*models.Folder) }).
+			Return(createTestFolder(userID, parent.ID), nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPost, "/mail/folders", gin.H{
+			"name":     "Invoices",
+			"parentId": parent.ID.Hex(),
+			"userId":   primitive.NewObjectID().Hex(),
+		})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "Invoices", created.Name)
+		assert.Equal(t, userID, created.UserID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("parent of another user", func(t *testing.T) {
+		mockRepo := &mocks.MockFolderRepository{}
+		userID := primitive.NewObjectID()
+		parent := createTestFolder(primitive.NewObjectID(), nil)
+		mockRepo.On("GetByID", mock.Anything, *parent.ID).Return(parent, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPost, "/mail/folders", gin.H{
+			"name":     "Invoices",
+			"parentId": parent.ID.Hex(),
+		})
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		w := request(setupRouter(&mocks.MockFolderRepository{}, &userID), http.MethodPost, "/mail/folders", gin.H{})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUpdateFolder(t *testing.T) {
+	t.Run("renames the folder", func(t *testing.T) {
+		mockRepo := &mocks.MockFolderRepository{}
+		userID := primitive.NewObjectID()
+		folder := createTestFolder(userID, nil)
+
+		var updated *models.Folder
+		mockRepo.On("GetByID", mock.Anything, *folder.ID).Return(folder, nil)
+		mockRepo.On("Update", mock.Anything, *folder.ID, mock.AnythingOfType("*models.Folder")).
+			Run(func(args mock.Arguments) { updated = args.Get(2).(*models.Folder) }).
+			Return(folder, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPut, "/mail/folders/"+folder.ID.Hex(), gin.H{"name": "Bills"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Bills", updated.Name)
+		assert.Equal(t, userID, updated.UserID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("moving a folder into its subfolder", func(t *testing.T) {
+		mockRepo := &mocks.MockFolderRepository{}
+		userID := primitive.NewObjectID()
+		folder := createTestFolder(userID, nil)
+		child := createTestFolder(userID, folder.ID)
+		grandChild := createTestFolder(userID, child.ID)
+
+		mockRepo.On("GetByID", mock.Anything, *folder.ID).Return(folder, nil)
+		mockRepo.On("GetByID", mock.Anything, *grandChild.ID).Return(grandChild, nil)
+		mockRepo.On("GetAll", mock.Anything, userID).Return([]*models.Folder{folder, child, grandChild}, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPut, "/mail/folders/"+folder.ID.Hex(), gin.H{
+			"name":     "Receipts",
+			"parentId": grandChild.ID.Hex(),
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("folder of another user", func(t *testing.T) {
+		mockRepo := &mocks.MockFolderRepository{}
+		userID := primitive.NewObjectID()
+		folder := createTestFolder(primitive.NewObjectID(), nil)
+		mockRepo.On("GetByID", mock.Anything, *folder.ID).Return(folder, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPut, "/mail/folders/"+folder.ID.Hex(), gin.H{"name": "Bills"})
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid folder ID", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		w := request(setupRouter(&mocks.MockFolderRepository{}, &userID), http.MethodPut, "/mail/folders/invalid", gin.H{"name": "Bills"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestDeleteFolder(t *testing.T) {
+	t.Run("deletes the folder", func(t *testing.T) {
+		mockRepo := &mocks.MockFolderRepository{}
+		userID := primitive.NewObjectID()
+		folder := createTestFolder(userID, nil)
+		mockRepo.On("GetByID", mock.Anything, *folder.ID).Return(folder, nil)
+		mockRepo.On("Delete", mock.Anything, *folder.ID).Return(nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodDelete, "/mail/folders/"+folder.ID.Hex(), nil)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("folder not found", func(t *testing.T) {
+		mockRepo := &mocks.MockFolderRepository{}
+		userID := primitive.NewObjectID()
+		folderID := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, folderID).Return(nil, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodDelete, "/mail/folders/"+folderID.Hex(), nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := &mocks.MockFolderRepository{}
+		userID := primitive.NewObjectID()
+		folder := createTestFolder(userID, nil)
+		mockRepo.On("GetByID", mock.Anything, *folder.ID).Return(folder, nil)
+		mockRepo.On("Delete", mock.Anything, *folder.ID).Return(errors.New("database error"))
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodDelete, "/mail/folders/"+folder.ID.Hex(), nil)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,39 @@
+package folder
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAllFolders retrieves the mail folders of the authenticated user
+// @Summary Get mail folders
+// @Description Get the mail folders of the authenticated user
+// @Tags MailFolder
+// @Produce json
+// @Success 200 {array} models.Folder
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/folders [get]
+func (c *Controller) GetAllFolders(ctx *gin.Context) {
+	// Get authenticated user from context
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	folders, err := c.folderRepo.GetAll(ctx, authUser.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if folders == nil {
+		folders = []*models.Folder{}
+	}
+
+	ctx.JSON(http.StatusOK, folders)
+}
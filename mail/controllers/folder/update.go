@@ -0,0 +1,77 @@
+package folder
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UpdateFolder updates a mail folder
+// @Summary Update mail folder
+// @Description Rename, recolor or move a mail folder of the authenticated user
+// @Tags MailFolder
+// @Accept json
+// @Produce json
+// @Param id path string true "Folder ID"
+// @Param folder body models.Folder true "Folder"
+// @Success 200 {object} models.Folder
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/folders/{id} [put]
+func (c *Controller) UpdateFolder(ctx *gin.Context) {
+	// Get authenticated user from context
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	folderID, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return
+	}
+
+	var folder models.Folder
+	if err := ctx.ShouldBindJSON(&folder); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	existingFolder := c.getOwnedFolder(ctx, authUser.UserID, folderID)
+	if existingFolder == nil {
+		return
+	}
+
+	if folder.ParentID != nil {
+		if c.getOwnedFolder(ctx, authUser.UserID, *folder.ParentID) == nil {
+			return
+		}
+		wouldCycle, err := c.wouldCycle(ctx, authUser.UserID, folderID, *folder.ParentID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if wouldCycle {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "A folder cannot be moved into itself or one of its subfolders"})
+			return
+		}
+	}
+
+	folder.UserID = authUser.UserID
+	folder.CreatedAt = existingFolder.CreatedAt
+
+	updatedFolder, err := c.folderRepo.Update(ctx, folderID, &folder)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updatedFolder)
+}
@@ -0,0 +1,50 @@
+package folder
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/mail/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// getOwnedFolder returns the folder with the given ID when it belongs to the user.
+// Otherwise it writes the error response and returns nil.
+func (c *Controller) getOwnedFolder(ctx *gin.Context, userID, folderID primitive.ObjectID) *models.Folder {
+	folder, err := c.folderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil
+	}
+	if folder == nil || folder.UserID != userID {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+		return nil
+	}
+	return folder
+}
+
+// wouldCycle reports whether moving a folder into the given parent would make it its own ancestor
+func (c *Controller) wouldCycle(ctx *gin.Context, userID, folderID, parentID primitive.ObjectID) (bool, error) {
+	folders, err := c.folderRepo.GetAll(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	parents := map[primitive.ObjectID]*primitive.ObjectID{}
+	for _, folder := range folders {
+		if folder.ID != nil {
+			parents[*folder.ID] = folder.ParentID
+		}
+	}
+
+	// walk up from the new parent, each folder being visited at most once
+	current := &parentID
+	for steps := 0; current != nil && steps <= len(folders); steps++ {
+		if *current == folderID {
+			return true, nil
+		}
+		current = parents[*current]
+	}
+	return false, nil
+}
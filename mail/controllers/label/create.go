@@ -0,0 +1,48 @@
+package label
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateLabel creates a mail label
+// @Summary Create mail label
+// @Description Create a mail label for the authenticated user
+// @Tags MailLabel
+// @Accept json
+// @Produce json
+// @Param label body models.Tag true "Label"
+// @Success 201 {object} models.Tag
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/labels [post]
+func (c *Controller) CreateLabel(ctx *gin.Context) {
+	// Get authenticated user from context
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var label models.Tag
+	if err := ctx.ShouldBindJSON(&label); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	label.ID = nil
+	label.UserID = &authUser.UserID
+
+	createdLabel, err := c.tagRepo.Create(ctx, &label)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, createdLabel)
+}
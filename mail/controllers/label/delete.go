@@ -0,0 +1,47 @@
+package label
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeleteLabel deletes a mail label
+// @Summary Delete mail label
+// @Description Delete a mail label of the authenticated user, the label is removed from its mails
+// @Tags MailLabel
+// @Param id path string true "Label ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/labels/{id} [delete]
+func (c *Controller) DeleteLabel(ctx *gin.Context) {
+	// Get authenticated user from context
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	labelID, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid label ID"})
+		return
+	}
+
+	if c.getOwnedLabel(ctx, authUser.UserID, labelID) == nil {
+		return
+	}
+
+	if err := c.tagRepo.Delete(ctx, labelID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
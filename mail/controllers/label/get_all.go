@@ -0,0 +1,39 @@
+package label
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAllLabels retrieves the mail labels of the authenticated user
+// @Summary Get mail labels
+// @Description Get the mail labels of the authenticated user
+// @Tags MailLabel
+// @Produce json
+// @Success 200 {array} models.Tag
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/labels [get]
+func (c *Controller) GetAllLabels(ctx *gin.Context) {
+	// Get authenticated user from context
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	labels, err := c.tagRepo.GetAll(ctx, &authUser.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if labels == nil {
+		labels = []*models.Tag{}
+	}
+
+	ctx.JSON(http.StatusOK, labels)
+}
@@ -0,0 +1,47 @@
+// Package label is a package that contains the mail label controller, labels being stored as tags
+package label
+
+import (
+	"github.com/atomic-blend/backend/mail/repositories"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Controller handles mail label related operations
+type Controller struct {
+	tagRepo repositories.TagRepositoryInterface
+}
+
+// NewLabelController creates a new mail label controller instance
+func NewLabelController(tagRepo repositories.TagRepositoryInterface) *Controller {
+	return &Controller{
+		tagRepo: tagRepo,
+	}
+}
+
+// SetupRoutes sets up the mail label routes
+func SetupRoutes(router *gin.Engine, database *mongo.Database) {
+	tagRepo := repositories.NewTagRepository(database)
+	labelController := NewLabelController(tagRepo)
+	setupLabelRoutes(router, labelController)
+}
+
+// SetupRoutesWithMock sets up the mail label routes with a mock repository for testing
+func SetupRoutesWithMock(router *gin.Engine, tagRepo repositories.TagRepositoryInterface) {
+	labelController := NewLabelController(tagRepo)
+	setupLabelRoutes(router, labelController)
+}
+
+// setupLabelRoutes sets up the routes for mail label controller
+func setupLabelRoutes(router *gin.Engine, labelController *Controller) {
+	labelRoutes := router.Group("/mail/labels")
+	auth.RequireAuth(labelRoutes)
+	{
+		labelRoutes.GET("", labelController.GetAllLabels)
+		labelRoutes.POST("", labelController.CreateLabel)
+		labelRoutes.PUT("/:id", labelController.UpdateLabel)
+		labelRoutes.DELETE("/:id", labelController.DeleteLabel)
+	}
+}
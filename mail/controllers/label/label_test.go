@@ -0,0 +1,170 @@
+package label
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/mail/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func setupRouter(mockRepo *mocks.MockTagRepository, userID *primitive.ObjectID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	controller := NewLabelController(mockRepo)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID != nil {
+			c.Set("authUser", &auth.UserAuthInfo{UserID: *userID})
+		}
+		c.Next()
+	})
+	router.GET("/mail/labels", controller.GetAllLabels)
+	router.POST("/mail/labels", controller.CreateLabel)
+	router.PUT("/mail/labels/:id", controller.UpdateLabel)
+	router.DELETE("/mail/labels/:id", controller.DeleteLabel)
+	return router
+}
+
+func createTestLabel(userID primitive.ObjectID) *models.Tag {
+	id := primitive.NewObjectID()
+	now := primitive.NewDateTimeFromTime(time.Now())
+	return &models.Tag{ID: &id, Name: "Important", UserID: &userID, CreatedAt: &now}
+}
+
+func request(router *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req, _ := http.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetAllLabels(t *testing.T) {
+	t.Run("lists the labels of the user", func(t *testing.T) {
+		mockRepo := &mocks.MockTagRepository{}
+		userID := primitive.NewObjectID()
+		mockRepo.On("GetAll", mock.Anything, &userID).Return([]*models.Tag{createTestLabel(userID)}, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodGet, "/mail/labels", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []models.Tag
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := &mocks.MockTagRepository{}
+		userID := primitive.NewObjectID()
+		mockRepo.On("GetAll", mock.Anything, &userID).Return(nil, errors.New("database error"))
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodGet, "/mail/labels", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		w := request(setupRouter(&mocks.MockTagRepository{}, nil), http.MethodGet, "/mail/labels", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestCreateLabel(t *testing.T) {
+	t.Run("creates the label for the user", func(t *testing.T) {
+		mockRepo := &mocks.MockTagRepository{}
+		userID := primitive.NewObjectID()
+
+		var created *models.Tag
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Tag")).
+			Run(func(args mock.Arguments) { created = args.Get(1).(*models.Tag) }).
+			Return(createTestLabel(userID), nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPost, "/mail/labels", gin.H{
+			"name":   "Important",
+			"userId": primitive.NewObjectID().Hex(),
+		})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "Important", created.Name)
+		assert.Equal(t, userID, *created.UserID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		w := request(setupRouter(&mocks.MockTagRepository{}, &userID), http.MethodPost, "/mail/labels", gin.H{"color": "#FF0000"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUpdateLabel(t *testing.T) {
+	t.Run("renames the label", func(t *testing.T) {
+		mockRepo := &mocks.MockTagRepository{}
+		userID := primitive.NewObjectID()
+		label := createTestLabel(userID)
+
+		var updated *models.Tag
+		mockRepo.On("GetByID", mock.Anything, *label.ID).Return(label, nil)
+		mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Tag")).
+			Run(func(args mock.Arguments) { updated = args.Get(1).(*models.Tag) }).
+			Return(label, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPut, "/mail/labels/"+label.ID.Hex(), gin.H{"name": "Urgent"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Urgent", updated.Name)
+		assert.Equal(t, *label.ID, *updated.ID)
+		assert.Equal(t, label.CreatedAt, updated.CreatedAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("label of another user", func(t *testing.T) {
+		mockRepo := &mocks.MockTagRepository{}
+		userID := primitive.NewObjectID()
+		label := createTestLabel(primitive.NewObjectID())
+		mockRepo.On("GetByID", mock.Anything, *label.ID).Return(label, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPut, "/mail/labels/"+label.ID.Hex(), gin.H{"name": "Urgent"})
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteLabel(t *testing.T) {
+	t.Run("deletes the label", func(t *testing.T) {
+		mockRepo := &mocks.MockTagRepository{}
+		userID := primitive.NewObjectID()
+		label := createTestLabel(userID)
+		mockRepo.On("GetByID", mock.Anything, *label.ID).Return(label, nil)
+		mockRepo.On("Delete", mock.Anything, *label.ID).Return(nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodDelete, "/mail/labels/"+label.ID.Hex(), nil)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid label ID", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		w := request(setupRouter(&mocks.MockTagRepository{}, &userID), http.MethodDelete, "/mail/labels/invalid", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,63 @@
+package label
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UpdateLabel updates a mail label
+// @Summary Update mail label
+// @Description Rename or recolor a mail label of the authenticated user
+// @Tags MailLabel
+// @Accept json
+// @Produce json
+// @Param id path string true "Label ID"
+// @Param label body models.Tag true "Label"
+// @Success 200 {object} models.Tag
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/labels/{id} [put]
+func (c *Controller) UpdateLabel(ctx *gin.Context) {
+	// Get authenticated user from context
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	labelID, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid label ID"})
+		return
+	}
+
+	var label models.Tag
+	if err := ctx.ShouldBindJSON(&label); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	existingLabel := c.getOwnedLabel(ctx, authUser.UserID, labelID)
+	if existingLabel == nil {
+		return
+	}
+
+	label.ID = &labelID
+	label.UserID = &authUser.UserID
+	label.CreatedAt = existingLabel.CreatedAt
+
+	updatedLabel, err := c.tagRepo.Update(ctx, &label)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updatedLabel)
+}
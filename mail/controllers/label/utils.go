@@ -0,0 +1,25 @@
+package label
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/mail/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// getOwnedLabel returns the label with the given ID when it belongs to the user.
+// Otherwise it writes the error response and returns nil.
+func (c *Controller) getOwnedLabel(ctx *gin.Context, userID, labelID primitive.ObjectID) *models.Tag {
+	label, err := c.tagRepo.GetByID(ctx, labelID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil
+	}
+	if label == nil || label.UserID == nil || *label.UserID != userID {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Label not found"})
+		return nil
+	}
+	return label
+}
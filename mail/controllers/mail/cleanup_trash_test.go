@@ -8,6 +8,7 @@ import (
 
 	"github.com/atomic-blend/backend/mail/tests/mocks"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
+	rspamdservice "github.com/atomic-blend/backend/shared/services/rspamd"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -85,7 +86,7 @@ func TestMailController_CleanupTrash(t *testing.T) {
 			userID := primitive.NewObjectID()
 			tt.setupMock(mockRepo, userID)
 
-			controller := NewMailController(mockRepo)
+			controller := NewMailController(mockRepo, &mocks.MockFolderRepository{}, &mocks.MockTagRepository{}, &rspamdservice.MockRspamdService{})
 
 			// Create router and add auth middleware
 			router := gin.New()
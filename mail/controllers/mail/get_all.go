@@ -21,14 +21,17 @@ type PaginatedMailResponse struct {
 
 // GetAllMails retrieves all mails for the authenticated user with pagination
 // @Summary Get all mails
-// @Description Get all mails for the authenticated user with pagination
+// @Description Get all mails for the authenticated user with pagination, optionally only the mails of a folder or of a label
 // @Tags Mail
 // @Produce json
 // @Param page query int false "Page number (default: 1)"
 // @Param size query int false "Number of items per page (default: 10, max: 100)"
+// @Param folderId query string false "Only the mails of this folder"
+// @Param labelId query string false "Only the mails with this label"
 // @Success 200 {object} PaginatedMailResponse
 // @Failure 401 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /mail [get]
 func (c *Controller) GetAllMails(ctx *gin.Context) {
@@ -43,8 +46,32 @@ func (c *Controller) GetAllMails(ctx *gin.Context) {
 	page := ctx.GetInt("page")
 	size := ctx.GetInt("size")
 
+	folderID, labelID := ctx.Query("folderId"), ctx.Query("labelId")
+	if folderID != "" && labelID != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Mails can be filtered by folder or by label, not both"})
+		return
+	}
+
 	// Get mails with pagination
-	mails, totalCount, err := c.mailRepo.GetAll(ctx, authUser.UserID, int64(page), int64(size))
+	var mails []*models.Mail
+	var totalCount int64
+	var err error
+	switch {
+	case folderID != "":
+		folder := c.getOwnedFolder(ctx, authUser.UserID, folderID)
+		if folder == nil {
+			return
+		}
+		mails, totalCount, err = c.mailRepo.GetAllInFolder(ctx, authUser.UserID, *folder.ID, int64(page), int64(size))
+	case labelID != "":
+		label := c.getOwnedLabel(ctx, authUser.UserID, labelID)
+		if label == nil {
+			return
+		}
+		mails, totalCount, err = c.mailRepo.GetAllWithTag(ctx, authUser.UserID, *label.ID, int64(page), int64(size))
+	default:
+		mails, totalCount, err = c.mailRepo.GetAll(ctx, authUser.UserID, int64(page), int64(size))
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
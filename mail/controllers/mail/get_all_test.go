@@ -9,6 +9,7 @@ import (
 	"github.com/atomic-blend/backend/mail/models"
 	"github.com/atomic-blend/backend/mail/tests/mocks"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
+	rspamdservice "github.com/atomic-blend/backend/shared/services/rspamd"
 	"github.com/webstradev/gin-pagination/v2/pkg/pagination"
 
 	"github.com/gin-gonic/gin"
@@ -86,7 +87,7 @@ func TestMailController_GetAllMails(t *testing.T) {
 			userID := primitive.NewObjectID()
 			tt.setupMock(mockRepo, userID)
 
-			controller := NewMailController(mockRepo)
+			controller := NewMailController(mockRepo, &mocks.MockFolderRepository{}, &mocks.MockTagRepository{}, &rspamdservice.MockRspamdService{})
 
 			router := gin.New()
 			router.Use(func(c *gin.Context) {
@@ -117,3 +118,100 @@ func TestMailController_GetAllMails(t *testing.T) {
 		})
 	}
 }
+
+func TestMailController_GetAllMails_ByFolderOrLabel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := primitive.NewObjectID()
+	folderID := primitive.NewObjectID()
+	labelID := primitive.NewObjectID()
+	otherUserID := primitive.NewObjectID()
+
+	tests := []struct {
+		name           string
+		queryParams    string
+		expectedStatus int
+		setupMock      func(*mocks.MockMailRepository, *mocks.MockFolderRepository, *mocks.MockTagRepository)
+	}{
+		{
+			name:           "Mails of a folder",
+			queryParams:    "?folderId=" + folderID.Hex() + "&page=2&size=15",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mailRepo *mocks.MockMailRepository, folderRepo *mocks.MockFolderRepository, tagRepo *mocks.MockTagRepository) {
+				folderRepo.On("GetByID", mock.Anything, folderID).Return(&models.Folder{ID: &folderID, UserID: userID}, nil)
+				mailRepo.On("GetAllInFolder", mock.Anything, userID, folderID, int64(2), int64(15)).Return([]*models.Mail{{UserID: userID, FolderID: &folderID}}, int64(16), nil)
+			},
+		},
+		{
+			name:           "Mails with a label",
+			queryParams:    "?labelId=" + labelID.Hex(),
+			expectedStatus: http.StatusOK,
+			setupMock: func(mailRepo *mocks.MockMailRepository, folderRepo *mocks.MockFolderRepository, tagRepo *mocks.MockTagRepository) {
+				tagRepo.On("GetByID", mock.Anything, labelID).Return(&models.Tag{ID: &labelID, UserID: &userID}, nil)
+				mailRepo.On("GetAllWithTag", mock.Anything, userID, labelID, int64(1), int64(10)).Return([]*models.Mail{}, int64(0), nil)
+			},
+		},
+		{
+			name:           "Folder of another user",
+			queryParams:    "?folderId=" + folderID.Hex(),
+			expectedStatus: http.StatusNotFound,
+			setupMock: func(mailRepo *mocks.MockMailRepository, folderRepo *mocks.MockFolderRepository, tagRepo *mocks.MockTagRepository) {
+				folderRepo.On("GetByID", mock.Anything, folderID).Return(&models.Folder{ID: &folderID, UserID: otherUserID}, nil)
+			},
+		},
+		{
+			name:           "Label not found",
+			queryParams:    "?labelId=" + labelID.Hex(),
+			expectedStatus: http.StatusNotFound,
+			setupMock: func(mailRepo *mocks.MockMailRepository, folderRepo *mocks.MockFolderRepository, tagRepo *mocks.MockTagRepository) {
+				tagRepo.On("GetByID", mock.Anything, labelID).Return(nil, nil)
+			},
+		},
+		{
+			name:           "Invalid folder ID",
+			queryParams:    "?folderId=invalid",
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(*mocks.MockMailRepository, *mocks.MockFolderRepository, *mocks.MockTagRepository) {},
+		},
+		{
+			name:           "Both folder and label",
+			queryParams:    "?folderId=" + folderID.Hex() + "&labelId=" + labelID.Hex(),
+			expectedStatus: http.StatusBadRequest,
+			setupMock:      func(*mocks.MockMailRepository, *mocks.MockFolderRepository, *mocks.MockTagRepository) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mailRepo := &mocks.MockMailRepository{}
+			folderRepo := &mocks.MockFolderRepository{}
+			tagRepo := &mocks.MockTagRepository{}
+			tt.setupMock(mailRepo, folderRepo, tagRepo)
+
+			controller := NewMailController(mailRepo, folderRepo, tagRepo, &rspamdservice.MockRspamdService{})
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+				c.Next()
+			})
+			router.GET("/mail", pagination.New(), controller.GetAllMails)
+
+			req, _ := http.NewRequest("GET", "/mail"+tt.queryParams, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response PaginatedMailResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.NotNil(t, response.Mails)
+			}
+
+			mailRepo.AssertExpectations(t)
+			folderRepo.AssertExpectations(t)
+			tagRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/atomic-blend/backend/mail/models"
 	"github.com/atomic-blend/backend/mail/tests/mocks"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
+	rspamdservice "github.com/atomic-blend/backend/shared/services/rspamd"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -79,7 +80,7 @@ func TestMailController_GetMailByID(t *testing.T) {
 
 			tt.setupMock(mockRepo, userID, mailID)
 
-			controller := NewMailController(mockRepo)
+			controller := NewMailController(mockRepo, &mocks.MockFolderRepository{}, &mocks.MockTagRepository{}, &rspamdservice.MockRspamdService{})
 
 			// Create router and add auth middleware
 			router := gin.New()
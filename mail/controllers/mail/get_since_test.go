@@ -12,6 +12,7 @@ import (
 	"github.com/atomic-blend/backend/mail/models"
 	"github.com/atomic-blend/backend/mail/tests/mocks"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
+	rspamdservice "github.com/atomic-blend/backend/shared/services/rspamd"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -210,7 +211,7 @@ func TestMailController_GetMailsSince(t *testing.T) {
 
 			tt.setupMock(mockRepo, userID, sinceTime, page, limit)
 
-			controller := NewMailController(mockRepo)
+			controller := NewMailController(mockRepo, &mocks.MockFolderRepository{}, &mocks.MockTagRepository{}, &rspamdservice.MockRspamdService{})
 
 			router := gin.New()
 			router.Use(func(c *gin.Context) {
@@ -0,0 +1,48 @@
+package mail
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSpamMails retrieves the spam mails of the authenticated user with pagination
+// @Summary Get spam mails
+// @Description Get the mails of the authenticated user classified as spam, by the spam filter or by the user, most recent first. Trashed mails are left out.
+// @Tags Mail
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param size query int false "Number of items per page (default: 10, max: 100)"
+// @Success 200 {object} PaginatedMailResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/spam [get]
+func (c *Controller) GetSpamMails(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	page := ctx.GetInt("page")
+	size := ctx.GetInt("size")
+
+	mails, totalCount, err := c.mailRepo.GetAllSpam(ctx, authUser.UserID, int64(page), int64(size))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	totalPages := (totalCount + int64(size) - 1) / int64(size)
+
+	ctx.JSON(http.StatusOK, PaginatedMailResponse{
+		Mails:      mails,
+		TotalCount: totalCount,
+		Page:       int64(page),
+		Size:       int64(size),
+		TotalPages: totalPages,
+	})
+}
@@ -0,0 +1,113 @@
+package mail
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/mail/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+	rspamdservice "github.com/atomic-blend/backend/shared/services/rspamd"
+	"github.com/webstradev/gin-pagination/v2/pkg/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMailController_GetSpamMails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		queryParams    string
+		expectedStatus int
+		setupMock      func(*mocks.MockMailRepository, primitive.ObjectID)
+		setupAuth      func(*gin.Context, primitive.ObjectID)
+	}{
+		{
+			name:           "Success with default pagination",
+			queryParams:    "",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockRepo *mocks.MockMailRepository, userID primitive.ObjectID) {
+				mailID := primitive.NewObjectID()
+				spam := true
+				mails := []*models.Mail{{ID: &mailID, UserID: userID, Spam: &spam}}
+				mockRepo.On("GetAllSpam", mock.Anything, userID, int64(1), int64(10)).Return(mails, int64(11), nil)
+			},
+			setupAuth: func(c *gin.Context, userID primitive.ObjectID) {
+				c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+			},
+		},
+		{
+			name:           "Success with custom pagination",
+			queryParams:    "?page=2&size=15",
+			expectedStatus: http.StatusOK,
+			setupMock: func(mockRepo *mocks.MockMailRepository, userID primitive.ObjectID) {
+				mockRepo.On("GetAllSpam", mock.Anything, userID, int64(2), int64(15)).Return([]*models.Mail{}, int64(0), nil)
+			},
+			setupAuth: func(c *gin.Context, userID primitive.ObjectID) {
+				c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+			},
+		},
+		{
+			name:           "Repository error",
+			queryParams:    "",
+			expectedStatus: http.StatusInternalServerError,
+			setupMock: func(mockRepo *mocks.MockMailRepository, userID primitive.ObjectID) {
+				mockRepo.On("GetAllSpam", mock.Anything, userID, int64(1), int64(10)).Return(nil, int64(0), errors.New("database error"))
+			},
+			setupAuth: func(c *gin.Context, userID primitive.ObjectID) {
+				c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+			},
+		},
+		{
+			name:           "Unauthorized",
+			queryParams:    "",
+			expectedStatus: http.StatusUnauthorized,
+			setupMock:      func(mockRepo *mocks.MockMailRepository, userID primitive.ObjectID) {},
+			setupAuth:      func(c *gin.Context, userID primitive.ObjectID) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.MockMailRepository{}
+			userID := primitive.NewObjectID()
+			tt.setupMock(mockRepo, userID)
+
+			controller := NewMailController(mockRepo, &mocks.MockFolderRepository{}, &mocks.MockTagRepository{}, &rspamdservice.MockRspamdService{})
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				tt.setupAuth(c, userID)
+				c.Next()
+			})
+			router.GET("/mail/spam", pagination.New(), controller.GetSpamMails)
+
+			req, _ := http.NewRequest("GET", "/mail/spam"+tt.queryParams, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var response PaginatedMailResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.NotNil(t, response.Mails)
+				if response.TotalCount == 11 {
+					assert.Equal(t, int64(2), response.TotalPages)
+					assert.Len(t, response.Mails, 1)
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
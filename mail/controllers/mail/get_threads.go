@@ -0,0 +1,58 @@
+package mail
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaginatedThreadResponse represents the paginated response for conversations
+type PaginatedThreadResponse struct {
+	Threads    []*models.MailThread `json:"threads"`
+	TotalCount int64                `json:"total_count"`
+	Page       int64                `json:"page"`
+	Size       int64                `json:"size"`
+	TotalPages int64                `json:"total_pages"`
+}
+
+// GetThreads retrieves the conversations of the authenticated user with pagination
+// @Summary Get mail threads
+// @Description Get the conversations of the authenticated user, most recently active first. Each conversation holds its mails, most recent first. Trashed and spam mails are left out.
+// @Tags Mail
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param size query int false "Number of conversations per page (default: 10, max: 100)"
+// @Success 200 {object} PaginatedThreadResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/threads [get]
+func (c *Controller) GetThreads(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	page := ctx.GetInt("page")
+	size := ctx.GetInt("size")
+
+	threads, totalCount, err := c.mailRepo.GetThreads(ctx, authUser.UserID, int64(page), int64(size))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	totalPages := (totalCount + int64(size) - 1) / int64(size)
+
+	ctx.JSON(http.StatusOK, PaginatedThreadResponse{
+		Threads:    threads,
+		TotalCount: totalCount,
+		Page:       int64(page),
+		Size:       int64(size),
+		TotalPages: totalPages,
+	})
+}
@@ -2,35 +2,59 @@ package mail
 
 import (
 	"github.com/atomic-blend/backend/mail/repositories"
+	"github.com/atomic-blend/backend/mail/utils/threading"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
+	rspamdservice "github.com/atomic-blend/backend/shared/services/rspamd"
+	rspamdinterfaces "github.com/atomic-blend/backend/shared/services/rspamd/interfaces"
 	"github.com/webstradev/gin-pagination/v2/pkg/pagination"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // Controller handles mail related operations
 type Controller struct {
-	mailRepo repositories.MailRepositoryInterface
+	mailRepo   repositories.MailRepositoryInterface
+	folderRepo repositories.FolderRepositoryInterface
+	tagRepo    repositories.TagRepositoryInterface
+	// rspamdService trains the spam filter with the mails reported as spam or as not spam
+	rspamdService rspamdinterfaces.RspamdServiceInterface
+	// threadKeyer verifies the messages of the spam reports, nil when threading is not configured
+	threadKeyer *threading.Keyer
+	// learnLimiter limits the number of spam reports of each user the spam filter learns from, per instance
+	learnLimiter *learnLimiter
 }
 
 // NewMailController creates a new mail controller instance
-func NewMailController(mailRepo repositories.MailRepositoryInterface) *Controller {
+func NewMailController(mailRepo repositories.MailRepositoryInterface, folderRepo repositories.FolderRepositoryInterface, tagRepo repositories.TagRepositoryInterface, rspamdService rspamdinterfaces.RspamdServiceInterface) *Controller {
+	threadKeyer, err := threading.NewKeyer()
+	if err != nil {
+		log.Warn().Err(err).Msg("Spam reports do not train the spam filter")
+	}
 	return &Controller{
-		mailRepo: mailRepo,
+		mailRepo:      mailRepo,
+		folderRepo:    folderRepo,
+		tagRepo:       tagRepo,
+		rspamdService: rspamdService,
+		threadKeyer:   threadKeyer,
+		learnLimiter:  newLearnLimiter(),
 	}
 }
 
 // SetupRoutes sets up the mail routes
 func SetupRoutes(router *gin.Engine, database *mongo.Database) {
 	mailRepo := repositories.NewMailRepository(database)
-	mailController := NewMailController(mailRepo)
+	folderRepo := repositories.NewFolderRepository(database)
+	tagRepo := repositories.NewTagRepository(database)
+	rspamdService := rspamdservice.NewRspamdService()
+	mailController := NewMailController(mailRepo, folderRepo, tagRepo, rspamdService)
 	setupMailRoutes(router, mailController)
 }
 
 // SetupRoutesWithMock sets up the mail routes with a mock repository for testing
-func SetupRoutesWithMock(router *gin.Engine, mailRepo repositories.MailRepositoryInterface) {
-	mailController := NewMailController(mailRepo)
+func SetupRoutesWithMock(router *gin.Engine, mailRepo repositories.MailRepositoryInterface, folderRepo repositories.FolderRepositoryInterface, tagRepo repositories.TagRepositoryInterface, rspamdService rspamdinterfaces.RspamdServiceInterface) {
+	mailController := NewMailController(mailRepo, folderRepo, tagRepo, rspamdService)
 	setupMailRoutes(router, mailController)
 }
 
@@ -42,6 +66,8 @@ func setupMailRoutes(router *gin.Engine, mailController *Controller) {
 		mailRoutes.GET("/", pagination.New(), mailController.GetAllMails)
 		mailRoutes.GET("/:id", mailController.GetMailByID)
 		mailRoutes.GET("/since", pagination.New(), mailController.GetMailsSince)
+		mailRoutes.GET("/threads", pagination.New(), mailController.GetThreads)
+		mailRoutes.GET("/spam", pagination.New(), mailController.GetSpamMails)
 		mailRoutes.PUT("/actions", mailController.PutMailActions)
 		mailRoutes.POST("/trash/empty", mailController.CleanupTrash)
 	}
@@ -0,0 +1,54 @@
+package mail
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/mail/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// getOwnedFolder returns the folder with the given ID when it belongs to the user.
+// Otherwise it writes the error response and returns nil.
+func (c *Controller) getOwnedFolder(ctx *gin.Context, userID primitive.ObjectID, idStr string) *models.Folder {
+	folderID, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return nil
+	}
+
+	folder, err := c.folderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil
+	}
+	if folder == nil || folder.UserID != userID {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+		return nil
+	}
+
+	return folder
+}
+
+// getOwnedLabel returns the label with the given ID when it belongs to the user.
+// Otherwise it writes the error response and returns nil.
+func (c *Controller) getOwnedLabel(ctx *gin.Context, userID primitive.ObjectID, idStr string) *models.Tag {
+	tagID, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid label ID"})
+		return nil
+	}
+
+	tag, err := c.tagRepo.GetByID(ctx, tagID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil
+	}
+	if tag == nil || tag.UserID == nil || *tag.UserID != userID {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Label not found"})
+		return nil
+	}
+
+	return tag
+}
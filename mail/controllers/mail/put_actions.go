@@ -20,6 +20,37 @@ type PutActionsPayload struct {
 	Unarchived []string `json:"unarchived,omitempty"`
 	Trashed    []string `json:"trashed,omitempty"`
 	Untrashed  []string `json:"untrashed,omitempty"`
+	// Moved moves mails to a folder
+	Moved *MoveAction `json:"moved,omitempty"`
+	// Labeled adds labels to mails
+	Labeled []LabelAction `json:"labeled,omitempty"`
+	// Unlabeled removes labels from mails
+	Unlabeled []LabelAction `json:"unlabeled,omitempty"`
+	// Spam reports mails as spam, moving them to the spam mailbox
+	Spam []SpamReport `json:"spam,omitempty"`
+	// NotSpam reports mails as not spam, moving them out of the spam mailbox
+	NotSpam []SpamReport `json:"notSpam,omitempty"`
+}
+
+// MoveAction moves mails to a folder, or back to the inbox when the folder ID is empty
+type MoveAction struct {
+	FolderID string   `json:"folderId"`
+	Mails    []string `json:"mails"`
+}
+
+// LabelAction adds a label to mails or removes it from them
+type LabelAction struct {
+	LabelID string   `json:"labelId"`
+	Mails   []string `json:"mails"`
+}
+
+// SpamReport reports a mail as spam or as not spam
+type SpamReport struct {
+	Mail string `json:"mail"`
+	// Message is the RFC 5322 message of the mail as decrypted by the client. Mails are stored encrypted,
+	// the spam filter only learns from the reports holding their message, whose Message-ID is the one of the
+	// reported mail, up to maxLearnedReports reports of a user per hour.
+	Message string `json:"message,omitempty"`
 }
 
 // PutMailActions updates the actions of a mail
@@ -56,6 +87,21 @@ func (c *Controller) PutMailActions(ctx *gin.Context) {
 
 	log.Debug().Interface("payload", payload).Msg("Parsed Payload")
 
+	// Check the folder and the labels before updating any mail
+	var folderID *primitive.ObjectID
+	if payload.Moved != nil && payload.Moved.FolderID != "" {
+		folder := c.getOwnedFolder(ctx, authUser.UserID, payload.Moved.FolderID)
+		if folder == nil {
+			return
+		}
+		folderID = folder.ID
+	}
+	for _, action := range append(append([]LabelAction{}, payload.Labeled...), payload.Unlabeled...) {
+		if c.getOwnedLabel(ctx, authUser.UserID, action.LabelID) == nil {
+			return
+		}
+	}
+
 	// Process "read" actions
 	_processRead(ctx, payload, c, authUser, true)
 
@@ -74,6 +120,35 @@ func (c *Controller) PutMailActions(ctx *gin.Context) {
 	// Process "untrash" actions
 	_processTrashed(ctx, payload, c, authUser, false)
 
+	// Process "move" actions
+	if payload.Moved != nil && !_processMoved(ctx, payload.Moved.Mails, c, authUser, folderID) {
+		return
+	}
+
+	// Process "label" actions
+	for _, action := range payload.Labeled {
+		if !_processLabel(ctx, action, c, authUser, true) {
+			return
+		}
+	}
+
+	// Process "unlabel" actions
+	for _, action := range payload.Unlabeled {
+		if !_processLabel(ctx, action, c, authUser, false) {
+			return
+		}
+	}
+
+	// Process "spam" actions
+	if !_processSpam(ctx, payload.Spam, c, authUser, true) {
+		return
+	}
+
+	// Process "not spam" actions
+	if !_processSpam(ctx, payload.NotSpam, c, authUser, false) {
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{"message": "Mail actions updated successfully"})
 }
 
@@ -175,3 +250,109 @@ func _processTrashed(ctx *gin.Context, payload PutActionsPayload, c *Controller,
 		}
 	}
 }
+
+func _processMoved(ctx *gin.Context, ids []string, c *Controller, authUser *auth.UserAuthInfo, folderID *primitive.ObjectID) bool {
+	for _, idStr := range ids {
+		mailID, err := primitive.ObjectIDFromHex(idStr)
+		if err != nil {
+			continue // Skip invalid IDs
+		}
+
+		mail, err := c.mailRepo.GetByID(ctx, mailID)
+		if err != nil || mail == nil || mail.UserID != authUser.UserID {
+			continue // Skip if mail not found or doesn't belong to user
+		}
+
+		mail.FolderID = folderID
+		now := primitive.NewDateTimeFromTime(time.Now())
+		mail.UpdatedAt = &now
+
+		if err := c.mailRepo.Update(ctx, mail); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move mail"})
+			return false
+		}
+	}
+	return true
+}
+
+func _processLabel(ctx *gin.Context, action LabelAction, c *Controller, authUser *auth.UserAuthInfo, labeled bool) bool {
+	// the label was checked before processing the actions
+	labelID, _ := primitive.ObjectIDFromHex(action.LabelID)
+
+	for _, idStr := range action.Mails {
+		mailID, err := primitive.ObjectIDFromHex(idStr)
+		if err != nil {
+			continue // Skip invalid IDs
+		}
+
+		mail, err := c.mailRepo.GetByID(ctx, mailID)
+		if err != nil || mail == nil || mail.UserID != authUser.UserID {
+			continue // Skip if mail not found or doesn't belong to user
+		}
+
+		tagIDs := []primitive.ObjectID{}
+		for _, tagID := range mail.TagIDs {
+			if tagID != labelID {
+				tagIDs = append(tagIDs, tagID)
+			}
+		}
+		if labeled {
+			tagIDs = append(tagIDs, labelID)
+		}
+		mail.TagIDs = tagIDs
+		now := primitive.NewDateTimeFromTime(time.Now())
+		mail.UpdatedAt = &now
+
+		if err := c.mailRepo.Update(ctx, mail); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update mail labels"})
+			return false
+		}
+	}
+	return true
+}
+
+func _processSpam(ctx *gin.Context, reports []SpamReport, c *Controller, authUser *auth.UserAuthInfo, spam bool) bool {
+	for _, report := range reports {
+		mailID, err := primitive.ObjectIDFromHex(report.Mail)
+		if err != nil {
+			continue // Skip invalid IDs
+		}
+
+		mail, err := c.mailRepo.GetByID(ctx, mailID)
+		if err != nil || mail == nil || mail.UserID != authUser.UserID {
+			continue // Skip if mail not found or doesn't belong to user
+		}
+
+		mail.Spam = &spam
+		now := primitive.NewDateTimeFromTime(time.Now())
+		mail.UpdatedAt = &now
+
+		if err := c.mailRepo.Update(ctx, mail); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update mail spam status"})
+			return false
+		}
+
+		if report.Message == "" {
+			continue
+		}
+		if !c.matchesReportedMail(authUser.UserID, mail, report.Message) {
+			log.Warn().Str("mail_id", report.Mail).Msg("The message of the spam report is not the reported mail")
+			continue
+		}
+		if !c.learnLimiter.allow(authUser.UserID, time.Now()) {
+			log.Warn().Str("mail_id", report.Mail).Str("user_id", authUser.UserID.Hex()).Msg("Too many spam reports to train the spam filter")
+			continue
+		}
+
+		// the report is saved even when the spam filter cannot learn from it
+		if spam {
+			err = c.rspamdService.LearnSpam([]byte(report.Message))
+		} else {
+			err = c.rspamdService.LearnHam([]byte(report.Message))
+		}
+		if err != nil {
+			log.Warn().Err(err).Str("mail_id", report.Mail).Bool("spam", spam).Msg("Failed to train the spam filter")
+		}
+	}
+	return true
+}
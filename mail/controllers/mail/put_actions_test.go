@@ -3,13 +3,16 @@ package mail
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/atomic-blend/backend/mail/models"
 	"github.com/atomic-blend/backend/mail/tests/mocks"
+	"github.com/atomic-blend/backend/mail/utils/threading"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
+	rspamdservice "github.com/atomic-blend/backend/shared/services/rspamd"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -574,7 +577,7 @@ func TestMailController_PutMailActions(t *testing.T) {
 			mockRepo := &mocks.MockMailRepository{}
 			tt.setupMock(mockRepo, userID, mailID)
 
-			controller := NewMailController(mockRepo)
+			controller := NewMailController(mockRepo, &mocks.MockFolderRepository{}, &mocks.MockTagRepository{}, &rspamdservice.MockRspamdService{})
 
 			router := gin.New()
 			router.Use(func(c *gin.Context) {
@@ -606,3 +609,255 @@ func TestMailController_PutMailActions(t *testing.T) {
 		})
 	}
 }
+
+func TestMailController_PutMailActions_MoveAndLabel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := primitive.NewObjectID()
+	folderID := primitive.NewObjectID()
+	labelID := primitive.NewObjectID()
+	otherLabelID := primitive.NewObjectID()
+
+	send := func(controller *Controller, payload PutActionsPayload) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+			c.Next()
+		})
+		router.PUT("/mail/actions", controller.PutMailActions)
+
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(payload)
+		req, _ := http.NewRequest("PUT", "/mail/actions", &body)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Moves mails to a folder", func(t *testing.T) {
+		mailRepo := &mocks.MockMailRepository{}
+		folderRepo := &mocks.MockFolderRepository{}
+		mailID := primitive.NewObjectID()
+
+		var updated *models.Mail
+		folderRepo.On("GetByID", mock.Anything, folderID).Return(&models.Folder{ID: &folderID, UserID: userID}, nil)
+		mailRepo.On("GetByID", mock.Anything, mailID).Return(&models.Mail{ID: &mailID, UserID: userID}, nil)
+		mailRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Mail")).
+			Run(func(args mock.Arguments) { updated = args.Get(1).(*models.Mail) }).
+			Return(nil)
+
+		w := send(NewMailController(mailRepo, folderRepo, &mocks.MockTagRepository{}, &rspamdservice.MockRspamdService{}), PutActionsPayload{
+			Moved: &MoveAction{FolderID: folderID.Hex(), Mails: []string{mailID.Hex()}},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, folderID, *updated.FolderID)
+		assert.NotNil(t, updated.UpdatedAt)
+		mailRepo.AssertExpectations(t)
+	})
+
+	t.Run("Moves mails back to the inbox", func(t *testing.T) {
+		mailRepo := &mocks.MockMailRepository{}
+		mailID := primitive.NewObjectID()
+
+		var updated *models.Mail
+		mailRepo.On("GetByID", mock.Anything, mailID).Return(&models.Mail{ID: &mailID, UserID: userID, FolderID: &folderID}, nil)
+		mailRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Mail")).
+			Run(func(args mock.Arguments) { updated = args.Get(1).(*models.Mail) }).
+			Return(nil)
+
+		w := send(NewMailController(mailRepo, &mocks.MockFolderRepository{}, &mocks.MockTagRepository{}, &rspamdservice.MockRspamdService{}), PutActionsPayload{
+			Moved: &MoveAction{Mails: []string{mailID.Hex()}},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Nil(t, updated.FolderID)
+	})
+
+	t.Run("Labels and unlabels mails", func(t *testing.T) {
+		mailRepo := &mocks.MockMailRepository{}
+		tagRepo := &mocks.MockTagRepository{}
+		mailID := primitive.NewObjectID()
+		mail := &models.Mail{ID: &mailID, UserID: userID, TagIDs: []primitive.ObjectID{otherLabelID}}
+
+		tagRepo.On("GetByID", mock.Anything, labelID).Return(&models.Tag{ID: &labelID, UserID: &userID}, nil)
+		tagRepo.On("GetByID", mock.Anything, otherLabelID).Return(&models.Tag{ID: &otherLabelID, UserID: &userID}, nil)
+		mailRepo.On("GetByID", mock.Anything, mailID).Return(mail, nil)
+		mailRepo.On("Update", mock.Anything, mail).Return(nil).Twice()
+
+		w := send(NewMailController(mailRepo, &mocks.MockFolderRepository{}, tagRepo, &rspamdservice.MockRspamdService{}), PutActionsPayload{
+			Labeled:   []LabelAction{{LabelID: labelID.Hex(), Mails: []string{mailID.Hex()}}},
+			Unlabeled: []LabelAction{{LabelID: otherLabelID.Hex(), Mails: []string{mailID.Hex()}}},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []primitive.ObjectID{labelID}, mail.TagIDs)
+		assert.NotNil(t, mail.UpdatedAt)
+		mailRepo.AssertExpectations(t)
+	})
+
+	t.Run("Label of another user", func(t *testing.T) {
+		mailRepo := &mocks.MockMailRepository{}
+		tagRepo := &mocks.MockTagRepository{}
+		otherUserID := primitive.NewObjectID()
+		tagRepo.On("GetByID", mock.Anything, labelID).Return(&models.Tag{ID: &labelID, UserID: &otherUserID}, nil)
+
+		w := send(NewMailController(mailRepo, &mocks.MockFolderRepository{}, tagRepo, &rspamdservice.MockRspamdService{}), PutActionsPayload{
+			Read:    []string{primitive.NewObjectID().Hex()},
+			Labeled: []LabelAction{{LabelID: labelID.Hex(), Mails: []string{primitive.NewObjectID().Hex()}}},
+		})
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mailRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid folder ID", func(t *testing.T) {
+		w := send(NewMailController(&mocks.MockMailRepository{}, &mocks.MockFolderRepository{}, &mocks.MockTagRepository{}, &rspamdservice.MockRspamdService{}), PutActionsPayload{
+			Moved: &MoveAction{FolderID: "invalid", Mails: []string{primitive.NewObjectID().Hex()}},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestMailController_PutMailActions_Spam(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := primitive.NewObjectID()
+
+	send := func(controller *Controller, payload PutActionsPayload) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+			c.Next()
+		})
+		router.PUT("/mail/actions", controller.PutMailActions)
+
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(payload)
+		req, _ := http.NewRequest("PUT", "/mail/actions", &body)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	keyer := threading.NewKeyerWithSecret([]byte("secret"))
+	newController := func(mailRepo *mocks.MockMailRepository, rspamdService *rspamdservice.MockRspamdService) *Controller {
+		controller := NewMailController(mailRepo, &mocks.MockFolderRepository{}, &mocks.MockTagRepository{}, rspamdService)
+		controller.threadKeyer = keyer
+		return controller
+	}
+	message := "Message-ID: <win@spam.example.com>\r\nSubject: win\r\n\r\nbody"
+	messageKey := keyer.Compute(userID, map[string]interface{}{"Message-ID": "<win@spam.example.com>"}).MessageKey
+
+	t.Run("Reports a mail as spam and trains the spam filter", func(t *testing.T) {
+		mailRepo := &mocks.MockMailRepository{}
+		rspamdService := &rspamdservice.MockRspamdService{}
+		mailID := primitive.NewObjectID()
+		mail := &models.Mail{ID: &mailID, UserID: userID, MessageKey: messageKey}
+		mailRepo.On("GetByID", mock.Anything, mailID).Return(mail, nil)
+		mailRepo.On("Update", mock.Anything, mock.MatchedBy(func(m *models.Mail) bool {
+			return m.Spam != nil && *m.Spam && m.UpdatedAt != nil
+		})).Return(nil)
+		rspamdService.On("LearnSpam", []byte(message)).Return(nil)
+
+		w := send(newController(mailRepo, rspamdService), PutActionsPayload{
+			Spam: []SpamReport{{Mail: mailID.Hex(), Message: message}},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mailRepo.AssertExpectations(t)
+		rspamdService.AssertExpectations(t)
+	})
+
+	t.Run("Reports a mail as not spam even when the spam filter fails", func(t *testing.T) {
+		mailRepo := &mocks.MockMailRepository{}
+		rspamdService := &rspamdservice.MockRspamdService{}
+		mailID := primitive.NewObjectID()
+		spam := true
+		mail := &models.Mail{ID: &mailID, UserID: userID, MessageKey: messageKey, Spam: &spam}
+		mailRepo.On("GetByID", mock.Anything, mailID).Return(mail, nil)
+		mailRepo.On("Update", mock.Anything, mock.MatchedBy(func(m *models.Mail) bool {
+			return m.Spam != nil && !*m.Spam && m.UpdatedAt != nil
+		})).Return(nil)
+		rspamdService.On("LearnHam", []byte(message)).Return(errors.New("rspamd unavailable"))
+
+		w := send(newController(mailRepo, rspamdService), PutActionsPayload{
+			NotSpam: []SpamReport{{Mail: mailID.Hex(), Message: message}},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mailRepo.AssertExpectations(t)
+		rspamdService.AssertExpectations(t)
+	})
+
+	t.Run("Does not train the spam filter with another message", func(t *testing.T) {
+		mailRepo := &mocks.MockMailRepository{}
+		rspamdService := &rspamdservice.MockRspamdService{}
+		mailID := primitive.NewObjectID()
+		mailRepo.On("GetByID", mock.Anything, mailID).Return(&models.Mail{ID: &mailID, UserID: userID, MessageKey: messageKey}, nil)
+		mailRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		w := send(newController(mailRepo, rspamdService), PutActionsPayload{
+			Spam: []SpamReport{
+				{Mail: mailID.Hex(), Message: "Message-ID: <ham@example.com>\r\nSubject: hello\r\n\r\nbody"},
+				{Mail: mailID.Hex(), Message: "Subject: win\r\n\r\nbody"},
+			},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mailRepo.AssertNumberOfCalls(t, "Update", 2)
+		rspamdService.AssertNotCalled(t, "LearnSpam", mock.Anything)
+	})
+
+	t.Run("Limits the reports the spam filter learns from", func(t *testing.T) {
+		mailRepo := &mocks.MockMailRepository{}
+		rspamdService := &rspamdservice.MockRspamdService{}
+		mailID := primitive.NewObjectID()
+		mailRepo.On("GetByID", mock.Anything, mailID).Return(&models.Mail{ID: &mailID, UserID: userID, MessageKey: messageKey}, nil)
+		mailRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+		rspamdService.On("LearnSpam", []byte(message)).Return(nil)
+
+		reports := make([]SpamReport, 0, maxLearnedReports+1)
+		for range maxLearnedReports + 1 {
+			reports = append(reports, SpamReport{Mail: mailID.Hex(), Message: message})
+		}
+		w := send(newController(mailRepo, rspamdService), PutActionsPayload{Spam: reports})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mailRepo.AssertNumberOfCalls(t, "Update", maxLearnedReports+1)
+		rspamdService.AssertNumberOfCalls(t, "LearnSpam", maxLearnedReports)
+	})
+
+	t.Run("Does not train the spam filter without the message", func(t *testing.T) {
+		mailRepo := &mocks.MockMailRepository{}
+		rspamdService := &rspamdservice.MockRspamdService{}
+		mailID := primitive.NewObjectID()
+		mailRepo.On("GetByID", mock.Anything, mailID).Return(&models.Mail{ID: &mailID, UserID: userID}, nil)
+		mailRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		w := send(NewMailController(mailRepo, &mocks.MockFolderRepository{}, &mocks.MockTagRepository{}, rspamdService), PutActionsPayload{
+			Spam: []SpamReport{{Mail: mailID.Hex()}},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		rspamdService.AssertNotCalled(t, "LearnSpam", mock.Anything)
+	})
+
+	t.Run("Skips the mails of another user", func(t *testing.T) {
+		mailRepo := &mocks.MockMailRepository{}
+		rspamdService := &rspamdservice.MockRspamdService{}
+		mailID := primitive.NewObjectID()
+		mailRepo.On("GetByID", mock.Anything, mailID).Return(&models.Mail{ID: &mailID, UserID: primitive.NewObjectID()}, nil)
+
+		w := send(NewMailController(mailRepo, &mocks.MockFolderRepository{}, &mocks.MockTagRepository{}, rspamdService), PutActionsPayload{
+			Spam: []SpamReport{{Mail: mailID.Hex(), Message: "message"}},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mailRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		rspamdService.AssertNotCalled(t, "LearnSpam", mock.Anything)
+	})
+}
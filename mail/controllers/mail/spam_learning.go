@@ -0,0 +1,66 @@
+package mail
+
+import (
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atomic-blend/backend/mail/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxLearnedReports is the number of spam reports of a user the spam filter learns from within learnWindow
+const maxLearnedReports = 50
+
+// learnWindow is the period over which the learnt spam reports of a user are counted
+const learnWindow = time.Hour
+
+// learnLimiter limits the number of spam reports of each user the spam filter learns from.
+// The reports are counted in memory, so the limit holds per instance of the service and is reset when it restarts:
+// with N replicas a user can train the spam filter with up to N times maxLearnedReports reports within learnWindow.
+type learnLimiter struct {
+	mu      sync.Mutex
+	reports map[primitive.ObjectID][]time.Time
+}
+
+func newLearnLimiter() *learnLimiter {
+	return &learnLimiter{reports: make(map[primitive.ObjectID][]time.Time)}
+}
+
+// allow reports whether the spam filter can learn from one more report of the user, and counts it when it can
+func (l *learnLimiter) allow(userID primitive.ObjectID, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := l.reports[userID][:0]
+	for _, at := range l.reports[userID] {
+		if now.Sub(at) < learnWindow {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= maxLearnedReports {
+		l.reports[userID] = recent
+		return false
+	}
+	l.reports[userID] = append(recent, now)
+	return true
+}
+
+// matchesReportedMail reports whether the message of a spam report is the reported mail, its Message-ID having
+// the message key of the mail. Mails without a message key, or reports when threading is not configured, never match.
+func (c *Controller) matchesReportedMail(userID primitive.ObjectID, reported *models.Mail, message string) bool {
+	if c.threadKeyer == nil || reported.MessageKey == "" {
+		return false
+	}
+	parsed, err := mail.ReadMessage(strings.NewReader(message))
+	if err != nil {
+		return false
+	}
+	headers := make(map[string]interface{}, len(parsed.Header))
+	for name, values := range parsed.Header {
+		headers[name] = values
+	}
+	return c.threadKeyer.Compute(userID, headers).MessageKey == reported.MessageKey
+}
@@ -2,7 +2,11 @@ package controllers
 
 import (
 	"github.com/atomic-blend/backend/mail/controllers/draftmail"
+	"github.com/atomic-blend/backend/mail/controllers/filter"
+	"github.com/atomic-blend/backend/mail/controllers/folder"
+	"github.com/atomic-blend/backend/mail/controllers/label"
 	"github.com/atomic-blend/backend/mail/controllers/mail"
+	"github.com/atomic-blend/backend/mail/controllers/sender"
 	"github.com/atomic-blend/backend/mail/controllers/sendmail"
 	amqpinterfaces "github.com/atomic-blend/backend/shared/services/amqp/interfaces"
 	"github.com/gin-gonic/gin"
@@ -15,4 +19,8 @@ func SetupAllControllers(router *gin.Engine, database *mongo.Database, amqpServi
 	mail.SetupRoutes(router, database)
 	sendmail.SetupRoutes(router, database, amqpService)
 	draftmail.SetupRoutes(router, database, amqpService)
+	folder.SetupRoutes(router, database)
+	label.SetupRoutes(router, database)
+	filter.SetupRoutes(router, database)
+	sender.SetupRoutes(router, database)
 }
@@ -0,0 +1,56 @@
+package sender
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/mail/utils/senderlist"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AddSender adds a sender to the allow list or to the block list
+// @Summary Add sender to a list
+// @Description Add an address or a domain to the allow list or to the block list of the authenticated user. A sender already in the other list is moved. Mails from allowed senders are never classified as spam, mails from blocked senders always are.
+// @Tags MailSender
+// @Accept json
+// @Produce json
+// @Param sender body models.SenderListEntry true "Sender"
+// @Success 201 {object} models.SenderListEntry
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/senders [post]
+func (c *Controller) AddSender(ctx *gin.Context) {
+	// Get authenticated user from context
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var entry models.SenderListEntry
+	if err := ctx.ShouldBindJSON(&entry); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sender, err := senderlist.Normalize(entry.Sender)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	savedEntry, err := c.senderListRepo.Upsert(ctx, &models.SenderListEntry{
+		UserID: authUser.UserID,
+		Sender: sender,
+		List:   entry.List,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, savedEntry)
+}
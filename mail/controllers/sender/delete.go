@@ -0,0 +1,53 @@
+package sender
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeleteSender removes a sender from its list
+// @Summary Remove sender from a list
+// @Description Remove a sender from the allow list or from the block list of the authenticated user
+// @Tags MailSender
+// @Param id path string true "Sender list entry ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/senders/{id} [delete]
+func (c *Controller) DeleteSender(ctx *gin.Context) {
+	// Get authenticated user from context
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	entryID, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sender ID"})
+		return
+	}
+
+	entry, err := c.senderListRepo.GetByID(ctx, entryID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if entry == nil || entry.UserID != authUser.UserID {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Sender not found"})
+		return
+	}
+
+	if err := c.senderListRepo.Delete(ctx, entryID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,51 @@
+package sender
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAllSenders retrieves the sender allow and block lists of the authenticated user
+// @Summary Get sender lists
+// @Description Get the senders of the allow and block lists of the authenticated user, optionally of a single list
+// @Tags MailSender
+// @Produce json
+// @Param list query string false "List (allowed or blocked)"
+// @Success 200 {array} models.SenderListEntry
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /mail/senders [get]
+func (c *Controller) GetAllSenders(ctx *gin.Context) {
+	// Get authenticated user from context
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	list := ctx.Query("list")
+	if list != "" && list != models.SenderListAllowed && list != models.SenderListBlocked {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid list, expected allowed or blocked"})
+		return
+	}
+
+	entries, err := c.senderListRepo.GetAll(ctx, authUser.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	senders := []*models.SenderListEntry{}
+	for _, entry := range entries {
+		if list == "" || entry.List == list {
+			senders = append(senders, entry)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, senders)
+}
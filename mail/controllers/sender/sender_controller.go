@@ -0,0 +1,46 @@
+// Package sender is a package that contains the sender allow and block lists controller
+package sender
+
+import (
+	"github.com/atomic-blend/backend/mail/repositories"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Controller handles sender allow and block lists related operations
+type Controller struct {
+	senderListRepo repositories.SenderListRepositoryInterface
+}
+
+// NewSenderController creates a new sender list controller instance
+func NewSenderController(senderListRepo repositories.SenderListRepositoryInterface) *Controller {
+	return &Controller{
+		senderListRepo: senderListRepo,
+	}
+}
+
+// SetupRoutes sets up the sender list routes
+func SetupRoutes(router *gin.Engine, database *mongo.Database) {
+	senderListRepo := repositories.NewSenderListRepository(database)
+	senderController := NewSenderController(senderListRepo)
+	setupSenderRoutes(router, senderController)
+}
+
+// SetupRoutesWithMock sets up the sender list routes with a mock repository for testing
+func SetupRoutesWithMock(router *gin.Engine, senderListRepo repositories.SenderListRepositoryInterface) {
+	senderController := NewSenderController(senderListRepo)
+	setupSenderRoutes(router, senderController)
+}
+
+// setupSenderRoutes sets up the routes for sender list controller
+func setupSenderRoutes(router *gin.Engine, senderController *Controller) {
+	senderRoutes := router.Group("/mail/senders")
+	auth.RequireAuth(senderRoutes)
+	{
+		senderRoutes.GET("", senderController.GetAllSenders)
+		senderRoutes.POST("", senderController.AddSender)
+		senderRoutes.DELETE("/:id", senderController.DeleteSender)
+	}
+}
@@ -0,0 +1,173 @@
+package sender
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/mail/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func setupRouter(mockRepo *mocks.MockSenderListRepository, userID *primitive.ObjectID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	controller := NewSenderController(mockRepo)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID != nil {
+			c.Set("authUser", &auth.UserAuthInfo{UserID: *userID})
+		}
+		c.Next()
+	})
+	router.GET("/mail/senders", controller.GetAllSenders)
+	router.POST("/mail/senders", controller.AddSender)
+	router.DELETE("/mail/senders/:id", controller.DeleteSender)
+	return router
+}
+
+func request(router *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req, _ := http.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetAllSenders(t *testing.T) {
+	userID := primitive.NewObjectID()
+	entries := []*models.SenderListEntry{
+		{UserID: userID, Sender: "example.com", List: models.SenderListBlocked},
+		{UserID: userID, Sender: "friend@example.com", List: models.SenderListAllowed},
+	}
+
+	t.Run("returns both lists", func(t *testing.T) {
+		mockRepo := &mocks.MockSenderListRepository{}
+		mockRepo.On("GetAll", mock.Anything, userID).Return(entries, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodGet, "/mail/senders", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []models.SenderListEntry
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 2)
+	})
+
+	t.Run("filters by list", func(t *testing.T) {
+		mockRepo := &mocks.MockSenderListRepository{}
+		mockRepo.On("GetAll", mock.Anything, userID).Return(entries, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodGet, "/mail/senders?list=allowed", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []models.SenderListEntry
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+		assert.Equal(t, "friend@example.com", response[0].Sender)
+	})
+
+	t.Run("invalid list", func(t *testing.T) {
+		mockRepo := &mocks.MockSenderListRepository{}
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodGet, "/mail/senders?list=other", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		w := request(setupRouter(&mocks.MockSenderListRepository{}, nil), http.MethodGet, "/mail/senders", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestAddSender(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	t.Run("normalizes and saves the sender", func(t *testing.T) {
+		mockRepo := &mocks.MockSenderListRepository{}
+		entryID := primitive.NewObjectID()
+		mockRepo.On("Upsert", mock.Anything, &models.SenderListEntry{UserID: userID, Sender: "example.com", List: models.SenderListBlocked}).
+			Return(&models.SenderListEntry{ID: &entryID, UserID: userID, Sender: "example.com", List: models.SenderListBlocked}, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPost, "/mail/senders", gin.H{"sender": " @Example.COM ", "list": "blocked"})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response models.SenderListEntry
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, entryID, *response.ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid sender", func(t *testing.T) {
+		mockRepo := &mocks.MockSenderListRepository{}
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPost, "/mail/senders", gin.H{"sender": "not a sender", "list": "allowed"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid list", func(t *testing.T) {
+		mockRepo := &mocks.MockSenderListRepository{}
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPost, "/mail/senders", gin.H{"sender": "example.com", "list": "other"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := &mocks.MockSenderListRepository{}
+		mockRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodPost, "/mail/senders", gin.H{"sender": "friend@example.com", "list": "allowed"})
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestDeleteSender(t *testing.T) {
+	userID := primitive.NewObjectID()
+	entryID := primitive.NewObjectID()
+
+	t.Run("deletes the sender", func(t *testing.T) {
+		mockRepo := &mocks.MockSenderListRepository{}
+		mockRepo.On("GetByID", mock.Anything, entryID).Return(&models.SenderListEntry{ID: &entryID, UserID: userID}, nil)
+		mockRepo.On("Delete", mock.Anything, entryID).Return(nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodDelete, "/mail/senders/"+entryID.Hex(), nil)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("sender of another user", func(t *testing.T) {
+		mockRepo := &mocks.MockSenderListRepository{}
+		mockRepo.On("GetByID", mock.Anything, entryID).Return(&models.SenderListEntry{ID: &entryID, UserID: primitive.NewObjectID()}, nil)
+
+		w := request(setupRouter(mockRepo, &userID), http.MethodDelete, "/mail/senders/"+entryID.Hex(), nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		w := request(setupRouter(&mocks.MockSenderListRepository{}, &userID), http.MethodDelete, "/mail/senders/invalid", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	userv1 "github.com/atomic-blend/backend/grpc/gen/user/v1"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
 	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/mail/utils/threading"
 	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
@@ -79,6 +80,14 @@ func (c *Controller) CreateSendMail(ctx *gin.Context) {
 		rawMail.Headers = normalizedHeaders
 	}
 
+	// give the mail a Message-ID so that the replies can be threaded with it
+	if threading.Header(rawMail.Headers, "Message-ID") == "" {
+		if rawMail.Headers == nil {
+			rawMail.Headers = make(map[string]interface{})
+		}
+		rawMail.Headers["Message-ID"] = threading.NewMessageID(threading.Header(rawMail.Headers, "From"))
+	}
+
 	//TODO: check email validity here
 
 	log.Debug().Interface("raw_mail", rawMail).Msg("Received raw mail for sending")
@@ -114,6 +123,17 @@ func (c *Controller) CreateSendMail(ctx *gin.Context) {
 		SendStatus: models.SendStatusPending,
 		Trashed:    false,
 	}
+	sendMail.Mail.UserID = authUser.UserID
+
+	// thread the mail with the conversation it replies to, before its headers are encrypted
+	if c.threadKeyer != nil {
+		thread, err := c.threadKeyer.Resolve(ctx, authUser.UserID, rawMail.Headers, c.mailRepo, c.sendMailRepo)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to look up the thread of the mail")
+		}
+		sendMail.Mail.MessageKey = thread.MessageKey
+		sendMail.Mail.ThreadID = thread.ThreadID
+	}
 
 	// Generate encrypted attachments upload requests to send them to S3
 	encryptedAttachments := make([]*awss3.PutObjectInput, 0)
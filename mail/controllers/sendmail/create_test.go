@@ -145,7 +145,7 @@ func TestSendMailController_CreateSendMail(t *testing.T) {
 			tt.setupAMQPMock(mockAMQPService, userID)
 			tt.setupS3Mock(mockS3Service, userID)
 
-			controller := NewSendMailController(mockRepo, mockUserClient, mockAMQPService, mockS3Service)
+			controller := NewSendMailController(mockRepo, &mocks.MockMailRepository{}, mockUserClient, mockAMQPService, mockS3Service)
 
 			router := gin.New()
 			router.Use(func(c *gin.Context) {
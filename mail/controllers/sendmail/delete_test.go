@@ -84,7 +84,7 @@ func TestSendMailController_DeleteSendMail(t *testing.T) {
 
 			tt.setupMock(mockRepo, userID, sendMailID)
 
-			controller := NewSendMailController(mockRepo, mockUserClient, mockAMQPService, mockS3Service)
+			controller := NewSendMailController(mockRepo, &mocks.MockMailRepository{}, mockUserClient, mockAMQPService, mockS3Service)
 
 			router := gin.New()
 			router.Use(func(c *gin.Context) {
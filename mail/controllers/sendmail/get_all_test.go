@@ -77,7 +77,7 @@ func TestSendMailController_GetAllSendMails(t *testing.T) {
 			userID := primitive.NewObjectID()
 			tt.setupMock(mockRepo, userID)
 
-			controller := NewSendMailController(mockRepo, mockUserClient, mockAMQPService, mockS3Service)
+			controller := NewSendMailController(mockRepo, &mocks.MockMailRepository{}, mockUserClient, mockAMQPService, mockS3Service)
 
 			router := gin.New()
 			router.Use(func(c *gin.Context) {
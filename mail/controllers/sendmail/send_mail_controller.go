@@ -2,11 +2,13 @@ package sendmail
 
 import (
 	"github.com/atomic-blend/backend/mail/repositories"
+	"github.com/atomic-blend/backend/mail/utils/threading"
 	userclient "github.com/atomic-blend/backend/shared/grpc/user"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
 	amqpinterfaces "github.com/atomic-blend/backend/shared/services/amqp/interfaces"
 	s3service "github.com/atomic-blend/backend/shared/services/s3"
 	s3interfaces "github.com/atomic-blend/backend/shared/services/s3/interfaces"
+	"github.com/rs/zerolog/log"
 	"github.com/webstradev/gin-pagination/v2/pkg/pagination"
 
 	"github.com/gin-gonic/gin"
@@ -16,33 +18,43 @@ import (
 // Controller handles send mail related operations
 type Controller struct {
 	sendMailRepo repositories.SendMailRepositoryInterface
+	mailRepo     repositories.MailRepositoryInterface
 	userClient   userclient.Interface
 	amqpService  amqpinterfaces.AMQPServiceInterface
 	s3Service    s3interfaces.S3ServiceInterface
+	// threadKeyer computes the thread of the sent mails, nil when threading is not configured
+	threadKeyer *threading.Keyer
 }
 
 // NewSendMailController creates a new send mail controller instance
-func NewSendMailController(sendMailRepo repositories.SendMailRepositoryInterface, userClient userclient.Interface, amqpService amqpinterfaces.AMQPServiceInterface, s3Service s3interfaces.S3ServiceInterface) *Controller {
+func NewSendMailController(sendMailRepo repositories.SendMailRepositoryInterface, mailRepo repositories.MailRepositoryInterface, userClient userclient.Interface, amqpService amqpinterfaces.AMQPServiceInterface, s3Service s3interfaces.S3ServiceInterface) *Controller {
+	threadKeyer, err := threading.NewKeyer()
+	if err != nil {
+		log.Warn().Err(err).Msg("Sent mails are not threaded")
+	}
 	return &Controller{
 		sendMailRepo: sendMailRepo,
+		mailRepo:     mailRepo,
 		userClient:   userClient,
 		amqpService:  amqpService,
 		s3Service:    s3Service,
+		threadKeyer:  threadKeyer,
 	}
 }
 
 // SetupRoutes sets up the send mail routes
 func SetupRoutes(router *gin.Engine, database *mongo.Database, amqpService amqpinterfaces.AMQPServiceInterface) {
 	sendMailRepo := repositories.NewSendMailRepository(database)
+	mailRepo := repositories.NewMailRepository(database)
 	userClient, _ := userclient.NewUserClient()
 	s3Service, _ := s3service.NewS3Service()
-	sendMailController := NewSendMailController(sendMailRepo, userClient, amqpService, s3Service)
+	sendMailController := NewSendMailController(sendMailRepo, mailRepo, userClient, amqpService, s3Service)
 	setupSendMailRoutes(router, sendMailController)
 }
 
 // SetupRoutesWithMock sets up the send mail routes with mock services for testing
-func SetupRoutesWithMock(router *gin.Engine, sendMailRepo repositories.SendMailRepositoryInterface, userClient userclient.Interface, amqpService amqpinterfaces.AMQPServiceInterface, s3Service s3interfaces.S3ServiceInterface) {
-	sendMailController := NewSendMailController(sendMailRepo, userClient, amqpService, s3Service)
+func SetupRoutesWithMock(router *gin.Engine, sendMailRepo repositories.SendMailRepositoryInterface, mailRepo repositories.MailRepositoryInterface, userClient userclient.Interface, amqpService amqpinterfaces.AMQPServiceInterface, s3Service s3interfaces.S3ServiceInterface) {
+	sendMailController := NewSendMailController(sendMailRepo, mailRepo, userClient, amqpService, s3Service)
 	setupSendMailRoutes(router, sendMailController)
 }
 
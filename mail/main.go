@@ -9,6 +9,7 @@ import (
 	"github.com/atomic-blend/backend/mail/controllers"
 	"github.com/atomic-blend/backend/mail/controllers/health"
 	trashcleanup "github.com/atomic-blend/backend/mail/cron/trash_cleanup"
+	"github.com/atomic-blend/backend/mail/repositories"
 	amqpservice "github.com/atomic-blend/backend/shared/services/amqp"
 	"github.com/atomic-blend/backend/shared/utils/db"
 	"github.com/jasonlvhit/gocron"
@@ -40,6 +41,19 @@ func main() {
 		log.Fatal().Msg("✅ Disconnected from MongoDB")
 	}()
 
+	if err := repositories.EnsureMailIndexes(context.TODO(), db.Database); err != nil {
+		log.Error().Err(err).Msg("❌ Error creating mail indexes")
+	}
+	if err := repositories.EnsureSendMailIndexes(context.TODO(), db.Database); err != nil {
+		log.Error().Err(err).Msg("❌ Error creating send mail indexes")
+	}
+	if err := repositories.EnsureMailFilterIndexes(context.TODO(), db.Database); err != nil {
+		log.Error().Err(err).Msg("❌ Error creating mail filter indexes")
+	}
+	if err := repositories.EnsureSenderListIndexes(context.TODO(), db.Database); err != nil {
+		log.Error().Err(err).Msg("❌ Error creating sender list indexes")
+	}
+
 	// Setup router with middleware
 	router := gin.Default()
 
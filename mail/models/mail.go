@@ -15,21 +15,26 @@ type MailAttachment struct {
 
 // Mail represents a mail message
 type Mail struct {
-	ID             *primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	UserID         primitive.ObjectID  `bson:"user_id" json:"userId"`
-	Headers        interface{}         `bson:"headers" json:"headers"`
-	TextContent    string              `bson:"text_content" json:"textContent"`
-	HTMLContent    string              `bson:"html_content" json:"htmlContent"`
-	Attachments    []MailAttachment    `bson:"attachments,omitempty" json:"attachments,omitempty"`
-	Read           *bool               `bson:"read,omitempty" json:"read,omitempty"`
-	Archived       *bool               `bson:"archived,omitempty" json:"archived,omitempty"`
-	Trashed        *bool               `bson:"trashed,omitempty" json:"trashed,omitempty"`
-	TrashedAt      *primitive.DateTime `bson:"trashed_at,omitempty" json:"trashedAt,omitempty"`
-	Greylisted     *bool               `bson:"graylisted,omitempty" json:"graylisted,omitempty"`
-	Rejected       *bool               `bson:"rejected,omitempty" json:"rejected,omitempty"`
-	RewriteSubject *bool               `bson:"rewrite_subject,omitempty" json:"rewriteSubject,omitempty"`
-	CreatedAt      *primitive.DateTime `bson:"created_at,omitempty" json:"createdAt,omitempty"`
-	UpdatedAt      *primitive.DateTime `bson:"updated_at,omitempty" json:"updatedAt,omitempty"`
+	ID             *primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID         primitive.ObjectID   `bson:"user_id" json:"userId"`
+	ThreadID       string               `bson:"thread_id,omitempty" json:"threadId,omitempty"` // keyed ID of the conversation of the mail
+	MessageKey     string               `bson:"message_key,omitempty" json:"-"`                // keyed Message-ID of the mail
+	FolderID       *primitive.ObjectID  `bson:"folder_id,omitempty" json:"folderId,omitempty"` // folder of the mail, the inbox when nil
+	TagIDs         []primitive.ObjectID `bson:"tag_ids,omitempty" json:"tagIds,omitempty"`     // labels of the mail
+	Headers        interface{}          `bson:"headers" json:"headers"`
+	TextContent    string               `bson:"text_content" json:"textContent"`
+	HTMLContent    string               `bson:"html_content" json:"htmlContent"`
+	Attachments    []MailAttachment     `bson:"attachments,omitempty" json:"attachments,omitempty"`
+	Read           *bool                `bson:"read,omitempty" json:"read,omitempty"`
+	Archived       *bool                `bson:"archived,omitempty" json:"archived,omitempty"`
+	Trashed        *bool                `bson:"trashed,omitempty" json:"trashed,omitempty"`
+	TrashedAt      *primitive.DateTime  `bson:"trashed_at,omitempty" json:"trashedAt,omitempty"`
+	Spam           *bool                `bson:"spam,omitempty" json:"spam,omitempty"` // classified as spam, by the spam filter or by the user
+	Greylisted     *bool                `bson:"graylisted,omitempty" json:"graylisted,omitempty"`
+	Rejected       *bool                `bson:"rejected,omitempty" json:"rejected,omitempty"`
+	RewriteSubject *bool                `bson:"rewrite_subject,omitempty" json:"rewriteSubject,omitempty"`
+	CreatedAt      *primitive.DateTime  `bson:"created_at,omitempty" json:"createdAt,omitempty"`
+	UpdatedAt      *primitive.DateTime  `bson:"updated_at,omitempty" json:"updatedAt,omitempty"`
 }
 
 // Encrypt encrypts the mail data
@@ -37,9 +42,14 @@ func (s *Mail) Encrypt(publicKey string) (*Mail, error) {
 	encryptedMail := &Mail{
 		ID:             s.ID,
 		UserID:         s.UserID,
+		ThreadID:       s.ThreadID,
+		MessageKey:     s.MessageKey,
+		FolderID:       s.FolderID,
+		TagIDs:         s.TagIDs,
 		Attachments:    s.Attachments,
 		Archived:       s.Archived,
 		Trashed:        s.Trashed,
+		Spam:           s.Spam,
 		Greylisted:     s.Greylisted,
 		Rejected:       s.Rejected,
 		RewriteSubject: s.RewriteSubject,
@@ -51,3 +61,13 @@ func (s *Mail) Encrypt(publicKey string) (*Mail, error) {
 
 	return encryptedMail, nil
 }
+
+// MailThread is a conversation, the mails sharing the same thread ID
+type MailThread struct {
+	ThreadID     string              `bson:"_id" json:"threadId"`
+	MessageCount int64               `bson:"message_count" json:"messageCount"`
+	UnreadCount  int64               `bson:"unread_count" json:"unreadCount"`
+	LatestAt     *primitive.DateTime `bson:"latest_at" json:"latestAt"`
+	// Mails are the mails of the thread, most recent first
+	Mails []*Mail `bson:"mails" json:"mails"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// MailFilter is the Sieve script filtering the incoming mails of a user.
+// It is stored in clear as it is evaluated when a mail is received, before the mail is encrypted.
+type MailFilter struct {
+	ID        *primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID    primitive.ObjectID  `bson:"user_id" json:"userId"`
+	Script    string              `bson:"script" json:"script"`
+	Enabled   bool                `bson:"enabled" json:"enabled"`
+	CreatedAt *primitive.DateTime `bson:"created_at,omitempty" json:"createdAt,omitempty"`
+	UpdatedAt *primitive.DateTime `bson:"updated_at,omitempty" json:"updatedAt,omitempty"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const (
+	// SenderListAllowed is the list of the senders whose mails are never classified as spam
+	SenderListAllowed = "allowed"
+	// SenderListBlocked is the list of the senders whose mails are always classified as spam
+	SenderListBlocked = "blocked"
+)
+
+// SenderListEntry is a sender of the allow list or of the block list of a user
+type SenderListEntry struct {
+	ID     *primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID primitive.ObjectID  `bson:"user_id" json:"userId"`
+	// Sender is an address, or a domain which matches its subdomains as well
+	Sender    string              `bson:"sender" json:"sender" binding:"required"`
+	List      string              `bson:"list" json:"list" binding:"required,oneof=allowed blocked"`
+	CreatedAt *primitive.DateTime `bson:"created_at,omitempty" json:"createdAt,omitempty"`
+	UpdatedAt *primitive.DateTime `bson:"updated_at,omitempty" json:"updatedAt,omitempty"`
+}
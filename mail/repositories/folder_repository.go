@@ -17,6 +17,7 @@ const folderCollection = "folders"
 // FolderRepositoryInterface defines the interface for folder repository operations
 type FolderRepositoryInterface interface {
 	GetAll(ctx context.Context, userID primitive.ObjectID) ([]*models.Folder, error)
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.Folder, error)
 	Create(ctx context.Context, folder *models.Folder) (*models.Folder, error)
 	Update(ctx context.Context, id primitive.ObjectID, folder *models.Folder) (*models.Folder, error)
 	Delete(ctx context.Context, id primitive.ObjectID) error
@@ -92,14 +93,27 @@ func (r *FolderRepository) Create(ctx context.Context, folder *models.Folder) (*
 	return folder, nil
 }
 
-// Delete removes a folder
+// Delete removes a folder, its mails move back to the inbox and its subfolders to its parent
 func (r *FolderRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
-	// First, get all tasks that belong to this folder and remove the folder_id
+	folder, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if folder == nil {
+		return nil
+	}
+
+	// Update mails collection to remove folder reference, so synced clients see the mails back in the inbox
+	now := primitive.NewDateTimeFromTime(time.Now())
 	filter := bson.M{"folder_id": id}
-	update := bson.M{"$set": bson.M{"folder_id": nil}}
+	update := bson.M{"$set": bson.M{"folder_id": nil, "updated_at": now}}
+	_, err = r.collection.Database().Collection(mailCollection).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
 
-	// Update tasks collection to remove folder reference
-	_, err := r.collection.Database().Collection("tasks").UpdateMany(ctx, filter, update)
+	// Move the subfolders to the parent of the folder
+	_, err = r.collection.UpdateMany(ctx, bson.M{"parent_id": id}, bson.M{"$set": bson.M{"parent_id": folder.ParentID, "updated_at": now}})
 	if err != nil {
 		return err
 	}
@@ -112,6 +126,10 @@ func (r *FolderRepository) Delete(ctx context.Context, id primitive.ObjectID) er
 
 // Update modifies an existing folder
 func (r *FolderRepository) Update(ctx context.Context, id primitive.ObjectID, folder *models.Folder) (*models.Folder, error) {
+	now := primitive.NewDateTimeFromTime(time.Now())
+	folder.ID = &id
+	folder.UpdatedAt = &now
+
 	filter := bson.M{"_id": id}
 	update := bson.M{"$set": folder}
 
@@ -131,12 +149,13 @@ func (r *FolderRepository) DeleteByUserID(ctx context.Context, userID primitive.
 		return err
 	}
 
-	// For each folder, remove folder references from tasks
+	// For each folder, remove folder references from mails
+	now := primitive.NewDateTimeFromTime(time.Now())
 	for _, folder := range folders {
 		if folder.ID != nil {
 			filter := bson.M{"folder_id": *folder.ID}
-			update := bson.M{"$set": bson.M{"folder_id": nil}}
-			_, err := r.collection.Database().Collection("tasks").UpdateMany(ctx, filter, update)
+			update := bson.M{"$set": bson.M{"folder_id": nil, "updated_at": now}}
+			_, err := r.collection.Database().Collection(mailCollection).UpdateMany(ctx, filter, update)
 			if err != nil {
 				return err
 			}
@@ -158,6 +158,44 @@ func TestFolderRepository_Delete(t *testing.T) {
 		require.NoError(t, err)
 		assert.Len(t, folders, 0)
 	})
+
+	t.Run("mails move back to the inbox and subfolders to the parent", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		parent := createTestFolder()
+		parent.UserID = userID
+		parent, err := repo.Create(context.Background(), parent)
+		require.NoError(t, err)
+
+		folder := createTestFolder()
+		folder.UserID = userID
+		folder.ParentID = parent.ID
+		folder, err = repo.Create(context.Background(), folder)
+		require.NoError(t, err)
+
+		child := createTestFolder()
+		child.UserID = userID
+		child.ParentID = folder.ID
+		child, err = repo.Create(context.Background(), child)
+		require.NoError(t, err)
+
+		mailRepo := NewMailRepository(repo.(*FolderRepository).collection.Database())
+		mail := &models.Mail{UserID: userID, FolderID: folder.ID}
+		_, err = mailRepo.Create(context.Background(), mail)
+		require.NoError(t, err)
+
+		deletedAt := time.Now().Truncate(time.Millisecond)
+		err = repo.Delete(context.Background(), *folder.ID)
+		require.NoError(t, err)
+
+		movedChild, err := repo.GetByID(context.Background(), *child.ID)
+		require.NoError(t, err)
+		assert.Equal(t, parent.ID, movedChild.ParentID)
+
+		movedMail, err := mailRepo.GetByID(context.Background(), *mail.ID)
+		require.NoError(t, err)
+		assert.Nil(t, movedMail.FolderID)
+		assert.False(t, movedMail.UpdatedAt.Time().Before(deletedAt))
+	})
 }
 
 func TestFolderRepository_DeleteByUserID(t *testing.T) {
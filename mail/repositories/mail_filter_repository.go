@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/shared/utils/db"
+
+	bson "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const mailFilterCollection = "mail_filters"
+
+// MailFilterRepositoryInterface defines the interface for mail filter repository operations
+type MailFilterRepositoryInterface interface {
+	// GetByUserID returns the mail filter of a user, nil when the user has none
+	GetByUserID(ctx context.Context, userID primitive.ObjectID) (*models.MailFilter, error)
+	// Upsert creates or replaces the mail filter of a user
+	Upsert(ctx context.Context, filter *models.MailFilter) (*models.MailFilter, error)
+	DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// EnsureMailFilterIndexes creates the index holding a single mail filter per user
+func EnsureMailFilterIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(mailFilterCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// MailFilterRepository handles database operations related to mail filters
+type MailFilterRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMailFilterRepository creates a new mail filter repository instance
+func NewMailFilterRepository(database *mongo.Database) MailFilterRepositoryInterface {
+	if database == nil {
+		database = db.Database
+	}
+	return &MailFilterRepository{
+		collection: database.Collection(mailFilterCollection),
+	}
+}
+
+// GetByUserID retrieves the mail filter of a user
+func (r *MailFilterRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID) (*models.MailFilter, error) {
+	var filter models.MailFilter
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&filter)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &filter, nil
+}
+
+// Upsert creates the mail filter of a user or replaces its script
+func (r *MailFilterRepository) Upsert(ctx context.Context, filter *models.MailFilter) (*models.MailFilter, error) {
+	now := primitive.NewDateTimeFromTime(time.Now())
+
+	update := bson.M{
+		"$set": bson.M{
+			"script":     filter.Script,
+			"enabled":    filter.Enabled,
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": now,
+		},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var updated models.MailFilter
+	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"user_id": filter.UserID}, update, opts).Decode(&updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteByUserID deletes the mail filter of a user
+func (r *MailFilterRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/shared/test_utils/inmemorymongo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func setupMailFilterTest(t *testing.T) (MailFilterRepositoryInterface, func()) {
+	// Start in-memory MongoDB server
+	mongoServer, err := inmemorymongo.CreateInMemoryMongoDB()
+	require.NoError(t, err)
+
+	// Connect to the in-memory MongoDB
+	client, err := inmemorymongo.ConnectToInMemoryDB(mongoServer.URI())
+	require.NoError(t, err)
+
+	// Get database reference
+	db := client.Database("test_db")
+	require.NoError(t, EnsureMailFilterIndexes(context.Background(), db))
+
+	repo := NewMailFilterRepository(db)
+
+	// Return cleanup function
+	cleanup := func() {
+		client.Disconnect(context.Background())
+		mongoServer.Stop()
+	}
+
+	return repo, cleanup
+}
+
+func TestMailFilterRepository_Upsert(t *testing.T) {
+	repo, cleanup := setupMailFilterTest(t)
+	defer cleanup()
+
+	userID := primitive.NewObjectID()
+
+	t.Run("no filter", func(t *testing.T) {
+		filter, err := repo.GetByUserID(context.Background(), userID)
+		require.NoError(t, err)
+		assert.Nil(t, filter)
+	})
+
+	t.Run("create then replace the filter", func(t *testing.T) {
+		created, err := repo.Upsert(context.Background(), &models.MailFilter{UserID: userID, Script: "keep;", Enabled: true})
+		require.NoError(t, err)
+		require.NotNil(t, created.ID)
+		assert.Equal(t, userID, created.UserID)
+		assert.NotNil(t, created.CreatedAt)
+
+		updated, err := repo.Upsert(context.Background(), &models.MailFilter{UserID: userID, Script: "discard;", Enabled: false})
+		require.NoError(t, err)
+		assert.Equal(t, *created.ID, *updated.ID)
+		assert.Equal(t, *created.CreatedAt, *updated.CreatedAt)
+
+		found, err := repo.GetByUserID(context.Background(), userID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, "discard;", found.Script)
+		assert.False(t, found.Enabled)
+	})
+
+	t.Run("delete the filters of a user", func(t *testing.T) {
+		otherUserID := primitive.NewObjectID()
+		_, err := repo.Upsert(context.Background(), &models.MailFilter{UserID: otherUserID, Script: "keep;", Enabled: true})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.DeleteByUserID(context.Background(), userID))
+
+		found, err := repo.GetByUserID(context.Background(), userID)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+
+		found, err = repo.GetByUserID(context.Background(), otherUserID)
+		require.NoError(t, err)
+		assert.NotNil(t, found)
+	})
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/atomic-blend/backend/mail/models"
@@ -28,6 +29,28 @@ type MailRepositoryInterface interface {
 	CleanupTrash(ctx context.Context, userID *primitive.ObjectID, days *int) error
 	// GetSince retrieves mails where updated_at is after the specified time for a specific user. If page and limit are >0, returns paginated results and total count. If page or limit <=0, returns all mails and total count.
 	GetSince(ctx context.Context, userID primitive.ObjectID, since time.Time, page, limit int64) ([]*models.Mail, int64, error)
+	// GetThreads retrieves the conversations of a user, most recently active first, trashed and spam mails are left out. Pagination works as in GetAll.
+	GetThreads(ctx context.Context, userID primitive.ObjectID, page, limit int64) ([]*models.MailThread, int64, error)
+	// GetAllInFolder retrieves the mails of a user in a folder, most recent first. Pagination works as in GetAll.
+	GetAllInFolder(ctx context.Context, userID, folderID primitive.ObjectID, page, limit int64) ([]*models.Mail, int64, error)
+	// GetAllWithTag retrieves the mails of a user with a label, most recent first. Pagination works as in GetAll.
+	GetAllWithTag(ctx context.Context, userID, tagID primitive.ObjectID, page, limit int64) ([]*models.Mail, int64, error)
+	// GetAllSpam retrieves the spam mails of a user which are not trashed, most recent first. Pagination works as in GetAll.
+	GetAllSpam(ctx context.Context, userID primitive.ObjectID, page, limit int64) ([]*models.Mail, int64, error)
+	// FindThreadID returns the thread of the first mail of the user with one of the given message keys, empty when there is none
+	FindThreadID(ctx context.Context, userID primitive.ObjectID, messageKeys []string) (string, error)
+}
+
+// EnsureMailIndexes creates the indexes used to look mails up by message key, to group them by thread and to list them by folder, label or as spam
+func EnsureMailIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(mailCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "message_key", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "thread_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "folder_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "tag_ids", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "spam", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	return err
 }
 
 // MailRepository handles database operations related to mails
@@ -167,6 +190,9 @@ func (r *MailRepository) Update(ctx context.Context, mail *models.Mail) error {
 			"archived":   mail.Archived,
 			"trashed":    mail.Trashed,
 			"trashed_at": mail.TrashedAt,
+			"spam":       mail.Spam,
+			"folder_id":  mail.FolderID,
+			"tag_ids":    mail.TagIDs,
 			"updated_at": mail.UpdatedAt,
 		},
 	}
@@ -232,3 +258,186 @@ func (r *MailRepository) GetSince(ctx context.Context, userID primitive.ObjectID
 
 	return mails, totalCount, nil
 }
+
+// GetThreads retrieves the conversations of a user, most recently active first, trashed and spam mails are left out. Pagination works as in GetAll.
+// Mails received before threading was introduced have no thread ID and make a thread of their own.
+func (r *MailRepository) GetThreads(ctx context.Context, userID primitive.ObjectID, page, limit int64) ([]*models.MailThread, int64, error) {
+	threadsPipeline := bson.A{bson.M{"$sort": bson.D{{Key: "latest_at", Value: -1}, {Key: "_id", Value: 1}}}}
+	if page > 0 && limit > 0 {
+		threadsPipeline = append(threadsPipeline, bson.M{"$skip": (page - 1) * limit}, bson.M{"$limit": limit})
+	}
+
+	// only the counters of the threads are grouped, the mails of the page being fetched afterwards,
+	// so that the size of the result does not grow with the mailbox
+	match := bson.M{"user_id": userID, "trashed": bson.M{"$ne": true}, "spam": bson.M{"$ne": true}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":           bson.M{"$ifNull": bson.A{"$thread_id", bson.M{"$toString": "$_id"}}},
+			"message_count": bson.M{"$sum": 1},
+			"unread_count":  bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$read", true}}, 0, 1}}},
+			"latest_at":     bson.M{"$max": "$created_at"},
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"threads": threadsPipeline,
+			"total":   bson.A{bson.M{"$count": "count"}},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return []*models.MailThread{}, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Threads []*models.MailThread `bson:"threads"`
+		Total   []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return []*models.MailThread{}, 0, err
+	}
+	if len(results) == 0 || len(results[0].Total) == 0 {
+		return []*models.MailThread{}, 0, nil
+	}
+	threads := results[0].Threads
+	if len(threads) == 0 {
+		return threads, results[0].Total[0].Count, nil
+	}
+
+	// a mail without thread ID is a thread of its own, keyed by its ID
+	threadIDs := make([]string, 0, len(threads))
+	mailIDs := make([]primitive.ObjectID, 0, len(threads))
+	byID := make(map[string]*models.MailThread, len(threads))
+	for _, thread := range threads {
+		thread.Mails = []*models.Mail{}
+		byID[thread.ThreadID] = thread
+		threadIDs = append(threadIDs, thread.ThreadID)
+		if mailID, err := primitive.ObjectIDFromHex(thread.ThreadID); err == nil {
+			mailIDs = append(mailIDs, mailID)
+		}
+	}
+	match["$or"] = bson.A{
+		bson.M{"thread_id": bson.M{"$in": threadIDs}},
+		bson.M{"thread_id": nil, "_id": bson.M{"$in": mailIDs}},
+	}
+	mailsCursor, err := r.collection.Find(ctx, match, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return []*models.MailThread{}, 0, err
+	}
+	defer mailsCursor.Close(ctx)
+
+	var mails []*models.Mail
+	if err := mailsCursor.All(ctx, &mails); err != nil {
+		return []*models.MailThread{}, 0, err
+	}
+	for _, mail := range mails {
+		threadID := mail.ThreadID
+		if threadID == "" {
+			threadID = mail.ID.Hex()
+		}
+		if thread, ok := byID[threadID]; ok {
+			thread.Mails = append(thread.Mails, mail)
+		}
+	}
+
+	return threads, results[0].Total[0].Count, nil
+}
+
+// GetAllInFolder retrieves the mails of a user in a folder, most recent first. Pagination works as in GetAll.
+func (r *MailRepository) GetAllInFolder(ctx context.Context, userID, folderID primitive.ObjectID, page, limit int64) ([]*models.Mail, int64, error) {
+	return r.getPage(ctx, bson.M{"user_id": userID, "folder_id": folderID}, page, limit)
+}
+
+// GetAllWithTag retrieves the mails of a user with a label, most recent first. Pagination works as in GetAll.
+func (r *MailRepository) GetAllWithTag(ctx context.Context, userID, tagID primitive.ObjectID, page, limit int64) ([]*models.Mail, int64, error) {
+	return r.getPage(ctx, bson.M{"user_id": userID, "tag_ids": tagID}, page, limit)
+}
+
+// GetAllSpam retrieves the spam mails of a user which are not trashed, most recent first. Pagination works as in GetAll.
+func (r *MailRepository) GetAllSpam(ctx context.Context, userID primitive.ObjectID, page, limit int64) ([]*models.Mail, int64, error) {
+	return r.getPage(ctx, bson.M{"user_id": userID, "spam": true, "trashed": bson.M{"$ne": true}}, page, limit)
+}
+
+// getPage retrieves the mails matching a filter sorted by created_at desc, with the total count of the matching mails
+func (r *MailRepository) getPage(ctx context.Context, filter bson.M, page, limit int64) ([]*models.Mail, int64, error) {
+	totalCount, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return []*models.Mail{}, 0, err
+	}
+
+	findOpts := options.Find()
+	findOpts.SetSort(bson.D{{Key: "created_at", Value: -1}})
+	if page > 0 && limit > 0 {
+		findOpts.SetSkip((page - 1) * limit)
+		findOpts.SetLimit(limit)
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return []*models.Mail{}, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	mails := []*models.Mail{}
+	if err = cursor.All(ctx, &mails); err != nil {
+		return []*models.Mail{}, 0, err
+	}
+
+	return mails, totalCount, nil
+}
+
+// FindThreadID returns the thread of the first mail of the user with one of the given message keys, empty when there is none
+func (r *MailRepository) FindThreadID(ctx context.Context, userID primitive.ObjectID, messageKeys []string) (string, error) {
+	return findThreadID(ctx, r.collection, "", userID, messageKeys)
+}
+
+// findThreadID looks up the thread of the mails of a collection by message key, the keys being tried in order.
+// prefix is the path of the mail in the documents of the collection.
+func findThreadID(ctx context.Context, collection *mongo.Collection, prefix string, userID primitive.ObjectID, messageKeys []string) (string, error) {
+	if len(messageKeys) == 0 {
+		return "", nil
+	}
+
+	filter := bson.M{
+		prefix + "user_id":     userID,
+		prefix + "message_key": bson.M{"$in": messageKeys},
+		prefix + "thread_id":   bson.M{"$exists": true, "$ne": ""},
+	}
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{
+		prefix + "message_key": 1,
+		prefix + "thread_id":   1,
+	}))
+	if err != nil {
+		return "", err
+	}
+	defer cursor.Close(ctx)
+
+	threads := map[string]string{}
+	for cursor.Next(ctx) {
+		var document bson.M
+		if err := cursor.Decode(&document); err != nil {
+			return "", err
+		}
+		if prefix != "" {
+			document, _ = document[strings.TrimSuffix(prefix, ".")].(bson.M)
+		}
+		key, _ := document["message_key"].(string)
+		thread, _ := document["thread_id"].(string)
+		if _, ok := threads[key]; !ok {
+			threads[key] = thread
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return "", err
+	}
+
+	for _, key := range messageKeys {
+		if thread, ok := threads[key]; ok {
+			return thread, nil
+		}
+	}
+	return "", nil
+}
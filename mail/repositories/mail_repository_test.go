@@ -1304,6 +1304,177 @@ func TestMailRepository_GetSince(t *testing.T) {
 	})
 }
 
+func TestMailRepository_GetAllInFolderAndWithTag(t *testing.T) {
+	repo, cleanup := setupMailTest(t)
+	defer cleanup()
+
+	userID := primitive.NewObjectID()
+	folderID := primitive.NewObjectID()
+	tagID := primitive.NewObjectID()
+
+	base := time.Now()
+	for i := 0; i < 3; i++ {
+		mail := createTestMail(userID)
+		createdAt := primitive.NewDateTimeFromTime(base.Add(time.Duration(i) * time.Millisecond))
+		mail.CreatedAt = &createdAt
+		mail.FolderID = &folderID
+		if i > 0 {
+			mail.TagIDs = []primitive.ObjectID{tagID}
+		}
+		_, err := repo.Create(context.Background(), mail)
+		require.NoError(t, err)
+	}
+
+	// mails of the inbox and of another user are left out
+	_, err := repo.Create(context.Background(), createTestMail(userID))
+	require.NoError(t, err)
+	otherMail := createTestMail(primitive.NewObjectID())
+	otherMail.FolderID = &folderID
+	otherMail.TagIDs = []primitive.ObjectID{tagID}
+	_, err = repo.Create(context.Background(), otherMail)
+	require.NoError(t, err)
+
+	t.Run("mails in a folder, paginated", func(t *testing.T) {
+		mails, total, err := repo.GetAllInFolder(context.Background(), userID, folderID, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		require.Len(t, mails, 2)
+		assert.True(t, mails[0].CreatedAt.Time().After(mails[1].CreatedAt.Time()))
+	})
+
+	t.Run("mails with a label", func(t *testing.T) {
+		mails, total, err := repo.GetAllWithTag(context.Background(), userID, tagID, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Len(t, mails, 2)
+	})
+
+	t.Run("empty folder", func(t *testing.T) {
+		mails, total, err := repo.GetAllInFolder(context.Background(), userID, primitive.NewObjectID(), 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+		assert.Empty(t, mails)
+	})
+}
+
+func TestMailRepository_GetAllSpam(t *testing.T) {
+	repo, cleanup := setupMailTest(t)
+	defer cleanup()
+
+	userID := primitive.NewObjectID()
+	spam, notSpam, trashed := true, false, true
+
+	spamMail := createTestMail(userID)
+	spamMail.Spam = &spam
+	_, err := repo.Create(context.Background(), spamMail)
+	require.NoError(t, err)
+
+	// mails which are not spam, trashed spam and the spam of another user are left out
+	hamMail := createTestMail(userID)
+	hamMail.Spam = &notSpam
+	_, err = repo.Create(context.Background(), hamMail)
+	require.NoError(t, err)
+	_, err = repo.Create(context.Background(), createTestMail(userID))
+	require.NoError(t, err)
+	trashedSpam := createTestMail(userID)
+	trashedSpam.Spam = &spam
+	trashedSpam.Trashed = &trashed
+	_, err = repo.Create(context.Background(), trashedSpam)
+	require.NoError(t, err)
+	otherSpam := createTestMail(primitive.NewObjectID())
+	otherSpam.Spam = &spam
+	_, err = repo.Create(context.Background(), otherSpam)
+	require.NoError(t, err)
+
+	t.Run("spam mails", func(t *testing.T) {
+		mails, total, err := repo.GetAllSpam(context.Background(), userID, 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, mails, 1)
+		assert.Equal(t, *spamMail.ID, *mails[0].ID)
+	})
+
+	t.Run("spam mails are left out of the threads", func(t *testing.T) {
+		threads, total, err := repo.GetThreads(context.Background(), userID, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		for _, thread := range threads {
+			for _, mail := range thread.Mails {
+				assert.NotEqual(t, *spamMail.ID, *mail.ID)
+			}
+		}
+	})
+
+	t.Run("not spam", func(t *testing.T) {
+		spamMail.Spam = &notSpam
+		require.NoError(t, repo.Update(context.Background(), spamMail))
+
+		mails, total, err := repo.GetAllSpam(context.Background(), userID, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+		assert.Empty(t, mails)
+	})
+}
+
+func TestMailRepository_GetThreads(t *testing.T) {
+	repo, cleanup := setupMailTest(t)
+	defer cleanup()
+
+	userID := primitive.NewObjectID()
+	read := true
+	create := func(threadID string, minutesAgo int, read *bool) *models.Mail {
+		mail := createTestMail(userID)
+		mail.ThreadID = threadID
+		mail.Read = read
+		createdAt := primitive.NewDateTimeFromTime(time.Now().Add(-time.Duration(minutesAgo) * time.Minute))
+		mail.CreatedAt = &createdAt
+		_, err := repo.Create(context.Background(), mail)
+		require.NoError(t, err)
+		return mail
+	}
+
+	// the conversation was answered after the single mail was received
+	first := create("thread-1", 30, &read)
+	single := create("", 20, nil)
+	reply := create("thread-1", 10, nil)
+	old := create("thread-2", 60, nil)
+
+	t.Run("threads of the first page with their mails", func(t *testing.T) {
+		threads, total, err := repo.GetThreads(context.Background(), userID, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		require.Len(t, threads, 2)
+
+		assert.Equal(t, "thread-1", threads[0].ThreadID)
+		assert.Equal(t, int64(2), threads[0].MessageCount)
+		assert.Equal(t, int64(1), threads[0].UnreadCount)
+		require.Len(t, threads[0].Mails, 2)
+		assert.Equal(t, *reply.ID, *threads[0].Mails[0].ID)
+		assert.Equal(t, *first.ID, *threads[0].Mails[1].ID)
+
+		assert.Equal(t, single.ID.Hex(), threads[1].ThreadID)
+		require.Len(t, threads[1].Mails, 1)
+		assert.Equal(t, *single.ID, *threads[1].Mails[0].ID)
+	})
+
+	t.Run("threads of the last page", func(t *testing.T) {
+		threads, total, err := repo.GetThreads(context.Background(), userID, 2, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		require.Len(t, threads, 1)
+		assert.Equal(t, "thread-2", threads[0].ThreadID)
+		require.Len(t, threads[0].Mails, 1)
+		assert.Equal(t, *old.ID, *threads[0].Mails[0].ID)
+	})
+
+	t.Run("no threads", func(t *testing.T) {
+		threads, total, err := repo.GetThreads(context.Background(), primitive.NewObjectID(), 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+		assert.Empty(t, threads)
+	})
+}
+
 func TestMailRepository_Integration(t *testing.T) {
 	repo, cleanup := setupMailTest(t)
 	defer cleanup()
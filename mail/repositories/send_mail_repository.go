@@ -23,6 +23,16 @@ type SendMailRepositoryInterface interface {
 	Create(ctx context.Context, sendMail *models.SendMail) (*models.SendMail, error)
 	Update(ctx context.Context, id primitive.ObjectID, update bson.M) (*models.SendMail, error)
 	Delete(ctx context.Context, id primitive.ObjectID) error
+	// FindThreadID returns the thread of the first sent mail of the user with one of the given message keys, empty when there is none
+	FindThreadID(ctx context.Context, userID primitive.ObjectID, messageKeys []string) (string, error)
+}
+
+// EnsureSendMailIndexes creates the index used to look sent mails up by message key
+func EnsureSendMailIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(sendMailCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "mail.user_id", Value: 1}, {Key: "mail.message_key", Value: 1}},
+	})
+	return err
 }
 
 // SendMailRepository handles database operations related to send mails
@@ -181,3 +191,8 @@ func (r *SendMailRepository) Delete(ctx context.Context, id primitive.ObjectID)
 	_, err := r.collection.UpdateOne(ctx, filter, update)
 	return err
 }
+
+// FindThreadID returns the thread of the first sent mail of the user with one of the given message keys, empty when there is none
+func (r *SendMailRepository) FindThreadID(ctx context.Context, userID primitive.ObjectID, messageKeys []string) (string, error) {
+	return findThreadID(ctx, r.collection, "mail.", userID, messageKeys)
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/shared/utils/db"
+
+	bson "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const senderListCollection = "sender_lists"
+
+// SenderListRepositoryInterface defines the interface for sender list repository operations
+type SenderListRepositoryInterface interface {
+	// GetAll retrieves the entries of the allow and block lists of a user, sorted by sender
+	GetAll(ctx context.Context, userID primitive.ObjectID) ([]*models.SenderListEntry, error)
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.SenderListEntry, error)
+	// GetBySenders retrieves the entries of a user for any of the given senders
+	GetBySenders(ctx context.Context, userID primitive.ObjectID, senders []string) ([]*models.SenderListEntry, error)
+	// Upsert adds a sender to a list of a user, moving it when it is in the other list
+	Upsert(ctx context.Context, entry *models.SenderListEntry) (*models.SenderListEntry, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// EnsureSenderListIndexes creates the index holding a sender once per user
+func EnsureSenderListIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(senderListCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "sender", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// SenderListRepository handles database operations related to sender lists
+type SenderListRepository struct {
+	collection *mongo.Collection
+}
+
+// NewSenderListRepository creates a new sender list repository instance
+func NewSenderListRepository(database *mongo.Database) SenderListRepositoryInterface {
+	if database == nil {
+		database = db.Database
+	}
+	return &SenderListRepository{
+		collection: database.Collection(senderListCollection),
+	}
+}
+
+// GetAll retrieves the entries of the allow and block lists of a user
+func (r *SenderListRepository) GetAll(ctx context.Context, userID primitive.ObjectID) ([]*models.SenderListEntry, error) {
+	return r.find(ctx, bson.M{"user_id": userID})
+}
+
+// GetByID retrieves an entry by its ID
+func (r *SenderListRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.SenderListEntry, error) {
+	var entry models.SenderListEntry
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// GetBySenders retrieves the entries of a user for any of the given senders
+func (r *SenderListRepository) GetBySenders(ctx context.Context, userID primitive.ObjectID, senders []string) ([]*models.SenderListEntry, error) {
+	if len(senders) == 0 {
+		return []*models.SenderListEntry{}, nil
+	}
+	return r.find(ctx, bson.M{"user_id": userID, "sender": bson.M{"$in": senders}})
+}
+
+// Upsert adds a sender to a list of a user, moving it when it is in the other list
+func (r *SenderListRepository) Upsert(ctx context.Context, entry *models.SenderListEntry) (*models.SenderListEntry, error) {
+	now := primitive.NewDateTimeFromTime(time.Now())
+
+	update := bson.M{
+		"$set": bson.M{
+			"list":       entry.List,
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": now,
+		},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var updated models.SenderListEntry
+	filter := bson.M{"user_id": entry.UserID, "sender": entry.Sender}
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// Delete removes an entry
+func (r *SenderListRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// DeleteByUserID removes the entries of a user
+func (r *SenderListRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
+
+func (r *SenderListRepository) find(ctx context.Context, filter bson.M) ([]*models.SenderListEntry, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "sender", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []*models.SenderListEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
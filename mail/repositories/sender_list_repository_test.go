@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/shared/test_utils/inmemorymongo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func setupSenderListTest(t *testing.T) (SenderListRepositoryInterface, func()) {
+	// Start in-memory MongoDB server
+	mongoServer, err := inmemorymongo.CreateInMemoryMongoDB()
+	require.NoError(t, err)
+
+	// Connect to the in-memory MongoDB
+	client, err := inmemorymongo.ConnectToInMemoryDB(mongoServer.URI())
+	require.NoError(t, err)
+
+	// Get database reference
+	db := client.Database("test_db")
+	require.NoError(t, EnsureSenderListIndexes(context.Background(), db))
+
+	repo := NewSenderListRepository(db)
+
+	// Return cleanup function
+	cleanup := func() {
+		client.Disconnect(context.Background())
+		mongoServer.Stop()
+	}
+
+	return repo, cleanup
+}
+
+func TestSenderListRepository(t *testing.T) {
+	repo, cleanup := setupSenderListTest(t)
+	defer cleanup()
+
+	userID := primitive.NewObjectID()
+	otherUserID := primitive.NewObjectID()
+
+	blocked, err := repo.Upsert(context.Background(), &models.SenderListEntry{UserID: userID, Sender: "example.com", List: models.SenderListBlocked})
+	require.NoError(t, err)
+	require.NotNil(t, blocked.ID)
+	_, err = repo.Upsert(context.Background(), &models.SenderListEntry{UserID: userID, Sender: "friend@example.com", List: models.SenderListAllowed})
+	require.NoError(t, err)
+	_, err = repo.Upsert(context.Background(), &models.SenderListEntry{UserID: otherUserID, Sender: "example.com", List: models.SenderListAllowed})
+	require.NoError(t, err)
+
+	t.Run("lists the entries of a user", func(t *testing.T) {
+		entries, err := repo.GetAll(context.Background(), userID)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "example.com", entries[0].Sender)
+		assert.Equal(t, "friend@example.com", entries[1].Sender)
+	})
+
+	t.Run("finds the entries of senders", func(t *testing.T) {
+		entries, err := repo.GetBySenders(context.Background(), userID, []string{"stranger@example.com", "example.com"})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, models.SenderListBlocked, entries[0].List)
+	})
+
+	t.Run("moves a sender to the other list", func(t *testing.T) {
+		moved, err := repo.Upsert(context.Background(), &models.SenderListEntry{UserID: userID, Sender: "example.com", List: models.SenderListAllowed})
+		require.NoError(t, err)
+		assert.Equal(t, *blocked.ID, *moved.ID)
+		assert.Equal(t, models.SenderListAllowed, moved.List)
+	})
+
+	t.Run("deletes entries", func(t *testing.T) {
+		require.NoError(t, repo.Delete(context.Background(), *blocked.ID))
+		entry, err := repo.GetByID(context.Background(), *blocked.ID)
+		require.NoError(t, err)
+		assert.Nil(t, entry)
+
+		require.NoError(t, repo.DeleteByUserID(context.Background(), userID))
+		entries, err := repo.GetAll(context.Background(), userID)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+
+		entries, err = repo.GetAll(context.Background(), otherUserID)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/shared/utils/db"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -33,9 +34,12 @@ type TagRepository struct {
 var _ TagRepositoryInterface = (*TagRepository)(nil)
 
 // NewTagRepository creates a new tag repository instance
-func NewTagRepository(db *mongo.Database) *TagRepository {
+func NewTagRepository(database *mongo.Database) *TagRepository {
+	if database == nil {
+		database = db.Database
+	}
 	return &TagRepository{
-		collection: db.Collection(tagCollection),
+		collection: database.Collection(tagCollection),
 	}
 }
 
@@ -125,10 +129,17 @@ func (r *TagRepository) Update(ctx context.Context, tag *models.Tag) (*models.Ta
 	return r.GetByID(ctx, *tag.ID)
 }
 
-// Delete removes a tag from the database
+// Delete removes a tag from the database and from the mails labeled with it
 func (r *TagRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	now := primitive.NewDateTimeFromTime(time.Now())
+	update := bson.M{"$pull": bson.M{"tag_ids": id}, "$set": bson.M{"updated_at": now}}
+	_, err := r.collection.Database().Collection(mailCollection).UpdateMany(ctx, bson.M{"tag_ids": id}, update)
+	if err != nil {
+		return err
+	}
+
 	filter := bson.M{"_id": id}
-	_, err := r.collection.DeleteOne(ctx, filter)
+	_, err = r.collection.DeleteOne(ctx, filter)
 	return err
 }
 
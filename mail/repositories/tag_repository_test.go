@@ -195,6 +195,27 @@ func TestTagRepository_Delete(t *testing.T) {
 		require.NoError(t, err)
 		assert.Nil(t, found)
 	})
+
+	t.Run("tag is removed from the mails", func(t *testing.T) {
+		tag := createTestTag()
+		created, err := repo.Create(context.Background(), tag)
+		require.NoError(t, err)
+
+		otherTagID := primitive.NewObjectID()
+		mailRepo := NewMailRepository(repo.(*TagRepository).collection.Database())
+		mail := &models.Mail{UserID: primitive.NewObjectID(), TagIDs: []primitive.ObjectID{*created.ID, otherTagID}}
+		_, err = mailRepo.Create(context.Background(), mail)
+		require.NoError(t, err)
+
+		deletedAt := time.Now().Truncate(time.Millisecond)
+		err = repo.Delete(context.Background(), *created.ID)
+		require.NoError(t, err)
+
+		labeledMail, err := mailRepo.GetByID(context.Background(), *mail.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{otherTagID}, labeledMail.TagIDs)
+		assert.False(t, labeledMail.UpdatedAt.Time().Before(deletedAt))
+	})
 }
 
 func TestTagRepository_DeleteByUserID(t *testing.T) {
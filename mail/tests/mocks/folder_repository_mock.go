@@ -23,6 +23,15 @@ func (m *MockFolderRepository) GetAll(ctx context.Context, userID primitive.Obje
 	return args.Get(0).([]*models.Folder), args.Error(1)
 }
 
+// GetByID retrieves a folder by its ID
+func (m *MockFolderRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Folder, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Folder), args.Error(1)
+}
+
 // Create creates a new folder
 func (m *MockFolderRepository) Create(ctx context.Context, folder *models.Folder) (*models.Folder, error) {
 	args := m.Called(ctx, folder)
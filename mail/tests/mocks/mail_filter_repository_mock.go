@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/atomic-blend/backend/mail/models"
+
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockMailFilterRepository provides a mock implementation of MailFilterRepositoryInterface
+type MockMailFilterRepository struct {
+	mock.Mock
+}
+
+// GetByUserID retrieves the mail filter of a user
+func (m *MockMailFilterRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID) (*models.MailFilter, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MailFilter), args.Error(1)
+}
+
+// Upsert creates or replaces the mail filter of a user
+func (m *MockMailFilterRepository) Upsert(ctx context.Context, filter *models.MailFilter) (*models.MailFilter, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MailFilter), args.Error(1)
+}
+
+// DeleteByUserID deletes the mail filter of a user
+func (m *MockMailFilterRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
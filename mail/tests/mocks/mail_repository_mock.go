@@ -67,3 +67,45 @@ func (m *MockMailRepository) GetSince(ctx context.Context, userID primitive.Obje
 	}
 	return args.Get(0).([]*models.Mail), args.Get(1).(int64), args.Error(2)
 }
+
+// GetThreads retrieves the conversations of a user
+func (m *MockMailRepository) GetThreads(ctx context.Context, userID primitive.ObjectID, page, limit int64) ([]*models.MailThread, int64, error) {
+	args := m.Called(ctx, userID, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*models.MailThread), args.Get(1).(int64), args.Error(2)
+}
+
+// FindThreadID returns the thread of the first mail of the user with one of the given message keys
+func (m *MockMailRepository) FindThreadID(ctx context.Context, userID primitive.ObjectID, messageKeys []string) (string, error) {
+	args := m.Called(ctx, userID, messageKeys)
+	return args.String(0), args.Error(1)
+}
+
+// GetAllInFolder retrieves the mails of a user in a folder
+func (m *MockMailRepository) GetAllInFolder(ctx context.Context, userID, folderID primitive.ObjectID, page, limit int64) ([]*models.Mail, int64, error) {
+	args := m.Called(ctx, userID, folderID, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*models.Mail), args.Get(1).(int64), args.Error(2)
+}
+
+// GetAllWithTag retrieves the mails of a user with a label
+func (m *MockMailRepository) GetAllWithTag(ctx context.Context, userID, tagID primitive.ObjectID, page, limit int64) ([]*models.Mail, int64, error) {
+	args := m.Called(ctx, userID, tagID, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*models.Mail), args.Get(1).(int64), args.Error(2)
+}
+
+// GetAllSpam retrieves the spam mails of a user
+func (m *MockMailRepository) GetAllSpam(ctx context.Context, userID primitive.ObjectID, page, limit int64) ([]*models.Mail, int64, error) {
+	args := m.Called(ctx, userID, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*models.Mail), args.Get(1).(int64), args.Error(2)
+}
@@ -55,3 +55,9 @@ func (m *MockSendMailRepository) Delete(ctx context.Context, id primitive.Object
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
+
+// FindThreadID returns the thread of the first sent mail of the user with one of the given message keys
+func (m *MockSendMailRepository) FindThreadID(ctx context.Context, userID primitive.ObjectID, messageKeys []string) (string, error) {
+	args := m.Called(ctx, userID, messageKeys)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/atomic-blend/backend/mail/models"
+
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockSenderListRepository provides a mock implementation of SenderListRepositoryInterface
+type MockSenderListRepository struct {
+	mock.Mock
+}
+
+// GetAll retrieves the entries of the allow and block lists of a user
+func (m *MockSenderListRepository) GetAll(ctx context.Context, userID primitive.ObjectID) ([]*models.SenderListEntry, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SenderListEntry), args.Error(1)
+}
+
+// GetByID retrieves an entry by its ID
+func (m *MockSenderListRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.SenderListEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SenderListEntry), args.Error(1)
+}
+
+// GetBySenders retrieves the entries of a user for any of the given senders
+func (m *MockSenderListRepository) GetBySenders(ctx context.Context, userID primitive.ObjectID, senders []string) ([]*models.SenderListEntry, error) {
+	args := m.Called(ctx, userID, senders)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SenderListEntry), args.Error(1)
+}
+
+// Upsert adds a sender to a list of a user
+func (m *MockSenderListRepository) Upsert(ctx context.Context, entry *models.SenderListEntry) (*models.SenderListEntry, error) {
+	args := m.Called(ctx, entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SenderListEntry), args.Error(1)
+}
+
+// Delete removes an entry
+func (m *MockSenderListRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// DeleteByUserID removes the entries of a user
+func (m *MockSenderListRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
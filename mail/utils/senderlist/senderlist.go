@@ -0,0 +1,132 @@
+// Package senderlist matches the senders of the received mails against the allow and block lists of the users
+package senderlist
+
+import (
+	"errors"
+	"math"
+	"net/mail"
+	"strings"
+
+	"github.com/atomic-blend/backend/mail/models"
+)
+
+// ErrInvalidSender is returned for a sender which is neither an address nor a domain
+var ErrInvalidSender = errors.New("sender must be an address or a domain")
+
+// Normalize returns the form a sender is stored under: a lowercase address, or a lowercase domain without a leading "@"
+func Normalize(sender string) (string, error) {
+	sender = strings.ToLower(strings.TrimSpace(sender))
+	sender = strings.TrimPrefix(sender, "@")
+	if sender == "" || strings.ContainsAny(sender, " \t\r\n<>,;") {
+		return "", ErrInvalidSender
+	}
+
+	local, domain, isAddress := strings.Cut(sender, "@")
+	if isAddress {
+		if local == "" {
+			return "", ErrInvalidSender
+		}
+		if _, err := mail.ParseAddress(sender); err != nil {
+			return "", ErrInvalidSender
+		}
+	} else {
+		domain = sender
+	}
+
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") || strings.Contains(domain, "..") {
+		return "", ErrInvalidSender
+	}
+	return sender, nil
+}
+
+// Candidates returns the list entries which can match a sender address, from the most specific to the least specific:
+// the address itself, then its domain and the parent domains of its domain
+func Candidates(address string) []string {
+	address = strings.ToLower(strings.Trim(strings.TrimSpace(address), "<>"))
+	at := strings.LastIndex(address, "@")
+	if at <= 0 || at == len(address)-1 {
+		return []string{}
+	}
+
+	candidates := []string{address}
+	domain := address[at+1:]
+	for strings.Contains(domain, ".") {
+		candidates = append(candidates, domain)
+		domain = domain[strings.Index(domain, ".")+1:]
+	}
+	return candidates
+}
+
+// Senders returns the addresses a received mail is matched with: its envelope sender and the addresses of its From header
+func Senders(envelopeFrom string, headers map[string]interface{}) []string {
+	senders := []string{}
+	if envelopeFrom != "" {
+		senders = append(senders, envelopeFrom)
+	}
+	for _, address := range FromAddresses(headers) {
+		if !strings.EqualFold(address, envelopeFrom) {
+			senders = append(senders, address)
+		}
+	}
+	return senders
+}
+
+// FromAddresses returns the addresses of the From header of a mail, which may hold one value or a list of values
+func FromAddresses(headers map[string]interface{}) []string {
+	addresses := []string{}
+	for key, value := range headers {
+		if !strings.EqualFold(key, "From") {
+			continue
+		}
+
+		var values []string
+		switch v := value.(type) {
+		case string:
+			values = []string{v}
+		case []string:
+			values = v
+		case []interface{}:
+			for _, item := range v {
+				if str, ok := item.(string); ok {
+					values = append(values, str)
+				}
+			}
+		}
+
+		for _, from := range values {
+			list, err := mail.ParseAddressList(from)
+			if err != nil {
+				continue
+			}
+			for _, address := range list {
+				addresses = append(addresses, address.Address)
+			}
+		}
+	}
+	return addresses
+}
+
+// Verdict returns the list the senders of a mail belong to, empty when none of the entries match.
+// The most specific entry wins, a blocked sender winning over an allowed one when both are as specific.
+func Verdict(entries []*models.SenderListEntry, senders []string) string {
+	best, bestRank := "", -1
+	for _, sender := range senders {
+		candidates := Candidates(sender)
+		for _, entry := range entries {
+			for i, candidate := range candidates {
+				if entry.Sender != candidate {
+					continue
+				}
+				// an address is more specific than any domain, a domain more specific than its parents
+				rank := strings.Count(candidate, ".") + 1
+				if i == 0 {
+					rank = math.MaxInt
+				}
+				if rank > bestRank || (rank == bestRank && entry.List == models.SenderListBlocked) {
+					best, bestRank = entry.List, rank
+				}
+			}
+		}
+	}
+	return best
+}
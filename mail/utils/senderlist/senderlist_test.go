@@ -0,0 +1,63 @@
+package senderlist
+
+import (
+	"testing"
+
+	"github.com/atomic-blend/backend/mail/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	valid := map[string]string{
+		" Boss@Example.COM ": "boss@example.com",
+		"@Example.com":       "example.com",
+		"news.example.org":   "news.example.org",
+	}
+	for sender, expected := range valid {
+		normalized, err := Normalize(sender)
+		require.NoError(t, err, sender)
+		assert.Equal(t, expected, normalized)
+	}
+
+	for _, sender := range []string{"", "@", "localhost", "@example.com@", "a b@example.com", "<a@example.com>", "example..com", ".example.com", "a@"} {
+		_, err := Normalize(sender)
+		assert.ErrorIs(t, err, ErrInvalidSender, sender)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	assert.Equal(t, []string{"a@mail.example.com", "mail.example.com", "example.com"}, Candidates("<A@Mail.Example.com>"))
+	assert.Empty(t, Candidates(""))
+	assert.Empty(t, Candidates("nobody"))
+}
+
+func TestSenders(t *testing.T) {
+	headers := map[string]interface{}{"From": "Boss <boss@example.com>", "To": "me@atomic-blend.com"}
+	assert.Equal(t, []string{"bounce@lists.example.com", "boss@example.com"}, Senders("bounce@lists.example.com", headers))
+	assert.Equal(t, []string{"boss@example.com"}, Senders("boss@example.com", headers))
+	assert.Equal(t, []string{"boss@example.com"}, Senders("", headers))
+	assert.Empty(t, Senders("", map[string]interface{}{"From": "not an address"}))
+	assert.Equal(t, []string{"boss@example.com"}, Senders("", map[string]interface{}{"From": []string{"Boss <boss@example.com>"}}))
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, Senders("", map[string]interface{}{"from": []interface{}{"a@example.com, b@example.com"}}))
+}
+
+func TestVerdict(t *testing.T) {
+	entries := []*models.SenderListEntry{
+		{Sender: "example.com", List: models.SenderListBlocked},
+		{Sender: "friend@example.com", List: models.SenderListAllowed},
+		{Sender: "news.example.com", List: models.SenderListAllowed},
+	}
+
+	assert.Equal(t, models.SenderListBlocked, Verdict(entries, []string{"stranger@example.com"}))
+	assert.Equal(t, models.SenderListAllowed, Verdict(entries, []string{"friend@example.com"}))
+	assert.Equal(t, models.SenderListAllowed, Verdict(entries, []string{"daily@news.example.com"}))
+	// the address of one sender is more specific than the domain of the other
+	assert.Equal(t, models.SenderListAllowed, Verdict(entries, []string{"bounce@deep.news.example.com", "friend@example.com"}))
+	assert.Equal(t, "", Verdict(entries, []string{"someone@other.org"}))
+
+	// a blocked sender wins over an allowed one as specific
+	entries = append(entries, &models.SenderListEntry{Sender: "other.org", List: models.SenderListBlocked}, &models.SenderListEntry{Sender: "another.org", List: models.SenderListAllowed})
+	assert.Equal(t, models.SenderListBlocked, Verdict(entries, []string{"a@another.org", "b@other.org"}))
+}
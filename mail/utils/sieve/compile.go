@@ -0,0 +1,390 @@
+package sieve
+
+import (
+	"strings"
+)
+
+// node is a compiled command, exec returns false when the script stops
+type node interface {
+	exec(s *state) bool
+}
+
+// condition is a compiled test
+type condition interface {
+	eval(s *state) bool
+}
+
+type compiler struct {
+	capabilities map[string]bool
+}
+
+// block compiles a list of commands, requires being only allowed at the start of the script
+func (c *compiler) block(commands []*command, top bool) ([]node, error) {
+	nodes := []node{}
+	requiresAllowed := top
+	var current *ifNode
+
+	for _, cmd := range commands {
+		if cmd.name != "require" {
+			requiresAllowed = false
+		}
+		if cmd.name != "elsif" && cmd.name != "else" {
+			current = nil
+		}
+
+		switch cmd.name {
+		case "require":
+			if !requiresAllowed {
+				return nil, errorf(cmd.line, "require is only allowed at the start of the script")
+			}
+			if err := c.require(cmd); err != nil {
+				return nil, err
+			}
+
+		case "if", "elsif", "else":
+			if cmd.name != "if" && current == nil {
+				return nil, errorf(cmd.line, "%s without a preceding if", cmd.name)
+			}
+			if !cmd.hasBlock {
+				return nil, errorf(cmd.line, "%s requires a block", cmd.name)
+			}
+			block, err := c.block(cmd.block, false)
+			if err != nil {
+				return nil, err
+			}
+
+			if cmd.name == "else" {
+				if len(cmd.args) > 0 || len(cmd.tests) > 0 {
+					return nil, errorf(cmd.line, "else takes no test")
+				}
+				current.elseBlock = block
+				current = nil
+				continue
+			}
+
+			if len(cmd.args) > 0 || len(cmd.tests) != 1 {
+				return nil, errorf(cmd.line, "%s requires a single test", cmd.name)
+			}
+			cond, err := c.test(cmd.tests[0])
+			if err != nil {
+				return nil, err
+			}
+			if cmd.name == "if" {
+				current = &ifNode{}
+				nodes = append(nodes, current)
+			}
+			current.branches = append(current.branches, branch{cond: cond, block: block})
+
+		default:
+			if cmd.hasBlock {
+				return nil, errorf(cmd.line, "%s does not take a block", cmd.name)
+			}
+			if len(cmd.tests) > 0 {
+				return nil, errorf(cmd.line, "%s does not take a test", cmd.name)
+			}
+			n, err := c.action(cmd)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes, nil
+}
+
+func (c *compiler) require(cmd *command) error {
+	if len(cmd.args) != 1 || cmd.args[0].tag != "" || cmd.args[0].isNumber || len(cmd.tests) > 0 || cmd.hasBlock {
+		return errorf(cmd.line, "require takes a string list")
+	}
+	for _, capability := range cmd.args[0].strings {
+		supported := false
+		for _, known := range Capabilities {
+			if capability == known {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return errorf(cmd.line, "unsupported extension %q", capability)
+		}
+		c.capabilities[capability] = true
+		// spamtestplus implies spamtest (RFC 5235 section 3.1)
+		if capability == "spamtestplus" {
+			c.capabilities["spamtest"] = true
+		}
+	}
+	return nil
+}
+
+func (c *compiler) requireCapability(line int, capability, usage string) error {
+	if !c.capabilities[capability] {
+		return errorf(line, "%s requires the %q extension", usage, capability)
+	}
+	return nil
+}
+
+func (c *compiler) action(cmd *command) (node, error) {
+	args, err := c.arguments(cmd.line, cmd.name, cmd.args, map[string]bool{"copy": cmd.name == "fileinto" || cmd.name == "redirect"})
+	if err != nil {
+		return nil, err
+	}
+
+	switch cmd.name {
+	case "stop", "keep", "discard":
+		if len(args.positional) > 0 {
+			return nil, errorf(cmd.line, "%s takes no arguments", cmd.name)
+		}
+		switch cmd.name {
+		case "stop":
+			return stopNode{}, nil
+		case "keep":
+			return keepNode{}, nil
+		default:
+			return discardNode{}, nil
+		}
+
+	case "fileinto":
+		if err := c.requireCapability(cmd.line, "fileinto", "fileinto"); err != nil {
+			return nil, err
+		}
+		mailbox, err := single(cmd.line, cmd.name, args.positional)
+		if err != nil {
+			return nil, err
+		}
+		return fileIntoNode{mailbox: mailbox, copy: args.copy}, nil
+
+	case "redirect":
+		address, err := single(cmd.line, cmd.name, args.positional)
+		if err != nil {
+			return nil, err
+		}
+		if at := strings.LastIndex(address, "@"); at <= 0 || at == len(address)-1 || strings.ContainsAny(address, " \t\r\n<>,") {
+			return nil, errorf(cmd.line, "invalid redirect address %q", address)
+		}
+		return redirectNode{address: address, copy: args.copy}, nil
+
+	case "addflag":
+		if err := c.requireCapability(cmd.line, "imap4flags", "addflag"); err != nil {
+			return nil, err
+		}
+		if len(args.positional) != 1 {
+			return nil, errorf(cmd.line, "addflag takes a list of flags")
+		}
+		return addFlagNode{flags: args.positional[0]}, nil
+	}
+
+	return nil, errorf(cmd.line, "unsupported command %q", cmd.name)
+}
+
+func (c *compiler) test(t *test) (condition, error) {
+	switch t.name {
+	case "true", "false":
+		if len(t.args) > 0 || len(t.tests) > 0 {
+			return nil, errorf(t.line, "%s takes no arguments", t.name)
+		}
+		return constCondition(t.name == "true"), nil
+
+	case "not":
+		if len(t.args) > 0 || len(t.tests) != 1 {
+			return nil, errorf(t.line, "not takes a single test")
+		}
+		cond, err := c.test(t.tests[0])
+		if err != nil {
+			return nil, err
+		}
+		return notCondition{cond: cond}, nil
+
+	case "allof", "anyof":
+		if len(t.args) > 0 || len(t.tests) == 0 {
+			return nil, errorf(t.line, "%s takes a list of tests", t.name)
+		}
+		conds := []condition{}
+		for _, sub := range t.tests {
+			cond, err := c.test(sub)
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, cond)
+		}
+		return listCondition{all: t.name == "allof", conds: conds}, nil
+
+	case "envelope":
+		if err := c.requireCapability(t.line, "envelope", "envelope"); err != nil {
+			return nil, err
+		}
+		if len(t.tests) > 0 {
+			return nil, errorf(t.line, "envelope does not take a test")
+		}
+		args, err := c.arguments(t.line, t.name, t.args, map[string]bool{"comparator": true, "match": true, "address": true})
+		if err != nil {
+			return nil, err
+		}
+		if len(args.positional) != 2 {
+			return nil, errorf(t.line, "envelope takes a list of envelope parts and a list of keys")
+		}
+		for _, part := range args.positional[0] {
+			if part = strings.ToLower(part); part != "from" && part != "to" {
+				return nil, errorf(t.line, "unsupported envelope part %q", part)
+			}
+		}
+		return envelopeCondition{parts: args.positional[0], addressPart: args.addressPart, matcher: args.matcher, keys: args.positional[1]}, nil
+
+	case "spamtest":
+		if err := c.requireCapability(t.line, "spamtest", "spamtest"); err != nil {
+			return nil, err
+		}
+		if len(t.tests) > 0 {
+			return nil, errorf(t.line, "spamtest does not take a test")
+		}
+		args, err := c.arguments(t.line, t.name, t.args, map[string]bool{"comparator": true, "match": true, "percent": true})
+		if err != nil {
+			return nil, err
+		}
+		value, err := single(t.line, t.name, args.positional)
+		if err != nil {
+			return nil, err
+		}
+		return spamtestCondition{percent: args.percent, matcher: args.matcher, key: value}, nil
+	}
+
+	return nil, errorf(t.line, "unsupported test %q", t.name)
+}
+
+// arguments holds the tagged and positional arguments of a command or a test
+type arguments struct {
+	matcher     matcher
+	addressPart string
+	percent     bool
+	copy        bool
+	positional  [][]string
+}
+
+// arguments reads the tagged arguments allowed by a command or a test, followed by its positional arguments
+func (c *compiler) arguments(line int, name string, args []argument, allowed map[string]bool) (*arguments, error) {
+	result := &arguments{matcher: matcher{matchType: "is", comparator: "i;ascii-casemap"}, addressPart: "all"}
+	matchTypeSet, comparatorSet, addressPartSet := false, false, false
+
+	// stringArgument returns the string following a tag
+	stringArgument := func(i int, tag string) (string, error) {
+		if i+1 >= len(args) || args[i+1].tag != "" || args[i+1].isNumber || len(args[i+1].strings) != 1 {
+			return "", errorf(line, ":%s requires a string", tag)
+		}
+		return args[i+1].strings[0], nil
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg.isNumber {
+			return nil, errorf(arg.line, "%s does not take a number", name)
+		}
+		if arg.tag == "" {
+			result.positional = append(result.positional, arg.strings)
+			continue
+		}
+		if len(result.positional) > 0 {
+			return nil, errorf(arg.line, "tagged argument \":%s\" after positional arguments", arg.tag)
+		}
+
+		switch arg.tag {
+		case "is", "contains", "matches", "value", "count":
+			if !allowed["match"] {
+				break
+			}
+			if matchTypeSet {
+				return nil, errorf(arg.line, "multiple match types")
+			}
+			matchTypeSet = true
+			result.matcher.matchType = arg.tag
+			if arg.tag == "value" || arg.tag == "count" {
+				if arg.tag == "count" {
+					return nil, errorf(arg.line, "the :count match type is not supported")
+				}
+				if err := c.requireCapability(arg.line, "relational", ":value"); err != nil {
+					return nil, err
+				}
+				relation, err := stringArgument(i, arg.tag)
+				if err != nil {
+					return nil, err
+				}
+				if relation = strings.ToLower(relation); !validRelation(relation) {
+					return nil, errorf(arg.line, "invalid relational match %q", relation)
+				}
+				result.matcher.relation = relation
+				i++
+			}
+			continue
+
+		case "comparator":
+			if !allowed["comparator"] {
+				break
+			}
+			if comparatorSet {
+				return nil, errorf(arg.line, "multiple comparators")
+			}
+			comparatorSet = true
+			comparator, err := stringArgument(i, arg.tag)
+			if err != nil {
+				return nil, err
+			}
+			comparator = strings.ToLower(comparator)
+			switch comparator {
+			case "i;octet", "i;ascii-casemap":
+			case "i;ascii-numeric":
+				if err := c.requireCapability(arg.line, "comparator-i;ascii-numeric", "the i;ascii-numeric comparator"); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, errorf(arg.line, "unsupported comparator %q", comparator)
+			}
+			result.matcher.comparator = comparator
+			i++
+			continue
+
+		case "all", "localpart", "domain":
+			if !allowed["address"] {
+				break
+			}
+			if addressPartSet {
+				return nil, errorf(arg.line, "multiple address parts")
+			}
+			addressPartSet = true
+			result.addressPart = arg.tag
+			continue
+
+		case "percent":
+			if !allowed["percent"] {
+				break
+			}
+			if err := c.requireCapability(arg.line, "spamtestplus", ":percent"); err != nil {
+				return nil, err
+			}
+			result.percent = true
+			continue
+
+		case "copy":
+			if !allowed["copy"] {
+				break
+			}
+			if err := c.requireCapability(arg.line, "copy", ":copy"); err != nil {
+				return nil, err
+			}
+			result.copy = true
+			continue
+		}
+
+		return nil, errorf(arg.line, "%s does not take the \":%s\" argument", name, arg.tag)
+	}
+
+	if result.matcher.comparator == "i;ascii-numeric" && (result.matcher.matchType == "contains" || result.matcher.matchType == "matches") {
+		return nil, errorf(line, "the i;ascii-numeric comparator does not support :%s", result.matcher.matchType)
+	}
+	return result, nil
+}
+
+// single returns the single string positional argument of a command or a test
+func single(line int, name string, positional [][]string) (string, error) {
+	if len(positional) != 1 || len(positional[0]) != 1 {
+		return "", errorf(line, "%s takes a single string", name)
+	}
+	return positional[0][0], nil
+}
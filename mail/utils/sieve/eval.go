@@ -0,0 +1,329 @@
+package sieve
+
+import (
+	"math"
+	"strings"
+)
+
+// run executes a list of commands, returning false when the script stops
+func run(nodes []node, s *state) bool {
+	for _, n := range nodes {
+		if !n.exec(s) {
+			return false
+		}
+	}
+	return true
+}
+
+type branch struct {
+	cond  condition
+	block []node
+}
+
+type ifNode struct {
+	branches  []branch
+	elseBlock []node
+}
+
+func (n *ifNode) exec(s *state) bool {
+	for _, b := range n.branches {
+		if b.cond.eval(s) {
+			return run(b.block, s)
+		}
+	}
+	return run(n.elseBlock, s)
+}
+
+type stopNode struct{}
+
+func (stopNode) exec(*state) bool {
+	return false
+}
+
+type keepNode struct{}
+
+func (keepNode) exec(s *state) bool {
+	s.keep = true
+	return true
+}
+
+type discardNode struct{}
+
+func (discardNode) exec(s *state) bool {
+	s.implicitKeep = false
+	return true
+}
+
+type fileIntoNode struct {
+	mailbox string
+	copy    bool
+}
+
+func (n fileIntoNode) exec(s *state) bool {
+	if !n.copy {
+		s.implicitKeep = false
+	}
+	for _, mailbox := range s.fileInto {
+		if mailbox == n.mailbox {
+			return true
+		}
+	}
+	s.fileInto = append(s.fileInto, n.mailbox)
+	return true
+}
+
+type redirectNode struct {
+	address string
+	copy    bool
+}
+
+func (n redirectNode) exec(s *state) bool {
+	if !n.copy {
+		s.implicitKeep = false
+	}
+	for _, address := range s.redirects {
+		if strings.EqualFold(address, n.address) {
+			return true
+		}
+	}
+	s.redirects = append(s.redirects, n.address)
+	return true
+}
+
+type addFlagNode struct {
+	flags []string
+}
+
+func (n addFlagNode) exec(s *state) bool {
+	for _, list := range n.flags {
+		// a flag list is a space separated list of flags
+		for _, flag := range strings.Fields(list) {
+			key := strings.ToLower(flag)
+			if s.flags[key] {
+				continue
+			}
+			s.flags[key] = true
+			s.flagOrder = append(s.flagOrder, flag)
+		}
+	}
+	return true
+}
+
+type constCondition bool
+
+func (c constCondition) eval(*state) bool {
+	return bool(c)
+}
+
+type notCondition struct {
+	cond condition
+}
+
+func (c notCondition) eval(s *state) bool {
+	return !c.cond.eval(s)
+}
+
+type listCondition struct {
+	all   bool
+	conds []condition
+}
+
+func (c listCondition) eval(s *state) bool {
+	for _, cond := range c.conds {
+		if cond.eval(s) != c.all {
+			return !c.all
+		}
+	}
+	return c.all
+}
+
+type envelopeCondition struct {
+	parts       []string
+	addressPart string
+	matcher     matcher
+	keys        []string
+}
+
+func (c envelopeCondition) eval(s *state) bool {
+	for _, part := range c.parts {
+		address := s.envelope.From
+		if strings.EqualFold(part, "to") {
+			address = s.envelope.To
+		}
+		if c.matcher.matchesAny(addressPart(address, c.addressPart), c.keys) {
+			return true
+		}
+	}
+	return false
+}
+
+// addressPart returns the part of an address compared by an envelope test
+func addressPart(address, part string) string {
+	address = strings.Trim(address, "<>")
+	at := strings.LastIndex(address, "@")
+	switch part {
+	case "localpart":
+		if at < 0 {
+			return address
+		}
+		return address[:at]
+	case "domain":
+		if at < 0 {
+			return ""
+		}
+		return address[at+1:]
+	default:
+		return address
+	}
+}
+
+type spamtestCondition struct {
+	percent bool
+	matcher matcher
+	key     string
+}
+
+func (c spamtestCondition) eval(s *state) bool {
+	value := s.spamtest(c.percent)
+	if value == "" {
+		return false
+	}
+	return c.matcher.matchesAny(value, []string{c.key})
+}
+
+// matcher compares values with a match type and a comparator (RFC 5228 section 2.7)
+type matcher struct {
+	matchType  string
+	comparator string
+	relation   string
+}
+
+func validRelation(relation string) bool {
+	switch relation {
+	case "gt", "ge", "lt", "le", "eq", "ne":
+		return true
+	}
+	return false
+}
+
+func (m matcher) matchesAny(value string, keys []string) bool {
+	for _, key := range keys {
+		if m.matches(value, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m matcher) matches(value, key string) bool {
+	switch m.matchType {
+	case "contains":
+		return strings.Contains(m.fold(value), m.fold(key))
+	case "matches":
+		return glob(m.fold(value), m.fold(key))
+	case "value":
+		cmp := m.compare(value, key)
+		switch m.relation {
+		case "gt":
+			return cmp > 0
+		case "ge":
+			return cmp >= 0
+		case "lt":
+			return cmp < 0
+		case "le":
+			return cmp <= 0
+		case "ne":
+			return cmp != 0
+		default:
+			return cmp == 0
+		}
+	default:
+		return m.compare(value, key) == 0
+	}
+}
+
+// fold returns the value as seen by the comparator
+func (m matcher) fold(value string) string {
+	if m.comparator != "i;ascii-casemap" {
+		return value
+	}
+	folded := []byte(value)
+	for i, c := range folded {
+		if c >= 'a' && c <= 'z' {
+			folded[i] = c - 'a' + 'A'
+		}
+	}
+	return string(folded)
+}
+
+func (m matcher) compare(value, key string) int {
+	if m.comparator == "i;ascii-numeric" {
+		a, b := numericValue(value), numericValue(key)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(m.fold(value), m.fold(key))
+}
+
+// numericValue returns the value of the leading digits of a string for the i;ascii-numeric comparator,
+// a string without leading digits being greater than any number (RFC 4790 section 9.1)
+func numericValue(value string) float64 {
+	n := 0
+	for n < len(value) && isDigit(value[n]) {
+		n++
+	}
+	if n == 0 {
+		return math.Inf(1)
+	}
+	number := 0.0
+	for _, c := range value[:n] {
+		number = number*10 + float64(c-'0')
+	}
+	return number
+}
+
+// glob matches a value against a pattern where "*" matches any sequence of characters and "?" a single character,
+// backslashes escaping them
+func glob(value, pattern string) bool {
+	v, p := []rune(value), []rune(pattern)
+	// backtrack records the position after the last star to resume from when the pattern does not match
+	starP, starV := -1, 0
+	i, j := 0, 0
+	for i < len(v) {
+		if j < len(p) {
+			switch {
+			case p[j] == '*':
+				starP, starV = j+1, i
+				j++
+				continue
+			case p[j] == '?':
+				i++
+				j++
+				continue
+			case p[j] == '\\' && j+1 < len(p) && p[j+1] == v[i]:
+				i++
+				j += 2
+				continue
+			case p[j] != '\\' && p[j] == v[i]:
+				i++
+				j++
+				continue
+			}
+		}
+		if starP < 0 {
+			return false
+		}
+		starV++
+		i, j = starV, starP
+	}
+	for j < len(p) && p[j] == '*' {
+		j++
+	}
+	return j == len(p)
+}
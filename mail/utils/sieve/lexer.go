@@ -0,0 +1,178 @@
+package sieve
+
+import (
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenTag
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	number int64
+	line   int
+}
+
+// lex splits a script into tokens, following the lexical structure of RFC 5228 section 8.1
+func lex(src string) ([]token, error) {
+	tokens := []token{}
+	line := 1
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, errorf(line, "unterminated comment")
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == '"':
+			start := line
+			value, n, ok := lexQuoted(src[i:], &line)
+			if !ok {
+				return nil, errorf(start, "unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value, line: start})
+			i += n
+		case strings.HasPrefix(src[i:], "text:"):
+			start := line
+			value, n, ok := lexMultiline(src[i:], &line)
+			if !ok {
+				return nil, errorf(start, "unterminated multi-line string")
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value, line: start})
+			i += n
+		case c == ':':
+			n := identifierLength(src[i+1:])
+			if n == 0 {
+				return nil, errorf(line, "invalid tag")
+			}
+			tokens = append(tokens, token{kind: tokenTag, text: strings.ToLower(src[i+1 : i+1+n]), line: line})
+			i += n + 1
+		case isDigit(c):
+			n := 0
+			for i+n < len(src) && isDigit(src[i+n]) {
+				n++
+			}
+			number, err := strconv.ParseInt(src[i:i+n], 10, 64)
+			if err != nil {
+				return nil, errorf(line, "invalid number")
+			}
+			if i+n < len(src) {
+				switch src[i+n] {
+				case 'K', 'k':
+					number, n = number<<10, n+1
+				case 'M', 'm':
+					number, n = number<<20, n+1
+				case 'G', 'g':
+					number, n = number<<30, n+1
+				}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, number: number, line: line})
+			i += n
+		case strings.ContainsRune("[](){},;", rune(c)):
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(c), line: line})
+			i++
+		default:
+			n := identifierLength(src[i:])
+			if n == 0 {
+				return nil, errorf(line, "unexpected character %q", c)
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: strings.ToLower(src[i : i+n]), line: line})
+			i += n
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, line: line}), nil
+}
+
+// lexQuoted reads a quoted string, returning its value and the number of bytes it spans
+func lexQuoted(src string, line *int) (string, int, bool) {
+	var value strings.Builder
+	for i := 1; i < len(src); i++ {
+		switch src[i] {
+		case '"':
+			return value.String(), i + 1, true
+		case '\\':
+			i++
+			if i == len(src) {
+				return "", 0, false
+			}
+			value.WriteByte(src[i])
+		case '\n':
+			*line++
+			value.WriteByte('\n')
+		default:
+			value.WriteByte(src[i])
+		}
+	}
+	return "", 0, false
+}
+
+// lexMultiline reads a "text:" string ended by a line holding a single dot, returning its value and the number of bytes it spans
+func lexMultiline(src string, line *int) (string, int, bool) {
+	newline := strings.IndexByte(src, '\n')
+	if newline < 0 {
+		return "", 0, false
+	}
+	if rest := strings.TrimSpace(src[len("text:"):newline]); rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", 0, false
+	}
+
+	var value strings.Builder
+	i := newline + 1
+	*line++
+	for i < len(src) {
+		end := strings.IndexByte(src[i:], '\n')
+		if end < 0 {
+			end = len(src) - i
+		}
+		content := strings.TrimSuffix(src[i:i+end], "\r")
+		i += end + 1
+		*line++
+		if content == "." {
+			return value.String(), min(i, len(src)), true
+		}
+		// dot-stuffing: a leading dot of a content line is doubled
+		value.WriteString(strings.TrimPrefix(content, "."))
+		value.WriteString("\r\n")
+	}
+	return "", 0, false
+}
+
+func identifierLength(src string) int {
+	n := 0
+	for n < len(src) {
+		c := src[n]
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (n > 0 && isDigit(c)) {
+			n++
+			continue
+		}
+		break
+	}
+	return n
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,229 @@
+package sieve
+
+// command is a command of a script, as described by the grammar of RFC 5228 section 8.2
+type command struct {
+	name  string
+	args  []argument
+	tests []*test
+	block []*command
+	// hasBlock distinguishes a command ended by an empty block from a command ended by a semicolon
+	hasBlock bool
+	line     int
+}
+
+// test is a test of a command, or of another test
+type test struct {
+	name  string
+	args  []argument
+	tests []*test
+	line  int
+}
+
+// argument is a tag, a number or a string list, a single string being a list of one
+type argument struct {
+	tag      string
+	strings  []string
+	isNumber bool
+	line     int
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func parse(src string) ([]*command, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	commands, err := p.commands()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, errorf(next.line, "unexpected %s", describe(next))
+	}
+	return commands, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isSymbol(symbol string) bool {
+	t := p.peek()
+	return t.kind == tokenSymbol && t.text == symbol
+}
+
+func (p *parser) expect(symbol string) error {
+	t := p.next()
+	if t.kind != tokenSymbol || t.text != symbol {
+		return errorf(t.line, "expected %q, found %s", symbol, describe(t))
+	}
+	return nil
+}
+
+func (p *parser) commands() ([]*command, error) {
+	commands := []*command{}
+	for p.peek().kind == tokenIdentifier {
+		cmd, err := p.command()
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, cmd)
+	}
+	return commands, nil
+}
+
+func (p *parser) command() (*command, error) {
+	name := p.next()
+	cmd := &command{name: name.text, line: name.line}
+
+	args, tests, err := p.arguments()
+	if err != nil {
+		return nil, err
+	}
+	cmd.args, cmd.tests = args, tests
+
+	if p.isSymbol("{") {
+		p.next()
+		block, err := p.commands()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("}"); err != nil {
+			return nil, err
+		}
+		cmd.block, cmd.hasBlock = block, true
+		return cmd, nil
+	}
+
+	if err := p.expect(";"); err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
+
+// arguments reads the arguments of a command or a test, followed by an optional test or test list
+func (p *parser) arguments() ([]argument, []*test, error) {
+	args := []argument{}
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokenTag:
+			p.next()
+			args = append(args, argument{tag: t.text, line: t.line})
+			continue
+		case t.kind == tokenNumber:
+			p.next()
+			args = append(args, argument{isNumber: true, line: t.line})
+			continue
+		case t.kind == tokenString:
+			p.next()
+			args = append(args, argument{strings: []string{t.text}, line: t.line})
+			continue
+		case p.isSymbol("["):
+			list, err := p.stringList()
+			if err != nil {
+				return nil, nil, err
+			}
+			args = append(args, argument{strings: list, line: t.line})
+			continue
+		}
+		break
+	}
+
+	switch {
+	case p.peek().kind == tokenIdentifier:
+		tst, err := p.test()
+		if err != nil {
+			return nil, nil, err
+		}
+		return args, []*test{tst}, nil
+	case p.isSymbol("("):
+		tests, err := p.testList()
+		if err != nil {
+			return nil, nil, err
+		}
+		return args, tests, nil
+	}
+	return args, nil, nil
+}
+
+func (p *parser) stringList() ([]string, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	list := []string{}
+	for {
+		t := p.next()
+		if t.kind != tokenString {
+			return nil, errorf(t.line, "expected a string, found %s", describe(t))
+		}
+		list = append(list, t.text)
+		if p.isSymbol(",") {
+			p.next()
+			continue
+		}
+		return list, p.expect("]")
+	}
+}
+
+func (p *parser) test() (*test, error) {
+	name := p.next()
+	if name.kind != tokenIdentifier {
+		return nil, errorf(name.line, "expected a test, found %s", describe(name))
+	}
+	args, tests, err := p.arguments()
+	if err != nil {
+		return nil, err
+	}
+	return &test{name: name.text, args: args, tests: tests, line: name.line}, nil
+}
+
+func (p *parser) testList() ([]*test, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	tests := []*test{}
+	for {
+		tst, err := p.test()
+		if err != nil {
+			return nil, err
+		}
+		tests = append(tests, tst)
+		if p.isSymbol(",") {
+			p.next()
+			continue
+		}
+		return tests, p.expect(")")
+	}
+}
+
+func describe(t token) string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of script"
+	case tokenIdentifier:
+		return "\"" + t.text + "\""
+	case tokenTag:
+		return "\":" + t.text + "\""
+	case tokenString:
+		return "a string"
+	case tokenNumber:
+		return "a number"
+	default:
+		return "\"" + t.text + "\""
+	}
+}
@@ -0,0 +1,136 @@
+// Package sieve implements the subset of the Sieve mail filtering language (RFC 5228) used to filter the incoming mails.
+//
+// Mails are encrypted as soon as they are received, scripts are therefore evaluated against the envelope of the mail
+// and its spam verdict only. The supported extensions are:
+//   - fileinto (RFC 5228) and copy (RFC 3894)
+//   - envelope (RFC 5228), with the "from" and "to" envelope parts
+//   - imap4flags (RFC 5232), the addflag action only
+//   - spamtest and spamtestplus (RFC 5235)
+//   - relational (RFC 5231) and comparator-i;ascii-numeric (RFC 4790)
+package sieve
+
+import (
+	"fmt"
+	"math"
+)
+
+// Capabilities are the extensions a script can require
+var Capabilities = []string{
+	"fileinto",
+	"copy",
+	"envelope",
+	"imap4flags",
+	"spamtest",
+	"spamtestplus",
+	"relational",
+	"comparator-i;ascii-numeric",
+}
+
+// Error is a syntax or validation error of a script
+type Error struct {
+	Line    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+func errorf(line int, format string, args ...interface{}) *Error {
+	return &Error{Line: line, Message: fmt.Sprintf(format, args...)}
+}
+
+// Envelope is the metadata of a mail a script is evaluated against
+type Envelope struct {
+	// From is the address of the MAIL FROM command, empty for bounces
+	From string
+	// To is the address of the RCPT TO command of the recipient the script belongs to
+	To string
+	// SpamTested tells whether the mail has been checked for spam
+	SpamTested bool
+	// SpamScore is the spam score of the mail, SpamThreshold the score from which it is considered spam
+	SpamScore     float64
+	SpamThreshold float64
+}
+
+// Result holds the actions taken by a script
+type Result struct {
+	// Keep tells whether the mail is delivered to the inbox, either explicitly or because no action cancelled the implicit keep
+	Keep bool
+	// FileInto are the mailboxes the mail is filed into, in order
+	FileInto []string
+	// Redirects are the addresses the mail is redirected to, in order
+	Redirects []string
+	// Flags are the flags added to the mail, in order and without duplicates
+	Flags []string
+}
+
+// Script is a compiled script
+type Script struct {
+	commands []node
+}
+
+// Parse parses and validates a script. The returned error is an *Error.
+func Parse(src string) (*Script, error) {
+	commands, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &compiler{capabilities: map[string]bool{}}
+	nodes, err := c.block(commands, true)
+	if err != nil {
+		return nil, err
+	}
+	return &Script{commands: nodes}, nil
+}
+
+// Evaluate runs the script against the envelope of a mail
+func (s *Script) Evaluate(envelope Envelope) *Result {
+	state := &state{envelope: envelope, implicitKeep: true, flags: map[string]bool{}}
+	run(s.commands, state)
+
+	return &Result{
+		Keep:      state.implicitKeep || state.keep,
+		FileInto:  state.fileInto,
+		Redirects: state.redirects,
+		Flags:     state.flagOrder,
+	}
+}
+
+// state is the state of the evaluation of a script
+type state struct {
+	envelope     Envelope
+	implicitKeep bool
+	keep         bool
+	fileInto     []string
+	redirects    []string
+	flags        map[string]bool
+	flagOrder    []string
+}
+
+// spamtest returns the value of the spamtest test: 0 when the mail has not been tested, then from 1 for
+// a mail which is surely not spam to 10 for a mail which is surely spam. With percent, the value goes from
+// 0 to 100 and is empty when the mail has not been tested.
+func (s *state) spamtest(percent bool) string {
+	envelope := s.envelope
+	if !envelope.SpamTested || envelope.SpamThreshold <= 0 {
+		if percent {
+			return ""
+		}
+		return "0"
+	}
+
+	ratio := envelope.SpamScore / envelope.SpamThreshold
+	if percent {
+		return fmt.Sprintf("%d", int(math.Round(math.Max(0, math.Min(1, ratio))*100)))
+	}
+	switch {
+	case ratio <= 0:
+		return "1"
+	case ratio >= 1:
+		return "10"
+	default:
+		return fmt.Sprintf("%d", 1+int(math.Ceil(ratio*8)))
+	}
+}
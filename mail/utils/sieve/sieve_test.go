@@ -0,0 +1,164 @@
+package sieve
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func evaluate(t *testing.T, src string, envelope Envelope) *Result {
+	t.Helper()
+	script, err := Parse(src)
+	require.NoError(t, err)
+	return script.Evaluate(envelope)
+}
+
+func TestEvaluate_ImplicitKeep(t *testing.T) {
+	result := evaluate(t, "# nothing to do\n", Envelope{From: "a@example.com", To: "b@example.com"})
+	assert.True(t, result.Keep)
+	assert.Empty(t, result.FileInto)
+	assert.Empty(t, result.Redirects)
+	assert.Empty(t, result.Flags)
+}
+
+func TestEvaluate_Envelope(t *testing.T) {
+	src := `require ["fileinto", "copy", "envelope", "imap4flags"];
+if envelope :domain :is "from" "Example.COM" {
+	fileinto "Work";
+	addflag ["\\Seen", "Important"];
+} elsif envelope :localpart :matches "to" "news+*" {
+	fileinto :copy "Newsletters";
+} elsif envelope :contains ["from", "to"] "spam" {
+	discard;
+	stop;
+}
+keep;`
+
+	t.Run("domain", func(t *testing.T) {
+		result := evaluate(t, src, Envelope{From: "boss@example.com", To: "me@atomic-blend.com"})
+		assert.Equal(t, []string{"Work"}, result.FileInto)
+		assert.Equal(t, []string{"\\Seen", "Important"}, result.Flags)
+		// the explicit keep delivers the mail to the inbox as well
+		assert.True(t, result.Keep)
+	})
+
+	t.Run("localpart with copy", func(t *testing.T) {
+		result := evaluate(t, src, Envelope{From: "list@other.org", To: "news+go@atomic-blend.com"})
+		assert.Equal(t, []string{"Newsletters"}, result.FileInto)
+		assert.True(t, result.Keep)
+	})
+
+	t.Run("discard and stop", func(t *testing.T) {
+		result := evaluate(t, src, Envelope{From: "spammer@other.org", To: "me@atomic-blend.com"})
+		assert.Empty(t, result.FileInto)
+		assert.False(t, result.Keep)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		result := evaluate(t, src, Envelope{From: "friend@other.org", To: "me@atomic-blend.com"})
+		assert.Empty(t, result.FileInto)
+		assert.True(t, result.Keep)
+	})
+}
+
+func TestEvaluate_FileIntoCancelsImplicitKeep(t *testing.T) {
+	result := evaluate(t, `require "fileinto"; fileinto "Archive"; fileinto "Archive";`, Envelope{})
+	assert.False(t, result.Keep)
+	assert.Equal(t, []string{"Archive"}, result.FileInto)
+}
+
+func TestEvaluate_Redirect(t *testing.T) {
+	result := evaluate(t, `require "copy"; redirect :copy "a@example.com"; redirect "A@example.com"; redirect "b@example.com";`, Envelope{})
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, result.Redirects)
+	assert.False(t, result.Keep)
+}
+
+func TestEvaluate_Spamtest(t *testing.T) {
+	src := `require ["spamtest", "relational", "comparator-i;ascii-numeric", "fileinto"];
+if spamtest :value "ge" :comparator "i;ascii-numeric" "8" {
+	fileinto "Trash";
+} elsif spamtest :value "eq" :comparator "i;ascii-numeric" "0" {
+	fileinto "Untested";
+}`
+
+	assert.Equal(t, []string{"Trash"}, evaluate(t, src, Envelope{SpamTested: true, SpamScore: 20, SpamThreshold: 15}).FileInto)
+	assert.Empty(t, evaluate(t, src, Envelope{SpamTested: true, SpamScore: 2, SpamThreshold: 15}).FileInto)
+	assert.Equal(t, []string{"Untested"}, evaluate(t, src, Envelope{}).FileInto)
+}
+
+func TestEvaluate_SpamtestPercent(t *testing.T) {
+	src := `require ["spamtestplus", "relational", "comparator-i;ascii-numeric", "imap4flags"];
+if spamtest :percent :value "gt" :comparator "i;ascii-numeric" "50" {
+	addflag "Suspicious";
+}`
+
+	assert.Equal(t, []string{"Suspicious"}, evaluate(t, src, Envelope{SpamTested: true, SpamScore: 9, SpamThreshold: 15}).Flags)
+	assert.Empty(t, evaluate(t, src, Envelope{SpamTested: true, SpamScore: 3, SpamThreshold: 15}).Flags)
+	// an untested mail never matches a percent test
+	assert.Empty(t, evaluate(t, src, Envelope{}).Flags)
+}
+
+func TestEvaluate_Tests(t *testing.T) {
+	src := `require ["envelope", "imap4flags"];
+if allof (envelope :is "from" "a@example.com", not envelope :is "to" "b@example.com") {
+	addflag "allof";
+}
+if anyof (false, envelope :comparator "i;octet" :is "from" "A@example.com") {
+	addflag "anyof";
+}
+if true {
+	addflag "true";
+}`
+
+	assert.Equal(t, []string{"allof", "true"}, evaluate(t, src, Envelope{From: "A@EXAMPLE.com", To: "c@example.com"}).Flags)
+	assert.Equal(t, []string{"anyof", "true"}, evaluate(t, src, Envelope{From: "A@example.com", To: "b@example.com"}).Flags)
+}
+
+func TestParse_Strings(t *testing.T) {
+	src := "require [\"fileinto\"]; /* a comment\n spanning lines */\nfileinto text:\nQuoted \"folder\"\n..dot\n.\n;"
+	result := evaluate(t, src, Envelope{})
+	assert.Equal(t, []string{"Quoted \"folder\"\r\n.dot\r\n"}, result.FileInto)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		line int
+	}{
+		{"missing semicolon", "keep", 1},
+		{"unterminated string", "require \"fileinto;", 1},
+		{"unknown command", "keep;\nreject \"no\";", 2},
+		{"missing require", "fileinto \"Work\";", 1},
+		{"unsupported extension", "require \"vacation\";", 1},
+		{"late require", "keep;\nrequire \"fileinto\";", 2},
+		{"elsif without if", "elsif true { keep; }", 1},
+		{"numeric contains", "require [\"envelope\", \"comparator-i;ascii-numeric\"];\nif envelope :comparator \"i;ascii-numeric\" :contains \"from\" \"1\" { keep; }", 2},
+		{"invalid envelope part", "require \"envelope\";\nif envelope \"cc\" \"a\" { keep; }", 2},
+		{"invalid redirect", "redirect \"nobody\";", 1},
+		{"unknown tag", "require \"fileinto\";\nfileinto :flags \"a\" \"Work\";", 2},
+		{"unsupported header test", "if header :is \"subject\" \"a\" { keep; }", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			require.Error(t, err)
+			var sieveErr *Error
+			require.True(t, errors.As(err, &sieveErr))
+			assert.Equal(t, tt.line, sieveErr.Line)
+		})
+	}
+}
+
+func TestGlob(t *testing.T) {
+	assert.True(t, glob("newsletter@example.com", "*@example.com"))
+	assert.True(t, glob("abc", "a?c"))
+	assert.True(t, glob("a*c", "a\\*c"))
+	assert.False(t, glob("abc", "a\\*c"))
+	assert.True(t, glob("", "*"))
+	assert.False(t, glob("ab", "a"))
+	assert.True(t, glob("aXbXc", "*b*c"))
+}
@@ -0,0 +1,195 @@
+// Package threading groups mails into conversations.
+//
+// Headers are encrypted for their recipient, so the Message-ID, In-Reply-To and References of a mail cannot be
+// matched in the database. Instead, a mail stores in the clear a message key, keyed hash of its Message-ID, and a
+// thread ID. A mail joins the thread of the first message it references which is already known, or else the
+// thread keyed on the root of its references. Keys are derived from a server secret and the user, so they cannot
+// be computed from the headers of a mail, nor compared between users.
+package threading
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// keyLength is the length in bytes of the message keys and thread IDs
+const keyLength = 16
+
+// ErrMissingSecret is returned when the MAIL_THREAD_KEY environment variable is not set
+var ErrMissingSecret = errors.New("MAIL_THREAD_KEY is not set")
+
+// Keyer computes the message keys and thread IDs of the mails
+type Keyer struct {
+	secret []byte
+}
+
+// Thread is the threading information of a mail
+type Thread struct {
+	// MessageKey is the keyed Message-ID of the mail, empty when it has none
+	MessageKey string
+	// ThreadID is the ID of the thread keyed on the root of the references of the mail
+	ThreadID string
+	// ReferenceKeys are the keyed IDs of the messages the mail references, the most recent first
+	ReferenceKeys []string
+}
+
+// NewKeyer creates a keyer from the MAIL_THREAD_KEY environment variable
+func NewKeyer() (*Keyer, error) {
+	secret := os.Getenv("MAIL_THREAD_KEY")
+	if secret == "" {
+		return nil, ErrMissingSecret
+	}
+	return NewKeyerWithSecret([]byte(secret)), nil
+}
+
+// NewKeyerWithSecret creates a keyer from the given secret
+func NewKeyerWithSecret(secret []byte) *Keyer {
+	return &Keyer{secret: secret}
+}
+
+// Compute returns the threading information of a mail of a user from its headers
+func (k *Keyer) Compute(userID primitive.ObjectID, headers map[string]interface{}) *Thread {
+	messageIDs := MessageIDs(Header(headers, "Message-ID"))
+	references := MessageIDs(Header(headers, "References"))
+	for _, id := range MessageIDs(Header(headers, "In-Reply-To")) {
+		if !contains(references, id) {
+			references = append(references, id)
+		}
+	}
+
+	thread := &Thread{ReferenceKeys: make([]string, 0, len(references))}
+	if len(messageIDs) > 0 {
+		thread.MessageKey = k.key(userID, "message", messageIDs[0])
+	}
+	for i := len(references) - 1; i >= 0; i-- {
+		thread.ReferenceKeys = append(thread.ReferenceKeys, k.key(userID, "message", references[i]))
+	}
+
+	switch {
+	case len(references) > 0:
+		thread.ThreadID = k.key(userID, "thread", references[0])
+	case len(messageIDs) > 0:
+		thread.ThreadID = k.key(userID, "thread", messageIDs[0])
+	default:
+		// a mail without any ID starts its own thread
+		thread.ThreadID = k.key(userID, "thread", uuid.New().String())
+	}
+	return thread
+}
+
+// Finder looks the thread of the mails of a user up by message key
+type Finder interface {
+	FindThreadID(ctx context.Context, userID primitive.ObjectID, messageKeys []string) (string, error)
+}
+
+// Resolve returns the threading information of a mail of a user, the mail joining the thread of the most recent
+// message it references found by the finders. The thread keyed on the root of its references is kept when none is
+// found or a lookup fails, the error being returned along with it.
+func (k *Keyer) Resolve(ctx context.Context, userID primitive.ObjectID, headers map[string]interface{}, finders ...Finder) (*Thread, error) {
+	thread := k.Compute(userID, headers)
+	if len(thread.ReferenceKeys) == 0 {
+		return thread, nil
+	}
+
+	for _, finder := range finders {
+		threadID, err := finder.FindThreadID(ctx, userID, thread.ReferenceKeys)
+		if err != nil {
+			return thread, err
+		}
+		if threadID != "" {
+			thread.ThreadID = threadID
+			break
+		}
+	}
+	return thread, nil
+}
+
+func (k *Keyer) key(userID primitive.ObjectID, kind, value string) string {
+	mac := hmac.New(sha256.New, k.secret)
+	fmt.Fprintf(mac, "%s\x00%s\x00%s", kind, userID.Hex(), value)
+	return hex.EncodeToString(mac.Sum(nil)[:keyLength])
+}
+
+// Header returns the value of a header regardless of the case of its name, the values of a repeated header
+// are joined with spaces
+func Header(headers map[string]interface{}, name string) string {
+	for key, value := range headers {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			return v
+		case []string:
+			return strings.Join(v, " ")
+		case []interface{}:
+			values := make([]string, 0, len(v))
+			for _, item := range v {
+				values = append(values, fmt.Sprintf("%v", item))
+			}
+			return strings.Join(values, " ")
+		}
+	}
+	return ""
+}
+
+// MessageIDs returns the message IDs of a Message-ID, In-Reply-To or References header, without angle brackets.
+// Values which are not enclosed in angle brackets are kept as long as they look like an address.
+func MessageIDs(value string) []string {
+	ids := []string{}
+	for len(value) > 0 {
+		start := strings.IndexByte(value, '<')
+		if start < 0 {
+			for _, field := range strings.Fields(value) {
+				if strings.Contains(field, "@") && !contains(ids, field) {
+					ids = append(ids, field)
+				}
+			}
+			break
+		}
+		for _, field := range strings.Fields(value[:start]) {
+			if strings.Contains(field, "@") && !contains(ids, field) {
+				ids = append(ids, field)
+			}
+		}
+
+		end := strings.IndexByte(value[start:], '>')
+		if end < 0 {
+			break
+		}
+		if id := strings.TrimSpace(value[start+1 : start+end]); id != "" && !contains(ids, id) {
+			ids = append(ids, id)
+		}
+		value = value[start+end+1:]
+	}
+	return ids
+}
+
+// NewMessageID returns a new Message-ID for a mail sent from the given address
+func NewMessageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndexByte(from, '@'); at >= 0 {
+		if d := strings.Trim(from[at+1:], "<> \t\""); d != "" {
+			domain = d
+		}
+	}
+	return "<" + uuid.New().String() + "@" + domain + ">"
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
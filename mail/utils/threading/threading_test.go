@@ -0,0 +1,140 @@
+package threading
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type finderFunc func(ctx context.Context, userID primitive.ObjectID, messageKeys []string) (string, error)
+
+func (f finderFunc) FindThreadID(ctx context.Context, userID primitive.ObjectID, messageKeys []string) (string, error) {
+	return f(ctx, userID, messageKeys)
+}
+
+func TestMessageIDs(t *testing.T) {
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, MessageIDs("<a@example.com>\r\n <b@example.com> <a@example.com>"))
+	assert.Equal(t, []string{"c@example.com"}, MessageIDs("c@example.com"))
+	assert.Equal(t, []string{"d@example.com", "e@example.com"}, MessageIDs("d@example.com (comment) <e@example.com>"))
+	assert.Empty(t, MessageIDs(""))
+	assert.Empty(t, MessageIDs("<unterminated@example.com"))
+}
+
+func TestHeader(t *testing.T) {
+	headers := map[string]interface{}{
+		"Message-Id": "<a@example.com>",
+		"References": []string{"<b@example.com>", "<c@example.com>"},
+	}
+	assert.Equal(t, "<a@example.com>", Header(headers, "Message-ID"))
+	assert.Equal(t, "<b@example.com> <c@example.com>", Header(headers, "references"))
+	assert.Equal(t, "", Header(headers, "In-Reply-To"))
+}
+
+func TestCompute(t *testing.T) {
+	keyer := NewKeyerWithSecret([]byte("secret"))
+	userID := primitive.NewObjectID()
+
+	original := keyer.Compute(userID, map[string]interface{}{"Message-Id": "<root@example.com>"})
+	reply := keyer.Compute(userID, map[string]interface{}{
+		"Message-Id":  "<reply@example.com>",
+		"In-Reply-To": "<root@example.com>",
+		"References":  "<root@example.com>",
+	})
+	late := keyer.Compute(userID, map[string]interface{}{
+		"Message-Id":  "<late@example.com>",
+		"In-Reply-To": "<reply@example.com>",
+		"References":  "<root@example.com> <reply@example.com>",
+	})
+
+	t.Run("replies share the thread of the root message", func(t *testing.T) {
+		assert.Equal(t, original.ThreadID, reply.ThreadID)
+		assert.Equal(t, original.ThreadID, late.ThreadID)
+		assert.Len(t, original.ThreadID, 2*keyLength)
+	})
+
+	t.Run("references are keyed like message IDs, most recent first", func(t *testing.T) {
+		assert.Equal(t, []string{reply.MessageKey, original.MessageKey}, late.ReferenceKeys)
+		assert.NotEqual(t, original.MessageKey, original.ThreadID)
+	})
+
+	t.Run("keys depend on the user and the secret", func(t *testing.T) {
+		other := keyer.Compute(primitive.NewObjectID(), map[string]interface{}{"Message-Id": "<root@example.com>"})
+		assert.NotEqual(t, original.ThreadID, other.ThreadID)
+
+		otherSecret := NewKeyerWithSecret([]byte("other")).Compute(userID, map[string]interface{}{"Message-Id": "<root@example.com>"})
+		assert.NotEqual(t, original.ThreadID, otherSecret.ThreadID)
+	})
+
+	t.Run("mail without IDs starts its own thread", func(t *testing.T) {
+		first := keyer.Compute(userID, map[string]interface{}{})
+		second := keyer.Compute(userID, map[string]interface{}{})
+		assert.Empty(t, first.MessageKey)
+		assert.NotEqual(t, first.ThreadID, second.ThreadID)
+	})
+}
+
+func TestResolve(t *testing.T) {
+	keyer := NewKeyerWithSecret([]byte("secret"))
+	userID := primitive.NewObjectID()
+	headers := map[string]interface{}{
+		"Message-Id":  "<reply@example.com>",
+		"In-Reply-To": "<sent@example.com>",
+	}
+
+	t.Run("joins the thread of a known message", func(t *testing.T) {
+		var lookedUp []string
+		notFound := finderFunc(func(ctx context.Context, id primitive.ObjectID, keys []string) (string, error) {
+			return "", nil
+		})
+		found := finderFunc(func(ctx context.Context, id primitive.ObjectID, keys []string) (string, error) {
+			assert.Equal(t, userID, id)
+			lookedUp = keys
+			return "existing", nil
+		})
+
+		thread, err := keyer.Resolve(context.Background(), userID, headers, notFound, found)
+		require.NoError(t, err)
+		assert.Equal(t, "existing", thread.ThreadID)
+		assert.Equal(t, thread.ReferenceKeys, lookedUp)
+	})
+
+	t.Run("keeps the computed thread when the lookup fails", func(t *testing.T) {
+		failing := finderFunc(func(ctx context.Context, id primitive.ObjectID, keys []string) (string, error) {
+			return "", errors.New("database error")
+		})
+
+		thread, err := keyer.Resolve(context.Background(), userID, headers, failing)
+		assert.Error(t, err)
+		assert.Equal(t, keyer.Compute(userID, headers).ThreadID, thread.ThreadID)
+	})
+
+	t.Run("no lookup without references", func(t *testing.T) {
+		unexpected := finderFunc(func(ctx context.Context, id primitive.ObjectID, keys []string) (string, error) {
+			t.Fail()
+			return "", nil
+		})
+
+		_, err := keyer.Resolve(context.Background(), userID, map[string]interface{}{"Message-Id": "<new@example.com>"}, unexpected)
+		assert.NoError(t, err)
+	})
+}
+
+func TestNewMessageID(t *testing.T) {
+	assert.Regexp(t, `^<[0-9a-f-]{36}@example\.com>$`, NewMessageID("Jane <jane@example.com>"))
+	assert.Regexp(t, `^<[0-9a-f-]{36}@localhost>$`, NewMessageID(""))
+}
+
+func TestNewKeyer(t *testing.T) {
+	t.Setenv("MAIL_THREAD_KEY", "")
+	_, err := NewKeyer()
+	assert.ErrorIs(t, err, ErrMissingSecret)
+
+	t.Setenv("MAIL_THREAD_KEY", "secret")
+	keyer, err := NewKeyer()
+	require.NoError(t, err)
+	assert.NotNil(t, keyer)
+}
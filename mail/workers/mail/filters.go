@@ -0,0 +1,159 @@
+package mail
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/mail/repositories"
+	"github.com/atomic-blend/backend/mail/utils/sieve"
+	amqpinterfaces "github.com/atomic-blend/backend/shared/services/amqp/interfaces"
+
+	"github.com/rs/zerolog/log"
+	"github.com/streadway/amqp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxRedirects is the maximum number of addresses the filter of a user can redirect a mail to
+	maxRedirects = 5
+	// redirectLoopHeader marks the mails redirected by a mail filter, which are not redirected again
+	redirectLoopHeader = "X-Loop"
+	redirectLoopValue  = "atomic-blend-mail-filter"
+)
+
+// mailFilterRepositories are the repositories used to apply the mail filter of a user
+type mailFilterRepositories struct {
+	filters repositories.MailFilterRepositoryInterface
+	folders repositories.FolderRepositoryInterface
+	tags    repositories.TagRepositoryInterface
+}
+
+// applyMailFilter evaluates the mail filter of the owner of the mail against its envelope and applies the
+// actions of the filter to the mail, returning the addresses the mail has to be redirected to.
+//
+// A mail has a single location: it is filed into the first mailbox of the filter that exists, "INBOX",
+// "Archive" and "Trash" being the inbox, the archive and the trash, any other mailbox a folder of the user.
+// Mails are never dropped, a discarded or redirected mail is moved to the trash. Spam is never redirected,
+// a redirected spam is kept in the spam mailbox instead.
+func applyMailFilter(ctx context.Context, repos mailFilterRepositories, mail *models.Mail, envelope sieve.Envelope) ([]string, error) {
+	filter, err := repos.filters.GetByUserID(ctx, mail.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil || !filter.Enabled || strings.TrimSpace(filter.Script) == "" {
+		return nil, nil
+	}
+
+	// scripts are validated when saved, an error means the supported language changed since
+	script, err := sieve.Parse(filter.Script)
+	if err != nil {
+		return nil, err
+	}
+	result := script.Evaluate(envelope)
+
+	filed := false
+	var folders []*models.Folder
+	for _, mailbox := range result.FileInto {
+		switch strings.ToLower(mailbox) {
+		case "inbox":
+			filed = true
+		case "archive":
+			mail.Archived = boolPtr(true)
+			filed = true
+		case "trash":
+			moveToTrash(mail)
+			filed = true
+		default:
+			if folders == nil {
+				if folders, err = repos.folders.GetAll(ctx, mail.UserID); err != nil {
+					return nil, err
+				}
+			}
+			for _, folder := range folders {
+				if strings.EqualFold(folder.Name, mailbox) {
+					mail.FolderID = folder.ID
+					filed = true
+					break
+				}
+			}
+			if !filed {
+				log.Warn().Str("mailbox", mailbox).Msg("Mail filter files into an unknown folder, ignoring")
+			}
+		}
+		if filed {
+			break
+		}
+	}
+	// a mail filed into unknown folders only stays in the inbox, as the implicit keep of a failed fileinto
+	spam := mail.Spam != nil && *mail.Spam
+	if !filed && !result.Keep && len(result.FileInto) == 0 && !(spam && len(result.Redirects) > 0) {
+		moveToTrash(mail)
+	}
+
+	var labels []*models.Tag
+	for _, flag := range result.Flags {
+		if strings.EqualFold(flag, "\\Seen") {
+			mail.Read = boolPtr(true)
+			continue
+		}
+		if labels == nil {
+			if labels, err = repos.tags.GetAll(ctx, &mail.UserID); err != nil {
+				return nil, err
+			}
+		}
+		for _, label := range labels {
+			if label.ID != nil && strings.EqualFold(label.Name, flag) {
+				mail.TagIDs = append(mail.TagIDs, *label.ID)
+				break
+			}
+		}
+	}
+
+	redirects := result.Redirects
+	if len(redirects) > 0 && spam {
+		log.Info().Strs("redirects", redirects).Msg("Mail is spam, keeping it instead of redirecting it")
+		return nil, nil
+	}
+	if len(redirects) > maxRedirects {
+		log.Warn().Int("redirects", len(redirects)).Msg("Mail filter redirects to too many addresses, truncating")
+		redirects = redirects[:maxRedirects]
+	}
+	return redirects, nil
+}
+
+func moveToTrash(mail *models.Mail) {
+	now := primitive.NewDateTimeFromTime(time.Now())
+	mail.Trashed = boolPtr(true)
+	mail.TrashedAt = &now
+}
+
+// redirectMail publishes the mail to be sent to the addresses the mail filter of a user redirected it to.
+// The mail is resent on behalf of the user, whose address is the sender of the envelope and the Resent-From
+// header, so that it is neither relayed under the domain of the original sender nor bounced to that sender.
+// Mails rejected or flagged by the spam filter and mails already redirected are not redirected.
+func redirectMail(amqpService amqpinterfaces.AMQPServiceInterface, mailContent *models.RawMail, sender string, redirects []string) {
+	if len(redirects) == 0 || mailContent.Rejected || mailContent.RewriteSubject {
+		return
+	}
+	if loop, ok := mailContent.Headers[redirectLoopHeader].(string); ok && strings.Contains(loop, redirectLoopValue) {
+		log.Warn().Strs("redirects", redirects).Msg("Mail already redirected by a mail filter, not redirecting it again")
+		return
+	}
+
+	headers := make(map[string]interface{}, len(mailContent.Headers)+3)
+	for key, value := range mailContent.Headers {
+		headers[key] = value
+	}
+	headers[redirectLoopHeader] = redirectLoopValue
+	headers["Resent-From"] = sender
+	headers["Resent-To"] = strings.Join(redirects, ", ")
+	redirected := *mailContent
+	redirected.Headers = headers
+
+	amqpService.PublishMessage("mail", "sent", map[string]interface{}{
+		"content": redirected,
+		"sender":  sender,
+	}, &amqp.Table{"recipients": strings.Join(redirects, ",")})
+}
@@ -0,0 +1,198 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/mail/tests/mocks"
+	"github.com/atomic-blend/backend/mail/utils/sieve"
+	amqpservice "github.com/atomic-blend/backend/shared/services/amqp"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func setupMailFilter(userID primitive.ObjectID, script string) (mailFilterRepositories, *mocks.MockFolderRepository, *mocks.MockTagRepository) {
+	filterRepo := &mocks.MockMailFilterRepository{}
+	filterRepo.On("GetByUserID", mock.Anything, userID).Return(&models.MailFilter{UserID: userID, Script: script, Enabled: true}, nil)
+	folderRepo := &mocks.MockFolderRepository{}
+	tagRepo := &mocks.MockTagRepository{}
+	return mailFilterRepositories{filters: filterRepo, folders: folderRepo, tags: tagRepo}, folderRepo, tagRepo
+}
+
+func TestApplyMailFilter(t *testing.T) {
+	userID := primitive.NewObjectID()
+	envelope := sieve.Envelope{From: "boss@example.com", To: "me@atomic-blend.com", SpamTested: true, SpamScore: 1, SpamThreshold: 15}
+
+	t.Run("no filter", func(t *testing.T) {
+		filterRepo := &mocks.MockMailFilterRepository{}
+		filterRepo.On("GetByUserID", mock.Anything, userID).Return(nil, nil)
+		mail := &models.Mail{UserID: userID}
+
+		redirects, err := applyMailFilter(t.Context(), mailFilterRepositories{filters: filterRepo}, mail, envelope)
+
+		require.NoError(t, err)
+		assert.Empty(t, redirects)
+		assert.Equal(t, &models.Mail{UserID: userID}, mail)
+	})
+
+	t.Run("disabled filter", func(t *testing.T) {
+		filterRepo := &mocks.MockMailFilterRepository{}
+		filterRepo.On("GetByUserID", mock.Anything, userID).Return(&models.MailFilter{UserID: userID, Script: "discard;"}, nil)
+		mail := &models.Mail{UserID: userID}
+
+		_, err := applyMailFilter(t.Context(), mailFilterRepositories{filters: filterRepo}, mail, envelope)
+
+		require.NoError(t, err)
+		assert.Nil(t, mail.Trashed)
+	})
+
+	t.Run("files into a folder and labels the mail", func(t *testing.T) {
+		repos, folderRepo, tagRepo := setupMailFilter(userID, `require ["fileinto", "envelope", "imap4flags"];
+if envelope :domain "from" "example.com" {
+	fileinto "work";
+	addflag ["\\Seen", "Important", "Unknown"];
+}`)
+		folderID, labelID := primitive.NewObjectID(), primitive.NewObjectID()
+		folderRepo.On("GetAll", mock.Anything, userID).Return([]*models.Folder{{ID: &folderID, Name: "Work", UserID: userID}}, nil)
+		tagRepo.On("GetAll", mock.Anything, &userID).Return([]*models.Tag{{ID: &labelID, Name: "important", UserID: &userID}}, nil)
+		mail := &models.Mail{UserID: userID}
+
+		_, err := applyMailFilter(t.Context(), repos, mail, envelope)
+
+		require.NoError(t, err)
+		assert.Equal(t, &folderID, mail.FolderID)
+		assert.Equal(t, []primitive.ObjectID{labelID}, mail.TagIDs)
+		require.NotNil(t, mail.Read)
+		assert.True(t, *mail.Read)
+		assert.Nil(t, mail.Trashed)
+	})
+
+	t.Run("archives the mail", func(t *testing.T) {
+		repos, _, _ := setupMailFilter(userID, `require "fileinto"; fileinto "Archive";`)
+		mail := &models.Mail{UserID: userID}
+
+		_, err := applyMailFilter(t.Context(), repos, mail, envelope)
+
+		require.NoError(t, err)
+		require.NotNil(t, mail.Archived)
+		assert.True(t, *mail.Archived)
+		assert.Nil(t, mail.FolderID)
+	})
+
+	t.Run("keeps the mail when the folder does not exist", func(t *testing.T) {
+		repos, folderRepo, _ := setupMailFilter(userID, `require "fileinto"; fileinto "Missing";`)
+		folderRepo.On("GetAll", mock.Anything, userID).Return([]*models.Folder{}, nil)
+		mail := &models.Mail{UserID: userID}
+
+		_, err := applyMailFilter(t.Context(), repos, mail, envelope)
+
+		require.NoError(t, err)
+		assert.Nil(t, mail.FolderID)
+		assert.Nil(t, mail.Trashed)
+	})
+
+	t.Run("trashes discarded mails", func(t *testing.T) {
+		repos, _, _ := setupMailFilter(userID, `require "spamtest";
+if spamtest :is "2" { discard; }`)
+		mail := &models.Mail{UserID: userID}
+
+		_, err := applyMailFilter(t.Context(), repos, mail, envelope)
+
+		require.NoError(t, err)
+		require.NotNil(t, mail.Trashed)
+		assert.True(t, *mail.Trashed)
+		assert.NotNil(t, mail.TrashedAt)
+	})
+
+	t.Run("returns the redirects", func(t *testing.T) {
+		repos, _, _ := setupMailFilter(userID, `require "copy";
+redirect :copy "a@example.com";
+redirect :copy "b@example.com";
+redirect :copy "c@example.com";
+redirect :copy "d@example.com";
+redirect :copy "e@example.com";
+redirect :copy "f@example.com";`)
+		mail := &models.Mail{UserID: userID}
+
+		redirects, err := applyMailFilter(t.Context(), repos, mail, envelope)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com"}, redirects)
+		assert.Nil(t, mail.Trashed)
+	})
+
+	t.Run("keeps spam instead of redirecting it", func(t *testing.T) {
+		repos, _, _ := setupMailFilter(userID, `redirect "a@example.com";`)
+		mail := &models.Mail{UserID: userID, Spam: boolPtr(true)}
+
+		redirects, err := applyMailFilter(t.Context(), repos, mail, envelope)
+
+		require.NoError(t, err)
+		assert.Empty(t, redirects)
+		assert.Nil(t, mail.Trashed)
+	})
+
+	t.Run("trashes redirected mails", func(t *testing.T) {
+		repos, _, _ := setupMailFilter(userID, `redirect "a@example.com";`)
+		mail := &models.Mail{UserID: userID}
+
+		redirects, err := applyMailFilter(t.Context(), repos, mail, envelope)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a@example.com"}, redirects)
+		require.NotNil(t, mail.Trashed)
+		assert.True(t, *mail.Trashed)
+	})
+}
+
+func TestRedirectMail(t *testing.T) {
+	t.Run("publishes the mail with its recipients on behalf of the user", func(t *testing.T) {
+		amqpService := &amqpservice.MockAMQPService{}
+		amqpService.On("PublishMessage", "mail", "sent", mock.MatchedBy(func(message map[string]interface{}) bool {
+			content, ok := message["content"].(models.RawMail)
+			return ok && message["sender"] == "user@atomic-blend.com" &&
+				content.Headers[redirectLoopHeader] == redirectLoopValue && content.Headers["Subject"] == "Hello" &&
+				content.Headers["From"] == "sender@example.org" &&
+				content.Headers["Resent-From"] == "user@atomic-blend.com" &&
+				content.Headers["Resent-To"] == "a@example.com, b@example.com"
+		}), &amqp.Table{"recipients": "a@example.com,b@example.com"}).Return()
+		mailContent := &models.RawMail{Headers: map[string]interface{}{"Subject": "Hello", "From": "sender@example.org"}}
+
+		redirectMail(amqpService, mailContent, "user@atomic-blend.com", []string{"a@example.com", "b@example.com"})
+
+		amqpService.AssertExpectations(t)
+		// the received mail is left untouched
+		assert.NotContains(t, mailContent.Headers, redirectLoopHeader)
+	})
+
+	t.Run("does not redirect twice", func(t *testing.T) {
+		amqpService := &amqpservice.MockAMQPService{}
+		mailContent := &models.RawMail{Headers: map[string]interface{}{redirectLoopHeader: redirectLoopValue}}
+
+		redirectMail(amqpService, mailContent, "user@atomic-blend.com", []string{"a@example.com"})
+
+		amqpService.AssertNotCalled(t, "PublishMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("does not redirect rejected mails", func(t *testing.T) {
+		amqpService := &amqpservice.MockAMQPService{}
+		mailContent := &models.RawMail{Headers: map[string]interface{}{}, Rejected: true}
+
+		redirectMail(amqpService, mailContent, "user@atomic-blend.com", []string{"a@example.com"})
+
+		amqpService.AssertNotCalled(t, "PublishMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("does not redirect mails flagged as spam", func(t *testing.T) {
+		amqpService := &amqpservice.MockAMQPService{}
+		mailContent := &models.RawMail{Headers: map[string]interface{}{}, RewriteSubject: true}
+
+		redirectMail(amqpService, mailContent, "user@atomic-blend.com", []string{"a@example.com"})
+
+		amqpService.AssertNotCalled(t, "PublishMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
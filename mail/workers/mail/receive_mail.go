@@ -13,8 +13,12 @@ import (
 	"github.com/atomic-blend/backend/mail/models"
 	"github.com/atomic-blend/backend/mail/notifications/payloads"
 	"github.com/atomic-blend/backend/mail/repositories"
+	"github.com/atomic-blend/backend/mail/utils/senderlist"
+	"github.com/atomic-blend/backend/mail/utils/sieve"
+	"github.com/atomic-blend/backend/mail/utils/threading"
 	userclient "github.com/atomic-blend/backend/shared/grpc/user"
 	ageencryptionservice "github.com/atomic-blend/backend/shared/services/age_encryption"
+	amqpservice "github.com/atomic-blend/backend/shared/services/amqp"
 	rspamdservice "github.com/atomic-blend/backend/shared/services/rspamd"
 	rspamdclient "github.com/atomic-blend/backend/shared/services/rspamd/client"
 	s3service "github.com/atomic-blend/backend/shared/services/s3"
@@ -30,12 +34,18 @@ import (
 
 func receiveMail(m *amqp.Delivery, payload ReceivedMailPayload) {
 	mailRepository := repositories.NewMailRepository(db.Database)
+	sendMailRepository := repositories.NewSendMailRepository(db.Database)
 	s3Service, err := s3service.NewS3Service()
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to create S3 service")
 		return
 	}
 
+	threadKeyer, err := threading.NewKeyer()
+	if err != nil {
+		log.Warn().Err(err).Msg("Received mails are not threaded")
+	}
+
 	// Create a reader from the MIME content string
 	reader := strings.NewReader(payload.Content)
 
@@ -74,6 +84,12 @@ func receiveMail(m *amqp.Delivery, payload ReceivedMailPayload) {
 		DeliverTo: payload.DeliverTo,
 	}
 
+	// envelope the mail filters of the recipients are evaluated against
+	envelope := sieve.Envelope{From: payload.From}
+
+	// the symbols of the spam filter tell which senders of the mail are authenticated
+	var symbols map[string]interface{}
+
 	checkResponse, err := rspamdService.CheckMessage(checkRequest)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to check message with Rspamd")
@@ -86,6 +102,11 @@ func receiveMail(m *amqp.Delivery, payload ReceivedMailPayload) {
 			Bool("is_spam", checkResponse.IsSpam()).
 			Msg("Rspamd check completed")
 
+		envelope.SpamTested = true
+		envelope.SpamScore = checkResponse.Score
+		envelope.SpamThreshold = checkResponse.RequiredScore
+		symbols = checkResponse.Symbols
+
 		// Log triggered symbols if any
 		if len(checkResponse.Symbols) > 0 {
 			log.Info().Interface("symbols", checkResponse.Symbols).Msg("Rspamd triggered symbols")
@@ -145,6 +166,19 @@ func receiveMail(m *amqp.Delivery, payload ReceivedMailPayload) {
 	encryptedAttachments := make([]*awss3.PutObjectInput, 0)
 	haveErrors := false
 
+	filterRepositories := mailFilterRepositories{
+		filters: repositories.NewMailFilterRepository(db.Database),
+		folders: repositories.NewFolderRepository(db.Database),
+		tags:    repositories.NewTagRepository(db.Database),
+	}
+	// the redirects of each recipient, sent on behalf of the recipient
+	redirects := make(map[string][]string)
+
+	// the allow and block lists of the recipients are matched with the senders of the mail
+	senderListRepository := repositories.NewSenderListRepository(db.Database)
+	senders := senderlist.Senders(payload.From, mailContent.Headers)
+	authenticated := authenticatedSenders(payload.From, mailContent.Headers, symbols)
+
 	for _, rcpt := range payload.Rcpt {
 		log.Info().Str("rcpt", rcpt).Msg("Handling recepient")
 
@@ -178,6 +212,32 @@ func receiveMail(m *amqp.Delivery, payload ReceivedMailPayload) {
 		}
 		mailEntity.UserID = userID
 
+		// thread the mail with its conversation, before its headers are encrypted
+		if threadKeyer != nil {
+			thread, err := threadKeyer.Resolve(context.Background(), userID, mailContent.Headers, mailRepository, sendMailRepository)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to look up the thread of the mail")
+			}
+			mailEntity.MessageKey = thread.MessageKey
+			mailEntity.ThreadID = thread.ThreadID
+		}
+
+		spam, err := isSpam(context.Background(), senderListRepository, userID, senders, authenticated, mailContent.Rejected, mailContent.RewriteSubject)
+		if err != nil {
+			log.Error().Err(err).Str("rcpt", rcpt).Msg("Failed to match the mail with the sender lists")
+		}
+		if spam {
+			mailEntity.Spam = boolPtr(true)
+		}
+
+		// apply the mail filter of the user, before the mail is encrypted
+		envelope.To = rcpt
+		rcptRedirects, err := applyMailFilter(context.Background(), filterRepositories, mailEntity, envelope)
+		if err != nil {
+			log.Error().Err(err).Str("rcpt", rcpt).Msg("Failed to apply the mail filter")
+		}
+		redirects[rcpt] = rcptRedirects
+
 		log.Info().Str("rcpt", rcpt).Str("publicKey", rcptPublicKey.Msg.PublicKey).Msg("User public key")
 		log.Info().Interface("encryptedMails", encryptedMails).Msg("Encrypted mails")
 
@@ -224,6 +284,11 @@ func receiveMail(m *amqp.Delivery, payload ReceivedMailPayload) {
 
 		encryptedMails = append(encryptedMails, *mailEntity)
 
+		// users are not notified of their spam
+		if spam {
+			continue
+		}
+
 		// encrypt the notification content for mongodb with user's public key
 		log.Info().Str("rcpt", rcpt).Msg("Encrypting notification content")
 		ageService := ageencryptionservice.NewAgeEncryptionService()
@@ -258,6 +323,12 @@ func receiveMail(m *amqp.Delivery, payload ReceivedMailPayload) {
 		return
 	}
 
+	// redirect the mail to the addresses of the mail filters
+	redirectService := amqpservice.NewAMQPService("MAIL")
+	for rcpt, rcptRedirects := range redirects {
+		redirectMail(redirectService, mailContent, rcpt, rcptRedirects)
+	}
+
 	// send notifications to the user
 	for userID, notification := range encryptedNotifications {
 
@@ -0,0 +1,73 @@
+package mail
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/mail/repositories"
+	"github.com/atomic-blend/backend/mail/utils/senderlist"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// the rspamd symbols of a passed SPF check of the envelope sender and of a passed DMARC check of the From header
+const (
+	spfAllowSymbol   = "R_SPF_ALLOW"
+	dmarcAllowSymbol = "DMARC_POLICY_ALLOW"
+)
+
+// authenticatedSenders returns the senders of a received mail the spam filter authenticated: the envelope sender when
+// it passed SPF, the addresses of the From header when they passed DMARC. Other senders may be forged.
+func authenticatedSenders(envelopeFrom string, headers map[string]interface{}, symbols map[string]interface{}) []string {
+	senders := []string{}
+	if _, ok := symbols[spfAllowSymbol]; ok && envelopeFrom != "" {
+		senders = append(senders, envelopeFrom)
+	}
+	if _, ok := symbols[dmarcAllowSymbol]; ok {
+		for _, address := range senderlist.FromAddresses(headers) {
+			if len(senders) == 0 || !strings.EqualFold(address, senders[0]) {
+				senders = append(senders, address)
+			}
+		}
+	}
+	return senders
+}
+
+// isSpam reports whether a received mail is spam for a user: mails rejected by the spam filter always are, since their
+// sender may be forged, mails from the blocked senders of the user always are, mails from the allowed senders of the
+// user only flagged by the spam filter are not when the sender is authenticated, other mails are spam when the spam
+// filter flagged them.
+func isSpam(ctx context.Context, senderListRepo repositories.SenderListRepositoryInterface, userID primitive.ObjectID, senders []string, authenticated []string, rejected bool, flagged bool) (bool, error) {
+	filteredAsSpam := rejected || flagged
+	candidates := make([]string, 0)
+	for _, sender := range senders {
+		for _, candidate := range senderlist.Candidates(sender) {
+			if !slices.Contains(candidates, candidate) {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return filteredAsSpam, nil
+	}
+
+	entries, err := senderListRepo.GetBySenders(ctx, userID, candidates)
+	if err != nil {
+		return filteredAsSpam, err
+	}
+
+	switch senderlist.Verdict(entries, senders) {
+	case models.SenderListAllowed:
+		// a forged sender must not lift the verdict of the spam filter
+		if senderlist.Verdict(entries, authenticated) != models.SenderListAllowed {
+			return filteredAsSpam, nil
+		}
+		return rejected, nil
+	case models.SenderListBlocked:
+		return true, nil
+	default:
+		return filteredAsSpam, nil
+	}
+}
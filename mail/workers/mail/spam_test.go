@@ -0,0 +1,100 @@
+package mail
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/atomic-blend/backend/mail/models"
+	"github.com/atomic-blend/backend/mail/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestIsSpam(t *testing.T) {
+	userID := primitive.NewObjectID()
+	senders := []string{"news@mail.example.com"}
+	candidates := []string{"news@mail.example.com", "mail.example.com", "example.com"}
+
+	t.Run("follows the spam filter without entries", func(t *testing.T) {
+		repo := &mocks.MockSenderListRepository{}
+		repo.On("GetBySenders", mock.Anything, userID, candidates).Return([]*models.SenderListEntry{}, nil)
+
+		spam, err := isSpam(t.Context(), repo, userID, senders, senders, false, true)
+		require.NoError(t, err)
+		assert.True(t, spam)
+
+		spam, err = isSpam(t.Context(), repo, userID, senders, senders, false, false)
+		require.NoError(t, err)
+		assert.False(t, spam)
+	})
+
+	t.Run("allowed sender", func(t *testing.T) {
+		repo := &mocks.MockSenderListRepository{}
+		repo.On("GetBySenders", mock.Anything, userID, candidates).Return([]*models.SenderListEntry{
+			{UserID: userID, Sender: "news@mail.example.com", List: models.SenderListAllowed},
+			{UserID: userID, Sender: "example.com", List: models.SenderListBlocked},
+		}, nil)
+
+		spam, err := isSpam(t.Context(), repo, userID, senders, senders, false, true)
+		require.NoError(t, err)
+		assert.False(t, spam)
+	})
+
+	t.Run("forged allowed sender does not lift a flag", func(t *testing.T) {
+		repo := &mocks.MockSenderListRepository{}
+		repo.On("GetBySenders", mock.Anything, userID, candidates).Return([]*models.SenderListEntry{
+			{UserID: userID, Sender: "news@mail.example.com", List: models.SenderListAllowed},
+		}, nil)
+
+		spam, err := isSpam(t.Context(), repo, userID, senders, []string{}, false, true)
+		require.NoError(t, err)
+		assert.True(t, spam)
+	})
+
+	t.Run("allowed sender does not lift a rejection", func(t *testing.T) {
+		repo := &mocks.MockSenderListRepository{}
+		repo.On("GetBySenders", mock.Anything, userID, candidates).Return([]*models.SenderListEntry{
+			{UserID: userID, Sender: "news@mail.example.com", List: models.SenderListAllowed},
+		}, nil)
+
+		spam, err := isSpam(t.Context(), repo, userID, senders, senders, true, false)
+		require.NoError(t, err)
+		assert.True(t, spam)
+	})
+
+	t.Run("blocked domain", func(t *testing.T) {
+		repo := &mocks.MockSenderListRepository{}
+		repo.On("GetBySenders", mock.Anything, userID, candidates).Return([]*models.SenderListEntry{
+			{UserID: userID, Sender: "example.com", List: models.SenderListBlocked},
+		}, nil)
+
+		spam, err := isSpam(t.Context(), repo, userID, senders, senders, false, false)
+		require.NoError(t, err)
+		assert.True(t, spam)
+	})
+
+	t.Run("keeps the spam filter verdict on error", func(t *testing.T) {
+		repo := &mocks.MockSenderListRepository{}
+		repo.On("GetBySenders", mock.Anything, userID, candidates).Return(nil, errors.New("database error"))
+
+		spam, err := isSpam(t.Context(), repo, userID, senders, senders, false, true)
+		assert.Error(t, err)
+		assert.True(t, spam)
+	})
+}
+
+func TestAuthenticatedSenders(t *testing.T) {
+	headers := map[string]interface{}{"From": []string{"Boss <boss@example.com>"}}
+	spf := map[string]interface{}{spfAllowSymbol: map[string]interface{}{}}
+	dmarc := map[string]interface{}{dmarcAllowSymbol: map[string]interface{}{}}
+	both := map[string]interface{}{spfAllowSymbol: map[string]interface{}{}, dmarcAllowSymbol: map[string]interface{}{}}
+
+	assert.Empty(t, authenticatedSenders("bounce@example.com", headers, nil))
+	assert.Equal(t, []string{"bounce@example.com"}, authenticatedSenders("bounce@example.com", headers, spf))
+	assert.Equal(t, []string{"boss@example.com"}, authenticatedSenders("bounce@example.com", headers, dmarc))
+	assert.Equal(t, []string{"bounce@example.com", "boss@example.com"}, authenticatedSenders("bounce@example.com", headers, both))
+	assert.Equal(t, []string{"boss@example.com"}, authenticatedSenders("boss@example.com", headers, both))
+}
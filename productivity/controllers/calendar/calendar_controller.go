@@ -0,0 +1,55 @@
+package calendar
+
+import (
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Controller handles the iCalendar feed of the tasks and habits of the users
+type Controller struct {
+	feedRepo  repositories.CalendarFeedRepositoryInterface
+	taskRepo  repositories.TaskRepositoryInterface
+	habitRepo repositories.HabitRepositoryInterface
+}
+
+// NewCalendarController creates a new calendar feed controller instance
+func NewCalendarController(feedRepo repositories.CalendarFeedRepositoryInterface, taskRepo repositories.TaskRepositoryInterface, habitRepo repositories.HabitRepositoryInterface) *Controller {
+	return &Controller{
+		feedRepo:  feedRepo,
+		taskRepo:  taskRepo,
+		habitRepo: habitRepo,
+	}
+}
+
+// SetupRoutes sets up the routes for the calendar feed controller
+func SetupRoutes(router *gin.Engine, database *mongo.Database) {
+	calendarController := NewCalendarController(
+		repositories.NewCalendarFeedRepository(database),
+		repositories.NewTaskRepository(database),
+		repositories.NewHabitRepository(database),
+	)
+	setupCalendarRoutes(router, calendarController)
+}
+
+// SetupRoutesWithMock sets up the calendar feed routes with mock repositories for testing
+func SetupRoutesWithMock(router *gin.Engine, feedRepo repositories.CalendarFeedRepositoryInterface, taskRepo repositories.TaskRepositoryInterface, habitRepo repositories.HabitRepositoryInterface) {
+	calendarController := NewCalendarController(feedRepo, taskRepo, habitRepo)
+	setupCalendarRoutes(router, calendarController)
+}
+
+// setupCalendarRoutes sets up the routes for calendar feed controller
+func setupCalendarRoutes(router *gin.Engine, calendarController *Controller) {
+	// calendar apps cannot authenticate, the feed is read with its secret token
+	router.GET("/calendar/feeds/:token", calendarController.GetFeed)
+
+	calendarRoutes := router.Group("/calendar")
+	auth.RequireAuth(calendarRoutes)
+	{
+		calendarRoutes.GET("/feed", calendarController.GetFeedInfo)
+		calendarRoutes.POST("/feed", calendarController.GenerateFeedToken)
+		calendarRoutes.DELETE("/feed", calendarController.RevokeFeedToken)
+	}
+}
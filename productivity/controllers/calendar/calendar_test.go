@@ -0,0 +1,155 @@
+package calendar
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func setupTest() (*Controller, *mocks.MockCalendarFeedRepository, *mocks.MockTaskRepository, *mocks.MockHabitRepository) {
+	gin.SetMode(gin.TestMode)
+	feedRepo := new(mocks.MockCalendarFeedRepository)
+	taskRepo := new(mocks.MockTaskRepository)
+	habitRepo := new(mocks.MockHabitRepository)
+	return NewCalendarController(feedRepo, taskRepo, habitRepo), feedRepo, taskRepo, habitRepo
+}
+
+func newContext(method, path string, userID *primitive.ObjectID) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(method, path, nil)
+	if userID != nil {
+		c.Set("authUser", &auth.UserAuthInfo{UserID: *userID})
+	}
+	return c, w
+}
+
+func TestGetFeedInfo(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	t.Run("Success", func(t *testing.T) {
+		controller, feedRepo, _, _ := setupTest()
+		feedRepo.On("GetByUserID", mock.Anything, userID).Return(&models.CalendarFeed{UserID: userID, TokenHash: "secret"}, nil)
+
+		c, w := newContext(http.MethodGet, "/calendar/feed", &userID)
+		controller.GetFeedInfo(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "secret")
+	})
+
+	t.Run("No Feed", func(t *testing.T) {
+		controller, feedRepo, _, _ := setupTest()
+		feedRepo.On("GetByUserID", mock.Anything, userID).Return(nil, nil)
+
+		c, w := newContext(http.MethodGet, "/calendar/feed", &userID)
+		controller.GetFeedInfo(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		controller, _, _, _ := setupTest()
+
+		c, w := newContext(http.MethodGet, "/calendar/feed", nil)
+		controller.GetFeedInfo(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestGenerateFeedToken(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	t.Run("Success", func(t *testing.T) {
+		controller, feedRepo, _, _ := setupTest()
+		var saved string
+		feedRepo.On("Save", mock.Anything, userID, mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) { saved = args.String(2) }).
+			Return(&models.CalendarFeed{UserID: userID, CreatedAt: primitive.NewDateTimeFromTime(time.Now())}, nil)
+
+		c, w := newContext(http.MethodPost, "/calendar/feed", &userID)
+		controller.GenerateFeedToken(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response FeedTokenResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Token, feedTokenLength)
+		assert.Equal(t, saved, response.Token)
+		assert.Equal(t, "/calendar/feeds/"+saved+".ics", response.Path)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		controller, feedRepo, _, _ := setupTest()
+		feedRepo.On("Save", mock.Anything, userID, mock.AnythingOfType("string")).Return(nil, errors.New("database error"))
+
+		c, w := newContext(http.MethodPost, "/calendar/feed", &userID)
+		controller.GenerateFeedToken(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestRevokeFeedToken(t *testing.T) {
+	userID := primitive.NewObjectID()
+	controller, feedRepo, _, _ := setupTest()
+	feedRepo.On("DeleteByUserID", mock.Anything, userID).Return(nil)
+
+	c, w := newContext(http.MethodDelete, "/calendar/feed", &userID)
+	controller.RevokeFeedToken(c)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	feedRepo.AssertExpectations(t)
+}
+
+func TestGetFeed(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	t.Run("Success Without Authentication", func(t *testing.T) {
+		_, feedRepo, taskRepo, habitRepo := setupTest()
+		start := time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC)
+		startDate := primitive.NewDateTimeFromTime(start)
+		endDate := primitive.NewDateTimeFromTime(start.Add(time.Hour))
+
+		feedRepo.On("GetByToken", mock.Anything, "abc123").Return(&models.CalendarFeed{UserID: userID}, nil)
+		taskRepo.On("GetAll", mock.Anything, &userID, (*int64)(nil), (*int64)(nil)).
+			Return([]*models.TaskEntity{{ID: "t1", Title: "Meeting", StartDate: &startDate, EndDate: &endDate}}, int64(1), nil)
+		habitRepo.On("GetAll", mock.Anything, &userID).Return([]*models.Habit{}, nil)
+
+		router := gin.New()
+		SetupRoutesWithMock(router, feedRepo, taskRepo, habitRepo)
+		req, _ := http.NewRequest(http.MethodGet, "/calendar/feeds/abc123.ics", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/calendar"))
+		assert.Contains(t, w.Body.String(), "SUMMARY:Meeting\r\n")
+	})
+
+	t.Run("Unknown Token", func(t *testing.T) {
+		controller, feedRepo, taskRepo, _ := setupTest()
+		feedRepo.On("GetByToken", mock.Anything, "revoked").Return(nil, nil)
+
+		c, w := newContext(http.MethodGet, "/calendar/feeds/revoked.ics", nil)
+		c.Params = []gin.Param{{Key: "token", Value: "revoked.ics"}}
+		controller.GetFeed(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		taskRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,57 @@
+package calendar
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/utils/ical"
+
+	"github.com/gin-gonic/gin"
+)
+
+// feedName is the name calendar apps show for the feed
+const feedName = "Atomic Blend"
+
+// GetFeed returns the iCalendar feed of a secret token
+// @Summary Get calendar feed content
+// @Description Get the dated tasks and the habits of the owner of the token as an iCalendar document. This route does not require authentication, the token is the secret.
+// @Tags Calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token, optionally followed by .ics"
+// @Success 200 {string} string
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /calendar/feeds/{token} [get]
+func (c *Controller) GetFeed(ctx *gin.Context) {
+	token := strings.TrimSuffix(ctx.Param("token"), ".ics")
+	if token == "" {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Calendar feed not found"})
+		return
+	}
+
+	feed, err := c.feedRepo.GetByToken(ctx, token)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if feed == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Calendar feed not found"})
+		return
+	}
+
+	tasks, _, err := c.taskRepo.GetAll(ctx, &feed.UserID, nil, nil)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	habits, err := c.habitRepo.GetAll(ctx, &feed.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, max-age=300")
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(ical.Feed(feedName, tasks, habits, time.Now())))
+}
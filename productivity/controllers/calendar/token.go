@@ -0,0 +1,108 @@
+package calendar
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+	"github.com/atomic-blend/backend/shared/utils/password"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// feedTokenLength is the length of the secret token of a calendar feed
+const feedTokenLength = 64
+
+// FeedTokenResponse is returned when a feed token is generated, the token cannot be read again afterwards
+type FeedTokenResponse struct {
+	Token     string             `json:"token"`
+	Path      string             `json:"path"`
+	CreatedAt primitive.DateTime `json:"createdAt"`
+}
+
+// GetFeedInfo tells whether the authenticated user has a calendar feed
+// @Summary Get calendar feed
+// @Description Get the creation date of the calendar feed of the authenticated user. The token itself is only returned when it is generated.
+// @Tags Calendar
+// @Produce json
+// @Success 200 {object} models.CalendarFeed
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /calendar/feed [get]
+func (c *Controller) GetFeedInfo(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	feed, err := c.feedRepo.GetByUserID(ctx, authUser.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if feed == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Calendar feed not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, feed)
+}
+
+// GenerateFeedToken generates the secret token of the calendar feed of the authenticated user
+// @Summary Generate calendar feed token
+// @Description Generate a new secret token for the calendar feed of the authenticated user. The previous token, if any, stops working.
+// @Tags Calendar
+// @Produce json
+// @Success 201 {object} FeedTokenResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /calendar/feed [post]
+func (c *Controller) GenerateFeedToken(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	token, err := password.GenerateRandomString(feedTokenLength)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token: " + err.Error()})
+		return
+	}
+
+	feed, err := c.feedRepo.Save(ctx, authUser.UserID, token)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, FeedTokenResponse{
+		Token:     token,
+		Path:      "/calendar/feeds/" + token + ".ics",
+		CreatedAt: feed.CreatedAt,
+	})
+}
+
+// RevokeFeedToken revokes the calendar feed of the authenticated user
+// @Summary Revoke calendar feed token
+// @Tags Calendar
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /calendar/feed [delete]
+func (c *Controller) RevokeFeedToken(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	if err := c.feedRepo.DeleteByUserID(ctx, authUser.UserID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
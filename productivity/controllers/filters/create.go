@@ -0,0 +1,51 @@
+package filters
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateFilter creates a new saved filter
+// @Summary Create saved filter
+// @Description Save a task filter with its sort criteria under a name
+// @Tags Filters
+// @Accept json
+// @Produce json
+// @Param filter body models.SavedFilter true "Saved filter"
+// @Success 201 {object} models.SavedFilter
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /filters [post]
+func (c *Controller) CreateFilter(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var filter models.SavedFilter
+	if err := ctx.ShouldBindJSON(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateFilter(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter.ID = nil
+	filter.UserID = authUser.UserID
+
+	createdFilter, err := c.filterRepo.Create(ctx, &filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating filter: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, createdFilter)
+}
@@ -0,0 +1,40 @@
+package filters
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteFilter deletes a saved filter, the tasks it matches are kept
+// @Summary Delete saved filter
+// @Tags Filters
+// @Param id path string true "Filter ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /filters/{id} [delete]
+func (c *Controller) DeleteFilter(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	filter, ok := c.getOwnedFilter(ctx, authUser.UserID)
+	if !ok {
+		return
+	}
+
+	if err := c.filterRepo.Delete(ctx, *filter.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting filter: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,102 @@
+package filters
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	userclient "github.com/atomic-blend/backend/shared/grpc/user"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Controller handles the saved filters of tasks
+type Controller struct {
+	filterRepo repositories.SavedFilterRepositoryInterface
+	taskRepo   repositories.TaskRepositoryInterface
+	userClient userclient.Interface
+}
+
+// NewFilterController creates a new saved filter controller instance
+func NewFilterController(filterRepo repositories.SavedFilterRepositoryInterface, taskRepo repositories.TaskRepositoryInterface, userClient userclient.Interface) *Controller {
+	return &Controller{
+		filterRepo: filterRepo,
+		taskRepo:   taskRepo,
+		userClient: userClient,
+	}
+}
+
+// SetupRoutes sets up the routes for the saved filter controller
+func SetupRoutes(router *gin.Engine, database *mongo.Database) {
+	var userClient userclient.Interface
+	if client, err := userclient.NewUserClient(); err == nil {
+		userClient = client
+	}
+	filterController := NewFilterController(repositories.NewSavedFilterRepository(database), repositories.NewTaskRepository(database), userClient)
+	setupFilterRoutes(router, filterController)
+}
+
+// SetupRoutesWithMock sets up the saved filter routes with mock repositories for testing
+func SetupRoutesWithMock(router *gin.Engine, filterRepo repositories.SavedFilterRepositoryInterface, taskRepo repositories.TaskRepositoryInterface, userClient userclient.Interface) {
+	filterController := NewFilterController(filterRepo, taskRepo, userClient)
+	setupFilterRoutes(router, filterController)
+}
+
+// setupFilterRoutes sets up the routes for saved filter controller
+func setupFilterRoutes(router *gin.Engine, filterController *Controller) {
+	filterRoutes := router.Group("/filters")
+	auth.RequireAuth(filterRoutes)
+	{
+		filterRoutes.POST("", filterController.CreateFilter)
+		filterRoutes.GET("", filterController.GetAllFilters)
+		filterRoutes.GET("/:id", filterController.GetFilterByID)
+		filterRoutes.GET("/:id/tasks", filterController.GetFilterTasks)
+		filterRoutes.PUT("/:id", filterController.UpdateFilter)
+		filterRoutes.DELETE("/:id", filterController.DeleteFilter)
+	}
+}
+
+// validateFilter checks the criteria of a saved filter which cannot be checked by the binding
+func validateFilter(filter *models.SavedFilter) error {
+	for _, sort := range filter.Sort {
+		valid := false
+		for _, key := range models.ValidTaskSortKeys {
+			valid = valid || key == sort.Key
+		}
+		if !valid {
+			return errors.New("invalid sort key: " + sort.Key)
+		}
+	}
+	if filter.DueWithinDays != nil && (filter.Filter.DueFrom != nil || filter.Filter.DueTo != nil) {
+		return errors.New("dueWithinDays cannot be combined with dueFrom or dueTo")
+	}
+	return nil
+}
+
+// getOwnedFilter returns the saved filter of the request if it belongs to the user, it writes the error response otherwise
+func (c *Controller) getOwnedFilter(ctx *gin.Context, userID primitive.ObjectID) (*models.SavedFilter, bool) {
+	filterID, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter ID"})
+		return nil, false
+	}
+
+	filter, err := c.filterRepo.GetByID(ctx, filterID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving filter: " + err.Error()})
+		return nil, false
+	}
+	if filter == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Filter not found"})
+		return nil, false
+	}
+	if filter.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return nil, false
+	}
+	return filter, true
+}
@@ -0,0 +1,215 @@
+package filters
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/controllers/tasks"
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func setupTest() (*Controller, *mocks.MockSavedFilterRepository, *mocks.MockTaskRepository) {
+	gin.SetMode(gin.TestMode)
+	filterRepo := new(mocks.MockSavedFilterRepository)
+	taskRepo := new(mocks.MockTaskRepository)
+	return NewFilterController(filterRepo, taskRepo, nil), filterRepo, taskRepo
+}
+
+func newContext(method, path, body string, userID *primitive.ObjectID, id string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(method, path, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	if id != "" {
+		c.Params = []gin.Param{{Key: "id", Value: id}}
+	}
+	if userID != nil {
+		c.Set("authUser", &auth.UserAuthInfo{UserID: *userID})
+	}
+	return c, w
+}
+
+func TestCreateFilter(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	t.Run("Success", func(t *testing.T) {
+		controller, filterRepo, _ := setupTest()
+		var saved *models.SavedFilter
+		filterRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.SavedFilter")).
+			Run(func(args mock.Arguments) { saved = args.Get(1).(*models.SavedFilter) }).
+			Return(&models.SavedFilter{Name: "High priority"}, nil)
+
+		c, w := newContext(http.MethodPost, "/filters", `{"name":"High priority","filter":{"priorities":[3],"completed":false},"sort":[{"key":"endDate"}]}`, &userID, "")
+		controller.CreateFilter(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		require.NotNil(t, saved)
+		assert.Equal(t, userID, saved.UserID)
+		assert.Equal(t, []int{3}, saved.Filter.Priorities)
+	})
+
+	t.Run("Invalid Sort Key", func(t *testing.T) {
+		controller, filterRepo, _ := setupTest()
+
+		c, w := newContext(http.MethodPost, "/filters", `{"name":"Bad","sort":[{"key":"color"}]}`, &userID, "")
+		controller.CreateFilter(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		filterRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Missing Name", func(t *testing.T) {
+		controller, _, _ := setupTest()
+
+		c, w := newContext(http.MethodPost, "/filters", `{"filter":{}}`, &userID, "")
+		controller.CreateFilter(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("No Auth", func(t *testing.T) {
+		controller, _, _ := setupTest()
+
+		c, w := newContext(http.MethodPost, "/filters", `{"name":"Any"}`, nil, "")
+		controller.CreateFilter(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestGetFilterTasks(t *testing.T) {
+	userID := primitive.NewObjectID()
+	filterID := primitive.NewObjectID()
+	tagID := primitive.NewObjectID()
+	completed := false
+	days := 7
+	saved := &models.SavedFilter{
+		ID:            &filterID,
+		UserID:        userID,
+		Name:          "Tag this week",
+		Filter:        models.TaskFilter{Completed: &completed, TagIDs: []primitive.ObjectID{tagID}},
+		DueWithinDays: &days,
+		Sort:          []models.TaskSort{{Key: models.TaskSortPriority, Descending: true}},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		controller, filterRepo, taskRepo := setupTest()
+		filterRepo.On("GetByID", mock.Anything, filterID).Return(saved, nil)
+		taskRepo.On("Search", mock.Anything, &userID, mock.MatchedBy(func(filter *models.TaskFilter) bool {
+			return filter.Completed != nil && !*filter.Completed &&
+				len(filter.TagIDs) == 1 && filter.TagIDs[0] == tagID &&
+				filter.DueFrom != nil && filter.DueTo != nil
+		}), saved.Sort, mock.Anything, mock.Anything).
+			Return([]*models.TaskEntity{{ID: primitive.NewObjectID().Hex(), Title: "Task"}}, int64(3), nil)
+
+		c, w := newContext(http.MethodGet, "/filters/"+filterID.Hex()+"/tasks?page=1&limit=1", "", &userID, filterID.Hex())
+		controller.GetFilterTasks(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response tasks.PaginatedTaskResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Tasks, 1)
+		assert.Equal(t, int64(3), response.TotalCount)
+		assert.Equal(t, int64(3), response.TotalPages)
+		taskRepo.AssertExpectations(t)
+	})
+
+	t.Run("Days In The Timezone", func(t *testing.T) {
+		controller, filterRepo, taskRepo := setupTest()
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+		filterRepo.On("GetByID", mock.Anything, filterID).Return(saved, nil)
+		taskRepo.On("Search", mock.Anything, &userID, mock.MatchedBy(func(filter *models.TaskFilter) bool {
+			// the days start at midnight in Tokyo
+			start := filter.DueFrom.Time().In(tokyo)
+			return start.Hour() == 0 && start.Minute() == 0
+		}), saved.Sort, mock.Anything, mock.Anything).Return([]*models.TaskEntity{}, int64(0), nil)
+
+		c, w := newContext(http.MethodGet, "/filters/"+filterID.Hex()+"/tasks?timezone=Asia/Tokyo", "", &userID, filterID.Hex())
+		controller.GetFilterTasks(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		taskRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Timezone", func(t *testing.T) {
+		controller, _, _ := setupTest()
+
+		c, w := newContext(http.MethodGet, "/filters/"+filterID.Hex()+"/tasks?timezone=Not/AZone", "", &userID, filterID.Hex())
+		controller.GetFilterTasks(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Other User", func(t *testing.T) {
+		controller, filterRepo, taskRepo := setupTest()
+		filterRepo.On("GetByID", mock.Anything, filterID).Return(saved, nil)
+
+		otherID := primitive.NewObjectID()
+		c, w := newContext(http.MethodGet, "/filters/"+filterID.Hex()+"/tasks", "", &otherID, filterID.Hex())
+		controller.GetFilterTasks(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		taskRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		controller, filterRepo, _ := setupTest()
+		filterRepo.On("GetByID", mock.Anything, filterID).Return(nil, nil)
+
+		c, w := newContext(http.MethodGet, "/filters/"+filterID.Hex()+"/tasks", "", &userID, filterID.Hex())
+		controller.GetFilterTasks(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Invalid Page", func(t *testing.T) {
+		controller, _, _ := setupTest()
+
+		c, w := newContext(http.MethodGet, "/filters/"+filterID.Hex()+"/tasks?page=0", "", &userID, filterID.Hex())
+		controller.GetFilterTasks(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUpdateFilter(t *testing.T) {
+	userID := primitive.NewObjectID()
+	filterID := primitive.NewObjectID()
+
+	t.Run("Relative And Absolute Dates", func(t *testing.T) {
+		controller, filterRepo, _ := setupTest()
+		filterRepo.On("GetByID", mock.Anything, filterID).Return(&models.SavedFilter{ID: &filterID, UserID: userID}, nil)
+
+		body := `{"name":"Mixed","dueWithinDays":7,"filter":{"dueFrom":"2025-01-01T00:00:00Z"}}`
+		c, w := newContext(http.MethodPut, "/filters/"+filterID.Hex(), body, &userID, filterID.Hex())
+		controller.UpdateFilter(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		filterRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		controller, filterRepo, _ := setupTest()
+		filterRepo.On("GetByID", mock.Anything, filterID).Return(&models.SavedFilter{ID: &filterID, UserID: userID}, nil)
+		filterRepo.On("Update", mock.Anything, filterID, mock.AnythingOfType("*models.SavedFilter")).Return(&models.SavedFilter{ID: &filterID, UserID: userID, Name: "Renamed"}, nil)
+
+		c, w := newContext(http.MethodPut, "/filters/"+filterID.Hex(), `{"name":"Renamed"}`, &userID, filterID.Hex())
+		controller.UpdateFilter(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		filterRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,64 @@
+package filters
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAllFilters returns the saved filters of the user
+// @Summary Get all saved filters
+// @Tags Filters
+// @Produce json
+// @Success 200 {array} models.SavedFilter
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /filters [get]
+func (c *Controller) GetAllFilters(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	filters, err := c.filterRepo.GetAll(ctx, authUser.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving filters: " + err.Error()})
+		return
+	}
+	if filters == nil {
+		filters = []*models.SavedFilter{}
+	}
+
+	ctx.JSON(http.StatusOK, filters)
+}
+
+// GetFilterByID returns a saved filter of the user
+// @Summary Get saved filter
+// @Tags Filters
+// @Produce json
+// @Param id path string true "Filter ID"
+// @Success 200 {object} models.SavedFilter
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /filters/{id} [get]
+func (c *Controller) GetFilterByID(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	filter, ok := c.getOwnedFilter(ctx, authUser.UserID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, filter)
+}
@@ -0,0 +1,66 @@
+package filters
+
+import (
+	"context"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/utils/patch"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// patchAdapter applies patches to saved filters
+type patchAdapter struct {
+	filterRepo repositories.SavedFilterRepositoryInterface
+}
+
+// NewPatchAdapter creates the patch adapter for saved filters
+func NewPatchAdapter(filterRepo repositories.SavedFilterRepositoryInterface) patch.Adapter {
+	return &patchAdapter{filterRepo: filterRepo}
+}
+
+func (a *patchAdapter) Get(ctx context.Context, id primitive.ObjectID) (*patch.Item, error) {
+	filter, err := a.filterRepo.GetByID(ctx, id)
+	if err != nil || filter == nil {
+		return nil, err
+	}
+	item := &patch.Item{ID: id, Object: filter, Owner: filter.UserID}
+	if filter.UpdatedAt != nil {
+		item.UpdatedAt = filter.UpdatedAt.Time()
+	}
+	return item, nil
+}
+
+func (a *patchAdapter) New() interface{} {
+	return &models.SavedFilter{}
+}
+
+func (a *patchAdapter) Create(ctx context.Context, userID primitive.ObjectID, object interface{}) error {
+	filter := object.(*models.SavedFilter)
+	if validateFilter(filter) != nil {
+		return patch.ErrInvalidData
+	}
+	filter.UserID = userID
+
+	_, err := a.filterRepo.Create(ctx, filter)
+	return err
+}
+
+func (a *patchAdapter) Update(ctx context.Context, p *patchmodels.Patch, item *patch.Item) error {
+	filter := item.Object.(*models.SavedFilter)
+	if err := patch.ApplyChanges(filter, p.Changes); err != nil {
+		return err
+	}
+	if validateFilter(filter) != nil {
+		return patch.ErrInvalidData
+	}
+
+	_, err := a.filterRepo.Update(ctx, item.ID, filter)
+	return err
+}
+
+func (a *patchAdapter) Delete(ctx context.Context, item *patch.Item) error {
+	return a.filterRepo.Delete(ctx, item.ID)
+}
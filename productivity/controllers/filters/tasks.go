@@ -0,0 +1,100 @@
+package filters
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/controllers/tasks"
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/timezone"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetFilterTasks returns the tasks matching a saved filter, evaluated at the time of the request
+// @Summary Get the tasks of a saved filter
+// @Description Get the tasks of the user matching the saved filter, sorted by its sort criteria, with optional pagination
+// @Tags Filters
+// @Produce json
+// @Param id path string true "Filter ID"
+// @Param page query int false "Page number (1-based)"
+// @Param limit query int false "Number of tasks per page"
+// @Param timezone query string false "IANA timezone the days of dueWithinDays start in, overriding the timezone of the user's devices"
+// @Success 200 {object} tasks.PaginatedTaskResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /filters/{id}/tasks [get]
+func (c *Controller) GetFilterTasks(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var page, limit *int64
+	if value := ctx.Query("page"); value != "" {
+		pageVal, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || pageVal < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+			return
+		}
+		page = &pageVal
+	}
+	if value := ctx.Query("limit"); value != "" {
+		limitVal, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || limitVal < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		limit = &limitVal
+	}
+
+	loc, ok := c.location(ctx, authUser.UserID)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+		return
+	}
+
+	filter, ok := c.getOwnedFilter(ctx, authUser.UserID)
+	if !ok {
+		return
+	}
+
+	matchingTasks, totalCount, err := c.taskRepo.Search(ctx, &authUser.UserID, filter.TaskFilter(time.Now().In(loc)), filter.Sort, page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if matchingTasks == nil {
+		matchingTasks = []*models.TaskEntity{}
+	}
+
+	response := tasks.PaginatedTaskResponse{
+		Tasks:      matchingTasks,
+		TotalCount: totalCount,
+	}
+	if page != nil && limit != nil {
+		response.Page = *page
+		response.Size = *limit
+		response.TotalPages = (totalCount + *limit - 1) / *limit
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// location returns the timezone the days of the saved filters start in: the timezone query parameter if set,
+// otherwise the timezone of the user's devices, otherwise UTC.
+// It returns false when the timezone query parameter is not a valid timezone.
+func (c *Controller) location(ctx *gin.Context, userID primitive.ObjectID) (*time.Location, bool) {
+	if name := ctx.Query("timezone"); name != "" {
+		loc, err := time.LoadLocation(name)
+		return loc, err == nil
+	}
+	return timezone.OfUser(ctx, c.userClient, userID.Hex()), true
+}
@@ -0,0 +1,55 @@
+package filters
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateFilter replaces the name and criteria of a saved filter
+// @Summary Update saved filter
+// @Tags Filters
+// @Accept json
+// @Produce json
+// @Param id path string true "Filter ID"
+// @Param filter body models.SavedFilter true "Saved filter"
+// @Success 200 {object} models.SavedFilter
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /filters/{id} [put]
+func (c *Controller) UpdateFilter(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	existing, ok := c.getOwnedFilter(ctx, authUser.UserID)
+	if !ok {
+		return
+	}
+
+	var filter models.SavedFilter
+	if err := ctx.ShouldBindJSON(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateFilter(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updatedFilter, err := c.filterRepo.Update(ctx, *existing.ID, &filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating filter: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updatedFilter)
+}
@@ -19,6 +19,7 @@ import (
 // @Success 201 {object} models.Folder
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /folders [post]
 func (c *Controller) CreateFolder(ctx *gin.Context) {
@@ -38,6 +39,18 @@ func (c *Controller) CreateFolder(ctx *gin.Context) {
 	// Set the user ID
 	folder.UserID = authUser.UserID
 
+	if folder.ParentID != nil {
+		folders, err := c.folderRepo.GetAll(ctx, authUser.UserID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving folders: " + err.Error()})
+			return
+		}
+		if !containsFolder(folders, *folder.ParentID) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Parent folder not found"})
+			return
+		}
+	}
+
 	// Create folder in database
 	createdFolder, err := c.folderRepo.Create(ctx, &folder)
 	if err != nil {
@@ -154,4 +154,26 @@ func TestCreateFolder(t *testing.T) {
 
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("Parent Folder Not Found", func(t *testing.T) {
+		mockRepo := new(mocks.MockFolderRepository)
+		controller := NewFolderController(mockRepo)
+
+		userID := primitive.NewObjectID()
+		parentID := primitive.NewObjectID()
+		mockRepo.On("GetAll", mock.Anything, userID).Return([]*models.Folder{}, nil)
+
+		jsonInput, _ := json.Marshal(models.Folder{Name: "Work", ParentID: &parentID})
+		req, _ := http.NewRequest(http.MethodPost, "/folders", bytes.NewBuffer(jsonInput))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller.CreateFolder(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
 }
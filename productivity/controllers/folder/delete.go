@@ -2,14 +2,27 @@ package folder
 
 import (
 	"net/http"
-	"github.com/atomic-blend/backend/shared/middlewares/auth"
 
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // DeleteFolder handles the deletion of a folder
+// @Summary Delete folder
+// @Description Delete a folder. With the reparent mode, the default, its subfolders and tasks move to its parent.
+// @Description With the cascade mode, its subfolders and the tasks of all of them are deleted.
+// @Tags Folders
+// @Param id path string true "Folder ID"
+// @Param mode query string false "reparent or cascade (default: reparent)"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /folders/{id} [delete]
 func (c *Controller) DeleteFolder(ctx *gin.Context) {
 	// Get authenticated user from context
 	authUser := auth.GetAuthUser(ctx)
@@ -18,21 +31,24 @@ func (c *Controller) DeleteFolder(ctx *gin.Context) {
 		return
 	}
 
-	folderID := ctx.Param("id")
-	if folderID == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Folder ID is required"})
+	mode := ctx.DefaultQuery("mode", models.FolderDeleteReparent)
+	if mode != models.FolderDeleteReparent && mode != models.FolderDeleteCascade {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delete mode"})
 		return
 	}
 
-	// Convert folderID to ObjectID
-	folderObjectID, err := primitive.ObjectIDFromHex(folderID)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+	folder, ok := c.getOwnedFolder(ctx, authUser.UserID)
+	if !ok {
 		return
 	}
 
 	// Delete folder in database
-	err = c.folderRepo.Delete(ctx, folderObjectID)
+	var err error
+	if mode == models.FolderDeleteCascade {
+		err = c.folderRepo.DeleteCascade(ctx, *folder.ID)
+	} else {
+		err = c.folderRepo.Delete(ctx, *folder.ID)
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting folder: " + err.Error()})
 		return
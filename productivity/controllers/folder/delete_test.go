@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
+	"github.com/atomic-blend/backend/productivity/models"
 	"github.com/atomic-blend/backend/productivity/tests/mocks"
 	"testing"
 
@@ -27,6 +28,7 @@ func TestDeleteFolder(t *testing.T) {
 		folderID := primitive.NewObjectID()
 
 		// Setup mock expectation
+		mockRepo.On("GetByID", mock.Anything, folderID).Return(&models.Folder{ID: &folderID, UserID: userID}, nil)
 		mockRepo.On("Delete", mock.Anything, folderID).Return(nil)
 
 		// Create request
@@ -116,6 +118,7 @@ func TestDeleteFolder(t *testing.T) {
 		folderID := primitive.NewObjectID()
 
 		// Setup mock to return error
+		mockRepo.On("GetByID", mock.Anything, folderID).Return(&models.Folder{ID: &folderID, UserID: userID}, nil)
 		mockRepo.On("Delete", mock.Anything, folderID).Return(errors.New("database error"))
 
 		// Create request
@@ -136,4 +139,86 @@ func TestDeleteFolder(t *testing.T) {
 
 		mockRepo.AssertExpectations(t)
 	})
+	t.Run("Cascade", func(t *testing.T) {
+		mockRepo := new(mocks.MockFolderRepository)
+		controller := NewFolderController(mockRepo)
+
+		userID := primitive.NewObjectID()
+		folderID := primitive.NewObjectID()
+
+		mockRepo.On("GetByID", mock.Anything, folderID).Return(&models.Folder{ID: &folderID, UserID: userID}, nil)
+		mockRepo.On("DeleteCascade", mock.Anything, folderID).Return(nil)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/folders/"+folderID.Hex()+"?mode=cascade", nil)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = []gin.Param{{Key: "id", Value: folderID.Hex()}}
+		c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller.DeleteFolder(c)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid Mode", func(t *testing.T) {
+		mockRepo := new(mocks.MockFolderRepository)
+		controller := NewFolderController(mockRepo)
+
+		folderID := primitive.NewObjectID()
+
+		req, _ := http.NewRequest(http.MethodDelete, "/folders/"+folderID.Hex()+"?mode=everything", nil)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = []gin.Param{{Key: "id", Value: folderID.Hex()}}
+		c.Set("authUser", &auth.UserAuthInfo{UserID: primitive.NewObjectID()})
+
+		controller.DeleteFolder(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockRepo := new(mocks.MockFolderRepository)
+		controller := NewFolderController(mockRepo)
+
+		folderID := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, folderID).Return(nil, nil)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/folders/"+folderID.Hex(), nil)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = []gin.Param{{Key: "id", Value: folderID.Hex()}}
+		c.Set("authUser", &auth.UserAuthInfo{UserID: primitive.NewObjectID()})
+
+		controller.DeleteFolder(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Other User", func(t *testing.T) {
+		mockRepo := new(mocks.MockFolderRepository)
+		controller := NewFolderController(mockRepo)
+
+		folderID := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, folderID).Return(&models.Folder{ID: &folderID, UserID: primitive.NewObjectID()}, nil)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/folders/"+folderID.Hex(), nil)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = []gin.Param{{Key: "id", Value: folderID.Hex()}}
+		c.Set("authUser", &auth.UserAuthInfo{UserID: primitive.NewObjectID()})
+
+		controller.DeleteFolder(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
 }
@@ -42,7 +42,9 @@ func setupFolderRoutes(router *gin.Engine, folderController *Controller) {
 		// Folder endpoints
 		folderRoutes.POST("", folderController.CreateFolder)
 		folderRoutes.GET("", folderController.GetAllFolders)
+		folderRoutes.GET("/tree", folderController.GetFolderTree)
 		folderRoutes.PUT("/:id", folderController.UpdateFolder)
+		folderRoutes.PUT("/:id/move", folderController.MoveFolder)
 		folderRoutes.DELETE("/:id", folderController.DeleteFolder)
 	}
 }
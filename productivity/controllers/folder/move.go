@@ -0,0 +1,109 @@
+package folder
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/foldertree"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MoveFolder moves a folder under a new parent
+// @Summary Move folder
+// @Description Move a folder under another folder of the user, or to the root when the parent is null
+// @Tags Folders
+// @Accept json
+// @Produce json
+// @Param id path string true "Folder ID"
+// @Param move body models.FolderMove true "New parent"
+// @Success 200 {object} models.Folder
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /folders/{id}/move [put]
+func (c *Controller) MoveFolder(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	folder, ok := c.getOwnedFolder(ctx, authUser.UserID)
+	if !ok {
+		return
+	}
+
+	var move models.FolderMove
+	if err := ctx.ShouldBindJSON(&move); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	folders, err := c.folderRepo.GetAll(ctx, authUser.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving folders: " + err.Error()})
+		return
+	}
+
+	if move.ParentID != nil && !containsFolder(folders, *move.ParentID) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Parent folder not found"})
+		return
+	}
+	if foldertree.WouldCycle(folders, *folder.ID, move.ParentID) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "A folder cannot be moved into itself or one of its subfolders"})
+		return
+	}
+
+	movedFolder, err := c.folderRepo.Move(ctx, *folder.ID, move.ParentID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error moving folder: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, movedFolder)
+}
+
+// getOwnedFolder returns the folder of the request if it belongs to the user, it writes the error response otherwise
+func (c *Controller) getOwnedFolder(ctx *gin.Context, userID primitive.ObjectID) (*models.Folder, bool) {
+	folderID := ctx.Param("id")
+	if folderID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Folder ID is required"})
+		return nil, false
+	}
+
+	folderObjectID, err := primitive.ObjectIDFromHex(folderID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return nil, false
+	}
+
+	folder, err := c.folderRepo.GetByID(ctx, folderObjectID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving folder: " + err.Error()})
+		return nil, false
+	}
+	if folder == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+		return nil, false
+	}
+	if folder.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return nil, false
+	}
+	folder.ID = &folderObjectID
+	return folder, true
+}
+
+func containsFolder(folders []*models.Folder, id primitive.ObjectID) bool {
+	for _, folder := range folders {
+		if folder.ID != nil && *folder.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package folder
+
+import (
+	"context"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/utils/foldertree"
+	"github.com/atomic-blend/backend/productivity/utils/patch"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// patchAdapter applies patches to folders
+type patchAdapter struct {
+	folderRepo repositories.FolderRepositoryInterface
+}
+
+// NewPatchAdapter creates the patch adapter for folders
+func NewPatchAdapter(folderRepo repositories.FolderRepositoryInterface) patch.Adapter {
+	return &patchAdapter{folderRepo: folderRepo}
+}
+
+func (a *patchAdapter) Get(ctx context.Context, id primitive.ObjectID) (*patch.Item, error) {
+	folder, err := a.folderRepo.GetByID(ctx, id)
+	if err != nil || folder == nil {
+		return nil, err
+	}
+	item := &patch.Item{ID: id, Object: folder, Owner: folder.UserID}
+	if folder.UpdatedAt != nil {
+		item.UpdatedAt = folder.UpdatedAt.Time()
+	}
+	return item, nil
+}
+
+func (a *patchAdapter) New() interface{} {
+	return &models.Folder{}
+}
+
+func (a *patchAdapter) Create(ctx context.Context, userID primitive.ObjectID, object interface{}) error {
+	folder := object.(*models.Folder)
+	folder.UserID = userID
+
+	if folder.ParentID != nil {
+		folders, err := a.folderRepo.GetAll(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !containsFolder(folders, *folder.ParentID) {
+			return patch.NewError("invalid_parent")
+		}
+	}
+
+	_, err := a.folderRepo.Create(ctx, folder)
+	return err
+}
+
+func (a *patchAdapter) Update(ctx context.Context, p *patchmodels.Patch, item *patch.Item) error {
+	folder := item.Object.(*models.Folder)
+	if err := patch.ApplyChanges(folder, p.Changes); err != nil {
+		return err
+	}
+
+	if folder.ParentID != nil {
+		folders, err := a.folderRepo.GetAll(ctx, item.Owner)
+		if err != nil {
+			return err
+		}
+		if !containsFolder(folders, *folder.ParentID) {
+			return patch.NewError("invalid_parent")
+		}
+		if foldertree.WouldCycle(folders, item.ID, folder.ParentID) {
+			return patch.NewError("folder_cycle")
+		}
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	folder.UpdatedAt = &now
+
+	_, err := a.folderRepo.Update(ctx, item.ID, folder)
+	return err
+}
+
+func (a *patchAdapter) Delete(ctx context.Context, item *patch.Item) error {
+	return a.folderRepo.Delete(ctx, item.ID)
+}
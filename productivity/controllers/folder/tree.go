@@ -0,0 +1,41 @@
+package folder
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/productivity/utils/foldertree"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFolderTree returns the folders of the user as a tree
+// @Summary Get folder tree
+// @Description Get the folders nested under their parents, with the number of tasks of each folder
+// @Tags Folders
+// @Produce json
+// @Success 200 {array} models.FolderNode
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /folders/tree [get]
+func (c *Controller) GetFolderTree(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	folders, err := c.folderRepo.GetAll(ctx, authUser.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving folders: " + err.Error()})
+		return
+	}
+
+	counts, err := c.folderRepo.GetTaskCounts(ctx, authUser.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error counting folder tasks: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, foldertree.Build(folders, counts))
+}
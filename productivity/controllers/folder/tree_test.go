@@ -0,0 +1,181 @@
+package folder
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetFolderTree(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := primitive.NewObjectID()
+	rootID := primitive.NewObjectID()
+	childID := primitive.NewObjectID()
+	folders := []*models.Folder{
+		{ID: &childID, Name: "Child", ParentID: &rootID, UserID: userID},
+		{ID: &rootID, Name: "Root", UserID: userID},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(mocks.MockFolderRepository)
+		controller := NewFolderController(mockRepo)
+
+		mockRepo.On("GetAll", mock.Anything, userID).Return(folders, nil)
+		mockRepo.On("GetTaskCounts", mock.Anything, userID).Return([]*models.FolderTaskCount{
+			{FolderID: rootID, Open: 1},
+			{FolderID: childID, Open: 2, Completed: 3},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/folders/tree", nil)
+		c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller.GetFolderTree(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var tree []*models.FolderNode
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tree))
+		require.Len(t, tree, 1)
+		assert.Equal(t, rootID, *tree[0].ID)
+		assert.Equal(t, int64(3), tree[0].TotalTaskCount)
+		require.Len(t, tree[0].Children, 1)
+		assert.Equal(t, int64(3), tree[0].Children[0].CompletedTaskCount)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("No Auth", func(t *testing.T) {
+		controller := NewFolderController(new(mocks.MockFolderRepository))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/folders/tree", nil)
+
+		controller.GetFolderTree(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Count Error", func(t *testing.T) {
+		mockRepo := new(mocks.MockFolderRepository)
+		controller := NewFolderController(mockRepo)
+
+		mockRepo.On("GetAll", mock.Anything, userID).Return(folders, nil)
+		mockRepo.On("GetTaskCounts", mock.Anything, userID).Return(nil, errors.New("database error"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/folders/tree", nil)
+		c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller.GetFolderTree(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestMoveFolder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := primitive.NewObjectID()
+	rootID := primitive.NewObjectID()
+	childID := primitive.NewObjectID()
+	otherID := primitive.NewObjectID()
+	folders := func() []*models.Folder {
+		return []*models.Folder{
+			{ID: &rootID, Name: "Root", UserID: userID},
+			{ID: &childID, Name: "Child", ParentID: &rootID, UserID: userID},
+			{ID: &otherID, Name: "Other", UserID: userID},
+		}
+	}
+
+	move := func(controller *Controller, folderID primitive.ObjectID, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPut, "/folders/"+folderID.Hex()+"/move", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = []gin.Param{{Key: "id", Value: folderID.Hex()}}
+		c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+		controller.MoveFolder(c)
+		return w
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(mocks.MockFolderRepository)
+		controller := NewFolderController(mockRepo)
+
+		mockRepo.On("GetByID", mock.Anything, rootID).Return(folders()[0], nil)
+		mockRepo.On("GetAll", mock.Anything, userID).Return(folders(), nil)
+		mockRepo.On("Move", mock.Anything, rootID, &otherID).Return(&models.Folder{ID: &rootID, ParentID: &otherID, UserID: userID}, nil)
+
+		w := move(controller, rootID, `{"parentId":"`+otherID.Hex()+`"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("To Root", func(t *testing.T) {
+		mockRepo := new(mocks.MockFolderRepository)
+		controller := NewFolderController(mockRepo)
+
+		mockRepo.On("GetByID", mock.Anything, childID).Return(folders()[1], nil)
+		mockRepo.On("GetAll", mock.Anything, userID).Return(folders(), nil)
+		mockRepo.On("Move", mock.Anything, childID, (*primitive.ObjectID)(nil)).Return(&models.Folder{ID: &childID, UserID: userID}, nil)
+
+		w := move(controller, childID, `{"parentId":null}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		mockRepo := new(mocks.MockFolderRepository)
+		controller := NewFolderController(mockRepo)
+
+		mockRepo.On("GetByID", mock.Anything, rootID).Return(folders()[0], nil)
+		mockRepo.On("GetAll", mock.Anything, userID).Return(folders(), nil)
+
+		w := move(controller, rootID, `{"parentId":"`+childID.Hex()+`"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown Parent", func(t *testing.T) {
+		mockRepo := new(mocks.MockFolderRepository)
+		controller := NewFolderController(mockRepo)
+
+		mockRepo.On("GetByID", mock.Anything, rootID).Return(folders()[0], nil)
+		mockRepo.On("GetAll", mock.Anything, userID).Return(folders(), nil)
+
+		w := move(controller, rootID, `{"parentId":"`+primitive.NewObjectID().Hex()+`"}`)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Other User", func(t *testing.T) {
+		mockRepo := new(mocks.MockFolderRepository)
+		controller := NewFolderController(mockRepo)
+
+		mockRepo.On("GetByID", mock.Anything, rootID).Return(&models.Folder{ID: &rootID, UserID: primitive.NewObjectID()}, nil)
+
+		w := move(controller, rootID, `{"parentId":null}`)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
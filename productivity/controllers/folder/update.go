@@ -5,6 +5,7 @@ import (
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
 
 	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/foldertree"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -40,6 +41,22 @@ func (c *Controller) UpdateFolder(ctx *gin.Context) {
 
 	folder.UserID = authUser.UserID
 
+	if folder.ParentID != nil {
+		folders, err := c.folderRepo.GetAll(ctx, authUser.UserID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving folders: " + err.Error()})
+			return
+		}
+		if !containsFolder(folders, *folder.ParentID) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Parent folder not found"})
+			return
+		}
+		if foldertree.WouldCycle(folders, folderObjectID, folder.ParentID) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "A folder cannot be moved into itself or one of its subfolders"})
+			return
+		}
+	}
+
 	// Update folder in database
 	updatedFolder, err := c.folderRepo.Update(ctx, folderObjectID, &folder)
 	if err != nil {
@@ -201,4 +201,29 @@ func TestUpdateFolder(t *testing.T) {
 
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("Parent Folder Not Found", func(t *testing.T) {
+		mockRepo := new(mocks.MockFolderRepository)
+		controller := NewFolderController(mockRepo)
+
+		userID := primitive.NewObjectID()
+		folderID := primitive.NewObjectID()
+		// the parent belongs to another user, it is not among the folders of the user
+		parentID := primitive.NewObjectID()
+		mockRepo.On("GetAll", mock.Anything, userID).Return([]*models.Folder{{ID: &folderID, UserID: userID, Name: "Work"}}, nil)
+
+		jsonInput, _ := json.Marshal(models.Folder{Name: "Work", ParentID: &parentID})
+		req, _ := http.NewRequest(http.MethodPut, "/folders/"+folderID.Hex(), bytes.NewBuffer(jsonInput))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = []gin.Param{{Key: "id", Value: folderID.Hex()}}
+		c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller.UpdateFolder(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
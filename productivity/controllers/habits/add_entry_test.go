@@ -65,7 +65,7 @@ func TestAddHabitEntry(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.AddHabitEntry(ctx)
 
 		// Assertions
@@ -109,7 +109,7 @@ func TestAddHabitEntry(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.AddHabitEntry(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -137,7 +137,7 @@ func TestAddHabitEntry(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.AddHabitEntry(ctx)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
@@ -170,7 +170,7 @@ func TestAddHabitEntry(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: wrongUserID})
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.AddHabitEntry(ctx)
 
 		assert.Equal(t, http.StatusForbidden, w.Code)
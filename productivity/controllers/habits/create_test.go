@@ -44,7 +44,7 @@ func TestCreateHabit(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly with our context that has auth
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.CreateHabit(ctx)
 
 		assert.Equal(t, http.StatusCreated, w.Code)
@@ -98,7 +98,7 @@ func TestCreateHabit(t *testing.T) {
 			IsSubscribed: &isSubscribed,
 		}})
 
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.CreateHabit(ctx)
 
 		assert.Equal(t, http.StatusForbidden, w.Code)
@@ -124,7 +124,7 @@ func TestCreateHabit(t *testing.T) {
 		ctx.Request = req
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.CreateHabit(ctx)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -146,7 +146,7 @@ func TestCreateHabit(t *testing.T) {
 		ctx.Request = req
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.CreateHabit(ctx)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -170,7 +170,7 @@ func TestCreateHabit(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.CreateHabit(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -195,7 +195,7 @@ func TestCreateHabit(t *testing.T) {
 		ctx.Request = req
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.CreateHabit(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -223,7 +223,7 @@ func TestCreateHabit(t *testing.T) {
 		ctx.Request = req
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.CreateHabit(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -252,7 +252,7 @@ func TestCreateHabit(t *testing.T) {
 		ctx.Request = req
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.CreateHabit(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -281,7 +281,7 @@ func TestCreateHabit(t *testing.T) {
 		ctx.Request = req
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.CreateHabit(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
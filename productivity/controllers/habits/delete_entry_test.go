@@ -33,7 +33,7 @@ func TestDeleteHabitEntry(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: entryID.Hex()}}
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.DeleteHabitEntry(ctx)
 
 		// Assertions
@@ -66,7 +66,7 @@ func TestDeleteHabitEntry(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: invalidID}}
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.DeleteHabitEntry(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -85,7 +85,7 @@ func TestDeleteHabitEntry(t *testing.T) {
 		// No params set to simulate missing ID
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.DeleteHabitEntry(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -108,7 +108,7 @@ func TestDeleteHabitEntry(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: entryID.Hex()}}
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.DeleteHabitEntry(ctx)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -41,7 +41,7 @@ func TestDeleteHabit(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: habitID.Hex()}}
 
 		// Call the controller directly with our context that has auth
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.DeleteHabit(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -59,7 +59,7 @@ func TestDeleteHabit(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: habitID}}
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.DeleteHabit(ctx)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -79,7 +79,7 @@ func TestDeleteHabit(t *testing.T) {
 		// No params set to simulate missing ID
 
 		// Call the controller directly with our context that has auth
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.DeleteHabit(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -100,7 +100,7 @@ func TestDeleteHabit(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: invalidID}}
 
 		// Call the controller directly with our context that has auth
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.DeleteHabit(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -123,7 +123,7 @@ func TestDeleteHabit(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: habitID.Hex()}}
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.DeleteHabit(ctx)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
@@ -150,7 +150,7 @@ func TestDeleteHabit(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: habitID.Hex()}}
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.DeleteHabit(ctx)
 
 		assert.Equal(t, http.StatusForbidden, w.Code)
@@ -180,7 +180,7 @@ func TestDeleteHabit(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: habitID.Hex()}}
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.DeleteHabit(ctx)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -68,7 +68,7 @@ func TestEditHabitEntry(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: entryID.Hex()}}
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.EditHabitEntry(ctx)
 
 		// Assertions
@@ -107,7 +107,7 @@ func TestEditHabitEntry(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: invalidID}}
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.EditHabitEntry(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -128,7 +128,7 @@ func TestEditHabitEntry(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: entryID.Hex()}}
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.EditHabitEntry(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -159,7 +159,7 @@ func TestEditHabitEntry(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: entryID.Hex()}}
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.EditHabitEntry(ctx)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
@@ -195,7 +195,7 @@ func TestEditHabitEntry(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: entryID.Hex()}}
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.EditHabitEntry(ctx)
 
 		assert.Equal(t, http.StatusForbidden, w.Code)
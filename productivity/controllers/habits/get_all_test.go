@@ -51,7 +51,7 @@ func TestGetAllHabits(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly with our context that has auth
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.GetAllHabits(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -83,7 +83,7 @@ func TestGetAllHabits(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly with our context that has auth
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.GetAllHabits(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
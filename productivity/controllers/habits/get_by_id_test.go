@@ -56,7 +56,7 @@ func TestGetHabitByID(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: habitID.Hex()}}
 
 		// Call the controller directly with our context that has auth
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.GetHabitByID(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -90,7 +90,7 @@ func TestGetHabitByID(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: nonExistentID.Hex()}}
 
 		// Call the controller directly with our context that has auth
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.GetHabitByID(ctx)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
@@ -132,7 +132,7 @@ func TestGetHabitByID(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: habitID.Hex()}}
 
 		// Call the controller directly with our context that has auth
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.GetHabitByID(ctx)
 
 		assert.Equal(t, http.StatusForbidden, w.Code)
@@ -152,7 +152,7 @@ func TestGetHabitByID(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: invalidID}}
 
 		// Call the controller directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.GetHabitByID(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -1,8 +1,9 @@
 package habits
 
 import (
-	"github.com/atomic-blend/backend/shared/middlewares/auth"
 	"github.com/atomic-blend/backend/productivity/repositories"
+	userclient "github.com/atomic-blend/backend/shared/grpc/user"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -10,26 +11,32 @@ import (
 
 // HabitController handles habit related operations
 type HabitController struct {
-	habitRepo repositories.HabitRepositoryInterface
+	habitRepo  repositories.HabitRepositoryInterface
+	userClient userclient.Interface
 }
 
 // NewHabitController creates a new habit controller instance
-func NewHabitController(habitRepo repositories.HabitRepositoryInterface) *HabitController {
+func NewHabitController(habitRepo repositories.HabitRepositoryInterface, userClient userclient.Interface) *HabitController {
 	return &HabitController{
-		habitRepo: habitRepo,
+		habitRepo:  habitRepo,
+		userClient: userClient,
 	}
 }
 
 // SetupRoutes sets up the routes for the habit controller
 func SetupRoutes(router *gin.Engine, database *mongo.Database) {
 	habitRepo := repositories.NewHabitRepository(database)
-	habitController := NewHabitController(habitRepo)
+	var userClient userclient.Interface
+	if client, err := userclient.NewUserClient(); err == nil {
+		userClient = client
+	}
+	habitController := NewHabitController(habitRepo, userClient)
 	setupHabitRoutes(router, habitController)
 }
 
 // SetupRoutesWithMock sets up the habit routes with a mock repository for testing
-func SetupRoutesWithMock(router *gin.Engine, habitRepo repositories.HabitRepositoryInterface) {
-	habitController := NewHabitController(habitRepo)
+func SetupRoutesWithMock(router *gin.Engine, habitRepo repositories.HabitRepositoryInterface, userClient userclient.Interface) {
+	habitController := NewHabitController(habitRepo, userClient)
 	setupHabitRoutes(router, habitController)
 }
 
@@ -41,7 +48,9 @@ func setupHabitRoutes(router *gin.Engine, habitController *HabitController) {
 		// Main habit endpoints
 		habitRoutes.POST("", habitController.CreateHabit)
 		habitRoutes.GET("", habitController.GetAllHabits)
+		habitRoutes.GET("/stats", habitController.GetAllHabitStats)
 		habitRoutes.GET("/:id", habitController.GetHabitByID)
+		habitRoutes.GET("/:id/stats", habitController.GetHabitStats)
 		habitRoutes.PUT("/:id", habitController.UpdateHabit)
 		habitRoutes.DELETE("/:id", habitController.DeleteHabit)
 
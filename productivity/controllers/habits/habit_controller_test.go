@@ -14,7 +14,7 @@ import (
 
 func TestNewHabitController(t *testing.T) {
 	mockRepo := new(mocks.MockHabitRepository)
-	controller := NewHabitController(mockRepo)
+	controller := NewHabitController(mockRepo, nil)
 
 	assert.NotNil(t, controller)
 	assert.Equal(t, mockRepo, controller.habitRepo)
@@ -25,7 +25,7 @@ func TestSetupRoutesWithMock(t *testing.T) {
 	router := gin.New()
 	mockRepo := new(mocks.MockHabitRepository)
 
-	SetupRoutesWithMock(router, mockRepo)
+	SetupRoutesWithMock(router, mockRepo, nil)
 
 	// Test that routes are properly registered by making test requests
 	testRoutes := []struct {
@@ -34,6 +34,8 @@ func TestSetupRoutesWithMock(t *testing.T) {
 	}{
 		{http.MethodGet, "/habits"},
 		{http.MethodGet, "/habits/123"},
+		{http.MethodGet, "/habits/stats"},
+		{http.MethodGet, "/habits/123/stats"},
 		{http.MethodPost, "/habits"},
 		{http.MethodPut, "/habits/123"},
 		{http.MethodDelete, "/habits/123"},
@@ -63,7 +65,7 @@ func TestSetupRoutes(t *testing.T) {
 
 	// Use SetupRoutesWithMock instead of SetupRoutes to avoid database dependency
 	assert.NotPanics(t, func() {
-		SetupRoutesWithMock(router, mockRepo)
+		SetupRoutesWithMock(router, mockRepo, nil)
 	})
 }
 
@@ -71,10 +73,10 @@ func TestRouteRegistration(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 	mockRepo := new(mocks.MockHabitRepository)
-	controller := NewHabitController(mockRepo)
+	controller := NewHabitController(mockRepo, nil)
 
 	// Call the function through public function
-	SetupRoutesWithMock(router, mockRepo)
+	SetupRoutesWithMock(router, mockRepo, nil)
 
 	// Verify all expected routes exist by checking if they're handled
 	paths := []string{
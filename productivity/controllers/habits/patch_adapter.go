@@ -0,0 +1,151 @@
+package habits
+
+import (
+	"context"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/utils/patch"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// patchAdapter applies patches to habits
+type patchAdapter struct {
+	habitRepo repositories.HabitRepositoryInterface
+	authUser  *auth.UserAuthInfo
+}
+
+// NewPatchAdapter creates the patch adapter for habits.
+// The authenticated user is needed to enforce the habit limit of users without a subscription.
+func NewPatchAdapter(habitRepo repositories.HabitRepositoryInterface, authUser *auth.UserAuthInfo) patch.Adapter {
+	return &patchAdapter{habitRepo: habitRepo, authUser: authUser}
+}
+
+func (a *patchAdapter) Get(ctx context.Context, id primitive.ObjectID) (*patch.Item, error) {
+	habit, err := a.habitRepo.GetByID(ctx, id)
+	if err != nil || habit == nil {
+		return nil, err
+	}
+	item := &patch.Item{ID: id, Object: habit, Owner: habit.UserID}
+	if habit.UpdatedAt != nil {
+		item.UpdatedAt = patch.ParseTime(*habit.UpdatedAt)
+	}
+	return item, nil
+}
+
+func (a *patchAdapter) New() interface{} {
+	return &models.Habit{}
+}
+
+func (a *patchAdapter) Create(ctx context.Context, userID primitive.ObjectID, object interface{}) error {
+	habit := object.(*models.Habit)
+
+	userHabits, err := a.habitRepo.GetAll(ctx, &userID)
+	if err != nil {
+		return err
+	}
+
+	// Check if user has reached habit limit
+	if len(userHabits) >= 3 && a.authUser != nil && a.authUser.Claims != nil {
+		if a.authUser.Claims.UserID != nil && !*a.authUser.Claims.IsSubscribed {
+			return patch.NewError("habit_limit_reached")
+		}
+	}
+
+	habit.UserID = userID
+	if habit.ID.IsZero() {
+		habit.ID = primitive.NewObjectID()
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	habit.CreatedAt = &now
+	habit.UpdatedAt = &now
+
+	_, err = a.habitRepo.Create(ctx, habit)
+	return err
+}
+
+func (a *patchAdapter) Update(ctx context.Context, p *patchmodels.Patch, item *patch.Item) error {
+	habit := item.Object.(*models.Habit)
+	if err := patch.ApplyChanges(habit, p.Changes); err != nil {
+		return err
+	}
+
+	_, err := a.habitRepo.Update(ctx, habit)
+	return err
+}
+
+func (a *patchAdapter) Delete(ctx context.Context, item *patch.Item) error {
+	return a.habitRepo.Delete(ctx, item.ID)
+}
+
+// entryPatchAdapter applies patches to habit entries
+type entryPatchAdapter struct {
+	habitRepo repositories.HabitRepositoryInterface
+}
+
+// NewEntryPatchAdapter creates the patch adapter for habit entries
+func NewEntryPatchAdapter(habitRepo repositories.HabitRepositoryInterface) patch.Adapter {
+	return &entryPatchAdapter{habitRepo: habitRepo}
+}
+
+func (a *entryPatchAdapter) Get(ctx context.Context, id primitive.ObjectID) (*patch.Item, error) {
+	entry, err := a.habitRepo.GetEntryByID(ctx, id)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	return &patch.Item{ID: id, Object: entry, Owner: entry.UserID, UpdatedAt: patch.ParseTime(entry.UpdatedAt)}, nil
+}
+
+func (a *entryPatchAdapter) New() interface{} {
+	return &models.HabitEntry{}
+}
+
+func (a *entryPatchAdapter) Create(ctx context.Context, userID primitive.ObjectID, object interface{}) error {
+	entry := object.(*models.HabitEntry)
+	if err := a.checkHabit(ctx, entry.HabitID, userID); err != nil {
+		return err
+	}
+
+	entry.UserID = userID
+	_, err := a.habitRepo.AddEntry(ctx, entry)
+	return err
+}
+
+func (a *entryPatchAdapter) Update(ctx context.Context, p *patchmodels.Patch, item *patch.Item) error {
+	entry := item.Object.(*models.HabitEntry)
+	if err := patch.ApplyChanges(entry, p.Changes); err != nil {
+		return err
+	}
+
+	// the entry may have been moved to another habit
+	if err := a.checkHabit(ctx, entry.HabitID, item.Owner); err != nil {
+		return err
+	}
+
+	_, err := a.habitRepo.UpdateEntry(ctx, entry)
+	return err
+}
+
+func (a *entryPatchAdapter) Delete(ctx context.Context, item *patch.Item) error {
+	return a.habitRepo.DeleteEntry(ctx, item.ID)
+}
+
+// checkHabit checks that the habit of an entry exists and belongs to the user
+func (a *entryPatchAdapter) checkHabit(ctx context.Context, habitID primitive.ObjectID, userID primitive.ObjectID) error {
+	habit, err := a.habitRepo.GetByID(ctx, habitID)
+	if err != nil {
+		return err
+	}
+	if habit == nil {
+		return patch.NewError("habit_not_found")
+	}
+	if habit.UserID != userID {
+		return patch.NewError("not_authorized")
+	}
+	return nil
+}
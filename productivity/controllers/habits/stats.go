@@ -0,0 +1,131 @@
+package habits
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/habitstats"
+	"github.com/atomic-blend/backend/productivity/utils/timezone"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetHabitStats computes the statistics of a habit
+// @Summary Get habit statistics
+// @Description Get the current and longest streak, the completion rates per week and month and the progress in the current period of a habit. Days are computed in the timezone of the user's devices unless a timezone is given.
+// @Tags Habits
+// @Produce json
+// @Param id path string true "Habit ID"
+// @Param timezone query string false "IANA timezone overriding the timezone of the user's devices"
+// @Success 200 {object} models.HabitStats
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /habits/{id}/stats [get]
+func (c *HabitController) GetHabitStats(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	objID, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid habit ID format"})
+		return
+	}
+
+	loc, ok := c.statsLocation(ctx, authUser.UserID)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+		return
+	}
+
+	habit, err := c.habitRepo.GetByID(ctx, objID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if habit == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Habit not found"})
+		return
+	}
+	if habit.UserID != authUser.UserID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this habit"})
+		return
+	}
+
+	entries, err := c.habitRepo.GetEntriesByHabitID(ctx, habit.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load habit entries: " + err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, habitstats.Compute(habit, entries, time.Now(), loc))
+}
+
+// GetAllHabitStats computes the statistics of all the habits of the authenticated user
+// @Summary Get statistics of all habits
+// @Description Get the statistics of every habit of the authenticated user
+// @Tags Habits
+// @Produce json
+// @Param timezone query string false "IANA timezone overriding the timezone of the user's devices"
+// @Success 200 {array} models.HabitStats
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /habits/stats [get]
+func (c *HabitController) GetAllHabitStats(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	loc, ok := c.statsLocation(ctx, authUser.UserID)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+		return
+	}
+
+	habits, err := c.habitRepo.GetAll(ctx, &authUser.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	stats := make([]*models.HabitStats, 0, len(habits))
+	for _, habit := range habits {
+		entries, err := c.habitRepo.GetEntriesByHabitID(ctx, habit.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load habit entries: " + err.Error()})
+			return
+		}
+		stats = append(stats, habitstats.Compute(habit, entries, now, loc))
+	}
+
+	ctx.JSON(http.StatusOK, stats)
+}
+
+// statsLocation returns the timezone the statistics are computed in: the timezone query parameter if set,
+// otherwise the timezone of the first device of the user reporting one, otherwise UTC.
+// It returns false when the timezone query parameter is not a valid timezone.
+func (c *HabitController) statsLocation(ctx *gin.Context, userID primitive.ObjectID) (*time.Location, bool) {
+	if name := ctx.Query("timezone"); name != "" {
+		loc, err := time.LoadLocation(name)
+		return loc, err == nil
+	}
+	return c.deviceLocation(ctx, userID), true
+}
+
+// deviceLocation returns the timezone reported by the devices of the user, or UTC if none is known
+func (c *HabitController) deviceLocation(ctx context.Context, userID primitive.ObjectID) *time.Location {
+	return timezone.OfUser(ctx, c.userClient, userID.Hex())
+}
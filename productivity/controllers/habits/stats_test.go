@@ -0,0 +1,215 @@
+package habits
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	userv1 "github.com/atomic-blend/backend/grpc/gen/user/v1"
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func statsContext(w *httptest.ResponseRecorder, url string, userID *primitive.ObjectID, habitID string) *gin.Context {
+	req, _ := http.NewRequest("GET", url, nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	if userID != nil {
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: *userID})
+	}
+	if habitID != "" {
+		ctx.Params = []gin.Param{{Key: "id", Value: habitID}}
+	}
+	return ctx
+}
+
+func devicesResponse(timezones ...string) *connect.Response[userv1.GetUserDevicesResponse] {
+	devices := []*userv1.UserDevice{}
+	for _, timezone := range timezones {
+		tz := timezone
+		devices = append(devices, &userv1.UserDevice{DeviceTimezone: &tz})
+	}
+	return connect.NewResponse(&userv1.GetUserDevicesResponse{Devices: devices})
+}
+
+func TestGetHabitStats(t *testing.T) {
+	t.Run("stats in the device timezone", func(t *testing.T) {
+		mockRepo := new(mocks.MockHabitRepository)
+		mockUserClient := new(mocks.MockUserClient)
+		controller := NewHabitController(mockRepo, mockUserClient)
+
+		userID := primitive.NewObjectID()
+		habit := createTestHabit()
+		habit.UserID = userID
+		startDate := primitive.NewDateTimeFromTime(time.Now().AddDate(0, 0, -3))
+		habit.StartDate = &startDate
+		habit.DaysOfWeek = nil
+		habit.EndDate = nil
+		times := 1
+		habit.NumberOfTimes = &times
+
+		entries := []models.HabitEntry{
+			{HabitID: habit.ID, UserID: userID, EntryDate: primitive.NewDateTimeFromTime(time.Now().AddDate(0, 0, -1))},
+			{HabitID: habit.ID, UserID: userID, EntryDate: primitive.NewDateTimeFromTime(time.Now())},
+		}
+
+		mockRepo.On("GetByID", mock.Anything, habit.ID).Return(habit, nil)
+		mockRepo.On("GetEntriesByHabitID", mock.Anything, habit.ID).Return(entries, nil)
+		mockUserClient.On("GetUserDevices", mock.Anything, mock.Anything).Return(devicesResponse("", "Europe/Paris"), nil)
+
+		w := httptest.NewRecorder()
+		controller.GetHabitStats(statsContext(w, "/habits/"+habit.ID.Hex()+"/stats", &userID, habit.ID.Hex()))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var stats models.HabitStats
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		assert.Equal(t, habit.ID, stats.HabitID)
+		assert.Equal(t, "Europe/Paris", stats.Timezone)
+		assert.Equal(t, 2, stats.TotalEntries)
+		assert.GreaterOrEqual(t, stats.CurrentStreak, 1)
+		require.NotNil(t, stats.CurrentPeriod)
+		mockUserClient.AssertExpectations(t)
+	})
+
+	t.Run("timezone query parameter", func(t *testing.T) {
+		mockRepo := new(mocks.MockHabitRepository)
+		mockUserClient := new(mocks.MockUserClient)
+		controller := NewHabitController(mockRepo, mockUserClient)
+
+		userID := primitive.NewObjectID()
+		habit := createTestHabit()
+		habit.UserID = userID
+
+		mockRepo.On("GetByID", mock.Anything, habit.ID).Return(habit, nil)
+		mockRepo.On("GetEntriesByHabitID", mock.Anything, habit.ID).Return([]models.HabitEntry{}, nil)
+
+		w := httptest.NewRecorder()
+		controller.GetHabitStats(statsContext(w, "/habits/"+habit.ID.Hex()+"/stats?timezone=America/New_York", &userID, habit.ID.Hex()))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var stats models.HabitStats
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		assert.Equal(t, "America/New_York", stats.Timezone)
+		mockUserClient.AssertNotCalled(t, "GetUserDevices", mock.Anything, mock.Anything)
+	})
+
+	t.Run("falls back to UTC when devices are unavailable", func(t *testing.T) {
+		mockRepo := new(mocks.MockHabitRepository)
+		mockUserClient := new(mocks.MockUserClient)
+		controller := NewHabitController(mockRepo, mockUserClient)
+
+		userID := primitive.NewObjectID()
+		habit := createTestHabit()
+		habit.UserID = userID
+
+		mockRepo.On("GetByID", mock.Anything, habit.ID).Return(habit, nil)
+		mockRepo.On("GetEntriesByHabitID", mock.Anything, habit.ID).Return([]models.HabitEntry{}, nil)
+		mockUserClient.On("GetUserDevices", mock.Anything, mock.Anything).Return(nil, errors.New("unavailable"))
+
+		w := httptest.NewRecorder()
+		controller.GetHabitStats(statsContext(w, "/habits/"+habit.ID.Hex()+"/stats", &userID, habit.ID.Hex()))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var stats models.HabitStats
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		assert.Equal(t, "UTC", stats.Timezone)
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		controller := NewHabitController(new(mocks.MockHabitRepository), nil)
+		userID := primitive.NewObjectID()
+		habitID := primitive.NewObjectID().Hex()
+
+		w := httptest.NewRecorder()
+		controller.GetHabitStats(statsContext(w, "/habits/"+habitID+"/stats?timezone=Mars/Olympus", &userID, habitID))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("habit of another user", func(t *testing.T) {
+		mockRepo := new(mocks.MockHabitRepository)
+		controller := NewHabitController(mockRepo, nil)
+
+		userID := primitive.NewObjectID()
+		habit := createTestHabit()
+		mockRepo.On("GetByID", mock.Anything, habit.ID).Return(habit, nil)
+
+		w := httptest.NewRecorder()
+		controller.GetHabitStats(statsContext(w, "/habits/"+habit.ID.Hex()+"/stats", &userID, habit.ID.Hex()))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("habit not found", func(t *testing.T) {
+		mockRepo := new(mocks.MockHabitRepository)
+		controller := NewHabitController(mockRepo, nil)
+
+		userID := primitive.NewObjectID()
+		habitID := primitive.NewObjectID()
+		mockRepo.On("GetByID", mock.Anything, habitID).Return(nil, nil)
+
+		w := httptest.NewRecorder()
+		controller.GetHabitStats(statsContext(w, "/habits/"+habitID.Hex()+"/stats", &userID, habitID.Hex()))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		controller := NewHabitController(new(mocks.MockHabitRepository), nil)
+
+		w := httptest.NewRecorder()
+		controller.GetHabitStats(statsContext(w, "/habits/123/stats", nil, "123"))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestGetAllHabitStats(t *testing.T) {
+	t.Run("stats of every habit", func(t *testing.T) {
+		mockRepo := new(mocks.MockHabitRepository)
+		controller := NewHabitController(mockRepo, nil)
+
+		userID := primitive.NewObjectID()
+		first := createTestHabit()
+		second := createTestHabit()
+
+		mockRepo.On("GetAll", mock.Anything, &userID).Return([]*models.Habit{first, second}, nil)
+		mockRepo.On("GetEntriesByHabitID", mock.Anything, mock.Anything).Return([]models.HabitEntry{}, nil)
+
+		w := httptest.NewRecorder()
+		controller.GetAllHabitStats(statsContext(w, "/habits/stats", &userID, ""))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var stats []models.HabitStats
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		require.Len(t, stats, 2)
+		assert.Equal(t, first.ID, stats[0].HabitID)
+		assert.Equal(t, second.ID, stats[1].HabitID)
+		assert.Equal(t, "UTC", stats[0].Timezone)
+	})
+
+	t.Run("no habits", func(t *testing.T) {
+		mockRepo := new(mocks.MockHabitRepository)
+		controller := NewHabitController(mockRepo, nil)
+
+		userID := primitive.NewObjectID()
+		mockRepo.On("GetAll", mock.Anything, &userID).Return(nil, nil)
+
+		w := httptest.NewRecorder()
+		controller.GetAllHabitStats(statsContext(w, "/habits/stats", &userID, ""))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, "[]", w.Body.String())
+	})
+}
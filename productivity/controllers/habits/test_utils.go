@@ -59,7 +59,7 @@ func setupTest() (*gin.Engine, *mocks.MockHabitRepository) {
 
 	router := gin.New()
 	mockRepo := new(mocks.MockHabitRepository)
-	habitController := NewHabitController(mockRepo)
+	habitController := NewHabitController(mockRepo, nil)
 
 	// Set up routes with middleware
 	habitRoutes := router.Group("/habits")
@@ -52,7 +52,7 @@ func TestUpdateHabit(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: habitID.Hex()}}
 
 		// Call the handler directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.UpdateHabit(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -99,7 +99,7 @@ func TestUpdateHabit(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: nonExistentID.Hex()}}
 
 		// Call the handler directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.UpdateHabit(ctx)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
@@ -132,7 +132,7 @@ func TestUpdateHabit(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: habitID.Hex()}}
 
 		// Call the handler directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.UpdateHabit(ctx)
 
 		assert.Equal(t, http.StatusForbidden, w.Code)
@@ -159,7 +159,7 @@ func TestUpdateHabit(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: habitID.Hex()}}
 
 		// Call the handler directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.UpdateHabit(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -182,7 +182,7 @@ func TestUpdateHabit(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: invalidID}}
 
 		// Call the handler directly
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.UpdateHabit(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -214,7 +214,7 @@ func TestUpdateHabit(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 		ctx.Params = []gin.Param{{Key: "id", Value: habitID.Hex()}}
 
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.UpdateHabit(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -250,7 +250,7 @@ func TestUpdateHabit(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 		ctx.Params = []gin.Param{{Key: "id", Value: habitID.Hex()}}
 
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.UpdateHabit(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -286,7 +286,7 @@ func TestUpdateHabit(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 		ctx.Params = []gin.Param{{Key: "id", Value: habitID.Hex()}}
 
-		controller := NewHabitController(mockRepo)
+		controller := NewHabitController(mockRepo, nil)
 		controller.UpdateHabit(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -0,0 +1,50 @@
+package imports
+
+import (
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Controller handles the import of tasks from the files of other tools
+type Controller struct {
+	taskRepo   repositories.TaskRepositoryInterface
+	tagRepo    repositories.TagRepositoryInterface
+	folderRepo repositories.FolderRepositoryInterface
+}
+
+// NewImportController creates a new import controller instance
+func NewImportController(taskRepo repositories.TaskRepositoryInterface, tagRepo repositories.TagRepositoryInterface, folderRepo repositories.FolderRepositoryInterface) *Controller {
+	return &Controller{
+		taskRepo:   taskRepo,
+		tagRepo:    tagRepo,
+		folderRepo: folderRepo,
+	}
+}
+
+// SetupRoutes sets up the routes for the import controller
+func SetupRoutes(router *gin.Engine, database *mongo.Database) {
+	importController := NewImportController(
+		repositories.NewTaskRepository(database),
+		repositories.NewTagRepository(database),
+		repositories.NewFolderRepository(database),
+	)
+	setupImportRoutes(router, importController)
+}
+
+// SetupRoutesWithMock sets up the import routes with mock repositories for testing
+func SetupRoutesWithMock(router *gin.Engine, taskRepo repositories.TaskRepositoryInterface, tagRepo repositories.TagRepositoryInterface, folderRepo repositories.FolderRepositoryInterface) {
+	importController := NewImportController(taskRepo, tagRepo, folderRepo)
+	setupImportRoutes(router, importController)
+}
+
+// setupImportRoutes sets up the routes for import controller
+func setupImportRoutes(router *gin.Engine, importController *Controller) {
+	importRoutes := router.Group("/imports")
+	auth.RequireAuth(importRoutes)
+	{
+		importRoutes.POST("/tasks", importController.ImportTasks)
+	}
+}
@@ -0,0 +1,179 @@
+package imports
+
+import (
+	"context"
+	"strings"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/taskimport"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// importer creates the tasks of an import, along with the tags and folders they reference by name.
+// Tags and folders are matched by name regardless of case. During a dry run nothing is created, the
+// tags and folders which would be created are only listed.
+type importer struct {
+	controller *Controller
+	userID     primitive.ObjectID
+	dryRun     bool
+	// tags are the tags of the user by lower-cased name
+	tags map[string]*models.Tag
+	// folders are the folders of the user by lower-cased path
+	folders    map[string]*models.Folder
+	newTags    []string
+	newFolders []string
+}
+
+func (c *Controller) newImporter(ctx context.Context, userID primitive.ObjectID, dryRun bool) (*importer, error) {
+	imp := &importer{
+		controller: c,
+		userID:     userID,
+		dryRun:     dryRun,
+		tags:       map[string]*models.Tag{},
+		folders:    map[string]*models.Folder{},
+		newTags:    []string{},
+		newFolders: []string{},
+	}
+
+	tags, err := c.tagRepo.GetAll(ctx, &userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		if _, ok := imp.tags[strings.ToLower(tag.Name)]; !ok {
+			imp.tags[strings.ToLower(tag.Name)] = tag
+		}
+	}
+
+	folders, err := c.folderRepo.GetAll(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[primitive.ObjectID]*models.Folder{}
+	for _, folder := range folders {
+		if folder.ID != nil {
+			byID[*folder.ID] = folder
+		}
+	}
+	for _, folder := range folders {
+		if path := folderPath(folder, byID); path != "" {
+			if _, ok := imp.folders[path]; !ok {
+				imp.folders[path] = folder
+			}
+		}
+	}
+
+	return imp, nil
+}
+
+// importRow resolves the tags and the folder of a row and creates its task
+func (imp *importer) importRow(ctx context.Context, row *models.TaskImportRow) error {
+	task := row.Task
+	task.User = imp.userID
+
+	task.TagIDs = []primitive.ObjectID{}
+	for _, name := range row.Tags {
+		tag, err := imp.tag(ctx, name)
+		if err != nil {
+			return err
+		}
+		if tag.ID != nil {
+			task.TagIDs = append(task.TagIDs, *tag.ID)
+		}
+	}
+
+	if row.Folder != "" {
+		folder, err := imp.folder(ctx, row.Folder)
+		if err != nil {
+			return err
+		}
+		task.FolderID = folder.ID
+	}
+
+	if imp.dryRun {
+		return nil
+	}
+
+	created, err := imp.controller.taskRepo.Create(ctx, task)
+	if err != nil {
+		return err
+	}
+	row.Task = created
+	return nil
+}
+
+// tag returns the tag of the user with the given name, creating it when the user has none
+func (imp *importer) tag(ctx context.Context, name string) (*models.Tag, error) {
+	key := strings.ToLower(name)
+	if tag, ok := imp.tags[key]; ok {
+		return tag, nil
+	}
+
+	userID := imp.userID
+	tag := &models.Tag{Name: name, UserID: &userID}
+	if !imp.dryRun {
+		created, err := imp.controller.tagRepo.Create(ctx, tag)
+		if err != nil {
+			return nil, err
+		}
+		tag = created
+	}
+	imp.tags[key] = tag
+	imp.newTags = append(imp.newTags, name)
+	return tag, nil
+}
+
+// folder returns the folder of the user at the given path, creating it and its missing parents
+func (imp *importer) folder(ctx context.Context, path string) (*models.Folder, error) {
+	var parent *models.Folder
+	names := taskimport.FolderPath(path)
+	for i, name := range names {
+		current := strings.Join(names[:i+1], "/")
+		key := strings.ToLower(current)
+		if folder, ok := imp.folders[key]; ok {
+			parent = folder
+			continue
+		}
+
+		folder := &models.Folder{Name: name, UserID: imp.userID}
+		if parent != nil {
+			folder.ParentID = parent.ID
+		}
+		if !imp.dryRun {
+			created, err := imp.controller.folderRepo.Create(ctx, folder)
+			if err != nil {
+				return nil, err
+			}
+			folder = created
+		}
+		imp.folders[key] = folder
+		imp.newFolders = append(imp.newFolders, current)
+		parent = folder
+	}
+	return parent, nil
+}
+
+// folderPath returns the lower-cased path of a folder, empty when its parents cannot be followed to the root
+func folderPath(folder *models.Folder, byID map[primitive.ObjectID]*models.Folder) string {
+	names := []string{}
+	seen := map[primitive.ObjectID]bool{}
+	for current := folder; current != nil; {
+		if current.ID != nil {
+			if seen[*current.ID] {
+				return ""
+			}
+			seen[*current.ID] = true
+		}
+		names = append([]string{strings.ToLower(strings.TrimSpace(current.Name))}, names...)
+		if current.ParentID == nil {
+			break
+		}
+		parent, ok := byID[*current.ParentID]
+		if !ok {
+			return ""
+		}
+		current = parent
+	}
+	return strings.Join(names, "/")
+}
@@ -0,0 +1,193 @@
+package imports
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const csvFile = "title,tags,folder\n" +
+	"Write report,work;Reports,Work/Clients\n" +
+	",work,\n" +
+	"Call the bank,,\n"
+
+func setupTest() (*Controller, *mocks.MockTaskRepository, *mocks.MockTagRepository, *mocks.MockFolderRepository) {
+	gin.SetMode(gin.TestMode)
+	taskRepo := new(mocks.MockTaskRepository)
+	tagRepo := new(mocks.MockTagRepository)
+	folderRepo := new(mocks.MockFolderRepository)
+	return NewImportController(taskRepo, tagRepo, folderRepo), taskRepo, tagRepo, folderRepo
+}
+
+func newContext(path, contentType string, body []byte, userID *primitive.ObjectID) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", contentType)
+	if userID != nil {
+		c.Set("authUser", &auth.UserAuthInfo{UserID: *userID})
+	}
+	return c, w
+}
+
+func multipartFile(t *testing.T, filename, content string) (string, []byte) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return writer.FormDataContentType(), body.Bytes()
+}
+
+// existingData returns a "Work" tag and a "Work" folder the user already has
+func existingData(userID primitive.ObjectID) ([]*models.Tag, []*models.Folder) {
+	tagID := primitive.NewObjectID()
+	folderID := primitive.NewObjectID()
+	return []*models.Tag{{ID: &tagID, UserID: &userID, Name: "Work"}},
+		[]*models.Folder{{ID: &folderID, UserID: userID, Name: "Work"}}
+}
+
+func TestImportTasks(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	t.Run("Dry Run", func(t *testing.T) {
+		controller, taskRepo, tagRepo, folderRepo := setupTest()
+		tags, folders := existingData(userID)
+		tagRepo.On("GetAll", mock.Anything, &userID).Return(tags, nil)
+		folderRepo.On("GetAll", mock.Anything, userID).Return(folders, nil)
+
+		contentType, body := multipartFile(t, "export.CSV", csvFile)
+		c, w := newContext("/imports/tasks?dryRun=true", contentType, body, &userID)
+		controller.ImportTasks(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var result models.TaskImportResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.True(t, result.DryRun)
+		require.Len(t, result.Tasks, 2)
+		assert.Equal(t, []primitive.ObjectID{*tags[0].ID}, result.Tasks[0].Task.TagIDs)
+		assert.Equal(t, []string{"Reports"}, result.NewTags)
+		assert.Equal(t, []string{"Work/Clients"}, result.NewFolders)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, 3, result.Errors[0].Row)
+
+		taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		tagRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		folderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		controller, taskRepo, tagRepo, folderRepo := setupTest()
+		tags, folders := existingData(userID)
+		tagRepo.On("GetAll", mock.Anything, &userID).Return(tags, nil)
+		folderRepo.On("GetAll", mock.Anything, userID).Return(folders, nil)
+
+		newTagID := primitive.NewObjectID()
+		tagRepo.On("Create", mock.Anything, mock.MatchedBy(func(tag *models.Tag) bool { return tag.Name == "Reports" })).
+			Return(&models.Tag{ID: &newTagID, UserID: &userID, Name: "Reports"}, nil).Once()
+
+		var createdFolder *models.Folder
+		newFolderID := primitive.NewObjectID()
+		folderRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Folder")).
+			Run(func(args mock.Arguments) { createdFolder = args.Get(1).(*models.Folder) }).
+			Return(&models.Folder{ID: &newFolderID, UserID: userID, Name: "Clients"}, nil).Once()
+
+		var created []*models.TaskEntity
+		taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.TaskEntity")).
+			Run(func(args mock.Arguments) { created = append(created, args.Get(1).(*models.TaskEntity)) }).
+			Return(&models.TaskEntity{ID: primitive.NewObjectID().Hex(), Title: "created"}, nil).Once()
+		taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.TaskEntity")).
+			Return(nil, errors.New("database error")).Once()
+
+		c, w := newContext("/imports/tasks?format=csv", "text/plain", []byte(csvFile), &userID)
+		controller.ImportTasks(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		require.NotNil(t, createdFolder)
+		assert.Equal(t, "Clients", createdFolder.Name)
+		assert.Equal(t, folders[0].ID, createdFolder.ParentID)
+
+		require.Len(t, created, 1)
+		assert.Equal(t, userID, created[0].User)
+		assert.Equal(t, []primitive.ObjectID{*tags[0].ID, newTagID}, created[0].TagIDs)
+		assert.Equal(t, &newFolderID, created[0].FolderID)
+
+		var result models.TaskImportResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.False(t, result.DryRun)
+		assert.Len(t, result.Tasks, 1)
+		require.Len(t, result.Errors, 2)
+		assert.Equal(t, models.TaskImportError{Row: 4, Error: "database error"}, *result.Errors[1])
+	})
+
+	t.Run("iCalendar Body", func(t *testing.T) {
+		controller, taskRepo, tagRepo, folderRepo := setupTest()
+		tagRepo.On("GetAll", mock.Anything, &userID).Return([]*models.Tag{}, nil)
+		folderRepo.On("GetAll", mock.Anything, userID).Return([]*models.Folder{}, nil)
+		taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.TaskEntity")).
+			Return(&models.TaskEntity{ID: primitive.NewObjectID().Hex(), Title: "Report"}, nil).Once()
+
+		ics := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:Report\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+		c, w := newContext("/imports/tasks", "text/calendar; charset=utf-8", []byte(ics), &userID)
+		controller.ImportTasks(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		taskRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Format", func(t *testing.T) {
+		controller, _, tagRepo, _ := setupTest()
+
+		contentType, body := multipartFile(t, "tasks.json", "[]")
+		c, w := newContext("/imports/tasks", contentType, body, &userID)
+		controller.ImportTasks(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		tagRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid File", func(t *testing.T) {
+		controller, _, _, _ := setupTest()
+
+		c, w := newContext("/imports/tasks", "text/csv", []byte("name\nTask\n"), &userID)
+		controller.ImportTasks(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "unknown column")
+	})
+
+	t.Run("File Too Large", func(t *testing.T) {
+		controller, _, _, _ := setupTest()
+
+		c, w := newContext("/imports/tasks", "text/csv", []byte(strings.Repeat("a", maxImportSize+1)), &userID)
+		controller.ImportTasks(c)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		controller, _, _, _ := setupTest()
+
+		c, w := newContext("/imports/tasks", "text/csv", []byte(csvFile), nil)
+		controller.ImportTasks(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
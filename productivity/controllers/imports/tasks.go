@@ -0,0 +1,160 @@
+package imports
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/taskimport"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize is the maximum size of an imported file
+const maxImportSize = 5 << 20
+
+// ImportTasks imports tasks from an iCalendar or a CSV file
+// @Summary Import tasks
+// @Description Import the VTODOs of an iCalendar file, or the rows of a CSV file, as tasks. Missing tags and folders are created.
+// @Description The file is sent as the "file" field of a multipart form, or as the body of the request. Its format is given by the format parameter, or else by the extension of the file or the content type.
+// @Description The CSV file starts with a header naming its columns: title (required), description, start_date, end_date, reminders, completed, priority (0 to 3), tags, folder (path such as Work/Clients) and recurrence (RRULE). Dates are RFC 3339 date-times or dates, lists are separated by semicolons.
+// @Description Rows which cannot be read are reported in errors and skipped, the other rows are imported. A dry run reports the result without importing anything.
+// @Tags Imports
+// @Accept multipart/form-data,text/calendar,text/csv
+// @Produce json
+// @Param file formData file false "File to import"
+// @Param format query string false "Format of the file" Enums(ics, csv)
+// @Param dryRun query bool false "Preview the import without saving anything"
+// @Success 200 {object} models.TaskImportResult "Dry run"
+// @Success 201 {object} models.TaskImportResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /imports/tasks [post]
+func (c *Controller) ImportTasks(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	dryRun := false
+	if value := ctx.Query("dryRun"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid dryRun parameter"})
+			return
+		}
+		dryRun = parsed
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize)
+	content, format, err := readImportFile(ctx)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "The file is too large"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var rows []*models.TaskImportRow
+	var rowErrors []*models.TaskImportError
+	switch format {
+	case models.TaskImportFormatICS:
+		rows, rowErrors, err = taskimport.ParseICS(bytes.NewReader(content))
+	case models.TaskImportFormatCSV:
+		rows, rowErrors, err = taskimport.ParseCSV(bytes.NewReader(content))
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file: " + err.Error()})
+		return
+	}
+
+	imp, err := c.newImporter(ctx, authUser.UserID, dryRun)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := &models.TaskImportResult{
+		DryRun: dryRun,
+		Tasks:  []*models.TaskImportRow{},
+		Errors: rowErrors,
+	}
+	for _, row := range rows {
+		if err := imp.importRow(ctx, row); err != nil {
+			result.Errors = append(result.Errors, &models.TaskImportError{Row: row.Row, Error: err.Error()})
+			continue
+		}
+		result.Tasks = append(result.Tasks, row)
+	}
+	result.NewTags = imp.newTags
+	result.NewFolders = imp.newFolders
+
+	status := http.StatusCreated
+	if dryRun {
+		status = http.StatusOK
+	}
+	ctx.JSON(status, result)
+}
+
+// readImportFile returns the content of the imported file and its format
+func readImportFile(ctx *gin.Context) ([]byte, string, error) {
+	format := strings.ToLower(ctx.Query("format"))
+	mediaType, _, _ := mime.ParseMediaType(ctx.GetHeader("Content-Type"))
+
+	var content []byte
+	if mediaType == "multipart/form-data" {
+		header, err := ctx.FormFile("file")
+		if err != nil {
+			return nil, "", err
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		defer file.Close()
+		if content, err = io.ReadAll(file); err != nil {
+			return nil, "", err
+		}
+
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+		}
+		if format == "" {
+			mediaType, _, _ = mime.ParseMediaType(header.Header.Get("Content-Type"))
+		}
+	} else {
+		var err error
+		if content, err = io.ReadAll(ctx.Request.Body); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if format == "" {
+		switch mediaType {
+		case "text/calendar":
+			format = models.TaskImportFormatICS
+		case "text/csv":
+			format = models.TaskImportFormatCSV
+		}
+	}
+	if format == "ical" || format == "ifb" {
+		format = models.TaskImportFormatICS
+	}
+	if format != models.TaskImportFormatICS && format != models.TaskImportFormatCSV {
+		return nil, "", errors.New("unsupported format, expected ics or csv")
+	}
+	return content, format, nil
+}
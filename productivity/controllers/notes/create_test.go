@@ -16,7 +16,7 @@ import (
 )
 
 func TestCreateNote(t *testing.T) {
-	_, mockNoteRepo := setupTest()
+	mockTaskRepo, mockNoteRepo := setupTest()
 
 	t.Run("successful create note", func(t *testing.T) {
 		// Create authenticated user
@@ -30,7 +30,7 @@ func TestCreateNote(t *testing.T) {
 		mockNoteRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.NoteEntity")).Return(note, nil).Once()
 
 		// Create controller
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		// Setup request
 		gin.SetMode(gin.TestMode)
@@ -67,7 +67,7 @@ func TestCreateNote(t *testing.T) {
 	})
 
 	t.Run("create note without authentication", func(t *testing.T) {
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -95,7 +95,7 @@ func TestCreateNote(t *testing.T) {
 
 	t.Run("create note with invalid JSON", func(t *testing.T) {
 		userID := primitive.NewObjectID()
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -126,7 +126,7 @@ func TestCreateNote(t *testing.T) {
 		// Mock repository error
 		mockNoteRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.NoteEntity")).Return(nil, assert.AnError).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -14,7 +14,7 @@ import (
 )
 
 func TestDeleteNote(t *testing.T) {
-	_, mockNoteRepo := setupTest()
+	mockTaskRepo, mockNoteRepo := setupTest()
 
 	t.Run("successful delete note", func(t *testing.T) {
 		userID := primitive.NewObjectID()
@@ -26,7 +26,7 @@ func TestDeleteNote(t *testing.T) {
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(existingNote, nil).Once()
 		mockNoteRepo.On("Delete", mock.Anything, noteID).Return(nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -52,7 +52,7 @@ func TestDeleteNote(t *testing.T) {
 	})
 
 	t.Run("delete note without authentication", func(t *testing.T) {
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -77,7 +77,7 @@ func TestDeleteNote(t *testing.T) {
 		// Mock repository response - note not found
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(nil, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -112,7 +112,7 @@ func TestDeleteNote(t *testing.T) {
 		// Mock repository response
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(existingNote, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -144,7 +144,7 @@ func TestDeleteNote(t *testing.T) {
 		// Mock repository error
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(nil, assert.AnError).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -179,7 +179,7 @@ func TestDeleteNote(t *testing.T) {
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(existingNote, nil).Once()
 		mockNoteRepo.On("Delete", mock.Anything, noteID).Return(assert.AnError).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -206,7 +206,7 @@ func TestDeleteNote(t *testing.T) {
 
 	t.Run("delete note missing ID parameter", func(t *testing.T) {
 		userID := primitive.NewObjectID()
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -16,7 +16,7 @@ import (
 )
 
 func TestGetAllNotes(t *testing.T) {
-	_, mockNoteRepo := setupTest()
+	mockTaskRepo, mockNoteRepo := setupTest()
 
 	t.Run("successful get all notes", func(t *testing.T) {
 		userID := primitive.NewObjectID()
@@ -29,7 +29,7 @@ func TestGetAllNotes(t *testing.T) {
 		// Mock repository response
 		mockNoteRepo.On("GetAll", mock.Anything, &userID, mock.AnythingOfType("*int64"), mock.AnythingOfType("*int64")).Return(notes, totalCount, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -56,7 +56,7 @@ func TestGetAllNotes(t *testing.T) {
 	})
 
 	t.Run("get all notes without authentication", func(t *testing.T) {
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -80,7 +80,7 @@ func TestGetAllNotes(t *testing.T) {
 		// Mock repository error
 		mockNoteRepo.On("GetAll", mock.Anything, &userID, mock.AnythingOfType("*int64"), mock.AnythingOfType("*int64")).Return(nil, int64(0), assert.AnError).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -113,7 +113,7 @@ func TestGetAllNotes(t *testing.T) {
 		// Mock repository response with empty result
 		mockNoteRepo.On("GetAll", mock.Anything, &userID, mock.AnythingOfType("*int64"), mock.AnythingOfType("*int64")).Return(emptyNotes, totalCount, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -150,7 +150,7 @@ func TestGetAllNotes(t *testing.T) {
 		// Mock repository response with pagination
 		mockNoteRepo.On("GetAll", mock.Anything, &userID, mock.AnythingOfType("*int64"), mock.AnythingOfType("*int64")).Return(notes, totalCount, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -182,7 +182,7 @@ func TestGetAllNotes(t *testing.T) {
 	t.Run("get all notes with invalid pagination parameters", func(t *testing.T) {
 		userID := primitive.NewObjectID()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -15,7 +15,7 @@ import (
 )
 
 func TestGetNoteByID(t *testing.T) {
-	_, mockNoteRepo := setupTest()
+	mockTaskRepo, mockNoteRepo := setupTest()
 
 	t.Run("successful get note by ID", func(t *testing.T) {
 		userID := primitive.NewObjectID()
@@ -26,7 +26,7 @@ func TestGetNoteByID(t *testing.T) {
 		// Mock repository response
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(note, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -54,7 +54,7 @@ func TestGetNoteByID(t *testing.T) {
 	})
 
 	t.Run("get note by ID without authentication", func(t *testing.T) {
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -79,7 +79,7 @@ func TestGetNoteByID(t *testing.T) {
 		// Mock repository response - note not found
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(nil, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -114,7 +114,7 @@ func TestGetNoteByID(t *testing.T) {
 		// Mock repository response
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(note, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -146,7 +146,7 @@ func TestGetNoteByID(t *testing.T) {
 		// Mock repository error
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(nil, assert.AnError).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -173,7 +173,7 @@ func TestGetNoteByID(t *testing.T) {
 
 	t.Run("get note by ID missing ID parameter", func(t *testing.T) {
 		userID := primitive.NewObjectID()
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
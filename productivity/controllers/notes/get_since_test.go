@@ -16,7 +16,7 @@ import (
 )
 
 func TestGetNotesSince(t *testing.T) {
-	_, mockNoteRepo := setupTest()
+	mockTaskRepo, mockNoteRepo := setupTest()
 
 	t.Run("successful get notes since", func(t *testing.T) {
 		userID := primitive.NewObjectID()
@@ -29,7 +29,7 @@ func TestGetNotesSince(t *testing.T) {
 		// Mock repository response
 		mockNoteRepo.On("GetSince", mock.Anything, userID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("*int64"), mock.AnythingOfType("*int64")).Return(notes, totalCount, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -61,7 +61,7 @@ func TestGetNotesSince(t *testing.T) {
 	t.Run("missing since parameter", func(t *testing.T) {
 		userID := primitive.NewObjectID()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -87,7 +87,7 @@ func TestGetNotesSince(t *testing.T) {
 	t.Run("invalid date format", func(t *testing.T) {
 		userID := primitive.NewObjectID()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -111,7 +111,7 @@ func TestGetNotesSince(t *testing.T) {
 	})
 
 	t.Run("unauthorized request", func(t *testing.T) {
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -135,7 +135,7 @@ func TestGetNotesSince(t *testing.T) {
 		// Mock repository error
 		mockNoteRepo.On("GetSince", mock.Anything, userID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("*int64"), mock.AnythingOfType("*int64")).Return(nil, int64(0), assert.AnError).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -170,7 +170,7 @@ func TestGetNotesSince(t *testing.T) {
 		// Mock repository response
 		mockNoteRepo.On("GetSince", mock.Anything, userID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("*int64"), mock.AnythingOfType("*int64")).Return(notes, totalCount, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -207,7 +207,7 @@ func TestGetNotesSince(t *testing.T) {
 		// Mock repository response - when no pagination, page and limit are nil
 		mockNoteRepo.On("GetSince", mock.Anything, userID, mock.AnythingOfType("time.Time"), (*int64)(nil), (*int64)(nil)).Return(notes, totalCount, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -0,0 +1,184 @@
+package notes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxConvertedTitleLength is the length the title of a task converted from a note without title is cut to
+const maxConvertedTitleLength = 100
+
+// LinkTask links a task to a note
+// @Summary Link task to note
+// @Description Link a task to a note. The note lists the task in its taskIds and the task lists the note in its noteIds.
+// @Tags Notes
+// @Produce json
+// @Param id path string true "Note ID"
+// @Param taskId path string true "Task ID"
+// @Success 200 {object} models.NoteEntity
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notes/{id}/tasks/{taskId} [put]
+func (c *NoteController) LinkTask(ctx *gin.Context) {
+	c.updateTaskLink(ctx, true)
+}
+
+// UnlinkTask removes the link between a task and a note
+// @Summary Unlink task from note
+// @Description Remove the link between a task and a note
+// @Tags Notes
+// @Produce json
+// @Param id path string true "Note ID"
+// @Param taskId path string true "Task ID"
+// @Success 200 {object} models.NoteEntity
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notes/{id}/tasks/{taskId} [delete]
+func (c *NoteController) UnlinkTask(ctx *gin.Context) {
+	c.updateTaskLink(ctx, false)
+}
+
+func (c *NoteController) updateTaskLink(ctx *gin.Context, link bool) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	taskID, err := primitive.ObjectIDFromHex(ctx.Param("taskId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	note := c.getOwnedNote(ctx, authUser.UserID)
+	if note == nil {
+		return
+	}
+
+	task, err := c.taskRepo.GetByID(ctx, taskID.Hex())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if task == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+	if task.User != authUser.UserID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this task"})
+		return
+	}
+
+	if link {
+		err = c.taskRepo.LinkNote(ctx, taskID, *note.ID)
+	} else {
+		err = c.taskRepo.UnlinkNote(ctx, taskID, *note.ID)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	updatedNote, err := c.noteRepo.GetByID(ctx, note.ID.Hex())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updatedNote)
+}
+
+// ConvertToTask creates a task from a note
+// @Summary Convert note to task
+// @Description Create a task from the title and the content of a note. The note is kept and linked to the new task.
+// @Tags Notes
+// @Produce json
+// @Param id path string true "Note ID"
+// @Success 201 {object} models.TaskEntity
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notes/{id}/convert [post]
+func (c *NoteController) ConvertToTask(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	note := c.getOwnedNote(ctx, authUser.UserID)
+	if note == nil {
+		return
+	}
+
+	title := convertedTaskTitle(note)
+	if title == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "The note has no title or content to create a task from"})
+		return
+	}
+
+	completed := false
+	task := &models.TaskEntity{
+		Title:       title,
+		User:        authUser.UserID,
+		Description: note.Content,
+		Completed:   &completed,
+	}
+
+	createdTask, err := c.taskRepo.Create(ctx, task)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	taskID, err := primitive.ObjectIDFromHex(createdTask.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := c.taskRepo.LinkNote(ctx, taskID, *note.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	createdTask.NoteIDs = []primitive.ObjectID{*note.ID}
+	ctx.JSON(http.StatusCreated, createdTask)
+}
+
+// convertedTaskTitle returns the title of the task created from a note: the title of the note, or the
+// first line of its content when it has no title
+func convertedTaskTitle(note *models.NoteEntity) string {
+	if note.Title != nil && strings.TrimSpace(*note.Title) != "" {
+		return strings.TrimSpace(*note.Title)
+	}
+	if note.Content == nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(*note.Content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if runes := []rune(line); len(runes) > maxConvertedTitleLength {
+			line = strings.TrimSpace(string(runes[:maxConvertedTitleLength]))
+		}
+		return line
+	}
+	return ""
+}
@@ -0,0 +1,229 @@
+package notes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func createTestTask(userID primitive.ObjectID) *models.TaskEntity {
+	return &models.TaskEntity{
+		ID:    primitive.NewObjectID().Hex(),
+		Title: "Test Task",
+		User:  userID,
+	}
+}
+
+func TestLinkTask(t *testing.T) {
+	t.Run("links the task to the note", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+		task := createTestTask(note.User)
+		taskID, _ := primitive.ObjectIDFromHex(task.ID)
+
+		linkedNote := *note
+		linkedNote.TaskIDs = []primitive.ObjectID{taskID}
+
+		mockNoteRepo.On("GetByID", mock.Anything, note.ID.Hex()).Return(note, nil).Once()
+		mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil).Once()
+		mockTaskRepo.On("LinkNote", mock.Anything, taskID, *note.ID).Return(nil).Once()
+		mockNoteRepo.On("GetByID", mock.Anything, note.ID.Hex()).Return(&linkedNote, nil).Once()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, note.User)
+		req, _ := http.NewRequest(http.MethodPut, "/notes/"+note.ID.Hex()+"/tasks/"+task.ID, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.NoteEntity
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []primitive.ObjectID{taskID}, response.TaskIDs)
+		mockNoteRepo.AssertExpectations(t)
+		mockTaskRepo.AssertExpectations(t)
+	})
+
+	t.Run("task of another user", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+		task := createTestTask(primitive.NewObjectID())
+
+		mockNoteRepo.On("GetByID", mock.Anything, note.ID.Hex()).Return(note, nil).Once()
+		mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil).Once()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, note.User)
+		req, _ := http.NewRequest(http.MethodPut, "/notes/"+note.ID.Hex()+"/tasks/"+task.ID, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockTaskRepo.AssertNotCalled(t, "LinkNote", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+		taskID := primitive.NewObjectID().Hex()
+
+		mockNoteRepo.On("GetByID", mock.Anything, note.ID.Hex()).Return(note, nil).Once()
+		mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(nil, nil).Once()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, note.User)
+		req, _ := http.NewRequest(http.MethodPut, "/notes/"+note.ID.Hex()+"/tasks/"+taskID, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("note of another user", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+
+		mockNoteRepo.On("GetByID", mock.Anything, note.ID.Hex()).Return(note, nil).Once()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, primitive.NewObjectID())
+		req, _ := http.NewRequest(http.MethodPut, "/notes/"+note.ID.Hex()+"/tasks/"+primitive.NewObjectID().Hex(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockTaskRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid task ID", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, note.User)
+		req, _ := http.NewRequest(http.MethodPut, "/notes/"+note.ID.Hex()+"/tasks/invalid", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUnlinkTask(t *testing.T) {
+	t.Run("removes the link", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+		task := createTestTask(note.User)
+		taskID, _ := primitive.ObjectIDFromHex(task.ID)
+
+		mockNoteRepo.On("GetByID", mock.Anything, note.ID.Hex()).Return(note, nil).Twice()
+		mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil).Once()
+		mockTaskRepo.On("UnlinkNote", mock.Anything, taskID, *note.ID).Return(nil).Once()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, note.User)
+		req, _ := http.NewRequest(http.MethodDelete, "/notes/"+note.ID.Hex()+"/tasks/"+task.ID, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockNoteRepo.AssertExpectations(t)
+		mockTaskRepo.AssertExpectations(t)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+		task := createTestTask(note.User)
+		taskID, _ := primitive.ObjectIDFromHex(task.ID)
+
+		mockNoteRepo.On("GetByID", mock.Anything, note.ID.Hex()).Return(note, nil).Once()
+		mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil).Once()
+		mockTaskRepo.On("UnlinkNote", mock.Anything, taskID, *note.ID).Return(errors.New("database error")).Once()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, note.User)
+		req, _ := http.NewRequest(http.MethodDelete, "/notes/"+note.ID.Hex()+"/tasks/"+task.ID, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestConvertToTask(t *testing.T) {
+	t.Run("creates a linked task from the note", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+
+		createdTask := createTestTask(note.User)
+		taskID, _ := primitive.ObjectIDFromHex(createdTask.ID)
+
+		var created *models.TaskEntity
+		mockNoteRepo.On("GetByID", mock.Anything, note.ID.Hex()).Return(note, nil).Once()
+		mockTaskRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.TaskEntity")).
+			Run(func(args mock.Arguments) { created = args.Get(1).(*models.TaskEntity) }).
+			Return(createdTask, nil).Once()
+		mockTaskRepo.On("LinkNote", mock.Anything, taskID, *note.ID).Return(nil).Once()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, note.User)
+		req, _ := http.NewRequest(http.MethodPost, "/notes/"+note.ID.Hex()+"/convert", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "Test Note", created.Title)
+		assert.Equal(t, note.Content, created.Description)
+		assert.Equal(t, note.User, created.User)
+		assert.False(t, *created.Completed)
+
+		var response models.TaskEntity
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, createdTask.ID, response.ID)
+		assert.Equal(t, []primitive.ObjectID{*note.ID}, response.NoteIDs)
+		mockTaskRepo.AssertExpectations(t)
+	})
+
+	t.Run("note without title or content", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+		note.Title = stringPtr("  ")
+		note.Content = stringPtr("\n\n")
+
+		mockNoteRepo.On("GetByID", mock.Anything, note.ID.Hex()).Return(note, nil).Once()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, note.User)
+		req, _ := http.NewRequest(http.MethodPost, "/notes/"+note.ID.Hex()+"/convert", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockTaskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestConvertedTaskTitle(t *testing.T) {
+	long := ""
+	for i := 0; i < maxConvertedTitleLength+20; i++ {
+		long += "a"
+	}
+
+	tests := []struct {
+		name     string
+		title    *string
+		content  *string
+		expected string
+	}{
+		{"title of the note", stringPtr(" Groceries "), stringPtr("milk"), "Groceries"},
+		{"first line of the content", nil, stringPtr("\n  Call the bank \nabout the card"), "Call the bank"},
+		{"long first line is cut", stringPtr(""), &long, long[:maxConvertedTitleLength]},
+		{"empty note", nil, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := &models.NoteEntity{Title: tt.title, Content: tt.content}
+			assert.Equal(t, tt.expected, convertedTaskTitle(note))
+		})
+	}
+}
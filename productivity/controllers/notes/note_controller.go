@@ -12,25 +12,28 @@ import (
 // NoteController handles note related operations
 type NoteController struct {
 	noteRepo repositories.NoteRepositoryInterface
+	taskRepo repositories.TaskRepositoryInterface
 }
 
 // NewNoteController creates a new note controller instance
-func NewNoteController(noteRepo repositories.NoteRepositoryInterface) *NoteController {
+func NewNoteController(noteRepo repositories.NoteRepositoryInterface, taskRepo repositories.TaskRepositoryInterface) *NoteController {
 	return &NoteController{
 		noteRepo: noteRepo,
+		taskRepo: taskRepo,
 	}
 }
 
 // SetupRoutes sets up the note routes
 func SetupRoutes(router *gin.Engine, database *mongo.Database) {
 	noteRepo := repositories.NewNoteRepository(database)
-	noteController := NewNoteController(noteRepo)
+	taskRepo := repositories.NewTaskRepository(database)
+	noteController := NewNoteController(noteRepo, taskRepo)
 	setupNoteRoutes(router, noteController)
 }
 
 // SetupRoutesWithMock sets up the note routes with a mock repository for testing
-func SetupRoutesWithMock(router *gin.Engine, noteRepo repositories.NoteRepositoryInterface) {
-	noteController := NewNoteController(noteRepo)
+func SetupRoutesWithMock(router *gin.Engine, noteRepo repositories.NoteRepositoryInterface, taskRepo repositories.TaskRepositoryInterface) {
+	noteController := NewNoteController(noteRepo, taskRepo)
 	setupNoteRoutes(router, noteController)
 }
 
@@ -45,6 +48,11 @@ func setupNoteRoutes(router *gin.Engine, noteController *NoteController) {
 		noteRoutes.POST("", noteController.CreateNote)
 		noteRoutes.PUT("/:id", noteController.UpdateNote)
 		noteRoutes.DELETE("/:id", noteController.DeleteNote)
+		noteRoutes.GET("/:id/revisions", noteController.GetNoteRevisions)
+		noteRoutes.POST("/:id/revisions/:rev/restore", noteController.RestoreNoteRevision)
+		noteRoutes.PUT("/:id/tasks/:taskId", noteController.LinkTask)
+		noteRoutes.DELETE("/:id/tasks/:taskId", noteController.UnlinkTask)
+		noteRoutes.POST("/:id/convert", noteController.ConvertToTask)
 		noteRoutes.POST("/patch", noteController.Patch)
 	}
 }
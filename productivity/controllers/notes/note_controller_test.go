@@ -16,18 +16,21 @@ import (
 
 func TestNewNoteController(t *testing.T) {
 	mockNoteRepo := new(mocks.MockNoteRepository)
-	controller := NewNoteController(mockNoteRepo)
+	mockTaskRepo := new(mocks.MockTaskRepository)
+	controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 	assert.NotNil(t, controller)
 	assert.Equal(t, mockNoteRepo, controller.noteRepo)
+	assert.Equal(t, mockTaskRepo, controller.taskRepo)
 }
 
 func TestSetupRoutesWithMock(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 	mockNoteRepo := new(mocks.MockNoteRepository)
+	mockTaskRepo := new(mocks.MockTaskRepository)
 
-	SetupRoutesWithMock(router, mockNoteRepo)
+	SetupRoutesWithMock(router, mockNoteRepo, mockTaskRepo)
 
 	// Test that routes are properly registered by making test requests
 	testRoutes := []struct {
@@ -81,9 +84,10 @@ func createTestNote() *models.NoteEntity {
 }
 
 // Helper function to set up test environment
-func setupTest() (*gin.Context, *mocks.MockNoteRepository) {
+func setupTest() (*mocks.MockTaskRepository, *mocks.MockNoteRepository) {
 	gin.SetMode(gin.TestMode)
 	mockNoteRepo := new(mocks.MockNoteRepository)
+	mockTaskRepo := new(mocks.MockTaskRepository)
 
-	return nil, mockNoteRepo
+	return mockTaskRepo, mockNoteRepo
 }
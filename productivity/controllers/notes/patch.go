@@ -2,25 +2,21 @@ package notes
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
-	"github.com/atomic-blend/backend/shared/middlewares/auth"
+	"time"
+
 	"github.com/atomic-blend/backend/productivity/models"
 	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
-	"time"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/utils/patch"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // PatchResponse represents the response structure for the patch operation
-type PatchResponse struct {
-	Success   []string                     `json:"success"`
-	Errors    []patchmodels.PatchError     `json:"errors"`
-	Conflicts []patchmodels.ConflictedItem `json:"conflicts"`
-	Date      time.Time                    `json:"date"`
-}
+type PatchResponse = patchmodels.PatchResponse
 
 // Patch handles the patching of notes
 func (c *NoteController) Patch(ctx *gin.Context) {
@@ -30,7 +26,6 @@ func (c *NoteController) Patch(ctx *gin.Context) {
 		return
 	}
 
-	var ct = context.TODO()
 	// parse the request body into a Patch struct
 	var patchs []patchmodels.Patch
 	if err := ctx.ShouldBindJSON(&patchs); err != nil {
@@ -38,148 +33,55 @@ func (c *NoteController) Patch(ctx *gin.Context) {
 		return
 	}
 
-	var success = make([]string, 0)
-	var errors = make([]patchmodels.PatchError, 0)
-	var conflicts = make([]patchmodels.ConflictedItem, 0)
+	processor := patch.NewProcessor()
+	processor.Register(patchmodels.ItemTypeNote, NewPatchAdapter(c.noteRepo))
 
-	for _, patch := range patchs {
-		//check if patch type is note
-		if patch.ItemType != patchmodels.ItemTypeNote {
-			errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "item_type_not_supported"})
-			continue
-		}
+	ctx.JSON(200, processor.Apply(ctx, authUser.UserID, patchs))
+}
 
-		// check if action is contained in validPatchActions
-		if !containString(patchmodels.ValidPatchActions, patch.Action) {
-			errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "invalid_action"})
-			continue
-		}
+// patchAdapter applies patches to notes
+type patchAdapter struct {
+	noteRepo repositories.NoteRepositoryInterface
+}
 
-		if patch.Action != patchmodels.PatchActionCreate {
-			if patch.ItemID == nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "item_id_required"})
-				continue
-			}
-			// get the note by ID
-			note, err := c.noteRepo.GetByID(ct, patch.ItemID.Hex())
-			if err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "note_not_found"})
-				continue
-			}
-
-			// check that the patch is dated after the last update of the note
-			if patch.Action != patchmodels.PatchActionCreate && patch.PatchDate.Time().Before(note.UpdatedAt.Time()) {
-				if patch.Force == nil || !*patch.Force {
-					conflicts = append(conflicts, patchmodels.ConflictedItem{Type: "note", PatchID: patch.ID.Hex(), RemoteObject: note})
-					continue
-				}
-			}
-		}
+// NewPatchAdapter creates the patch adapter for notes
+func NewPatchAdapter(noteRepo repositories.NoteRepositoryInterface) patch.Adapter {
+	return &patchAdapter{noteRepo: noteRepo}
+}
 
-		switch patch.Action {
-		case patchmodels.PatchActionUpdate:
-			// get the original note and check ownership
-			if patch.ItemID == nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "item_id_required"})
-				continue
-			}
-			note, err := c.noteRepo.GetByID(ct, patch.ItemID.Hex())
-			if err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "note_not_found"})
-				continue
-			}
-
-			if note.User != authUser.UserID {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "not_authorized"})
-				continue
-			}
-
-			// apply the changes to the note
-			_, err = c.noteRepo.UpdatePatch(ct, &patch)
-			if err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "update_failed"})
-			} else {
-				success = append(success, patch.ID.Hex())
-			}
-			continue
-		case patchmodels.PatchActionDelete:
-			// get the original note and check ownership
-			if patch.ItemID == nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "item_id_required"})
-				continue
-			}
-			note, err := c.noteRepo.GetByID(ct, patch.ItemID.Hex())
-			if err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "note_not_found"})
-				continue
-			}
-
-			if note.User != authUser.UserID {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "not_authorized"})
-				continue
-			}
-
-			// delete the note
-			err = c.noteRepo.Delete(ct, patch.ItemID.Hex())
-			if err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(),
-					ErrorCode: "delete_failed"})
-			} else {
-				success = append(success, patch.ID.Hex())
-			}
-			continue
-		case patchmodels.PatchActionCreate:
-			// the content of the note is under the data key of the first change
-			newNote := &models.NoteEntity{}
-
-			// Convert map to json string
-			jsonStr, err := json.Marshal(patch.Changes[0].Value)
-			if err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(),
-					ErrorCode: "invalid_note_data"})
-				continue
-			}
-			if err := json.Unmarshal(jsonStr, newNote); err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(),
-					ErrorCode: "invalid_note_data"})
-				continue
-			}
-
-			validate := validator.New()
-			if err := validate.Struct(newNote); err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(),
-					ErrorCode: "invalid_note_data"})
-				continue
-			}
-
-			newNote.User = authUser.UserID
-			newNote.CreatedAt = primitive.NewDateTimeFromTime(time.Now())
-			newNote.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
-
-			_, err = c.noteRepo.Create(ct, newNote)
-			if err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(),
-					ErrorCode: "create_failed"})
-			} else {
-				success = append(success, patch.ID.Hex())
-			}
-			continue
+func (a *patchAdapter) Get(ctx context.Context, id primitive.ObjectID) (*patch.Item, error) {
+	note, err := a.noteRepo.GetByID(ctx, id.Hex())
+	if err != nil || note == nil {
+		return nil, err
+	}
+	item := &patch.Item{ID: id, Object: note, Owner: note.User, UpdatedAt: note.UpdatedAt.Time()}
+	if note.FieldUpdatedAt != nil {
+		item.FieldUpdatedAt = func(key string) time.Time {
+			return note.FieldUpdatedAt.LastModified(key, note.CreatedAt).Time()
 		}
 	}
+	return item, nil
+}
 
-	ctx.JSON(200, PatchResponse{
-		Success:   success,
-		Errors:    errors,
-		Conflicts: conflicts,
-		Date:      time.Now(),
-	})
+func (a *patchAdapter) New() interface{} {
+	return &models.NoteEntity{}
 }
 
-func containString(slice []string, item string) bool {
-	for _, v := range slice {
-		if v == item {
-			return true
-		}
-	}
-	return false
+func (a *patchAdapter) Create(ctx context.Context, userID primitive.ObjectID, object interface{}) error {
+	newNote := object.(*models.NoteEntity)
+	newNote.User = userID
+	newNote.CreatedAt = primitive.NewDateTimeFromTime(time.Now())
+	newNote.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+
+	_, err := a.noteRepo.Create(ctx, newNote)
+	return err
+}
+
+func (a *patchAdapter) Update(ctx context.Context, p *patchmodels.Patch, _ *patch.Item) error {
+	_, err := a.noteRepo.UpdatePatch(ctx, p)
+	return err
+}
+
+func (a *patchAdapter) Delete(ctx context.Context, item *patch.Item) error {
+	return a.noteRepo.Delete(ctx, item.ID.Hex())
 }
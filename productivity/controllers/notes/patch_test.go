@@ -18,7 +18,7 @@ import (
 )
 
 func TestPatch(t *testing.T) {
-	_, mockNoteRepo := setupTest()
+	mockTaskRepo, mockNoteRepo := setupTest()
 
 	t.Run("successful patch update", func(t *testing.T) {
 		// Create authenticated user
@@ -65,7 +65,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: existingNote.User})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -114,7 +114,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: existingNote.User})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -175,7 +175,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -211,7 +211,7 @@ func TestPatch(t *testing.T) {
 		ctx.Request = req
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -230,7 +230,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -262,7 +262,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -301,7 +301,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -344,7 +344,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -364,6 +364,7 @@ func TestPatch(t *testing.T) {
 
 		// Create existing note that was updated recently
 		existingNote := createTestNote()
+		existingNote.User = userID
 		existingNote.UpdatedAt = primitive.NewDateTimeFromTime(time.Now()) // Note was updated now
 
 		// Create patch that is older than the note update
@@ -392,7 +393,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -433,7 +434,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -473,7 +474,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -519,7 +520,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: existingNote.User})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -566,7 +567,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -612,7 +613,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: existingNote.User})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -659,7 +660,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -707,7 +708,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -762,7 +763,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -796,6 +797,7 @@ func TestPatch(t *testing.T) {
 		noteID2 := primitive.NewObjectID()
 		patchID2 := primitive.NewObjectID()
 		existingNote2 := createTestNote()
+		existingNote2.User = existingNote1.User
 		existingNote2.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
 
 		patchDate2 := primitive.NewDateTimeFromTime(time.Now().Add(-1 * time.Hour))
@@ -838,7 +840,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: existingNote1.User})
 
 		// Call the handler directly
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -0,0 +1,135 @@
+package notes
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetNoteRevisions retrieves the previous versions of a note
+// @Summary Get note revisions
+// @Description Get the previous versions of a note, most recent first
+// @Tags Notes
+// @Produce json
+// @Param id path string true "Note ID"
+// @Success 200 {array} models.NoteRevision
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notes/{id}/revisions [get]
+func (c *NoteController) GetNoteRevisions(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	note := c.getOwnedNote(ctx, authUser.UserID)
+	if note == nil {
+		return
+	}
+
+	revisions, err := c.noteRepo.GetRevisions(ctx, *note.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, revisions)
+}
+
+// RestoreNoteRevision restores a previous version of a note
+// @Summary Restore note revision
+// @Description Restore the title and content of a previous version of a note. The current version is kept as a new revision.
+// @Tags Notes
+// @Produce json
+// @Param id path string true "Note ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} models.NoteEntity
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notes/{id}/revisions/{rev}/restore [post]
+func (c *NoteController) RestoreNoteRevision(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	rev, err := strconv.Atoi(ctx.Param("rev"))
+	if err != nil || rev < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision number"})
+		return
+	}
+
+	note := c.getOwnedNote(ctx, authUser.UserID)
+	if note == nil {
+		return
+	}
+
+	revision, err := c.noteRepo.GetRevision(ctx, *note.ID, rev)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if revision == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+
+	restored := models.NoteEntity{
+		Title:   revision.Title,
+		Content: revision.Content,
+		User:    note.User,
+		Deleted: note.Deleted,
+	}
+
+	updatedNote, err := c.noteRepo.Update(ctx, note.ID.Hex(), &restored)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updatedNote)
+}
+
+// getOwnedNote loads the note of the id path parameter, writing the error response and returning nil
+// when it does not exist or belongs to another user
+func (c *NoteController) getOwnedNote(ctx *gin.Context, userID primitive.ObjectID) *models.NoteEntity {
+	id := ctx.Param("id")
+	if strings.TrimSpace(id) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Note ID is required"})
+		return nil
+	}
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return nil
+	}
+
+	note, err := c.noteRepo.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil
+	}
+	if note == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Note not found"})
+		return nil
+	}
+	if note.User != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this note"})
+		return nil
+	}
+
+	return note
+}
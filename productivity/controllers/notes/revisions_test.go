@@ -0,0 +1,191 @@
+package notes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func setupAuthenticatedRouter(mockNoteRepo *mocks.MockNoteRepository, mockTaskRepo *mocks.MockTaskRepository, userID primitive.ObjectID) *gin.Engine {
+	controller := NewNoteController(mockNoteRepo, mockTaskRepo)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+	})
+	router.GET("/notes/:id/revisions", controller.GetNoteRevisions)
+	router.POST("/notes/:id/revisions/:rev/restore", controller.RestoreNoteRevision)
+	router.PUT("/notes/:id/tasks/:taskId", controller.LinkTask)
+	router.DELETE("/notes/:id/tasks/:taskId", controller.UnlinkTask)
+	router.POST("/notes/:id/convert", controller.ConvertToTask)
+	return router
+}
+
+func createTestRevision(note *models.NoteEntity, rev int, title string) *models.NoteRevision {
+	id := primitive.NewObjectID()
+	content := "Content of revision"
+	return &models.NoteRevision{
+		ID:      &id,
+		NoteID:  *note.ID,
+		User:    note.User,
+		Rev:     rev,
+		Title:   &title,
+		Content: &content,
+	}
+}
+
+func TestGetNoteRevisions(t *testing.T) {
+	t.Run("lists the revisions of the note", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+		revisions := []*models.NoteRevision{
+			createTestRevision(note, 2, "Second"),
+			createTestRevision(note, 1, "First"),
+		}
+
+		mockNoteRepo.On("GetByID", mock.Anything, note.ID.Hex()).Return(note, nil).Once()
+		mockNoteRepo.On("GetRevisions", mock.Anything, *note.ID).Return(revisions, nil).Once()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, note.User)
+		req, _ := http.NewRequest(http.MethodGet, "/notes/"+note.ID.Hex()+"/revisions", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []models.NoteRevision
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 2)
+		assert.Equal(t, 2, response[0].Rev)
+		mockNoteRepo.AssertExpectations(t)
+	})
+
+	t.Run("note of another user", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+
+		mockNoteRepo.On("GetByID", mock.Anything, note.ID.Hex()).Return(note, nil).Once()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, primitive.NewObjectID())
+		req, _ := http.NewRequest(http.MethodGet, "/notes/"+note.ID.Hex()+"/revisions", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockNoteRepo.AssertNotCalled(t, "GetRevisions", mock.Anything, mock.Anything)
+	})
+
+	t.Run("note not found", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		noteID := primitive.NewObjectID().Hex()
+
+		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(nil, nil).Once()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, primitive.NewObjectID())
+		req, _ := http.NewRequest(http.MethodGet, "/notes/"+noteID+"/revisions", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid note ID", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, primitive.NewObjectID())
+		req, _ := http.NewRequest(http.MethodGet, "/notes/invalid/revisions", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestRestoreNoteRevision(t *testing.T) {
+	t.Run("restores the revision through an update", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+		revision := createTestRevision(note, 3, "Old Title")
+
+		restoredNote := createTestNote()
+		restoredNote.ID = note.ID
+		restoredNote.User = note.User
+		restoredNote.Title = revision.Title
+		restoredNote.Content = revision.Content
+
+		var updated *models.NoteEntity
+		mockNoteRepo.On("GetByID", mock.Anything, note.ID.Hex()).Return(note, nil).Once()
+		mockNoteRepo.On("GetRevision", mock.Anything, *note.ID, 3).Return(revision, nil).Once()
+		mockNoteRepo.On("Update", mock.Anything, note.ID.Hex(), mock.AnythingOfType("*models.NoteEntity")).
+			Run(func(args mock.Arguments) { updated = args.Get(2).(*models.NoteEntity) }).
+			Return(restoredNote, nil).Once()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, note.User)
+		req, _ := http.NewRequest(http.MethodPost, "/notes/"+note.ID.Hex()+"/revisions/3/restore", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Old Title", *updated.Title)
+		assert.Equal(t, "Content of revision", *updated.Content)
+		assert.Equal(t, note.User, updated.User)
+
+		var response models.NoteEntity
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Old Title", *response.Title)
+		mockNoteRepo.AssertExpectations(t)
+	})
+
+	t.Run("revision not found", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+
+		mockNoteRepo.On("GetByID", mock.Anything, note.ID.Hex()).Return(note, nil).Once()
+		mockNoteRepo.On("GetRevision", mock.Anything, *note.ID, 7).Return(nil, nil).Once()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, note.User)
+		req, _ := http.NewRequest(http.MethodPost, "/notes/"+note.ID.Hex()+"/revisions/7/restore", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockNoteRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid revision number", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, note.User)
+		req, _ := http.NewRequest(http.MethodPost, "/notes/"+note.ID.Hex()+"/revisions/latest/restore", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("note of another user", func(t *testing.T) {
+		mockTaskRepo, mockNoteRepo := setupTest()
+		note := createTestNote()
+
+		mockNoteRepo.On("GetByID", mock.Anything, note.ID.Hex()).Return(note, nil).Once()
+
+		router := setupAuthenticatedRouter(mockNoteRepo, mockTaskRepo, primitive.NewObjectID())
+		req, _ := http.NewRequest(http.MethodPost, "/notes/"+note.ID.Hex()+"/revisions/1/restore", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockNoteRepo.AssertNotCalled(t, "GetRevision", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
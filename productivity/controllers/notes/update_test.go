@@ -16,7 +16,7 @@ import (
 )
 
 func TestUpdateNote(t *testing.T) {
-	_, mockNoteRepo := setupTest()
+	mockTaskRepo, mockNoteRepo := setupTest()
 
 	t.Run("successful update note", func(t *testing.T) {
 		userID := primitive.NewObjectID()
@@ -34,7 +34,7 @@ func TestUpdateNote(t *testing.T) {
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(existingNote, nil).Once()
 		mockNoteRepo.On("Update", mock.Anything, noteID, mock.AnythingOfType("*models.NoteEntity")).Return(updatedNote, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -70,7 +70,7 @@ func TestUpdateNote(t *testing.T) {
 	})
 
 	t.Run("update note without authentication", func(t *testing.T) {
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -103,7 +103,7 @@ func TestUpdateNote(t *testing.T) {
 		// Mock repository response - note not found
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(nil, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -146,7 +146,7 @@ func TestUpdateNote(t *testing.T) {
 		// Mock repository response
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(existingNote, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -188,7 +188,7 @@ func TestUpdateNote(t *testing.T) {
 		// Mock repository response
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(existingNote, nil).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -222,7 +222,7 @@ func TestUpdateNote(t *testing.T) {
 		// Mock repository error
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(nil, assert.AnError).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -265,7 +265,7 @@ func TestUpdateNote(t *testing.T) {
 		mockNoteRepo.On("GetByID", mock.Anything, noteID).Return(existingNote, nil).Once()
 		mockNoteRepo.On("Update", mock.Anything, noteID, mock.AnythingOfType("*models.NoteEntity")).Return(nil, assert.AnError).Once()
 
-		controller := NewNoteController(mockNoteRepo)
+		controller := NewNoteController(mockNoteRepo, mockTaskRepo)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
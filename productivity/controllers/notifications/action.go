@@ -0,0 +1,251 @@
+package notifications
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/controllers/tasks"
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+	"github.com/atomic-blend/backend/productivity/utils/patch"
+	"github.com/atomic-blend/backend/productivity/utils/recurrence"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxSnoozeMinutes is the longest a notification can be snoozed
+const maxSnoozeMinutes = 24 * 60
+
+// ApplyAction applies an action taken by the user from a notification
+// @Summary Apply notification action
+// @Description Complete a task, log a habit or snooze a notification from the notification itself
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param action body models.NotificationAction true "Notification action"
+// @Success 200 {object} models.TaskEntity
+// @Success 200 {object} models.HabitEntry
+// @Success 201 {object} models.HabitEntry
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notifications/actions [post]
+func (c *NotificationController) ApplyAction(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var action models.NotificationAction
+	if err := ctx.ShouldBindJSON(&action); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch {
+	case action.Action == models.NotificationActionSnooze:
+		c.snooze(ctx, authUser.UserID, &action)
+	case action.Action == models.NotificationActionComplete && action.EntityType == models.NotificationEntityTask:
+		c.completeTask(ctx, authUser.UserID, &action)
+	case action.Action == models.NotificationActionLogHabit && action.EntityType == models.NotificationEntityHabit:
+		c.logHabit(ctx, authUser.UserID, &action)
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Action not supported for this entity"})
+	}
+}
+
+// completeTask completes the task of the notification, a recurring task moves to its next occurrence
+func (c *NotificationController) completeTask(ctx *gin.Context, userID primitive.ObjectID, action *models.NotificationAction) {
+	task, ok := c.getTask(ctx, userID, action.EntityID)
+	if !ok {
+		return
+	}
+
+	// the notification may be older than the current occurrence of the task
+	if (task.Completed != nil && *task.Completed) || occurrenceDone(task, action.Occurrence) {
+		ctx.JSON(http.StatusOK, task)
+		return
+	}
+
+	itemID, err := primitive.ObjectIDFromHex(task.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	// completing goes through the patch adapter of the tasks, so that recurring tasks
+	// advance and parents are completed with their last subtask
+	now := primitive.NewDateTimeFromTime(time.Now())
+	force := true
+	processor := patch.NewProcessor()
+	processor.Register(patchmodels.ItemTypeTask, tasks.NewPatchAdapter(c.taskRepo, c.userClient))
+	response := processor.Apply(ctx, userID, []patchmodels.Patch{{
+		ID:        primitive.NewObjectID(),
+		Action:    patchmodels.PatchActionUpdate,
+		ItemType:  patchmodels.ItemTypeTask,
+		ItemID:    &itemID,
+		Changes:   []patchmodels.PatchChange{{Key: "completed", Value: true}},
+		PatchDate: &now,
+		Force:     &force,
+	}})
+	if len(response.Errors) > 0 {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": response.Errors[0].ErrorCode})
+		return
+	}
+
+	updatedTask, err := c.taskRepo.GetByID(ctx, task.ID)
+	if err != nil || updatedTask == nil {
+		ctx.JSON(http.StatusOK, task)
+		return
+	}
+	ctx.JSON(http.StatusOK, updatedTask)
+}
+
+// logHabit adds an entry to the habit of the notification
+func (c *NotificationController) logHabit(ctx *gin.Context, userID primitive.ObjectID, action *models.NotificationAction) {
+	habit, ok := c.getHabit(ctx, userID, action.EntityID)
+	if !ok {
+		return
+	}
+
+	entry := &models.HabitEntry{
+		HabitID:   habit.ID,
+		UserID:    userID,
+		EntryDate: primitive.NewDateTimeFromTime(time.Now()),
+	}
+
+	// the entry is dated at the notified occurrence, so that the same notification only logs the habit once
+	if action.Occurrence != nil {
+		entries, err := c.habitRepo.GetEntriesByHabitID(ctx, habit.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		for i := range entries {
+			if entries[i].EntryDate == *action.Occurrence {
+				ctx.JSON(http.StatusOK, &entries[i])
+				return
+			}
+		}
+		entry.EntryDate = *action.Occurrence
+	}
+	createdEntry, err := c.habitRepo.AddEntry(ctx, entry)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, createdEntry)
+}
+
+// snooze schedules the notification to be sent again a few minutes later
+func (c *NotificationController) snooze(ctx *gin.Context, userID primitive.ObjectID, action *models.NotificationAction) {
+	minutes := models.DefaultSnoozeMinutes
+	if action.Minutes != nil {
+		minutes = *action.Minutes
+	}
+	if minutes < 1 || minutes > maxSnoozeMinutes {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Snooze duration must be between 1 minute and 24 hours"})
+		return
+	}
+
+	kind := action.Kind
+	switch action.EntityType {
+	case models.NotificationEntityTask:
+		if _, ok := c.getTask(ctx, userID, action.EntityID); !ok {
+			return
+		}
+		if kind != "due" && kind != "starting" {
+			kind = "reminder"
+		}
+	case models.NotificationEntityHabit:
+		if _, ok := c.getHabit(ctx, userID, action.EntityID); !ok {
+			return
+		}
+		kind = "reminder"
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Action not supported for this entity"})
+		return
+	}
+
+	snooze := &models.NotificationSnooze{
+		UserID:     userID,
+		EntityType: action.EntityType,
+		EntityID:   action.EntityID,
+		Kind:       kind,
+		Occurrence: action.Occurrence,
+		RemindAt:   primitive.NewDateTimeFromTime(time.Now().Add(time.Duration(minutes) * time.Minute)),
+	}
+	if err := c.notificationRepo.Snooze(ctx, snooze); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, snooze)
+}
+
+// getTask loads a task of the user, writing the error response when it cannot be acted on
+func (c *NotificationController) getTask(ctx *gin.Context, userID primitive.ObjectID, id string) (*models.TaskEntity, bool) {
+	task, err := c.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if task == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return nil, false
+	}
+	if task.User != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this task"})
+		return nil, false
+	}
+	return task, true
+}
+
+// getHabit loads a habit of the user, writing the error response when it cannot be acted on
+func (c *NotificationController) getHabit(ctx *gin.Context, userID primitive.ObjectID, id string) (*models.Habit, bool) {
+	habitID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid habit ID"})
+		return nil, false
+	}
+
+	habit, err := c.habitRepo.GetByID(ctx, habitID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if habit == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Habit not found"})
+		return nil, false
+	}
+	if habit.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this habit"})
+		return nil, false
+	}
+	return habit, true
+}
+
+// occurrenceDone returns true when a recurring task already moved past the occurrence of a notification,
+// that is when all the dates of its current occurrence are after the date of the notification
+func occurrenceDone(task *models.TaskEntity, occurrence *primitive.DateTime) bool {
+	if occurrence == nil || !recurrence.IsRecurring(task) {
+		return false
+	}
+
+	var earliest *time.Time
+	for _, date := range append([]*primitive.DateTime{task.StartDate, task.EndDate}, task.Reminders...) {
+		if date == nil {
+			continue
+		}
+		if t := date.Time(); earliest == nil || t.Before(*earliest) {
+			earliest = &t
+		}
+	}
+	return earliest != nil && earliest.After(occurrence.Time())
+}
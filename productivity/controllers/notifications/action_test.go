@@ -0,0 +1,230 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func setupTest() (*NotificationController, *mocks.MockTaskRepository, *mocks.MockHabitRepository, *mocks.MockNotificationRepository) {
+	gin.SetMode(gin.TestMode)
+
+	taskRepo := new(mocks.MockTaskRepository)
+	habitRepo := new(mocks.MockHabitRepository)
+	notificationRepo := new(mocks.MockNotificationRepository)
+	controller := NewNotificationController(taskRepo, habitRepo, notificationRepo, nil)
+
+	return controller, taskRepo, habitRepo, notificationRepo
+}
+
+func actionRequest(t *testing.T, controller *NotificationController, userID *primitive.ObjectID, action models.NotificationAction) *httptest.ResponseRecorder {
+	body, err := json.Marshal(action)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/notifications/actions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	if userID != nil {
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: *userID})
+	}
+	controller.ApplyAction(ctx)
+	return w
+}
+
+func TestApplyAction(t *testing.T) {
+	t.Run("unauthorized", func(t *testing.T) {
+		controller, _, _, _ := setupTest()
+		w := actionRequest(t, controller, nil, models.NotificationAction{Action: models.NotificationActionSnooze, EntityType: models.NotificationEntityTask, EntityID: "id"})
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("completes a task", func(t *testing.T) {
+		controller, taskRepo, _, _ := setupTest()
+		userID := primitive.NewObjectID()
+		taskID := primitive.NewObjectID()
+		completed := false
+		task := &models.TaskEntity{ID: taskID.Hex(), Title: "Task", User: userID, Completed: &completed}
+		done := true
+		completedTask := &models.TaskEntity{ID: taskID.Hex(), Title: "Task", User: userID, Completed: &done}
+
+		taskRepo.On("GetByID", mock.Anything, taskID.Hex()).Return(task, nil).Twice()
+		taskRepo.On("UpdatePatch", mock.Anything, mock.MatchedBy(func(p *patchmodels.Patch) bool {
+			return *p.ItemID == taskID && p.Changes[0].Key == "completed" && p.Changes[0].Value == true
+		})).Return(completedTask, nil).Once()
+		taskRepo.On("GetByID", mock.Anything, taskID.Hex()).Return(completedTask, nil).Once()
+
+		w := actionRequest(t, controller, &userID, models.NotificationAction{
+			Action:     models.NotificationActionComplete,
+			EntityType: models.NotificationEntityTask,
+			EntityID:   taskID.Hex(),
+		})
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response models.TaskEntity
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, *response.Completed)
+		taskRepo.AssertExpectations(t)
+	})
+
+	t.Run("does not complete a recurring task past the notified occurrence", func(t *testing.T) {
+		controller, taskRepo, _, _ := setupTest()
+		userID := primitive.NewObjectID()
+		taskID := primitive.NewObjectID()
+		completed := false
+		rule := "FREQ=DAILY"
+		start := primitive.NewDateTimeFromTime(time.Now().Add(20 * time.Hour))
+		task := &models.TaskEntity{ID: taskID.Hex(), Title: "Task", User: userID, Completed: &completed, Recurrence: &rule, StartDate: &start}
+		occurrence := primitive.NewDateTimeFromTime(time.Now().Add(-4 * time.Hour))
+
+		taskRepo.On("GetByID", mock.Anything, taskID.Hex()).Return(task, nil)
+
+		w := actionRequest(t, controller, &userID, models.NotificationAction{
+			Action:     models.NotificationActionComplete,
+			EntityType: models.NotificationEntityTask,
+			EntityID:   taskID.Hex(),
+			Occurrence: &occurrence,
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		taskRepo.AssertNotCalled(t, "UpdatePatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("cannot complete the task of another user", func(t *testing.T) {
+		controller, taskRepo, _, _ := setupTest()
+		userID := primitive.NewObjectID()
+		taskID := primitive.NewObjectID()
+		task := &models.TaskEntity{ID: taskID.Hex(), Title: "Task", User: primitive.NewObjectID()}
+
+		taskRepo.On("GetByID", mock.Anything, taskID.Hex()).Return(task, nil)
+
+		w := actionRequest(t, controller, &userID, models.NotificationAction{
+			Action:     models.NotificationActionComplete,
+			EntityType: models.NotificationEntityTask,
+			EntityID:   taskID.Hex(),
+		})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("logs a habit", func(t *testing.T) {
+		controller, _, habitRepo, _ := setupTest()
+		userID := primitive.NewObjectID()
+		habit := &models.Habit{ID: primitive.NewObjectID(), UserID: userID}
+
+		habitRepo.On("GetByID", mock.Anything, habit.ID).Return(habit, nil)
+		habitRepo.On("AddEntry", mock.Anything, mock.MatchedBy(func(entry *models.HabitEntry) bool {
+			return entry.HabitID == habit.ID && entry.UserID == userID
+		})).Return(&models.HabitEntry{ID: primitive.NewObjectID(), HabitID: habit.ID, UserID: userID}, nil)
+
+		w := actionRequest(t, controller, &userID, models.NotificationAction{
+			Action:     models.NotificationActionLogHabit,
+			EntityType: models.NotificationEntityHabit,
+			EntityID:   habit.ID.Hex(),
+		})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		habitRepo.AssertExpectations(t)
+	})
+
+	t.Run("logs a habit once per notified occurrence", func(t *testing.T) {
+		controller, _, habitRepo, _ := setupTest()
+		userID := primitive.NewObjectID()
+		habit := &models.Habit{ID: primitive.NewObjectID(), UserID: userID}
+		occurrence := primitive.NewDateTimeFromTime(time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC))
+		action := models.NotificationAction{
+			Action:     models.NotificationActionLogHabit,
+			EntityType: models.NotificationEntityHabit,
+			EntityID:   habit.ID.Hex(),
+			Occurrence: &occurrence,
+		}
+
+		habitRepo.On("GetByID", mock.Anything, habit.ID).Return(habit, nil)
+		habitRepo.On("GetEntriesByHabitID", mock.Anything, habit.ID).Return([]models.HabitEntry{}, nil).Once()
+		habitRepo.On("AddEntry", mock.Anything, mock.MatchedBy(func(entry *models.HabitEntry) bool {
+			return entry.HabitID == habit.ID && entry.EntryDate == occurrence
+		})).Return(&models.HabitEntry{ID: primitive.NewObjectID(), HabitID: habit.ID, UserID: userID, EntryDate: occurrence}, nil).Once()
+
+		w := actionRequest(t, controller, &userID, action)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		// the same action again finds the entry of the occurrence
+		habitRepo.On("GetEntriesByHabitID", mock.Anything, habit.ID).Return([]models.HabitEntry{
+			{ID: primitive.NewObjectID(), HabitID: habit.ID, UserID: userID, EntryDate: occurrence},
+		}, nil).Once()
+
+		w = actionRequest(t, controller, &userID, action)
+		assert.Equal(t, http.StatusOK, w.Code)
+		habitRepo.AssertNumberOfCalls(t, "AddEntry", 1)
+		habitRepo.AssertExpectations(t)
+	})
+
+	t.Run("snoozes a task reminder", func(t *testing.T) {
+		controller, taskRepo, _, notificationRepo := setupTest()
+		userID := primitive.NewObjectID()
+		taskID := primitive.NewObjectID()
+		task := &models.TaskEntity{ID: taskID.Hex(), Title: "Task", User: userID}
+		before := time.Now()
+
+		taskRepo.On("GetByID", mock.Anything, taskID.Hex()).Return(task, nil)
+		notificationRepo.On("Snooze", mock.Anything, mock.MatchedBy(func(snooze *models.NotificationSnooze) bool {
+			delay := snooze.RemindAt.Time().Sub(before)
+			return snooze.UserID == userID && snooze.Kind == "reminder" &&
+				delay >= 9*time.Minute && delay <= 11*time.Minute
+		})).Return(nil)
+
+		w := actionRequest(t, controller, &userID, models.NotificationAction{
+			Action:     models.NotificationActionSnooze,
+			EntityType: models.NotificationEntityTask,
+			EntityID:   taskID.Hex(),
+			Kind:       "reminder",
+		})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		notificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects an invalid snooze duration", func(t *testing.T) {
+		controller, _, _, _ := setupTest()
+		userID := primitive.NewObjectID()
+		minutes := 0
+
+		w := actionRequest(t, controller, &userID, models.NotificationAction{
+			Action:     models.NotificationActionSnooze,
+			EntityType: models.NotificationEntityHabit,
+			EntityID:   primitive.NewObjectID().Hex(),
+			Minutes:    &minutes,
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("rejects an action not supported by the entity", func(t *testing.T) {
+		controller, _, _, _ := setupTest()
+		userID := primitive.NewObjectID()
+
+		w := actionRequest(t, controller, &userID, models.NotificationAction{
+			Action:     models.NotificationActionLogHabit,
+			EntityType: models.NotificationEntityTask,
+			EntityID:   primitive.NewObjectID().Hex(),
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
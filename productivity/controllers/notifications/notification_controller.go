@@ -0,0 +1,58 @@
+package notifications
+
+import (
+	"github.com/atomic-blend/backend/productivity/repositories"
+	userclient "github.com/atomic-blend/backend/shared/grpc/user"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NotificationController handles the actions taken by the users from their notifications
+type NotificationController struct {
+	taskRepo         repositories.TaskRepositoryInterface
+	habitRepo        repositories.HabitRepositoryInterface
+	notificationRepo repositories.NotificationRepositoryInterface
+	userClient       userclient.Interface
+}
+
+// NewNotificationController creates a new notification controller instance
+func NewNotificationController(taskRepo repositories.TaskRepositoryInterface, habitRepo repositories.HabitRepositoryInterface, notificationRepo repositories.NotificationRepositoryInterface, userClient userclient.Interface) *NotificationController {
+	return &NotificationController{
+		taskRepo:         taskRepo,
+		habitRepo:        habitRepo,
+		notificationRepo: notificationRepo,
+		userClient:       userClient,
+	}
+}
+
+// SetupRoutes sets up the notification routes
+func SetupRoutes(router *gin.Engine, database *mongo.Database) {
+	var userClient userclient.Interface
+	if client, err := userclient.NewUserClient(); err == nil {
+		userClient = client
+	}
+	notificationController := NewNotificationController(
+		repositories.NewTaskRepository(database),
+		repositories.NewHabitRepository(database),
+		repositories.NewNotificationRepository(database),
+		userClient,
+	)
+	setupNotificationRoutes(router, notificationController)
+}
+
+// SetupRoutesWithMock sets up the notification routes with mock repositories for testing
+func SetupRoutesWithMock(router *gin.Engine, taskRepo repositories.TaskRepositoryInterface, habitRepo repositories.HabitRepositoryInterface, notificationRepo repositories.NotificationRepositoryInterface, userClient userclient.Interface) {
+	notificationController := NewNotificationController(taskRepo, habitRepo, notificationRepo, userClient)
+	setupNotificationRoutes(router, notificationController)
+}
+
+// setupNotificationRoutes sets up the routes for notification controller
+func setupNotificationRoutes(router *gin.Engine, notificationController *NotificationController) {
+	notificationRoutes := router.Group("/notifications")
+	auth.RequireAuth(notificationRoutes)
+	{
+		notificationRoutes.POST("/actions", notificationController.ApplyAction)
+	}
+}
@@ -0,0 +1,54 @@
+package pomodorocontroller
+
+import (
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Controller handles the pomodoro sessions
+type Controller struct {
+	pomodoroRepo  repositories.PomodoroRepositoryInterface
+	timeEntryRepo repositories.TimeEntryRepositoryInterface
+	taskRepo      repositories.TaskRepositoryInterface
+}
+
+// NewPomodoroController creates a new pomodoro controller instance
+func NewPomodoroController(pomodoroRepo repositories.PomodoroRepositoryInterface, timeEntryRepo repositories.TimeEntryRepositoryInterface, taskRepo repositories.TaskRepositoryInterface) *Controller {
+	return &Controller{
+		pomodoroRepo:  pomodoroRepo,
+		timeEntryRepo: timeEntryRepo,
+		taskRepo:      taskRepo,
+	}
+}
+
+// SetupRoutes sets up the pomodoro routes
+func SetupRoutes(router *gin.Engine, database *mongo.Database) {
+	pomodoroController := NewPomodoroController(
+		repositories.NewPomodoroRepository(database),
+		repositories.NewTimeEntryRepository(database),
+		repositories.NewTaskRepository(database),
+	)
+	setupPomodoroRoutes(router, pomodoroController)
+}
+
+// SetupRoutesWithMock sets up the pomodoro routes with mock repositories for testing
+func SetupRoutesWithMock(router *gin.Engine, pomodoroRepo repositories.PomodoroRepositoryInterface, timeEntryRepo repositories.TimeEntryRepositoryInterface, taskRepo repositories.TaskRepositoryInterface) {
+	pomodoroController := NewPomodoroController(pomodoroRepo, timeEntryRepo, taskRepo)
+	setupPomodoroRoutes(router, pomodoroController)
+}
+
+// setupPomodoroRoutes sets up the routes for pomodoro controller
+func setupPomodoroRoutes(router *gin.Engine, pomodoroController *Controller) {
+	pomodoroRoutes := router.Group("/pomodoro/sessions")
+	auth.RequireAuth(pomodoroRoutes)
+	{
+		pomodoroRoutes.POST("", pomodoroController.Start)
+		pomodoroRoutes.GET("/current", pomodoroController.GetCurrent)
+		pomodoroRoutes.GET("/:id", pomodoroController.GetByID)
+		pomodoroRoutes.POST("/:id/skip", pomodoroController.Skip)
+		pomodoroRoutes.POST("/:id/stop", pomodoroController.Stop)
+	}
+}
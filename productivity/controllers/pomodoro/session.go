@@ -0,0 +1,238 @@
+package pomodorocontroller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/utils/pomodoro"
+	"github.com/atomic-blend/backend/productivity/utils/timer"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Start starts a pomodoro session for the authenticated user
+// @Summary Start pomodoro session
+// @Description Start a session with a work phase, the phases then follow each other until the session is stopped
+// @Tags Pomodoro
+// @Accept json
+// @Produce json
+// @Param session body models.PomodoroStartRequest false "Task and durations of the session"
+// @Success 201 {object} models.PomodoroSession
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /pomodoro/sessions [post]
+func (c *Controller) Start(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var request models.PomodoroStartRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if request.TaskID != nil {
+		task, err := c.taskRepo.GetByID(ctx, request.TaskID.Hex())
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve task"})
+			return
+		}
+		if task == nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+			return
+		}
+		if task.User != authUser.UserID {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+	}
+
+	session, err := c.pomodoroRepo.Create(ctx, pomodoro.Start(authUser.UserID, &request, time.Now()))
+	if errors.Is(err, repositories.ErrPomodoroSessionActive) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "A pomodoro session is already active"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start pomodoro session"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, session)
+}
+
+// GetCurrent returns the active pomodoro session of the authenticated user
+// @Summary Get current pomodoro session
+// @Tags Pomodoro
+// @Produce json
+// @Success 200 {object} models.PomodoroSession
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /pomodoro/sessions/current [get]
+func (c *Controller) GetCurrent(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	session, err := c.pomodoroRepo.GetActive(ctx, authUser.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve pomodoro session"})
+		return
+	}
+	if session == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "No active pomodoro session"})
+		return
+	}
+
+	c.respond(ctx, session, nil)
+}
+
+// GetByID returns a pomodoro session of the authenticated user
+// @Summary Get pomodoro session
+// @Tags Pomodoro
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} models.PomodoroSession
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /pomodoro/sessions/{id} [get]
+func (c *Controller) GetByID(ctx *gin.Context) {
+	session, ok := c.getSession(ctx)
+	if !ok {
+		return
+	}
+	c.respond(ctx, session, nil)
+}
+
+// Skip ends the current phase of a pomodoro session and starts the next one
+// @Summary Skip pomodoro phase
+// @Tags Pomodoro
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} models.PomodoroSession
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /pomodoro/sessions/{id}/skip [post]
+func (c *Controller) Skip(ctx *gin.Context) {
+	session, ok := c.getSession(ctx)
+	if !ok {
+		return
+	}
+
+	c.respond(ctx, session, func(now time.Time) ([]*models.TimeEntry, error) {
+		transition, err := pomodoro.Skip(session, now)
+		return []*models.TimeEntry{transition.Entry}, err
+	})
+}
+
+// Stop stops a pomodoro session, the time spent in its current phase is kept as a time entry
+// @Summary Stop pomodoro session
+// @Tags Pomodoro
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} models.PomodoroSession
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /pomodoro/sessions/{id}/stop [post]
+func (c *Controller) Stop(ctx *gin.Context) {
+	session, ok := c.getSession(ctx)
+	if !ok {
+		return
+	}
+
+	c.respond(ctx, session, func(now time.Time) ([]*models.TimeEntry, error) {
+		entry, err := pomodoro.Stop(session, now)
+		return []*models.TimeEntry{entry}, err
+	})
+}
+
+// respond brings the session up to date, moving it through the phases that ended before the cron did and
+// stopping it when it ran for longer than the timers can, applies the change requested by the user if any,
+// saves it and writes it in the response
+func (c *Controller) respond(ctx *gin.Context, session *models.PomodoroSession, change func(now time.Time) ([]*models.TimeEntry, error)) {
+	now := time.Now()
+	phaseStartedAt := session.PhaseStartedAt
+	_, entries, stopped := pomodoro.CatchUp(session, now, timer.MaxDuration())
+
+	// a session stopped for running too long is returned as is
+	if change != nil && !stopped {
+		changeEntries, err := change(now)
+		if err != nil {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		entries = append(entries, changeEntries...)
+	}
+
+	if session.PhaseStartedAt != phaseStartedAt || session.Status != models.PomodoroStatusActive {
+		err := pomodoro.Save(ctx, c.pomodoroRepo, c.timeEntryRepo, session, phaseStartedAt, entries)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "The pomodoro session was changed, try again"})
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pomodoro session"})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, session)
+}
+
+// getSession returns the session of the request if it belongs to the authenticated user,
+// it writes the error response otherwise
+func (c *Controller) getSession(ctx *gin.Context) (*models.PomodoroSession, bool) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return nil, false
+	}
+
+	id, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return nil, false
+	}
+
+	session, err := c.pomodoroRepo.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve pomodoro session"})
+		return nil, false
+	}
+	if session == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Pomodoro session not found"})
+		return nil, false
+	}
+	if session.UserID != authUser.UserID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return nil, false
+	}
+	return session, true
+}
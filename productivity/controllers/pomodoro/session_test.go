@@ -0,0 +1,236 @@
+package pomodorocontroller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+	"github.com/atomic-blend/backend/productivity/utils/pomodoro"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func setupTest() (*Controller, *mocks.MockPomodoroRepository, *mocks.MockTimeEntryRepository, *mocks.MockTaskRepository) {
+	gin.SetMode(gin.TestMode)
+
+	pomodoroRepo := new(mocks.MockPomodoroRepository)
+	timeEntryRepo := new(mocks.MockTimeEntryRepository)
+	taskRepo := new(mocks.MockTaskRepository)
+	controller := NewPomodoroController(pomodoroRepo, timeEntryRepo, taskRepo)
+
+	return controller, pomodoroRepo, timeEntryRepo, taskRepo
+}
+
+func sessionRequest(handler gin.HandlerFunc, userID *primitive.ObjectID, sessionID string, body interface{}) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/pomodoro/sessions", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.Params = gin.Params{{Key: "id", Value: sessionID}}
+	if userID != nil {
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: *userID})
+	}
+	handler(ctx)
+	return w
+}
+
+func TestStart(t *testing.T) {
+	t.Run("unauthorized", func(t *testing.T) {
+		controller, _, _, _ := setupTest()
+		w := sessionRequest(controller.Start, nil, "", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("starts a session with the default durations", func(t *testing.T) {
+		controller, pomodoroRepo, _, _ := setupTest()
+		userID := primitive.NewObjectID()
+		pomodoroRepo.On("Create", mock.Anything, mock.MatchedBy(func(session *models.PomodoroSession) bool {
+			return session.UserID == userID && session.Phase == models.PomodoroPhaseWork && session.WorkMinutes == 45 && session.ShortBreakMinutes == 5
+		})).Return(&models.PomodoroSession{}, nil)
+
+		w := sessionRequest(controller.Start, &userID, "", gin.H{"workMinutes": 45})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		pomodoroRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid durations", func(t *testing.T) {
+		controller, _, _, _ := setupTest()
+		userID := primitive.NewObjectID()
+
+		w := sessionRequest(controller.Start, &userID, "", gin.H{"shortBreakMinutes": 500})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("task of another user", func(t *testing.T) {
+		controller, _, _, taskRepo := setupTest()
+		userID := primitive.NewObjectID()
+		task := &models.TaskEntity{ID: primitive.NewObjectID().Hex(), User: primitive.NewObjectID()}
+		taskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+
+		w := sessionRequest(controller.Start, &userID, "", gin.H{"taskId": task.ID})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("a session is already active", func(t *testing.T) {
+		controller, pomodoroRepo, _, _ := setupTest()
+		userID := primitive.NewObjectID()
+		pomodoroRepo.On("Create", mock.Anything, mock.Anything).Return(nil, repositories.ErrPomodoroSessionActive)
+
+		w := sessionRequest(controller.Start, &userID, "", nil)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestSessionActions(t *testing.T) {
+	t.Run("get the current session up to date", func(t *testing.T) {
+		controller, pomodoroRepo, timeEntryRepo, _ := setupTest()
+		userID := primitive.NewObjectID()
+		session := pomodoro.Start(userID, nil, time.Now().Add(-27*time.Minute))
+		pomodoroRepo.On("GetActive", mock.Anything, userID).Return(session, nil)
+		pomodoroRepo.On("Save", mock.Anything, session, mock.Anything).Return(nil)
+		timeEntryRepo.On("Create", mock.Anything, mock.Anything).Return(&models.TimeEntry{}, nil)
+
+		w := sessionRequest(controller.GetCurrent, &userID, "", nil)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response models.PomodoroSession
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, models.PomodoroPhaseShortBreak, response.Phase)
+		assert.Equal(t, 1, response.Cycle)
+		timeEntryRepo.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("no current session", func(t *testing.T) {
+		controller, pomodoroRepo, _, _ := setupTest()
+		userID := primitive.NewObjectID()
+		pomodoroRepo.On("GetActive", mock.Anything, userID).Return(nil, nil)
+
+		w := sessionRequest(controller.GetCurrent, &userID, "", nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("skip the current phase", func(t *testing.T) {
+		controller, pomodoroRepo, timeEntryRepo, _ := setupTest()
+		userID := primitive.NewObjectID()
+		session := pomodoro.Start(userID, nil, time.Now().Add(-10*time.Minute))
+		pomodoroRepo.On("GetByID", mock.Anything, session.ID).Return(session, nil)
+		pomodoroRepo.On("Save", mock.Anything, session, mock.Anything).Return(nil)
+		timeEntryRepo.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.TimeEntry) bool {
+			return !*entry.PomoBreak
+		})).Return(&models.TimeEntry{}, nil)
+
+		w := sessionRequest(controller.Skip, &userID, session.ID.Hex(), nil)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, models.PomodoroPhaseShortBreak, session.Phase)
+		timeEntryRepo.AssertExpectations(t)
+	})
+
+	t.Run("stop the session", func(t *testing.T) {
+		controller, pomodoroRepo, timeEntryRepo, _ := setupTest()
+		userID := primitive.NewObjectID()
+		session := pomodoro.Start(userID, nil, time.Now().Add(-10*time.Minute))
+		pomodoroRepo.On("GetByID", mock.Anything, session.ID).Return(session, nil)
+		pomodoroRepo.On("Save", mock.Anything, session, mock.Anything).Return(nil)
+		timeEntryRepo.On("Create", mock.Anything, mock.Anything).Return(&models.TimeEntry{}, nil)
+
+		w := sessionRequest(controller.Stop, &userID, session.ID.Hex(), nil)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, models.PomodoroStatusStopped, session.Status)
+		timeEntryRepo.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("stop a forgotten session at the limit", func(t *testing.T) {
+		controller, pomodoroRepo, timeEntryRepo, _ := setupTest()
+		userID := primitive.NewObjectID()
+		createdAt := time.Now().Add(-48 * time.Hour)
+		session := pomodoro.Start(userID, nil, createdAt)
+		pomodoroRepo.On("GetByID", mock.Anything, session.ID).Return(session, nil)
+		pomodoroRepo.On("Save", mock.Anything, session, mock.Anything).Return(nil)
+		timeEntryRepo.On("Create", mock.Anything, mock.Anything).Return(&models.TimeEntry{}, nil)
+
+		w := sessionRequest(controller.Skip, &userID, session.ID.Hex(), nil)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, models.PomodoroStatusStopped, session.Status)
+		assert.Equal(t, createdAt.Add(24*time.Hour).Unix(), session.StoppedAt.Time().Unix())
+	})
+
+	t.Run("stop a stopped session", func(t *testing.T) {
+		controller, pomodoroRepo, _, _ := setupTest()
+		userID := primitive.NewObjectID()
+		session := pomodoro.Start(userID, nil, time.Now().Add(-10*time.Minute))
+		_, _ = pomodoro.Stop(session, time.Now())
+		pomodoroRepo.On("GetByID", mock.Anything, session.ID).Return(session, nil)
+
+		w := sessionRequest(controller.Stop, &userID, session.ID.Hex(), nil)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		pomodoroRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("session changed concurrently", func(t *testing.T) {
+		controller, pomodoroRepo, timeEntryRepo, _ := setupTest()
+		userID := primitive.NewObjectID()
+		session := pomodoro.Start(userID, nil, time.Now().Add(-10*time.Minute))
+		pomodoroRepo.On("GetByID", mock.Anything, session.ID).Return(session, nil)
+		pomodoroRepo.On("Save", mock.Anything, session, mock.Anything).Return(mongo.ErrNoDocuments)
+		var created *models.TimeEntry
+		timeEntryRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			created = args.Get(1).(*models.TimeEntry)
+		}).Return(&models.TimeEntry{}, nil)
+		timeEntryRepo.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+		w := sessionRequest(controller.Skip, &userID, session.ID.Hex(), nil)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		// the entry of the skipped phase is deleted again
+		require.NotNil(t, created)
+		timeEntryRepo.AssertCalled(t, "Delete", mock.Anything, created.ID.Hex())
+	})
+
+	t.Run("session of another user", func(t *testing.T) {
+		controller, pomodoroRepo, _, _ := setupTest()
+		userID := primitive.NewObjectID()
+		session := pomodoro.Start(primitive.NewObjectID(), nil, time.Now())
+		pomodoroRepo.On("GetByID", mock.Anything, session.ID).Return(session, nil)
+
+		w := sessionRequest(controller.GetByID, &userID, session.ID.Hex(), nil)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("invalid session ID", func(t *testing.T) {
+		controller, _, _, _ := setupTest()
+		userID := primitive.NewObjectID()
+
+		w := sessionRequest(controller.GetByID, &userID, "invalid", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,161 @@
+package synccontroller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultChangeLimit = 500
+	maxChangeLimit     = 1000
+)
+
+// GetChanges returns the items changed and deleted after a cursor
+// @Summary Get changes
+// @Description Get the current state of the items modified after the cursor and the tombstones of the items deleted after it, across all the item types. Without cursor, or when the cursor is older than the purged tombstones, a snapshot of all the items is returned, paged like the changes: while hasMore is set, the next page is requested with the returned cursor and snapshot.
+// @Tags Sync
+// @Produce json
+// @Param cursor query int false "Cursor returned by the previous request"
+// @Param limit query int false "Maximum number of changes (default 500, max 1000)"
+// @Param snapshot query string false "Snapshot position returned by the previous request"
+// @Success 200 {object} models.ChangeSet
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /sync/changes [get]
+func (c *Controller) GetChanges(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var cursor int64
+	if cursorStr := ctx.Query("cursor"); cursorStr != "" {
+		cursorVal, err := strconv.ParseInt(cursorStr, 10, 64)
+		if err != nil || cursorVal < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor parameter"})
+			return
+		}
+		cursor = cursorVal
+	}
+
+	limit := int64(defaultChangeLimit)
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		limitVal, err := strconv.ParseInt(limitStr, 10, 64)
+		if err != nil || limitVal < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		limit = min(limitVal, maxChangeLimit)
+	}
+
+	purged, err := c.changeRepo.PurgedCursor(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	set := &models.ChangeSet{}
+
+	// clients without cursor, or that may have missed purged tombstones, start over from a snapshot,
+	// the next pages of the snapshot being requested with its position
+	snapshot := ctx.Query("snapshot")
+	if snapshot != "" || cursor == 0 || cursor < purged {
+		// the cursor is read before the first page so that changes made meanwhile are sent again
+		current := cursor
+		if snapshot == "" {
+			current, err = c.changeRepo.CurrentCursor(ctx)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		next, err := c.changeRepo.LoadSnapshot(ctx, authUser.UserID, snapshot, limit, set)
+		if errors.Is(err, repositories.ErrInvalidSnapshot) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid snapshot parameter"})
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		set.Cursor = current
+		set.Snapshot = next
+		set.HasMore = next != ""
+		set.ResetRequired = snapshot == "" && cursor != 0
+		ctx.JSON(http.StatusOK, normalizeChangeSet(set))
+		return
+	}
+
+	// fetch one more change than requested to know if another page follows
+	changes, err := c.changeRepo.GetSince(ctx, authUser.UserID, cursor, limit+1)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if int64(len(changes)) > limit {
+		changes = changes[:limit]
+		set.HasMore = true
+	}
+
+	set.Cursor = cursor
+	itemIDs := map[string][]primitive.ObjectID{}
+	for _, change := range changes {
+		if change.Deleted {
+			set.Deleted = append(set.Deleted, change)
+		} else {
+			itemIDs[change.ItemType] = append(itemIDs[change.ItemType], change.ItemID)
+		}
+		set.Cursor = change.Cursor
+	}
+
+	if len(itemIDs) > 0 {
+		if err := c.changeRepo.LoadItems(ctx, authUser.UserID, itemIDs, set); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, normalizeChangeSet(set))
+}
+
+// normalizeChangeSet replaces nil lists by empty ones so that every list is serialized as an array
+func normalizeChangeSet(set *models.ChangeSet) *models.ChangeSet {
+	if set.Tasks == nil {
+		set.Tasks = []*models.TaskEntity{}
+	}
+	if set.Notes == nil {
+		set.Notes = []*models.NoteEntity{}
+	}
+	if set.Habits == nil {
+		set.Habits = []*models.Habit{}
+	}
+	if set.HabitEntries == nil {
+		set.HabitEntries = []*models.HabitEntry{}
+	}
+	if set.Folders == nil {
+		set.Folders = []*models.Folder{}
+	}
+	if set.Tags == nil {
+		set.Tags = []*models.Tag{}
+	}
+	if set.TimeEntries == nil {
+		set.TimeEntries = []*models.TimeEntry{}
+	}
+	if set.SavedFilters == nil {
+		set.SavedFilters = []*models.SavedFilter{}
+	}
+	if set.Deleted == nil {
+		set.Deleted = []*models.Change{}
+	}
+	return set
+}
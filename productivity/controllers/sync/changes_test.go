@@ -0,0 +1,195 @@
+package synccontroller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func doGetChanges(controller *Controller, userID *primitive.ObjectID, query string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/sync/changes"+query, nil)
+
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	if userID != nil {
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: *userID})
+	}
+
+	controller.GetChanges(ctx)
+	return w
+}
+
+func TestGetChanges(t *testing.T) {
+	t.Run("changes after cursor", func(t *testing.T) {
+		controller, repos := setupTest()
+		userID := primitive.NewObjectID()
+		taskID := primitive.NewObjectID()
+		noteID := primitive.NewObjectID()
+
+		repos.change.On("PurgedCursor", mock.Anything).Return(int64(3), nil)
+		repos.change.On("GetSince", mock.Anything, userID, int64(10), int64(501)).Return([]*models.Change{
+			{ItemType: patchmodels.ItemTypeTask, ItemID: taskID, Cursor: 11},
+			{ItemType: patchmodels.ItemTypeNote, ItemID: noteID, Cursor: 12, Deleted: true},
+		}, nil)
+		repos.change.On("LoadItems", mock.Anything, userID, map[string][]primitive.ObjectID{
+			patchmodels.ItemTypeTask: {taskID},
+		}, mock.Anything).Run(func(args mock.Arguments) {
+			set := args.Get(3).(*models.ChangeSet)
+			set.Tasks = []*models.TaskEntity{{ID: taskID.Hex(), Title: "Task", User: userID}}
+		}).Return(nil)
+
+		w := doGetChanges(controller, &userID, "?cursor=10")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var set models.ChangeSet
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+		assert.Equal(t, int64(12), set.Cursor)
+		assert.False(t, set.HasMore)
+		assert.False(t, set.ResetRequired)
+		require.Len(t, set.Tasks, 1)
+		assert.Equal(t, taskID.Hex(), set.Tasks[0].ID)
+		require.Len(t, set.Deleted, 1)
+		assert.Equal(t, noteID, set.Deleted[0].ItemID)
+		assert.Equal(t, patchmodels.ItemTypeNote, set.Deleted[0].ItemType)
+		assert.NotNil(t, set.Notes)
+		repos.change.AssertExpectations(t)
+	})
+
+	t.Run("paginates with limit", func(t *testing.T) {
+		controller, repos := setupTest()
+		userID := primitive.NewObjectID()
+
+		repos.change.On("PurgedCursor", mock.Anything).Return(int64(0), nil)
+		repos.change.On("GetSince", mock.Anything, userID, int64(5), int64(3)).Return([]*models.Change{
+			{ItemType: patchmodels.ItemTypeTag, ItemID: primitive.NewObjectID(), Cursor: 6, Deleted: true},
+			{ItemType: patchmodels.ItemTypeTag, ItemID: primitive.NewObjectID(), Cursor: 7, Deleted: true},
+			{ItemType: patchmodels.ItemTypeTag, ItemID: primitive.NewObjectID(), Cursor: 8, Deleted: true},
+		}, nil)
+
+		w := doGetChanges(controller, &userID, "?cursor=5&limit=2")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var set models.ChangeSet
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+		assert.Equal(t, int64(7), set.Cursor)
+		assert.True(t, set.HasMore)
+		assert.Len(t, set.Deleted, 2)
+		repos.change.AssertNotCalled(t, "LoadItems", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("snapshot without cursor", func(t *testing.T) {
+		controller, repos := setupTest()
+		userID := primitive.NewObjectID()
+
+		repos.change.On("PurgedCursor", mock.Anything).Return(int64(4), nil)
+		repos.change.On("CurrentCursor", mock.Anything).Return(int64(42), nil)
+		repos.change.On("LoadSnapshot", mock.Anything, userID, "", int64(500), mock.Anything).Return("", nil)
+
+		w := doGetChanges(controller, &userID, "")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var set models.ChangeSet
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+		assert.Equal(t, int64(42), set.Cursor)
+		assert.False(t, set.HasMore)
+		assert.Empty(t, set.Snapshot)
+		assert.False(t, set.ResetRequired)
+		repos.change.AssertNotCalled(t, "GetSince", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("paginates the snapshot with limit", func(t *testing.T) {
+		controller, repos := setupTest()
+		userID := primitive.NewObjectID()
+
+		repos.change.On("PurgedCursor", mock.Anything).Return(int64(4), nil)
+		repos.change.On("CurrentCursor", mock.Anything).Return(int64(42), nil)
+		repos.change.On("LoadSnapshot", mock.Anything, userID, "", int64(2), mock.Anything).Return("task:abc", nil)
+
+		w := doGetChanges(controller, &userID, "?limit=2")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var set models.ChangeSet
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+		assert.Equal(t, int64(42), set.Cursor)
+		assert.True(t, set.HasMore)
+		assert.Equal(t, "task:abc", set.Snapshot)
+	})
+
+	t.Run("resumes the snapshot from its position", func(t *testing.T) {
+		controller, repos := setupTest()
+		userID := primitive.NewObjectID()
+
+		repos.change.On("PurgedCursor", mock.Anything).Return(int64(4), nil)
+		repos.change.On("LoadSnapshot", mock.Anything, userID, "task:abc", int64(2), mock.Anything).Return("", nil)
+
+		w := doGetChanges(controller, &userID, "?cursor=42&snapshot=task:abc&limit=2")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var set models.ChangeSet
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+		assert.Equal(t, int64(42), set.Cursor)
+		assert.False(t, set.HasMore)
+		assert.False(t, set.ResetRequired)
+		repos.change.AssertNotCalled(t, "CurrentCursor", mock.Anything)
+		repos.change.AssertNotCalled(t, "GetSince", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid snapshot", func(t *testing.T) {
+		controller, repos := setupTest()
+		userID := primitive.NewObjectID()
+
+		repos.change.On("PurgedCursor", mock.Anything).Return(int64(4), nil)
+		repos.change.On("LoadSnapshot", mock.Anything, userID, "nope", int64(500), mock.Anything).Return("", repositories.ErrInvalidSnapshot)
+
+		w := doGetChanges(controller, &userID, "?cursor=42&snapshot=nope")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("reset when cursor is older than purged tombstones", func(t *testing.T) {
+		controller, repos := setupTest()
+		userID := primitive.NewObjectID()
+
+		repos.change.On("PurgedCursor", mock.Anything).Return(int64(100), nil)
+		repos.change.On("CurrentCursor", mock.Anything).Return(int64(150), nil)
+		repos.change.On("LoadSnapshot", mock.Anything, userID, "", int64(500), mock.Anything).Return("", nil)
+
+		w := doGetChanges(controller, &userID, "?cursor=20")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var set models.ChangeSet
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+		assert.Equal(t, int64(150), set.Cursor)
+		assert.True(t, set.ResetRequired)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		controller, _ := setupTest()
+		userID := primitive.NewObjectID()
+
+		w := doGetChanges(controller, &userID, "?cursor=abc")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		controller, _ := setupTest()
+
+		w := doGetChanges(controller, nil, "")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
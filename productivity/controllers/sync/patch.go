@@ -0,0 +1,59 @@
+package synccontroller
+
+import (
+	"net/http"
+
+	"github.com/atomic-blend/backend/productivity/controllers/filters"
+	"github.com/atomic-blend/backend/productivity/controllers/folder"
+	"github.com/atomic-blend/backend/productivity/controllers/habits"
+	"github.com/atomic-blend/backend/productivity/controllers/notes"
+	"github.com/atomic-blend/backend/productivity/controllers/tags"
+	"github.com/atomic-blend/backend/productivity/controllers/tasks"
+	timeentrycontroller "github.com/atomic-blend/backend/productivity/controllers/timeEntry"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+	"github.com/atomic-blend/backend/productivity/utils/patch"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Patch applies a batch of patches targeting any type of item
+// @Summary Sync patch
+// @Description Apply create, update and delete patches on tasks, notes, habits, habit entries, folders, tags, time entries and saved filters. Each patch is reported as a success, an error or a conflict.
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param patches body []patchmodels.Patch true "Patches"
+// @Success 200 {object} patchmodels.PatchResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /sync/patch [post]
+func (c *Controller) Patch(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var patchs []patchmodels.Patch
+	if err := ctx.ShouldBindJSON(&patchs); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.processor(authUser).Apply(ctx, authUser.UserID, patchs))
+}
+
+// processor returns a patch processor handling every item type on behalf of the user
+func (c *Controller) processor(authUser *auth.UserAuthInfo) *patch.Processor {
+	processor := patch.NewProcessor()
+	processor.Register(patchmodels.ItemTypeTask, tasks.NewPatchAdapter(c.taskRepo, c.userClient))
+	processor.Register(patchmodels.ItemTypeNote, notes.NewPatchAdapter(c.noteRepo))
+	processor.Register(patchmodels.ItemTypeHabit, habits.NewPatchAdapter(c.habitRepo, authUser))
+	processor.Register(patchmodels.ItemTypeHabitEntry, habits.NewEntryPatchAdapter(c.habitRepo))
+	processor.Register(patchmodels.ItemTypeFolder, folder.NewPatchAdapter(c.folderRepo))
+	processor.Register(patchmodels.ItemTypeTag, tags.NewPatchAdapter(c.tagRepo, c.taskRepo, authUser))
+	processor.Register(patchmodels.ItemTypeTimeEntry, timeentrycontroller.NewPatchAdapter(c.timeEntryRepo, c.taskRepo))
+	processor.Register(patchmodels.ItemTypeSavedFilter, filters.NewPatchAdapter(c.filterRepo))
+	return processor
+}
@@ -0,0 +1,326 @@
+package synccontroller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type testRepos struct {
+	task      *mocks.MockTaskRepository
+	note      *mocks.MockNoteRepository
+	habit     *mocks.MockHabitRepository
+	folder    *mocks.MockFolderRepository
+	tag       *mocks.MockTagRepository
+	timeEntry *mocks.MockTimeEntryRepository
+	filter    *mocks.MockSavedFilterRepository
+	change    *mocks.MockChangeRepository
+}
+
+func setupTest() (*Controller, *testRepos) {
+	gin.SetMode(gin.TestMode)
+	repos := &testRepos{
+		task:      new(mocks.MockTaskRepository),
+		note:      new(mocks.MockNoteRepository),
+		habit:     new(mocks.MockHabitRepository),
+		folder:    new(mocks.MockFolderRepository),
+		tag:       new(mocks.MockTagRepository),
+		timeEntry: new(mocks.MockTimeEntryRepository),
+		filter:    new(mocks.MockSavedFilterRepository),
+		change:    new(mocks.MockChangeRepository),
+	}
+	controller := NewSyncController(repos.task, repos.note, repos.habit, repos.folder, repos.tag, repos.timeEntry, repos.filter, repos.change, nil)
+	return controller, repos
+}
+
+func doPatch(controller *Controller, userID *primitive.ObjectID, body interface{}) *httptest.ResponseRecorder {
+	patchJSON, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/sync/patch", bytes.NewBuffer(patchJSON))
+	req.Header.Set("Content-Type", "application/json")
+
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	if userID != nil {
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: *userID})
+	}
+
+	controller.Patch(ctx)
+	return w
+}
+
+func TestPatch(t *testing.T) {
+	t.Run("mixed item types", func(t *testing.T) {
+		controller, repos := setupTest()
+		userID := primitive.NewObjectID()
+		now := primitive.NewDateTimeFromTime(time.Now())
+		hourAgo := primitive.NewDateTimeFromTime(time.Now().Add(-1 * time.Hour))
+
+		// create a folder
+		createFolder := patchmodels.Patch{
+			ID:        primitive.NewObjectID(),
+			Action:    patchmodels.PatchActionCreate,
+			ItemType:  patchmodels.ItemTypeFolder,
+			Changes:   []patchmodels.PatchChange{{Key: "data", Value: map[string]interface{}{"name": "Work"}}},
+			PatchDate: &now,
+		}
+		repos.folder.On("Create", mock.Anything, mock.MatchedBy(func(folder *models.Folder) bool {
+			return folder.Name == "Work" && folder.UserID == userID
+		})).Return(&models.Folder{}, nil).Once()
+
+		// rename a tag
+		tagID := primitive.NewObjectID()
+		renameTag := patchmodels.Patch{
+			ID:        primitive.NewObjectID(),
+			Action:    patchmodels.PatchActionUpdate,
+			ItemType:  patchmodels.ItemTypeTag,
+			ItemID:    &tagID,
+			Changes:   []patchmodels.PatchChange{{Key: "name", Value: "urgent"}, {Key: "userId", Value: primitive.NewObjectID().Hex()}},
+			PatchDate: &now,
+		}
+		repos.tag.On("GetByID", mock.Anything, tagID).Return(&models.Tag{ID: &tagID, UserID: &userID, Name: "todo", UpdatedAt: &hourAgo}, nil).Once()
+		repos.tag.On("Update", mock.Anything, mock.MatchedBy(func(tag *models.Tag) bool {
+			return tag.Name == "urgent" && *tag.UserID == userID
+		})).Return(&models.Tag{}, nil).Once()
+
+		// delete a habit entry
+		entryID := primitive.NewObjectID()
+		deleteEntry := patchmodels.Patch{
+			ID:        primitive.NewObjectID(),
+			Action:    patchmodels.PatchActionDelete,
+			ItemType:  patchmodels.ItemTypeHabitEntry,
+			ItemID:    &entryID,
+			Changes:   []patchmodels.PatchChange{},
+			PatchDate: &now,
+		}
+		repos.habit.On("GetEntryByID", mock.Anything, entryID).Return(&models.HabitEntry{ID: entryID, UserID: userID}, nil).Once()
+		repos.habit.On("DeleteEntry", mock.Anything, entryID).Return(nil).Once()
+
+		// delete a time entry of another user
+		timeEntryID := primitive.NewObjectID()
+		otherUserID := primitive.NewObjectID()
+		deleteTimeEntry := patchmodels.Patch{
+			ID:        primitive.NewObjectID(),
+			Action:    patchmodels.PatchActionDelete,
+			ItemType:  patchmodels.ItemTypeTimeEntry,
+			ItemID:    &timeEntryID,
+			Changes:   []patchmodels.PatchChange{},
+			PatchDate: &now,
+		}
+		repos.timeEntry.On("GetByID", mock.Anything, timeEntryID.Hex()).Return(&models.TimeEntry{ID: &timeEntryID, User: &otherUserID}, nil).Once()
+
+		// unknown item type
+		unknown := patchmodels.Patch{
+			ID:        primitive.NewObjectID(),
+			Action:    patchmodels.PatchActionCreate,
+			ItemType:  "calendar",
+			Changes:   []patchmodels.PatchChange{},
+			PatchDate: &now,
+		}
+
+		w := doPatch(controller, &userID, []patchmodels.Patch{createFolder, renameTag, deleteEntry, deleteTimeEntry, unknown})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response patchmodels.PatchResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{createFolder.ID.Hex(), renameTag.ID.Hex(), deleteEntry.ID.Hex()}, response.Success)
+		assert.Equal(t, []patchmodels.PatchError{
+			{PatchID: deleteTimeEntry.ID.Hex(), ErrorCode: "not_authorized"},
+			{PatchID: unknown.ID.Hex(), ErrorCode: "item_type_not_supported"},
+		}, response.Errors)
+		assert.Len(t, response.Conflicts, 0)
+		repos.folder.AssertExpectations(t)
+		repos.tag.AssertExpectations(t)
+		repos.habit.AssertExpectations(t)
+	})
+
+	t.Run("conflict on outdated patch", func(t *testing.T) {
+		controller, repos := setupTest()
+		userID := primitive.NewObjectID()
+		folderID := primitive.NewObjectID()
+		now := primitive.NewDateTimeFromTime(time.Now())
+		hourAgo := primitive.NewDateTimeFromTime(time.Now().Add(-1 * time.Hour))
+
+		patch := patchmodels.Patch{
+			ID:        primitive.NewObjectID(),
+			Action:    patchmodels.PatchActionUpdate,
+			ItemType:  patchmodels.ItemTypeFolder,
+			ItemID:    &folderID,
+			Changes:   []patchmodels.PatchChange{{Key: "name", Value: "Home"}},
+			PatchDate: &hourAgo,
+		}
+		repos.folder.On("GetByID", mock.Anything, folderID).Return(&models.Folder{ID: &folderID, UserID: userID, Name: "Work", UpdatedAt: &now}, nil).Once()
+
+		w := doPatch(controller, &userID, []patchmodels.Patch{patch})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response patchmodels.PatchResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Success, 0)
+		assert.Len(t, response.Conflicts, 1)
+		assert.Equal(t, patchmodels.ItemTypeFolder, response.Conflicts[0].Type)
+		assert.Equal(t, patch.ID.Hex(), response.Conflicts[0].PatchID)
+		repos.folder.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid create data", func(t *testing.T) {
+		controller, repos := setupTest()
+		userID := primitive.NewObjectID()
+		now := primitive.NewDateTimeFromTime(time.Now())
+
+		patch := patchmodels.Patch{
+			ID:        primitive.NewObjectID(),
+			Action:    patchmodels.PatchActionCreate,
+			ItemType:  patchmodels.ItemTypeTimeEntry,
+			Changes:   []patchmodels.PatchChange{{Key: "data", Value: map[string]interface{}{"note": "missing dates"}}},
+			PatchDate: &now,
+		}
+
+		w := doPatch(controller, &userID, []patchmodels.Patch{patch})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response patchmodels.PatchResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []patchmodels.PatchError{{PatchID: patch.ID.Hex(), ErrorCode: "invalid_time_entry_data"}}, response.Errors)
+		repos.timeEntry.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("time entry of the task of another user", func(t *testing.T) {
+		controller, repos := setupTest()
+		userID := primitive.NewObjectID()
+		taskID := primitive.NewObjectID()
+		now := primitive.NewDateTimeFromTime(time.Now())
+
+		otherTask := patchmodels.Patch{
+			ID:        primitive.NewObjectID(),
+			Action:    patchmodels.PatchActionCreate,
+			ItemType:  patchmodels.ItemTypeTimeEntry,
+			Changes:   []patchmodels.PatchChange{{Key: "data", Value: map[string]interface{}{"startDate": "2025-05-28T10:00:00Z", "endDate": "2025-05-28T12:00:00Z", "taskId": taskID.Hex()}}},
+			PatchDate: &now,
+		}
+		runningTimer := patchmodels.Patch{
+			ID:        primitive.NewObjectID(),
+			Action:    patchmodels.PatchActionCreate,
+			ItemType:  patchmodels.ItemTypeTimeEntry,
+			Changes:   []patchmodels.PatchChange{{Key: "data", Value: map[string]interface{}{"startDate": "2025-05-28T10:00:00Z", "endDate": "2025-05-28T12:00:00Z", "timerState": models.TimerStateRunning}}},
+			PatchDate: &now,
+		}
+		repos.task.On("GetByID", mock.Anything, taskID.Hex()).Return(&models.TaskEntity{User: primitive.NewObjectID()}, nil).Once()
+
+		w := doPatch(controller, &userID, []patchmodels.Patch{otherTask, runningTimer})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response patchmodels.PatchResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []patchmodels.PatchError{
+			{PatchID: otherTask.ID.Hex(), ErrorCode: "not_authorized"},
+			{PatchID: runningTimer.ID.Hex(), ErrorCode: "invalid_timer_state"},
+		}, response.Errors)
+		repos.timeEntry.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("folder with the parent of another user", func(t *testing.T) {
+		controller, repos := setupTest()
+		userID := primitive.NewObjectID()
+		folderID := primitive.NewObjectID()
+		parentID := primitive.NewObjectID()
+		now := primitive.NewDateTimeFromTime(time.Now())
+		hourAgo := primitive.NewDateTimeFromTime(time.Now().Add(-1 * time.Hour))
+
+		create := patchmodels.Patch{
+			ID:        primitive.NewObjectID(),
+			Action:    patchmodels.PatchActionCreate,
+			ItemType:  patchmodels.ItemTypeFolder,
+			Changes:   []patchmodels.PatchChange{{Key: "data", Value: map[string]interface{}{"name": "Work", "parentId": parentID.Hex()}}},
+			PatchDate: &now,
+		}
+		update := patchmodels.Patch{
+			ID:        primitive.NewObjectID(),
+			Action:    patchmodels.PatchActionUpdate,
+			ItemType:  patchmodels.ItemTypeFolder,
+			ItemID:    &folderID,
+			Changes:   []patchmodels.PatchChange{{Key: "parentId", Value: parentID.Hex()}},
+			PatchDate: &now,
+		}
+		repos.folder.On("GetAll", mock.Anything, userID).Return([]*models.Folder{{ID: &folderID, UserID: userID, Name: "Home"}}, nil)
+		repos.folder.On("GetByID", mock.Anything, folderID).Return(&models.Folder{ID: &folderID, UserID: userID, Name: "Home", UpdatedAt: &hourAgo}, nil).Once()
+
+		w := doPatch(controller, &userID, []patchmodels.Patch{create, update})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response patchmodels.PatchResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []patchmodels.PatchError{
+			{PatchID: create.ID.Hex(), ErrorCode: "invalid_parent"},
+			{PatchID: update.ID.Hex(), ErrorCode: "invalid_parent"},
+		}, response.Errors)
+		repos.folder.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		repos.folder.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("saved filter", func(t *testing.T) {
+		controller, repos := setupTest()
+		userID := primitive.NewObjectID()
+		filterID := primitive.NewObjectID()
+		now := primitive.NewDateTimeFromTime(time.Now())
+		hourAgo := primitive.NewDateTimeFromTime(time.Now().Add(-1 * time.Hour))
+
+		create := patchmodels.Patch{
+			ID:        primitive.NewObjectID(),
+			Action:    patchmodels.PatchActionCreate,
+			ItemType:  patchmodels.ItemTypeSavedFilter,
+			Changes:   []patchmodels.PatchChange{{Key: "data", Value: map[string]interface{}{"name": "Urgent", "filter": map[string]interface{}{"priorities": []int{3}}}}},
+			PatchDate: &now,
+		}
+		repos.filter.On("Create", mock.Anything, mock.MatchedBy(func(filter *models.SavedFilter) bool {
+			return filter.Name == "Urgent" && filter.UserID == userID && len(filter.Filter.Priorities) == 1
+		})).Return(&models.SavedFilter{}, nil).Once()
+
+		invalidSort := patchmodels.Patch{
+			ID:        primitive.NewObjectID(),
+			Action:    patchmodels.PatchActionUpdate,
+			ItemType:  patchmodels.ItemTypeSavedFilter,
+			ItemID:    &filterID,
+			Changes:   []patchmodels.PatchChange{{Key: "sort", Value: []interface{}{map[string]interface{}{"key": "color"}}}},
+			PatchDate: &now,
+		}
+		repos.filter.On("GetByID", mock.Anything, filterID).Return(&models.SavedFilter{ID: &filterID, UserID: userID, Name: "Urgent", UpdatedAt: &hourAgo}, nil).Once()
+
+		w := doPatch(controller, &userID, []patchmodels.Patch{create, invalidSort})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response patchmodels.PatchResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Success, 1)
+		assert.Equal(t, []patchmodels.PatchError{{PatchID: invalidSort.ID.Hex(), ErrorCode: "invalid_saved_filter_data"}}, response.Errors)
+		repos.filter.AssertExpectations(t)
+		repos.filter.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unauthorized - no auth user", func(t *testing.T) {
+		controller, _ := setupTest()
+
+		w := doPatch(controller, nil, []patchmodels.Patch{})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
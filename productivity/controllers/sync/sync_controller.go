@@ -0,0 +1,95 @@
+package synccontroller
+
+import (
+	"github.com/atomic-blend/backend/productivity/repositories"
+	userclient "github.com/atomic-blend/backend/shared/grpc/user"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Controller handles the synchronization of all the productivity items of a user
+type Controller struct {
+	taskRepo      repositories.TaskRepositoryInterface
+	noteRepo      repositories.NoteRepositoryInterface
+	habitRepo     repositories.HabitRepositoryInterface
+	folderRepo    repositories.FolderRepositoryInterface
+	tagRepo       repositories.TagRepositoryInterface
+	timeEntryRepo repositories.TimeEntryRepositoryInterface
+	filterRepo    repositories.SavedFilterRepositoryInterface
+	changeRepo    repositories.ChangeRepositoryInterface
+	userClient    userclient.Interface
+}
+
+// NewSyncController creates a new sync controller instance
+func NewSyncController(
+	taskRepo repositories.TaskRepositoryInterface,
+	noteRepo repositories.NoteRepositoryInterface,
+	habitRepo repositories.HabitRepositoryInterface,
+	folderRepo repositories.FolderRepositoryInterface,
+	tagRepo repositories.TagRepositoryInterface,
+	timeEntryRepo repositories.TimeEntryRepositoryInterface,
+	filterRepo repositories.SavedFilterRepositoryInterface,
+	changeRepo repositories.ChangeRepositoryInterface,
+	userClient userclient.Interface,
+) *Controller {
+	return &Controller{
+		taskRepo:      taskRepo,
+		noteRepo:      noteRepo,
+		habitRepo:     habitRepo,
+		folderRepo:    folderRepo,
+		tagRepo:       tagRepo,
+		timeEntryRepo: timeEntryRepo,
+		filterRepo:    filterRepo,
+		changeRepo:    changeRepo,
+		userClient:    userClient,
+	}
+}
+
+// SetupRoutes sets up the sync routes
+func SetupRoutes(router *gin.Engine, database *mongo.Database) {
+	var userClient userclient.Interface
+	if client, err := userclient.NewUserClient(); err == nil {
+		userClient = client
+	}
+	syncController := NewSyncController(
+		repositories.NewTaskRepository(database),
+		repositories.NewNoteRepository(database),
+		repositories.NewHabitRepository(database),
+		repositories.NewFolderRepository(database),
+		repositories.NewTagRepository(database),
+		repositories.NewTimeEntryRepository(database),
+		repositories.NewSavedFilterRepository(database),
+		repositories.NewChangeRepository(database),
+		userClient,
+	)
+	setupSyncRoutes(router, syncController)
+}
+
+// SetupRoutesWithMock sets up the sync routes with mock repositories for testing
+func SetupRoutesWithMock(
+	router *gin.Engine,
+	taskRepo repositories.TaskRepositoryInterface,
+	noteRepo repositories.NoteRepositoryInterface,
+	habitRepo repositories.HabitRepositoryInterface,
+	folderRepo repositories.FolderRepositoryInterface,
+	tagRepo repositories.TagRepositoryInterface,
+	timeEntryRepo repositories.TimeEntryRepositoryInterface,
+	filterRepo repositories.SavedFilterRepositoryInterface,
+	changeRepo repositories.ChangeRepositoryInterface,
+	userClient userclient.Interface,
+) {
+	syncController := NewSyncController(taskRepo, noteRepo, habitRepo, folderRepo, tagRepo, timeEntryRepo, filterRepo, changeRepo, userClient)
+	setupSyncRoutes(router, syncController)
+}
+
+// setupSyncRoutes sets up the routes for sync controller
+func setupSyncRoutes(router *gin.Engine, syncController *Controller) {
+	syncRoutes := router.Group("/sync")
+	auth.RequireAuth(syncRoutes)
+	{
+		syncRoutes.POST("/patch", syncController.Patch)
+		syncRoutes.GET("/changes", syncController.GetChanges)
+	}
+}
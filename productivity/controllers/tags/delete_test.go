@@ -9,8 +9,6 @@ import (
 
 	"testing"
 
-	"github.com/atomic-blend/backend/productivity/models"
-
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -30,58 +28,10 @@ func TestDeleteTag(t *testing.T) {
 		tag.ID = &tagID
 		tag.UserID = &userID // This needs to be a valid ObjectID
 
-		// Create mock tasks with the tag
-		tagID1 := tagID
-		tagID2 := primitive.NewObjectID()
-		tag1 := &models.Tag{
-			ID:     &tagID1,
-			UserID: &userID,
-			Name:   "Test Tag 1",
-		}
-		tag2 := &models.Tag{
-			ID:     &tagID2,
-			UserID: &userID,
-			Name:   "Test Tag 2",
-		}
-
-		tags1 := []*models.Tag{tag1, tag2}
-		tags2 := []*models.Tag{tag2}
-
-		task1 := &models.TaskEntity{
-			ID:   primitive.NewObjectID().Hex(),
-			User: userID,
-			Tags: &tags1,
-		}
-		task2 := &models.TaskEntity{
-			ID:   primitive.NewObjectID().Hex(),
-			User: userID,
-			Tags: &tags2,
-		}
-		tasks := []*models.TaskEntity{task1, task2}
-
-		// Set up mock expectations
+		// Set up mock expectations, the tag is removed from the tasks before being deleted
 		mockTagRepo.On("GetByID", mock.Anything, tagID).Return(tag, nil).Once()
-		mockTaskRepo.On("GetAll", mock.Anything, mock.MatchedBy(func(u *primitive.ObjectID) bool {
-			return *u == userID
-		}), mock.Anything, mock.Anything).Return(tasks, int64(len(tasks)), nil).Once()
-
-		// Task1 should be updated since it contains the tag
-		mockTaskRepo.On("Update", mock.Anything, task1.ID, mock.MatchedBy(func(t *models.TaskEntity) bool {
-			// The updated task should have the tag removed
-			if t.Tags == nil {
-				return false
-			}
-
-			// Check that the tag with tagID was removed
-			for _, tag := range *t.Tags {
-				if tag.ID != nil && *tag.ID == tagID {
-					return false
-				}
-			}
-			return len(*t.Tags) == 1
-		})).Return(task1, nil).Once()
-
-		mockTagRepo.On("Delete", mock.Anything, tagID).Return(nil).Once()
+		removed := mockTaskRepo.On("RemoveTag", mock.Anything, userID, tagID).Return(nil).Once()
+		mockTagRepo.On("Delete", mock.Anything, tagID).Return(nil).Once().NotBefore(removed)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("DELETE", "/tags/"+tagID.Hex(), nil)
@@ -103,106 +53,6 @@ func TestDeleteTag(t *testing.T) {
 		mockTaskRepo.AssertExpectations(t)
 	})
 
-	t.Run("successful delete tag - some tasks don't have the tag", func(t *testing.T) {
-		// Create authenticated user
-		userID := primitive.NewObjectID()
-		tagID := primitive.NewObjectID()
-
-		// Create tag
-		tag := createTestTag()
-		tag.ID = &tagID
-		tag.UserID = &userID
-
-		// Create tasks with different tag scenarios
-		tagID1 := tagID
-		otherTagID := primitive.NewObjectID()
-		anotherTagID := primitive.NewObjectID()
-
-		tag1 := &models.Tag{
-			ID:     &tagID1,
-			UserID: &userID,
-			Name:   "Tag to delete",
-		}
-		otherTag := &models.Tag{
-			ID:     &otherTagID,
-			UserID: &userID,
-			Name:   "Other Tag",
-		}
-		anotherTag := &models.Tag{
-			ID:     &anotherTagID,
-			UserID: &userID,
-			Name:   "Another Tag",
-		}
-
-		// Task1 has the tag to be deleted and another tag
-		tags1 := []*models.Tag{tag1, otherTag}
-		// Task2 has a different tag
-		tags2 := []*models.Tag{anotherTag}
-
-		task1 := &models.TaskEntity{
-			ID:   primitive.NewObjectID().Hex(),
-			User: userID,
-			Tags: &tags1, // Has the tag being deleted
-		}
-		task2 := &models.TaskEntity{
-			ID:   primitive.NewObjectID().Hex(),
-			User: userID,
-			Tags: &tags2, // Doesn't have the tag
-		}
-		task3 := &models.TaskEntity{
-			ID:   primitive.NewObjectID().Hex(),
-			User: userID,
-			Tags: nil, // No tags at all
-		}
-		tasks := []*models.TaskEntity{task1, task2, task3}
-
-		// Set up mock expectations
-		mockTagRepo.On("GetByID", mock.Anything, tagID).Return(tag, nil).Once()
-		mockTaskRepo.On("GetAll", mock.Anything, mock.MatchedBy(func(u *primitive.ObjectID) bool {
-			return *u == userID
-		}), mock.Anything, mock.Anything).Return(tasks, int64(len(tasks)), nil).Once()
-
-		// Only task1 should be updated since it's the only one containing the tag
-		mockTaskRepo.On("Update", mock.Anything, task1.ID, mock.MatchedBy(func(t *models.TaskEntity) bool {
-			// The updated task should have the tag removed
-			if t.Tags == nil {
-				return false
-			}
-
-			// Check that the tag with tagID was removed
-			for _, tag := range *t.Tags {
-				if tag.ID != nil && *tag.ID == tagID {
-					return false
-				}
-			}
-			return len(*t.Tags) == 1
-		})).Return(task1, nil).Once()
-
-		// Task2 and task3 should not be updated
-		// No mock expectations for updating them
-
-		mockTagRepo.On("Delete", mock.Anything, tagID).Return(nil).Once()
-
-		w := httptest.NewRecorder()
-		req, _ := http.NewRequest("DELETE", "/tags/"+tagID.Hex(), nil)
-
-		// Create a new context with the request and set auth user
-		ctx, _ := gin.CreateTestContext(w)
-		ctx.Request = req
-		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
-
-		// Copy params from original request to the new context
-		ctx.Params = []gin.Param{{Key: "id", Value: tagID.Hex()}}
-
-		// Call the controller directly
-		controller := NewTagController(mockTagRepo, mockTaskRepo)
-		controller.DeleteTag(ctx)
-
-		assert.Equal(t, http.StatusOK, w.Code)
-		mockTagRepo.AssertExpectations(t)
-		mockTaskRepo.AssertExpectations(t)
-	})
-
 	t.Run("unauthorized access - no auth user", func(t *testing.T) {
 		tagID := primitive.NewObjectID().Hex()
 
@@ -319,7 +169,7 @@ func TestDeleteTag(t *testing.T) {
 		assert.Equal(t, http.StatusForbidden, w.Code)
 	})
 
-	t.Run("error getting tasks", func(t *testing.T) {
+	t.Run("error removing the tag from tasks", func(t *testing.T) {
 		userID := primitive.NewObjectID()
 		tagID := primitive.NewObjectID()
 
@@ -328,67 +178,7 @@ func TestDeleteTag(t *testing.T) {
 		tag.UserID = &userID
 
 		mockTagRepo.On("GetByID", mock.Anything, tagID).Return(tag, nil).Once()
-		mockTaskRepo.On("GetAll", mock.Anything, mock.MatchedBy(func(u *primitive.ObjectID) bool {
-			return *u == userID
-		}), mock.Anything, mock.Anything).Return(nil, int64(0), errors.New("database error")).Once()
-
-		w := httptest.NewRecorder()
-		req, _ := http.NewRequest("DELETE", "/tags/"+tagID.Hex(), nil)
-
-		// Create a new context with the request and set auth user
-		ctx, _ := gin.CreateTestContext(w)
-		ctx.Request = req
-		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
-
-		// Copy params from original request to the new context
-		ctx.Params = []gin.Param{{Key: "id", Value: tagID.Hex()}}
-
-		// Call the controller directly
-		controller := NewTagController(mockTagRepo, mockTaskRepo)
-		controller.DeleteTag(ctx)
-
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
-	})
-
-	t.Run("error updating task", func(t *testing.T) {
-		userID := primitive.NewObjectID()
-		tagID := primitive.NewObjectID()
-
-		tag := createTestTag()
-		tag.ID = &tagID
-		tag.UserID = &userID
-
-		// Create a Tag object for the task
-		taskTag := &models.Tag{
-			ID:     &tagID,
-			UserID: &userID,
-			Name:   "Test Tag",
-		}
-
-		// Create another Tag object
-		otherTagID := primitive.NewObjectID()
-		otherTag := &models.Tag{
-			ID:     &otherTagID,
-			UserID: &userID,
-			Name:   "Other Tag",
-		}
-
-		// Create a slice of Tag objects
-		tags := []*models.Tag{taskTag, otherTag}
-
-		task := &models.TaskEntity{
-			ID:   primitive.NewObjectID().Hex(),
-			User: userID,
-			Tags: &tags,
-		}
-		tasks := []*models.TaskEntity{task}
-
-		mockTagRepo.On("GetByID", mock.Anything, tagID).Return(tag, nil).Once()
-		mockTaskRepo.On("GetAll", mock.Anything, mock.MatchedBy(func(u *primitive.ObjectID) bool {
-			return *u == userID
-		}), mock.Anything, mock.Anything).Return(tasks, int64(len(tasks)), nil).Once()
-
-		mockTaskRepo.On("Update", mock.Anything, task.ID, mock.Anything).Return(nil, errors.New("database error")).Once()
+		mockTaskRepo.On("RemoveTag", mock.Anything, userID, tagID).Return(errors.New("database error")).Once()
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("DELETE", "/tags/"+tagID.Hex(), nil)
@@ -406,6 +196,7 @@ func TestDeleteTag(t *testing.T) {
 		controller.DeleteTag(ctx)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockTagRepo.AssertNotCalled(t, "Delete", mock.Anything, tagID)
 	})
 
 	t.Run("internal server error on delete", func(t *testing.T) {
@@ -416,13 +207,8 @@ func TestDeleteTag(t *testing.T) {
 		tag.ID = &tagID
 		tag.UserID = &userID
 
-		// No tasks have this tag
-		tasks := []*models.TaskEntity{}
-
 		mockTagRepo.On("GetByID", mock.Anything, tagID).Return(tag, nil).Once()
-		mockTaskRepo.On("GetAll", mock.Anything, mock.MatchedBy(func(u *primitive.ObjectID) bool {
-			return *u == userID
-		}), mock.Anything, mock.Anything).Return(tasks, int64(len(tasks)), nil).Once()
+		mockTaskRepo.On("RemoveTag", mock.Anything, userID, tagID).Return(nil).Once()
 		mockTagRepo.On("Delete", mock.Anything, tagID).Return(errors.New("database error")).Once()
 
 		w := httptest.NewRecorder()
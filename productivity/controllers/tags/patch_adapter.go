@@ -0,0 +1,83 @@
+package tags
+
+import (
+	"context"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/utils/patch"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// patchAdapter applies patches to tags
+type patchAdapter struct {
+	controller *TagController
+	authUser   *auth.UserAuthInfo
+}
+
+// NewPatchAdapter creates the patch adapter for tags.
+// The authenticated user is needed to enforce the tag limit of users without a subscription.
+func NewPatchAdapter(tagRepo repositories.TagRepositoryInterface, taskRepo repositories.TaskRepositoryInterface, authUser *auth.UserAuthInfo) patch.Adapter {
+	return &patchAdapter{controller: NewTagController(tagRepo, taskRepo), authUser: authUser}
+}
+
+func (a *patchAdapter) Get(ctx context.Context, id primitive.ObjectID) (*patch.Item, error) {
+	tag, err := a.controller.tagRepo.GetByID(ctx, id)
+	if err != nil || tag == nil || tag.UserID == nil {
+		return nil, err
+	}
+	item := &patch.Item{ID: id, Object: tag, Owner: *tag.UserID}
+	if tag.UpdatedAt != nil {
+		item.UpdatedAt = tag.UpdatedAt.Time()
+	}
+	return item, nil
+}
+
+func (a *patchAdapter) New() interface{} {
+	return &models.Tag{}
+}
+
+func (a *patchAdapter) Create(ctx context.Context, userID primitive.ObjectID, object interface{}) error {
+	tag := object.(*models.Tag)
+
+	userTags, err := a.controller.tagRepo.GetAll(ctx, &userID)
+	if err != nil {
+		return err
+	}
+
+	// check if the user is subscribed if he have more than 5 tags
+	if len(userTags) >= 5 && a.authUser != nil && a.authUser.Claims != nil {
+		if a.authUser.Claims.UserID != nil && !*a.authUser.Claims.IsSubscribed {
+			return patch.NewError("tag_limit_reached")
+		}
+	}
+
+	tag.UserID = &userID
+	now := primitive.NewDateTimeFromTime(time.Now())
+	tag.CreatedAt = &now
+	tag.UpdatedAt = &now
+
+	_, err = a.controller.tagRepo.Create(ctx, tag)
+	return err
+}
+
+func (a *patchAdapter) Update(ctx context.Context, p *patchmodels.Patch, item *patch.Item) error {
+	tag := item.Object.(*models.Tag)
+	if err := patch.ApplyChanges(tag, p.Changes); err != nil {
+		return err
+	}
+
+	_, err := a.controller.tagRepo.Update(ctx, tag)
+	return err
+}
+
+func (a *patchAdapter) Delete(ctx context.Context, item *patch.Item) error {
+	if err := a.controller.removeTagFromTasks(ctx, item.Owner, item.ID); err != nil {
+		return err
+	}
+	return a.controller.tagRepo.Delete(ctx, item.ID)
+}
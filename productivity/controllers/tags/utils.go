@@ -1,18 +1,16 @@
 package tags
 
 import (
-	"github.com/atomic-blend/backend/productivity/models"
+	"context"
+	"errors"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// removeTagFromSlice filters out a specific tag ID from a slice of Tag objects
-func removeTagFromSlice(tags []*models.Tag, tagToRemove primitive.ObjectID) []*models.Tag {
-	result := make([]*models.Tag, 0, len(tags))
-	for _, tag := range tags {
-		if tag.ID != nil && *tag.ID != tagToRemove {
-			result = append(result, tag)
-		}
+// removeTagFromTasks removes a tag from all the tasks of a user
+func (c *TagController) removeTagFromTasks(ctx context.Context, userID primitive.ObjectID, tagID primitive.ObjectID) error {
+	if err := c.taskRepo.RemoveTag(ctx, userID, tagID); err != nil {
+		return errors.New("Error removing the tag from tasks: " + err.Error())
 	}
-	return result
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
 	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/recurrence"
 
 	"github.com/gin-gonic/gin"
 )
@@ -37,46 +38,30 @@ func (c *TaskController) CreateTask(ctx *gin.Context) {
 	// Set task owner to authenticated user
 	task.User = authUser.UserID
 
+	// Validate the parent task if the task is a subtask
+	if task.ParentID != nil {
+		if err := c.validateParent(ctx, *task.ParentID, authUser.UserID, ""); err != nil {
+			ctx.JSON(parentErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Validate the recurrence rule if any is provided
+	if err := recurrence.ValidateTask(&task); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence: " + err.Error()})
+		return
+	}
+
 	// Set default values if needed
 	if task.Completed == nil {
 		completed := false
 		task.Completed = &completed
 	}
 
-	// Validate tags if any are provided
-	if task.Tags != nil && len(*task.Tags) > 0 {
-		var validatedTags []*models.Tag
-
-		// Check if all tags exist and belong to the user
-		for _, tag := range *task.Tags {
-			if tag.ID == nil {
-				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Tag ID is required"})
-				return
-			}
-
-			// Fetch the tag from the database to verify it exists
-			dbTag, err := c.tagRepo.GetByID(ctx, *tag.ID)
-			if err != nil {
-				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error validating tags: " + err.Error()})
-				return
-			}
-			if dbTag == nil {
-				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Tag not found: " + tag.ID.Hex()})
-				return
-			}
-
-			// Make sure the tag belongs to the user
-			if dbTag.UserID == nil || *dbTag.UserID != authUser.UserID {
-				ctx.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to use this tag: " + tag.ID.Hex()})
-				return
-			}
-
-			// Add the validated tag from the database
-			validatedTags = append(validatedTags, dbTag)
-		}
-
-		// Replace the tags with validated tags from the database
-		task.Tags = &validatedTags
+	// Validate the tags, the task references them by ID
+	if status, err := c.resolveTags(ctx, authUser.UserID, &task); err != nil {
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
 	}
 
 	createdTask, err := c.taskRepo.Create(ctx, &task)
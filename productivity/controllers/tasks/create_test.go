@@ -54,7 +54,7 @@ func TestCreateTask(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly with our context that has auth
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.CreateTask(ctx)
 
 		assert.Equal(t, http.StatusCreated, w.Code)
@@ -81,7 +81,7 @@ func TestCreateTask(t *testing.T) {
 		ctx.Request = req
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.CreateTask(ctx)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -102,7 +102,7 @@ func TestCreateTask(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.CreateTask(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -131,7 +131,7 @@ func TestCreateTask(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.CreateTask(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -170,7 +170,7 @@ func TestCreateTask(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.CreateTask(ctx)
 
 		assert.Equal(t, http.StatusForbidden, w.Code)
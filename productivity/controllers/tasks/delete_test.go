@@ -43,7 +43,7 @@ func TestDeleteTask(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
 
 		// Call the controller directly with our context that has auth
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.DeleteTask(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -61,7 +61,7 @@ func TestDeleteTask(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.DeleteTask(ctx)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -82,7 +82,7 @@ func TestDeleteTask(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: ""}}
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.DeleteTask(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -106,7 +106,7 @@ func TestDeleteTask(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.DeleteTask(ctx)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
@@ -130,7 +130,7 @@ func TestDeleteTask(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.DeleteTask(ctx)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
@@ -159,7 +159,7 @@ func TestDeleteTask(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.DeleteTask(ctx)
 
 		assert.Equal(t, http.StatusForbidden, w.Code)
@@ -188,7 +188,7 @@ func TestDeleteTask(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.DeleteTask(ctx)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -0,0 +1,119 @@
+package tasks
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// parseTaskQuery builds the task filter and sort criteria from the query parameters of the request.
+// The filter is nil when no filtering parameter is provided.
+func parseTaskQuery(ctx *gin.Context) (*models.TaskFilter, []models.TaskSort, error) {
+	filter := &models.TaskFilter{}
+	filtered := false
+
+	if value := ctx.Query("completed"); value != "" {
+		completed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, nil, errors.New("invalid completed parameter")
+		}
+		filter.Completed = &completed
+		filtered = true
+	}
+
+	if value := ctx.Query("overdue"); value != "" {
+		overdue, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, nil, errors.New("invalid overdue parameter")
+		}
+		// overdue tasks are open, they cannot be completed too
+		if overdue && filter.Completed != nil && *filter.Completed {
+			return nil, nil, errors.New("overdue and completed parameters conflict")
+		}
+		filter.Overdue = &overdue
+		filtered = true
+	}
+
+	if value := ctx.Query("dueFrom"); value != "" {
+		dueFrom, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, nil, errors.New("invalid dueFrom parameter, expected ISO8601 format")
+		}
+		date := primitive.NewDateTimeFromTime(dueFrom)
+		filter.DueFrom = &date
+		filtered = true
+	}
+
+	if value := ctx.Query("dueTo"); value != "" {
+		dueTo, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, nil, errors.New("invalid dueTo parameter, expected ISO8601 format")
+		}
+		date := primitive.NewDateTimeFromTime(dueTo)
+		filter.DueTo = &date
+		filtered = true
+	}
+
+	if value := ctx.Query("priority"); value != "" {
+		for _, item := range strings.Split(value, ",") {
+			priority, err := strconv.Atoi(strings.TrimSpace(item))
+			if err != nil {
+				return nil, nil, errors.New("invalid priority parameter")
+			}
+			filter.Priorities = append(filter.Priorities, priority)
+		}
+		filtered = true
+	}
+
+	if value := ctx.Query("tags"); value != "" {
+		for _, item := range strings.Split(value, ",") {
+			tagID, err := primitive.ObjectIDFromHex(strings.TrimSpace(item))
+			if err != nil {
+				return nil, nil, errors.New("invalid tags parameter")
+			}
+			filter.TagIDs = append(filter.TagIDs, tagID)
+		}
+		filtered = true
+	}
+
+	if value := ctx.Query("folderId"); value != "" {
+		folderID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return nil, nil, errors.New("invalid folderId parameter")
+		}
+		filter.FolderID = &folderID
+		filtered = true
+	}
+
+	var sorts []models.TaskSort
+	if value := ctx.Query("sort"); value != "" {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			sort := models.TaskSort{Key: strings.TrimPrefix(item, "-"), Descending: strings.HasPrefix(item, "-")}
+			if !containString(models.ValidTaskSortKeys, sort.Key) {
+				return nil, nil, errors.New("invalid sort key: " + sort.Key)
+			}
+			sorts = append(sorts, sort)
+		}
+	}
+
+	if !filtered {
+		filter = nil
+	}
+	return filter, sorts, nil
+}
+
+func containString(slice []string, item string) bool {
+	for _, v := range slice {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
@@ -3,13 +3,19 @@ package tasks
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/recurrence"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
 )
 
+// maxOccurrenceDays is the longest occurrence window that can be requested
+const maxOccurrenceDays = 366
+
 // PaginatedTaskResponse represents the paginated response for tasks
 type PaginatedTaskResponse struct {
 	Tasks      []*models.TaskEntity `json:"tasks"`
@@ -17,16 +23,30 @@ type PaginatedTaskResponse struct {
 	Page       int64                `json:"page"`
 	Size       int64                `json:"size"`
 	TotalPages int64                `json:"total_pages"`
+	// Occurrences contains the expanded occurrences of the recurring tasks when a from/to window is requested
+	Occurrences []*models.TaskOccurrence `json:"occurrences,omitempty"`
 }
 
 // GetAllTasks retrieves tasks for the authenticated user with optional pagination
 // @Summary Get all tasks
-// @Description Get tasks for the authenticated user with optional pagination. If both page and limit are provided, returns paginated results with total count. If either is missing, returns all tasks.
+// @Description Get tasks for the authenticated user with optional pagination. If both page and limit are provided, returns paginated results with total count. If either is missing, returns all tasks. If both from and to are provided, the occurrences of recurring tasks within that window are returned as well.
 // @Tags Tasks
 // @Produce json
 // @Param page query int false "Page number (1-based)"
 // @Param limit query int false "Number of tasks per page"
+// @Param from query string false "Start of the occurrence window in ISO8601 format, requires to, at most 366 days before to"
+// @Param to query string false "End of the occurrence window in ISO8601 format, requires from"
+// @Param timezone query string false "IANA timezone the occurrences are computed in, overriding the timezone of the user's devices"
+// @Param completed query bool false "Only return completed (true) or open (false) tasks"
+// @Param overdue query bool false "Only return open tasks whose due date has passed (true) or the others (false), true conflicts with completed=true"
+// @Param dueFrom query string false "Only return tasks due after this date, in ISO8601 format"
+// @Param dueTo query string false "Only return tasks due before this date, in ISO8601 format"
+// @Param priority query string false "Comma separated list of priorities"
+// @Param tags query string false "Comma separated list of tag IDs, tasks having any of them are returned"
+// @Param folderId query string false "Only return tasks of this folder"
+// @Param sort query string false "Comma separated list of sort keys (createdAt, updatedAt, startDate, endDate, priority, title), prefixed by - for descending order"
 // @Success 200 {object} PaginatedTaskResponse
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /tasks [get]
@@ -63,8 +83,49 @@ func (c *TaskController) GetAllTasks(ctx *gin.Context) {
 		limit = &limitVal
 	}
 
+	// Parse the optional occurrence window
+	fromStr := ctx.Query("from")
+	toStr := ctx.Query("to")
+	var from, to time.Time
+	loc := time.UTC
+	expand := fromStr != "" || toStr != ""
+	if expand {
+		from, err = time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from parameter. Expected ISO8601 format (e.g., 2024-01-01T00:00:00Z)"})
+			return
+		}
+		to, err = time.Parse(time.RFC3339, toStr)
+		if err != nil || to.Before(from) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to parameter. Expected ISO8601 format (e.g., 2024-01-01T00:00:00Z) after from"})
+			return
+		}
+		if to.Sub(from) > maxOccurrenceDays*24*time.Hour {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "The occurrence window cannot exceed 366 days"})
+			return
+		}
+		var ok bool
+		if loc, ok = c.location(ctx, authUser.UserID); !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+			return
+		}
+	}
+
+	// Parse the optional filter and sort criteria
+	filter, sorts, err := parseTaskQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Get tasks with pagination
-	tasks, totalCount, err := c.taskRepo.GetAll(ctx, &authUser.UserID, page, limit)
+	var tasks []*models.TaskEntity
+	var totalCount int64
+	if filter != nil || len(sorts) > 0 {
+		tasks, totalCount, err = c.taskRepo.Search(ctx, &authUser.UserID, filter, sorts, page, limit)
+	} else {
+		tasks, totalCount, err = c.taskRepo.GetAll(ctx, &authUser.UserID, page, limit)
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -81,6 +142,19 @@ func (c *TaskController) GetAllTasks(ctx *gin.Context) {
 		TotalCount: totalCount,
 	}
 
+	// Expand the recurring tasks within the requested window
+	if expand {
+		response.Occurrences = []*models.TaskOccurrence{}
+		for _, task := range tasks {
+			occurrences, err := recurrence.ExpandTaskIn(task, from, to, loc)
+			if err != nil {
+				log.Error().Err(err).Str("task_id", task.ID).Msg("Failed to expand the occurrences of a recurring task")
+				continue
+			}
+			response.Occurrences = append(response.Occurrences, occurrences...)
+		}
+	}
+
 	// Add pagination metadata if both page and limit were provided
 	if page != nil && limit != nil {
 		totalPages := (totalCount + *limit - 1) / *limit // Ceiling division
@@ -6,11 +6,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 
+	"connectrpc.com/connect"
+	userv1 "github.com/atomic-blend/backend/grpc/gen/user/v1"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
 
 	"testing"
+	"time"
 
 	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -49,7 +53,7 @@ func TestGetAllTasks(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly with our context that has auth
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.GetAllTasks(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -82,7 +86,7 @@ func TestGetAllTasks(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly with our context that has auth
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.GetAllTasks(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -102,7 +106,7 @@ func TestGetAllTasks(t *testing.T) {
 		ctx.Request = req
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.GetAllTasks(ctx)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -125,7 +129,7 @@ func TestGetAllTasks(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly with our context that has auth
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.GetAllTasks(ctx)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -159,7 +163,7 @@ func TestGetAllTasks(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly with our context that has auth
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.GetAllTasks(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -186,7 +190,7 @@ func TestGetAllTasks(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly with our context that has auth
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.GetAllTasks(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -205,9 +209,207 @@ func TestGetAllTasks(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the controller directly with our context that has auth
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.GetAllTasks(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
+	t.Run("successful get all tasks - with recurring occurrences", func(t *testing.T) {
+		// Create authenticated user
+		userID := primitive.NewObjectID()
+
+		start := primitive.NewDateTimeFromTime(time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC))
+		rule := "FREQ=DAILY"
+		recurringTask := createTestTask()
+		recurringTask.ID = primitive.NewObjectID().Hex()
+		recurringTask.User = userID
+		recurringTask.StartDate = &start
+		recurringTask.EndDate = nil
+		recurringTask.Reminders = nil
+		recurringTask.Recurrence = &rule
+
+		plainTask := createTestTask()
+		plainTask.ID = primitive.NewObjectID().Hex()
+		plainTask.User = userID
+
+		tasks := []*models.TaskEntity{recurringTask, plainTask}
+
+		mockTaskRepo.On("GetAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tasks, int64(2), nil).Once()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/tasks?from=2025-01-06T00:00:00Z&to=2025-01-08T23:59:59Z", nil)
+
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = req
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.GetAllTasks(ctx)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response PaginatedTaskResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Tasks, 2)
+		assert.Len(t, response.Occurrences, 3)
+		for _, occurrence := range response.Occurrences {
+			assert.Equal(t, recurringTask.ID, occurrence.TaskID)
+		}
+	})
+
+	t.Run("recurring occurrences in the timezone of the user", func(t *testing.T) {
+		// Create authenticated user
+		userID := primitive.NewObjectID()
+
+		paris, err := time.LoadLocation("Europe/Paris")
+		assert.NoError(t, err)
+		// weekly at 09:00 in Paris, across the switch to summer time
+		start := primitive.NewDateTimeFromTime(time.Date(2025, 3, 24, 9, 0, 0, 0, paris))
+		rule := "FREQ=WEEKLY"
+		recurringTask := createTestTask()
+		recurringTask.ID = primitive.NewObjectID().Hex()
+		recurringTask.User = userID
+		recurringTask.StartDate = &start
+		recurringTask.EndDate = nil
+		recurringTask.Reminders = nil
+		recurringTask.Recurrence = &rule
+
+		mockTaskRepo.On("GetAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*models.TaskEntity{recurringTask}, int64(1), nil).Once()
+		timezone := "Europe/Paris"
+		mockUserClient := new(mocks.MockUserClient)
+		mockUserClient.On("GetUserDevices", mock.Anything, mock.Anything).Return(connect.NewResponse(&userv1.GetUserDevicesResponse{
+			Devices: []*userv1.UserDevice{{DeviceTimezone: &timezone}},
+		}), nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/tasks?from=2025-03-30T00:00:00Z&to=2025-04-06T00:00:00Z", nil)
+
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = req
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, mockUserClient)
+		controller.GetAllTasks(ctx)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response PaginatedTaskResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		if assert.Len(t, response.Occurrences, 1) {
+			assert.Equal(t, time.Date(2025, 3, 31, 9, 0, 0, 0, paris), response.Occurrences[0].StartDate.Time().In(paris))
+		}
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		// Create authenticated user
+		userID := primitive.NewObjectID()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/tasks?from=2025-01-06T00:00:00Z&to=2025-01-08T23:59:59Z&timezone=Not/AZone", nil)
+
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = req
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.GetAllTasks(ctx)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid occurrence window", func(t *testing.T) {
+		// Create authenticated user
+		userID := primitive.NewObjectID()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/tasks?from=2025-01-06T00:00:00Z", nil)
+
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = req
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.GetAllTasks(ctx)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("occurrence window too large", func(t *testing.T) {
+		// Create authenticated user
+		userID := primitive.NewObjectID()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/tasks?from=2025-01-01T00:00:00Z&to=2026-01-03T00:00:00Z", nil)
+
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = req
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.GetAllTasks(ctx)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("successful get all tasks - with filter and sort", func(t *testing.T) {
+		// Create authenticated user
+		userID := primitive.NewObjectID()
+		folderID := primitive.NewObjectID()
+
+		task := createTestTask()
+		task.ID = primitive.NewObjectID().Hex()
+		task.User = userID
+
+		mockTaskRepo.On("Search", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *models.TaskFilter) bool {
+			return filter != nil && !*filter.Completed && *filter.FolderID == folderID && len(filter.Priorities) == 2
+		}), []models.TaskSort{{Key: models.TaskSortPriority, Descending: true}, {Key: models.TaskSortEndDate}}, mock.Anything, mock.Anything).Return([]*models.TaskEntity{task}, int64(1), nil).Once()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/tasks?completed=false&folderId="+folderID.Hex()+"&priority=1,2&sort=-priority,endDate", nil)
+
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = req
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.GetAllTasks(ctx)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response PaginatedTaskResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Tasks, 1)
+		assert.Equal(t, int64(1), response.TotalCount)
+	})
+
+	t.Run("invalid sort key", func(t *testing.T) {
+		// Create authenticated user
+		userID := primitive.NewObjectID()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/tasks?sort=color", nil)
+
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = req
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.GetAllTasks(ctx)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("conflicting overdue and completed filters", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/tasks?completed=true&overdue=true", nil)
+
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = req
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.GetAllTasks(ctx)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "overdue and completed parameters conflict")
+	})
 }
@@ -40,7 +40,7 @@ func TestGetTaskByID(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
 
 		// Call the controller directly with our context that has auth
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.GetTaskByID(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -65,7 +65,7 @@ func TestGetTaskByID(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.GetTaskByID(ctx)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -85,7 +85,7 @@ func TestGetTaskByID(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: ""}}
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.GetTaskByID(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -108,7 +108,7 @@ func TestGetTaskByID(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.GetTaskByID(ctx)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
@@ -131,7 +131,7 @@ func TestGetTaskByID(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
 
 		// Call the controller directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.GetTaskByID(ctx)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -29,7 +29,7 @@ func TestGetTasksSince(t *testing.T) {
 		// Mock repository response
 		mockTaskRepo.On("GetSince", mock.Anything, userID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("*int64"), mock.AnythingOfType("*int64")).Return(tasks, totalCount, nil).Once()
 
-		controller := NewTaskController(mockTaskRepo, nil)
+		controller := NewTaskController(mockTaskRepo, nil, nil)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -61,7 +61,7 @@ func TestGetTasksSince(t *testing.T) {
 	t.Run("missing since parameter", func(t *testing.T) {
 		userID := primitive.NewObjectID()
 
-		controller := NewTaskController(mockTaskRepo, nil)
+		controller := NewTaskController(mockTaskRepo, nil, nil)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -87,7 +87,7 @@ func TestGetTasksSince(t *testing.T) {
 	t.Run("invalid date format", func(t *testing.T) {
 		userID := primitive.NewObjectID()
 
-		controller := NewTaskController(mockTaskRepo, nil)
+		controller := NewTaskController(mockTaskRepo, nil, nil)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -111,7 +111,7 @@ func TestGetTasksSince(t *testing.T) {
 	})
 
 	t.Run("unauthorized request", func(t *testing.T) {
-		controller := NewTaskController(mockTaskRepo, nil)
+		controller := NewTaskController(mockTaskRepo, nil, nil)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -135,7 +135,7 @@ func TestGetTasksSince(t *testing.T) {
 		// Mock repository error
 		mockTaskRepo.On("GetSince", mock.Anything, userID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("*int64"), mock.AnythingOfType("*int64")).Return(nil, int64(0), assert.AnError).Once()
 
-		controller := NewTaskController(mockTaskRepo, nil)
+		controller := NewTaskController(mockTaskRepo, nil, nil)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -170,7 +170,7 @@ func TestGetTasksSince(t *testing.T) {
 		// Mock repository response
 		mockTaskRepo.On("GetSince", mock.Anything, userID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("*int64"), mock.AnythingOfType("*int64")).Return(tasks, totalCount, nil).Once()
 
-		controller := NewTaskController(mockTaskRepo, nil)
+		controller := NewTaskController(mockTaskRepo, nil, nil)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -207,7 +207,7 @@ func TestGetTasksSince(t *testing.T) {
 		// Mock repository response - when no pagination, page and limit are nil
 		mockTaskRepo.On("GetSince", mock.Anything, userID, mock.AnythingOfType("time.Time"), (*int64)(nil), (*int64)(nil)).Return(tasks, totalCount, nil).Once()
 
-		controller := NewTaskController(mockTaskRepo, nil)
+		controller := NewTaskController(mockTaskRepo, nil, nil)
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
@@ -2,25 +2,24 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
-	"github.com/atomic-blend/backend/shared/middlewares/auth"
+	"time"
+
 	"github.com/atomic-blend/backend/productivity/models"
 	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
-	"time"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/utils/patch"
+	"github.com/atomic-blend/backend/productivity/utils/recurrence"
+	"github.com/atomic-blend/backend/productivity/utils/timezone"
+	userclient "github.com/atomic-blend/backend/shared/grpc/user"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // PatchResponse represents the response structure for the patch operation
-type PatchResponse struct {
-	Success   []string                     `json:"success"`
-	Errors    []patchmodels.PatchError     `json:"errors"`
-	Conflicts []patchmodels.ConflictedItem `json:"conflicts"`
-	Date      time.Time                    `json:"date"`
-}
+type PatchResponse = patchmodels.PatchResponse
 
 // Patch handles the patching of tasks
 func (c *TaskController) Patch(ctx *gin.Context) {
@@ -30,7 +29,6 @@ func (c *TaskController) Patch(ctx *gin.Context) {
 		return
 	}
 
-	var ct = context.TODO()
 	// parse the request body into a Patch struct
 	var patchs []patchmodels.Patch
 	if err := ctx.ShouldBindJSON(&patchs); err != nil {
@@ -38,152 +36,119 @@ func (c *TaskController) Patch(ctx *gin.Context) {
 		return
 	}
 
-	var success = make([]string, 0)
-	var errors = make([]patchmodels.PatchError, 0)
-	var conflicts = make([]patchmodels.ConflictedItem, 0)
+	processor := patch.NewProcessor()
+	processor.Register(patchmodels.ItemTypeTask, &patchAdapter{controller: c})
 
-	for _, patch := range patchs {
-		//check if patch type is task
-		if patch.ItemType != patchmodels.ItemTypeTask {
-			errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "item_type_not_supported"})
-			continue
+	ctx.JSON(200, processor.Apply(ctx, authUser.UserID, patchs))
+}
+
+// patchAdapter applies patches to tasks
+type patchAdapter struct {
+	controller *TaskController
+}
+
+// NewPatchAdapter creates the patch adapter for tasks, the user client giving the timezone recurring tasks advance in
+func NewPatchAdapter(taskRepo repositories.TaskRepositoryInterface, userClient userclient.Interface) patch.Adapter {
+	return &patchAdapter{controller: &TaskController{taskRepo: taskRepo, userClient: userClient}}
+}
+
+func (a *patchAdapter) Get(ctx context.Context, id primitive.ObjectID) (*patch.Item, error) {
+	task, err := a.controller.taskRepo.GetByID(ctx, id.Hex())
+	if err != nil || task == nil {
+		return nil, err
+	}
+	item := &patch.Item{ID: id, Object: task, Owner: task.User, UpdatedAt: task.UpdatedAt.Time()}
+	if task.FieldUpdatedAt != nil {
+		item.FieldUpdatedAt = func(key string) time.Time {
+			return task.FieldUpdatedAt.LastModified(key, task.CreatedAt).Time()
 		}
+	}
+	return item, nil
+}
 
-		// check if action is contained in validPatchActions
-		if !containString(patchmodels.ValidPatchActions, patch.Action) {
-			errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "invalid_action"})
-			continue
+func (a *patchAdapter) New() interface{} {
+	return &models.TaskEntity{}
+}
+
+func (a *patchAdapter) Create(ctx context.Context, userID primitive.ObjectID, object interface{}) error {
+	newTask := object.(*models.TaskEntity)
+
+	if newTask.ParentID != nil {
+		if err := a.controller.validateParent(ctx, *newTask.ParentID, userID, ""); err != nil {
+			return patch.NewError("invalid_parent")
 		}
+	}
+
+	if err := recurrence.ValidateTask(newTask); err != nil {
+		return patch.ErrInvalidData
+	}
+
+	newTask.User = userID
+	newTask.CreatedAt = primitive.NewDateTimeFromTime(time.Now())
+	newTask.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+
+	_, err := a.controller.taskRepo.Create(ctx, newTask)
+	return err
+}
+
+func (a *patchAdapter) Update(ctx context.Context, p *patchmodels.Patch, item *patch.Item) error {
+	task := item.Object.(*models.TaskEntity)
 
-		if patch.Action != patchmodels.PatchActionCreate {
-			if patch.ItemID == nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "item_id_required"})
-				continue
-			}
-			// get the task by ID
-			task, err := c.taskRepo.GetByID(ct, patch.ItemID.Hex())
-			if err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "task_not_found"})
-				continue
-			}
-
-			// check that the patch is dated after the last update of the task
-			if patch.Action != patchmodels.PatchActionCreate && patch.PatchDate.Time().Before(task.UpdatedAt.Time()) {
-				if patch.Force == nil || !*patch.Force {
-					conflicts = append(conflicts, patchmodels.ConflictedItem{Type: "task", PatchID: patch.ID.Hex(), RemoteObject: task})
-					continue
-				}
-			}
+	// validate the new parent if the task is moved under another task
+	if parentID, ok := patchParentID(p); ok {
+		if parentID == nil {
+			return patch.NewError("invalid_parent")
+		}
+		if err := a.controller.validateParent(ctx, *parentID, task.User, task.ID); err != nil {
+			return patch.NewError("invalid_parent")
 		}
+	}
 
-		switch patch.Action {
-		case patchmodels.PatchActionUpdate:
-			// get the task by ID and check ownership
-			if patch.ItemID == nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "item_id_required"})
-				continue
-			}
-
-			task, err := c.taskRepo.GetByID(ct, patch.ItemID.Hex())
-			if err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "task_not_found"})
-				continue
-			}
-
-			// check ownership
-			if task.User != authUser.UserID {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "not_authorized"})
-				continue
-			}
-
-			// apply changes to the task
-			_, err = c.taskRepo.UpdatePatch(ct, &patch)
-			if err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "update_failed"})
-			} else {
-				success = append(success, patch.ID.Hex())
-			}
-			continue
-		case patchmodels.PatchActionDelete:
-			// get the task by ID and check ownership
-			if patch.ItemID == nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "item_id_required"})
-				continue
-			}
-
-			task, err := c.taskRepo.GetByID(ct, patch.ItemID.Hex())
-			if err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "task_not_found"})
-				continue
-			}
-
-			// check ownership
-			if task.User != authUser.UserID {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: "not_authorized"})
-				continue
-			}
-
-			// delete the task
-			err = c.taskRepo.Delete(ct, patch.ItemID.Hex())
-			if err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(),
-					ErrorCode: "delete_failed"})
-			} else {
-				success = append(success, patch.ID.Hex())
-			}
-			continue
-		case patchmodels.PatchActionCreate:
-			// the content of the task is under the data key of the first change
-			newTask := &models.TaskEntity{}
-
-			// Convert map to json string
-			jsonStr, err := json.Marshal(patch.Changes[0].Value)
-			if err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(),
-					ErrorCode: "invalid_task_data"})
-				continue
-			}
-			if err := json.Unmarshal(jsonStr, newTask); err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(),
-					ErrorCode: "invalid_task_data"})
-				continue
-			}
-
-			validate := validator.New()
-			if err := validate.Struct(newTask); err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(),
-					ErrorCode: "invalid_task_data"})
-				continue
-			}
-
-			newTask.User = authUser.UserID
-			newTask.CreatedAt = primitive.NewDateTimeFromTime(time.Now())
-			newTask.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
-
-			_, err = c.taskRepo.Create(ct, newTask)
-			if err != nil {
-				errors = append(errors, patchmodels.PatchError{PatchID: patch.ID.Hex(),
-					ErrorCode: "create_failed"})
-			} else {
-				success = append(success, patch.ID.Hex())
-			}
-			continue
+	// apply changes to the task
+	updatedTask, err := a.controller.taskRepo.UpdatePatch(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	// completing an occurrence of a recurring task moves it to the next occurrence,
+	// completing the last open subtask may complete the parent
+	wasCompleted := task.Completed != nil && *task.Completed
+	if !wasCompleted && updatedTask != nil && updatedTask.Completed != nil && *updatedTask.Completed {
+		advanced := false
+		if recurrence.IsRecurring(updatedTask) {
+			advanced, err = recurrence.AdvanceTask(updatedTask, timezone.OfUser(ctx, a.controller.userClient, updatedTask.User.Hex()))
+		}
+		if err == nil && advanced {
+			_, err = a.controller.taskRepo.Update(ctx, updatedTask.ID, updatedTask)
 		}
+		if err == nil && !advanced {
+			err = a.controller.completeParentIfDone(ctx, updatedTask)
+		}
+		return err
 	}
+	return nil
+}
 
-	ctx.JSON(200, PatchResponse{
-		Success:   success,
-		Errors:    errors,
-		Conflicts: conflicts,
-		Date:      time.Now(),
-	})
+func (a *patchAdapter) Delete(ctx context.Context, item *patch.Item) error {
+	return a.controller.taskRepo.Delete(ctx, item.ID.Hex())
 }
 
-func containString(slice []string, item string) bool {
-	for _, v := range slice {
-		if v == item {
-			return true
+// patchParentID returns the parent ID set by a patch, if the patch changes the parent of the task.
+// A nil ID with true means the value could not be parsed.
+func patchParentID(patch *patchmodels.Patch) (*primitive.ObjectID, bool) {
+	for _, change := range patch.Changes {
+		if change.Key != "parentId" || change.Value == nil {
+			continue
+		}
+		hex, ok := change.Value.(string)
+		if !ok {
+			return nil, true
+		}
+		parentID, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, true
 		}
+		return &parentID, true
 	}
-	return false
+	return nil, false
 }
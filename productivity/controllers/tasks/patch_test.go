@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
 
+	"github.com/atomic-blend/backend/productivity/models"
 	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
 	"github.com/atomic-blend/backend/productivity/tests/mocks"
 	"testing"
@@ -69,7 +70,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -121,7 +122,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -180,7 +181,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -216,7 +217,7 @@ func TestPatch(t *testing.T) {
 		ctx.Request = req
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -235,7 +236,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -267,7 +268,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -306,7 +307,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -349,7 +350,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -399,7 +400,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -440,7 +441,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -480,7 +481,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -529,7 +530,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -578,7 +579,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -627,7 +628,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -682,7 +683,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -765,7 +766,7 @@ func TestPatch(t *testing.T) {
 		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.Patch(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -786,4 +787,105 @@ func TestPatch(t *testing.T) {
 		assert.Equal(t, patchID3.Hex(), response.Errors[0].PatchID)
 		assert.Equal(t, "invalid_action", response.Errors[0].ErrorCode)
 	})
+	t.Run("outdated patch merged when fields do not collide", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		taskID := primitive.NewObjectID()
+		patchID := primitive.NewObjectID()
+
+		// the description was modified remotely after the patch was made
+		existingTask := createTestTask()
+		existingTask.ID = taskID.Hex()
+		existingTask.User = userID
+		existingTask.CreatedAt = primitive.NewDateTimeFromTime(time.Now().Add(-2 * time.Hour))
+		existingTask.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+		existingTask.FieldUpdatedAt = models.FieldTimestamps{"description": existingTask.UpdatedAt}
+
+		patchDate := primitive.NewDateTimeFromTime(time.Now().Add(-1 * time.Hour))
+		patch := patchmodels.Patch{
+			ID:        patchID,
+			Action:    patchmodels.PatchActionUpdate,
+			ItemType:  patchmodels.ItemTypeTask,
+			ItemID:    &taskID,
+			Changes:   []patchmodels.PatchChange{{Key: "title", Value: "Offline Title"}},
+			PatchDate: &patchDate,
+		}
+
+		mockTaskRepo.On("GetByID", mock.Anything, taskID.Hex()).Return(existingTask, nil)
+		mockTaskRepo.On("UpdatePatch", mock.Anything, &patch).Return(existingTask, nil).Once()
+
+		patchJSON, _ := json.Marshal([]patchmodels.Patch{patch})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/tasks/patch", bytes.NewBuffer(patchJSON))
+		req.Header.Set("Content-Type", "application/json")
+
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = req
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.Patch(ctx)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response PatchResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{patchID.Hex()}, response.Success)
+		assert.Len(t, response.Conflicts, 0)
+	})
+
+	t.Run("outdated patch reports colliding keys only", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		taskID := primitive.NewObjectID()
+		patchID := primitive.NewObjectID()
+
+		// the title was modified remotely after the patch was made
+		existingTask := createTestTask()
+		existingTask.ID = taskID.Hex()
+		existingTask.User = userID
+		existingTask.CreatedAt = primitive.NewDateTimeFromTime(time.Now().Add(-2 * time.Hour))
+		existingTask.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+		existingTask.FieldUpdatedAt = models.FieldTimestamps{"title": existingTask.UpdatedAt}
+
+		patchDate := primitive.NewDateTimeFromTime(time.Now().Add(-1 * time.Hour))
+		patch := patchmodels.Patch{
+			ID:       patchID,
+			Action:   patchmodels.PatchActionUpdate,
+			ItemType: patchmodels.ItemTypeTask,
+			ItemID:   &taskID,
+			Changes: []patchmodels.PatchChange{
+				{Key: "title", Value: "Offline Title"},
+				{Key: "priority", Value: 3},
+			},
+			PatchDate: &patchDate,
+		}
+
+		// only the priority is applied
+		mockTaskRepo.On("GetByID", mock.Anything, taskID.Hex()).Return(existingTask, nil)
+		mockTaskRepo.On("UpdatePatch", mock.Anything, mock.MatchedBy(func(p *patchmodels.Patch) bool {
+			return p.ID == patchID && len(p.Changes) == 1 && p.Changes[0].Key == "priority"
+		})).Return(existingTask, nil).Once()
+
+		patchJSON, _ := json.Marshal([]patchmodels.Patch{patch})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/tasks/patch", bytes.NewBuffer(patchJSON))
+		req.Header.Set("Content-Type", "application/json")
+
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = req
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.Patch(ctx)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response PatchResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Success, 0)
+		assert.Len(t, response.Errors, 0)
+		assert.Len(t, response.Conflicts, 1)
+		assert.Equal(t, patchID.Hex(), response.Conflicts[0].PatchID)
+		assert.Equal(t, []string{"title"}, response.Conflicts[0].ConflictingKeys)
+		mockTaskRepo.AssertExpectations(t)
+	})
 }
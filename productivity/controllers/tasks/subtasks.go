@@ -0,0 +1,305 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxTaskDepth bounds the walk up the task hierarchy when looking for cycles
+const maxTaskDepth = 64
+
+var (
+	errParentNotFound  = errors.New("parent task not found")
+	errParentForbidden = errors.New("you don't have permission to use this parent task")
+	errParentCycle     = errors.New("a task cannot be nested under itself or one of its subtasks")
+)
+
+// ReorderSubtasksRequest represents the body of the reorder subtasks request
+type ReorderSubtasksRequest struct {
+	SubtaskIDs []string `json:"subtaskIds" binding:"required"`
+}
+
+// GetSubtasks retrieves the subtasks of a task
+// @Summary Get subtasks
+// @Description Get the direct subtasks of a task, ordered by position
+// @Tags Tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.TaskEntity
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tasks/{id}/subtasks [get]
+func (c *TaskController) GetSubtasks(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	id := ctx.Param("id")
+	task, err := c.taskRepo.GetByID(ctx, id)
+	if err != nil || task == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	if task.User != authUser.UserID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this task"})
+		return
+	}
+
+	subtasks, err := c.taskRepo.GetChildren(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if subtasks == nil {
+		subtasks = []*models.TaskEntity{}
+	}
+
+	ctx.JSON(http.StatusOK, subtasks)
+}
+
+// CreateSubtask creates a new task under a parent task
+// @Summary Create subtask
+// @Description Create a new task nested under the given task
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Parent task ID"
+// @Param task body models.TaskEntity true "Task"
+// @Success 201 {object} models.TaskEntity
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tasks/{id}/subtasks [post]
+func (c *TaskController) CreateSubtask(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	parentID, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	var task models.TaskEntity
+	if err := ctx.ShouldBindJSON(&task); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.validateParent(ctx, parentID, authUser.UserID, ""); err != nil {
+		ctx.JSON(parentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	task.User = authUser.UserID
+	task.ParentID = &parentID
+	if task.Completed == nil {
+		completed := false
+		task.Completed = &completed
+	}
+
+	// Append the subtask after its siblings unless a position is given
+	if task.Position == nil {
+		siblings, err := c.taskRepo.GetChildren(ctx, parentID.Hex())
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		position := len(siblings)
+		task.Position = &position
+	}
+
+	createdTask, err := c.taskRepo.Create(ctx, &task)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, createdTask)
+}
+
+// ReorderSubtasks changes the order of the subtasks of a task
+// @Summary Reorder subtasks
+// @Description Set the order of the subtasks of a task. Every subtask of the task must be listed exactly once.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param order body ReorderSubtasksRequest true "Ordered subtask IDs"
+// @Success 200 {array} models.TaskEntity
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tasks/{id}/subtasks/order [put]
+func (c *TaskController) ReorderSubtasks(ctx *gin.Context) {
+	authUser := auth.GetAuthUser(ctx)
+	if authUser == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	id := ctx.Param("id")
+	task, err := c.taskRepo.GetByID(ctx, id)
+	if err != nil || task == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	if task.User != authUser.UserID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this task"})
+		return
+	}
+
+	var req ReorderSubtasksRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	subtasks, err := c.taskRepo.GetChildren(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The new order must be a permutation of the current subtasks
+	remaining := make(map[string]bool, len(subtasks))
+	for _, subtask := range subtasks {
+		remaining[subtask.ID] = true
+	}
+	for _, subtaskID := range req.SubtaskIDs {
+		if !remaining[subtaskID] {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Unknown or duplicated subtask: " + subtaskID})
+			return
+		}
+		delete(remaining, subtaskID)
+	}
+	if len(remaining) > 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "All subtasks must be listed"})
+		return
+	}
+
+	if err := c.taskRepo.ReorderChildren(ctx, id, req.SubtaskIDs); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	subtasks, err = c.taskRepo.GetChildren(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subtasks)
+}
+
+// validateParent checks that the parent task exists, belongs to the user and that nesting
+// the task identified by taskID under it would not create a cycle
+func (c *TaskController) validateParent(ctx context.Context, parentID primitive.ObjectID, userID primitive.ObjectID, taskID string) error {
+	parent, err := c.taskRepo.GetByID(ctx, parentID.Hex())
+	if err != nil || parent == nil {
+		return errParentNotFound
+	}
+
+	if parent.User != userID {
+		return errParentForbidden
+	}
+
+	if taskID == "" {
+		return nil
+	}
+
+	// walk up the hierarchy: the task must not be one of the ancestors of its new parent
+	current := parent
+	for depth := 0; current != nil && depth < maxTaskDepth; depth++ {
+		if current.ID == taskID {
+			return errParentCycle
+		}
+		if current.ParentID == nil {
+			return nil
+		}
+		current, err = c.taskRepo.GetByID(ctx, current.ParentID.Hex())
+		if err != nil {
+			return err
+		}
+	}
+
+	if current != nil {
+		return errParentCycle
+	}
+	return nil
+}
+
+// completeParentIfDone completes the parent of a task when all of its subtasks are completed
+// and the parent opted in with CompleteWithSubtasks
+func (c *TaskController) completeParentIfDone(ctx context.Context, task *models.TaskEntity) error {
+	if task == nil || task.ParentID == nil || task.Completed == nil || !*task.Completed {
+		return nil
+	}
+
+	parent, err := c.taskRepo.GetByID(ctx, task.ParentID.Hex())
+	if err != nil || parent == nil {
+		return err
+	}
+
+	if parent.CompleteWithSubtasks == nil || !*parent.CompleteWithSubtasks {
+		return nil
+	}
+	if parent.Completed != nil && *parent.Completed {
+		return nil
+	}
+
+	siblings, err := c.taskRepo.GetChildren(ctx, parent.ID)
+	if err != nil {
+		return err
+	}
+	for _, sibling := range siblings {
+		if sibling.Completed == nil || !*sibling.Completed {
+			return nil
+		}
+	}
+
+	completed := true
+	parent.Completed = &completed
+	updatedParent, err := c.taskRepo.Update(ctx, parent.ID, parent)
+	if err != nil {
+		return err
+	}
+
+	// completion cascades up the hierarchy
+	return c.completeParentIfDone(ctx, updatedParent)
+}
+
+// parentErrorStatus maps a parent validation error to an HTTP status code
+func parentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errParentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errParentForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, errParentCycle):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,244 @@
+package tasks
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetSubtasks(t *testing.T) {
+	_, mockTaskRepo, mockTagRepo := setupTest()
+
+	t.Run("successful get subtasks", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		parentID := primitive.NewObjectID()
+
+		parent := createTestTask()
+		parent.ID = parentID.Hex()
+		parent.User = userID
+
+		child := createTestTask()
+		child.ID = primitive.NewObjectID().Hex()
+		child.User = userID
+		child.ParentID = &parentID
+
+		mockTaskRepo.On("GetByID", mock.Anything, parentID.Hex()).Return(parent, nil).Once()
+		mockTaskRepo.On("GetChildren", mock.Anything, parentID.Hex()).Return([]*models.TaskEntity{child}, nil).Once()
+
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request, _ = http.NewRequest("GET", "/tasks/"+parentID.Hex()+"/subtasks", nil)
+		ctx.Params = []gin.Param{{Key: "id", Value: parentID.Hex()}}
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.GetSubtasks(ctx)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []*models.TaskEntity
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+		assert.Equal(t, child.ID, response[0].ID)
+	})
+
+	t.Run("forbidden - task of another user", func(t *testing.T) {
+		parentID := primitive.NewObjectID()
+		parent := createTestTask()
+		parent.ID = parentID.Hex()
+
+		mockTaskRepo.On("GetByID", mock.Anything, parentID.Hex()).Return(parent, nil).Once()
+
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request, _ = http.NewRequest("GET", "/tasks/"+parentID.Hex()+"/subtasks", nil)
+		ctx.Params = []gin.Param{{Key: "id", Value: parentID.Hex()}}
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: primitive.NewObjectID()})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.GetSubtasks(ctx)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestCreateSubtask(t *testing.T) {
+	_, mockTaskRepo, mockTagRepo := setupTest()
+
+	t.Run("successful create subtask", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		parentID := primitive.NewObjectID()
+
+		parent := createTestTask()
+		parent.ID = parentID.Hex()
+		parent.User = userID
+
+		sibling := createTestTask()
+		sibling.ParentID = &parentID
+
+		mockTaskRepo.On("GetByID", mock.Anything, parentID.Hex()).Return(parent, nil).Once()
+		mockTaskRepo.On("GetChildren", mock.Anything, parentID.Hex()).Return([]*models.TaskEntity{sibling}, nil).Once()
+		mockTaskRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *models.TaskEntity) bool {
+			return task.ParentID != nil && *task.ParentID == parentID && task.User == userID && *task.Position == 1
+		})).Return(createTestTask(), nil).Once()
+
+		body, _ := json.Marshal(map[string]interface{}{"title": "Subtask"})
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request, _ = http.NewRequest("POST", "/tasks/"+parentID.Hex()+"/subtasks", bytes.NewBuffer(body))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		ctx.Params = []gin.Param{{Key: "id", Value: parentID.Hex()}}
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.CreateSubtask(ctx)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockTaskRepo.AssertExpectations(t)
+	})
+
+	t.Run("parent not found", func(t *testing.T) {
+		parentID := primitive.NewObjectID()
+		mockTaskRepo.On("GetByID", mock.Anything, parentID.Hex()).Return(nil, nil).Once()
+
+		body, _ := json.Marshal(map[string]interface{}{"title": "Subtask"})
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request, _ = http.NewRequest("POST", "/tasks/"+parentID.Hex()+"/subtasks", bytes.NewBuffer(body))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		ctx.Params = []gin.Param{{Key: "id", Value: parentID.Hex()}}
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: primitive.NewObjectID()})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.CreateSubtask(ctx)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestReorderSubtasks(t *testing.T) {
+	_, mockTaskRepo, mockTagRepo := setupTest()
+
+	userID := primitive.NewObjectID()
+	parentID := primitive.NewObjectID()
+	parent := createTestTask()
+	parent.ID = parentID.Hex()
+	parent.User = userID
+
+	child1 := createTestTask()
+	child1.ID = primitive.NewObjectID().Hex()
+	child2 := createTestTask()
+	child2.ID = primitive.NewObjectID().Hex()
+
+	t.Run("successful reorder", func(t *testing.T) {
+		mockTaskRepo.On("GetByID", mock.Anything, parentID.Hex()).Return(parent, nil).Once()
+		mockTaskRepo.On("GetChildren", mock.Anything, parentID.Hex()).Return([]*models.TaskEntity{child1, child2}, nil).Twice()
+		mockTaskRepo.On("ReorderChildren", mock.Anything, parentID.Hex(), []string{child2.ID, child1.ID}).Return(nil).Once()
+
+		body, _ := json.Marshal(ReorderSubtasksRequest{SubtaskIDs: []string{child2.ID, child1.ID}})
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request, _ = http.NewRequest("PUT", "/tasks/"+parentID.Hex()+"/subtasks/order", bytes.NewBuffer(body))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		ctx.Params = []gin.Param{{Key: "id", Value: parentID.Hex()}}
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.ReorderSubtasks(ctx)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTaskRepo.AssertExpectations(t)
+	})
+
+	t.Run("missing subtask in order", func(t *testing.T) {
+		mockTaskRepo.On("GetByID", mock.Anything, parentID.Hex()).Return(parent, nil).Once()
+		mockTaskRepo.On("GetChildren", mock.Anything, parentID.Hex()).Return([]*models.TaskEntity{child1, child2}, nil).Once()
+
+		body, _ := json.Marshal(ReorderSubtasksRequest{SubtaskIDs: []string{child2.ID}})
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request, _ = http.NewRequest("PUT", "/tasks/"+parentID.Hex()+"/subtasks/order", bytes.NewBuffer(body))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		ctx.Params = []gin.Param{{Key: "id", Value: parentID.Hex()}}
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.ReorderSubtasks(ctx)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestValidateParent(t *testing.T) {
+	_, mockTaskRepo, mockTagRepo := setupTest()
+	controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+
+	userID := primitive.NewObjectID()
+	rootID := primitive.NewObjectID()
+	childID := primitive.NewObjectID()
+
+	root := createTestTask()
+	root.ID = rootID.Hex()
+	root.User = userID
+
+	child := createTestTask()
+	child.ID = childID.Hex()
+	child.User = userID
+	child.ParentID = &rootID
+
+	mockTaskRepo.On("GetByID", mock.Anything, rootID.Hex()).Return(root, nil)
+	mockTaskRepo.On("GetByID", mock.Anything, childID.Hex()).Return(child, nil)
+
+	t.Run("valid parent", func(t *testing.T) {
+		assert.NoError(t, controller.validateParent(t.Context(), childID, userID, primitive.NewObjectID().Hex()))
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		assert.ErrorIs(t, controller.validateParent(t.Context(), childID, userID, rootID.Hex()), errParentCycle)
+	})
+
+	t.Run("self", func(t *testing.T) {
+		assert.ErrorIs(t, controller.validateParent(t.Context(), rootID, userID, rootID.Hex()), errParentCycle)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		assert.ErrorIs(t, controller.validateParent(t.Context(), rootID, primitive.NewObjectID(), ""), errParentForbidden)
+	})
+}
+
+func TestCompleteParentIfDone(t *testing.T) {
+	_, mockTaskRepo, mockTagRepo := setupTest()
+	controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+
+	completed := true
+	optIn := true
+	parentID := primitive.NewObjectID()
+
+	parent := createTestTask()
+	parent.ID = parentID.Hex()
+	parent.CompleteWithSubtasks = &optIn
+
+	child := createTestTask()
+	child.ID = primitive.NewObjectID().Hex()
+	child.ParentID = &parentID
+	child.Completed = &completed
+
+	mockTaskRepo.On("GetByID", mock.Anything, parentID.Hex()).Return(parent, nil).Once()
+	mockTaskRepo.On("GetChildren", mock.Anything, parentID.Hex()).Return([]*models.TaskEntity{child}, nil).Once()
+	mockTaskRepo.On("Update", mock.Anything, parentID.Hex(), mock.MatchedBy(func(task *models.TaskEntity) bool {
+		return *task.Completed
+	})).Return(parent, nil).Once()
+
+	assert.NoError(t, controller.completeParentIfDone(t.Context(), child))
+	mockTaskRepo.AssertExpectations(t)
+}
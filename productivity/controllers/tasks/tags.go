@@ -0,0 +1,63 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// resolveTags checks that the tags of a task exist and belong to the user, then sets the tag IDs of the task
+// from them. The tags are given either as tag objects or as IDs, the tag objects take precedence.
+// It returns the status of the response and the error when a tag is not valid.
+func (c *TaskController) resolveTags(ctx context.Context, userID primitive.ObjectID, task *models.TaskEntity) (int, error) {
+	ids := task.TagIDs
+	if task.Tags != nil {
+		ids = make([]primitive.ObjectID, 0, len(*task.Tags))
+		for _, tag := range *task.Tags {
+			if tag == nil || tag.ID == nil {
+				return http.StatusBadRequest, errors.New("Tag ID is required")
+			}
+			ids = append(ids, *tag.ID)
+		}
+	}
+
+	validatedTags := make([]*models.Tag, 0, len(ids))
+	tagIDs := make([]primitive.ObjectID, 0, len(ids))
+	seen := map[primitive.ObjectID]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		// Fetch the tag from the database to verify it exists
+		dbTag, err := c.tagRepo.GetByID(ctx, id)
+		if err != nil {
+			return http.StatusInternalServerError, errors.New("Error validating tags: " + err.Error())
+		}
+		if dbTag == nil {
+			return http.StatusBadRequest, errors.New("Tag not found: " + id.Hex())
+		}
+
+		// Make sure the tag belongs to the user
+		if dbTag.UserID == nil || *dbTag.UserID != userID {
+			return http.StatusForbidden, errors.New("You don't have permission to use this tag: " + id.Hex())
+		}
+
+		validatedTags = append(validatedTags, dbTag)
+		tagIDs = append(tagIDs, id)
+	}
+
+	if len(tagIDs) == 0 {
+		task.TagIDs = nil
+		task.Tags = nil
+		return http.StatusOK, nil
+	}
+	task.TagIDs = tagIDs
+	task.Tags = &validatedTags
+	return http.StatusOK, nil
+}
@@ -0,0 +1,86 @@
+package tasks
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestResolveTags(t *testing.T) {
+	userID := primitive.NewObjectID()
+	tagID := primitive.NewObjectID()
+	tag := &models.Tag{ID: &tagID, UserID: &userID, Name: "Work"}
+
+	t.Run("tag IDs", func(t *testing.T) {
+		mockTagRepo := new(mocks.MockTagRepository)
+		controller := NewTaskController(new(mocks.MockTaskRepository), mockTagRepo, nil)
+		mockTagRepo.On("GetByID", mock.Anything, tagID).Return(tag, nil).Once()
+
+		task := &models.TaskEntity{TagIDs: []primitive.ObjectID{tagID, tagID}}
+		status, err := controller.resolveTags(context.Background(), userID, task)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []primitive.ObjectID{tagID}, task.TagIDs)
+		require.NotNil(t, task.Tags)
+		assert.Equal(t, "Work", (*task.Tags)[0].Name)
+		mockTagRepo.AssertExpectations(t)
+	})
+
+	t.Run("tag objects take precedence", func(t *testing.T) {
+		mockTagRepo := new(mocks.MockTagRepository)
+		controller := NewTaskController(new(mocks.MockTaskRepository), mockTagRepo, nil)
+		mockTagRepo.On("GetByID", mock.Anything, tagID).Return(tag, nil).Once()
+
+		tags := []*models.Tag{{ID: &tagID}}
+		task := &models.TaskEntity{TagIDs: []primitive.ObjectID{primitive.NewObjectID()}, Tags: &tags}
+		_, err := controller.resolveTags(context.Background(), userID, task)
+
+		require.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{tagID}, task.TagIDs)
+	})
+
+	t.Run("no tags", func(t *testing.T) {
+		controller := NewTaskController(new(mocks.MockTaskRepository), new(mocks.MockTagRepository), nil)
+
+		tags := []*models.Tag{}
+		task := &models.TaskEntity{Tags: &tags}
+		_, err := controller.resolveTags(context.Background(), userID, task)
+
+		require.NoError(t, err)
+		assert.Nil(t, task.TagIDs)
+		assert.Nil(t, task.Tags)
+	})
+
+	t.Run("tag of another user", func(t *testing.T) {
+		mockTagRepo := new(mocks.MockTagRepository)
+		controller := NewTaskController(new(mocks.MockTaskRepository), mockTagRepo, nil)
+		mockTagRepo.On("GetByID", mock.Anything, tagID).Return(tag, nil).Once()
+
+		task := &models.TaskEntity{TagIDs: []primitive.ObjectID{tagID}}
+		status, err := controller.resolveTags(context.Background(), primitive.NewObjectID(), task)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusForbidden, status)
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		mockTagRepo := new(mocks.MockTagRepository)
+		controller := NewTaskController(new(mocks.MockTaskRepository), mockTagRepo, nil)
+		mockTagRepo.On("GetByID", mock.Anything, tagID).Return(nil, nil).Once()
+
+		task := &models.TaskEntity{TagIDs: []primitive.ObjectID{tagID}}
+		status, err := controller.resolveTags(context.Background(), userID, task)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
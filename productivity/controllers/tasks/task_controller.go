@@ -1,25 +1,33 @@
 package tasks
 
 import (
+	"time"
+
 	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/utils/timezone"
+	userclient "github.com/atomic-blend/backend/shared/grpc/user"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
 	"github.com/webstradev/gin-pagination/v2/pkg/pagination"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // TaskController handles task related operations
 type TaskController struct {
-	taskRepo repositories.TaskRepositoryInterface
-	tagRepo  repositories.TagRepositoryInterface
+	taskRepo   repositories.TaskRepositoryInterface
+	tagRepo    repositories.TagRepositoryInterface
+	userClient userclient.Interface
 }
 
-// NewTaskController creates a new task controller instance
-func NewTaskController(taskRepo repositories.TaskRepositoryInterface, tagRepo repositories.TagRepositoryInterface) *TaskController {
+// NewTaskController creates a new task controller instance.
+// The user client gives the timezone of the users recurring tasks are evaluated in, UTC being used without it.
+func NewTaskController(taskRepo repositories.TaskRepositoryInterface, tagRepo repositories.TagRepositoryInterface, userClient userclient.Interface) *TaskController {
 	return &TaskController{
-		taskRepo: taskRepo,
-		tagRepo:  tagRepo,
+		taskRepo:   taskRepo,
+		tagRepo:    tagRepo,
+		userClient: userClient,
 	}
 }
 
@@ -27,13 +35,17 @@ func NewTaskController(taskRepo repositories.TaskRepositoryInterface, tagRepo re
 func SetupRoutes(router *gin.Engine, database *mongo.Database) {
 	taskRepo := repositories.NewTaskRepository(database)
 	tagRepo := repositories.NewTagRepository(database)
-	taskController := NewTaskController(taskRepo, tagRepo)
+	var userClient userclient.Interface
+	if client, err := userclient.NewUserClient(); err == nil {
+		userClient = client
+	}
+	taskController := NewTaskController(taskRepo, tagRepo, userClient)
 	setupTaskRoutes(router, taskController)
 }
 
 // SetupRoutesWithMock sets up the task routes with a mock repository for testing
-func SetupRoutesWithMock(router *gin.Engine, taskRepo repositories.TaskRepositoryInterface, tagRepo repositories.TagRepositoryInterface) {
-	taskController := NewTaskController(taskRepo, tagRepo)
+func SetupRoutesWithMock(router *gin.Engine, taskRepo repositories.TaskRepositoryInterface, tagRepo repositories.TagRepositoryInterface, userClient userclient.Interface) {
+	taskController := NewTaskController(taskRepo, tagRepo, userClient)
 	setupTaskRoutes(router, taskController)
 }
 
@@ -49,5 +61,19 @@ func setupTaskRoutes(router *gin.Engine, taskController *TaskController) {
 		taskRoutes.PUT("/:id", taskController.UpdateTask)
 		taskRoutes.POST("/patch", taskController.Patch)
 		taskRoutes.DELETE("/:id", taskController.DeleteTask)
+		taskRoutes.GET("/:id/subtasks", taskController.GetSubtasks)
+		taskRoutes.POST("/:id/subtasks", taskController.CreateSubtask)
+		taskRoutes.PUT("/:id/subtasks/order", taskController.ReorderSubtasks)
+	}
+}
+
+// location returns the timezone recurring tasks are evaluated in: the timezone query parameter if set,
+// otherwise the timezone of the user's devices, otherwise UTC.
+// It returns false when the timezone query parameter is not a valid timezone.
+func (c *TaskController) location(ctx *gin.Context, userID primitive.ObjectID) (*time.Location, bool) {
+	if name := ctx.Query("timezone"); name != "" {
+		loc, err := time.LoadLocation(name)
+		return loc, err == nil
 	}
+	return timezone.OfUser(ctx, c.userClient, userID.Hex()), true
 }
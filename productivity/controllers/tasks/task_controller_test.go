@@ -17,7 +17,7 @@ import (
 func TestNewTaskController(t *testing.T) {
 	mockTaskRepo := new(mocks.MockTaskRepository)
 	mockTagRepo := new(mocks.MockTagRepository)
-	controller := NewTaskController(mockTaskRepo, mockTagRepo)
+	controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 
 	assert.NotNil(t, controller)
 	assert.Equal(t, mockTaskRepo, controller.taskRepo)
@@ -30,7 +30,7 @@ func TestSetupRoutesWithMock(t *testing.T) {
 	mockTaskRepo := new(mocks.MockTaskRepository)
 	mockTagRepo := new(mocks.MockTagRepository)
 
-	SetupRoutesWithMock(router, mockTaskRepo, mockTagRepo)
+	SetupRoutesWithMock(router, mockTaskRepo, mockTagRepo, nil)
 
 	// Test that routes are properly registered by making test requests
 	testRoutes := []struct {
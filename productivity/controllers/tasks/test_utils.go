@@ -56,7 +56,7 @@ func setupTest() (*gin.Engine, *mocks.MockTaskRepository, *mocks.MockTagReposito
 	router := gin.New()
 	mockTaskRepo := new(mocks.MockTaskRepository)
 	mockTagRepo := new(mocks.MockTagRepository)
-	taskController := NewTaskController(mockTaskRepo, mockTagRepo)
+	taskController := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 
 	// Set up routes with middleware
 	taskRoutes := router.Group("/tasks")
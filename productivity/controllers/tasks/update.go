@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
 	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/recurrence"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,6 +17,7 @@ import (
 // @Produce json
 // @Param id path string true "Task ID"
 // @Param task body models.TaskEntity true "Task"
+// @Param timezone query string false "IANA timezone a completed recurring task advances in, overriding the timezone of the user's devices"
 // @Success 200 {object} models.TaskEntity
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
@@ -67,40 +69,38 @@ func (c *TaskController) UpdateTask(ctx *gin.Context) {
 	task.User = existingTask.User
 	task.ID = existingTask.ID
 
-	// Validate tags if any are provided
-	if task.Tags != nil && len(*task.Tags) > 0 {
-		var validatedTags []*models.Tag
-
-		// Check if all tags exist and belong to the user
-		for _, tag := range *task.Tags {
-			if tag.ID == nil {
-				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Tag ID is required"})
-				return
-			}
-
-			// Fetch the tag from the database to verify it exists
-			dbTag, err := c.tagRepo.GetByID(ctx, *tag.ID)
-			if err != nil {
-				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error validating tags: " + err.Error()})
-				return
-			}
-			if dbTag == nil {
-				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Tag not found: " + tag.ID.Hex()})
-				return
-			}
-
-			// Make sure the tag belongs to the user
-			if dbTag.UserID == nil || *dbTag.UserID != authUser.UserID {
-				ctx.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to use this tag: " + tag.ID.Hex()})
-				return
-			}
-
-			// Add the validated tag from the database
-			validatedTags = append(validatedTags, dbTag)
+	// Validate the parent task if the task is a subtask
+	if task.ParentID != nil {
+		if err := c.validateParent(ctx, *task.ParentID, authUser.UserID, task.ID); err != nil {
+			ctx.JSON(parentErrorStatus(err), gin.H{"error": err.Error()})
+			return
 		}
+	}
+
+	// Validate the recurrence rule if any is provided
+	if err := recurrence.ValidateTask(&task); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence: " + err.Error()})
+		return
+	}
+
+	// Completing an occurrence of a recurring task moves it to the next occurrence
+	wasCompleted := existingTask.Completed != nil && *existingTask.Completed
+	if !wasCompleted && task.Completed != nil && *task.Completed && recurrence.IsRecurring(&task) {
+		loc, ok := c.location(ctx, authUser.UserID)
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+			return
+		}
+		if _, err := recurrence.AdvanceTask(&task, loc); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence: " + err.Error()})
+			return
+		}
+	}
 
-		// Replace the tags with validated tags from the database
-		task.Tags = &validatedTags
+	// Validate the tags, the task references them by ID
+	if status, err := c.resolveTags(ctx, authUser.UserID, &task); err != nil {
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
 	}
 
 	updatedTask, err := c.taskRepo.Update(ctx, id, &task)
@@ -109,5 +109,13 @@ func (c *TaskController) UpdateTask(ctx *gin.Context) {
 		return
 	}
 
+	// Completing the last open subtask may complete the parent
+	if !wasCompleted {
+		if err := c.completeParentIfDone(ctx, updatedTask); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	ctx.JSON(http.StatusOK, updatedTask)
 }
@@ -88,7 +88,7 @@ func TestUpdateTask(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.UpdateTask(ctx)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -169,7 +169,7 @@ func TestUpdateTask(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.UpdateTask(ctx)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -226,7 +226,7 @@ func TestUpdateTask(t *testing.T) {
 		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
 
 		// Call the handler directly
-		controller := NewTaskController(mockTaskRepo, mockTagRepo)
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
 		controller.UpdateTask(ctx)
 
 		assert.Equal(t, http.StatusForbidden, w.Code)
@@ -235,4 +235,77 @@ func TestUpdateTask(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Contains(t, response["error"], "You don't have permission to use this tag")
 	})
+	t.Run("completing a recurring task moves it to the next occurrence", func(t *testing.T) {
+		// Create authenticated user
+		userID := primitive.NewObjectID()
+		taskID := primitive.NewObjectID().Hex()
+
+		start := primitive.NewDateTimeFromTime(time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC))
+		rule := "FREQ=WEEKLY"
+		existingTask := createTestTask()
+		existingTask.ID = taskID
+		existingTask.User = userID
+		existingTask.Tags = nil
+		existingTask.StartDate = &start
+		existingTask.EndDate = nil
+		existingTask.Reminders = nil
+		existingTask.Recurrence = &rule
+
+		completedTask := *existingTask
+		completed := true
+		completedTask.Completed = &completed
+
+		mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(existingTask, nil).Once()
+		mockTaskRepo.On("Update", mock.Anything, taskID, mock.MatchedBy(func(task *models.TaskEntity) bool {
+			return !*task.Completed && task.StartDate.Time().UTC().Equal(time.Date(2025, 1, 13, 9, 0, 0, 0, time.UTC))
+		})).Return(existingTask, nil).Once()
+
+		taskJSON, _ := json.Marshal(completedTask)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/tasks/"+taskID, bytes.NewBuffer(taskJSON))
+		req.Header.Set("Content-Type", "application/json")
+
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = req
+		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.UpdateTask(ctx)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTaskRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid recurrence rule", func(t *testing.T) {
+		// Create authenticated user
+		userID := primitive.NewObjectID()
+		taskID := primitive.NewObjectID().Hex()
+
+		existingTask := createTestTask()
+		existingTask.ID = taskID
+		existingTask.User = userID
+
+		invalidTask := createTestTask()
+		invalidTask.Tags = nil
+		rule := "FREQ=SOMETIMES"
+		invalidTask.Recurrence = &rule
+
+		mockTaskRepo.On("GetByID", mock.Anything, taskID).Return(existingTask, nil).Once()
+
+		taskJSON, _ := json.Marshal(invalidTask)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/tasks/"+taskID, bytes.NewBuffer(taskJSON))
+		req.Header.Set("Content-Type", "application/json")
+
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = req
+		ctx.Params = []gin.Param{{Key: "id", Value: taskID}}
+		ctx.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+
+		controller := NewTaskController(mockTaskRepo, mockTagRepo, nil)
+		controller.UpdateTask(ctx)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
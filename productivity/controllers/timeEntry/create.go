@@ -25,13 +25,18 @@ func (tc *Controller) Create(c *gin.Context) {
 		return
 	}
 
+	ctx := context.Background()
+	if err := validateTimeEntry(ctx, tc.taskRepository, authUser.UserID, &timeEntry, nil); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
 	// Set user ID and timestamps
 	timeEntry.User = &authUser.UserID
 	now := time.Now().Format(time.RFC3339)
 	timeEntry.CreatedAt = now
 	timeEntry.UpdatedAt = now
 
-	ctx := context.Background()
 	createdTimeEntry, err := tc.timeEntryRepository.Create(ctx, &timeEntry)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create time entry"})
@@ -21,7 +21,7 @@ import (
 
 func TestTimeEntryController_Create(t *testing.T) {
 	mockRepo := new(mocks.MockTimeEntryRepository)
-	controller := NewTimeEntryController(mockRepo)
+	controller := NewTimeEntryController(mockRepo, nil, nil, nil)
 	router := setupTestRouter()
 
 	userID := primitive.NewObjectID()
@@ -66,7 +66,7 @@ func TestTimeEntryController_Create(t *testing.T) {
 
 func TestTimeEntryController_Create_RepositoryError(t *testing.T) {
 	mockRepo := new(mocks.MockTimeEntryRepository)
-	controller := NewTimeEntryController(mockRepo)
+	controller := NewTimeEntryController(mockRepo, nil, nil, nil)
 	router := setupTestRouter()
 
 	userID := primitive.NewObjectID()
@@ -92,3 +92,46 @@ func TestTimeEntryController_Create_RepositoryError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.Code)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestTimeEntryController_Create_Validation(t *testing.T) {
+	userID := primitive.NewObjectID()
+	taskID := primitive.NewObjectID()
+	running := models.TimerStateRunning
+
+	tests := []struct {
+		name       string
+		entry      models.TimeEntry
+		task       *models.TaskEntity
+		wantStatus int
+	}{
+		{"task of another user", models.TimeEntry{TaskID: &taskID}, &models.TaskEntity{User: primitive.NewObjectID()}, http.StatusForbidden},
+		{"unknown task", models.TimeEntry{TaskID: &taskID}, nil, http.StatusNotFound},
+		{"running timer", models.TimeEntry{TimerState: &running}, nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockTimeEntryRepository)
+			taskRepo := new(mocks.MockTaskRepository)
+			controller := NewTimeEntryController(mockRepo, taskRepo, nil, nil)
+			router := setupTestRouter()
+			taskRepo.On("GetByID", mock.Anything, taskID.Hex()).Return(tt.task, nil)
+
+			router.POST("/time-entries", func(c *gin.Context) {
+				c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+				controller.Create(c)
+			})
+
+			tt.entry.StartDate = "2025-05-28T10:00:00Z"
+			tt.entry.EndDate = "2025-05-28T12:00:00Z"
+			body, _ := json.Marshal(tt.entry)
+			req, _ := http.NewRequest("POST", "/time-entries", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.wantStatus, resp.Code)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
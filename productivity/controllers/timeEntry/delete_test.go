@@ -17,7 +17,7 @@ import (
 
 func TestTimeEntryController_Delete(t *testing.T) {
 	mockRepo := new(mocks.MockTimeEntryRepository)
-	controller := NewTimeEntryController(mockRepo)
+	controller := NewTimeEntryController(mockRepo, nil, nil, nil)
 	router := setupTestRouter()
 
 	userID := primitive.NewObjectID()
@@ -17,7 +17,7 @@ import (
 
 func TestTimeEntryController_GetAll(t *testing.T) {
 	mockRepo := new(mocks.MockTimeEntryRepository)
-	controller := NewTimeEntryController(mockRepo)
+	controller := NewTimeEntryController(mockRepo, nil, nil, nil)
 	router := setupTestRouter()
 
 	userID := primitive.NewObjectID()
@@ -49,7 +49,7 @@ func TestTimeEntryController_GetAll(t *testing.T) {
 
 func TestTimeEntryController_GetAll_Unauthorized(t *testing.T) {
 	mockRepo := new(mocks.MockTimeEntryRepository)
-	controller := NewTimeEntryController(mockRepo)
+	controller := NewTimeEntryController(mockRepo, nil, nil, nil)
 	router := setupTestRouter()
 
 	router.GET("/time-entries", controller.GetAll)
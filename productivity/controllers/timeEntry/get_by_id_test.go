@@ -17,7 +17,7 @@ import (
 
 func TestTimeEntryController_GetByID(t *testing.T) {
 	mockRepo := new(mocks.MockTimeEntryRepository)
-	controller := NewTimeEntryController(mockRepo)
+	controller := NewTimeEntryController(mockRepo, nil, nil, nil)
 	router := setupTestRouter()
 
 	userID := primitive.NewObjectID()
@@ -47,7 +47,7 @@ func TestTimeEntryController_GetByID(t *testing.T) {
 
 func TestTimeEntryController_GetByID_Forbidden(t *testing.T) {
 	mockRepo := new(mocks.MockTimeEntryRepository)
-	controller := NewTimeEntryController(mockRepo)
+	controller := NewTimeEntryController(mockRepo, nil, nil, nil)
 	router := setupTestRouter()
 
 	userID := primitive.NewObjectID()
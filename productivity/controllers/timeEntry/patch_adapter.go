@@ -0,0 +1,92 @@
+package timeentrycontroller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/utils/patch"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// patchAdapter applies patches to time entries
+type patchAdapter struct {
+	timeEntryRepository repositories.TimeEntryRepositoryInterface
+	taskRepository      repositories.TaskRepositoryInterface
+}
+
+// NewPatchAdapter creates the patch adapter for time entries
+func NewPatchAdapter(timeEntryRepository repositories.TimeEntryRepositoryInterface, taskRepository repositories.TaskRepositoryInterface) patch.Adapter {
+	return &patchAdapter{timeEntryRepository: timeEntryRepository, taskRepository: taskRepository}
+}
+
+func (a *patchAdapter) Get(ctx context.Context, id primitive.ObjectID) (*patch.Item, error) {
+	timeEntry, err := a.timeEntryRepository.GetByID(ctx, id.Hex())
+	if err != nil || timeEntry == nil || timeEntry.User == nil {
+		return nil, err
+	}
+	return &patch.Item{ID: id, Object: timeEntry, Owner: *timeEntry.User, UpdatedAt: patch.ParseTime(timeEntry.UpdatedAt)}, nil
+}
+
+func (a *patchAdapter) New() interface{} {
+	return &models.TimeEntry{}
+}
+
+func (a *patchAdapter) Create(ctx context.Context, userID primitive.ObjectID, object interface{}) error {
+	timeEntry := object.(*models.TimeEntry)
+	if err := a.validate(ctx, userID, timeEntry, nil); err != nil {
+		return err
+	}
+	timeEntry.User = &userID
+
+	_, err := a.timeEntryRepository.Create(ctx, timeEntry)
+	return err
+}
+
+func (a *patchAdapter) Update(ctx context.Context, p *patchmodels.Patch, item *patch.Item) error {
+	timeEntry := item.Object.(*models.TimeEntry)
+	// the changes are applied in place, the task and timer state are kept to check what changed
+	previous := &models.TimeEntry{}
+	if timeEntry.TaskID != nil {
+		taskID := *timeEntry.TaskID
+		previous.TaskID = &taskID
+	}
+	if timeEntry.TimerState != nil {
+		timerState := *timeEntry.TimerState
+		previous.TimerState = &timerState
+	}
+	if err := patch.ApplyChanges(timeEntry, p.Changes); err != nil {
+		return err
+	}
+	if err := a.validate(ctx, item.Owner, timeEntry, previous); err != nil {
+		return err
+	}
+
+	_, err := a.timeEntryRepository.Update(ctx, item.ID.Hex(), timeEntry)
+	if errors.Is(err, repositories.ErrTimerActive) {
+		return patch.NewError("timer_active")
+	}
+	return err
+}
+
+func (a *patchAdapter) Delete(ctx context.Context, item *patch.Item) error {
+	return a.timeEntryRepository.Delete(ctx, item.ID.Hex())
+}
+
+// validate applies the checks of the time entry endpoints to a time entry of a patch
+func (a *patchAdapter) validate(ctx context.Context, userID primitive.ObjectID, timeEntry *models.TimeEntry, previous *models.TimeEntry) error {
+	err := validateTimeEntry(ctx, a.taskRepository, userID, timeEntry, previous)
+	switch {
+	case errors.Is(err, errTimerState):
+		return patch.NewError("invalid_timer_state")
+	case errors.Is(err, errTaskNotFound):
+		return patch.NewError("task_not_found")
+	case errors.Is(err, errTaskAccessDenied):
+		return patch.NewError("not_authorized")
+	default:
+		return err
+	}
+}
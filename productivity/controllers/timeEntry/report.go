@@ -0,0 +1,186 @@
+package timeentrycontroller
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/timereport"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultReportDays is the range of a report when no dates are given
+const defaultReportDays = 30
+
+// maxReportDays is the longest range of a report
+const maxReportDays = 366
+
+// GetReport aggregates the time tracked by the authenticated user over a date range
+// @Summary Get time tracking report
+// @Description Aggregate the tracked time per task, folder, tag, day, week or month, pomodoro and break time reported separately
+// @Tags TimeEntries
+// @Produce json
+// @Produce text/csv
+// @Param from query string false "Start of the range, RFC3339 or YYYY-MM-DD (default: 30 days before to)"
+// @Param to query string false "End of the range, RFC3339 or YYYY-MM-DD included (default: now)"
+// @Param groupBy query string false "task, folder, tag, day, week or month (default: day)"
+// @Param timezone query string false "IANA timezone of the dates and periods (default: UTC)"
+// @Param format query string false "json or csv (default: json)"
+// @Success 200 {object} models.TimeReport
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /time-entries/report [get]
+func (tc *Controller) GetReport(c *gin.Context) {
+	authUser := auth.GetAuthUser(c)
+	if authUser == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	loc := time.UTC
+	if name := c.Query("timezone"); name != "" {
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+			return
+		}
+		loc = location
+	}
+
+	groupBy := c.DefaultQuery("groupBy", models.ReportGroupDay)
+	if !slices.Contains(models.ValidReportGroups, groupBy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid groupBy"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format"})
+		return
+	}
+
+	from, to, err := reportRange(c.Query("from"), c.Query("to"), loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	timeEntries, err := tc.timeEntryRepository.GetAll(c, &authUser.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve time entries"})
+		return
+	}
+
+	labels, err := tc.reportLabels(c, authUser, groupBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve report labels"})
+		return
+	}
+
+	report := timereport.Build(timeEntries, labels, groupBy, from, to, loc)
+
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="time-report.csv"`)
+		c.Status(http.StatusOK)
+		if err := timereport.WriteCSV(c.Writer, report); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// reportLabels loads the tasks, and the folders or tags when needed, used to group and name the report
+func (tc *Controller) reportLabels(c *gin.Context, authUser *auth.UserAuthInfo, groupBy string) (timereport.Labels, error) {
+	labels := timereport.Labels{
+		Tasks:   map[string]*models.TaskEntity{},
+		Folders: map[string]string{},
+		Tags:    map[string]string{},
+	}
+
+	switch groupBy {
+	case models.ReportGroupTask, models.ReportGroupFolder, models.ReportGroupTag:
+	default:
+		return labels, nil
+	}
+
+	tasks, _, err := tc.taskRepository.GetAll(c, &authUser.UserID, nil, nil)
+	if err != nil {
+		return labels, err
+	}
+	for _, task := range tasks {
+		labels.Tasks[task.ID] = task
+	}
+
+	switch groupBy {
+	case models.ReportGroupFolder:
+		folders, err := tc.folderRepository.GetAll(c, authUser.UserID)
+		if err != nil {
+			return labels, err
+		}
+		for _, folder := range folders {
+			if folder.ID != nil {
+				labels.Folders[folder.ID.Hex()] = folder.Name
+			}
+		}
+	case models.ReportGroupTag:
+		tags, err := tc.tagRepository.GetAll(c, &authUser.UserID)
+		if err != nil {
+			return labels, err
+		}
+		for _, tag := range tags {
+			if tag.ID != nil {
+				labels.Tags[tag.ID.Hex()] = tag.Name
+			}
+		}
+	}
+	return labels, nil
+}
+
+// reportRange parses the range of a report. Dates without a time are days in the given location,
+// the day of the end of the range is included.
+func reportRange(fromValue, toValue string, loc *time.Location) (time.Time, time.Time, error) {
+	to := time.Now()
+	if toValue != "" {
+		date, dateOnly, err := parseReportDate(toValue, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("Invalid to date")
+		}
+		to = date
+		if dateOnly {
+			to = date.AddDate(0, 0, 1)
+		}
+	}
+
+	from := to.AddDate(0, 0, -defaultReportDays)
+	if fromValue != "" {
+		date, _, err := parseReportDate(fromValue, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("Invalid from date")
+		}
+		from = date
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("from must be before to")
+	}
+	if to.Sub(from) > maxReportDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.New("The range of a report cannot exceed 366 days")
+	}
+	return from, to, nil
+}
+
+func parseReportDate(value string, loc *time.Location) (time.Time, bool, error) {
+	if date, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return date, true, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	return date, false, err
+}
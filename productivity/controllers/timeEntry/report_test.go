@@ -0,0 +1,122 @@
+package timeentrycontroller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTimeEntryController_GetReport(t *testing.T) {
+	userID := primitive.NewObjectID()
+	taskID := primitive.NewObjectID()
+	folderID := primitive.NewObjectID()
+	pomodoro := true
+
+	timeEntries := []*models.TimeEntry{
+		{User: &userID, TaskID: &taskID, StartDate: "2025-06-02T10:00:00Z", EndDate: "2025-06-02T11:00:00Z", Pomodoro: &pomodoro},
+		{User: &userID, StartDate: "2025-06-03T10:00:00Z", EndDate: "2025-06-03T10:30:00Z"},
+	}
+	tasks := []*models.TaskEntity{{ID: taskID.Hex(), Title: "Write", FolderID: &folderID}}
+	folders := []*models.Folder{{ID: &folderID, Name: "Work"}}
+
+	serve := func(controller *Controller, url string, authenticated bool) *httptest.ResponseRecorder {
+		router := setupTestRouter()
+		router.GET("/time-entries/report", func(c *gin.Context) {
+			if authenticated {
+				c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+			}
+			controller.GetReport(c)
+		})
+		req, _ := http.NewRequest("GET", url, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("per day", func(t *testing.T) {
+		mockRepo := new(mocks.MockTimeEntryRepository)
+		mockRepo.On("GetAll", mock.Anything, &userID).Return(timeEntries, nil)
+		controller := NewTimeEntryController(mockRepo, nil, nil, nil)
+
+		resp := serve(controller, "/time-entries/report?from=2025-06-02&to=2025-06-04", true)
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var report models.TimeReport
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+		assert.Equal(t, models.ReportGroupDay, report.GroupBy)
+		assert.Len(t, report.Groups, 3)
+		assert.Equal(t, int64(5400), report.Totals.TrackedSeconds)
+		assert.Equal(t, int64(3600), report.Totals.PomodoroSeconds)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("per folder", func(t *testing.T) {
+		mockRepo := new(mocks.MockTimeEntryRepository)
+		mockTaskRepo := new(mocks.MockTaskRepository)
+		mockFolderRepo := new(mocks.MockFolderRepository)
+		mockRepo.On("GetAll", mock.Anything, &userID).Return(timeEntries, nil)
+		mockTaskRepo.On("GetAll", mock.Anything, &userID, (*int64)(nil), (*int64)(nil)).Return(tasks, int64(1), nil)
+		mockFolderRepo.On("GetAll", mock.Anything, userID).Return(folders, nil)
+		controller := NewTimeEntryController(mockRepo, mockTaskRepo, mockFolderRepo, nil)
+
+		resp := serve(controller, "/time-entries/report?from=2025-06-01&to=2025-06-30&groupBy=folder", true)
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var report models.TimeReport
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+		require.Len(t, report.Groups, 2)
+		assert.Equal(t, folderID.Hex(), report.Groups[0].Key)
+		assert.Equal(t, "Work", report.Groups[0].Label)
+		assert.Equal(t, int64(3600), report.Groups[0].TrackedSeconds)
+		assert.Equal(t, "", report.Groups[1].Key)
+		mockTaskRepo.AssertExpectations(t)
+		mockFolderRepo.AssertExpectations(t)
+	})
+
+	t.Run("csv export", func(t *testing.T) {
+		mockRepo := new(mocks.MockTimeEntryRepository)
+		mockRepo.On("GetAll", mock.Anything, &userID).Return(timeEntries, nil)
+		controller := NewTimeEntryController(mockRepo, nil, nil, nil)
+
+		resp := serve(controller, "/time-entries/report?from=2025-06-02&to=2025-06-02&format=csv", true)
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Header().Get("Content-Type"), "text/csv")
+		lines := strings.Split(strings.TrimSpace(resp.Body.String()), "\n")
+		require.Len(t, lines, 3)
+		assert.True(t, strings.HasPrefix(lines[2], "total,Total,"))
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, url := range []string{
+			"/time-entries/report?groupBy=year",
+			"/time-entries/report?timezone=Mars/Olympus",
+			"/time-entries/report?format=xml",
+			"/time-entries/report?from=2025-06-10&to=2025-06-01",
+			"/time-entries/report?from=2024-01-01&to=2025-06-01",
+			"/time-entries/report?from=yesterday",
+		} {
+			controller := NewTimeEntryController(new(mocks.MockTimeEntryRepository), nil, nil, nil)
+			resp := serve(controller, url, true)
+			assert.Equal(t, http.StatusBadRequest, resp.Code, url)
+		}
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		controller := NewTimeEntryController(new(mocks.MockTimeEntryRepository), nil, nil, nil)
+		resp := serve(controller, "/time-entries/report", false)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+}
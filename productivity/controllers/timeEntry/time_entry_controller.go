@@ -11,19 +11,35 @@ import (
 // Controller handles time entry related operations
 type Controller struct {
 	timeEntryRepository repositories.TimeEntryRepositoryInterface
+	taskRepository      repositories.TaskRepositoryInterface
+	folderRepository    repositories.FolderRepositoryInterface
+	tagRepository       repositories.TagRepositoryInterface
 }
 
 // NewTimeEntryController creates a new instance of TimeEntryController
-func NewTimeEntryController(timeEntryRepository repositories.TimeEntryRepositoryInterface) *Controller {
+func NewTimeEntryController(
+	timeEntryRepository repositories.TimeEntryRepositoryInterface,
+	taskRepository repositories.TaskRepositoryInterface,
+	folderRepository repositories.FolderRepositoryInterface,
+	tagRepository repositories.TagRepositoryInterface,
+) *Controller {
 	return &Controller{
 		timeEntryRepository: timeEntryRepository,
+		taskRepository:      taskRepository,
+		folderRepository:    folderRepository,
+		tagRepository:       tagRepository,
 	}
 }
 
 // SetupRoutes configures all time entry related routes
 func SetupRoutes(router *gin.Engine, database *mongo.Database) {
 	timeEntryRepo := repositories.NewTimeEntryRepository(database)
-	timeEntryController := NewTimeEntryController(timeEntryRepo)
+	timeEntryController := NewTimeEntryController(
+		timeEntryRepo,
+		repositories.NewTaskRepository(database),
+		repositories.NewFolderRepository(database),
+		repositories.NewTagRepository(database),
+	)
 
 	// Apply authentication middleware to all time entry routes
 	timeEntryGroup := router.Group("/time-entries")
@@ -31,6 +47,12 @@ func SetupRoutes(router *gin.Engine, database *mongo.Database) {
 	timeEntryGroup.Use(auth.Middleware())
 	{
 		timeEntryGroup.GET("", timeEntryController.GetAll)
+		timeEntryGroup.GET("/report", timeEntryController.GetReport)
+		timeEntryGroup.GET("/timer", timeEntryController.GetTimer)
+		timeEntryGroup.POST("/timer/start", timeEntryController.StartTimer)
+		timeEntryGroup.POST("/timer/pause", timeEntryController.PauseTimer)
+		timeEntryGroup.POST("/timer/resume", timeEntryController.ResumeTimer)
+		timeEntryGroup.POST("/timer/stop", timeEntryController.StopTimer)
 		timeEntryGroup.GET("/:id", timeEntryController.GetByID)
 		timeEntryGroup.POST("", timeEntryController.Create)
 		timeEntryGroup.PUT("/:id", timeEntryController.Update)
@@ -0,0 +1,183 @@
+package timeentrycontroller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/utils/timer"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// StartTimer starts a timer for the authenticated user
+// @Summary Start timer
+// @Description Start a running time entry, a user can only have one running or paused timer
+// @Tags TimeEntries
+// @Accept json
+// @Produce json
+// @Param timer body models.TimerStartRequest false "Task and kind of the time entry"
+// @Success 201 {object} models.TimeEntry
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /time-entries/timer/start [post]
+func (tc *Controller) StartTimer(c *gin.Context) {
+	authUser := auth.GetAuthUser(c)
+	if authUser == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var request models.TimerStartRequest
+	if err := c.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if request.TaskID != nil {
+		task, err := tc.taskRepository.GetByID(c, request.TaskID.Hex())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve task"})
+			return
+		}
+		if task == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+			return
+		}
+		if task.User != authUser.UserID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+	}
+
+	timeEntry := timer.Start(authUser.UserID, &request, time.Now())
+	createdTimeEntry, err := tc.timeEntryRepository.StartTimer(c, timeEntry)
+	if errors.Is(err, repositories.ErrTimerAlreadyRunning) {
+		c.JSON(http.StatusConflict, gin.H{"error": "A timer is already running"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start timer"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, createdTimeEntry)
+}
+
+// GetTimer returns the running or paused timer of the authenticated user
+// @Summary Get timer
+// @Description Get the running or paused timer, its duration computed at the time of the request
+// @Tags TimeEntries
+// @Produce json
+// @Success 200 {object} models.TimeEntry
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /time-entries/timer [get]
+func (tc *Controller) GetTimer(c *gin.Context) {
+	timeEntry := tc.activeTimer(c)
+	if timeEntry == nil {
+		return
+	}
+
+	timer.SetDuration(timeEntry, time.Now())
+	c.JSON(http.StatusOK, timeEntry)
+}
+
+// PauseTimer pauses the running timer of the authenticated user
+// @Summary Pause timer
+// @Tags TimeEntries
+// @Produce json
+// @Success 200 {object} models.TimeEntry
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /time-entries/timer/pause [post]
+func (tc *Controller) PauseTimer(c *gin.Context) {
+	tc.changeTimer(c, timer.Pause)
+}
+
+// ResumeTimer resumes the paused timer of the authenticated user
+// @Summary Resume timer
+// @Tags TimeEntries
+// @Produce json
+// @Success 200 {object} models.TimeEntry
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /time-entries/timer/resume [post]
+func (tc *Controller) ResumeTimer(c *gin.Context) {
+	tc.changeTimer(c, timer.Resume)
+}
+
+// StopTimer stops the running or paused timer of the authenticated user
+// @Summary Stop timer
+// @Description Stop the timer, its end date and duration are set by the server
+// @Tags TimeEntries
+// @Produce json
+// @Success 200 {object} models.TimeEntry
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /time-entries/timer/stop [post]
+func (tc *Controller) StopTimer(c *gin.Context) {
+	tc.changeTimer(c, timer.Stop)
+}
+
+// changeTimer applies a transition to the active timer of the user and saves it
+func (tc *Controller) changeTimer(c *gin.Context, transition func(*models.TimeEntry, time.Time) error) {
+	timeEntry := tc.activeTimer(c)
+	if timeEntry == nil {
+		return
+	}
+
+	if err := transition(timeEntry, time.Now()); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	updatedTimeEntry, err := tc.timeEntryRepository.UpdateTimer(c, timeEntry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusConflict, gin.H{"error": timer.ErrStopped.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update timer"})
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedTimeEntry)
+}
+
+// activeTimer returns the active timer of the authenticated user, it writes the error response
+// and returns nil when there is none
+func (tc *Controller) activeTimer(c *gin.Context) *models.TimeEntry {
+	authUser := auth.GetAuthUser(c)
+	if authUser == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return nil
+	}
+
+	timeEntry, err := tc.timeEntryRepository.GetActiveTimer(c, authUser.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve timer"})
+		return nil
+	}
+	if timeEntry == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No timer running"})
+		return nil
+	}
+	return timeEntry
+}
@@ -0,0 +1,198 @@
+package timeentrycontroller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+	"github.com/atomic-blend/backend/shared/middlewares/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func serveTimer(handler gin.HandlerFunc, userID *primitive.ObjectID, body []byte) *httptest.ResponseRecorder {
+	router := setupTestRouter()
+	router.POST("/time-entries/timer", func(c *gin.Context) {
+		if userID != nil {
+			c.Set("authUser", &auth.UserAuthInfo{UserID: *userID})
+		}
+		handler(c)
+	})
+
+	req, _ := http.NewRequest("POST", "/time-entries/timer", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func runningTimer(userID primitive.ObjectID, startedAgo time.Duration) *models.TimeEntry {
+	id := primitive.NewObjectID()
+	running := models.TimerStateRunning
+	active := true
+	return &models.TimeEntry{
+		ID:          &id,
+		User:        &userID,
+		StartDate:   time.Now().Add(-startedAgo).UTC().Format(time.RFC3339),
+		TimerState:  &running,
+		ActiveTimer: &active,
+	}
+}
+
+func TestTimeEntryController_StartTimer(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	t.Run("starts a timer on a task", func(t *testing.T) {
+		mockRepo := new(mocks.MockTimeEntryRepository)
+		mockTaskRepo := new(mocks.MockTaskRepository)
+		controller := NewTimeEntryController(mockRepo, mockTaskRepo, nil, nil)
+		task := &models.TaskEntity{ID: primitive.NewObjectID().Hex(), User: userID}
+		mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+		mockRepo.On("StartTimer", mock.Anything, mock.MatchedBy(func(entry *models.TimeEntry) bool {
+			return entry.TaskID.Hex() == task.ID && *entry.TimerState == models.TimerStateRunning && *entry.User == userID
+		})).Return(&models.TimeEntry{}, nil)
+
+		body, _ := json.Marshal(gin.H{"taskId": task.ID})
+		resp := serveTimer(controller.StartTimer, &userID, body)
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("starts a timer without body", func(t *testing.T) {
+		mockRepo := new(mocks.MockTimeEntryRepository)
+		controller := NewTimeEntryController(mockRepo, nil, nil, nil)
+		mockRepo.On("StartTimer", mock.Anything, mock.Anything).Return(&models.TimeEntry{}, nil)
+
+		resp := serveTimer(controller.StartTimer, &userID, nil)
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+	})
+
+	t.Run("a timer is already running", func(t *testing.T) {
+		mockRepo := new(mocks.MockTimeEntryRepository)
+		controller := NewTimeEntryController(mockRepo, nil, nil, nil)
+		mockRepo.On("StartTimer", mock.Anything, mock.Anything).Return(nil, repositories.ErrTimerAlreadyRunning)
+
+		resp := serveTimer(controller.StartTimer, &userID, nil)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+
+	t.Run("task of another user", func(t *testing.T) {
+		mockRepo := new(mocks.MockTimeEntryRepository)
+		mockTaskRepo := new(mocks.MockTaskRepository)
+		controller := NewTimeEntryController(mockRepo, mockTaskRepo, nil, nil)
+		task := &models.TaskEntity{ID: primitive.NewObjectID().Hex(), User: primitive.NewObjectID()}
+		mockTaskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+
+		body, _ := json.Marshal(gin.H{"taskId": task.ID})
+		resp := serveTimer(controller.StartTimer, &userID, body)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		mockRepo.AssertNotCalled(t, "StartTimer", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		controller := NewTimeEntryController(new(mocks.MockTimeEntryRepository), nil, nil, nil)
+		resp := serveTimer(controller.StartTimer, nil, nil)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+}
+
+func TestTimeEntryController_TimerTransitions(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	t.Run("stop sets the end date and duration", func(t *testing.T) {
+		mockRepo := new(mocks.MockTimeEntryRepository)
+		controller := NewTimeEntryController(mockRepo, nil, nil, nil)
+		mockRepo.On("GetActiveTimer", mock.Anything, userID).Return(runningTimer(userID, time.Hour), nil)
+		var saved *models.TimeEntry
+		mockRepo.On("UpdateTimer", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*models.TimeEntry)
+		}).Return(&models.TimeEntry{}, nil)
+
+		resp := serveTimer(controller.StopTimer, &userID, nil)
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.NotNil(t, saved)
+		assert.Equal(t, models.TimerStateStopped, *saved.TimerState)
+		assert.NotEmpty(t, saved.EndDate)
+		assert.Nil(t, saved.ActiveTimer)
+		assert.InDelta(t, 3600, parseSeconds(t, *saved.Duration), 2)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("pause a running timer", func(t *testing.T) {
+		mockRepo := new(mocks.MockTimeEntryRepository)
+		controller := NewTimeEntryController(mockRepo, nil, nil, nil)
+		mockRepo.On("GetActiveTimer", mock.Anything, userID).Return(runningTimer(userID, time.Minute), nil)
+		mockRepo.On("UpdateTimer", mock.Anything, mock.Anything).Return(&models.TimeEntry{}, nil)
+
+		resp := serveTimer(controller.PauseTimer, &userID, nil)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("resume a running timer", func(t *testing.T) {
+		mockRepo := new(mocks.MockTimeEntryRepository)
+		controller := NewTimeEntryController(mockRepo, nil, nil, nil)
+		mockRepo.On("GetActiveTimer", mock.Anything, userID).Return(runningTimer(userID, time.Minute), nil)
+
+		resp := serveTimer(controller.ResumeTimer, &userID, nil)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+		mockRepo.AssertNotCalled(t, "UpdateTimer", mock.Anything, mock.Anything)
+	})
+
+	t.Run("timer stopped concurrently", func(t *testing.T) {
+		mockRepo := new(mocks.MockTimeEntryRepository)
+		controller := NewTimeEntryController(mockRepo, nil, nil, nil)
+		mockRepo.On("GetActiveTimer", mock.Anything, userID).Return(runningTimer(userID, time.Minute), nil)
+		mockRepo.On("UpdateTimer", mock.Anything, mock.Anything).Return(nil, mongo.ErrNoDocuments)
+
+		resp := serveTimer(controller.StopTimer, &userID, nil)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+
+	t.Run("no timer running", func(t *testing.T) {
+		mockRepo := new(mocks.MockTimeEntryRepository)
+		controller := NewTimeEntryController(mockRepo, nil, nil, nil)
+		mockRepo.On("GetActiveTimer", mock.Anything, userID).Return(nil, nil)
+
+		resp := serveTimer(controller.StopTimer, &userID, nil)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("get the running timer", func(t *testing.T) {
+		mockRepo := new(mocks.MockTimeEntryRepository)
+		controller := NewTimeEntryController(mockRepo, nil, nil, nil)
+		mockRepo.On("GetActiveTimer", mock.Anything, userID).Return(runningTimer(userID, 10*time.Minute), nil)
+
+		resp := serveTimer(controller.GetTimer, &userID, nil)
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var entry models.TimeEntry
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &entry))
+		assert.InDelta(t, 600, parseSeconds(t, *entry.Duration), 2)
+	})
+}
+
+func parseSeconds(t *testing.T, value string) float64 {
+	var seconds float64
+	require.NoError(t, json.Unmarshal([]byte(value), &seconds))
+	return seconds
+}
@@ -2,9 +2,11 @@ package timeentrycontroller
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
 	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/repositories"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -40,6 +42,11 @@ func (tc *Controller) Update(c *gin.Context) {
 		return
 	}
 
+	if err := validateTimeEntry(ctx, tc.taskRepository, authUser.UserID, &updateData, existingEntry); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
 	// Preserve original fields and update timestamp
 	updateData.ID = existingEntry.ID
 	updateData.User = existingEntry.User
@@ -47,6 +54,10 @@ func (tc *Controller) Update(c *gin.Context) {
 	updateData.UpdatedAt = time.Now().Format(time.RFC3339)
 
 	updatedTimeEntry, err := tc.timeEntryRepository.Update(ctx, idParam, &updateData)
+	if errors.Is(err, repositories.ErrTimerActive) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Stop the timer before editing the time entry"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update time entry"})
 		return
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"github.com/atomic-blend/backend/shared/middlewares/auth"
 	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/repositories"
 	"github.com/atomic-blend/backend/productivity/tests/mocks"
 	"testing"
 	"time"
@@ -19,7 +20,7 @@ import (
 
 func TestTimeEntryController_Update(t *testing.T) {
 	mockRepo := new(mocks.MockTimeEntryRepository)
-	controller := NewTimeEntryController(mockRepo)
+	controller := NewTimeEntryController(mockRepo, nil, nil, nil)
 	router := setupTestRouter()
 
 	userID := primitive.NewObjectID()
@@ -65,3 +66,40 @@ func TestTimeEntryController_Update(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.Code)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestTimeEntryController_Update_ActiveTimer(t *testing.T) {
+	mockRepo := new(mocks.MockTimeEntryRepository)
+	controller := NewTimeEntryController(mockRepo, nil, nil, nil)
+	router := setupTestRouter()
+
+	userID := primitive.NewObjectID()
+	entryID := primitive.NewObjectID()
+	running := models.TimerStateRunning
+	active := true
+
+	existingEntry := &models.TimeEntry{
+		ID:          &entryID,
+		User:        &userID,
+		StartDate:   "2025-05-28T10:00:00Z",
+		CreatedAt:   "2025-05-28T10:00:00Z",
+		TimerState:  &running,
+		ActiveTimer: &active,
+	}
+
+	mockRepo.On("GetByID", mock.Anything, entryID.Hex()).Return(existingEntry, nil)
+	mockRepo.On("Update", mock.Anything, entryID.Hex(), mock.AnythingOfType("*models.TimeEntry")).Return(nil, repositories.ErrTimerActive)
+
+	router.PUT("/time-entries/:id", func(c *gin.Context) {
+		c.Set("authUser", &auth.UserAuthInfo{UserID: userID})
+		controller.Update(c)
+	})
+
+	body, _ := json.Marshal(models.TimeEntry{StartDate: "2025-05-28T09:00:00Z", EndDate: "2025-05-28T13:00:00Z"})
+	req, _ := http.NewRequest("PUT", "/time-entries/"+entryID.Hex(), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,60 @@
+package timeentrycontroller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// errTaskNotFound is returned for a time entry of a task which does not exist
+var errTaskNotFound = errors.New("task not found")
+
+// errTaskAccessDenied is returned for a time entry of a task of another user
+var errTaskAccessDenied = errors.New("task belongs to another user")
+
+// errTimerState is returned for a time entry saved as a running or paused timer, only the timer endpoints can start one
+var errTimerState = errors.New("timers are started with the timer endpoints")
+
+// validateTimeEntry checks a time entry the user creates, or edits when previous holds the entry before the edit:
+// its task must be a task of the user, and it cannot be saved as a running or paused timer
+func validateTimeEntry(ctx context.Context, taskRepository repositories.TaskRepositoryInterface, userID primitive.ObjectID, timeEntry *models.TimeEntry, previous *models.TimeEntry) error {
+	if timeEntry.TimerState != nil && *timeEntry.TimerState != models.TimerStateStopped &&
+		(previous == nil || previous.TimerState == nil || *previous.TimerState != *timeEntry.TimerState) {
+		return errTimerState
+	}
+	if timeEntry.TaskID == nil || (previous != nil && previous.TaskID != nil && *previous.TaskID == *timeEntry.TaskID) {
+		return nil
+	}
+
+	task, err := taskRepository.GetByID(ctx, timeEntry.TaskID.Hex())
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return errTaskNotFound
+	}
+	if task.User != userID {
+		return errTaskAccessDenied
+	}
+	return nil
+}
+
+// writeValidationError writes the response of a time entry which failed validateTimeEntry
+func writeValidationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errTimerState):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, errTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	case errors.Is(err, errTaskAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve task"})
+	}
+}
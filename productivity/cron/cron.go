@@ -2,6 +2,8 @@ package cron
 
 import (
 	"github.com/atomic-blend/backend/productivity/cron/notifications"
+	"github.com/atomic-blend/backend/productivity/cron/timers"
+	"github.com/atomic-blend/backend/productivity/cron/tombstones"
 	"github.com/rs/zerolog/log"
 )
 
@@ -9,5 +11,7 @@ import (
 func MainCron() {
 	log.Debug().Msg("Starting cron jobs")
 	notifications.MainNotificationCron()
+	tombstones.PurgeTombstonesCron()
+	timers.AutoStopTimersCron()
 	// Add more cron jobs here as needed
 }
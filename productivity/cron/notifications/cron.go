@@ -7,4 +7,6 @@ func MainNotificationCron() {
 	log.Debug().Msg("Starting notification cron jobs")
 	TaskDueNotificationCron()
 	HabitReminderNotificationCron()
+	SnoozedNotificationCron()
+	PomodoroNotificationCron()
 }
@@ -0,0 +1,70 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	authv1 "github.com/atomic-blend/backend/grpc/gen/auth/v1"
+	userv1 "github.com/atomic-blend/backend/grpc/gen/user/v1"
+	"github.com/atomic-blend/backend/productivity/utils/timezone"
+	userclient "github.com/atomic-blend/backend/shared/grpc/user"
+
+	"github.com/rs/zerolog/log"
+)
+
+// userDevices loads the devices of each user at most once during a run of a cron job
+type userDevices struct {
+	client  userclient.Interface
+	devices map[string][]*userv1.UserDevice
+}
+
+func newUserDevices(client userclient.Interface) *userDevices {
+	return &userDevices{
+		client:  client,
+		devices: make(map[string][]*userv1.UserDevice),
+	}
+}
+
+// get returns the devices of the user
+func (u *userDevices) get(ctx context.Context, userID string) ([]*userv1.UserDevice, error) {
+	if devices, ok := u.devices[userID]; ok {
+		return devices, nil
+	}
+
+	req := &connect.Request[userv1.GetUserDevicesRequest]{
+		Msg: &userv1.GetUserDevicesRequest{
+			User: &authv1.User{
+				Id: userID,
+			},
+		},
+	}
+	resp, err := u.client.GetUserDevices(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	u.devices[userID] = resp.Msg.Devices
+	return resp.Msg.Devices, nil
+}
+
+// location returns the timezone of the user reported by their devices, UTC if it is unknown
+func (u *userDevices) location(ctx context.Context, userID string) *time.Location {
+	devices, err := u.get(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to get user devices for user: %s", userID)
+		return time.UTC
+	}
+	return timezone.FromDevices(devices)
+}
+
+// fcmTokens returns the FCM tokens of the devices
+func fcmTokens(devices []*userv1.UserDevice) []string {
+	deviceTokens := []string{}
+	for _, device := range devices {
+		if device.FcmToken != "" {
+			deviceTokens = append(deviceTokens, device.FcmToken)
+		}
+	}
+	return deviceTokens
+}
@@ -5,19 +5,19 @@ import (
 	"os"
 	"time"
 
-	"connectrpc.com/connect"
-	authv1 "github.com/atomic-blend/backend/grpc/gen/auth/v1"
-	userv1 "github.com/atomic-blend/backend/grpc/gen/user/v1"
 	"github.com/atomic-blend/backend/productivity/cron/notifications/payloads"
 	"github.com/atomic-blend/backend/productivity/models"
 	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/utils/habitstats"
 	userclient "github.com/atomic-blend/backend/shared/grpc/user"
 	"github.com/atomic-blend/backend/shared/utils/db"
 	fcmutils "github.com/atomic-blend/backend/shared/utils/fcm_utils"
 	"github.com/atomic-blend/backend/shared/utils/shortcuts"
 
+	"firebase.google.com/go/v4/messaging"
 	fcm "github.com/appleboy/go-fcm"
 	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // HabitReminderNotificationCron is a cron job that sends notifications to users for their habits
@@ -26,6 +26,7 @@ func HabitReminderNotificationCron() {
 	ctx := context.TODO()
 
 	habitRepo := repositories.NewHabitRepository(db.Database)
+	ledger := repositories.NewNotificationRepository(db.Database)
 
 	userService, err := userclient.NewUserClient()
 	if err != nil {
@@ -63,15 +64,30 @@ func HabitReminderNotificationCron() {
 	now := time.Now()
 	log.Debug().Msgf("Current time: %s", now.Format(time.RFC3339))
 
+	// reminders missed since the last run are sent as well
+	from := notificationWindow(ctx, ledger, habitReminderJob, now)
+	send := func(ctx context.Context, data map[string]string, deviceTokens []string) (*messaging.BatchResponse, error) {
+		return fcmutils.SendMulticast(ctx, fcmClient, data, deviceTokens)
+	}
+
+	devices := newUserDevices(userService)
+
 	// Process each habit
 	for _, habit := range habits {
 		log.Debug().Msgf("Processing habit: %s", habit.ID)
 
+		if len(habit.Reminders) == 0 {
+			continue
+		}
+
 		// Get the user for this habit
 		userID := habit.UserID.Hex()
 
+		// Reminders are set at the wall clock time of the user
+		loc := devices.location(ctx, userID)
+
 		// Check if this habit should send a notification now
-		shouldSendNotification, reminderToSend := shouldSendHabitNotification(habit, now)
+		shouldSendNotification, reminderToSend := habitReminderBetween(habit, from, now, loc)
 		if !shouldSendNotification {
 			log.Debug().Msgf("No notification needed for habit: %s", habit.ID)
 			continue
@@ -80,26 +96,13 @@ func HabitReminderNotificationCron() {
 		log.Debug().Msgf("Reminder time matched: %s", reminderToSend.Format(time.RFC3339))
 
 		// Get user devices using gRPC client
-		req := &connect.Request[userv1.GetUserDevicesRequest]{
-			Msg: &userv1.GetUserDevicesRequest{
-				User: &authv1.User{
-					Id: userID,
-				},
-			},
-		}
-		resp, err := userService.GetUserDevices(ctx, req)
+		userDevices, err := devices.get(ctx, userID)
 		if err != nil {
 			log.Error().Err(err).Msgf("Failed to get user devices for user: %s", userID)
 			continue
 		}
 
-		deviceTokens := []string{}
-		for _, device := range resp.Msg.Devices {
-			if device.FcmToken != "" {
-				deviceTokens = append(deviceTokens, device.FcmToken)
-			}
-		}
-
+		deviceTokens := fcmTokens(userDevices)
 		if len(deviceTokens) == 0 {
 			log.Debug().Msgf("No device tokens found for user: %s", userID)
 			continue
@@ -108,23 +111,59 @@ func HabitReminderNotificationCron() {
 		log.Debug().Msgf("Device tokens for user %s: %v", userID, deviceTokens)
 
 		// Create and send notification
-		payload := payloads.NewHabitReminderPayload(
-			*habit.Name,
-			*habit.Citation,
-			habit.Emoji,
-		)
+		payload := habitPayload(habit, reminderToSend)
 		log.Debug().Msgf("Payload: %v", payload)
 
 		data := payload.GetData()
 		log.Debug().Msgf("Data: %v", data)
 
 		// Send notification to the user
-		fcmutils.SendMulticast(ctx, fcmClient, data, deviceTokens)
-		log.Debug().Msgf("Sent notification for habit: %s", habit.ID)
+		notification := &models.Notification{
+			UserID:     habit.UserID,
+			EntityType: models.NotificationEntityHabit,
+			EntityID:   habit.ID.Hex(),
+			Kind:       "reminder",
+			Occurrence: primitive.NewDateTimeFromTime(reminderToSend),
+		}
+		if dispatch(ctx, ledger, send, notification, data, deviceTokens) {
+			log.Debug().Msgf("Sent notification for habit: %s", habit.ID)
+		}
+	}
+
+	if err := ledger.SetLastRun(ctx, habitReminderJob, now); err != nil {
+		log.Error().Err(err).Msg("Failed to save the last run of the habit reminders")
 	}
 }
 
-// shouldSendHabitNotification determines if a habit should send a notification at the current time
+// habitPayload creates the payload of the reminder of a habit at the given date
+func habitPayload(habit *models.Habit, reminder time.Time) *payloads.HabitReminderPayload {
+	name := ""
+	if habit.Name != nil {
+		name = *habit.Name
+	}
+	citation := ""
+	if habit.Citation != nil {
+		citation = *habit.Citation
+	}
+
+	actionable := payloads.NewHabitActionable(habit.ID.Hex(), reminder.UTC().Format(time.RFC3339))
+	return payloads.NewHabitReminderPayload(actionable, name, citation, habit.Emoji)
+}
+
+// habitReminderBetween returns the latest reminder of the habit between from, excluded, and to.
+// Each minute of the window is evaluated on the wall clock of the user.
+func habitReminderBetween(habit *models.Habit, from, to time.Time, loc *time.Location) (bool, time.Time) {
+	from = from.Truncate(time.Minute)
+	for minute := to.Truncate(time.Minute); minute.After(from); minute = minute.Add(-time.Minute) {
+		if send, reminder := shouldSendHabitNotification(habit, minute.In(loc)); send {
+			return true, reminder
+		}
+	}
+	return false, time.Time{}
+}
+
+// shouldSendHabitNotification determines if a habit should send a notification at the current time.
+// now must be in the timezone of the user, days and reminders being evaluated on their wall clock.
 // Returns true and the reminder time if a notification should be sent, false otherwise
 func shouldSendHabitNotification(habit *models.Habit, now time.Time) (bool, time.Time) {
 	// Check if habit has ended
@@ -148,9 +187,10 @@ func shouldSendHabitNotification(habit *models.Habit, now time.Time) (bool, time
 
 // shouldSendDailyWeeklyNotification handles daily and weekly frequency logic
 func shouldSendDailyWeeklyNotification(habit *models.Habit, now time.Time) (bool, time.Time) {
-	// Check if current day of week is in days of week
-	if !shortcuts.ContainsInt(*habit.DaysOfWeek, int(now.Weekday()-1)) {
-		log.Debug().Msgf("Current day of week is not in days of week: %d", int(now.Weekday()))
+	// Check if current day of week is in days of week, a daily habit without days is done every day
+	everyDay := *habit.Frequency == models.FrequencyDaily && (habit.DaysOfWeek == nil || len(*habit.DaysOfWeek) == 0)
+	if !everyDay && (habit.DaysOfWeek == nil || !shortcuts.ContainsInt(*habit.DaysOfWeek, habitstats.WeekdayIndex(now))) {
+		log.Debug().Msgf("Current day of week is not in days of week: %d", habitstats.WeekdayIndex(now))
 		return false, time.Time{}
 	}
 
@@ -160,9 +200,18 @@ func shouldSendDailyWeeklyNotification(habit *models.Habit, now time.Time) (bool
 
 // shouldSendMonthlyNotification handles monthly frequency logic
 func shouldSendMonthlyNotification(habit *models.Habit, now time.Time) (bool, time.Time) {
-	// Check if current day of month is in days of month
-	midnightNow := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
-	if !shortcuts.ContainsDateTime(*habit.DaysOfMonth, midnightNow) {
+	// Check if current day of month is in days of month, which are stored at midnight UTC
+	if habit.DaysOfMonth == nil {
+		return false, time.Time{}
+	}
+	scheduled := false
+	for _, day := range *habit.DaysOfMonth {
+		if day.Time().UTC().Day() == now.Day() {
+			scheduled = true
+			break
+		}
+	}
+	if !scheduled {
 		return false, time.Time{}
 	}
 
@@ -191,8 +240,12 @@ func shouldSendRepeatingNotification(habit *models.Habit, now time.Time) (bool,
 	return findMatchingReminder(habit.Reminders, now)
 }
 
-// findMatchingReminder checks if the current time matches any of the habit's reminders
+// findMatchingReminder checks if the current time matches any of the habit's reminders.
+// Reminders are wall clock times on the day of now, in its location: a reminder falling in the gap of
+// a daylight saving time transition is sent once the clock moved forward, and a reminder in the repeated
+// hour is only sent once.
 func findMatchingReminder(reminders []string, now time.Time) (bool, time.Time) {
+	currentMinute := now.Truncate(time.Minute)
 	for _, reminder := range reminders {
 		log.Debug().Msgf("Checking reminder: %s", reminder)
 		reminderTime, err := time.Parse("15:04", reminder)
@@ -200,9 +253,10 @@ func findMatchingReminder(reminders []string, now time.Time) (bool, time.Time) {
 			log.Error().Err(err).Msgf("Failed to parse reminder time: %s", reminder)
 			continue
 		}
-		if now.Hour() == reminderTime.Hour() && now.Minute() == reminderTime.Minute() {
-			log.Debug().Msgf("Reminder time matched: %s", reminderTime.Format(time.RFC3339))
-			return true, reminderTime
+		reminderAt := time.Date(now.Year(), now.Month(), now.Day(), reminderTime.Hour(), reminderTime.Minute(), 0, 0, now.Location())
+		if reminderAt.Equal(currentMinute) {
+			log.Debug().Msgf("Reminder time matched: %s", reminderAt.Format(time.RFC3339))
+			return true, reminderAt
 		}
 	}
 	log.Debug().Msg("No matching reminder found")
@@ -0,0 +1,115 @@
+package notifications
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+
+	"firebase.google.com/go/v4/messaging"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// names of the notification jobs, used to remember when they last ran
+const (
+	taskDueJob       = "task_due"
+	habitReminderJob = "habit_reminder"
+)
+
+// defaultCatchUpMinutes is how far back missed notifications are sent when NOTIFICATION_CATCHUP_MINUTES is not set
+const defaultCatchUpMinutes = 60
+
+// sender sends a push notification to the given devices
+type sender func(ctx context.Context, data map[string]string, deviceTokens []string) (*messaging.BatchResponse, error)
+
+// CatchUpWindow returns how far back notifications missed while the cron was not running are still sent,
+// read from NOTIFICATION_CATCHUP_MINUTES
+func CatchUpWindow() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("NOTIFICATION_CATCHUP_MINUTES"))
+	if err != nil || minutes < 1 {
+		minutes = defaultCatchUpMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// notificationWindow returns the start of the window of the notifications to send at now, excluded.
+// The window starts at the last run of the job so that no minute is skipped between two runs,
+// it covers the current minute only on the first run and never goes further back than the catch up window.
+func notificationWindow(ctx context.Context, ledger repositories.NotificationRepositoryInterface, job string, now time.Time) time.Time {
+	to := now.Truncate(time.Minute)
+	from := to.Add(-time.Minute)
+
+	lastRun, err := ledger.LastRun(ctx, job)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to get the last run of %s", job)
+		return from
+	}
+	if lastRun.IsZero() {
+		return from
+	}
+
+	from = lastRun.Truncate(time.Minute)
+	if limit := to.Add(-CatchUpWindow()); from.Before(limit) {
+		from = limit
+	}
+	if !from.Before(to) {
+		from = to.Add(-time.Minute)
+	}
+	return from
+}
+
+// dispatch sends the notification unless it was already sent for the same occurrence, and records the
+// results of FCM in the ledger. A notification is claimed before being sent, so that overlapping runs
+// never send it twice, and it is not sent again if the process stops after the claim.
+func dispatch(ctx context.Context, ledger repositories.NotificationRepositoryInterface, send sender, notification *models.Notification, data map[string]string, deviceTokens []string) bool {
+	claimed, err := ledger.Claim(ctx, notification)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to claim %s notification for %s: %s", notification.Kind, notification.EntityType, notification.EntityID)
+		return false
+	}
+	if !claimed {
+		log.Debug().Msgf("Notification already sent for %s: %s at %s", notification.EntityType, notification.EntityID, notification.Occurrence.Time().Format(time.RFC3339))
+		return false
+	}
+
+	res, err := send(ctx, data, deviceTokens)
+	recordDeliveries(notification, res, err)
+
+	if err := ledger.RecordResult(ctx, notification); err != nil {
+		log.Error().Err(err).Msgf("Failed to record the result of notification: %s", notification.ID.Hex())
+	}
+	return notification.Status == models.NotificationStatusSent
+}
+
+// recordDeliveries sets the status and the delivery of each device from the response of FCM
+func recordDeliveries(notification *models.Notification, res *messaging.BatchResponse, err error) {
+	sentAt := primitive.NewDateTimeFromTime(time.Now())
+	notification.SentAt = &sentAt
+	notification.Status = models.NotificationStatusFailed
+
+	if err != nil {
+		notification.Error = err.Error()
+		return
+	}
+	if res == nil {
+		return
+	}
+
+	notification.SuccessCount = res.SuccessCount
+	notification.FailureCount = res.FailureCount
+	notification.Deliveries = make([]models.NotificationDelivery, 0, len(res.Responses))
+	for _, response := range res.Responses {
+		delivery := models.NotificationDelivery{MessageID: response.MessageID}
+		if response.Error != nil {
+			delivery.Error = response.Error.Error()
+		}
+		notification.Deliveries = append(notification.Deliveries, delivery)
+	}
+	if res.SuccessCount > 0 {
+		notification.Status = models.NotificationStatusSent
+	}
+}
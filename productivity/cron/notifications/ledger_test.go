@@ -0,0 +1,120 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+
+	"firebase.google.com/go/v4/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTestNotification() *models.Notification {
+	return &models.Notification{
+		UserID:     primitive.NewObjectID(),
+		EntityType: models.NotificationEntityHabit,
+		EntityID:   primitive.NewObjectID().Hex(),
+		Kind:       "reminder",
+		Occurrence: primitive.NewDateTimeFromTime(time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC)),
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	ctx := context.Background()
+	data := map[string]string{"type": "HABIT_REMINDER"}
+	tokens := []string{"token-1", "token-2"}
+
+	t.Run("sends and records the deliveries", func(t *testing.T) {
+		ledger := new(mocks.MockNotificationRepository)
+		notification := newTestNotification()
+		ledger.On("Claim", ctx, notification).Return(true, nil)
+		ledger.On("RecordResult", ctx, notification).Return(nil)
+
+		calls := 0
+		send := func(context.Context, map[string]string, []string) (*messaging.BatchResponse, error) {
+			calls++
+			return &messaging.BatchResponse{
+				SuccessCount: 1,
+				FailureCount: 1,
+				Responses: []*messaging.SendResponse{
+					{Success: true, MessageID: "message-1"},
+					{Error: errors.New("unregistered")},
+				},
+			}, nil
+		}
+
+		assert.True(t, dispatch(ctx, ledger, send, notification, data, tokens))
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, models.NotificationStatusSent, notification.Status)
+		assert.Equal(t, 1, notification.SuccessCount)
+		assert.Equal(t, 1, notification.FailureCount)
+		assert.Equal(t, "message-1", notification.Deliveries[0].MessageID)
+		assert.Equal(t, "unregistered", notification.Deliveries[1].Error)
+		assert.NotNil(t, notification.SentAt)
+		ledger.AssertExpectations(t)
+	})
+
+	t.Run("skips a notification already sent", func(t *testing.T) {
+		ledger := new(mocks.MockNotificationRepository)
+		notification := newTestNotification()
+		ledger.On("Claim", ctx, notification).Return(false, nil)
+
+		send := func(context.Context, map[string]string, []string) (*messaging.BatchResponse, error) {
+			t.Fatal("the notification must not be sent twice")
+			return nil, nil
+		}
+
+		assert.False(t, dispatch(ctx, ledger, send, notification, data, tokens))
+		ledger.AssertNotCalled(t, "RecordResult", mock.Anything, mock.Anything)
+	})
+
+	t.Run("records a failed send", func(t *testing.T) {
+		ledger := new(mocks.MockNotificationRepository)
+		notification := newTestNotification()
+		ledger.On("Claim", ctx, notification).Return(true, nil)
+		ledger.On("RecordResult", ctx, notification).Return(nil)
+
+		send := func(context.Context, map[string]string, []string) (*messaging.BatchResponse, error) {
+			return nil, errors.New("unavailable")
+		}
+
+		assert.False(t, dispatch(ctx, ledger, send, notification, data, tokens))
+		assert.Equal(t, models.NotificationStatusFailed, notification.Status)
+		assert.Equal(t, "unavailable", notification.Error)
+		ledger.AssertExpectations(t)
+	})
+}
+
+func TestNotificationWindow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 2, 8, 0, 30, 0, time.UTC)
+	minute := now.Truncate(time.Minute)
+
+	tests := []struct {
+		name     string
+		lastRun  time.Time
+		err      error
+		expected time.Time
+	}{
+		{name: "first run covers the current minute", lastRun: time.Time{}, expected: minute.Add(-time.Minute)},
+		{name: "starts at the last run", lastRun: now.Add(-5 * time.Minute), expected: minute.Add(-5 * time.Minute)},
+		{name: "never goes back further than the catch up window", lastRun: now.Add(-24 * time.Hour), expected: minute.Add(-CatchUpWindow())},
+		{name: "run within the same minute", lastRun: now.Add(-10 * time.Second), expected: minute.Add(-time.Minute)},
+		{name: "ledger error covers the current minute", err: errors.New("unavailable"), expected: minute.Add(-time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger := new(mocks.MockNotificationRepository)
+			ledger.On("LastRun", ctx, taskDueJob).Return(tt.lastRun, tt.err)
+
+			assert.True(t, tt.expected.Equal(notificationWindow(ctx, ledger, taskDueJob, now)))
+		})
+	}
+}
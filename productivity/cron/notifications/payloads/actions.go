@@ -0,0 +1,56 @@
+package payloads
+
+import (
+	"strings"
+
+	"github.com/atomic-blend/backend/productivity/models"
+)
+
+// Actionable holds what a notification needs to let the user act on it from the lock screen.
+// The actions are sent back with the entity to the notification action endpoint.
+type Actionable struct {
+	EntityType string   `json:"entityType"`
+	EntityID   string   `json:"entityId"`
+	Kind       string   `json:"kind"`
+	Occurrence string   `json:"occurrence"`
+	Actions    []string `json:"actions"`
+}
+
+// taskActions are the actions offered by the task notifications
+var taskActions = []string{models.NotificationActionComplete, models.NotificationActionSnooze}
+
+// habitActions are the actions offered by the habit notifications
+var habitActions = []string{models.NotificationActionLogHabit, models.NotificationActionSnooze}
+
+// NewTaskActionable creates the actions of a notification of a task occurrence
+func NewTaskActionable(taskID string, kind string, occurrence string) Actionable {
+	return Actionable{
+		EntityType: models.NotificationEntityTask,
+		EntityID:   taskID,
+		Kind:       kind,
+		Occurrence: occurrence,
+		Actions:    taskActions,
+	}
+}
+
+// NewHabitActionable creates the actions of a notification of a habit reminder
+func NewHabitActionable(habitID string, occurrence string) Actionable {
+	return Actionable{
+		EntityType: models.NotificationEntityHabit,
+		EntityID:   habitID,
+		Kind:       "reminder",
+		Occurrence: occurrence,
+		Actions:    habitActions,
+	}
+}
+
+// addTo adds the actions to the data of a notification, the list of actions is comma separated
+// as FCM data only holds strings
+func (a Actionable) addTo(data map[string]string) map[string]string {
+	data["entityType"] = a.EntityType
+	data["entityId"] = a.EntityID
+	data["kind"] = a.Kind
+	data["occurrence"] = a.Occurrence
+	data["actions"] = strings.Join(a.Actions, ",")
+	return data
+}
@@ -1,20 +1,22 @@
 package payloads
 
-// HabitReminderPayload represents the payload for task starting notifications.
+// HabitReminderPayload represents the payload for habit reminder notifications.
 type HabitReminderPayload struct {
-	Type  string `json:"type"`
-	Title string `json:"title"`
-	Citation string `json:"citation"`
-	Emoji *string `json:"emoji"`
+	Actionable
+	Type     string  `json:"type"`
+	Title    string  `json:"title"`
+	Citation string  `json:"citation"`
+	Emoji    *string `json:"emoji"`
 }
 
 // NewHabitReminderPayload creates a new HabitReminderPayload with the given title.
-func NewHabitReminderPayload(title string, citation string, emoji *string) *HabitReminderPayload {
+func NewHabitReminderPayload(actionable Actionable, title string, citation string, emoji *string) *HabitReminderPayload {
 	return &HabitReminderPayload{
-		Type:  "HABIT_REMINDER",
-		Title: title,
-		Citation: citation,
-		Emoji: emoji,
+		Actionable: actionable,
+		Type:       "HABIT_REMINDER",
+		Title:      title,
+		Citation:   citation,
+		Emoji:      emoji,
 	}
 }
 
@@ -25,9 +27,9 @@ func (p *HabitReminderPayload) GetType() string {
 
 // GetData returns the ready to send data for the payload.
 func (p *HabitReminderPayload) GetData() map[string]string {
-	return map[string]string{
-		"type":  p.Type,
-		"title": p.Title,
+	return p.addTo(map[string]string{
+		"type":     p.Type,
+		"title":    p.Title,
 		"citation": p.Citation,
-	}
+	})
 }
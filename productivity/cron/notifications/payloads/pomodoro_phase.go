@@ -0,0 +1,42 @@
+package payloads
+
+import "strconv"
+
+// PomodoroPhasePayload represents the payload for the phase transitions of a pomodoro session.
+type PomodoroPhasePayload struct {
+	Type          string `json:"type"`
+	SessionID     string `json:"sessionId"`
+	Phase         string `json:"phase"`
+	PreviousPhase string `json:"previousPhase"`
+	Cycle         int    `json:"cycle"`
+	EndsAt        string `json:"endsAt"`
+}
+
+// NewPomodoroPhasePayload creates a new PomodoroPhasePayload for the phase starting in the session.
+func NewPomodoroPhasePayload(sessionID string, previousPhase string, phase string, cycle int, endsAt string) *PomodoroPhasePayload {
+	return &PomodoroPhasePayload{
+		Type:          "POMODORO_PHASE",
+		SessionID:     sessionID,
+		Phase:         phase,
+		PreviousPhase: previousPhase,
+		Cycle:         cycle,
+		EndsAt:        endsAt,
+	}
+}
+
+// GetType returns the type of the payload.
+func (p *PomodoroPhasePayload) GetType() string {
+	return p.Type
+}
+
+// GetData returns the ready to send data for the payload.
+func (p *PomodoroPhasePayload) GetData() map[string]string {
+	return map[string]string{
+		"type":          p.Type,
+		"sessionId":     p.SessionID,
+		"phase":         p.Phase,
+		"previousPhase": p.PreviousPhase,
+		"cycle":         strconv.Itoa(p.Cycle),
+		"endsAt":        p.EndsAt,
+	}
+}
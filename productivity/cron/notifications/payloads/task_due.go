@@ -2,15 +2,17 @@ package payloads
 
 // TaskDuePayload represents the payload for task due notifications.
 type TaskDuePayload struct {
-	Type string `json:"type"`
+	Actionable
+	Type  string `json:"type"`
 	Title string `json:"title"`
 }
 
 // NewTaskDuePayload creates a new TaskDuePayload with the given title.
-func NewTaskDuePayload(title string) *TaskDuePayload {
+func NewTaskDuePayload(actionable Actionable, title string) *TaskDuePayload {
 	return &TaskDuePayload{
-		Type: "TASK_DUE",
-		Title: title,
+		Actionable: actionable,
+		Type:       "TASK_DUE",
+		Title:      title,
 	}
 }
 
@@ -19,11 +21,10 @@ func (p *TaskDuePayload) GetType() string {
 	return p.Type
 }
 
-
 // GetData returns the ready to send data for the payload.
 func (p *TaskDuePayload) GetData() map[string]string {
-	return map[string]string{
-		"type": p.Type,
+	return p.addTo(map[string]string{
+		"type":  p.Type,
 		"title": p.Title,
-	}
-}
\ No newline at end of file
+	})
+}
@@ -2,15 +2,17 @@ package payloads
 
 // TaskStartingPayload represents the payload for task starting notifications.
 type TaskStartingPayload struct {
+	Actionable
 	Type  string `json:"type"`
 	Title string `json:"title"`
 }
 
 // NewTaskStartingPayload creates a new TaskStartingPayload with the given title.
-func NewTaskStartingPayload(title string) *TaskStartingPayload {
+func NewTaskStartingPayload(actionable Actionable, title string) *TaskStartingPayload {
 	return &TaskStartingPayload{
-		Type:  "TASK_STARTING",
-		Title: title,
+		Actionable: actionable,
+		Type:       "TASK_STARTING",
+		Title:      title,
 	}
 }
 
@@ -21,8 +23,8 @@ func (p *TaskStartingPayload) GetType() string {
 
 // GetData returns the ready to send data for the payload.
 func (p *TaskStartingPayload) GetData() map[string]string {
-	return map[string]string{
+	return p.addTo(map[string]string{
 		"type":  p.Type,
 		"title": p.Title,
-	}
+	})
 }
@@ -0,0 +1,140 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/cron/notifications/payloads"
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/utils/pomodoro"
+	"github.com/atomic-blend/backend/productivity/utils/timer"
+	userclient "github.com/atomic-blend/backend/shared/grpc/user"
+	"github.com/atomic-blend/backend/shared/utils/db"
+	fcmutils "github.com/atomic-blend/backend/shared/utils/fcm_utils"
+
+	"firebase.google.com/go/v4/messaging"
+	fcm "github.com/appleboy/go-fcm"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PomodoroNotificationCron is a cron job that moves the pomodoro sessions to their next phase when the
+// current one ends, creates the time entries of the ended phases and notifies the users of the new phase.
+func PomodoroNotificationCron() {
+	log.Debug().Msg("Starting pomodoro notification cron job")
+	ctx := context.TODO()
+
+	sessionRepo := repositories.NewPomodoroRepository(db.Database)
+	timeEntryRepo := repositories.NewTimeEntryRepository(db.Database)
+	ledger := repositories.NewNotificationRepository(db.Database)
+
+	now := time.Now()
+	sessions, err := sessionRepo.GetDue(ctx, now)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get pomodoro sessions")
+		return
+	}
+	if len(sessions) == 0 {
+		return
+	}
+
+	userService, err := userclient.NewUserClient()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create user client")
+		return
+	}
+
+	if firebaseProjectID == "" {
+		log.Error().Msg("FIREBASE_PROJECT_ID is required for FCM")
+		return
+	}
+
+	fcmClient, err := fcm.NewClient(
+		ctx,
+		fcm.WithProjectID(firebaseProjectID),
+		fcm.WithCredentialsFile(os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")),
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create FCM client")
+		return
+	}
+
+	send := func(ctx context.Context, data map[string]string, deviceTokens []string) (*messaging.BatchResponse, error) {
+		return fcmutils.SendMulticast(ctx, fcmClient, data, deviceTokens)
+	}
+	devices := newUserDevices(userService)
+
+	for _, session := range sessions {
+		transition, err := advancePomodoro(ctx, sessionRepo, timeEntryRepo, session, now, timer.MaxDuration())
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to advance pomodoro session: %s", session.ID.Hex())
+			continue
+		}
+		if transition == nil {
+			continue
+		}
+
+		userDevices, err := devices.get(ctx, session.UserID.Hex())
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to get user devices for user: %s", session.UserID.Hex())
+			continue
+		}
+		deviceTokens := fcmTokens(userDevices)
+		if len(deviceTokens) == 0 {
+			continue
+		}
+
+		notification := &models.Notification{
+			UserID:     session.UserID,
+			EntityType: models.NotificationEntityPomodoro,
+			EntityID:   session.ID.Hex(),
+			Kind:       transition.To,
+			Occurrence: primitive.NewDateTimeFromTime(transition.At),
+		}
+		if dispatch(ctx, ledger, send, notification, pomodoroPayload(session, transition).GetData(), deviceTokens) {
+			log.Debug().Msgf("Sent pomodoro %s notification for session: %s", transition.To, session.ID.Hex())
+		}
+	}
+}
+
+// advancePomodoro moves the session through the phases that ended at now and saves it with their time
+// entries. It returns the last transition, the one to notify, or nil when the session was changed in the
+// meantime or was stopped because it ran for longer than limit.
+func advancePomodoro(ctx context.Context, sessionRepo repositories.PomodoroRepositoryInterface, timeEntryRepo repositories.TimeEntryRepositoryInterface, session *models.PomodoroSession, now time.Time, limit time.Duration) (*pomodoro.Transition, error) {
+	phaseStartedAt := session.PhaseStartedAt
+
+	transitions, entries, stopped := pomodoro.CatchUp(session, now, limit)
+	if stopped {
+		return nil, ignoreConflict(pomodoro.Save(ctx, sessionRepo, timeEntryRepo, session, phaseStartedAt, entries))
+	}
+	if len(transitions) == 0 {
+		return nil, nil
+	}
+	if err := pomodoro.Save(ctx, sessionRepo, timeEntryRepo, session, phaseStartedAt, entries); err != nil {
+		return nil, ignoreConflict(err)
+	}
+	return &transitions[len(transitions)-1], nil
+}
+
+// pomodoroPayload returns the notification of the phase started by the transition
+func pomodoroPayload(session *models.PomodoroSession, transition *pomodoro.Transition) *payloads.PomodoroPhasePayload {
+	return payloads.NewPomodoroPhasePayload(
+		session.ID.Hex(),
+		transition.From,
+		transition.To,
+		session.Cycle,
+		session.PhaseEndsAt.Time().UTC().Format(time.RFC3339),
+	)
+}
+
+// ignoreConflict ignores the error returned when the session was changed by its user during the run
+func ignoreConflict(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	return err
+}
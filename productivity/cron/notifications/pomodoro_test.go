@@ -0,0 +1,81 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+	"github.com/atomic-blend/backend/productivity/utils/pomodoro"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestAdvancePomodoro(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	t.Run("moves to the break and creates the time entry", func(t *testing.T) {
+		sessionRepo := new(mocks.MockPomodoroRepository)
+		timeEntryRepo := new(mocks.MockTimeEntryRepository)
+		session := pomodoro.Start(primitive.NewObjectID(), nil, start)
+		phaseStartedAt := session.PhaseStartedAt
+		sessionRepo.On("Save", ctx, session, phaseStartedAt).Return(nil)
+		timeEntryRepo.On("Create", ctx, mock.MatchedBy(func(entry *models.TimeEntry) bool {
+			return *entry.Duration == "1500" && !*entry.PomoBreak
+		})).Return(&models.TimeEntry{}, nil)
+
+		transition, err := advancePomodoro(ctx, sessionRepo, timeEntryRepo, session, start.Add(26*time.Minute), 24*time.Hour)
+
+		require.NoError(t, err)
+		require.NotNil(t, transition)
+		assert.Equal(t, models.PomodoroPhaseShortBreak, transition.To)
+
+		data := pomodoroPayload(session, transition).GetData()
+		assert.Equal(t, "POMODORO_PHASE", data["type"])
+		assert.Equal(t, models.PomodoroPhaseShortBreak, data["phase"])
+		assert.Equal(t, models.PomodoroPhaseWork, data["previousPhase"])
+		assert.Equal(t, "1", data["cycle"])
+		assert.Equal(t, "2025-06-02T09:30:00Z", data["endsAt"])
+		sessionRepo.AssertExpectations(t)
+		timeEntryRepo.AssertExpectations(t)
+	})
+
+	t.Run("session changed by the user in the meantime", func(t *testing.T) {
+		sessionRepo := new(mocks.MockPomodoroRepository)
+		timeEntryRepo := new(mocks.MockTimeEntryRepository)
+		session := pomodoro.Start(primitive.NewObjectID(), nil, start)
+		sessionRepo.On("Save", ctx, session, mock.Anything).Return(mongo.ErrNoDocuments)
+		timeEntryRepo.On("Create", ctx, mock.Anything).Return(&models.TimeEntry{}, nil)
+		timeEntryRepo.On("Delete", ctx, mock.Anything).Return(nil)
+
+		transition, err := advancePomodoro(ctx, sessionRepo, timeEntryRepo, session, start.Add(26*time.Minute), 24*time.Hour)
+
+		require.NoError(t, err)
+		assert.Nil(t, transition)
+		// the entry of the ended phase is deleted again
+		timeEntryRepo.AssertNumberOfCalls(t, "Delete", 1)
+	})
+
+	t.Run("forgotten session is stopped at the limit", func(t *testing.T) {
+		sessionRepo := new(mocks.MockPomodoroRepository)
+		timeEntryRepo := new(mocks.MockTimeEntryRepository)
+		session := pomodoro.Start(primitive.NewObjectID(), nil, start)
+		sessionRepo.On("Save", ctx, session, mock.Anything).Return(nil)
+		timeEntryRepo.On("Create", ctx, mock.Anything).Return(&models.TimeEntry{}, nil)
+
+		transition, err := advancePomodoro(ctx, sessionRepo, timeEntryRepo, session, start.Add(3*time.Hour), time.Hour)
+
+		require.NoError(t, err)
+		assert.Nil(t, transition)
+		assert.Equal(t, models.PomodoroStatusStopped, session.Status)
+		assert.Equal(t, start.Add(time.Hour), session.StoppedAt.Time().UTC())
+		// work, break, work and the break interrupted by the limit
+		timeEntryRepo.AssertNumberOfCalls(t, "Create", 4)
+	})
+}
@@ -0,0 +1,246 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func habitWithReminder(frequency string, reminder string) *models.Habit {
+	start := primitive.NewDateTimeFromTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	return &models.Habit{
+		ID:        primitive.NewObjectID(),
+		Frequency: &frequency,
+		StartDate: &start,
+		Reminders: []string{reminder},
+	}
+}
+
+func TestShouldSendHabitNotification(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	sunday := []int{6}
+	monday := []int{0}
+	fifteenth := []primitive.DateTime{primitive.NewDateTimeFromTime(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC))}
+
+	tests := []struct {
+		name     string
+		habit    *models.Habit
+		now      time.Time
+		expected bool
+	}{
+		{
+			name:     "daily reminder at the wall clock time of paris",
+			habit:    habitWithReminder(models.FrequencyDaily, "08:00"),
+			now:      time.Date(2025, 6, 2, 6, 0, 30, 0, time.UTC).In(paris),
+			expected: true,
+		},
+		{
+			name:     "daily reminder is not sent at the same time in utc",
+			habit:    habitWithReminder(models.FrequencyDaily, "08:00"),
+			now:      time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC).In(paris),
+			expected: false,
+		},
+		{
+			name:     "daily reminder on the previous utc day in tokyo",
+			habit:    habitWithReminder(models.FrequencyDaily, "07:00"),
+			now:      time.Date(2025, 6, 1, 22, 0, 0, 0, time.UTC).In(tokyo),
+			expected: true,
+		},
+		{
+			name: "weekly reminder on sunday",
+			habit: func() *models.Habit {
+				habit := habitWithReminder(models.FrequencyWeekly, "09:00")
+				habit.DaysOfWeek = &sunday
+				return habit
+			}(),
+			now:      time.Date(2025, 6, 1, 9, 0, 0, 0, paris),
+			expected: true,
+		},
+		{
+			name: "weekly reminder uses the day of the user",
+			habit: func() *models.Habit {
+				habit := habitWithReminder(models.FrequencyWeekly, "00:30")
+				habit.DaysOfWeek = &monday
+				return habit
+			}(),
+			// sunday in utc, monday in paris
+			now:      time.Date(2025, 6, 1, 22, 30, 0, 0, time.UTC).In(paris),
+			expected: true,
+		},
+		{
+			name: "monthly reminder on the day of the month",
+			habit: func() *models.Habit {
+				habit := habitWithReminder(models.FrequencyMonthly, "20:00")
+				habit.DaysOfMonth = &fifteenth
+				return habit
+			}(),
+			now:      time.Date(2025, 7, 15, 20, 0, 0, 0, tokyo),
+			expected: true,
+		},
+		{
+			name: "monthly reminder on another day",
+			habit: func() *models.Habit {
+				habit := habitWithReminder(models.FrequencyMonthly, "20:00")
+				habit.DaysOfMonth = &fifteenth
+				return habit
+			}(),
+			now:      time.Date(2025, 7, 16, 20, 0, 0, 0, tokyo),
+			expected: false,
+		},
+		{
+			name:     "reminder in the gap of the switch to summer time is sent after the switch",
+			habit:    habitWithReminder(models.FrequencyDaily, "02:30"),
+			now:      time.Date(2025, 3, 30, 1, 30, 0, 0, time.UTC).In(paris),
+			expected: true,
+		},
+		{
+			name: "ended habit",
+			habit: func() *models.Habit {
+				habit := habitWithReminder(models.FrequencyDaily, "08:00")
+				end := primitive.NewDateTimeFromTime(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))
+				habit.EndDate = &end
+				return habit
+			}(),
+			now:      time.Date(2025, 6, 2, 8, 0, 0, 0, paris),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			send, _ := shouldSendHabitNotification(tt.habit, tt.now)
+			assert.Equal(t, tt.expected, send)
+		})
+	}
+}
+
+func TestShouldSendHabitNotificationOnceOnFallBack(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	// 02:30 happens twice in paris on october 26th 2025, at 00:30 and 01:30 utc
+	habit := habitWithReminder(models.FrequencyDaily, "02:30")
+	sent := 0
+	for minute := time.Date(2025, 10, 25, 22, 0, 0, 0, time.UTC); minute.Before(time.Date(2025, 10, 26, 4, 0, 0, 0, time.UTC)); minute = minute.Add(time.Minute) {
+		if send, _ := shouldSendHabitNotification(habit, minute.In(paris)); send {
+			sent++
+		}
+	}
+	assert.Equal(t, 1, sent)
+}
+
+func TestHabitReminderBetween(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	habit := habitWithReminder(models.FrequencyDaily, "08:00")
+	habit.Reminders = append(habit.Reminders, "08:30")
+	now := time.Date(2025, 6, 2, 7, 0, 10, 0, time.UTC).In(paris)
+
+	// reminders missed while the cron was down are caught up, the latest one first
+	send, reminder := habitReminderBetween(habit, now.Add(-time.Hour), now, paris)
+	assert.True(t, send)
+	assert.True(t, time.Date(2025, 6, 2, 8, 30, 0, 0, paris).Equal(reminder))
+
+	// nothing is sent when the window only covers the current minute
+	send, _ = habitReminderBetween(habit, now.Add(-time.Minute), now, paris)
+	assert.False(t, send)
+}
+
+func TestTaskOccurrencesBetweenInUserTimezone(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	// every day at 09:00 in paris, created before the switch to summer time
+	rule := "FREQ=DAILY"
+	completed := false
+	start := primitive.NewDateTimeFromTime(time.Date(2025, 3, 28, 9, 0, 0, 0, paris))
+	end := primitive.NewDateTimeFromTime(time.Date(2025, 3, 28, 10, 0, 0, 0, paris))
+	task := &models.TaskEntity{
+		ID:         primitive.NewObjectID().Hex(),
+		Title:      "Standup",
+		StartDate:  &start,
+		EndDate:    &end,
+		Completed:  &completed,
+		Recurrence: &rule,
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		loc      *time.Location
+		expected string
+	}{
+		{
+			name:     "starting at 09:00 in paris after the switch",
+			now:      time.Date(2025, 3, 31, 9, 0, 0, 0, paris),
+			loc:      paris,
+			expected: "starting",
+		},
+		{
+			name:     "due at 10:00 in paris after the switch",
+			now:      time.Date(2025, 3, 31, 10, 0, 20, 0, paris),
+			loc:      paris,
+			expected: "due",
+		},
+		{
+			name:     "utc drifts by one hour after the switch",
+			now:      time.Date(2025, 3, 31, 9, 0, 0, 0, paris),
+			loc:      time.UTC,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notification := ""
+			from := tt.now.Add(-time.Minute)
+			for _, occurrence := range taskOccurrencesBetween(task, from, tt.now, tt.loc) {
+				if notification, _ = shouldSendTaskNotification(occurrence, task.Completed, from, tt.now); notification != "" {
+					break
+				}
+			}
+			assert.Equal(t, tt.expected, notification)
+		})
+	}
+}
+
+func TestShouldSendTaskNotificationCatchUp(t *testing.T) {
+	completed := false
+	now := time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC)
+	first := primitive.NewDateTimeFromTime(now.Add(-40 * time.Minute))
+	second := primitive.NewDateTimeFromTime(now.Add(-20 * time.Minute))
+	occurrence := &models.TaskOccurrence{Reminders: []*primitive.DateTime{&first, &second}}
+
+	kind, date := shouldSendTaskNotification(occurrence, &completed, now.Add(-time.Hour), now)
+	assert.Equal(t, "reminder", kind)
+	assert.True(t, second.Time().Equal(date))
+
+	kind, _ = shouldSendTaskNotification(occurrence, &completed, now.Add(-time.Minute), now)
+	assert.Empty(t, kind)
+}
+
+func TestIsDateBetween(t *testing.T) {
+	completed := true
+	notCompleted := false
+	now := time.Date(2025, 6, 2, 8, 0, 45, 0, time.UTC)
+	from := now.Add(-time.Minute)
+	date := primitive.NewDateTimeFromTime(time.Date(2025, 6, 2, 10, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)))
+	next := primitive.NewDateTimeFromTime(now.Add(time.Minute))
+	previous := primitive.NewDateTimeFromTime(from)
+
+	assert.True(t, isDateBetween(&date, &notCompleted, from, now))
+	assert.False(t, isDateBetween(&date, &completed, from, now))
+	assert.False(t, isDateBetween(&next, &notCompleted, from, now))
+	assert.False(t, isDateBetween(&previous, &notCompleted, from, now))
+	assert.False(t, isDateBetween(nil, &notCompleted, from, now))
+}
@@ -0,0 +1,142 @@
+package notifications
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+	userclient "github.com/atomic-blend/backend/shared/grpc/user"
+	"github.com/atomic-blend/backend/shared/utils/db"
+	fcmutils "github.com/atomic-blend/backend/shared/utils/fcm_utils"
+
+	"firebase.google.com/go/v4/messaging"
+	fcm "github.com/appleboy/go-fcm"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// snoozedJob is the name of the job sending the snoozed notifications
+const snoozedJob = "snoozed"
+
+// kindSnoozed is the kind of the notifications sent again after a snooze in the ledger
+const kindSnoozed = "snoozed"
+
+// SnoozedNotificationCron is a cron job that sends again the notifications snoozed by the users
+func SnoozedNotificationCron() {
+	log.Debug().Msg("Starting snoozed notification cron job")
+	ctx := context.TODO()
+
+	taskRepo := repositories.NewTaskRepository(db.Database)
+	habitRepo := repositories.NewHabitRepository(db.Database)
+	ledger := repositories.NewNotificationRepository(db.Database)
+
+	userService, err := userclient.NewUserClient()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create user client")
+		return
+	}
+
+	if firebaseProjectID == "" {
+		log.Error().Msg("FIREBASE_PROJECT_ID is required for FCM")
+		return
+	}
+
+	fcmClient, err := fcm.NewClient(
+		ctx,
+		fcm.WithProjectID(firebaseProjectID),
+		fcm.WithCredentialsFile(os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")),
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create FCM client")
+		return
+	}
+
+	now := time.Now()
+	from := notificationWindow(ctx, ledger, snoozedJob, now)
+
+	snoozes, err := ledger.GetSnoozesBetween(ctx, from, now)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get snoozed notifications")
+		return
+	}
+
+	send := func(ctx context.Context, data map[string]string, deviceTokens []string) (*messaging.BatchResponse, error) {
+		return fcmutils.SendMulticast(ctx, fcmClient, data, deviceTokens)
+	}
+	devices := newUserDevices(userService)
+
+	for _, snooze := range snoozes {
+		data, err := snoozedData(ctx, taskRepo, habitRepo, snooze)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to load snoozed %s: %s", snooze.EntityType, snooze.EntityID)
+			continue
+		}
+		if data == nil {
+			log.Debug().Msgf("Snoozed %s no longer needs a notification: %s", snooze.EntityType, snooze.EntityID)
+			continue
+		}
+
+		userDevices, err := devices.get(ctx, snooze.UserID.Hex())
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to get user devices for user: %s", snooze.UserID.Hex())
+			continue
+		}
+		deviceTokens := fcmTokens(userDevices)
+		if len(deviceTokens) == 0 {
+			continue
+		}
+
+		notification := &models.Notification{
+			UserID:     snooze.UserID,
+			EntityType: snooze.EntityType,
+			EntityID:   snooze.EntityID,
+			Kind:       kindSnoozed,
+			Occurrence: snooze.RemindAt,
+		}
+		if dispatch(ctx, ledger, send, notification, data, deviceTokens) {
+			log.Debug().Msgf("Sent snoozed notification for %s: %s", snooze.EntityType, snooze.EntityID)
+		}
+	}
+
+	if err := ledger.SetLastRun(ctx, snoozedJob, now); err != nil {
+		log.Error().Err(err).Msg("Failed to save the last run of the snoozed notifications")
+	}
+}
+
+// snoozedData returns the data of the notification to send again for a snooze, built like the original
+// notification. It returns nil when the entity was deleted, or when the task was completed in the meantime.
+func snoozedData(ctx context.Context, taskRepo repositories.TaskRepositoryInterface, habitRepo repositories.HabitRepositoryInterface, snooze *models.NotificationSnooze) (map[string]string, error) {
+	occurrence := snooze.RemindAt.Time()
+	if snooze.Occurrence != nil {
+		occurrence = snooze.Occurrence.Time()
+	}
+
+	switch snooze.EntityType {
+	case models.NotificationEntityTask:
+		task, err := taskRepo.GetByID(ctx, snooze.EntityID)
+		if err != nil || task == nil || task.User != snooze.UserID {
+			return nil, err
+		}
+		if task.Completed != nil && *task.Completed {
+			return nil, nil
+		}
+		payload := taskPayload(task, snooze.Kind, occurrence)
+		if payload == nil {
+			return nil, nil
+		}
+		return payload.GetData(), nil
+	case models.NotificationEntityHabit:
+		habitID, err := primitive.ObjectIDFromHex(snooze.EntityID)
+		if err != nil {
+			return nil, nil
+		}
+		habit, err := habitRepo.GetByID(ctx, habitID)
+		if err != nil || habit == nil || habit.UserID != snooze.UserID {
+			return nil, err
+		}
+		return habitPayload(habit, occurrence).GetData(), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,81 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSnoozedData(t *testing.T) {
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	occurrence := primitive.NewDateTimeFromTime(time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC))
+	remindAt := primitive.NewDateTimeFromTime(time.Date(2025, 6, 2, 8, 10, 0, 0, time.UTC))
+
+	t.Run("task reminder is sent again with its actions", func(t *testing.T) {
+		taskRepo := new(mocks.MockTaskRepository)
+		habitRepo := new(mocks.MockHabitRepository)
+		completed := false
+		task := &models.TaskEntity{ID: primitive.NewObjectID().Hex(), Title: "Call", User: userID, Completed: &completed}
+		taskRepo.On("GetByID", ctx, task.ID).Return(task, nil)
+
+		data, err := snoozedData(ctx, taskRepo, habitRepo, &models.NotificationSnooze{
+			UserID:     userID,
+			EntityType: models.NotificationEntityTask,
+			EntityID:   task.ID,
+			Kind:       "reminder",
+			Occurrence: &occurrence,
+			RemindAt:   remindAt,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "TASK_REMINDER", data["type"])
+		assert.Equal(t, task.ID, data["entityId"])
+		assert.Equal(t, "2025-06-02T08:00:00Z", data["occurrence"])
+		assert.Equal(t, "complete,snooze", data["actions"])
+	})
+
+	t.Run("completed task is not sent again", func(t *testing.T) {
+		taskRepo := new(mocks.MockTaskRepository)
+		habitRepo := new(mocks.MockHabitRepository)
+		completed := true
+		task := &models.TaskEntity{ID: primitive.NewObjectID().Hex(), Title: "Call", User: userID, Completed: &completed}
+		taskRepo.On("GetByID", ctx, task.ID).Return(task, nil)
+
+		data, err := snoozedData(ctx, taskRepo, habitRepo, &models.NotificationSnooze{
+			UserID:     userID,
+			EntityType: models.NotificationEntityTask,
+			EntityID:   task.ID,
+			Kind:       "due",
+			RemindAt:   remindAt,
+		})
+		require.NoError(t, err)
+		assert.Nil(t, data)
+	})
+
+	t.Run("habit reminder is sent again with its actions", func(t *testing.T) {
+		taskRepo := new(mocks.MockTaskRepository)
+		habitRepo := new(mocks.MockHabitRepository)
+		name := "Read"
+		habit := &models.Habit{ID: primitive.NewObjectID(), UserID: userID, Name: &name}
+		habitRepo.On("GetByID", ctx, habit.ID).Return(habit, nil)
+
+		data, err := snoozedData(ctx, taskRepo, habitRepo, &models.NotificationSnooze{
+			UserID:     userID,
+			EntityType: models.NotificationEntityHabit,
+			EntityID:   habit.ID.Hex(),
+			Kind:       "reminder",
+			RemindAt:   remindAt,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "HABIT_REMINDER", data["type"])
+		assert.Equal(t, "Read", data["title"])
+		assert.Equal(t, "log_habit,snooze", data["actions"])
+	})
+}
@@ -5,16 +5,15 @@ import (
 	"os"
 	"time"
 
-	"connectrpc.com/connect"
-	authv1 "github.com/atomic-blend/backend/grpc/gen/auth/v1"
-	userv1 "github.com/atomic-blend/backend/grpc/gen/user/v1"
 	"github.com/atomic-blend/backend/productivity/cron/notifications/payloads"
 	"github.com/atomic-blend/backend/productivity/models"
 	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/utils/recurrence"
 	userclient "github.com/atomic-blend/backend/shared/grpc/user"
 	"github.com/atomic-blend/backend/shared/utils/db"
 	fcmutils "github.com/atomic-blend/backend/shared/utils/fcm_utils"
 
+	"firebase.google.com/go/v4/messaging"
 	fcm "github.com/appleboy/go-fcm"
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -31,6 +30,7 @@ func TaskDueNotificationCron() {
 	ctx := context.TODO()
 
 	taskRepo := repositories.NewTaskRepository(db.Database)
+	ledger := repositories.NewNotificationRepository(db.Database)
 
 	userService, err := userclient.NewUserClient()
 	if err != nil {
@@ -67,12 +67,35 @@ func TaskDueNotificationCron() {
 	now := time.Now()
 	log.Debug().Msgf("Current time: %s", now.Format(time.RFC3339))
 
+	// notifications missed since the last run are sent as well
+	from := notificationWindow(ctx, ledger, taskDueJob, now)
+	send := func(ctx context.Context, data map[string]string, deviceTokens []string) (*messaging.BatchResponse, error) {
+		return fcmutils.SendMulticast(ctx, fcmClient, data, deviceTokens)
+	}
+
+	devices := newUserDevices(userService)
+
 	// Process each task
 	for _, task := range tasks {
 		log.Debug().Msgf("Processing task: %s", task.ID)
 
-		// Check if this task should send a notification now
-		notificationType := shouldSendTaskNotification(task)
+		// Get the user for this task
+		userID := task.User.Hex()
+
+		// Recurring tasks repeat at the wall clock time of the user
+		loc := time.UTC
+		if recurrence.IsRecurring(task) {
+			loc = devices.location(ctx, userID)
+		}
+
+		// Check if this task, or one of its occurrences, should send a notification now
+		var notificationType string
+		var notificationDate time.Time
+		for _, candidate := range taskOccurrencesBetween(task, from, now, loc) {
+			if notificationType, notificationDate = shouldSendTaskNotification(candidate, task.Completed, from, now); notificationType != "" {
+				break
+			}
+		}
 		if notificationType == "" {
 			log.Debug().Msgf("No notification needed for task: %s", task.ID)
 			continue
@@ -80,30 +103,14 @@ func TaskDueNotificationCron() {
 
 		log.Debug().Msgf("Notification type: %s for task: %s", notificationType, task.ID)
 
-		// Get the user for this task
-		userID := task.User.Hex()
-
 		// get user devices using gRPC client
-		req := &connect.Request[userv1.GetUserDevicesRequest]{
-			Msg: &userv1.GetUserDevicesRequest{
-				User: &authv1.User{
-					Id: userID,
-				},
-			},
-		}
-		resp, err := userService.GetUserDevices(ctx, req)
+		userDevices, err := devices.get(ctx, userID)
 		if err != nil {
 			log.Error().Err(err).Msgf("Failed to get user devices for task: %s", task.ID)
 			continue
 		}
 
-		deviceTokens := []string{}
-		for _, device := range resp.Msg.Devices {
-			if device.FcmToken != "" {
-				deviceTokens = append(deviceTokens, device.FcmToken)
-			}
-		}
-
+		deviceTokens := fcmTokens(userDevices)
 		if len(deviceTokens) == 0 {
 			log.Debug().Msgf("No device tokens found for user: %s", userID)
 			continue
@@ -112,74 +119,126 @@ func TaskDueNotificationCron() {
 		log.Debug().Msgf("Found %d device tokens for user: %s", len(deviceTokens), userID)
 
 		// Create and send appropriate notification based on type
-		var payload interface{ GetData() map[string]string }
-
-		switch notificationType {
-		case "due":
-			payload = payloads.NewTaskDuePayload(task.Title)
-		case "starting":
-			payload = payloads.NewTaskStartingPayload(task.Title)
-		case "reminder":
-			// Find the specific reminder that triggered
-			for _, reminder := range task.Reminders {
-				if isDateNow(reminder, task.Completed) {
-					payload = payloads.NewTaskReminderPayload(
-						task.Title,
-						reminder.Time().UTC().Format(time.RFC3339),
-					)
-					break
-				}
-			}
-		}
-
+		payload := taskPayload(task, notificationType, notificationDate)
 		if payload == nil {
 			log.Error().Msgf("Failed to create payload for task: %s", task.ID)
 			continue
 		}
 
 		data := payload.GetData()
-		fcmutils.SendMulticast(ctx, fcmClient, data, deviceTokens)
-		log.Debug().Msgf("Sent %s notification for task: %s", notificationType, task.ID)
+		notification := &models.Notification{
+			UserID:     task.User,
+			EntityType: models.NotificationEntityTask,
+			EntityID:   task.ID,
+			Kind:       notificationType,
+			Occurrence: primitive.NewDateTimeFromTime(notificationDate),
+		}
+		if dispatch(ctx, ledger, send, notification, data, deviceTokens) {
+			log.Debug().Msgf("Sent %s notification for task: %s", notificationType, task.ID)
+		}
+	}
+
+	if err := ledger.SetLastRun(ctx, taskDueJob, now); err != nil {
+		log.Error().Err(err).Msg("Failed to save the last run of the task due notifications")
+	}
+}
+
+// taskPayload creates the payload of a notification of the given type for the occurrence of a task at date
+func taskPayload(task *models.TaskEntity, notificationType string, date time.Time) interface{ GetData() map[string]string } {
+	occurrence := date.UTC().Format(time.RFC3339)
+	actionable := payloads.NewTaskActionable(task.ID, notificationType, occurrence)
+
+	switch notificationType {
+	case "due":
+		return payloads.NewTaskDuePayload(actionable, task.Title)
+	case "starting":
+		return payloads.NewTaskStartingPayload(actionable, task.Title)
+	case "reminder":
+		return payloads.NewTaskReminderPayload(actionable, task.Title, occurrence)
 	}
+	return nil
 }
 
-// shouldSendTaskNotification determines if a task should send a notification at the current time
-// Returns notification type ("due", "starting", "reminder") or empty string if no notification
-func shouldSendTaskNotification(task *models.TaskEntity) string {
+// taskOccurrencesBetween returns the occurrences of a task that may trigger a notification between from and to.
+// A non recurring task has a single occurrence made of its own dates, the occurrences of a recurring
+// task are evaluated in the timezone of the user.
+func taskOccurrencesBetween(task *models.TaskEntity, from, to time.Time, loc *time.Location) []*models.TaskOccurrence {
+	if !recurrence.IsRecurring(task) {
+		return []*models.TaskOccurrence{{
+			TaskID:    task.ID,
+			StartDate: task.StartDate,
+			EndDate:   task.EndDate,
+			Reminders: task.Reminders,
+		}}
+	}
+
+	// the occurrence anchor can be before (due date) or after (reminders) the notification time,
+	// so the window must cover the largest offset between the anchor and the other dates
+	span := time.Minute
+	anchor := task.StartDate
+	if anchor == nil {
+		anchor = task.EndDate
+	}
+	for _, date := range append([]*primitive.DateTime{task.EndDate}, task.Reminders...) {
+		if date == nil {
+			continue
+		}
+		offset := date.Time().Sub(anchor.Time())
+		if offset < 0 {
+			offset = -offset
+		}
+		if offset+time.Minute > span {
+			span = offset + time.Minute
+		}
+	}
+
+	occurrences, err := recurrence.ExpandTaskIn(task, from.Add(-span), to.Add(span), loc)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to expand recurring task: %s", task.ID)
+		return nil
+	}
+	return occurrences
+}
+
+// shouldSendTaskNotification determines if a task occurrence should send a notification between from, excluded, and to.
+// Returns the notification type ("due", "starting", "reminder") and the date that triggered it,
+// or an empty string if no notification
+func shouldSendTaskNotification(occurrence *models.TaskOccurrence, completed *bool, from, to time.Time) (string, time.Time) {
 	// Check if task is due
-	if isDateNow(task.EndDate, task.Completed) {
-		log.Debug().Msgf("Task is due: %s", task.EndDate.Time().Format(time.RFC3339))
-		return "due"
+	if isDateBetween(occurrence.EndDate, completed, from, to) {
+		log.Debug().Msgf("Task is due: %s", occurrence.EndDate.Time().Format(time.RFC3339))
+		return "due", occurrence.EndDate.Time()
 	}
 
 	// Check if task is starting
-	if isDateNow(task.StartDate, task.Completed) {
-		log.Debug().Msgf("Task is starting: %s", task.StartDate.Time().Format(time.RFC3339))
-		return "starting"
+	if isDateBetween(occurrence.StartDate, completed, from, to) {
+		log.Debug().Msgf("Task is starting: %s", occurrence.StartDate.Time().Format(time.RFC3339))
+		return "starting", occurrence.StartDate.Time()
 	}
 
-	// Check for reminders
-	for _, reminder := range task.Reminders {
-		if isDateNow(reminder, task.Completed) {
-			log.Debug().Msgf("Task reminder: %s", reminder.Time().Format(time.RFC3339))
-			return "reminder"
+	// Check for reminders, the latest one first
+	var latest *primitive.DateTime
+	for _, reminder := range occurrence.Reminders {
+		if isDateBetween(reminder, completed, from, to) && (latest == nil || reminder.Time().After(latest.Time())) {
+			latest = reminder
 		}
 	}
+	if latest != nil {
+		log.Debug().Msgf("Task reminder: %s", latest.Time().Format(time.RFC3339))
+		return "reminder", latest.Time()
+	}
 
-	return ""
+	return "", time.Time{}
 }
 
-func isDateNow(date *primitive.DateTime, completed *bool) bool {
-	now := time.Now()
+// isDateBetween returns true if the minute of the date is after the minute of from and not after the minute of to,
+// and the task is not completed. Dates are instants, they are compared regardless of the timezone of the server.
+func isDateBetween(date *primitive.DateTime, completed *bool, from, to time.Time) bool {
 	if date == nil {
 		return false
 	}
 
-	dateTime := date.Time()
-	return dateTime.Year() == now.Year() &&
-		dateTime.Month() == now.Month() &&
-		dateTime.Day() == now.Day() &&
-		dateTime.Hour() == now.Hour() &&
-		dateTime.Minute() == now.Minute() &&
+	minute := date.Time().Truncate(time.Minute)
+	return minute.After(from.Truncate(time.Minute)) && !minute.After(to.Truncate(time.Minute)) &&
 		completed != nil && !*completed
 }
@@ -0,0 +1,54 @@
+package timers
+
+import (
+	"context"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/productivity/utils/timer"
+	"github.com/atomic-blend/backend/shared/utils/db"
+
+	"github.com/rs/zerolog/log"
+)
+
+// AutoStopTimersCron is a cron job that stops the timers forgotten by their users.
+// A timer which tracked more than timer.MaxDuration is stopped, its duration capped to it.
+func AutoStopTimersCron() {
+	log.Debug().Msg("Starting timer auto-stop cron job")
+	ctx := context.TODO()
+
+	timeEntryRepo := repositories.NewTimeEntryRepository(db.Database)
+	stopped, err := autoStopTimers(ctx, timeEntryRepo, time.Now(), timer.MaxDuration())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to retrieve running timers")
+		return
+	}
+
+	if stopped > 0 {
+		log.Info().Int("count", stopped).Msg("Stopped timers running for too long")
+	}
+}
+
+// autoStopTimers stops the active timers which tracked more than limit and returns how many were stopped
+func autoStopTimers(ctx context.Context, timeEntryRepo repositories.TimeEntryRepositoryInterface, now time.Time, limit time.Duration) (int, error) {
+	timeEntries, err := timeEntryRepo.GetActiveTimers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	stopped := 0
+	for _, timeEntry := range timeEntries {
+		expired, err := timer.StopAfter(timeEntry, now, limit)
+		if err != nil || !expired {
+			continue
+		}
+
+		// the timer may have been stopped by its user in the meantime
+		if _, err := timeEntryRepo.UpdateTimer(ctx, timeEntry); err != nil {
+			log.Error().Err(err).Str("time_entry_id", timeEntry.ID.Hex()).Msg("Failed to stop timer")
+			continue
+		}
+		stopped++
+	}
+	return stopped, nil
+}
@@ -0,0 +1,46 @@
+package timers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+	"github.com/atomic-blend/backend/productivity/utils/timer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestAutoStopTimers(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 3, 12, 0, 0, 0, time.UTC)
+
+	newTimer := func(startedAgo time.Duration) *models.TimeEntry {
+		entry := timer.Start(primitive.NewObjectID(), nil, now.Add(-startedAgo))
+		id := primitive.NewObjectID()
+		entry.ID = &id
+		return entry
+	}
+	forgotten := newTimer(30 * time.Hour)
+	recent := newTimer(time.Hour)
+	stoppedByUser := newTimer(48 * time.Hour)
+
+	repo := new(mocks.MockTimeEntryRepository)
+	repo.On("GetActiveTimers", ctx).Return([]*models.TimeEntry{forgotten, recent, stoppedByUser}, nil)
+	repo.On("UpdateTimer", ctx, forgotten).Return(forgotten, nil)
+	repo.On("UpdateTimer", ctx, stoppedByUser).Return(nil, mongo.ErrNoDocuments)
+
+	stopped, err := autoStopTimers(ctx, repo, now, 24*time.Hour)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, stopped)
+	assert.Equal(t, models.TimerStateStopped, *forgotten.TimerState)
+	assert.Equal(t, "86400", *forgotten.Duration)
+	assert.Equal(t, models.TimerStateRunning, *recent.TimerState)
+	repo.AssertNotCalled(t, "UpdateTimer", ctx, recent)
+	repo.AssertExpectations(t)
+}
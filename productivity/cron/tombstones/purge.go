@@ -0,0 +1,45 @@
+package tombstones
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/repositories"
+	"github.com/atomic-blend/backend/shared/utils/db"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultRetentionDays is how long tombstones are kept when SYNC_TOMBSTONE_RETENTION_DAYS is not set
+const defaultRetentionDays = 90
+
+// PurgeTombstonesCron is a cron job that deletes the tombstones of the sync change feed older than the retention window.
+// Clients that did not synchronize within the window receive a full snapshot on their next sync.
+func PurgeTombstonesCron() {
+	log.Debug().Msg("Starting tombstone purge cron job")
+	ctx := context.TODO()
+
+	changeRepo := repositories.NewChangeRepository(db.Database)
+
+	before := time.Now().AddDate(0, 0, -RetentionDays())
+	purged, err := changeRepo.PurgeTombstones(ctx, before)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to purge tombstones")
+		return
+	}
+
+	if purged > 0 {
+		log.Info().Int64("count", purged).Msg("Purged tombstones")
+	}
+}
+
+// RetentionDays returns the number of days tombstones are kept, read from SYNC_TOMBSTONE_RETENTION_DAYS
+func RetentionDays() int {
+	days, err := strconv.Atoi(os.Getenv("SYNC_TOMBSTONE_RETENTION_DAYS"))
+	if err != nil || days < 1 {
+		return defaultRetentionDays
+	}
+	return days
+}
@@ -19,8 +19,13 @@ func startGRPCServer() {
 	tagRepo := repositories.NewTagRepository(db.Database)
 	folderRepo := repositories.NewFolderRepository(db.Database)
 	timeEntryRepo := repositories.NewTimeEntryRepository(db.Database)
+	changeRepo := repositories.NewChangeRepository(db.Database)
+	ledgerRepo := repositories.NewNotificationRepository(db.Database)
+	pomodoroRepo := repositories.NewPomodoroRepository(db.Database)
+	filterRepo := repositories.NewSavedFilterRepository(db.Database)
+	calendarRepo := repositories.NewCalendarFeedRepository(db.Database)
 
-	globalGRPCServer := globalGRPC.NewGrpcServer(taskRepo, habitRepo, noteRepo, tagRepo, folderRepo, timeEntryRepo)
+	globalGRPCServer := globalGRPC.NewGrpcServer(taskRepo, habitRepo, noteRepo, tagRepo, folderRepo, timeEntryRepo, changeRepo, ledgerRepo, pomodoroRepo, filterRepo, calendarRepo)
 
 	// TODO: register gRPC services here
 	globalPath, globalHandler := productivityv1connect.NewProductivityServiceHandler(globalGRPCServer)
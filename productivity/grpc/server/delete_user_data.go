@@ -60,7 +60,7 @@ func (s *GrpcServer) DeleteUserData(ctx context.Context, req *connect.Request[pr
 		}), nil
 	}
 
-	// Delete all notes for the user
+	// Delete all notes for the user (this also removes the links between notes and tasks)
 	if err := s.noteRepo.DeleteByUserID(ctx, userID); err != nil {
 		log.Error().Err(err).Msg("Failed to delete user notes")
 		return connect.NewResponse(&productivityv1.DeleteUserDataResponse{
@@ -92,6 +92,46 @@ func (s *GrpcServer) DeleteUserData(ctx context.Context, req *connect.Request[pr
 		}), nil
 	}
 
+	// Delete the sync change feed of the user
+	if err := s.changeRepo.DeleteByUserID(ctx, userID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete user sync changes")
+		return connect.NewResponse(&productivityv1.DeleteUserDataResponse{
+			Success: false,
+		}), nil
+	}
+
+	// Delete the notifications sent to the user
+	if err := s.ledgerRepo.DeleteByUserID(ctx, userID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete user notifications")
+		return connect.NewResponse(&productivityv1.DeleteUserDataResponse{
+			Success: false,
+		}), nil
+	}
+
+	// Delete the pomodoro sessions of the user
+	if err := s.pomodoroRepo.DeleteByUserID(ctx, userID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete user pomodoro sessions")
+		return connect.NewResponse(&productivityv1.DeleteUserDataResponse{
+			Success: false,
+		}), nil
+	}
+
+	// Delete the saved filters of the user
+	if err := s.filterRepo.DeleteByUserID(ctx, userID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete user saved filters")
+		return connect.NewResponse(&productivityv1.DeleteUserDataResponse{
+			Success: false,
+		}), nil
+	}
+
+	// Delete the calendar feed of the user
+	if err := s.calendarRepo.DeleteByUserID(ctx, userID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete user calendar feed")
+		return connect.NewResponse(&productivityv1.DeleteUserDataResponse{
+			Success: false,
+		}), nil
+	}
+
 	log.Info().Str("userID", userIDHex).Msg("Successfully deleted user data")
 
 	return connect.NewResponse(&productivityv1.DeleteUserDataResponse{
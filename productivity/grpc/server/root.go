@@ -12,10 +12,15 @@ type GrpcServer struct {
 	tagRepo       repositories.TagRepositoryInterface
 	folderRepo    repositories.FolderRepositoryInterface
 	timeEntryRepo repositories.TimeEntryRepositoryInterface
+	changeRepo    repositories.ChangeRepositoryInterface
+	ledgerRepo    repositories.NotificationRepositoryInterface
+	pomodoroRepo  repositories.PomodoroRepositoryInterface
+	filterRepo    repositories.SavedFilterRepositoryInterface
+	calendarRepo  repositories.CalendarFeedRepositoryInterface
 }
 
 // NewGrpcServer create a new instance of GrpcServer
-func NewGrpcServer(taskRepo repositories.TaskRepositoryInterface, habitRepo repositories.HabitRepositoryInterface, noteRepo repositories.NoteRepositoryInterface, tagRepo repositories.TagRepositoryInterface, folderRepo repositories.FolderRepositoryInterface, timeEntryRepo repositories.TimeEntryRepositoryInterface) *GrpcServer {
+func NewGrpcServer(taskRepo repositories.TaskRepositoryInterface, habitRepo repositories.HabitRepositoryInterface, noteRepo repositories.NoteRepositoryInterface, tagRepo repositories.TagRepositoryInterface, folderRepo repositories.FolderRepositoryInterface, timeEntryRepo repositories.TimeEntryRepositoryInterface, changeRepo repositories.ChangeRepositoryInterface, ledgerRepo repositories.NotificationRepositoryInterface, pomodoroRepo repositories.PomodoroRepositoryInterface, filterRepo repositories.SavedFilterRepositoryInterface, calendarRepo repositories.CalendarFeedRepositoryInterface) *GrpcServer {
 	return &GrpcServer{
 		taskRepo:      taskRepo,
 		habitRepo:     habitRepo,
@@ -23,5 +28,10 @@ func NewGrpcServer(taskRepo repositories.TaskRepositoryInterface, habitRepo repo
 		tagRepo:       tagRepo,
 		folderRepo:    folderRepo,
 		timeEntryRepo: timeEntryRepo,
+		changeRepo:    changeRepo,
+		ledgerRepo:    ledgerRepo,
+		pomodoroRepo:  pomodoroRepo,
+		filterRepo:    filterRepo,
+		calendarRepo:  calendarRepo,
 	}
 }
@@ -1,15 +1,22 @@
 package main
 
 import (
+	"github.com/atomic-blend/backend/productivity/controllers/calendar"
+	"github.com/atomic-blend/backend/productivity/controllers/filters"
 	"github.com/atomic-blend/backend/productivity/controllers/folder"
 	"github.com/atomic-blend/backend/productivity/controllers/habits"
 	"github.com/atomic-blend/backend/productivity/controllers/health"
+	"github.com/atomic-blend/backend/productivity/controllers/imports"
 	"github.com/atomic-blend/backend/productivity/controllers/notes"
+	"github.com/atomic-blend/backend/productivity/controllers/notifications"
+	pomodorocontroller "github.com/atomic-blend/backend/productivity/controllers/pomodoro"
+	synccontroller "github.com/atomic-blend/backend/productivity/controllers/sync"
 	"github.com/atomic-blend/backend/productivity/controllers/tags"
 	"github.com/atomic-blend/backend/productivity/controllers/tasks"
 	timeentrycontroller "github.com/atomic-blend/backend/productivity/controllers/timeEntry"
 	"github.com/atomic-blend/backend/productivity/cron"
 	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/repositories"
 	"github.com/atomic-blend/backend/shared/utils/db"
 	"context"
 	"os"
@@ -47,6 +54,33 @@ func main() {
 
 	models.RegisterValidators()
 
+	if err := repositories.EnsureTaskIndexes(context.TODO(), db.Database); err != nil {
+		log.Error().Err(err).Msg("❌ Error creating task indexes")
+	}
+	if err := repositories.EnsureChangeIndexes(context.TODO(), db.Database); err != nil {
+		log.Error().Err(err).Msg("❌ Error creating sync change indexes")
+	}
+	if err := repositories.EnsureNotificationIndexes(context.TODO(), db.Database); err != nil {
+		log.Error().Err(err).Msg("❌ Error creating notification ledger indexes")
+	}
+	if err := repositories.EnsureTimeEntryIndexes(context.TODO(), db.Database); err != nil {
+		log.Error().Err(err).Msg("❌ Error creating time entry indexes")
+	}
+	if err := repositories.EnsurePomodoroIndexes(context.TODO(), db.Database); err != nil {
+		log.Error().Err(err).Msg("❌ Error creating pomodoro session indexes")
+	}
+	if err := repositories.EnsureNoteRevisionIndexes(context.TODO(), db.Database); err != nil {
+		log.Error().Err(err).Msg("❌ Error creating note revision indexes")
+	}
+	if err := repositories.EnsureCalendarFeedIndexes(context.TODO(), db.Database); err != nil {
+		log.Error().Err(err).Msg("❌ Error creating calendar feed indexes")
+	}
+	if migrated, err := repositories.MigrateTaskTags(context.TODO(), db.Database); err != nil {
+		log.Error().Err(err).Msg("❌ Error migrating task tags")
+	} else if migrated > 0 {
+		log.Info().Int64("tasks", migrated).Msg("✅ Migrated the tags of the tasks to references")
+	}
+
 	// start grpc server
 	go startGRPCServer()
 
@@ -153,6 +187,12 @@ func main() {
 	folder.SetupRoutes(router, db.Database)
 	timeentrycontroller.SetupRoutes(router, db.Database)
 	notes.SetupRoutes(router, db.Database)
+	synccontroller.SetupRoutes(router, db.Database)
+	notifications.SetupRoutes(router, db.Database)
+	pomodorocontroller.SetupRoutes(router, db.Database)
+	filters.SetupRoutes(router, db.Database)
+	calendar.SetupRoutes(router, db.Database)
+	imports.SetupRoutes(router, db.Database)
 
 	// Define port
 	port := os.Getenv("PORT")
@@ -0,0 +1,11 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// CalendarFeed is the iCalendar feed of the tasks and habits of a user. Only the hash of its secret token is stored.
+type CalendarFeed struct {
+	ID        primitive.ObjectID `json:"-" bson:"_id"`
+	UserID    primitive.ObjectID `json:"userId" bson:"user_id"`
+	TokenHash string             `json:"-" bson:"token_hash"`
+	CreatedAt primitive.DateTime `json:"createdAt" bson:"created_at"`
+}
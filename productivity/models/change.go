@@ -0,0 +1,39 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Change is the last modification of an item in the change feed of a user.
+// Each item has a single change, its cursor moves forward every time the item is modified.
+type Change struct {
+	ID        primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"-" bson:"user_id"`
+	ItemType  string             `json:"itemType" bson:"item_type"`
+	ItemID    primitive.ObjectID `json:"itemId" bson:"item_id"`
+	Cursor    int64              `json:"cursor" bson:"cursor"`
+	Deleted   bool               `json:"-" bson:"deleted"`
+	ChangedAt primitive.DateTime `json:"changedAt" bson:"changed_at"`
+}
+
+// ChangeSet is a page of the change feed of a user: the current state of the items changed
+// after a cursor, and the tombstones of the items deleted after it
+type ChangeSet struct {
+	// Cursor is the position to resume from on the next request
+	Cursor  int64 `json:"cursor"`
+	HasMore bool  `json:"hasMore"`
+	// ResetRequired is set when the requested cursor is older than the purged tombstones,
+	// the change set is then the first page of a snapshot replacing the local data of the client
+	ResetRequired bool `json:"resetRequired"`
+	// Snapshot is the position to resume a snapshot from, sent back along with the cursor while HasMore is set
+	Snapshot     string         `json:"snapshot,omitempty"`
+	Tasks        []*TaskEntity  `json:"tasks"`
+	Notes        []*NoteEntity  `json:"notes"`
+	Habits       []*Habit       `json:"habits"`
+	HabitEntries []*HabitEntry  `json:"habitEntries"`
+	Folders      []*Folder      `json:"folders"`
+	Tags         []*Tag         `json:"tags"`
+	TimeEntries  []*TimeEntry   `json:"timeEntries"`
+	SavedFilters []*SavedFilter `json:"savedFilters"`
+	Deleted      []*Change      `json:"deleted"`
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// untrackedFields are managed by the server and never tracked
+var untrackedFields = map[string]bool{
+	"id":             true,
+	"user":           true,
+	"createdAt":      true,
+	"updatedAt":      true,
+	"fieldUpdatedAt": true,
+}
+
+// FieldTimestamps records when each field of an entity was last modified, keyed by the JSON name of the field.
+// A field without an entry has not been modified since the entity was created.
+type FieldTimestamps map[string]primitive.DateTime
+
+// NewFieldTimestamps starts tracking the fields of an existing entity, considering every field
+// modified at the date of its last update
+func NewFieldTimestamps(entity interface{}, updatedAt primitive.DateTime) FieldTimestamps {
+	fields := FieldTimestamps{}
+	for key := range jsonFields(entity) {
+		if !untrackedFields[key] {
+			fields[key] = updatedAt
+		}
+	}
+	return fields
+}
+
+// Touch returns a copy of the timestamps where the given fields are modified at the given date
+func (f FieldTimestamps) Touch(at primitive.DateTime, keys ...string) FieldTimestamps {
+	fields := make(FieldTimestamps, len(f)+len(keys))
+	for key, date := range f {
+		fields[key] = date
+	}
+	for _, key := range keys {
+		if !untrackedFields[key] {
+			fields[key] = at
+		}
+	}
+	return fields
+}
+
+// LastModified returns when a field was last modified, or createdAt if it never was
+func (f FieldTimestamps) LastModified(key string, createdAt primitive.DateTime) primitive.DateTime {
+	if date, ok := f[key]; ok {
+		return date
+	}
+	return createdAt
+}
+
+// TrackChanges returns the field timestamps of an entity after it was replaced by a new version.
+// Entities created before fields were tracked start being tracked from their last update.
+func TrackChanges(before, after interface{}, fields FieldTimestamps, updatedAt, now primitive.DateTime) FieldTimestamps {
+	if fields == nil {
+		fields = NewFieldTimestamps(before, updatedAt)
+	}
+	return fields.Touch(now, ChangedFields(before, after)...)
+}
+
+// ChangedFields returns the JSON names of the tracked fields whose value differs between two versions of an entity
+func ChangedFields(before, after interface{}) []string {
+	beforeFields := jsonFields(before)
+	afterFields := jsonFields(after)
+
+	changed := []string{}
+	for key, value := range afterFields {
+		if !untrackedFields[key] && !reflect.DeepEqual(beforeFields[key], value) {
+			changed = append(changed, key)
+		}
+	}
+	for key, value := range beforeFields {
+		if _, ok := afterFields[key]; !ok && value != nil && !untrackedFields[key] {
+			changed = append(changed, key)
+		}
+	}
+	return changed
+}
+
+func jsonFields(entity interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	jsonStr, err := json.Marshal(entity)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(jsonStr, &fields)
+	return fields
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestChangedFields(t *testing.T) {
+	title := "Title"
+	otherTitle := "Other title"
+	content := "Content"
+
+	before := &NoteEntity{Title: &title, Content: &content, UpdatedAt: primitive.NewDateTimeFromTime(time.Now())}
+	after := &NoteEntity{Title: &otherTitle, Content: &content}
+
+	assert.ElementsMatch(t, []string{"title"}, ChangedFields(before, after))
+	assert.Empty(t, ChangedFields(before, before))
+}
+
+func TestFieldTimestamps(t *testing.T) {
+	createdAt := primitive.NewDateTimeFromTime(time.Now().Add(-2 * time.Hour))
+	updatedAt := primitive.NewDateTimeFromTime(time.Now().Add(-1 * time.Hour))
+	now := primitive.NewDateTimeFromTime(time.Now())
+
+	t.Run("touch", func(t *testing.T) {
+		fields := FieldTimestamps{"title": updatedAt}
+		touched := fields.Touch(now, "content", "updatedAt")
+
+		assert.Equal(t, FieldTimestamps{"title": updatedAt}, fields)
+		assert.Equal(t, FieldTimestamps{"title": updatedAt, "content": now}, touched)
+		assert.Equal(t, now, touched.LastModified("content", createdAt))
+		assert.Equal(t, createdAt, touched.LastModified("deleted", createdAt))
+	})
+
+	t.Run("tracking starts from the last update", func(t *testing.T) {
+		title := "Title"
+		otherTitle := "Other title"
+		content := "Content"
+		before := &NoteEntity{Title: &title, Content: &content}
+		after := &NoteEntity{Title: &otherTitle, Content: &content}
+
+		fields := TrackChanges(before, after, nil, updatedAt, now)
+
+		assert.Equal(t, now, fields["title"])
+		assert.Equal(t, updatedAt, fields["content"])
+		assert.NotContains(t, fields, "createdAt")
+	})
+}
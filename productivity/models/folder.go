@@ -13,3 +13,34 @@ type Folder struct {
 	CreatedAt *primitive.DateTime `bson:"created_at,omitempty" json:"createdAt,omitempty"`
 	UpdatedAt *primitive.DateTime `bson:"updated_at,omitempty" json:"updatedAt,omitempty"`
 }
+
+// modes of the deletion of a folder
+const (
+	// FolderDeleteReparent moves the subfolders and the tasks of the folder to its parent
+	FolderDeleteReparent = "reparent"
+	// FolderDeleteCascade deletes the subfolders of the folder, and the tasks of the folder and its subfolders
+	FolderDeleteCascade = "cascade"
+)
+
+// FolderMove is the body of a request moving a folder, a nil parent moves it to the root
+type FolderMove struct {
+	ParentID *primitive.ObjectID `json:"parentId"`
+}
+
+// FolderTaskCount is the number of tasks directly in a folder
+type FolderTaskCount struct {
+	FolderID  primitive.ObjectID `bson:"_id"`
+	Open      int64              `bson:"open"`
+	Completed int64              `bson:"completed"`
+}
+
+// FolderNode is a folder of the folder tree, with its subfolders and the number of its tasks
+type FolderNode struct {
+	*Folder
+	// TaskCount is the number of tasks not completed directly in the folder
+	TaskCount          int64 `json:"taskCount"`
+	CompletedTaskCount int64 `json:"completedTaskCount"`
+	// TotalTaskCount is the number of tasks not completed in the folder and its subfolders
+	TotalTaskCount int64         `json:"totalTaskCount"`
+	Children       []*FolderNode `json:"children"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// HabitStats summarizes how well a user keeps up with a habit
+type HabitStats struct {
+	HabitID primitive.ObjectID `json:"habitId"`
+	// Timezone is the timezone the days, weeks and months were computed in
+	Timezone      string `json:"timezone"`
+	CurrentStreak int    `json:"currentStreak"`
+	LongestStreak int    `json:"longestStreak"`
+	// CompletionRate is the share of the scheduled periods so far that were completed, between 0 and 1
+	CompletionRate float64 `json:"completionRate"`
+	TotalEntries   int     `json:"totalEntries"`
+	// CurrentPeriod is the progress in the last scheduled period, nil when the habit has not started
+	CurrentPeriod *HabitPeriodProgress  `json:"currentPeriod"`
+	Weekly        []HabitCompletionRate `json:"weekly"`
+	Monthly       []HabitCompletionRate `json:"monthly"`
+}
+
+// HabitPeriodProgress is the number of entries logged in a scheduled period against the number of times expected
+type HabitPeriodProgress struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Count     int       `json:"count"`
+	Target    int       `json:"target"`
+	Completed bool      `json:"completed"`
+}
+
+// HabitCompletionRate is the share of the scheduled periods of a week or a month that were completed
+type HabitCompletionRate struct {
+	Start     time.Time `json:"start"`
+	Scheduled int       `json:"scheduled"`
+	Completed int       `json:"completed"`
+	Rate      float64   `json:"rate"`
+}
@@ -6,11 +6,13 @@ import "go.mongodb.org/mongo-driver/bson/primitive"
 // @Summary Note entity
 // @Description Represents a note in the system
 type NoteEntity struct {
-	ID        *primitive.ObjectID `json:"id" bson:"_id"`
-	Title     *string             `json:"title" bson:"title"`
-	Content   *string             `json:"content" bson:"content"`
-	User      primitive.ObjectID  `json:"user" bson:"user"`
-	Deleted   *bool               `json:"deleted,omitempty" bson:"deleted,omitempty"`
-	CreatedAt primitive.DateTime  `json:"createdAt" bson:"created_at"`
-	UpdatedAt primitive.DateTime  `json:"updatedAt" bson:"updated_at"`
+	ID             *primitive.ObjectID  `json:"id" bson:"_id"`
+	Title          *string              `json:"title" bson:"title"`
+	Content        *string              `json:"content" bson:"content"`
+	User           primitive.ObjectID   `json:"user" bson:"user"`
+	Deleted        *bool                `json:"deleted,omitempty" bson:"deleted,omitempty"`
+	TaskIDs        []primitive.ObjectID `json:"taskIds" bson:"-"` // tasks linked to the note, populated when the note is read
+	CreatedAt      primitive.DateTime   `json:"createdAt" bson:"created_at"`
+	UpdatedAt      primitive.DateTime   `json:"updatedAt" bson:"updated_at"`
+	FieldUpdatedAt FieldTimestamps      `json:"fieldUpdatedAt,omitempty" bson:"field_updated_at"`
 }
@@ -0,0 +1,19 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// NoteRevision is a previous version of a note, saved before the note is overwritten
+// @Summary Note revision
+// @Description A previous version of a note that can be restored
+type NoteRevision struct {
+	ID      *primitive.ObjectID `json:"id" bson:"_id"`
+	NoteID  primitive.ObjectID  `json:"noteId" bson:"note_id"`
+	User    primitive.ObjectID  `json:"user" bson:"user"`
+	Rev     int                 `json:"rev" bson:"rev"`
+	Title   *string             `json:"title" bson:"title"`
+	Content *string             `json:"content" bson:"content"`
+	// UpdatedAt is the last modification of the note in this version
+	UpdatedAt primitive.DateTime `json:"updatedAt" bson:"updated_at"`
+	// CreatedAt is when this version was replaced
+	CreatedAt primitive.DateTime `json:"createdAt" bson:"created_at"`
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// entity types of the notification ledger
+const (
+	NotificationEntityTask     = "task"
+	NotificationEntityHabit    = "habit"
+	NotificationEntityPomodoro = "pomodoro"
+)
+
+// statuses of a notification in the ledger
+const (
+	// NotificationStatusPending is set when the notification is claimed, before it is sent
+	NotificationStatusPending = "pending"
+	// NotificationStatusSent is set when at least one device received the notification
+	NotificationStatusSent = "sent"
+	// NotificationStatusFailed is set when no device received the notification
+	NotificationStatusFailed = "failed"
+)
+
+// Notification is an entry of the notification ledger. A notification is sent at most once
+// for each user, entity, kind and occurrence.
+type Notification struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"userId" bson:"user_id"`
+	EntityType string             `json:"entityType" bson:"entity_type"`
+	EntityID   string             `json:"entityId" bson:"entity_id"`
+	// Kind tells which date of the entity triggered the notification: due, starting or reminder
+	Kind string `json:"kind" bson:"kind"`
+	// Occurrence is the date the notification was scheduled at
+	Occurrence   primitive.DateTime     `json:"occurrence" bson:"occurrence"`
+	Status       string                 `json:"status" bson:"status"`
+	SuccessCount int                    `json:"successCount" bson:"success_count"`
+	FailureCount int                    `json:"failureCount" bson:"failure_count"`
+	Deliveries   []NotificationDelivery `json:"deliveries,omitempty" bson:"deliveries,omitempty"`
+	Error        string                 `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt    primitive.DateTime     `json:"createdAt" bson:"created_at"`
+	SentAt       *primitive.DateTime    `json:"sentAt,omitempty" bson:"sent_at,omitempty"`
+}
+
+// NotificationDelivery is the result of a notification for one device, as returned by FCM
+type NotificationDelivery struct {
+	MessageID string `json:"messageId,omitempty" bson:"message_id,omitempty"`
+	Error     string `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+// actions offered by the notifications, applied with the notification action endpoint
+const (
+	// NotificationActionComplete completes the task, or moves a recurring task to its next occurrence
+	NotificationActionComplete = "complete"
+	// NotificationActionSnooze sends the notification again a few minutes later
+	NotificationActionSnooze = "snooze"
+	// NotificationActionLogHabit adds an entry to the habit
+	NotificationActionLogHabit = "log_habit"
+)
+
+// DefaultSnoozeMinutes is how long a notification is snoozed when no duration is given
+const DefaultSnoozeMinutes = 10
+
+// NotificationAction is an action taken by the user from a notification
+type NotificationAction struct {
+	Action     string `json:"action" binding:"required"`
+	EntityType string `json:"entityType" binding:"required"`
+	EntityID   string `json:"entityId" binding:"required"`
+	// Kind is the kind of the notification the action was taken from, it is sent again when snoozed
+	Kind string `json:"kind"`
+	// Occurrence is the date the notification was scheduled at
+	Occurrence *primitive.DateTime `json:"occurrence"`
+	// Minutes is how long the notification is snoozed, DefaultSnoozeMinutes when not set
+	Minutes *int `json:"minutes"`
+}
+
+// NotificationSnooze is a notification snoozed by the user, sent again by the cron at RemindAt
+type NotificationSnooze struct {
+	ID         primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID  `json:"userId" bson:"user_id"`
+	EntityType string              `json:"entityType" bson:"entity_type"`
+	EntityID   string              `json:"entityId" bson:"entity_id"`
+	Kind       string              `json:"kind" bson:"kind"`
+	Occurrence *primitive.DateTime `json:"occurrence,omitempty" bson:"occurrence,omitempty"`
+	RemindAt   primitive.DateTime  `json:"remindAt" bson:"remind_at"`
+	CreatedAt  primitive.DateTime  `json:"createdAt" bson:"created_at"`
+}
@@ -5,4 +5,6 @@ type ConflictedItem struct {
 	Type         string      `json:"type"`
 	PatchID      string      `json:"patchId"`
 	RemoteObject interface{} `json:"remoteObject"`
+	// ConflictingKeys lists the changed keys also modified remotely, it is empty when the whole item conflicts
+	ConflictingKeys []string `json:"conflictingKeys,omitempty"`
 }
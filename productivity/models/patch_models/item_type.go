@@ -5,7 +5,28 @@ const (
 	ItemTypeTask = "task"
 	// ItemTypeNote is the type for notes
 	ItemTypeNote = "note"
+	// ItemTypeHabit is the type for habits
+	ItemTypeHabit = "habit"
+	// ItemTypeHabitEntry is the type for habit entries
+	ItemTypeHabitEntry = "habit_entry"
+	// ItemTypeFolder is the type for folders
+	ItemTypeFolder = "folder"
+	// ItemTypeTag is the type for tags
+	ItemTypeTag = "tag"
+	// ItemTypeTimeEntry is the type for time entries
+	ItemTypeTimeEntry = "time_entry"
+	// ItemTypeSavedFilter is the type for saved filters
+	ItemTypeSavedFilter = "saved_filter"
 )
 
 // ValidItemTypes contains the valid item types for patch operations
-var ValidItemTypes = []string{ItemTypeTask, ItemTypeNote}
+var ValidItemTypes = []string{
+	ItemTypeTask,
+	ItemTypeNote,
+	ItemTypeHabit,
+	ItemTypeHabitEntry,
+	ItemTypeFolder,
+	ItemTypeTag,
+	ItemTypeTimeEntry,
+	ItemTypeSavedFilter,
+}
@@ -0,0 +1,11 @@
+package patchmodels
+
+import "time"
+
+// PatchResponse represents the response structure for the patch operation
+type PatchResponse struct {
+	Success   []string         `json:"success"`
+	Errors    []PatchError     `json:"errors"`
+	Conflicts []ConflictedItem `json:"conflicts"`
+	Date      time.Time        `json:"date"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// phases of a pomodoro session
+const (
+	PomodoroPhaseWork       = "work"
+	PomodoroPhaseShortBreak = "short_break"
+	PomodoroPhaseLongBreak  = "long_break"
+)
+
+// statuses of a pomodoro session
+const (
+	PomodoroStatusActive  = "active"
+	PomodoroStatusStopped = "stopped"
+)
+
+// default settings of a pomodoro session
+const (
+	DefaultPomodoroWorkMinutes           = 25
+	DefaultPomodoroShortBreakMinutes     = 5
+	DefaultPomodoroLongBreakMinutes      = 15
+	DefaultPomodoroCyclesBeforeLongBreak = 4
+)
+
+// PomodoroSettings are the durations of the phases of a pomodoro session.
+// The zero values are replaced with the defaults when the session starts.
+type PomodoroSettings struct {
+	WorkMinutes       int `json:"workMinutes" bson:"work_minutes" binding:"omitempty,min=1,max=240"`
+	ShortBreakMinutes int `json:"shortBreakMinutes" bson:"short_break_minutes" binding:"omitempty,min=1,max=60"`
+	LongBreakMinutes  int `json:"longBreakMinutes" bson:"long_break_minutes" binding:"omitempty,min=1,max=120"`
+	// CyclesBeforeLongBreak is the number of work phases after which the break is a long one
+	CyclesBeforeLongBreak int `json:"cyclesBeforeLongBreak" bson:"cycles_before_long_break" binding:"omitempty,min=1,max=12"`
+}
+
+// PomodoroSession is a series of work and break phases. The phases follow each other until the session
+// is stopped, a time entry is created for each of them.
+type PomodoroSession struct {
+	ID               primitive.ObjectID  `json:"id" bson:"_id"`
+	UserID           primitive.ObjectID  `json:"userId" bson:"user_id"`
+	TaskID           *primitive.ObjectID `json:"taskId,omitempty" bson:"task_id,omitempty"`
+	PomodoroSettings `bson:",inline"`
+	Status           string `json:"status" bson:"status"`
+	Phase            string `json:"phase" bson:"phase"`
+	// Cycle is the number of work phases completed
+	Cycle          int                 `json:"cycle" bson:"cycle"`
+	PhaseStartedAt primitive.DateTime  `json:"phaseStartedAt" bson:"phase_started_at"`
+	PhaseEndsAt    primitive.DateTime  `json:"phaseEndsAt" bson:"phase_ends_at"`
+	CreatedAt      primitive.DateTime  `json:"createdAt" bson:"created_at"`
+	UpdatedAt      primitive.DateTime  `json:"updatedAt" bson:"updated_at"`
+	StoppedAt      *primitive.DateTime `json:"stoppedAt,omitempty" bson:"stopped_at,omitempty"`
+}
+
+// PomodoroStartRequest is the body of a request starting a pomodoro session
+type PomodoroStartRequest struct {
+	TaskID *primitive.ObjectID `json:"taskId"`
+	PomodoroSettings
+}
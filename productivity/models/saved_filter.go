@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SavedFilter is a named task filter of a user, evaluated by the server each time its tasks are requested
+type SavedFilter struct {
+	ID     *primitive.ObjectID `json:"id" bson:"_id"`
+	UserID primitive.ObjectID  `json:"userId" bson:"user_id"`
+	Name   string              `json:"name" bson:"name" binding:"required"`
+	Filter TaskFilter          `json:"filter" bson:"filter"`
+	// DueWithinDays keeps the tasks due between the start of the current day and that many days later,
+	// so that views such as "this week" follow the current date
+	DueWithinDays *int                `json:"dueWithinDays,omitempty" bson:"due_within_days,omitempty" binding:"omitempty,min=0,max=366"`
+	Sort          []TaskSort          `json:"sort,omitempty" bson:"sort,omitempty"`
+	CreatedAt     *primitive.DateTime `json:"createdAt,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt     *primitive.DateTime `json:"updatedAt,omitempty" bson:"updated_at,omitempty"`
+}
+
+// TaskFilter returns the task filter of the saved filter at the given date, the days starting at midnight
+// in the location of now
+func (f *SavedFilter) TaskFilter(now time.Time) *TaskFilter {
+	filter := f.Filter
+	if f.DueWithinDays != nil {
+		year, month, day := now.Date()
+		start := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+		dueFrom := primitive.NewDateTimeFromTime(start)
+		dueTo := primitive.NewDateTimeFromTime(start.AddDate(0, 0, *f.DueWithinDays+1).Add(-time.Millisecond))
+		filter.DueFrom = &dueFrom
+		filter.DueTo = &dueTo
+	}
+	return &filter
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSavedFilterTaskFilter(t *testing.T) {
+	now := time.Date(2025, 6, 11, 15, 30, 0, 0, time.UTC)
+	completed := false
+
+	t.Run("static criteria", func(t *testing.T) {
+		saved := &SavedFilter{Filter: TaskFilter{Completed: &completed, Priorities: []int{3}}}
+
+		filter := saved.TaskFilter(now)
+
+		assert.Equal(t, &completed, filter.Completed)
+		assert.Equal(t, []int{3}, filter.Priorities)
+		assert.Nil(t, filter.DueFrom)
+		assert.Nil(t, filter.DueTo)
+	})
+
+	t.Run("due within days follows the current date", func(t *testing.T) {
+		days := 6
+		saved := &SavedFilter{Filter: TaskFilter{Completed: &completed}, DueWithinDays: &days}
+
+		filter := saved.TaskFilter(now)
+
+		require.NotNil(t, filter.DueFrom)
+		require.NotNil(t, filter.DueTo)
+		assert.Equal(t, primitive.NewDateTimeFromTime(time.Date(2025, 6, 11, 0, 0, 0, 0, time.UTC)), *filter.DueFrom)
+		assert.Equal(t, primitive.NewDateTimeFromTime(time.Date(2025, 6, 17, 23, 59, 59, 999000000, time.UTC)), *filter.DueTo)
+		// the stored filter is left untouched
+		assert.Nil(t, saved.Filter.DueFrom)
+	})
+	t.Run("due within days in the location of the user", func(t *testing.T) {
+		days := 0
+		saved := &SavedFilter{DueWithinDays: &days}
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+
+		// 15:30 UTC is already the next day in Tokyo
+		filter := saved.TaskFilter(now.In(tokyo))
+
+		require.NotNil(t, filter.DueFrom)
+		assert.Equal(t, primitive.NewDateTimeFromTime(time.Date(2025, 6, 12, 0, 0, 0, 0, tokyo)), *filter.DueFrom)
+		assert.Equal(t, primitive.NewDateTimeFromTime(time.Date(2025, 6, 12, 23, 59, 59, 999000000, tokyo)), *filter.DueTo)
+	})
+}
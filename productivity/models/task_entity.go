@@ -12,10 +12,20 @@ type TaskEntity struct {
 	EndDate     *primitive.DateTime   `json:"endDate,omitempty" bson:"end_date"`
 	Reminders   []*primitive.DateTime `json:"reminders,omitempty" bson:"reminders"`
 	Completed   *bool                 `json:"completed" bson:"completed"`
-	Tags        *[]*Tag               `json:"tags" bson:"tags"`
+	TagIDs      []primitive.ObjectID  `json:"tagIds" bson:"tag_ids"`
+	Tags        *[]*Tag               `json:"tags" bson:"-"` // populated from TagIDs when the task is read
+	NoteIDs     []primitive.ObjectID  `json:"noteIds" bson:"note_ids"`
 	Priority    *int                  `json:"priority" bson:"priority"`
 	FolderID    *primitive.ObjectID   `json:"folderId" bson:"folder_id"`
+	// Recurrence is an RFC 5545 RRULE value (e.g. "FREQ=WEEKLY;BYDAY=MO") anchored on StartDate, or EndDate when there is no StartDate
+	Recurrence *string `json:"recurrence,omitempty" bson:"recurrence,omitempty"`
+	// ExcludedDates are the EXDATE occurrences skipped by the recurrence
+	ExcludedDates        []*primitive.DateTime `json:"excludedDates,omitempty" bson:"excluded_dates,omitempty"`
+	ParentID             *primitive.ObjectID   `json:"parentId,omitempty" bson:"parent_id,omitempty"`
+	Position             *int                  `json:"position,omitempty" bson:"position,omitempty"`
+	CompleteWithSubtasks *bool                 `json:"completeWithSubtasks,omitempty" bson:"complete_with_subtasks,omitempty"`
 	// TimeEntries []*TimeEntry          `json:"timeEntries" bson:"time_entries"`
-	CreatedAt primitive.DateTime `json:"createdAt" bson:"created_at"`
-	UpdatedAt primitive.DateTime `json:"updatedAt" bson:"updated_at"`
+	CreatedAt      primitive.DateTime `json:"createdAt" bson:"created_at"`
+	UpdatedAt      primitive.DateTime `json:"updatedAt" bson:"updated_at"`
+	FieldUpdatedAt FieldTimestamps    `json:"fieldUpdatedAt,omitempty" bson:"field_updated_at"`
 }
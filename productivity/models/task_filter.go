@@ -0,0 +1,33 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Task sort keys
+const (
+	TaskSortCreatedAt = "createdAt"
+	TaskSortUpdatedAt = "updatedAt"
+	TaskSortStartDate = "startDate"
+	TaskSortEndDate   = "endDate"
+	TaskSortPriority  = "priority"
+	TaskSortTitle     = "title"
+)
+
+// ValidTaskSortKeys contains the keys tasks can be sorted by
+var ValidTaskSortKeys = []string{TaskSortCreatedAt, TaskSortUpdatedAt, TaskSortStartDate, TaskSortEndDate, TaskSortPriority, TaskSortTitle}
+
+// TaskFilter is a declarative filter over task fields, every set criterion must match
+type TaskFilter struct {
+	Completed  *bool                `json:"completed,omitempty" bson:"completed,omitempty"`
+	DueFrom    *primitive.DateTime  `json:"dueFrom,omitempty" bson:"due_from,omitempty"`
+	DueTo      *primitive.DateTime  `json:"dueTo,omitempty" bson:"due_to,omitempty"`
+	Priorities []int                `json:"priorities,omitempty" bson:"priorities,omitempty"`
+	TagIDs     []primitive.ObjectID `json:"tagIds,omitempty" bson:"tag_ids,omitempty"`
+	FolderID   *primitive.ObjectID  `json:"folderId,omitempty" bson:"folder_id,omitempty"`
+	Overdue    *bool                `json:"overdue,omitempty" bson:"overdue,omitempty"`
+}
+
+// TaskSort is a sort criterion over a task field
+type TaskSort struct {
+	Key        string `json:"key" bson:"key"`
+	Descending bool   `json:"descending" bson:"descending"`
+}
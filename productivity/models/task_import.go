@@ -0,0 +1,35 @@
+package models
+
+// formats of the files tasks are imported from
+const (
+	TaskImportFormatICS = "ics"
+	TaskImportFormatCSV = "csv"
+)
+
+// TaskImportRow is a task read from an imported file
+type TaskImportRow struct {
+	// Row is the line of the file the task begins at
+	Row  int         `json:"row"`
+	Task *TaskEntity `json:"task"`
+	// Tags are the names of the tags of the task, they are created when the user has no tag with the same name
+	Tags []string `json:"tags"`
+	// Folder is the path of the folder of the task, such as "Work/Clients", missing folders are created
+	Folder string `json:"folder,omitempty"`
+}
+
+// TaskImportError is an error on a row of an imported file, the row is not imported
+type TaskImportError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// TaskImportResult is the outcome of an import, or what it would be for a dry run
+type TaskImportResult struct {
+	DryRun bool             `json:"dryRun"`
+	Tasks  []*TaskImportRow `json:"tasks"`
+	// NewTags are the names of the tags created by the import
+	NewTags []string `json:"newTags"`
+	// NewFolders are the paths of the folders created by the import
+	NewFolders []string           `json:"newFolders"`
+	Errors     []*TaskImportError `json:"errors"`
+}
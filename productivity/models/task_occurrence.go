@@ -0,0 +1,11 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// TaskOccurrence represents a single expanded occurrence of a recurring task
+type TaskOccurrence struct {
+	TaskID    string                `json:"taskId"`
+	StartDate *primitive.DateTime   `json:"startDate"`
+	EndDate   *primitive.DateTime   `json:"endDate,omitempty"`
+	Reminders []*primitive.DateTime `json:"reminders,omitempty"`
+}
@@ -4,6 +4,13 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// states of a time entry tracked with a running timer
+const (
+	TimerStateRunning = "running"
+	TimerStatePaused  = "paused"
+	TimerStateStopped = "stopped"
+)
+
 // TimeEntry represents a time entry in a task
 type TimeEntry struct {
 	ID        *primitive.ObjectID `json:"id" bson:"_id"`
@@ -16,6 +23,22 @@ type TimeEntry struct {
 	Pomodoro  *bool               `json:"pomodoro" bson:"pomodoro"`
 	PomoBreak *bool               `json:"pomoBreak" bson:"pomo_break"`
 	Note      *string             `json:"note" bson:"note"`
-	CreatedAt string              `json:"createdAt" bson:"created_at"`
-	UpdatedAt string              `json:"updatedAt" bson:"updated_at"`
+	// TimerState is set on the entries started with the timer endpoints
+	TimerState *string `json:"timerState,omitempty" bson:"timer_state,omitempty"`
+	// PausedAt is when the running timer was paused, set while it is paused
+	PausedAt *string `json:"pausedAt,omitempty" bson:"paused_at,omitempty"`
+	// PausedSeconds is the time the timer spent paused, excluded from the duration
+	PausedSeconds *int64 `json:"pausedSeconds,omitempty" bson:"paused_seconds,omitempty"`
+	// ActiveTimer is only set while the timer is running or paused, a user has at most one
+	ActiveTimer *bool  `json:"-" bson:"active_timer,omitempty"`
+	CreatedAt   string `json:"createdAt" bson:"created_at"`
+	UpdatedAt   string `json:"updatedAt" bson:"updated_at"`
+}
+
+// TimerStartRequest is the body of a request starting a timer
+type TimerStartRequest struct {
+	TaskID    *primitive.ObjectID `json:"taskId"`
+	Pomodoro  *bool               `json:"pomodoro"`
+	PomoBreak *bool               `json:"pomoBreak"`
+	Note      *string             `json:"note"`
 }
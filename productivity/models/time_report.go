@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// groupings of the time tracking reports
+const (
+	ReportGroupTask   = "task"
+	ReportGroupFolder = "folder"
+	ReportGroupTag    = "tag"
+	ReportGroupDay    = "day"
+	ReportGroupWeek   = "week"
+	ReportGroupMonth  = "month"
+)
+
+// ValidReportGroups contains the valid groupings of the time tracking reports
+var ValidReportGroups = []string{ReportGroupTask, ReportGroupFolder, ReportGroupTag, ReportGroupDay, ReportGroupWeek, ReportGroupMonth}
+
+// TimeTotals is the time tracked in a report or in one of its groups, in seconds
+type TimeTotals struct {
+	Entries int `json:"entries"`
+	// TrackedSeconds is the time worked, pomodoro breaks excluded
+	TrackedSeconds int64 `json:"trackedSeconds"`
+	// PomodoroSeconds is the part of the tracked time spent in pomodoro sessions
+	PomodoroSeconds int64 `json:"pomodoroSeconds"`
+	BreakSeconds    int64 `json:"breakSeconds"`
+}
+
+// TimeReportGroup is the time tracked for a task, a folder, a tag or a period.
+// The key is the ID of the task, folder or tag, it is empty for the time not attached to any.
+type TimeReportGroup struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	// Start is the beginning of the period when grouping by day, week or month
+	Start *time.Time `json:"start,omitempty"`
+	TimeTotals
+}
+
+// TimeReport aggregates the time entries of a user over a date range
+type TimeReport struct {
+	From     time.Time         `json:"from"`
+	To       time.Time         `json:"to"`
+	Timezone string            `json:"timezone"`
+	GroupBy  string            `json:"groupBy"`
+	Totals   TimeTotals        `json:"totals"`
+	Groups   []TimeReportGroup `json:"groups"`
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/utils/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const calendarFeedCollection = "calendar_feeds"
+
+// CalendarFeedRepositoryInterface defines the interface for calendar feed repository operations
+type CalendarFeedRepositoryInterface interface {
+	// GetByUserID retrieves the feed of a user, nil when the user has none
+	GetByUserID(ctx context.Context, userID primitive.ObjectID) (*models.CalendarFeed, error)
+	// GetByToken retrieves the feed of a secret token, nil when no feed uses it
+	GetByToken(ctx context.Context, token string) (*models.CalendarFeed, error)
+	// Save sets the secret token of the feed of a user, replacing the previous one
+	Save(ctx context.Context, userID primitive.ObjectID, token string) (*models.CalendarFeed, error)
+	DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// CalendarFeedRepository handles database operations related to calendar feeds
+type CalendarFeedRepository struct {
+	collection *mongo.Collection
+}
+
+// Ensure CalendarFeedRepository implements CalendarFeedRepositoryInterface
+var _ CalendarFeedRepositoryInterface = (*CalendarFeedRepository)(nil)
+
+// NewCalendarFeedRepository creates a new calendar feed repository instance
+func NewCalendarFeedRepository(database *mongo.Database) CalendarFeedRepositoryInterface {
+	if database == nil {
+		database = db.Database
+	}
+	return &CalendarFeedRepository{
+		collection: database.Collection(calendarFeedCollection),
+	}
+}
+
+// EnsureCalendarFeedIndexes creates the indexes allowing a single feed per user and looking feeds up by token
+func EnsureCalendarFeedIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(calendarFeedCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	return err
+}
+
+// GetByUserID retrieves the feed of a user, nil when the user has none
+func (r *CalendarFeedRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID) (*models.CalendarFeed, error) {
+	return r.findOne(ctx, bson.M{"user_id": userID})
+}
+
+// GetByToken retrieves the feed of a secret token, nil when no feed uses it
+func (r *CalendarFeedRepository) GetByToken(ctx context.Context, token string) (*models.CalendarFeed, error) {
+	if token == "" {
+		return nil, nil
+	}
+	return r.findOne(ctx, bson.M{"token_hash": hashCalendarToken(token)})
+}
+
+// Save sets the secret token of the feed of a user, replacing the previous one
+func (r *CalendarFeedRepository) Save(ctx context.Context, userID primitive.ObjectID, token string) (*models.CalendarFeed, error) {
+	if token == "" {
+		return nil, errors.New("token is required")
+	}
+
+	update := bson.M{"$set": bson.M{
+		"token_hash": hashCalendarToken(token),
+		"created_at": primitive.NewDateTimeFromTime(time.Now()),
+	}}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"user_id": userID}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByUserID(ctx, userID)
+}
+
+// DeleteByUserID deletes the feed of a user, revoking its token
+func (r *CalendarFeedRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
+
+func (r *CalendarFeedRepository) findOne(ctx context.Context, filter bson.M) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := r.collection.FindOne(ctx, filter).Decode(&feed)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// hashCalendarToken returns the hash under which a token is stored, so that the feeds cannot be read from a copy of the database
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
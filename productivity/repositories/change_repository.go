@@ -0,0 +1,450 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const changeCollection = "sync_changes"
+const counterCollection = "counters"
+
+// counters of the change feed
+const (
+	// cursorCounter holds the last cursor given to a change
+	cursorCounter = "sync_cursor"
+	// purgedCounter holds the highest cursor of the purged tombstones
+	purgedCounter = "sync_purged_cursor"
+)
+
+// inFlightTimeout is how long a cursor given to a change which was never written, e.g. because the
+// writer crashed, holds the changes after it back
+const inFlightTimeout = time.Minute
+
+// ErrInvalidSnapshot is returned when the position to resume a snapshot from is not one given by LoadSnapshot
+var ErrInvalidSnapshot = errors.New("invalid snapshot position")
+
+// changeSources tells where the items of each type are stored and which field holds their owner
+var changeSources = map[string]struct {
+	collection string
+	userField  string
+}{
+	patchmodels.ItemTypeTask:        {taskCollection, "user"},
+	patchmodels.ItemTypeNote:        {noteCollection, "user"},
+	patchmodels.ItemTypeHabit:       {habitCollection, "user_id"},
+	patchmodels.ItemTypeHabitEntry:  {habitEntryCollection, "user_id"},
+	patchmodels.ItemTypeFolder:      {folderCollection, "user_id"},
+	patchmodels.ItemTypeTag:         {tagCollection, "user_id"},
+	patchmodels.ItemTypeTimeEntry:   {timeEntryCollection, "user"},
+	patchmodels.ItemTypeSavedFilter: {savedFilterCollection, "user_id"},
+}
+
+// snapshotOrder is the order in which the item types are paged in a snapshot
+var snapshotOrder = []string{
+	patchmodels.ItemTypeTask,
+	patchmodels.ItemTypeNote,
+	patchmodels.ItemTypeHabit,
+	patchmodels.ItemTypeHabitEntry,
+	patchmodels.ItemTypeFolder,
+	patchmodels.ItemTypeTag,
+	patchmodels.ItemTypeTimeEntry,
+	patchmodels.ItemTypeSavedFilter,
+}
+
+// ChangeRepositoryInterface defines the interface for the change feed operations
+type ChangeRepositoryInterface interface {
+	// Record marks the items of a user as changed, or deleted, at a new cursor
+	Record(ctx context.Context, userID primitive.ObjectID, itemType string, itemIDs []primitive.ObjectID, deleted bool) error
+	// GetSince retrieves at most limit changes of a user after the cursor, ordered by cursor
+	GetSince(ctx context.Context, userID primitive.ObjectID, cursor int64, limit int64) ([]*models.Change, error)
+	// LoadItems fills the change set with the current state of the given items of a user, keyed by item type
+	LoadItems(ctx context.Context, userID primitive.ObjectID, itemIDs map[string][]primitive.ObjectID, set *models.ChangeSet) error
+	// LoadSnapshot fills the change set with at most limit items of a user after the position, and returns
+	// the position of the next page, empty once all the items are loaded
+	LoadSnapshot(ctx context.Context, userID primitive.ObjectID, after string, limit int64, set *models.ChangeSet) (string, error)
+	// CurrentCursor returns the last cursor up to which all the changes are written
+	CurrentCursor(ctx context.Context) (int64, error)
+	// PurgedCursor returns the highest cursor of the purged tombstones, clients behind it must start over
+	PurgedCursor(ctx context.Context) (int64, error)
+	// PurgeTombstones deletes the tombstones older than the given date and returns how many were deleted
+	PurgeTombstones(ctx context.Context, before time.Time) (int64, error)
+	DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// ChangeRepository handles the change feed used by clients to synchronize their items
+type ChangeRepository struct {
+	collection *mongo.Collection
+	counters   *mongo.Collection
+}
+
+// Ensure ChangeRepository implements ChangeRepositoryInterface
+var _ ChangeRepositoryInterface = (*ChangeRepository)(nil)
+
+// NewChangeRepository creates a new change repository instance
+func NewChangeRepository(db *mongo.Database) *ChangeRepository {
+	return &ChangeRepository{
+		collection: db.Collection(changeCollection),
+		counters:   db.Collection(counterCollection),
+	}
+}
+
+// EnsureChangeIndexes creates the indexes used by the change feed
+func EnsureChangeIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(changeCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "item_type", Value: 1}, {Key: "item_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "cursor", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted", Value: 1}, {Key: "changed_at", Value: 1}}},
+	})
+	return err
+}
+
+// Record marks the items of a user as changed, or deleted, at a new cursor
+func (r *ChangeRepository) Record(ctx context.Context, userID primitive.ObjectID, itemType string, itemIDs []primitive.ObjectID, deleted bool) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+
+	cursor, err := r.nextCursor(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	writes := make([]mongo.WriteModel, 0, len(itemIDs))
+	for _, itemID := range itemIDs {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"user_id": userID, "item_type": itemType, "item_id": itemID}).
+			SetUpdate(bson.M{"$set": bson.M{"cursor": cursor, "deleted": deleted, "changed_at": now}}).
+			SetUpsert(true))
+	}
+
+	_, err = r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+
+	// the changes after the cursor are served once it is written, or has failed to be
+	if releaseErr := r.releaseCursor(context.WithoutCancel(ctx), cursor); err == nil {
+		err = releaseErr
+	}
+	return err
+}
+
+// recordMatching records a change for the items of the collection matching the filter.
+// Deletions must be recorded before the items are removed, as the owner is read from the items.
+func (r *ChangeRepository) recordMatching(ctx context.Context, itemType string, collection *mongo.Collection, filter bson.M, deleted bool) error {
+	if r == nil {
+		return nil
+	}
+
+	userField := changeSources[itemType].userField
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1, userField: 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	itemIDs := map[primitive.ObjectID][]primitive.ObjectID{}
+	for cursor.Next(ctx) {
+		var item bson.M
+		if err := cursor.Decode(&item); err != nil {
+			return err
+		}
+		itemID, okID := item["_id"].(primitive.ObjectID)
+		userID, okUser := item[userField].(primitive.ObjectID)
+		if okID && okUser {
+			itemIDs[userID] = append(itemIDs[userID], itemID)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	for userID, ids := range itemIDs {
+		if err := r.Record(ctx, userID, itemType, ids, deleted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSince retrieves at most limit changes of a user after the cursor, ordered by cursor.
+// The changes after a cursor still being written are held back, so that a client moving past
+// a cursor has seen all the changes before it.
+func (r *ChangeRepository) GetSince(ctx context.Context, userID primitive.ObjectID, cursor int64, limit int64) ([]*models.Change, error) {
+	horizon, err := r.CurrentCursor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if horizon <= cursor {
+		return []*models.Change{}, nil
+	}
+
+	filter := bson.M{"user_id": userID, "cursor": bson.M{"$gt": cursor, "$lte": horizon}}
+	findOpts := options.Find().SetSort(bson.D{{Key: "cursor", Value: 1}})
+	if limit > 0 {
+		findOpts.SetLimit(limit)
+	}
+
+	result, err := r.collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close(ctx)
+
+	changes := []*models.Change{}
+	if err := result.All(ctx, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// LoadItems fills the change set with the current state of the given items of a user, keyed by item type
+func (r *ChangeRepository) LoadItems(ctx context.Context, userID primitive.ObjectID, itemIDs map[string][]primitive.ObjectID, set *models.ChangeSet) error {
+	for itemType, source := range changeSources {
+		ids := itemIDs[itemType]
+		if len(ids) == 0 {
+			continue
+		}
+
+		filter := bson.M{source.userField: userID, "_id": bson.M{"$in": ids}}
+		if err := r.loadItems(ctx, itemType, filter, set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadSnapshot fills the change set with at most limit items of a user after the position, and returns
+// the position of the next page, empty once all the items are loaded. The items are paged by type, in
+// snapshotOrder, then by ID.
+func (r *ChangeRepository) LoadSnapshot(ctx context.Context, userID primitive.ObjectID, after string, limit int64, set *models.ChangeSet) (string, error) {
+	start := 0
+	var afterID *primitive.ObjectID
+	if after != "" {
+		itemType, hexID, _ := strings.Cut(after, ":")
+		id, err := primitive.ObjectIDFromHex(hexID)
+		start = slices.Index(snapshotOrder, itemType)
+		if start < 0 || err != nil {
+			return "", ErrInvalidSnapshot
+		}
+		afterID = &id
+	}
+
+	remaining := limit
+	for i := start; i < len(snapshotOrder); i++ {
+		itemType := snapshotOrder[i]
+		filter := bson.M{changeSources[itemType].userField: userID}
+		if i == start && afterID != nil {
+			filter["_id"] = bson.M{"$gt": *afterID}
+		}
+
+		// fetch one more ID than needed to know if another page follows
+		ids, err := r.snapshotIDs(ctx, itemType, filter, remaining+1)
+		if err != nil {
+			return "", err
+		}
+		more := int64(len(ids)) > remaining
+		if more {
+			ids = ids[:remaining]
+		}
+		if len(ids) > 0 {
+			if err := r.loadItems(ctx, itemType, bson.M{"_id": bson.M{"$in": ids}}, set); err != nil {
+				return "", err
+			}
+		}
+
+		remaining -= int64(len(ids))
+		if more || (remaining == 0 && i+1 < len(snapshotOrder)) {
+			return itemType + ":" + ids[len(ids)-1].Hex(), nil
+		}
+	}
+	return "", nil
+}
+
+// snapshotIDs returns the IDs of at most limit items of the type matching the filter, ordered by ID
+func (r *ChangeRepository) snapshotIDs(ctx context.Context, itemType string, filter bson.M, limit int64) ([]primitive.ObjectID, error) {
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"_id": 1})
+	cursor, err := r.collection.Database().Collection(changeSources[itemType].collection).Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids, nil
+}
+
+// loadItems fills the change set with the items of the type matching the filter
+func (r *ChangeRepository) loadItems(ctx context.Context, itemType string, filter bson.M, set *models.ChangeSet) error {
+	db := r.collection.Database()
+	cursor, err := db.Collection(changeSources[itemType].collection).Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	switch itemType {
+	case patchmodels.ItemTypeTask:
+		err = cursor.All(ctx, &set.Tasks)
+		if err == nil {
+			err = populateTaskTags(ctx, db, set.Tasks)
+		}
+	case patchmodels.ItemTypeNote:
+		err = cursor.All(ctx, &set.Notes)
+		if err == nil {
+			err = populateNoteTasks(ctx, db, set.Notes)
+		}
+	case patchmodels.ItemTypeHabit:
+		err = cursor.All(ctx, &set.Habits)
+	case patchmodels.ItemTypeHabitEntry:
+		err = cursor.All(ctx, &set.HabitEntries)
+	case patchmodels.ItemTypeFolder:
+		err = cursor.All(ctx, &set.Folders)
+	case patchmodels.ItemTypeTag:
+		err = cursor.All(ctx, &set.Tags)
+	case patchmodels.ItemTypeTimeEntry:
+		err = cursor.All(ctx, &set.TimeEntries)
+	case patchmodels.ItemTypeSavedFilter:
+		err = cursor.All(ctx, &set.SavedFilters)
+	}
+	return err
+}
+
+// CurrentCursor returns the last cursor up to which all the changes are written: the last cursor given to
+// a change, or the cursor before the oldest one still being written
+func (r *ChangeRepository) CurrentCursor(ctx context.Context) (int64, error) {
+	var counter struct {
+		Value    int64 `bson:"value"`
+		InFlight []struct {
+			Cursor int64              `bson:"cursor"`
+			At     primitive.DateTime `bson:"at"`
+		} `bson:"in_flight"`
+	}
+	err := r.counters.FindOne(ctx, bson.M{"_id": cursorCounter}).Decode(&counter)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	horizon := counter.Value
+	stale := time.Now().Add(-inFlightTimeout)
+	for _, inFlight := range counter.InFlight {
+		if inFlight.At.Time().After(stale) && inFlight.Cursor <= horizon {
+			horizon = inFlight.Cursor - 1
+		}
+	}
+	return horizon, nil
+}
+
+// PurgedCursor returns the highest cursor of the purged tombstones, clients behind it must start over
+func (r *ChangeRepository) PurgedCursor(ctx context.Context) (int64, error) {
+	return r.counter(ctx, purgedCounter)
+}
+
+// PurgeTombstones deletes the tombstones older than the given date and returns how many were deleted
+func (r *ChangeRepository) PurgeTombstones(ctx context.Context, before time.Time) (int64, error) {
+	filter := bson.M{"deleted": true, "changed_at": bson.M{"$lt": primitive.NewDateTimeFromTime(before)}}
+
+	var newest models.Change
+	err := r.collection.FindOne(ctx, filter, options.FindOne().SetSort(bson.D{{Key: "cursor", Value: -1}})).Decode(&newest)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	// move the watermark first so that no client can miss a deletion
+	_, err = r.counters.UpdateOne(ctx,
+		bson.M{"_id": purgedCounter},
+		bson.M{"$max": bson.M{"value": newest.Cursor}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	filter["cursor"] = bson.M{"$lte": newest.Cursor}
+	result, err := r.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteByUserID deletes the change feed of a specific user
+func (r *ChangeRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	filter := bson.M{"user_id": userID}
+	_, err := r.collection.DeleteMany(ctx, filter)
+	return err
+}
+
+// nextCursor increments the cursor counter and returns its new value. The cursor is marked as in flight
+// in the same update, until released once its change is written.
+func (r *ChangeRepository) nextCursor(ctx context.Context) (int64, error) {
+	var counter struct {
+		Value int64 `bson:"value"`
+	}
+	now := primitive.NewDateTimeFromTime(time.Now())
+	err := r.counters.FindOneAndUpdate(ctx,
+		bson.M{"_id": cursorCounter},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{"value": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$value", int64(0)}}, int64(1)}}}}},
+			{{Key: "$set", Value: bson.M{"in_flight": bson.M{"$concatArrays": bson.A{
+				bson.M{"$ifNull": bson.A{"$in_flight", bson.A{}}},
+				bson.A{bson.M{"cursor": "$value", "at": now}},
+			}}}}},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	return counter.Value, err
+}
+
+// releaseCursor removes a cursor from the cursors in flight, along with the stale ones
+func (r *ChangeRepository) releaseCursor(ctx context.Context, cursor int64) error {
+	stale := primitive.NewDateTimeFromTime(time.Now().Add(-inFlightTimeout))
+	_, err := r.counters.UpdateOne(ctx,
+		bson.M{"_id": cursorCounter},
+		bson.M{"$pull": bson.M{"in_flight": bson.M{"$or": bson.A{
+			bson.M{"cursor": cursor},
+			bson.M{"at": bson.M{"$lt": stale}},
+		}}}},
+	)
+	return err
+}
+
+func (r *ChangeRepository) counter(ctx context.Context, name string) (int64, error) {
+	var counter struct {
+		Value int64 `bson:"value"`
+	}
+	err := r.counters.FindOne(ctx, bson.M{"_id": name}).Decode(&counter)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	return counter.Value, err
+}
@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+	"github.com/atomic-blend/backend/shared/test_utils/inmemorymongo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func setupChangeTest(t *testing.T) (*mongo.Database, func()) {
+	// Start in-memory MongoDB server
+	mongoServer, err := inmemorymongo.CreateInMemoryMongoDB()
+	require.NoError(t, err)
+
+	// Connect to the in-memory MongoDB
+	client, err := inmemorymongo.ConnectToInMemoryDB(mongoServer.URI())
+	require.NoError(t, err)
+
+	db := client.Database("test_db")
+	require.NoError(t, EnsureChangeIndexes(context.Background(), db))
+
+	// Return cleanup function
+	cleanup := func() {
+		client.Disconnect(context.Background())
+		mongoServer.Stop()
+	}
+
+	return db, cleanup
+}
+
+func TestChangeRepository_RecordsWrites(t *testing.T) {
+	db, cleanup := setupChangeTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	changeRepo := NewChangeRepository(db)
+	taskRepo := NewTaskRepository(db)
+	folderRepo := NewFolderRepository(db)
+	userID := primitive.NewObjectID()
+
+	folder, err := folderRepo.Create(ctx, &models.Folder{Name: "Work", UserID: userID})
+	require.NoError(t, err)
+	task, err := taskRepo.Create(ctx, &models.TaskEntity{Title: "Task", User: userID})
+	require.NoError(t, err)
+
+	changes, err := changeRepo.GetSince(ctx, userID, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, patchmodels.ItemTypeFolder, changes[0].ItemType)
+	assert.Equal(t, patchmodels.ItemTypeTask, changes[1].ItemType)
+	cursor := changes[1].Cursor
+
+	// deleting the task leaves a tombstone after the cursor
+	require.NoError(t, taskRepo.Delete(ctx, task.ID))
+	changes, err = changeRepo.GetSince(ctx, userID, cursor, 0)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.True(t, changes[0].Deleted)
+	assert.Equal(t, task.ID, changes[0].ItemID.Hex())
+
+	// the snapshot only holds existing items
+	set := &models.ChangeSet{}
+	next, err := changeRepo.LoadSnapshot(ctx, userID, "", 10, set)
+	require.NoError(t, err)
+	assert.Empty(t, next)
+	assert.Len(t, set.Folders, 1)
+	assert.Equal(t, *folder.ID, *set.Folders[0].ID)
+	assert.Empty(t, set.Tasks)
+
+	// other users do not see the changes
+	changes, err = changeRepo.GetSince(ctx, primitive.NewObjectID(), 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestChangeRepository_LoadSnapshot(t *testing.T) {
+	db, cleanup := setupChangeTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	changeRepo := NewChangeRepository(db)
+	taskRepo := NewTaskRepository(db)
+	folderRepo := NewFolderRepository(db)
+	userID := primitive.NewObjectID()
+
+	for _, title := range []string{"First", "Second", "Third"} {
+		_, err := taskRepo.Create(ctx, &models.TaskEntity{Title: title, User: userID})
+		require.NoError(t, err)
+	}
+	_, err := folderRepo.Create(ctx, &models.Folder{Name: "Work", UserID: userID})
+	require.NoError(t, err)
+	_, err = taskRepo.Create(ctx, &models.TaskEntity{Title: "Other", User: primitive.NewObjectID()})
+	require.NoError(t, err)
+
+	// the pages hold at most limit items, across the item types
+	first := &models.ChangeSet{}
+	next, err := changeRepo.LoadSnapshot(ctx, userID, "", 2, first)
+	require.NoError(t, err)
+	require.NotEmpty(t, next)
+	require.Len(t, first.Tasks, 2)
+	assert.Equal(t, "First", first.Tasks[0].Title)
+	assert.Empty(t, first.Folders)
+
+	second := &models.ChangeSet{}
+	next, err = changeRepo.LoadSnapshot(ctx, userID, next, 2, second)
+	require.NoError(t, err)
+	require.Len(t, second.Tasks, 1)
+	assert.Equal(t, "Third", second.Tasks[0].Title)
+	require.Len(t, second.Folders, 1)
+
+	if next != "" {
+		last := &models.ChangeSet{}
+		next, err = changeRepo.LoadSnapshot(ctx, userID, next, 2, last)
+		require.NoError(t, err)
+		assert.Empty(t, last.Tasks)
+		assert.Empty(t, last.Folders)
+	}
+	assert.Empty(t, next)
+
+	_, err = changeRepo.LoadSnapshot(ctx, userID, "unknown:123", 2, &models.ChangeSet{})
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+}
+
+func TestChangeRepository_PurgeTombstones(t *testing.T) {
+	db, cleanup := setupChangeTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewChangeRepository(db)
+	userID := primitive.NewObjectID()
+
+	require.NoError(t, repo.Record(ctx, userID, patchmodels.ItemTypeNote, []primitive.ObjectID{primitive.NewObjectID()}, true))
+	require.NoError(t, repo.Record(ctx, userID, patchmodels.ItemTypeNote, []primitive.ObjectID{primitive.NewObjectID()}, false))
+
+	purged, err := repo.PurgeTombstones(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	purgedCursor, err := repo.PurgedCursor(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purgedCursor)
+
+	changes, err := repo.GetSince(ctx, userID, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.False(t, changes[0].Deleted)
+
+	current, err := repo.CurrentCursor(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), current)
+}
+
+func TestChangeRepository_HoldsBackChangesInFlight(t *testing.T) {
+	db, cleanup := setupChangeTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewChangeRepository(db)
+	userID := primitive.NewObjectID()
+
+	// a writer got a cursor but did not write its change yet
+	inFlight, err := repo.nextCursor(ctx)
+	require.NoError(t, err)
+	require.NoError(t, repo.Record(ctx, userID, patchmodels.ItemTypeNote, []primitive.ObjectID{primitive.NewObjectID()}, false))
+
+	changes, err := repo.GetSince(ctx, userID, 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	current, err := repo.CurrentCursor(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, inFlight-1, current)
+
+	require.NoError(t, repo.releaseCursor(ctx, inFlight))
+
+	changes, err = repo.GetSince(ctx, userID, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+	current, err = repo.CurrentCursor(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, inFlight+1, current)
+}
@@ -5,11 +5,13 @@ import (
 	"time"
 
 	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
 	"github.com/atomic-blend/backend/shared/utils/db"
 
 	bson "go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const folderCollection = "folders"
@@ -17,15 +19,20 @@ const folderCollection = "folders"
 // FolderRepositoryInterface defines the interface for folder repository operations
 type FolderRepositoryInterface interface {
 	GetAll(ctx context.Context, userID primitive.ObjectID) ([]*models.Folder, error)
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.Folder, error)
 	Create(ctx context.Context, folder *models.Folder) (*models.Folder, error)
 	Update(ctx context.Context, id primitive.ObjectID, folder *models.Folder) (*models.Folder, error)
 	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteCascade(ctx context.Context, id primitive.ObjectID) error
 	DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error
+	Move(ctx context.Context, id primitive.ObjectID, parentID *primitive.ObjectID) (*models.Folder, error)
+	GetTaskCounts(ctx context.Context, userID primitive.ObjectID) ([]*models.FolderTaskCount, error)
 }
 
 // FolderRepository handles database operations related to folders
 type FolderRepository struct {
 	collection *mongo.Collection
+	changes    *ChangeRepository
 }
 
 // NewFolderRepository creates a new folder repository instance
@@ -35,6 +42,7 @@ func NewFolderRepository(database *mongo.Database) FolderRepositoryInterface {
 	}
 	return &FolderRepository{
 		collection: database.Collection(folderCollection),
+		changes:    NewChangeRepository(database),
 	}
 }
 
@@ -89,27 +97,124 @@ func (r *FolderRepository) Create(ctx context.Context, folder *models.Folder) (*
 		return nil, err
 	}
 
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeFolder, r.collection, bson.M{"_id": folder.ID}, false); err != nil {
+		return nil, err
+	}
+
 	return folder, nil
 }
 
-// Delete removes a folder
+// Delete removes a folder, its subfolders and its tasks are moved to its parent
 func (r *FolderRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
-	// First, get all tasks that belong to this folder and remove the folder_id
+	folder, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	var parentID *primitive.ObjectID
+	if folder != nil {
+		parentID = folder.ParentID
+	}
+	now := primitive.NewDateTimeFromTime(time.Now())
+
+	// Move the tasks of the folder to its parent, or out of any folder
 	filter := bson.M{"folder_id": id}
-	update := bson.M{"$set": bson.M{"folder_id": nil}}
+	update := bson.M{"$set": bson.M{"folder_id": parentID, "updated_at": now}}
+	tasks := r.collection.Database().Collection(taskCollection)
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeTask, tasks, filter, false); err != nil {
+		return err
+	}
+	if _, err := tasks.UpdateMany(ctx, filter, update); err != nil {
+		return err
+	}
 
-	// Update tasks collection to remove folder reference
-	_, err := r.collection.Database().Collection("tasks").UpdateMany(ctx, filter, update)
-	if err != nil {
+	// Move the subfolders to the parent
+	childFilter := bson.M{"parent_id": id}
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeFolder, r.collection, childFilter, false); err != nil {
+		return err
+	}
+	if _, err := r.collection.UpdateMany(ctx, childFilter, bson.M{"$set": bson.M{"parent_id": parentID, "updated_at": now}}); err != nil {
 		return err
 	}
 
 	// Then delete the folder
 	deleteFilter := bson.M{"_id": id}
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeFolder, r.collection, deleteFilter, true); err != nil {
+		return err
+	}
 	_, err = r.collection.DeleteOne(ctx, deleteFilter)
 	return err
 }
 
+// DeleteCascade removes a folder with all its subfolders, and the tasks of all of them with their subtasks
+func (r *FolderRepository) DeleteCascade(ctx context.Context, id primitive.ObjectID) error {
+	folderIDs, err := descendantIDs(ctx, r.collection, bson.M{"_id": id}, "parent_id")
+	if err != nil {
+		return err
+	}
+
+	tasks := r.collection.Database().Collection(taskCollection)
+	taskIDs, err := descendantIDs(ctx, tasks, bson.M{"folder_id": bson.M{"$in": folderIDs}}, "parent_id")
+	if err != nil {
+		return err
+	}
+
+	// the tasks are deleted like by the task repository, so that the notes linked to them are updated
+	taskRepo := &TaskRepository{collection: tasks, changes: r.changes}
+	if err := taskRepo.deleteMatching(ctx, bson.M{"_id": bson.M{"$in": taskIDs}}); err != nil {
+		return err
+	}
+
+	folderFilter := bson.M{"_id": bson.M{"$in": folderIDs}}
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeFolder, r.collection, folderFilter, true); err != nil {
+		return err
+	}
+	_, err = r.collection.DeleteMany(ctx, folderFilter)
+	return err
+}
+
+// Move sets the parent of a folder, a nil parent moves it to the root
+func (r *FolderRepository) Move(ctx context.Context, id primitive.ObjectID, parentID *primitive.ObjectID) (*models.Folder, error) {
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{
+		"parent_id":  parentID,
+		"updated_at": primitive.NewDateTimeFromTime(time.Now()),
+	}}
+
+	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+		return nil, err
+	}
+
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeFolder, r.collection, filter, false); err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// GetTaskCounts returns the number of open and completed tasks directly in each folder of a user
+func (r *FolderRepository) GetTaskCounts(ctx context.Context, userID primitive.ObjectID) ([]*models.FolderTaskCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user": userID, "folder_id": bson.M{"$ne": nil}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$folder_id",
+			"open":      bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$completed", true}}, 0, 1}}},
+			"completed": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$completed", true}}, 1, 0}}},
+		}}},
+	}
+
+	cursor, err := r.collection.Database().Collection(taskCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var counts []*models.FolderTaskCount
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
 // Update modifies an existing folder
 func (r *FolderRepository) Update(ctx context.Context, id primitive.ObjectID, folder *models.Folder) (*models.Folder, error) {
 	filter := bson.M{"_id": id}
@@ -120,6 +225,10 @@ func (r *FolderRepository) Update(ctx context.Context, id primitive.ObjectID, fo
 		return nil, err
 	}
 
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeFolder, r.collection, filter, false); err != nil {
+		return nil, err
+	}
+
 	return folder, nil
 }
 
@@ -148,3 +257,41 @@ func (r *FolderRepository) DeleteByUserID(ctx context.Context, userID primitive.
 	_, err = r.collection.DeleteMany(ctx, filter)
 	return err
 }
+
+// descendantIDs returns the IDs of the documents matching the filter and of all their descendants,
+// following the given parent field
+func descendantIDs(ctx context.Context, collection *mongo.Collection, filter bson.M, parentField string) ([]primitive.ObjectID, error) {
+	ids := []primitive.ObjectID{}
+	seen := map[primitive.ObjectID]bool{}
+	for filter != nil {
+		cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return nil, err
+		}
+
+		var documents []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		err = cursor.All(ctx, &documents)
+		cursor.Close(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		parents := []primitive.ObjectID{}
+		for _, document := range documents {
+			if seen[document.ID] {
+				continue
+			}
+			seen[document.ID] = true
+			ids = append(ids, document.ID)
+			parents = append(parents, document.ID)
+		}
+
+		filter = nil
+		if len(parents) > 0 {
+			filter = bson.M{parentField: bson.M{"$in": parents}}
+		}
+	}
+	return ids, nil
+}
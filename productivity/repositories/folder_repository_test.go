@@ -6,10 +6,12 @@ import (
 	"time"
 
 	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
 	"github.com/atomic-blend/backend/shared/test_utils/inmemorymongo"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -222,3 +224,130 @@ func TestFolderRepository_DeleteByUserID(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, finalUser2Folders, 0)
 }
+
+func TestFolderRepository_Hierarchy(t *testing.T) {
+	mongoServer, err := inmemorymongo.CreateInMemoryMongoDB()
+	require.NoError(t, err)
+	defer mongoServer.Stop()
+
+	client, err := inmemorymongo.ConnectToInMemoryDB(mongoServer.URI())
+	require.NoError(t, err)
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	db := client.Database("test_db")
+	repo := NewFolderRepository(db)
+	tasks := db.Collection(taskCollection)
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+
+	createFolder := func(parentID *primitive.ObjectID) primitive.ObjectID {
+		folder := createTestFolder()
+		folder.UserID = userID
+		folder.ParentID = parentID
+		created, err := repo.Create(ctx, folder)
+		require.NoError(t, err)
+		return *created.ID
+	}
+	createTask := func(folderID, parentID *primitive.ObjectID, completed bool) primitive.ObjectID {
+		id := primitive.NewObjectID()
+		_, err := tasks.InsertOne(ctx, bson.M{"_id": id, "user": userID, "folder_id": folderID, "parent_id": parentID, "completed": completed})
+		require.NoError(t, err)
+		return id
+	}
+	taskFolder := func(id primitive.ObjectID) *primitive.ObjectID {
+		var task models.TaskEntity
+		require.NoError(t, tasks.FindOne(ctx, bson.M{"_id": id}).Decode(&task))
+		return task.FolderID
+	}
+
+	t.Run("task counts", func(t *testing.T) {
+		folderID := createFolder(nil)
+		createTask(&folderID, nil, false)
+		createTask(&folderID, nil, false)
+		createTask(&folderID, nil, true)
+		createTask(nil, nil, false)
+
+		counts, err := repo.GetTaskCounts(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, counts, 1)
+		assert.Equal(t, folderID, counts[0].FolderID)
+		assert.Equal(t, int64(2), counts[0].Open)
+		assert.Equal(t, int64(1), counts[0].Completed)
+	})
+
+	t.Run("move", func(t *testing.T) {
+		parentID := createFolder(nil)
+		folderID := createFolder(nil)
+
+		moved, err := repo.Move(ctx, folderID, &parentID)
+		require.NoError(t, err)
+		assert.Equal(t, parentID, *moved.ParentID)
+
+		moved, err = repo.Move(ctx, folderID, nil)
+		require.NoError(t, err)
+		assert.Nil(t, moved.ParentID)
+	})
+
+	t.Run("delete moves the content to the parent", func(t *testing.T) {
+		parentID := createFolder(nil)
+		folderID := createFolder(&parentID)
+		childID := createFolder(&folderID)
+		taskID := createTask(&folderID, nil, false)
+
+		require.NoError(t, repo.Delete(ctx, folderID))
+
+		deleted, err := repo.GetByID(ctx, folderID)
+		require.NoError(t, err)
+		assert.Nil(t, deleted)
+		child, err := repo.GetByID(ctx, childID)
+		require.NoError(t, err)
+		assert.Equal(t, parentID, *child.ParentID)
+		assert.Equal(t, parentID, *taskFolder(taskID))
+	})
+
+	t.Run("delete cascade", func(t *testing.T) {
+		folderID := createFolder(nil)
+		childID := createFolder(&folderID)
+		otherID := createFolder(nil)
+		taskID := createTask(&childID, nil, false)
+		createTask(nil, &taskID, false)
+		keptID := createTask(&otherID, nil, false)
+
+		require.NoError(t, repo.DeleteCascade(ctx, folderID))
+
+		for _, id := range []primitive.ObjectID{folderID, childID} {
+			folder, err := repo.GetByID(ctx, id)
+			require.NoError(t, err)
+			assert.Nil(t, folder)
+		}
+		count, err := tasks.CountDocuments(ctx, bson.M{"$or": bson.A{bson.M{"_id": taskID}, bson.M{"parent_id": taskID}}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+		assert.Equal(t, otherID, *taskFolder(keptID))
+	})
+
+	t.Run("delete cascade records the change of the linked notes", func(t *testing.T) {
+		folderID := createFolder(nil)
+		noteID := primitive.NewObjectID()
+		_, err := db.Collection(noteCollection).InsertOne(ctx, bson.M{"_id": noteID, "user": userID})
+		require.NoError(t, err)
+		_, err = tasks.InsertOne(ctx, bson.M{"_id": primitive.NewObjectID(), "user": userID, "folder_id": folderID, "note_ids": bson.A{noteID}})
+		require.NoError(t, err)
+
+		changes := NewChangeRepository(db)
+		cursor, err := changes.CurrentCursor(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.DeleteCascade(ctx, folderID))
+
+		since, err := changes.GetSince(ctx, userID, cursor, 0)
+		require.NoError(t, err)
+		var noteChanged bool
+		for _, change := range since {
+			if change.ItemType == patchmodels.ItemTypeNote && change.ItemID == noteID {
+				noteChanged = !change.Deleted
+			}
+		}
+		assert.True(t, noteChanged)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
 	"github.com/atomic-blend/backend/shared/utils/db"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -26,6 +27,7 @@ type HabitRepositoryInterface interface {
 
 	// Habit Entry methods
 	AddEntry(ctx context.Context, entry *models.HabitEntry) (*models.HabitEntry, error)
+	GetEntryByID(ctx context.Context, id primitive.ObjectID) (*models.HabitEntry, error)
 	GetEntriesByHabitID(ctx context.Context, habitID primitive.ObjectID) ([]models.HabitEntry, error)
 	UpdateEntry(ctx context.Context, entry *models.HabitEntry) (*models.HabitEntry, error)
 	DeleteEntry(ctx context.Context, id primitive.ObjectID) error
@@ -36,6 +38,7 @@ type HabitRepositoryInterface interface {
 type HabitRepository struct {
 	collection      *mongo.Collection
 	entryCollection *mongo.Collection
+	changes         *ChangeRepository
 }
 
 // Ensure HabitRepository implements HabitRepositoryInterface
@@ -49,6 +52,7 @@ func NewHabitRepository(database *mongo.Database) *HabitRepository {
 	return &HabitRepository{
 		collection:      database.Collection(habitCollection),
 		entryCollection: database.Collection(habitEntryCollection),
+		changes:         NewChangeRepository(database),
 	}
 }
 
@@ -69,6 +73,9 @@ func (r *HabitRepository) Create(ctx context.Context, habit *models.Habit) (*mod
 	if err != nil {
 		return nil, err
 	}
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeHabit, r.collection, bson.M{"_id": habit.ID}, false); err != nil {
+		return nil, err
+	}
 	return habit, nil
 }
 
@@ -118,11 +125,17 @@ func (r *HabitRepository) Update(ctx context.Context, habit *models.Habit) (*mod
 	if err != nil {
 		return nil, err
 	}
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeHabit, r.collection, filter, false); err != nil {
+		return nil, err
+	}
 	return habit, nil
 }
 
 // Delete removes a habit from the database
 func (r *HabitRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeHabit, r.collection, bson.M{"_id": id}, true); err != nil {
+		return err
+	}
 	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
 	return err
 }
@@ -144,9 +157,25 @@ func (r *HabitRepository) AddEntry(ctx context.Context, entry *models.HabitEntry
 	if err != nil {
 		return nil, err
 	}
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeHabitEntry, r.entryCollection, bson.M{"_id": entry.ID}, false); err != nil {
+		return nil, err
+	}
 	return entry, nil
 }
 
+// GetEntryByID retrieves a habit entry by its ID
+func (r *HabitRepository) GetEntryByID(ctx context.Context, id primitive.ObjectID) (*models.HabitEntry, error) {
+	var entry models.HabitEntry
+	err := r.entryCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
 // GetEntriesByHabitID retrieves all entries for a specific habit
 func (r *HabitRepository) GetEntriesByHabitID(ctx context.Context, habitID primitive.ObjectID) ([]models.HabitEntry, error) {
 	filter := bson.M{"habit_id": habitID}
@@ -177,11 +206,17 @@ func (r *HabitRepository) UpdateEntry(ctx context.Context, entry *models.HabitEn
 	if err != nil {
 		return nil, err
 	}
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeHabitEntry, r.entryCollection, filter, false); err != nil {
+		return nil, err
+	}
 	return entry, nil
 }
 
 // DeleteEntry removes a habit entry from the database
 func (r *HabitRepository) DeleteEntry(ctx context.Context, id primitive.ObjectID) error {
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeHabitEntry, r.entryCollection, bson.M{"_id": id}, true); err != nil {
+		return err
+	}
 	_, err := r.entryCollection.DeleteOne(ctx, bson.M{"_id": id})
 	return err
 }
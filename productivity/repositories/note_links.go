@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// populateNoteTasks sets the tasks linked to the notes. Only the tasks of the owner of a note are considered.
+func populateNoteTasks(ctx context.Context, db *mongo.Database, notes []*models.NoteEntity) error {
+	ids := make([]primitive.ObjectID, 0, len(notes))
+	for _, note := range notes {
+		if note.ID != nil {
+			ids = append(ids, *note.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "user": 1, "note_ids": 1})
+	cursor, err := db.Collection(taskCollection).Find(ctx, bson.M{"note_ids": bson.M{"$in": ids}}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []struct {
+		ID      primitive.ObjectID   `bson:"_id"`
+		User    primitive.ObjectID   `bson:"user"`
+		NoteIDs []primitive.ObjectID `bson:"note_ids"`
+	}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return err
+	}
+
+	for _, note := range notes {
+		if note.ID == nil {
+			continue
+		}
+		var taskIDs []primitive.ObjectID
+		for _, task := range tasks {
+			if task.User != note.User {
+				continue
+			}
+			for _, id := range task.NoteIDs {
+				if id == *note.ID {
+					taskIDs = append(taskIDs, task.ID)
+					break
+				}
+			}
+		}
+		note.TaskIDs = taskIDs
+	}
+	return nil
+}
+
+// LinkNote links a note to a task, the note then lists the task in its backlinks
+func (r *TaskRepository) LinkNote(ctx context.Context, taskID primitive.ObjectID, noteID primitive.ObjectID) error {
+	filter := bson.M{"_id": taskID}
+	noteIDs := bson.M{"$setUnion": bson.A{bson.M{"$ifNull": bson.A{"$note_ids", bson.A{}}}, bson.A{noteID}}}
+	if err := setTaskNoteIDs(ctx, r.collection, filter, noteIDs); err != nil {
+		return err
+	}
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeTask, r.collection, filter, false); err != nil {
+		return err
+	}
+	return r.recordNoteChanges(ctx, []primitive.ObjectID{noteID})
+}
+
+// UnlinkNote removes the link between a note and a task
+func (r *TaskRepository) UnlinkNote(ctx context.Context, taskID primitive.ObjectID, noteID primitive.ObjectID) error {
+	filter := bson.M{"_id": taskID, "note_ids": noteID}
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeTask, r.collection, filter, false); err != nil {
+		return err
+	}
+	if err := setTaskNoteIDs(ctx, r.collection, filter, withoutNoteID(noteID)); err != nil {
+		return err
+	}
+	return r.recordNoteChanges(ctx, []primitive.ObjectID{noteID})
+}
+
+// recordNoteChanges marks the notes whose backlinks changed as modified for the sync of the apps
+func (r *TaskRepository) recordNoteChanges(ctx context.Context, noteIDs []primitive.ObjectID) error {
+	if len(noteIDs) == 0 {
+		return nil
+	}
+	notes := r.collection.Database().Collection(noteCollection)
+	return r.changes.recordMatching(ctx, patchmodels.ItemTypeNote, notes, bson.M{"_id": bson.M{"$in": noteIDs}}, false)
+}
+
+// linkedNoteIDs returns the notes linked to the matching tasks
+func (r *TaskRepository) linkedNoteIDs(ctx context.Context, filter bson.M) ([]primitive.ObjectID, error) {
+	values, err := r.collection.Distinct(ctx, "note_ids", filter)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(primitive.ObjectID); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// removeNoteLinks removes a deleted note from the tasks linked to it
+func removeNoteLinks(ctx context.Context, db *mongo.Database, changes *ChangeRepository, noteID primitive.ObjectID) error {
+	tasks := db.Collection(taskCollection)
+	filter := bson.M{"note_ids": noteID}
+	if err := changes.recordMatching(ctx, patchmodels.ItemTypeTask, tasks, filter, false); err != nil {
+		return err
+	}
+	return setTaskNoteIDs(ctx, tasks, filter, withoutNoteID(noteID))
+}
+
+// setTaskNoteIDs sets the linked notes of the matching tasks from an aggregation expression in a single update,
+// so that no concurrent change of a task is overwritten. The tasks created before fields were tracked keep
+// being considered modified at their last update.
+func setTaskNoteIDs(ctx context.Context, tasks *mongo.Collection, filter bson.M, noteIDs interface{}) error {
+	now := primitive.NewDateTimeFromTime(time.Now())
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"note_ids":   noteIDs,
+			"updated_at": now,
+			"field_updated_at": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{bson.M{"$type": "$field_updated_at"}, "object"}},
+				bson.M{"$mergeObjects": bson.A{"$field_updated_at", bson.M{"noteIds": now}}},
+				"$field_updated_at",
+			}},
+		}}},
+	}
+	_, err := tasks.UpdateMany(ctx, filter, update)
+	return err
+}
+
+// withoutNoteID is the expression of the linked notes of a task without the given note
+func withoutNoteID(noteID primitive.ObjectID) bson.M {
+	return bson.M{"$filter": bson.M{
+		"input": bson.M{"$ifNull": bson.A{"$note_ids", bson.A{}}},
+		"cond":  bson.M{"$ne": bson.A{"$$this", noteID}},
+	}}
+}
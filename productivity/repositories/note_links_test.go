@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/test_utils/inmemorymongo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNoteLinks(t *testing.T) {
+	mongoServer, err := inmemorymongo.CreateInMemoryMongoDB()
+	require.NoError(t, err)
+	defer mongoServer.Stop()
+
+	client, err := inmemorymongo.ConnectToInMemoryDB(mongoServer.URI())
+	require.NoError(t, err)
+	defer client.Disconnect(context.Background())
+
+	db := client.Database("test_db")
+	noteRepo := NewNoteRepository(db)
+	taskRepo := NewTaskRepository(db)
+
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+
+	note, err := noteRepo.Create(ctx, &models.NoteEntity{Title: stringPtr("Meeting"), User: userID})
+	require.NoError(t, err)
+	task, err := taskRepo.Create(ctx, &models.TaskEntity{Title: "Follow up", User: userID, NoteIDs: []primitive.ObjectID{primitive.NewObjectID()}})
+	require.NoError(t, err)
+	assert.Nil(t, task.NoteIDs, "links are only made from the notes")
+	taskID, _ := primitive.ObjectIDFromHex(task.ID)
+
+	t.Run("LinkNote adds the backlink to the note", func(t *testing.T) {
+		require.NoError(t, taskRepo.LinkNote(ctx, taskID, *note.ID))
+		require.NoError(t, taskRepo.LinkNote(ctx, taskID, *note.ID))
+
+		linkedTask, err := taskRepo.GetByID(ctx, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{*note.ID}, linkedTask.NoteIDs)
+		assert.Contains(t, linkedTask.FieldUpdatedAt, "noteIds")
+
+		linkedNote, err := noteRepo.GetByID(ctx, note.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{taskID}, linkedNote.TaskIDs)
+	})
+
+	t.Run("Update of the task keeps the links", func(t *testing.T) {
+		updated, err := taskRepo.Update(ctx, task.ID, &models.TaskEntity{Title: "Follow up by mail", User: userID})
+		require.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{*note.ID}, updated.NoteIDs)
+	})
+
+	t.Run("UnlinkNote removes the backlink", func(t *testing.T) {
+		require.NoError(t, taskRepo.UnlinkNote(ctx, taskID, *note.ID))
+
+		unlinkedNote, err := noteRepo.GetByID(ctx, note.ID.Hex())
+		require.NoError(t, err)
+		assert.Empty(t, unlinkedNote.TaskIDs)
+	})
+
+	t.Run("Delete of the note removes the links", func(t *testing.T) {
+		require.NoError(t, taskRepo.LinkNote(ctx, taskID, *note.ID))
+		require.NoError(t, noteRepo.Delete(ctx, note.ID.Hex()))
+
+		unlinkedTask, err := taskRepo.GetByID(ctx, task.ID)
+		require.NoError(t, err)
+		assert.Empty(t, unlinkedTask.NoteIDs)
+	})
+
+	t.Run("Backlinks ignore the tasks of other users", func(t *testing.T) {
+		other, err := noteRepo.Create(ctx, &models.NoteEntity{Title: stringPtr("Other"), User: userID})
+		require.NoError(t, err)
+		foreignTask, err := taskRepo.Create(ctx, &models.TaskEntity{Title: "Foreign", User: primitive.NewObjectID()})
+		require.NoError(t, err)
+		foreignTaskID, _ := primitive.ObjectIDFromHex(foreignTask.ID)
+		require.NoError(t, taskRepo.LinkNote(ctx, foreignTaskID, *other.ID))
+
+		fetched, err := noteRepo.GetByID(ctx, other.ID.Hex())
+		require.NoError(t, err)
+		assert.Empty(t, fetched.TaskIDs)
+	})
+}
@@ -29,17 +29,25 @@ type NoteRepositoryInterface interface {
 	UpdatePatch(ctx context.Context, patch *patchmodels.Patch) (*models.NoteEntity, error)
 	// GetSince retrieves notes where updated_at is after the specified time for a specific user. If page and limit are both provided and >0, returns paginated results and total count. If either is nil or <=0, returns all notes and total count.
 	GetSince(ctx context.Context, userID primitive.ObjectID, since time.Time, page, limit *int64) ([]*models.NoteEntity, int64, error)
+	GetRevisions(ctx context.Context, noteID primitive.ObjectID) ([]*models.NoteRevision, error)
+	GetRevision(ctx context.Context, noteID primitive.ObjectID, rev int) (*models.NoteRevision, error)
 }
 
 // NoteRepository handles database operations related to notes
 type NoteRepository struct {
-	collection *mongo.Collection
+	collection        *mongo.Collection
+	revisions         *mongo.Collection
+	revisionRetention int
+	changes           *ChangeRepository
 }
 
 // NewNoteRepository creates a new note repository instance
 func NewNoteRepository(db *mongo.Database) NoteRepositoryInterface {
 	return &NoteRepository{
-		collection: db.Collection("notes"),
+		collection:        db.Collection("notes"),
+		revisions:         db.Collection(noteRevisionCollection),
+		revisionRetention: NoteRevisionRetention(),
+		changes:           NewChangeRepository(db),
 	}
 }
 
@@ -76,6 +84,9 @@ func (r *NoteRepository) GetAll(ctx context.Context, userID *primitive.ObjectID,
 	if err := cursor.All(ctx, &notes); err != nil {
 		return nil, 0, err
 	}
+	if err := populateNoteTasks(ctx, r.collection.Database(), notes); err != nil {
+		return nil, 0, err
+	}
 
 	return notes, totalCount, nil
 }
@@ -102,6 +113,10 @@ func (r *NoteRepository) GetByID(ctx context.Context, id string) (*models.NoteEn
 		return nil, err
 	}
 
+	if err := populateNoteTasks(ctx, r.collection.Database(), []*models.NoteEntity{&note}); err != nil {
+		return nil, err
+	}
+
 	return &note, nil
 }
 
@@ -114,6 +129,7 @@ func (r *NoteRepository) Create(ctx context.Context, note *models.NoteEntity) (*
 	now := primitive.NewDateTimeFromTime(time.Now())
 	note.CreatedAt = now
 	note.UpdatedAt = now
+	note.FieldUpdatedAt = models.FieldTimestamps{}
 
 	// Generate a new ObjectID if not provided
 	if note.ID == nil {
@@ -126,6 +142,10 @@ func (r *NoteRepository) Create(ctx context.Context, note *models.NoteEntity) (*
 		return nil, err
 	}
 
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeNote, r.collection, bson.M{"_id": note.ID}, false); err != nil {
+		return nil, err
+	}
+
 	return note, nil
 }
 
@@ -143,25 +163,46 @@ func (r *NoteRepository) Update(ctx context.Context, id string, note *models.Not
 		return nil, err
 	}
 
+	existing, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	// Set updated timestamp
 	updatedAt := primitive.NewDateTimeFromTime(time.Now())
 
 	// Prepare update document excluding the ID and created_at
-	update := bson.M{"$set": bson.M{
+	set := bson.M{
 		"title":      note.Title,
 		"content":    note.Content,
 		"user":       note.User,
 		"deleted":    note.Deleted,
 		"updated_at": updatedAt,
-	}}
+	}
+
+	if existing != nil && (noteTextChanged(existing.Title, note.Title) || noteTextChanged(existing.Content, note.Content)) {
+		if err := r.saveRevision(ctx, existing); err != nil {
+			return nil, err
+		}
+	}
+
+	// record which fields this update modified
+	if existing != nil {
+		note.TaskIDs = existing.TaskIDs
+		set["field_updated_at"] = models.TrackChanges(existing, note, existing.FieldUpdatedAt, existing.UpdatedAt, updatedAt)
+	}
 
 	filter := bson.M{"_id": objID}
 
-	_, err = r.collection.UpdateOne(ctx, filter, update)
+	_, err = r.collection.UpdateOne(ctx, filter, bson.M{"$set": set})
 	if err != nil {
 		return nil, err
 	}
 
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeNote, r.collection, filter, false); err != nil {
+		return nil, err
+	}
+
 	// Return the updated note
 	return r.GetByID(ctx, id)
 }
@@ -178,6 +219,10 @@ func (r *NoteRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	filter := bson.M{"_id": objID}
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeNote, r.collection, filter, true); err != nil {
+		return err
+	}
+
 	result, err := r.collection.DeleteOne(ctx, filter)
 	if err != nil {
 		return err
@@ -187,13 +232,28 @@ func (r *NoteRepository) Delete(ctx context.Context, id string) error {
 		return errors.New("note not found")
 	}
 
-	return nil
+	if err := removeNoteLinks(ctx, r.collection.Database(), r.changes, objID); err != nil {
+		return err
+	}
+
+	_, err = r.revisions.DeleteMany(ctx, bson.M{"note_id": objID})
+	return err
 }
 
 // DeleteByUserID deletes all notes for a specific user
 func (r *NoteRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
 	filter := bson.M{"user": userID}
-	_, err := r.collection.DeleteMany(ctx, filter)
+	if _, err := r.collection.DeleteMany(ctx, filter); err != nil {
+		return err
+	}
+	if _, err := r.revisions.DeleteMany(ctx, filter); err != nil {
+		return err
+	}
+
+	// none of the tasks of the user can link to a note anymore
+	_, err := r.collection.Database().Collection(taskCollection).UpdateMany(ctx,
+		bson.M{"user": userID, "note_ids": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"note_ids": ""}})
 	return err
 }
 
@@ -207,8 +267,27 @@ func (r *NoteRepository) UpdatePatch(ctx context.Context, patch *patchmodels.Pat
 		return nil, errors.New("item type not supported")
 	}
 
+	existing, err := r.GetByID(ctx, patch.ItemID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, errors.New("note not found")
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
 	updatePayload := bson.M{}
+	changedKeys := make([]string, 0, len(patch.Changes))
 	for _, change := range patch.Changes {
+		if change.Key == "fieldUpdatedAt" {
+			continue
+		}
+		// the linked tasks are read from the tasks
+		if change.Key == "taskIds" {
+			continue
+		}
+		changedKeys = append(changedKeys, change.Key)
+
 		//convert Key from camelCase to snake_case
 		key := keyconverter.ToSnakeCase(change.Key)
 		value := change.Value
@@ -229,14 +308,32 @@ func (r *NoteRepository) UpdatePatch(ctx context.Context, patch *patchmodels.Pat
 		updatePayload[key] = value
 	}
 
-	updatePayload["updated_at"] = primitive.NewDateTimeFromTime(time.Now())
+	updatePayload["updated_at"] = now
+
+	if patchChangesNoteText(existing, updatePayload) {
+		if err := r.saveRevision(ctx, existing); err != nil {
+			return nil, err
+		}
+	}
+
+	// record the modification of the patched fields, notes created before fields were tracked
+	// start being tracked from their last update
+	fields := existing.FieldUpdatedAt
+	if fields == nil {
+		fields = models.NewFieldTimestamps(existing, existing.UpdatedAt)
+	}
+	updatePayload["field_updated_at"] = fields.Touch(now, changedKeys...)
 
 	// Perform the update operation
-	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": patch.ItemID}, bson.M{"$set": updatePayload})
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": patch.ItemID}, bson.M{"$set": updatePayload})
 	if err != nil {
 		return nil, err
 	}
 
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeNote, r.collection, bson.M{"_id": patch.ItemID}, false); err != nil {
+		return nil, err
+	}
+
 	return r.GetByID(ctx, patch.ItemID.Hex())
 }
 
@@ -394,6 +491,9 @@ func (r *NoteRepository) GetSince(ctx context.Context, userID primitive.ObjectID
 	if err := cursor.All(ctx, &notes); err != nil {
 		return nil, 0, err
 	}
+	if err := populateNoteTasks(ctx, r.collection.Database(), notes); err != nil {
+		return nil, 0, err
+	}
 
 	return notes, totalCount, nil
 }
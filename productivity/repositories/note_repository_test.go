@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
 	"github.com/atomic-blend/backend/shared/test_utils/inmemorymongo"
 
 	"github.com/stretchr/testify/assert"
@@ -377,6 +378,83 @@ func TestNoteRepository_Pagination(t *testing.T) {
 	})
 }
 
+func TestNoteRepository_Revisions(t *testing.T) {
+	t.Setenv("NOTE_REVISION_RETENTION", "2")
+	repo, cleanup := setupNoteTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+
+	note, err := repo.Create(ctx, &models.NoteEntity{
+		Title:   stringPtr("v1"),
+		Content: stringPtr("content v1"),
+		User:    userID,
+	})
+	require.NoError(t, err)
+
+	t.Run("Update saves the previous version", func(t *testing.T) {
+		_, err := repo.Update(ctx, note.ID.Hex(), &models.NoteEntity{Title: stringPtr("v2"), Content: stringPtr("content v2"), User: userID})
+		require.NoError(t, err)
+
+		revisions, err := repo.GetRevisions(ctx, *note.ID)
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+		assert.Equal(t, 1, revisions[0].Rev)
+		assert.Equal(t, "v1", *revisions[0].Title)
+		assert.Equal(t, "content v1", *revisions[0].Content)
+		assert.Equal(t, userID, revisions[0].User)
+	})
+
+	t.Run("Update without text change does not save a version", func(t *testing.T) {
+		deleted := true
+		_, err := repo.Update(ctx, note.ID.Hex(), &models.NoteEntity{Title: stringPtr("v2"), Content: stringPtr("content v2"), User: userID, Deleted: &deleted})
+		require.NoError(t, err)
+
+		revisions, err := repo.GetRevisions(ctx, *note.ID)
+		require.NoError(t, err)
+		assert.Len(t, revisions, 1)
+	})
+
+	t.Run("UpdatePatch saves the previous version", func(t *testing.T) {
+		_, err := repo.UpdatePatch(ctx, &patchmodels.Patch{
+			Action:   "update",
+			ItemType: patchmodels.ItemTypeNote,
+			ItemID:   note.ID,
+			Changes:  []patchmodels.PatchChange{{Key: "content", Value: "content v3"}},
+		})
+		require.NoError(t, err)
+
+		revision, err := repo.GetRevision(ctx, *note.ID, 2)
+		require.NoError(t, err)
+		require.NotNil(t, revision)
+		assert.Equal(t, "content v2", *revision.Content)
+	})
+
+	t.Run("Oldest revisions beyond the retention are pruned", func(t *testing.T) {
+		_, err := repo.Update(ctx, note.ID.Hex(), &models.NoteEntity{Title: stringPtr("v4"), Content: stringPtr("content v4"), User: userID})
+		require.NoError(t, err)
+
+		revisions, err := repo.GetRevisions(ctx, *note.ID)
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, 3, revisions[0].Rev)
+		assert.Equal(t, 2, revisions[1].Rev)
+
+		pruned, err := repo.GetRevision(ctx, *note.ID, 1)
+		require.NoError(t, err)
+		assert.Nil(t, pruned)
+	})
+
+	t.Run("Delete removes the revisions", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, note.ID.Hex()))
+
+		revisions, err := repo.GetRevisions(ctx, *note.ID)
+		require.NoError(t, err)
+		assert.Empty(t, revisions)
+	})
+}
+
 // Helper function to create string pointers
 func stringPtr(s string) *string {
 	return &s
@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	bson "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	noteRevisionCollection = "note_revisions"
+
+	defaultNoteRevisionRetention = 50
+	// number of attempts to number a revision when concurrent updates pick the same number
+	noteRevisionAttempts = 3
+)
+
+// NoteRevisionRetention returns the number of revisions kept per note, read from NOTE_REVISION_RETENTION
+func NoteRevisionRetention() int {
+	count, err := strconv.Atoi(os.Getenv("NOTE_REVISION_RETENTION"))
+	if err != nil || count < 1 {
+		return defaultNoteRevisionRetention
+	}
+	return count
+}
+
+// EnsureNoteRevisionIndexes creates the index numbering the revisions of each note
+func EnsureNoteRevisionIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(noteRevisionCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "note_id", Value: 1}, {Key: "rev", Value: -1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user", Value: 1}},
+		},
+	})
+	return err
+}
+
+// GetRevisions retrieves the saved versions of a note, most recent first
+func (r *NoteRepository) GetRevisions(ctx context.Context, noteID primitive.ObjectID) ([]*models.NoteRevision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "rev", Value: -1}})
+	cursor, err := r.revisions.Find(ctx, bson.M{"note_id": noteID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []*models.NoteRevision{}
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// GetRevision retrieves a saved version of a note by its number, nil if it does not exist
+func (r *NoteRepository) GetRevision(ctx context.Context, noteID primitive.ObjectID, rev int) (*models.NoteRevision, error) {
+	var revision models.NoteRevision
+	err := r.revisions.FindOne(ctx, bson.M{"note_id": noteID, "rev": rev}).Decode(&revision)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// saveRevision stores the current version of a note before it is overwritten, then drops the
+// revisions beyond the retention count
+func (r *NoteRepository) saveRevision(ctx context.Context, note *models.NoteEntity) error {
+	revision := &models.NoteRevision{
+		NoteID:    *note.ID,
+		User:      note.User,
+		Title:     note.Title,
+		Content:   note.Content,
+		UpdatedAt: note.UpdatedAt,
+		CreatedAt: primitive.NewDateTimeFromTime(time.Now()),
+	}
+
+	for attempt := 1; ; attempt++ {
+		last, err := r.lastRevision(ctx, *note.ID)
+		if err != nil {
+			return err
+		}
+		id := primitive.NewObjectID()
+		revision.ID = &id
+		revision.Rev = last + 1
+
+		_, err = r.revisions.InsertOne(ctx, revision)
+		if err == nil {
+			break
+		}
+		if !mongo.IsDuplicateKeyError(err) || attempt == noteRevisionAttempts {
+			return err
+		}
+	}
+
+	return r.pruneRevisions(ctx, *note.ID)
+}
+
+// lastRevision returns the number of the most recent revision of a note, 0 when it has none
+func (r *NoteRepository) lastRevision(ctx context.Context, noteID primitive.ObjectID) (int, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "rev", Value: -1}})
+	var last models.NoteRevision
+	err := r.revisions.FindOne(ctx, bson.M{"note_id": noteID}, opts).Decode(&last)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return last.Rev, nil
+}
+
+// pruneRevisions deletes the oldest revisions of a note beyond the retention count
+func (r *NoteRepository) pruneRevisions(ctx context.Context, noteID primitive.ObjectID) error {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "rev", Value: -1}}).
+		SetSkip(int64(r.revisionRetention))
+	var newestExpired models.NoteRevision
+	err := r.revisions.FindOne(ctx, bson.M{"note_id": noteID}, opts).Decode(&newestExpired)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		return err
+	}
+
+	_, err = r.revisions.DeleteMany(ctx, bson.M{"note_id": noteID, "rev": bson.M{"$lte": newestExpired.Rev}})
+	return err
+}
+
+// patchChangesNoteText reports whether a patch payload modifies the title or the content of a note
+func patchChangesNoteText(existing *models.NoteEntity, payload bson.M) bool {
+	if title, ok := payload["title"]; ok && noteTextChanged(existing.Title, title) {
+		return true
+	}
+	if content, ok := payload["content"]; ok && noteTextChanged(existing.Content, content) {
+		return true
+	}
+	return false
+}
+
+// noteTextChanged reports whether a new value of the title or the content differs from the stored one
+func noteTextChanged(current *string, value interface{}) bool {
+	var next *string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		next = &v
+	case *string:
+		next = v
+	default:
+		return true
+	}
+
+	if current == nil || next == nil {
+		return current != next
+	}
+	return *current != *next
+}
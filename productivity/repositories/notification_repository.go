@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const notificationCollection = "notification_ledger"
+const notificationRunCollection = "notification_runs"
+const notificationSnoozeCollection = "notification_snoozes"
+
+// notificationRetention is how long the entries of the ledger are kept
+const notificationRetention = 30 * 24 * time.Hour
+
+// NotificationRepositoryInterface defines the interface for the notification ledger operations
+type NotificationRepositoryInterface interface {
+	// Claim inserts the notification in the ledger as pending.
+	// It returns false when the notification was already claimed for the same occurrence.
+	Claim(ctx context.Context, notification *models.Notification) (bool, error)
+	// RecordResult stores the outcome of sending a claimed notification
+	RecordResult(ctx context.Context, notification *models.Notification) error
+	// LastRun returns when a notification job last ran, zero if it never did
+	LastRun(ctx context.Context, job string) (time.Time, error)
+	// SetLastRun stores when a notification job last ran
+	SetLastRun(ctx context.Context, job string, at time.Time) error
+	// Snooze schedules a notification to be sent again
+	Snooze(ctx context.Context, snooze *models.NotificationSnooze) error
+	// GetSnoozesBetween retrieves the snoozed notifications to send after from and until to
+	GetSnoozesBetween(ctx context.Context, from, to time.Time) ([]*models.NotificationSnooze, error)
+	DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// NotificationRepository handles the ledger of the notifications sent to the users
+type NotificationRepository struct {
+	collection *mongo.Collection
+	runs       *mongo.Collection
+	snoozes    *mongo.Collection
+}
+
+// Ensure NotificationRepository implements NotificationRepositoryInterface
+var _ NotificationRepositoryInterface = (*NotificationRepository)(nil)
+
+// NewNotificationRepository creates a new notification repository instance
+func NewNotificationRepository(db *mongo.Database) *NotificationRepository {
+	return &NotificationRepository{
+		collection: db.Collection(notificationCollection),
+		runs:       db.Collection(notificationRunCollection),
+		snoozes:    db.Collection(notificationSnoozeCollection),
+	}
+}
+
+// EnsureNotificationIndexes creates the indexes of the notification ledger.
+// The unique index is what prevents a notification from being sent twice.
+func EnsureNotificationIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(notificationCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "entity_type", Value: 1},
+				{Key: "entity_id", Value: 1},
+				{Key: "kind", Value: 1},
+				{Key: "occurrence", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(notificationRetention.Seconds())),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(notificationSnoozeCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "remind_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(notificationRetention.Seconds())),
+	})
+	return err
+}
+
+// Claim inserts the notification in the ledger as pending.
+// It returns false when the notification was already claimed for the same occurrence.
+func (r *NotificationRepository) Claim(ctx context.Context, notification *models.Notification) (bool, error) {
+	if notification.ID.IsZero() {
+		notification.ID = primitive.NewObjectID()
+	}
+	notification.Status = models.NotificationStatusPending
+	notification.CreatedAt = primitive.NewDateTimeFromTime(time.Now())
+
+	_, err := r.collection.InsertOne(ctx, notification)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RecordResult stores the outcome of sending a claimed notification
+func (r *NotificationRepository) RecordResult(ctx context.Context, notification *models.Notification) error {
+	update := bson.M{
+		"status":        notification.Status,
+		"success_count": notification.SuccessCount,
+		"failure_count": notification.FailureCount,
+		"deliveries":    notification.Deliveries,
+		"error":         notification.Error,
+		"sent_at":       notification.SentAt,
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": notification.ID}, bson.M{"$set": update})
+	return err
+}
+
+// LastRun returns when a notification job last ran, zero if it never did
+func (r *NotificationRepository) LastRun(ctx context.Context, job string) (time.Time, error) {
+	var run struct {
+		LastRun primitive.DateTime `bson:"last_run"`
+	}
+	err := r.runs.FindOne(ctx, bson.M{"_id": job}).Decode(&run)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return run.LastRun.Time(), nil
+}
+
+// SetLastRun stores when a notification job last ran
+func (r *NotificationRepository) SetLastRun(ctx context.Context, job string, at time.Time) error {
+	_, err := r.runs.UpdateOne(ctx,
+		bson.M{"_id": job},
+		bson.M{"$max": bson.M{"last_run": primitive.NewDateTimeFromTime(at)}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// Snooze schedules a notification to be sent again
+func (r *NotificationRepository) Snooze(ctx context.Context, snooze *models.NotificationSnooze) error {
+	if snooze.ID.IsZero() {
+		snooze.ID = primitive.NewObjectID()
+	}
+	snooze.CreatedAt = primitive.NewDateTimeFromTime(time.Now())
+
+	_, err := r.snoozes.InsertOne(ctx, snooze)
+	return err
+}
+
+// GetSnoozesBetween retrieves the snoozed notifications to send after from and until to
+func (r *NotificationRepository) GetSnoozesBetween(ctx context.Context, from, to time.Time) ([]*models.NotificationSnooze, error) {
+	filter := bson.M{"remind_at": bson.M{
+		"$gt":  primitive.NewDateTimeFromTime(from),
+		"$lte": primitive.NewDateTimeFromTime(to),
+	}}
+
+	cursor, err := r.snoozes.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	snoozes := []*models.NotificationSnooze{}
+	if err := cursor.All(ctx, &snoozes); err != nil {
+		return nil, err
+	}
+	return snoozes, nil
+}
+
+// DeleteByUserID deletes the notifications and the snoozed notifications of a specific user
+func (r *NotificationRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID}); err != nil {
+		return err
+	}
+	_, err := r.snoozes.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
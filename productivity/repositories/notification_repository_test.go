@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/test_utils/inmemorymongo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func setupNotificationTest(t *testing.T) (*mongo.Database, func()) {
+	// Start in-memory MongoDB server
+	mongoServer, err := inmemorymongo.CreateInMemoryMongoDB()
+	require.NoError(t, err)
+
+	// Connect to the in-memory MongoDB
+	client, err := inmemorymongo.ConnectToInMemoryDB(mongoServer.URI())
+	require.NoError(t, err)
+
+	db := client.Database("test_db")
+	require.NoError(t, EnsureNotificationIndexes(context.Background(), db))
+
+	// Return cleanup function
+	cleanup := func() {
+		client.Disconnect(context.Background())
+		mongoServer.Stop()
+	}
+
+	return db, cleanup
+}
+
+func TestNotificationRepository_Claim(t *testing.T) {
+	db, cleanup := setupNotificationTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewNotificationRepository(db)
+	userID := primitive.NewObjectID()
+	occurrence := primitive.NewDateTimeFromTime(time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC))
+
+	newNotification := func(kind string) *models.Notification {
+		return &models.Notification{
+			UserID:     userID,
+			EntityType: models.NotificationEntityTask,
+			EntityID:   "task-1",
+			Kind:       kind,
+			Occurrence: occurrence,
+		}
+	}
+
+	first := newNotification("due")
+	claimed, err := repo.Claim(ctx, first)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, models.NotificationStatusPending, first.Status)
+
+	// the same occurrence cannot be claimed twice
+	claimed, err = repo.Claim(ctx, newNotification("due"))
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	// another kind of notification of the same occurrence can
+	claimed, err = repo.Claim(ctx, newNotification("reminder"))
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	sentAt := primitive.NewDateTimeFromTime(time.Now())
+	first.Status = models.NotificationStatusSent
+	first.SuccessCount = 1
+	first.Deliveries = []models.NotificationDelivery{{MessageID: "message-1"}}
+	first.SentAt = &sentAt
+	require.NoError(t, repo.RecordResult(ctx, first))
+
+	var stored models.Notification
+	require.NoError(t, db.Collection(notificationCollection).FindOne(ctx, map[string]interface{}{"_id": first.ID}).Decode(&stored))
+	assert.Equal(t, models.NotificationStatusSent, stored.Status)
+	assert.Equal(t, 1, stored.SuccessCount)
+	assert.Equal(t, "message-1", stored.Deliveries[0].MessageID)
+
+	require.NoError(t, repo.DeleteByUserID(ctx, userID))
+	count, err := db.Collection(notificationCollection).CountDocuments(ctx, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestNotificationRepository_LastRun(t *testing.T) {
+	db, cleanup := setupNotificationTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewNotificationRepository(db)
+
+	lastRun, err := repo.LastRun(ctx, "task_due")
+	require.NoError(t, err)
+	assert.True(t, lastRun.IsZero())
+
+	at := time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.SetLastRun(ctx, "task_due", at))
+	// an overlapping run finishing late does not move the last run backward
+	require.NoError(t, repo.SetLastRun(ctx, "task_due", at.Add(-time.Minute)))
+
+	lastRun, err = repo.LastRun(ctx, "task_due")
+	require.NoError(t, err)
+	assert.True(t, at.Equal(lastRun))
+}
+
+func TestNotificationRepository_Snoozes(t *testing.T) {
+	db, cleanup := setupNotificationTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewNotificationRepository(db)
+	userID := primitive.NewObjectID()
+	now := time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC)
+
+	snooze := &models.NotificationSnooze{
+		UserID:     userID,
+		EntityType: models.NotificationEntityHabit,
+		EntityID:   primitive.NewObjectID().Hex(),
+		Kind:       "reminder",
+		RemindAt:   primitive.NewDateTimeFromTime(now.Add(10 * time.Minute)),
+	}
+	require.NoError(t, repo.Snooze(ctx, snooze))
+
+	snoozes, err := repo.GetSnoozesBetween(ctx, now, now.Add(5*time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, snoozes)
+
+	snoozes, err = repo.GetSnoozesBetween(ctx, now.Add(9*time.Minute), now.Add(10*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, snoozes, 1)
+	assert.Equal(t, snooze.EntityID, snoozes[0].EntityID)
+
+	require.NoError(t, repo.DeleteByUserID(ctx, userID))
+	snoozes, err = repo.GetSnoozesBetween(ctx, now, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, snoozes)
+}
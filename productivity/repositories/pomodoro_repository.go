@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/utils/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const pomodoroCollection = "pomodoro_sessions"
+
+// ErrPomodoroSessionActive is returned when starting a session while the user already has an active one
+var ErrPomodoroSessionActive = errors.New("a pomodoro session is already active")
+
+// PomodoroRepositoryInterface defines the interface for pomodoro session repository operations
+type PomodoroRepositoryInterface interface {
+	Create(ctx context.Context, session *models.PomodoroSession) (*models.PomodoroSession, error)
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.PomodoroSession, error)
+	GetActive(ctx context.Context, userID primitive.ObjectID) (*models.PomodoroSession, error)
+	GetDue(ctx context.Context, now time.Time) ([]*models.PomodoroSession, error)
+	Save(ctx context.Context, session *models.PomodoroSession, phaseStartedAt primitive.DateTime) error
+	DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// PomodoroRepository handles the pomodoro sessions in the database
+type PomodoroRepository struct {
+	collection *mongo.Collection
+}
+
+// Ensure PomodoroRepository implements PomodoroRepositoryInterface
+var _ PomodoroRepositoryInterface = (*PomodoroRepository)(nil)
+
+// NewPomodoroRepository creates a new pomodoro session repository
+func NewPomodoroRepository(database *mongo.Database) *PomodoroRepository {
+	if database == nil {
+		database = db.Database
+	}
+	return &PomodoroRepository{
+		collection: database.Collection(pomodoroCollection),
+	}
+}
+
+// EnsurePomodoroIndexes creates the index allowing a single active session per user, and the index
+// used by the cron to find the phases that ended
+func EnsurePomodoroIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(pomodoroCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": models.PomodoroStatusActive}),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "phase_ends_at", Value: 1}},
+		},
+	})
+	return err
+}
+
+// Create inserts a session. It returns ErrPomodoroSessionActive when the user already has an active session.
+func (r *PomodoroRepository) Create(ctx context.Context, session *models.PomodoroSession) (*models.PomodoroSession, error) {
+	active, err := r.GetActive(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, ErrPomodoroSessionActive
+	}
+
+	if session.ID.IsZero() {
+		session.ID = primitive.NewObjectID()
+	}
+
+	// the unique index catches the sessions started concurrently
+	_, err = r.collection.InsertOne(ctx, session)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrPomodoroSessionActive
+	}
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetByID returns a session, nil when it does not exist
+func (r *PomodoroRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.PomodoroSession, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+// GetActive returns the active session of a user, nil when there is none
+func (r *PomodoroRepository) GetActive(ctx context.Context, userID primitive.ObjectID) (*models.PomodoroSession, error) {
+	return r.findOne(ctx, bson.M{"user_id": userID, "status": models.PomodoroStatusActive})
+}
+
+// GetDue returns the active sessions whose current phase ended at now
+func (r *PomodoroRepository) GetDue(ctx context.Context, now time.Time) ([]*models.PomodoroSession, error) {
+	filter := bson.M{
+		"status":        models.PomodoroStatusActive,
+		"phase_ends_at": bson.M{"$lte": primitive.NewDateTimeFromTime(now)},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []*models.PomodoroSession
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Save stores the session if its phase did not change since it was read, phaseStartedAt being the start
+// of the phase when it was read. It returns mongo.ErrNoDocuments otherwise, so that a phase is ended once
+// when the cron and the user change the session at the same time.
+func (r *PomodoroRepository) Save(ctx context.Context, session *models.PomodoroSession, phaseStartedAt primitive.DateTime) error {
+	filter := bson.M{
+		"_id":              session.ID,
+		"status":           models.PomodoroStatusActive,
+		"phase_started_at": phaseStartedAt,
+	}
+
+	result, err := r.collection.ReplaceOne(ctx, filter, session)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeleteByUserID deletes all the sessions of a user
+func (r *PomodoroRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
+
+func (r *PomodoroRepository) findOne(ctx context.Context, filter bson.M) (*models.PomodoroSession, error) {
+	var session models.PomodoroSession
+	err := r.collection.FindOne(ctx, filter).Decode(&session)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/test_utils/inmemorymongo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func setupPomodoroTest(t *testing.T) (*PomodoroRepository, func()) {
+	// Start in-memory MongoDB server
+	mongoServer, err := inmemorymongo.CreateInMemoryMongoDB()
+	require.NoError(t, err)
+
+	// Connect to the in-memory MongoDB
+	client, err := inmemorymongo.ConnectToInMemoryDB(mongoServer.URI())
+	require.NoError(t, err)
+
+	db := client.Database("test_db")
+	require.NoError(t, EnsurePomodoroIndexes(context.Background(), db))
+
+	// Return cleanup function
+	cleanup := func() {
+		client.Disconnect(context.Background())
+		mongoServer.Stop()
+	}
+
+	return NewPomodoroRepository(db), cleanup
+}
+
+func newPomodoroSession(userID primitive.ObjectID, start time.Time) *models.PomodoroSession {
+	return &models.PomodoroSession{
+		UserID:           userID,
+		PomodoroSettings: models.PomodoroSettings{WorkMinutes: 25, ShortBreakMinutes: 5, LongBreakMinutes: 15, CyclesBeforeLongBreak: 4},
+		Status:           models.PomodoroStatusActive,
+		Phase:            models.PomodoroPhaseWork,
+		PhaseStartedAt:   primitive.NewDateTimeFromTime(start),
+		PhaseEndsAt:      primitive.NewDateTimeFromTime(start.Add(25 * time.Minute)),
+		CreatedAt:        primitive.NewDateTimeFromTime(start),
+	}
+}
+
+func TestPomodoroRepository_Create(t *testing.T) {
+	repo, cleanup := setupPomodoroTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	created, err := repo.Create(ctx, newPomodoroSession(userID, start))
+	require.NoError(t, err)
+	assert.False(t, created.ID.IsZero())
+
+	_, err = repo.Create(ctx, newPomodoroSession(userID, start))
+	assert.ErrorIs(t, err, ErrPomodoroSessionActive)
+
+	active, err := repo.GetActive(ctx, userID)
+	require.NoError(t, err)
+	require.NotNil(t, active)
+	assert.Equal(t, created.ID, active.ID)
+
+	// another user can start a session
+	_, err = repo.Create(ctx, newPomodoroSession(primitive.NewObjectID(), start))
+	require.NoError(t, err)
+}
+
+func TestPomodoroRepository_Save(t *testing.T) {
+	repo, cleanup := setupPomodoroTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	session, err := repo.Create(ctx, newPomodoroSession(userID, start))
+	require.NoError(t, err)
+
+	due, err := repo.GetDue(ctx, start.Add(10*time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	due, err = repo.GetDue(ctx, start.Add(25*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+
+	phaseStartedAt := session.PhaseStartedAt
+	session.Phase = models.PomodoroPhaseShortBreak
+	session.Cycle = 1
+	session.PhaseStartedAt = primitive.NewDateTimeFromTime(start.Add(25 * time.Minute))
+	session.PhaseEndsAt = primitive.NewDateTimeFromTime(start.Add(30 * time.Minute))
+	require.NoError(t, repo.Save(ctx, session, phaseStartedAt))
+
+	// saving again from the same phase is a conflict
+	assert.ErrorIs(t, repo.Save(ctx, session, phaseStartedAt), mongo.ErrNoDocuments)
+
+	saved, err := repo.GetByID(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PomodoroPhaseShortBreak, saved.Phase)
+	assert.Equal(t, 1, saved.Cycle)
+
+	// a stopped session lets the user start a new one
+	phaseStartedAt = session.PhaseStartedAt
+	session.Status = models.PomodoroStatusStopped
+	require.NoError(t, repo.Save(ctx, session, phaseStartedAt))
+	_, err = repo.Create(ctx, newPomodoroSession(userID, start.Add(time.Hour)))
+	require.NoError(t, err)
+
+	require.NoError(t, repo.DeleteByUserID(ctx, userID))
+	saved, err = repo.GetByID(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Nil(t, saved)
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+	"github.com/atomic-blend/backend/shared/utils/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const savedFilterCollection = "saved_filters"
+
+// SavedFilterRepositoryInterface defines the interface for saved filter repository operations
+type SavedFilterRepositoryInterface interface {
+	GetAll(ctx context.Context, userID primitive.ObjectID) ([]*models.SavedFilter, error)
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.SavedFilter, error)
+	Create(ctx context.Context, filter *models.SavedFilter) (*models.SavedFilter, error)
+	Update(ctx context.Context, id primitive.ObjectID, filter *models.SavedFilter) (*models.SavedFilter, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// SavedFilterRepository handles database operations related to saved filters
+type SavedFilterRepository struct {
+	collection *mongo.Collection
+	changes    *ChangeRepository
+}
+
+// Ensure SavedFilterRepository implements SavedFilterRepositoryInterface
+var _ SavedFilterRepositoryInterface = (*SavedFilterRepository)(nil)
+
+// NewSavedFilterRepository creates a new saved filter repository instance
+func NewSavedFilterRepository(database *mongo.Database) SavedFilterRepositoryInterface {
+	if database == nil {
+		database = db.Database
+	}
+	return &SavedFilterRepository{
+		collection: database.Collection(savedFilterCollection),
+		changes:    NewChangeRepository(database),
+	}
+}
+
+// GetAll retrieves the saved filters of a user ordered by name
+func (r *SavedFilterRepository) GetAll(ctx context.Context, userID primitive.ObjectID) ([]*models.SavedFilter, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var filters []*models.SavedFilter
+	if err := cursor.All(ctx, &filters); err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+// GetByID retrieves a saved filter, nil when it does not exist
+func (r *SavedFilterRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.SavedFilter, error) {
+	var filter models.SavedFilter
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&filter)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &filter, nil
+}
+
+// Create creates a new saved filter
+func (r *SavedFilterRepository) Create(ctx context.Context, filter *models.SavedFilter) (*models.SavedFilter, error) {
+	now := primitive.NewDateTimeFromTime(time.Now())
+	if filter.ID == nil {
+		id := primitive.NewObjectID()
+		filter.ID = &id
+	}
+	filter.CreatedAt = &now
+	filter.UpdatedAt = &now
+
+	if _, err := r.collection.InsertOne(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeSavedFilter, r.collection, bson.M{"_id": filter.ID}, false); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// Update replaces the criteria of a saved filter, its owner and creation date are kept
+func (r *SavedFilterRepository) Update(ctx context.Context, id primitive.ObjectID, filter *models.SavedFilter) (*models.SavedFilter, error) {
+	now := primitive.NewDateTimeFromTime(time.Now())
+	filter.ID = &id
+	filter.UpdatedAt = &now
+
+	query := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{
+		"name":            filter.Name,
+		"filter":          filter.Filter,
+		"due_within_days": filter.DueWithinDays,
+		"sort":            filter.Sort,
+		"updated_at":      filter.UpdatedAt,
+	}}
+	if _, err := r.collection.UpdateOne(ctx, query, update); err != nil {
+		return nil, err
+	}
+
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeSavedFilter, r.collection, query, false); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Delete deletes a saved filter, the tasks it matches are not affected
+func (r *SavedFilterRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	filter := bson.M{"_id": id}
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeSavedFilter, r.collection, filter, true); err != nil {
+		return err
+	}
+	_, err := r.collection.DeleteOne(ctx, filter)
+	return err
+}
+
+// DeleteByUserID deletes all the saved filters of a user
+func (r *SavedFilterRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
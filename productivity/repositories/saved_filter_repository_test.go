@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/test_utils/inmemorymongo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSavedFilterRepository(t *testing.T) {
+	mongoServer, err := inmemorymongo.CreateInMemoryMongoDB()
+	require.NoError(t, err)
+	defer mongoServer.Stop()
+
+	client, err := inmemorymongo.ConnectToInMemoryDB(mongoServer.URI())
+	require.NoError(t, err)
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	repo := NewSavedFilterRepository(client.Database("test_db"))
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	completed := false
+
+	created, err := repo.Create(ctx, &models.SavedFilter{
+		UserID: userID,
+		Name:   "Open",
+		Filter: models.TaskFilter{Completed: &completed},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, created.ID)
+	_, err = repo.Create(ctx, &models.SavedFilter{UserID: primitive.NewObjectID(), Name: "Other user"})
+	require.NoError(t, err)
+
+	t.Run("get all", func(t *testing.T) {
+		filters, err := repo.GetAll(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, filters, 1)
+		assert.Equal(t, "Open", filters[0].Name)
+		assert.False(t, *filters[0].Filter.Completed)
+	})
+
+	t.Run("update keeps the owner", func(t *testing.T) {
+		days := 7
+		updated, err := repo.Update(ctx, *created.ID, &models.SavedFilter{
+			Name:          "Open this week",
+			DueWithinDays: &days,
+			Sort:          []models.TaskSort{{Key: models.TaskSortEndDate}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, userID, updated.UserID)
+		assert.Equal(t, "Open this week", updated.Name)
+		assert.Equal(t, 7, *updated.DueWithinDays)
+		assert.Nil(t, updated.Filter.Completed)
+		assert.Equal(t, created.CreatedAt, updated.CreatedAt)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, *created.ID))
+		filter, err := repo.GetByID(ctx, *created.ID)
+		require.NoError(t, err)
+		assert.Nil(t, filter)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -27,6 +28,7 @@ type TagRepositoryInterface interface {
 // TagRepository handles database operations related to tags
 type TagRepository struct {
 	collection *mongo.Collection
+	changes    *ChangeRepository
 }
 
 // Ensure TagRepository implements TagRepositoryInterface
@@ -36,6 +38,7 @@ var _ TagRepositoryInterface = (*TagRepository)(nil)
 func NewTagRepository(db *mongo.Database) *TagRepository {
 	return &TagRepository{
 		collection: db.Collection(tagCollection),
+		changes:    NewChangeRepository(db),
 	}
 }
 
@@ -93,6 +96,10 @@ func (r *TagRepository) Create(ctx context.Context, tag *models.Tag) (*models.Ta
 		return nil, err
 	}
 
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeTag, r.collection, bson.M{"_id": tag.ID}, false); err != nil {
+		return nil, err
+	}
+
 	return tag, nil
 }
 
@@ -122,12 +129,19 @@ func (r *TagRepository) Update(ctx context.Context, tag *models.Tag) (*models.Ta
 		return nil, err
 	}
 
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeTag, r.collection, filter, false); err != nil {
+		return nil, err
+	}
+
 	return r.GetByID(ctx, *tag.ID)
 }
 
 // Delete removes a tag from the database
 func (r *TagRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
 	filter := bson.M{"_id": id}
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeTag, r.collection, filter, true); err != nil {
+		return err
+	}
 	_, err := r.collection.DeleteOne(ctx, filter)
 	return err
 }
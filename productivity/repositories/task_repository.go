@@ -21,6 +21,8 @@ const taskCollection = "tasks"
 type TaskRepositoryInterface interface {
 	// GetAll retrieves tasks for a user. If page and limit are both provided and >0, returns paginated results and total count. If either is nil or <=0, returns all tasks and total count.
 	GetAll(ctx context.Context, userID *primitive.ObjectID, page, limit *int64) ([]*models.TaskEntity, int64, error)
+	// Search retrieves tasks for a user matching the filter, sorted by the given criteria (most recent first by default). Pagination works as in GetAll.
+	Search(ctx context.Context, userID *primitive.ObjectID, filter *models.TaskFilter, sorts []models.TaskSort, page, limit *int64) ([]*models.TaskEntity, int64, error)
 	GetByID(ctx context.Context, id string) (*models.TaskEntity, error)
 	Create(ctx context.Context, task *models.TaskEntity) (*models.TaskEntity, error)
 	Update(ctx context.Context, id string, task *models.TaskEntity) (*models.TaskEntity, error)
@@ -29,35 +31,52 @@ type TaskRepositoryInterface interface {
 	UpdatePatch(ctx context.Context, patch *patchmodels.Patch) (*models.TaskEntity, error)
 	// GetSince retrieves tasks where updated_at is after the specified time for a specific user. If page and limit are both provided and >0, returns paginated results and total count. If either is nil or <=0, returns all tasks and total count.
 	GetSince(ctx context.Context, userID primitive.ObjectID, since time.Time, page, limit *int64) ([]*models.TaskEntity, int64, error)
+	// GetChildren retrieves the direct subtasks of a task ordered by position
+	GetChildren(ctx context.Context, parentID string) ([]*models.TaskEntity, error)
+	// ReorderChildren sets the position of the subtasks of a task following the order of childIDs
+	ReorderChildren(ctx context.Context, parentID string, childIDs []string) error
+	// RemoveTag removes a tag from all the tasks of a user
+	RemoveTag(ctx context.Context, userID primitive.ObjectID, tagID primitive.ObjectID) error
+	// LinkNote links a note to a task
+	LinkNote(ctx context.Context, taskID primitive.ObjectID, noteID primitive.ObjectID) error
+	// UnlinkNote removes the link between a note and a task
+	UnlinkNote(ctx context.Context, taskID primitive.ObjectID, noteID primitive.ObjectID) error
 }
 
 // TaskRepository handles database operations related to tasks
 type TaskRepository struct {
 	collection *mongo.Collection
+	changes    *ChangeRepository
 }
 
 // NewTaskRepository creates a new task repository instance
 func NewTaskRepository(db *mongo.Database) TaskRepositoryInterface {
 	return &TaskRepository{
 		collection: db.Collection("tasks"),
+		changes:    NewChangeRepository(db),
 	}
 }
 
 // GetAll retrieves tasks for a user. If page and limit are both provided and >0, returns paginated results and total count. If either is nil or <=0, returns all tasks and total count.
 func (r *TaskRepository) GetAll(ctx context.Context, userID *primitive.ObjectID, page, limit *int64) ([]*models.TaskEntity, int64, error) {
-	filter := bson.M{}
+	return r.Search(ctx, userID, nil, nil, page, limit)
+}
+
+// Search retrieves tasks for a user matching the filter, sorted by the given criteria (most recent first by default). Pagination works as in GetAll.
+func (r *TaskRepository) Search(ctx context.Context, userID *primitive.ObjectID, filter *models.TaskFilter, sorts []models.TaskSort, page, limit *int64) ([]*models.TaskEntity, int64, error) {
+	query := taskFilterQuery(filter, time.Now())
 	if userID != nil {
-		filter["user"] = userID
+		query["user"] = userID
 	}
 
-	totalCount, err := r.collection.CountDocuments(ctx, filter)
+	totalCount, err := r.collection.CountDocuments(ctx, query)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// Build find options: always sort by created_at desc to return most recent first
+	// Build find options: sort by the requested keys, then by created_at desc to return most recent first
 	findOpts := options.Find()
-	findOpts.SetSort(bson.D{{Key: "created_at", Value: -1}})
+	findOpts.SetSort(taskSortOptions(sorts))
 
 	// Only apply pagination if both page and limit are provided and > 0
 	if page != nil && limit != nil && *page > 0 && *limit > 0 {
@@ -66,7 +85,7 @@ func (r *TaskRepository) GetAll(ctx context.Context, userID *primitive.ObjectID,
 		findOpts.SetLimit(*limit)
 	}
 
-	cursor, err := r.collection.Find(ctx, filter, findOpts)
+	cursor, err := r.collection.Find(ctx, query, findOpts)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -76,10 +95,119 @@ func (r *TaskRepository) GetAll(ctx context.Context, userID *primitive.ObjectID,
 	if err := cursor.All(ctx, &tasks); err != nil {
 		return nil, 0, err
 	}
+	if err := populateTaskTags(ctx, r.collection.Database(), tasks); err != nil {
+		return nil, 0, err
+	}
 
 	return tasks, totalCount, nil
 }
 
+// taskFilterQuery compiles a task filter into a MongoDB query
+func taskFilterQuery(filter *models.TaskFilter, now time.Time) bson.M {
+	query := bson.M{}
+	if filter == nil {
+		return query
+	}
+
+	if filter.Completed != nil {
+		if *filter.Completed {
+			query["completed"] = true
+		} else {
+			query["completed"] = bson.M{"$ne": true}
+		}
+	}
+
+	endDate := bson.M{}
+	if filter.DueFrom != nil {
+		endDate["$gte"] = *filter.DueFrom
+	}
+	if filter.DueTo != nil {
+		endDate["$lte"] = *filter.DueTo
+	}
+
+	if filter.Overdue != nil {
+		nowDate := primitive.NewDateTimeFromTime(now)
+		if *filter.Overdue {
+			// overdue tasks are open tasks whose due date has passed
+			query["completed"] = bson.M{"$ne": true}
+			if current, ok := endDate["$lte"].(primitive.DateTime); !ok || nowDate < current {
+				endDate["$lte"] = nowDate
+			}
+		} else {
+			query["$or"] = bson.A{
+				bson.M{"completed": true},
+				bson.M{"end_date": nil},
+				bson.M{"end_date": bson.M{"$gt": nowDate}},
+			}
+		}
+	}
+
+	if len(endDate) > 0 {
+		query["end_date"] = endDate
+	}
+
+	if len(filter.Priorities) > 0 {
+		query["priority"] = bson.M{"$in": filter.Priorities}
+	}
+
+	if len(filter.TagIDs) > 0 {
+		query["tag_ids"] = bson.M{"$in": filter.TagIDs}
+	}
+
+	if filter.FolderID != nil {
+		query["folder_id"] = *filter.FolderID
+	}
+
+	return query
+}
+
+// taskSortOptions compiles sort criteria into MongoDB sort options, unknown keys are ignored
+func taskSortOptions(sorts []models.TaskSort) bson.D {
+	sortFields := map[string]string{
+		models.TaskSortCreatedAt: "created_at",
+		models.TaskSortUpdatedAt: "updated_at",
+		models.TaskSortStartDate: "start_date",
+		models.TaskSortEndDate:   "end_date",
+		models.TaskSortPriority:  "priority",
+		models.TaskSortTitle:     "title",
+	}
+
+	sortOptions := bson.D{}
+	hasCreatedAt := false
+	for _, sort := range sorts {
+		field, ok := sortFields[sort.Key]
+		if !ok {
+			continue
+		}
+		direction := 1
+		if sort.Descending {
+			direction = -1
+		}
+		sortOptions = append(sortOptions, bson.E{Key: field, Value: direction})
+		hasCreatedAt = hasCreatedAt || field == "created_at"
+	}
+
+	// keep a stable order for equal values
+	if !hasCreatedAt {
+		sortOptions = append(sortOptions, bson.E{Key: "created_at", Value: -1})
+	}
+	return sortOptions
+}
+
+// EnsureTaskIndexes creates the indexes backing the task queries
+func EnsureTaskIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(taskCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "user", Value: 1}, {Key: "updated_at", Value: -1}}},
+		{Keys: bson.D{{Key: "user", Value: 1}, {Key: "completed", Value: 1}, {Key: "end_date", Value: 1}}},
+		{Keys: bson.D{{Key: "user", Value: 1}, {Key: "priority", Value: -1}}},
+		{Keys: bson.D{{Key: "user", Value: 1}, {Key: "folder_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user", Value: 1}, {Key: "tag_ids", Value: 1}}},
+		{Keys: bson.D{{Key: "parent_id", Value: 1}, {Key: "position", Value: 1}}},
+	})
+	return err
+}
+
 // GetByID retrieves a task by its ID
 func (r *TaskRepository) GetByID(ctx context.Context, id string) (*models.TaskEntity, error) {
 	var task models.TaskEntity
@@ -97,6 +225,9 @@ func (r *TaskRepository) GetByID(ctx context.Context, id string) (*models.TaskEn
 		return nil, err
 	}
 
+	if err := populateTaskTags(ctx, r.collection.Database(), []*models.TaskEntity{&task}); err != nil {
+		return nil, err
+	}
 	return &task, nil
 }
 
@@ -112,6 +243,19 @@ func (r *TaskRepository) Create(ctx context.Context, task *models.TaskEntity) (*
 
 	task.CreatedAt = now
 	task.UpdatedAt = now
+	task.FieldUpdatedAt = models.FieldTimestamps{}
+
+	// tasks created from tag objects reference the tags by ID
+	if task.TagIDs == nil && task.Tags != nil {
+		for _, tag := range *task.Tags {
+			if tag != nil && tag.ID != nil {
+				task.TagIDs = append(task.TagIDs, *tag.ID)
+			}
+		}
+	}
+
+	// the links to notes are made from the notes
+	task.NoteIDs = nil
 
 	// Convert string ID to ObjectID for storing in MongoDB
 	objID, err := primitive.ObjectIDFromHex(task.ID)
@@ -120,23 +264,34 @@ func (r *TaskRepository) Create(ctx context.Context, task *models.TaskEntity) (*
 	}
 
 	_, err = r.collection.InsertOne(ctx, bson.M{
-		"_id":         objID,
-		"title":       task.Title,
-		"user":        task.User,
-		"description": task.Description,
-		"start_date":  task.StartDate,
-		"end_date":    task.EndDate,
-		"completed":   task.Completed,
-		"reminders":   task.Reminders,
-		"priority":    task.Priority,
-		"created_at":  task.CreatedAt,
-		"updated_at":  task.UpdatedAt,
+		"_id":                    objID,
+		"title":                  task.Title,
+		"user":                   task.User,
+		"description":            task.Description,
+		"start_date":             task.StartDate,
+		"end_date":               task.EndDate,
+		"completed":              task.Completed,
+		"reminders":              task.Reminders,
+		"priority":               task.Priority,
+		"tag_ids":                task.TagIDs,
+		"recurrence":             task.Recurrence,
+		"excluded_dates":         task.ExcludedDates,
+		"parent_id":              task.ParentID,
+		"position":               task.Position,
+		"complete_with_subtasks": task.CompleteWithSubtasks,
+		"created_at":             task.CreatedAt,
+		"updated_at":             task.UpdatedAt,
+		"field_updated_at":       task.FieldUpdatedAt,
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeTask, r.collection, bson.M{"_id": objID}, false); err != nil {
+		return nil, err
+	}
+
 	return task, nil
 }
 
@@ -147,42 +302,107 @@ func (r *TaskRepository) Update(ctx context.Context, id string, task *models.Tas
 		return nil, err
 	}
 
+	existing, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	task.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
 
-	update := bson.M{
-		"$set": bson.M{
-			"title":       task.Title,
-			"description": task.Description,
-			"start_date":  task.StartDate,
-			"end_date":    task.EndDate,
-			"completed":   task.Completed,
-			"reminders":   task.Reminders,
-			"priority":    task.Priority,
-			"tags":        task.Tags,
-			"folder_id":   task.FolderID,
-			"updated_at":  task.UpdatedAt,
-		},
+	// the links to notes are made from the notes
+	task.NoteIDs = nil
+	if existing != nil {
+		task.NoteIDs = existing.NoteIDs
+	}
+
+	set := bson.M{
+		"title":                  task.Title,
+		"description":            task.Description,
+		"start_date":             task.StartDate,
+		"end_date":               task.EndDate,
+		"completed":              task.Completed,
+		"reminders":              task.Reminders,
+		"priority":               task.Priority,
+		"tag_ids":                task.TagIDs,
+		"folder_id":              task.FolderID,
+		"recurrence":             task.Recurrence,
+		"excluded_dates":         task.ExcludedDates,
+		"parent_id":              task.ParentID,
+		"position":               task.Position,
+		"complete_with_subtasks": task.CompleteWithSubtasks,
+		"updated_at":             task.UpdatedAt,
+	}
+
+	// record which fields this update modified
+	if existing != nil {
+		task.FieldUpdatedAt = models.TrackChanges(existing, task, existing.FieldUpdatedAt, existing.UpdatedAt, task.UpdatedAt)
+		set["field_updated_at"] = task.FieldUpdatedAt
 	}
 
 	filter := bson.M{"_id": objID}
-	_, err = r.collection.UpdateOne(ctx, filter, update)
+	_, err = r.collection.UpdateOne(ctx, filter, bson.M{"$set": set})
 	if err != nil {
 		return nil, err
 	}
 
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeTask, r.collection, filter, false); err != nil {
+		return nil, err
+	}
+
 	return r.GetByID(ctx, id)
 }
 
-// Delete deletes a task by ID
+// Delete deletes a task by ID, along with all of its subtasks
 func (r *TaskRepository) Delete(ctx context.Context, id string) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
-	filter := bson.M{"_id": objID}
-	_, err = r.collection.DeleteOne(ctx, filter)
-	return err
+	// Collect the whole subtree of the task
+	ids := []primitive.ObjectID{objID}
+	parents := []primitive.ObjectID{objID}
+	for len(parents) > 0 {
+		cursor, err := r.collection.Find(ctx, bson.M{"parent_id": bson.M{"$in": parents}}, options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return err
+		}
+
+		var children []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		err = cursor.All(ctx, &children)
+		cursor.Close(ctx)
+		if err != nil {
+			return err
+		}
+
+		parents = parents[:0]
+		for _, child := range children {
+			ids = append(ids, child.ID)
+			parents = append(parents, child.ID)
+		}
+	}
+
+	return r.deleteMatching(ctx, bson.M{"_id": bson.M{"$in": ids}})
+}
+
+// deleteMatching deletes the matching tasks, recording their deletion and the change of the notes linked to them
+func (r *TaskRepository) deleteMatching(ctx context.Context, filter bson.M) error {
+	noteIDs, err := r.linkedNoteIDs(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeTask, r.collection, filter, true); err != nil {
+		return err
+	}
+
+	if _, err := r.collection.DeleteMany(ctx, filter); err != nil {
+		return err
+	}
+
+	// the linked notes lose the deleted tasks from their backlinks
+	return r.recordNoteChanges(ctx, noteIDs)
 }
 
 // DeleteByUserID deletes all tasks for a specific user
@@ -202,8 +422,27 @@ func (r *TaskRepository) UpdatePatch(ctx context.Context, patch *patchmodels.Pat
 		return nil, errors.New("item type not supported")
 	}
 
+	existing, err := r.GetByID(ctx, patch.ItemID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, errors.New("task not found")
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
 	updatePayload := bson.M{}
+	changedKeys := make([]string, 0, len(patch.Changes))
 	for _, change := range patch.Changes {
+		if change.Key == "fieldUpdatedAt" {
+			continue
+		}
+		// the links to notes are made from the notes
+		if change.Key == "noteIds" {
+			continue
+		}
+		changedKeys = append(changedKeys, change.Key)
+
 		//convert Key from camelCase to snake_case
 		key := keyconverter.ToSnakeCase(change.Key)
 		print("Key: ", change.Key, "\n")
@@ -215,25 +454,58 @@ func (r *TaskRepository) UpdatePatch(ctx context.Context, patch *patchmodels.Pat
 			} else {
 				return nil, errors.New("invalid date format for field: " + key)
 			}
+		} else if isDateTimeArrayField(key) {
+			if dateValues, err := convertToDateTimeArray(change.Value); err == nil {
+				value = dateValues
+			} else {
+				return nil, errors.New("invalid date format for field: " + key)
+			}
 		} else if isBooleanField(key) {
 			if boolValue, err := convertToBoolean(change.Value, isBooleanPointerField(key)); err == nil {
 				value = boolValue
 			} else {
 				return nil, errors.New("invalid boolean format for field: " + key)
 			}
+		} else if isTagField(key) {
+			// tags are stored as references, whether given as tag objects or as IDs
+			tagIDs, err := convertToTagIDs(change.Value)
+			if err != nil {
+				return nil, errors.New("invalid tags format")
+			}
+			key = "tag_ids"
+			value = tagIDs
+			changedKeys = append(changedKeys, "tags", "tagIds")
+		} else if isObjectIDField(key) {
+			if idValue, err := convertToObjectID(change.Value); err == nil {
+				value = idValue
+			} else {
+				return nil, errors.New("invalid id format for field: " + key)
+			}
 		}
 
 		updatePayload[key] = value
 	}
 
-	updatePayload["updated_at"] = primitive.NewDateTimeFromTime(time.Now())
+	updatePayload["updated_at"] = now
+
+	// record the modification of the patched fields, tasks created before fields were tracked
+	// start being tracked from their last update
+	fields := existing.FieldUpdatedAt
+	if fields == nil {
+		fields = models.NewFieldTimestamps(existing, existing.UpdatedAt)
+	}
+	updatePayload["field_updated_at"] = fields.Touch(now, changedKeys...)
 
 	// Perform the update operation
-	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": patch.ItemID}, bson.M{"$set": updatePayload})
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": patch.ItemID}, bson.M{"$set": updatePayload})
 	if err != nil {
 		return nil, err
 	}
 
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeTask, r.collection, bson.M{"_id": patch.ItemID}, false); err != nil {
+		return nil, err
+	}
+
 	return r.GetByID(ctx, patch.ItemID.Hex())
 }
 
@@ -259,9 +531,20 @@ func isDateTimePointerField(fieldName string) bool {
 	return false
 }
 
+// Helper function to check if a field is an array of date/time
+func isDateTimeArrayField(fieldName string) bool {
+	dateTimeArrayFields := []string{"excluded_dates"}
+	for _, field := range dateTimeArrayFields {
+		if fieldName == field {
+			return true
+		}
+	}
+	return false
+}
+
 // Helper function to check if a field is a boolean field
 func isBooleanField(fieldName string) bool {
-	booleanFields := []string{"completed"}
+	booleanFields := []string{"completed", "complete_with_subtasks"}
 	for _, field := range booleanFields {
 		if fieldName == field {
 			return true
@@ -272,7 +555,7 @@ func isBooleanField(fieldName string) bool {
 
 // Helper function to check if a field should be a pointer to bool
 func isBooleanPointerField(fieldName string) bool {
-	pointerFields := []string{"completed"}
+	pointerFields := []string{"completed", "complete_with_subtasks"}
 	for _, field := range pointerFields {
 		if fieldName == field {
 			return true
@@ -281,6 +564,35 @@ func isBooleanPointerField(fieldName string) bool {
 	return false
 }
 
+// Helper function to check if a field is a reference to another document
+func isObjectIDField(fieldName string) bool {
+	objectIDFields := []string{"parent_id"}
+	for _, field := range objectIDFields {
+		if fieldName == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper function to convert an hex string to *primitive.ObjectID
+func convertToObjectID(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	hex, ok := value.(string)
+	if !ok {
+		return nil, errors.New("unsupported id format")
+	}
+
+	objID, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return nil, err
+	}
+	return &objID, nil
+}
+
 // Helper function to convert various date formats to primitive.DateTime
 func convertToDateTime(value interface{}, isPointer bool) (interface{}, error) {
 	if value == nil {
@@ -327,6 +639,30 @@ func convertToDateTime(value interface{}, isPointer bool) (interface{}, error) {
 	return dt, nil
 }
 
+// Helper function to convert an array of dates to []*primitive.DateTime
+func convertToDateTimeArray(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("unsupported date array format")
+	}
+
+	dates := make([]*primitive.DateTime, 0, len(values))
+	for _, v := range values {
+		date, err := convertToDateTime(v, true)
+		if err != nil {
+			return nil, err
+		}
+		if date != nil {
+			dates = append(dates, date.(*primitive.DateTime))
+		}
+	}
+	return dates, nil
+}
+
 // Helper function to convert various boolean formats to bool
 func convertToBoolean(value interface{}, isPointer bool) (interface{}, error) {
 	if value == nil {
@@ -397,6 +733,69 @@ func (r *TaskRepository) GetSince(ctx context.Context, userID primitive.ObjectID
 	if err := cursor.All(ctx, &tasks); err != nil {
 		return nil, 0, err
 	}
+	if err := populateTaskTags(ctx, r.collection.Database(), tasks); err != nil {
+		return nil, 0, err
+	}
 
 	return tasks, totalCount, nil
 }
+
+// GetChildren retrieves the direct subtasks of a task ordered by position
+func (r *TaskRepository) GetChildren(ctx context.Context, parentID string) ([]*models.TaskEntity, error) {
+	objID, err := primitive.ObjectIDFromHex(parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	findOpts := options.Find()
+	findOpts.SetSort(bson.D{{Key: "position", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"parent_id": objID}, findOpts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []*models.TaskEntity
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	if err := populateTaskTags(ctx, r.collection.Database(), tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// ReorderChildren sets the position of the subtasks of a task following the order of childIDs
+func (r *TaskRepository) ReorderChildren(ctx context.Context, parentID string, childIDs []string) error {
+	parentObjID, err := primitive.ObjectIDFromHex(parentID)
+	if err != nil {
+		return err
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	writes := make([]mongo.WriteModel, 0, len(childIDs))
+	childObjIDs := make([]primitive.ObjectID, 0, len(childIDs))
+	for position, childID := range childIDs {
+		childObjID, err := primitive.ObjectIDFromHex(childID)
+		if err != nil {
+			return err
+		}
+		childObjIDs = append(childObjIDs, childObjID)
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": childObjID, "parent_id": parentObjID}).
+			SetUpdate(bson.M{"$set": bson.M{"position": position, "updated_at": now}}))
+	}
+
+	if len(writes) == 0 {
+		return nil
+	}
+
+	_, err = r.collection.BulkWrite(ctx, writes)
+	if err != nil {
+		return err
+	}
+
+	return r.changes.recordMatching(ctx, patchmodels.ItemTypeTask, r.collection, bson.M{"_id": bson.M{"$in": childObjIDs}, "parent_id": parentObjID}, false)
+}
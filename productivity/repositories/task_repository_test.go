@@ -11,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -146,6 +147,75 @@ func TestTaskRepository_Delete(t *testing.T) {
 		require.NoError(t, err)
 		assert.Nil(t, found)
 	})
+
+	t.Run("delete cascades to subtasks", func(t *testing.T) {
+		parent, err := repo.Create(context.Background(), createTestTask())
+		require.NoError(t, err)
+		parentID, _ := primitive.ObjectIDFromHex(parent.ID)
+
+		child := createTestTask()
+		child.ParentID = &parentID
+		child, err = repo.Create(context.Background(), child)
+		require.NoError(t, err)
+		childID, _ := primitive.ObjectIDFromHex(child.ID)
+
+		grandChild := createTestTask()
+		grandChild.ParentID = &childID
+		grandChild, err = repo.Create(context.Background(), grandChild)
+		require.NoError(t, err)
+
+		err = repo.Delete(context.Background(), parent.ID)
+		require.NoError(t, err)
+
+		for _, id := range []string{parent.ID, child.ID, grandChild.ID} {
+			found, err := repo.GetByID(context.Background(), id)
+			require.NoError(t, err)
+			assert.Nil(t, found)
+		}
+	})
+}
+
+func TestTaskRepository_Children(t *testing.T) {
+	repo, cleanup := setupTaskTest(t)
+	defer cleanup()
+
+	parent, err := repo.Create(context.Background(), createTestTask())
+	require.NoError(t, err)
+	parentID, _ := primitive.ObjectIDFromHex(parent.ID)
+
+	var childIDs []string
+	for i := 0; i < 3; i++ {
+		position := i
+		child := createTestTask()
+		child.Title = fmt.Sprintf("Subtask %d", i)
+		child.ParentID = &parentID
+		child.Position = &position
+		created, err := repo.Create(context.Background(), child)
+		require.NoError(t, err)
+		childIDs = append(childIDs, created.ID)
+	}
+
+	t.Run("get children ordered by position", func(t *testing.T) {
+		children, err := repo.GetChildren(context.Background(), parent.ID)
+		require.NoError(t, err)
+		require.Len(t, children, 3)
+		for i, child := range children {
+			assert.Equal(t, childIDs[i], child.ID)
+		}
+	})
+
+	t.Run("reorder children", func(t *testing.T) {
+		reversed := []string{childIDs[2], childIDs[1], childIDs[0]}
+		err := repo.ReorderChildren(context.Background(), parent.ID, reversed)
+		require.NoError(t, err)
+
+		children, err := repo.GetChildren(context.Background(), parent.ID)
+		require.NoError(t, err)
+		require.Len(t, children, 3)
+		for i, child := range children {
+			assert.Equal(t, reversed[i], child.ID)
+		}
+	})
 }
 
 func TestTaskRepository_GetAll(t *testing.T) {
@@ -449,3 +519,51 @@ func TestTaskRepository_DeleteByUserID(t *testing.T) {
 	assert.Len(t, finalTasks, 0)
 	assert.Equal(t, int64(0), totalCount)
 }
+
+func TestTaskFilterQuery(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	nowDate := primitive.NewDateTimeFromTime(now)
+
+	t.Run("nil filter", func(t *testing.T) {
+		assert.Equal(t, bson.M{}, taskFilterQuery(nil, now))
+	})
+
+	t.Run("all criteria", func(t *testing.T) {
+		completed := false
+		folderID := primitive.NewObjectID()
+		tagID := primitive.NewObjectID()
+		dueFrom := primitive.NewDateTimeFromTime(now.Add(-24 * time.Hour))
+		filter := &models.TaskFilter{
+			Completed:  &completed,
+			DueFrom:    &dueFrom,
+			Priorities: []int{1, 2},
+			TagIDs:     []primitive.ObjectID{tagID},
+			FolderID:   &folderID,
+		}
+
+		assert.Equal(t, bson.M{
+			"completed": bson.M{"$ne": true},
+			"end_date":  bson.M{"$gte": dueFrom},
+			"priority":  bson.M{"$in": []int{1, 2}},
+			"tag_ids":   bson.M{"$in": []primitive.ObjectID{tagID}},
+			"folder_id": folderID,
+		}, taskFilterQuery(filter, now))
+	})
+
+	t.Run("overdue", func(t *testing.T) {
+		overdue := true
+		assert.Equal(t, bson.M{
+			"completed": bson.M{"$ne": true},
+			"end_date":  bson.M{"$lte": nowDate},
+		}, taskFilterQuery(&models.TaskFilter{Overdue: &overdue}, now))
+	})
+}
+
+func TestTaskSortOptions(t *testing.T) {
+	assert.Equal(t, bson.D{{Key: "created_at", Value: -1}}, taskSortOptions(nil))
+	assert.Equal(t, bson.D{
+		{Key: "priority", Value: -1},
+		{Key: "end_date", Value: 1},
+		{Key: "created_at", Value: -1},
+	}, taskSortOptions([]models.TaskSort{{Key: models.TaskSortPriority, Descending: true}, {Key: models.TaskSortEndDate}, {Key: "unknown"}}))
+}
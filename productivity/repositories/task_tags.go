@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// populateTaskTags sets the tags of the tasks from their tag IDs. The tags which no longer exist, or which
+// belong to another user, are left out.
+func populateTaskTags(ctx context.Context, db *mongo.Database, tasks []*models.TaskEntity) error {
+	ids := []primitive.ObjectID{}
+	seen := map[primitive.ObjectID]bool{}
+	for _, task := range tasks {
+		for _, id := range task.TagIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	cursor, err := db.Collection(tagCollection).Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var tags []*models.Tag
+	if err := cursor.All(ctx, &tags); err != nil {
+		return err
+	}
+	tagsByID := make(map[primitive.ObjectID]*models.Tag, len(tags))
+	for _, tag := range tags {
+		tagsByID[*tag.ID] = tag
+	}
+
+	for _, task := range tasks {
+		if len(task.TagIDs) == 0 {
+			continue
+		}
+		taskTags := make([]*models.Tag, 0, len(task.TagIDs))
+		for _, id := range task.TagIDs {
+			if tag, ok := tagsByID[id]; ok && tag.UserID != nil && *tag.UserID == task.User {
+				taskTags = append(taskTags, tag)
+			}
+		}
+		task.Tags = &taskTags
+	}
+	return nil
+}
+
+// legacyTaskTagsIndex is the index of the tags embedded in the tasks, replaced by the index of tag_ids
+const legacyTaskTagsIndex = "user_1_tags._id_1"
+
+// MigrateTaskTags replaces the copies of the tags embedded in the tasks by references to the tags,
+// and drops the index of the embedded tags. It returns the number of migrated tasks, running it again
+// once done does nothing.
+func MigrateTaskTags(ctx context.Context, db *mongo.Database) (int64, error) {
+	filter := bson.M{"tags": bson.M{"$exists": true}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"tag_ids": bson.M{"$setUnion": bson.A{
+				bson.M{"$ifNull": bson.A{"$tag_ids", bson.A{}}},
+				bson.M{"$map": bson.M{
+					"input": bson.M{"$filter": bson.M{
+						"input": bson.M{"$ifNull": bson.A{"$tags", bson.A{}}},
+						"cond":  bson.M{"$eq": bson.A{bson.M{"$type": "$$this._id"}, "objectId"}},
+					}},
+					"in": "$$this._id",
+				}},
+			}},
+		}}},
+		{{Key: "$unset", Value: "tags"}},
+	}
+
+	result, err := db.Collection(taskCollection).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	// the index is gone once dropped, or was never created on a new database
+	var commandErr mongo.CommandError
+	if _, err := db.Collection(taskCollection).Indexes().DropOne(ctx, legacyTaskTagsIndex); err != nil &&
+		!(errors.As(err, &commandErr) && (commandErr.Name == "IndexNotFound" || commandErr.Name == "NamespaceNotFound")) {
+		return result.ModifiedCount, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// RemoveTag removes a tag from all the tasks of a user in a single update, so that no concurrent change
+// of a task is overwritten
+func (r *TaskRepository) RemoveTag(ctx context.Context, userID primitive.ObjectID, tagID primitive.ObjectID) error {
+	// the tasks are collected first as the filter no longer matches them once updated,
+	// their change being recorded after the update so that clients load them without the tag
+	cursor, err := r.collection.Find(ctx, bson.M{"user": userID, "tag_ids": tagID}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+	var tasks []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	taskIDs := make([]primitive.ObjectID, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	// the tasks created before fields were tracked keep being considered modified at their last update
+	now := primitive.NewDateTimeFromTime(time.Now())
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"tag_ids": bson.M{"$filter": bson.M{
+				"input": "$tag_ids",
+				"cond":  bson.M{"$ne": bson.A{"$$this", tagID}},
+			}},
+			"updated_at": now,
+			"field_updated_at": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{bson.M{"$type": "$field_updated_at"}, "object"}},
+				bson.M{"$mergeObjects": bson.A{"$field_updated_at", bson.M{"tags": now, "tagIds": now}}},
+				"$field_updated_at",
+			}},
+		}}},
+	}
+	filter := bson.M{"_id": bson.M{"$in": taskIDs}}
+	if _, err := r.collection.UpdateMany(ctx, filter, update); err != nil {
+		return err
+	}
+	return r.changes.recordMatching(ctx, patchmodels.ItemTypeTask, r.collection, filter, false)
+}
+
+// Helper function to check if a field holds the tags of a task
+func isTagField(fieldName string) bool {
+	return fieldName == "tags" || fieldName == "tag_ids"
+}
+
+// Helper function to convert a list of tags, as tag objects or IDs, to []primitive.ObjectID
+func convertToTagIDs(value interface{}) ([]primitive.ObjectID, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("unsupported tags format")
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(values))
+	for _, v := range values {
+		if tag, ok := v.(map[string]interface{}); ok {
+			v = tag["id"]
+		}
+		hex, ok := v.(string)
+		if !ok {
+			return nil, errors.New("unsupported tag format")
+		}
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
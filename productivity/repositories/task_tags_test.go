@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/shared/test_utils/inmemorymongo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestTaskRepository_Tags(t *testing.T) {
+	mongoServer, err := inmemorymongo.CreateInMemoryMongoDB()
+	require.NoError(t, err)
+	defer mongoServer.Stop()
+
+	client, err := inmemorymongo.ConnectToInMemoryDB(mongoServer.URI())
+	require.NoError(t, err)
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	db := client.Database("test_db")
+	repo := NewTaskRepository(db)
+	tagRepo := NewTagRepository(db)
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+
+	createTag := func(owner primitive.ObjectID, name string) *models.Tag {
+		tag, err := tagRepo.Create(ctx, &models.Tag{UserID: &owner, Name: name})
+		require.NoError(t, err)
+		return tag
+	}
+	work := createTag(userID, "Work")
+	home := createTag(userID, "Home")
+	foreign := createTag(primitive.NewObjectID(), "Foreign")
+
+	t.Run("tags are populated on read", func(t *testing.T) {
+		task := createTestTask()
+		task.User = userID
+		task.TagIDs = []primitive.ObjectID{*work.ID, *foreign.ID, *home.ID}
+		created, err := repo.Create(ctx, task)
+		require.NoError(t, err)
+
+		work.Name = "Office"
+		_, err = tagRepo.Update(ctx, work)
+		require.NoError(t, err)
+
+		found, err := repo.GetByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found.Tags)
+		require.Len(t, *found.Tags, 2)
+		assert.Equal(t, "Office", (*found.Tags)[0].Name)
+		assert.Equal(t, "Home", (*found.Tags)[1].Name)
+	})
+
+	t.Run("remove tag", func(t *testing.T) {
+		tagged := createTestTask()
+		tagged.User = userID
+		tagged.TagIDs = []primitive.ObjectID{*home.ID, *work.ID}
+		tagged, err := repo.Create(ctx, tagged)
+		require.NoError(t, err)
+
+		otherUser := createTestTask()
+		otherUser.User = primitive.NewObjectID()
+		otherUser.TagIDs = []primitive.ObjectID{*home.ID}
+		otherUser, err = repo.Create(ctx, otherUser)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.RemoveTag(ctx, userID, *home.ID))
+
+		found, err := repo.GetByID(ctx, tagged.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{*work.ID}, found.TagIDs)
+		assert.Contains(t, found.FieldUpdatedAt, "tags")
+
+		found, err = repo.GetByID(ctx, otherUser.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{*home.ID}, found.TagIDs)
+	})
+
+	t.Run("migrate embedded tags", func(t *testing.T) {
+		taskID := primitive.NewObjectID()
+		_, err := db.Collection(taskCollection).InsertOne(ctx, bson.M{
+			"_id":   taskID,
+			"title": "Legacy task",
+			"user":  userID,
+			"tags": bson.A{
+				bson.M{"_id": *work.ID, "name": "Work"},
+				bson.M{"name": "Without ID"},
+				bson.M{"_id": *home.ID, "name": "Home"},
+			},
+		})
+		require.NoError(t, err)
+		_, err = db.Collection(taskCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "user", Value: 1}, {Key: "tags._id", Value: 1}},
+		})
+		require.NoError(t, err)
+
+		migrated, err := MigrateTaskTags(ctx, db)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), migrated)
+
+		var raw bson.M
+		require.NoError(t, db.Collection(taskCollection).FindOne(ctx, bson.M{"_id": taskID}).Decode(&raw))
+		assert.NotContains(t, raw, "tags")
+
+		found, err := repo.GetByID(ctx, taskID.Hex())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []primitive.ObjectID{*work.ID, *home.ID}, found.TagIDs)
+
+		indexes, err := db.Collection(taskCollection).Indexes().ListSpecifications(ctx)
+		require.NoError(t, err)
+		for _, index := range indexes {
+			assert.NotEqual(t, legacyTaskTagsIndex, index.Name)
+		}
+
+		migrated, err = MigrateTaskTags(ctx, db)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), migrated)
+	})
+}
+
+func TestConvertToTagIDs(t *testing.T) {
+	first := primitive.NewObjectID()
+	second := primitive.NewObjectID()
+
+	ids, err := convertToTagIDs([]interface{}{
+		map[string]interface{}{"id": first.Hex(), "name": "Work"},
+		second.Hex(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []primitive.ObjectID{first, second}, ids)
+
+	ids, err = convertToTagIDs(nil)
+	require.NoError(t, err)
+	assert.Nil(t, ids)
+
+	_, err = convertToTagIDs([]interface{}{"not an id"})
+	assert.Error(t, err)
+	_, err = convertToTagIDs("not a list")
+	assert.Error(t, err)
+}
@@ -2,18 +2,27 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
 	"github.com/atomic-blend/backend/shared/utils/db"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const timeEntryCollection = "time_entries"
 
+// ErrTimerAlreadyRunning is returned when starting a timer while the user already has one running or paused
+var ErrTimerAlreadyRunning = errors.New("a timer is already running")
+
+// ErrTimerActive is returned when updating a time entry whose timer is running or paused
+var ErrTimerActive = errors.New("the timer of the time entry is running")
+
 // TimeEntryRepositoryInterface defines methods that a TimeEntryRepository must implement
 type TimeEntryRepositoryInterface interface {
 	Create(ctx context.Context, timeEntry *models.TimeEntry) (*models.TimeEntry, error)
@@ -22,11 +31,16 @@ type TimeEntryRepositoryInterface interface {
 	Update(ctx context.Context, id string, timeEntry *models.TimeEntry) (*models.TimeEntry, error)
 	Delete(ctx context.Context, id string) error
 	DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error
+	StartTimer(ctx context.Context, timeEntry *models.TimeEntry) (*models.TimeEntry, error)
+	GetActiveTimer(ctx context.Context, userID primitive.ObjectID) (*models.TimeEntry, error)
+	GetActiveTimers(ctx context.Context) ([]*models.TimeEntry, error)
+	UpdateTimer(ctx context.Context, timeEntry *models.TimeEntry) (*models.TimeEntry, error)
 }
 
 // TimeEntryRepository provides methods to interact with time entry data in the database
 type TimeEntryRepository struct {
 	collection *mongo.Collection
+	changes    *ChangeRepository
 }
 
 // Ensure TimeEntryRepository implements TimeEntryRepositoryInterface
@@ -39,9 +53,21 @@ func NewTimeEntryRepository(database *mongo.Database) *TimeEntryRepository {
 	}
 	return &TimeEntryRepository{
 		collection: database.Collection(timeEntryCollection),
+		changes:    NewChangeRepository(database),
 	}
 }
 
+// EnsureTimeEntryIndexes creates the index allowing a single running or paused timer per user
+func EnsureTimeEntryIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(timeEntryCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"active_timer": true}),
+	})
+	return err
+}
+
 // Create adds a new time entry to the database
 func (r *TimeEntryRepository) Create(ctx context.Context, timeEntry *models.TimeEntry) (*models.TimeEntry, error) {
 	// Generate new ObjectID if not provided
@@ -60,6 +86,10 @@ func (r *TimeEntryRepository) Create(ctx context.Context, timeEntry *models.Time
 		return nil, err
 	}
 
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeTimeEntry, r.collection, bson.M{"_id": timeEntry.ID}, false); err != nil {
+		return nil, err
+	}
+
 	return timeEntry, nil
 }
 
@@ -100,7 +130,8 @@ func (r *TimeEntryRepository) GetAll(ctx context.Context, userID *primitive.Obje
 	return timeEntries, nil
 }
 
-// Update modifies an existing time entry in the database
+// Update modifies an existing time entry in the database.
+// It returns ErrTimerActive while the timer of the entry is running or paused.
 func (r *TimeEntryRepository) Update(ctx context.Context, id string, timeEntry *models.TimeEntry) (*models.TimeEntry, error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -122,10 +153,24 @@ func (r *TimeEntryRepository) Update(ctx context.Context, id string, timeEntry *
 		},
 	}
 
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, updateDoc)
+	// the entry of a running or paused timer is only updated by the timer endpoints
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID, "active_timer": bson.M{"$exists": false}}, updateDoc)
 	if err != nil {
 		return nil, err
 	}
+	if result.MatchedCount == 0 {
+		active, err := r.collection.CountDocuments(ctx, bson.M{"_id": objectID, "active_timer": true})
+		if err != nil {
+			return nil, err
+		}
+		if active > 0 {
+			return nil, ErrTimerActive
+		}
+	}
+
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeTimeEntry, r.collection, bson.M{"_id": objectID}, false); err != nil {
+		return nil, err
+	}
 
 	// Return the updated time entry
 	return r.GetByID(ctx, id)
@@ -138,6 +183,10 @@ func (r *TimeEntryRepository) Delete(ctx context.Context, id string) error {
 		return err
 	}
 
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeTimeEntry, r.collection, bson.M{"_id": objectID}, true); err != nil {
+		return err
+	}
+
 	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
 	return err
 }
@@ -148,3 +197,97 @@ func (r *TimeEntryRepository) DeleteByUserID(ctx context.Context, userID primiti
 	_, err := r.collection.DeleteMany(ctx, filter)
 	return err
 }
+
+// StartTimer inserts a running timer.
+// It returns ErrTimerAlreadyRunning when the user already has a running or paused timer.
+func (r *TimeEntryRepository) StartTimer(ctx context.Context, timeEntry *models.TimeEntry) (*models.TimeEntry, error) {
+	if timeEntry.User == nil {
+		return nil, errors.New("time entry has no user")
+	}
+
+	active, err := r.GetActiveTimer(ctx, *timeEntry.User)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, ErrTimerAlreadyRunning
+	}
+
+	// the unique index catches the timers started concurrently
+	created, err := r.Create(ctx, timeEntry)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrTimerAlreadyRunning
+	}
+	return created, err
+}
+
+// GetActiveTimer returns the running or paused timer of a user, nil when there is none
+func (r *TimeEntryRepository) GetActiveTimer(ctx context.Context, userID primitive.ObjectID) (*models.TimeEntry, error) {
+	var timeEntry models.TimeEntry
+	err := r.collection.FindOne(ctx, bson.M{"user": userID, "active_timer": true}).Decode(&timeEntry)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &timeEntry, nil
+}
+
+// GetActiveTimers returns the running and paused timers of all the users
+func (r *TimeEntryRepository) GetActiveTimers(ctx context.Context) ([]*models.TimeEntry, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"active_timer": true})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var timeEntries []*models.TimeEntry
+	if err := cursor.All(ctx, &timeEntries); err != nil {
+		return nil, err
+	}
+	return timeEntries, nil
+}
+
+// UpdateTimer saves the state of an active timer. It returns mongo.ErrNoDocuments when the timer
+// was stopped in the meantime, so a timer is only stopped once.
+func (r *TimeEntryRepository) UpdateTimer(ctx context.Context, timeEntry *models.TimeEntry) (*models.TimeEntry, error) {
+	timeEntry.UpdatedAt = time.Now().Format(time.RFC3339)
+
+	set := bson.M{
+		"end_date":       timeEntry.EndDate,
+		"duration":       timeEntry.Duration,
+		"timer_state":    timeEntry.TimerState,
+		"paused_seconds": timeEntry.PausedSeconds,
+		"updated_at":     timeEntry.UpdatedAt,
+	}
+	unset := bson.M{}
+	if timeEntry.PausedAt != nil {
+		set["paused_at"] = timeEntry.PausedAt
+	} else {
+		unset["paused_at"] = ""
+	}
+	if timeEntry.ActiveTimer == nil {
+		unset["active_timer"] = ""
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	filter := bson.M{"_id": timeEntry.ID, "active_timer": true}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	if err := r.changes.recordMatching(ctx, patchmodels.ItemTypeTimeEntry, r.collection, bson.M{"_id": timeEntry.ID}, false); err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, timeEntry.ID.Hex())
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func setupTimeEntryTest(t *testing.T) (TimeEntryRepositoryInterface, func()) {
@@ -295,3 +296,72 @@ func TestTimeEntryRepository_DeleteByUserID(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, finalUser2Entries, 0)
 }
+
+func TestTimeEntryRepository_Timer(t *testing.T) {
+	mongoServer, err := inmemorymongo.CreateInMemoryMongoDB()
+	require.NoError(t, err)
+	defer mongoServer.Stop()
+
+	client, err := inmemorymongo.ConnectToInMemoryDB(mongoServer.URI())
+	require.NoError(t, err)
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	db := client.Database("test_db")
+	require.NoError(t, EnsureTimeEntryIndexes(context.Background(), db))
+	repo := NewTimeEntryRepository(db)
+
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	running := models.TimerStateRunning
+	active := true
+	newTimer := func() *models.TimeEntry {
+		return &models.TimeEntry{
+			User:        &userID,
+			StartDate:   "2025-05-28T10:00:00Z",
+			TimerState:  &running,
+			ActiveTimer: &active,
+		}
+	}
+
+	started, err := repo.StartTimer(ctx, newTimer())
+	require.NoError(t, err)
+
+	// the entry of an active timer is not updated by generic updates
+	_, err = repo.Update(ctx, started.ID.Hex(), &models.TimeEntry{StartDate: "2025-05-28T09:00:00Z", EndDate: "2025-05-28T12:00:00Z"})
+	assert.ErrorIs(t, err, ErrTimerActive)
+
+	_, err = repo.StartTimer(ctx, newTimer())
+	assert.ErrorIs(t, err, ErrTimerAlreadyRunning)
+
+	activeTimer, err := repo.GetActiveTimer(ctx, userID)
+	require.NoError(t, err)
+	require.NotNil(t, activeTimer)
+	assert.Equal(t, started.ID, activeTimer.ID)
+
+	stopped := models.TimerStateStopped
+	duration := "3600"
+	activeTimer.TimerState = &stopped
+	activeTimer.ActiveTimer = nil
+	activeTimer.EndDate = "2025-05-28T11:00:00Z"
+	activeTimer.Duration = &duration
+	updated, err := repo.UpdateTimer(ctx, activeTimer)
+	require.NoError(t, err)
+	assert.Equal(t, models.TimerStateStopped, *updated.TimerState)
+	assert.Equal(t, "2025-05-28T11:00:00Z", updated.EndDate)
+
+	// a stopped timer is not updated again
+	_, err = repo.UpdateTimer(ctx, activeTimer)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	activeTimer, err = repo.GetActiveTimer(ctx, userID)
+	require.NoError(t, err)
+	assert.Nil(t, activeTimer)
+
+	// a new timer can be started once the previous one is stopped
+	_, err = repo.StartTimer(ctx, newTimer())
+	require.NoError(t, err)
+
+	timers, err := repo.GetActiveTimers(ctx)
+	require.NoError(t, err)
+	assert.Len(t, timers, 1)
+}
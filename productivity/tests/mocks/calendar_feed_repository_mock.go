@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockCalendarFeedRepository provides a mock implementation of CalendarFeedRepositoryInterface
+type MockCalendarFeedRepository struct {
+	mock.Mock
+}
+
+// GetByUserID retrieves the feed of a user
+func (m *MockCalendarFeedRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID) (*models.CalendarFeed, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CalendarFeed), args.Error(1)
+}
+
+// GetByToken retrieves the feed of a secret token
+func (m *MockCalendarFeedRepository) GetByToken(ctx context.Context, token string) (*models.CalendarFeed, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CalendarFeed), args.Error(1)
+}
+
+// Save sets the secret token of the feed of a user
+func (m *MockCalendarFeedRepository) Save(ctx context.Context, userID primitive.ObjectID, token string) (*models.CalendarFeed, error) {
+	args := m.Called(ctx, userID, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CalendarFeed), args.Error(1)
+}
+
+// DeleteByUserID deletes the feed of a user
+func (m *MockCalendarFeedRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
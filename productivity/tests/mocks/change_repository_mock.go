@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockChangeRepository provides a mock implementation of ChangeRepositoryInterface
+type MockChangeRepository struct {
+	mock.Mock
+}
+
+// Record marks the items of a user as changed, or deleted, at a new cursor
+func (m *MockChangeRepository) Record(ctx context.Context, userID primitive.ObjectID, itemType string, itemIDs []primitive.ObjectID, deleted bool) error {
+	args := m.Called(ctx, userID, itemType, itemIDs, deleted)
+	return args.Error(0)
+}
+
+// GetSince retrieves at most limit changes of a user after the cursor
+func (m *MockChangeRepository) GetSince(ctx context.Context, userID primitive.ObjectID, cursor int64, limit int64) ([]*models.Change, error) {
+	args := m.Called(ctx, userID, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Change), args.Error(1)
+}
+
+// LoadItems fills the change set with the current state of the given items
+func (m *MockChangeRepository) LoadItems(ctx context.Context, userID primitive.ObjectID, itemIDs map[string][]primitive.ObjectID, set *models.ChangeSet) error {
+	args := m.Called(ctx, userID, itemIDs, set)
+	return args.Error(0)
+}
+
+// LoadSnapshot fills the change set with a page of the items of a user
+func (m *MockChangeRepository) LoadSnapshot(ctx context.Context, userID primitive.ObjectID, after string, limit int64, set *models.ChangeSet) (string, error) {
+	args := m.Called(ctx, userID, after, limit, set)
+	return args.String(0), args.Error(1)
+}
+
+// CurrentCursor returns the last cursor given to a change
+func (m *MockChangeRepository) CurrentCursor(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// PurgedCursor returns the highest cursor of the purged tombstones
+func (m *MockChangeRepository) PurgedCursor(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// PurgeTombstones deletes the tombstones older than the given date
+func (m *MockChangeRepository) PurgeTombstones(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// DeleteByUserID deletes the change feed of a specific user
+func (m *MockChangeRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
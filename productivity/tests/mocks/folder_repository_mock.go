@@ -23,6 +23,15 @@ func (m *MockFolderRepository) GetAll(ctx context.Context, userID primitive.Obje
 	return args.Get(0).([]*models.Folder), args.Error(1)
 }
 
+// GetByID retrieves a folder by its ID
+func (m *MockFolderRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Folder, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Folder), args.Error(1)
+}
+
 // Create creates a new folder
 func (m *MockFolderRepository) Create(ctx context.Context, folder *models.Folder) (*models.Folder, error) {
 	args := m.Called(ctx, folder)
@@ -52,3 +61,27 @@ func (m *MockFolderRepository) DeleteByUserID(ctx context.Context, userID primit
 	args := m.Called(ctx, userID)
 	return args.Error(0)
 }
+
+// DeleteCascade mocks the DeleteCascade method
+func (m *MockFolderRepository) DeleteCascade(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Move mocks the Move method
+func (m *MockFolderRepository) Move(ctx context.Context, id primitive.ObjectID, parentID *primitive.ObjectID) (*models.Folder, error) {
+	args := m.Called(ctx, id, parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Folder), args.Error(1)
+}
+
+// GetTaskCounts mocks the GetTaskCounts method
+func (m *MockFolderRepository) GetTaskCounts(ctx context.Context, userID primitive.ObjectID) ([]*models.FolderTaskCount, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.FolderTaskCount), args.Error(1)
+}
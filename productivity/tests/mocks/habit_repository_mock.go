@@ -77,6 +77,15 @@ func (m *MockHabitRepository) AddEntry(ctx context.Context, entry *models.HabitE
 	return args.Get(0).(*models.HabitEntry), args.Error(1)
 }
 
+// GetEntryByID gets a habit entry by ID
+func (m *MockHabitRepository) GetEntryByID(ctx context.Context, id primitive.ObjectID) (*models.HabitEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HabitEntry), args.Error(1)
+}
+
 // GetEntriesByHabitID gets all entries for a habit
 func (m *MockHabitRepository) GetEntriesByHabitID(ctx context.Context, habitID primitive.ObjectID) ([]models.HabitEntry, error) {
 	args := m.Called(ctx, habitID)
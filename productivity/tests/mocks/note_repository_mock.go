@@ -81,3 +81,21 @@ func (m *MockNoteRepository) GetSince(ctx context.Context, userID primitive.Obje
 	}
 	return args.Get(0).([]*models.NoteEntity), args.Get(1).(int64), args.Error(2)
 }
+
+// GetRevisions retrieves the saved versions of a note
+func (m *MockNoteRepository) GetRevisions(ctx context.Context, noteID primitive.ObjectID) ([]*models.NoteRevision, error) {
+	args := m.Called(ctx, noteID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.NoteRevision), args.Error(1)
+}
+
+// GetRevision retrieves a saved version of a note by its number
+func (m *MockNoteRepository) GetRevision(ctx context.Context, noteID primitive.ObjectID, rev int) (*models.NoteRevision, error) {
+	args := m.Called(ctx, noteID, rev)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NoteRevision), args.Error(1)
+}
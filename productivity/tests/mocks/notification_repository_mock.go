@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockNotificationRepository provides a mock implementation of NotificationRepositoryInterface
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+// Claim inserts the notification in the ledger as pending
+func (m *MockNotificationRepository) Claim(ctx context.Context, notification *models.Notification) (bool, error) {
+	args := m.Called(ctx, notification)
+	return args.Bool(0), args.Error(1)
+}
+
+// RecordResult stores the outcome of sending a claimed notification
+func (m *MockNotificationRepository) RecordResult(ctx context.Context, notification *models.Notification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+// LastRun returns when a notification job last ran
+func (m *MockNotificationRepository) LastRun(ctx context.Context, job string) (time.Time, error) {
+	args := m.Called(ctx, job)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+// SetLastRun stores when a notification job last ran
+func (m *MockNotificationRepository) SetLastRun(ctx context.Context, job string, at time.Time) error {
+	args := m.Called(ctx, job, at)
+	return args.Error(0)
+}
+
+// Snooze schedules a notification to be sent again
+func (m *MockNotificationRepository) Snooze(ctx context.Context, snooze *models.NotificationSnooze) error {
+	args := m.Called(ctx, snooze)
+	return args.Error(0)
+}
+
+// GetSnoozesBetween retrieves the snoozed notifications to send between from and to
+func (m *MockNotificationRepository) GetSnoozesBetween(ctx context.Context, from, to time.Time) ([]*models.NotificationSnooze, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.NotificationSnooze), args.Error(1)
+}
+
+// DeleteByUserID deletes the notifications of a specific user
+func (m *MockNotificationRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
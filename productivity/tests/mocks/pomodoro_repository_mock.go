@@ -0,0 +1,64 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockPomodoroRepository is a mock implementation of PomodoroRepositoryInterface
+type MockPomodoroRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockPomodoroRepository) Create(ctx context.Context, session *models.PomodoroSession) (*models.PomodoroSession, error) {
+	args := m.Called(ctx, session)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PomodoroSession), args.Error(1)
+}
+
+// GetByID mocks the GetByID method
+func (m *MockPomodoroRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.PomodoroSession, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PomodoroSession), args.Error(1)
+}
+
+// GetActive mocks the GetActive method
+func (m *MockPomodoroRepository) GetActive(ctx context.Context, userID primitive.ObjectID) (*models.PomodoroSession, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PomodoroSession), args.Error(1)
+}
+
+// GetDue mocks the GetDue method
+func (m *MockPomodoroRepository) GetDue(ctx context.Context, now time.Time) ([]*models.PomodoroSession, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PomodoroSession), args.Error(1)
+}
+
+// Save mocks the Save method
+func (m *MockPomodoroRepository) Save(ctx context.Context, session *models.PomodoroSession, phaseStartedAt primitive.DateTime) error {
+	args := m.Called(ctx, session, phaseStartedAt)
+	return args.Error(0)
+}
+
+// DeleteByUserID mocks the DeleteByUserID method
+func (m *MockPomodoroRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
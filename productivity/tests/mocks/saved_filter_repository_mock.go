@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockSavedFilterRepository provides a mock implementation of SavedFilterRepositoryInterface
+type MockSavedFilterRepository struct {
+	mock.Mock
+}
+
+// GetAll retrieves the saved filters of a user
+func (m *MockSavedFilterRepository) GetAll(ctx context.Context, userID primitive.ObjectID) ([]*models.SavedFilter, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SavedFilter), args.Error(1)
+}
+
+// GetByID retrieves a saved filter by its ID
+func (m *MockSavedFilterRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.SavedFilter, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SavedFilter), args.Error(1)
+}
+
+// Create creates a new saved filter
+func (m *MockSavedFilterRepository) Create(ctx context.Context, filter *models.SavedFilter) (*models.SavedFilter, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SavedFilter), args.Error(1)
+}
+
+// Update updates a saved filter
+func (m *MockSavedFilterRepository) Update(ctx context.Context, id primitive.ObjectID, filter *models.SavedFilter) (*models.SavedFilter, error) {
+	args := m.Called(ctx, id, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SavedFilter), args.Error(1)
+}
+
+// Delete deletes a saved filter
+func (m *MockSavedFilterRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// DeleteByUserID deletes all the saved filters of a user
+func (m *MockSavedFilterRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
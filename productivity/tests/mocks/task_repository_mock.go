@@ -25,6 +25,15 @@ func (m *MockTaskRepository) GetAll(ctx context.Context, userID *primitive.Objec
 	return args.Get(0).([]*models.TaskEntity), args.Get(1).(int64), args.Error(2)
 }
 
+// Search gets the tasks matching a filter
+func (m *MockTaskRepository) Search(ctx context.Context, userID *primitive.ObjectID, filter *models.TaskFilter, sorts []models.TaskSort, page, limit *int64) ([]*models.TaskEntity, int64, error) {
+	args := m.Called(ctx, userID, filter, sorts, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*models.TaskEntity), args.Get(1).(int64), args.Error(2)
+}
+
 // GetByID gets a task by ID
 func (m *MockTaskRepository) GetByID(ctx context.Context, id string) (*models.TaskEntity, error) {
 	args := m.Called(ctx, id)
@@ -108,3 +117,36 @@ func (m *MockTaskRepository) GetSince(ctx context.Context, userID primitive.Obje
 	}
 	return args.Get(0).([]*models.TaskEntity), args.Get(1).(int64), args.Error(2)
 }
+
+// GetChildren gets the subtasks of a task
+func (m *MockTaskRepository) GetChildren(ctx context.Context, parentID string) ([]*models.TaskEntity, error) {
+	args := m.Called(ctx, parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.TaskEntity), args.Error(1)
+}
+
+// ReorderChildren reorders the subtasks of a task
+func (m *MockTaskRepository) ReorderChildren(ctx context.Context, parentID string, childIDs []string) error {
+	args := m.Called(ctx, parentID, childIDs)
+	return args.Error(0)
+}
+
+// RemoveTag removes a tag from all the tasks of a user
+func (m *MockTaskRepository) RemoveTag(ctx context.Context, userID primitive.ObjectID, tagID primitive.ObjectID) error {
+	args := m.Called(ctx, userID, tagID)
+	return args.Error(0)
+}
+
+// LinkNote links a note to a task
+func (m *MockTaskRepository) LinkNote(ctx context.Context, taskID primitive.ObjectID, noteID primitive.ObjectID) error {
+	args := m.Called(ctx, taskID, noteID)
+	return args.Error(0)
+}
+
+// UnlinkNote removes the link between a note and a task
+func (m *MockTaskRepository) UnlinkNote(ctx context.Context, taskID primitive.ObjectID, noteID primitive.ObjectID) error {
+	args := m.Called(ctx, taskID, noteID)
+	return args.Error(0)
+}
@@ -61,3 +61,39 @@ func (m *MockTimeEntryRepository) DeleteByUserID(ctx context.Context, userID pri
 	args := m.Called(ctx, userID)
 	return args.Error(0)
 }
+
+// StartTimer mocks the StartTimer method
+func (m *MockTimeEntryRepository) StartTimer(ctx context.Context, timeEntry *models.TimeEntry) (*models.TimeEntry, error) {
+	args := m.Called(ctx, timeEntry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+
+// GetActiveTimer mocks the GetActiveTimer method
+func (m *MockTimeEntryRepository) GetActiveTimer(ctx context.Context, userID primitive.ObjectID) (*models.TimeEntry, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+
+// GetActiveTimers mocks the GetActiveTimers method
+func (m *MockTimeEntryRepository) GetActiveTimers(ctx context.Context) ([]*models.TimeEntry, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.TimeEntry), args.Error(1)
+}
+
+// UpdateTimer mocks the UpdateTimer method
+func (m *MockTimeEntryRepository) UpdateTimer(ctx context.Context, timeEntry *models.TimeEntry) (*models.TimeEntry, error) {
+	args := m.Called(ctx, timeEntry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
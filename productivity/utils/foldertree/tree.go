@@ -0,0 +1,123 @@
+package foldertree
+
+import (
+	"sort"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Build returns the roots of the folder tree with their task counts. A folder whose parent does not exist,
+// or which is part of a cycle, is listed as a root. Siblings are sorted by name.
+func Build(folders []*models.Folder, counts []*models.FolderTaskCount) []*models.FolderNode {
+	nodes := map[primitive.ObjectID]*models.FolderNode{}
+	for _, folder := range folders {
+		if folder.ID == nil {
+			continue
+		}
+		nodes[*folder.ID] = &models.FolderNode{Folder: folder, Children: []*models.FolderNode{}}
+	}
+	for _, count := range counts {
+		if node, ok := nodes[count.FolderID]; ok {
+			node.TaskCount = count.Open
+			node.CompletedTaskCount = count.Completed
+		}
+	}
+
+	parents := parentsOf(folders)
+	roots := []*models.FolderNode{}
+	for _, folder := range folders {
+		if folder.ID == nil {
+			continue
+		}
+		node := nodes[*folder.ID]
+		parent, ok := nodes[derefID(folder.ParentID)]
+		if folder.ParentID == nil || !ok || inCycle(parents, *folder.ID) {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	sortNodes(roots)
+	for _, root := range roots {
+		total(root)
+	}
+	return roots
+}
+
+// WouldCycle tells whether moving the folder under the new parent would make the folder one of its own ancestors
+func WouldCycle(folders []*models.Folder, folderID primitive.ObjectID, parentID *primitive.ObjectID) bool {
+	if parentID == nil {
+		return false
+	}
+
+	parents := parentsOf(folders)
+	seen := map[primitive.ObjectID]bool{}
+	for current := *parentID; ; {
+		if current == folderID {
+			return true
+		}
+		if seen[current] {
+			// an existing cycle above the new parent, which does not contain the folder
+			return false
+		}
+		seen[current] = true
+
+		parent, ok := parents[current]
+		if !ok {
+			return false
+		}
+		current = parent
+	}
+}
+
+// parentsOf maps each folder to its parent
+func parentsOf(folders []*models.Folder) map[primitive.ObjectID]primitive.ObjectID {
+	parents := map[primitive.ObjectID]primitive.ObjectID{}
+	for _, folder := range folders {
+		if folder.ID != nil && folder.ParentID != nil {
+			parents[*folder.ID] = *folder.ParentID
+		}
+	}
+	return parents
+}
+
+// inCycle tells whether the folder is its own ancestor
+func inCycle(parents map[primitive.ObjectID]primitive.ObjectID, folderID primitive.ObjectID) bool {
+	seen := map[primitive.ObjectID]bool{}
+	for current, ok := parents[folderID]; ok; current, ok = parents[current] {
+		if current == folderID {
+			return true
+		}
+		if seen[current] {
+			return false
+		}
+		seen[current] = true
+	}
+	return false
+}
+
+// total sums the open tasks of the node and its descendants
+func total(node *models.FolderNode) int64 {
+	sortNodes(node.Children)
+	node.TotalTaskCount = node.TaskCount
+	for _, child := range node.Children {
+		node.TotalTaskCount += total(child)
+	}
+	return node.TotalTaskCount
+}
+
+func sortNodes(nodes []*models.FolderNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+func derefID(id *primitive.ObjectID) primitive.ObjectID {
+	if id == nil {
+		return primitive.NilObjectID
+	}
+	return *id
+}
@@ -0,0 +1,83 @@
+package foldertree
+
+import (
+	"testing"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func folder(name string, parentID *primitive.ObjectID) *models.Folder {
+	id := primitive.NewObjectID()
+	return &models.Folder{ID: &id, Name: name, ParentID: parentID}
+}
+
+func TestBuild(t *testing.T) {
+	work := folder("Work", nil)
+	home := folder("Home", nil)
+	projects := folder("Projects", work.ID)
+	archive := folder("Archive", work.ID)
+	client := folder("Client", projects.ID)
+	missing := primitive.NewObjectID()
+	orphan := folder("Orphan", &missing)
+
+	counts := []*models.FolderTaskCount{
+		{FolderID: *work.ID, Open: 1, Completed: 4},
+		{FolderID: *projects.ID, Open: 2},
+		{FolderID: *client.ID, Open: 3, Completed: 1},
+		{FolderID: primitive.NewObjectID(), Open: 10},
+	}
+
+	roots := Build([]*models.Folder{client, work, home, projects, archive, orphan}, counts)
+
+	require.Len(t, roots, 3)
+	assert.Equal(t, "Home", roots[0].Name)
+	assert.Equal(t, "Orphan", roots[1].Name)
+	assert.Equal(t, "Work", roots[2].Name)
+
+	workNode := roots[2]
+	assert.Equal(t, int64(1), workNode.TaskCount)
+	assert.Equal(t, int64(4), workNode.CompletedTaskCount)
+	assert.Equal(t, int64(6), workNode.TotalTaskCount)
+	require.Len(t, workNode.Children, 2)
+	assert.Equal(t, "Archive", workNode.Children[0].Name)
+	assert.Equal(t, "Projects", workNode.Children[1].Name)
+	assert.Equal(t, int64(5), workNode.Children[1].TotalTaskCount)
+	require.Len(t, workNode.Children[1].Children, 1)
+	assert.Equal(t, int64(3), workNode.Children[1].Children[0].TotalTaskCount)
+	assert.Empty(t, roots[0].Children)
+}
+
+func TestBuildCycle(t *testing.T) {
+	a := folder("A", nil)
+	b := folder("B", a.ID)
+	a.ParentID = b.ID
+	c := folder("C", b.ID)
+
+	roots := Build([]*models.Folder{a, b, c}, nil)
+
+	// the folders of the cycle are listed as roots instead of being lost
+	require.Len(t, roots, 2)
+	assert.Equal(t, "A", roots[0].Name)
+	assert.Equal(t, "B", roots[1].Name)
+	require.Len(t, roots[1].Children, 1)
+	assert.Equal(t, "C", roots[1].Children[0].Name)
+}
+
+func TestWouldCycle(t *testing.T) {
+	root := folder("Root", nil)
+	child := folder("Child", root.ID)
+	grandChild := folder("Grand child", child.ID)
+	other := folder("Other", nil)
+	folders := []*models.Folder{root, child, grandChild, other}
+
+	assert.True(t, WouldCycle(folders, *root.ID, root.ID))
+	assert.True(t, WouldCycle(folders, *root.ID, grandChild.ID))
+	assert.True(t, WouldCycle(folders, *child.ID, grandChild.ID))
+	assert.False(t, WouldCycle(folders, *grandChild.ID, root.ID))
+	assert.False(t, WouldCycle(folders, *root.ID, other.ID))
+	assert.False(t, WouldCycle(folders, *root.ID, nil))
+}
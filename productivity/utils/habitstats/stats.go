@@ -0,0 +1,261 @@
+package habitstats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+)
+
+// HistoryWeeks and HistoryMonths are the number of weeks and months returned in the completion rates
+const (
+	HistoryWeeks  = 12
+	HistoryMonths = 12
+)
+
+// period is a span of time in which a habit is expected to be done a number of times
+type period struct {
+	start time.Time
+	end   time.Time
+}
+
+// Compute returns the statistics of a habit from its entries at the given time.
+// Days, weeks and months are those of the given location, weeks start on Monday.
+//
+// The scheduled periods depend on the frequency of the habit:
+//   - daily: every day, or only the days listed in DaysOfWeek
+//   - weekly: every week, DaysOfWeek only being the days the habit is reminded on
+//   - monthly: the days of the month listed in DaysOfMonth, or every month when none is listed
+//   - repeating: Duration milliseconds from the last entry, or from the start date before the first one,
+//     until the end of the day the habit is due on
+//
+// A period is completed when at least NumberOfTimes entries (1 by default) were logged in it.
+func Compute(habit *models.Habit, entries []models.HabitEntry, now time.Time, loc *time.Location) *models.HabitStats {
+	stats := &models.HabitStats{
+		HabitID:      habit.ID,
+		Timezone:     loc.String(),
+		TotalEntries: len(entries),
+		Weekly:       []models.HabitCompletionRate{},
+		Monthly:      []models.HabitCompletionRate{},
+	}
+
+	periods := scheduledPeriods(habit, entries, now, loc)
+	if len(periods) == 0 {
+		return stats
+	}
+
+	// count the entries logged in each period
+	counts := make([]int, len(periods))
+	for _, entry := range entries {
+		date := entry.EntryDate.Time()
+		i := sort.Search(len(periods), func(i int) bool { return periods[i].end.After(date) })
+		if i < len(periods) && !date.Before(periods[i].start) {
+			counts[i]++
+		}
+	}
+
+	target := Target(habit)
+	completed := make([]bool, len(periods))
+	for i := range periods {
+		completed[i] = counts[i] >= target
+	}
+
+	// the period in progress only counts once it is completed
+	last := len(periods) - 1
+	evaluated := len(periods)
+	if now.Before(periods[last].end) && !completed[last] {
+		evaluated--
+	}
+
+	run := 0
+	done := 0
+	for i := 0; i < evaluated; i++ {
+		if completed[i] {
+			run++
+			done++
+			stats.LongestStreak = max(stats.LongestStreak, run)
+		} else {
+			run = 0
+		}
+	}
+	stats.CurrentStreak = run
+	if evaluated > 0 {
+		stats.CompletionRate = float64(done) / float64(evaluated)
+	}
+
+	stats.CurrentPeriod = &models.HabitPeriodProgress{
+		Start:     periods[last].start,
+		End:       periods[last].end,
+		Count:     counts[last],
+		Target:    target,
+		Completed: completed[last],
+	}
+
+	stats.Weekly = completionRates(periods[:evaluated], completed, startOfWeek, HistoryWeeks)
+	stats.Monthly = completionRates(periods[:evaluated], completed, startOfMonth, HistoryMonths)
+	return stats
+}
+
+// Target returns the number of entries expected in each period of the habit
+func Target(habit *models.Habit) int {
+	if habit.NumberOfTimes != nil && *habit.NumberOfTimes > 0 {
+		return *habit.NumberOfTimes
+	}
+	return 1
+}
+
+// WeekdayIndex returns the index of the day of the week as used by DaysOfWeek: 0 is Monday and 6 is Sunday
+func WeekdayIndex(date time.Time) int {
+	return (int(date.Weekday()) + 6) % 7
+}
+
+// scheduledPeriods returns the periods of the habit which started before now, in chronological order
+func scheduledPeriods(habit *models.Habit, entries []models.HabitEntry, now time.Time, loc *time.Location) []period {
+	if habit.StartDate == nil || habit.Frequency == nil {
+		return nil
+	}
+
+	start := habit.StartDate.Time().In(loc)
+	limit := now
+	if habit.EndDate != nil && habit.EndDate.Time().Before(limit) {
+		limit = habit.EndDate.Time()
+	}
+	if start.After(limit) {
+		return nil
+	}
+
+	days := daysOfWeek(habit)
+	switch *habit.Frequency {
+	case models.FrequencyWeekly:
+		return calendarPeriods(startOfWeek(start), limit, func(t time.Time) time.Time { return t.AddDate(0, 0, 7) })
+	case models.FrequencyMonthly:
+		monthDays := daysOfMonth(habit)
+		if len(monthDays) == 0 {
+			return calendarPeriods(startOfMonth(start), limit, func(t time.Time) time.Time { return t.AddDate(0, 1, 0) })
+		}
+		return dayPeriods(start, limit, func(day time.Time) bool { return monthDays[day.Day()] })
+	case models.FrequencyRepeating:
+		if habit.Duration != nil && *habit.Duration > 0 {
+			return repeatingPeriods(start, limit, time.Duration(*habit.Duration)*time.Millisecond, entryDates(entries), Target(habit))
+		}
+		return dayPeriods(start, limit, func(time.Time) bool { return true })
+	default:
+		if len(days) == 0 {
+			return dayPeriods(start, limit, func(time.Time) bool { return true })
+		}
+		return dayPeriods(start, limit, func(day time.Time) bool { return days[WeekdayIndex(day)] })
+	}
+}
+
+// dayPeriods returns the days from the day of start until limit which are scheduled
+func dayPeriods(start, limit time.Time, scheduled func(day time.Time) bool) []period {
+	periods := []period{}
+	for day := startOfDay(start); !day.After(limit); day = day.AddDate(0, 0, 1) {
+		if scheduled(day) {
+			periods = append(periods, period{start: day, end: day.AddDate(0, 0, 1)})
+		}
+	}
+	return periods
+}
+
+// calendarPeriods returns the consecutive periods from first until limit
+func calendarPeriods(first, limit time.Time, next func(time.Time) time.Time) []period {
+	periods := []period{}
+	for start := first; !start.After(limit); start = next(start) {
+		periods = append(periods, period{start: start, end: next(start)})
+	}
+	return periods
+}
+
+// repeatingPeriods returns the periods of a habit due the given length after its last entry, as the habit
+// reminders do, from start until limit. A period ends with the entry completing it, the next period being
+// due the given length after that entry, or at the end of the day it is due on when it is missed.
+// The entry dates are in chronological order.
+func repeatingPeriods(start, limit time.Time, length time.Duration, dates []time.Time, target int) []period {
+	periods := []period{}
+	next := 0
+	for from := start; !from.After(limit); {
+		end := startOfDay(from.Add(length)).AddDate(0, 0, 1)
+		for next < len(dates) && dates[next].Before(from) {
+			next++
+		}
+		if next+target-1 < len(dates) && dates[next+target-1].Before(end) {
+			// entries are stored with a millisecond precision
+			end = dates[next+target-1].Add(time.Millisecond)
+		}
+		periods = append(periods, period{start: from, end: end})
+		from = end
+	}
+	return periods
+}
+
+// entryDates returns the dates of the entries in chronological order
+func entryDates(entries []models.HabitEntry) []time.Time {
+	dates := make([]time.Time, 0, len(entries))
+	for _, entry := range entries {
+		dates = append(dates, entry.EntryDate.Time())
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates
+}
+
+// completionRates groups the periods by week or month and returns the rates of the last groups
+func completionRates(periods []period, completed []bool, group func(time.Time) time.Time, count int) []models.HabitCompletionRate {
+	rates := []models.HabitCompletionRate{}
+	for i, p := range periods {
+		start := group(p.start)
+		if len(rates) == 0 || !rates[len(rates)-1].Start.Equal(start) {
+			rates = append(rates, models.HabitCompletionRate{Start: start})
+		}
+		rate := &rates[len(rates)-1]
+		rate.Scheduled++
+		if completed[i] {
+			rate.Completed++
+		}
+	}
+
+	for i := range rates {
+		rates[i].Rate = float64(rates[i].Completed) / float64(rates[i].Scheduled)
+	}
+	if len(rates) > count {
+		rates = rates[len(rates)-count:]
+	}
+	return rates
+}
+
+// daysOfWeek returns the days of the week the habit is scheduled on, indexed as WeekdayIndex
+func daysOfWeek(habit *models.Habit) map[int]bool {
+	days := map[int]bool{}
+	if habit.DaysOfWeek != nil {
+		for _, day := range *habit.DaysOfWeek {
+			if day >= 0 && day <= 6 {
+				days[day] = true
+			}
+		}
+	}
+	return days
+}
+
+// daysOfMonth returns the days of the month the habit is scheduled on.
+// DaysOfMonth holds dates at midnight UTC of which only the day of the month is used.
+func daysOfMonth(habit *models.Habit) map[int]bool {
+	days := map[int]bool{}
+	if habit.DaysOfMonth != nil {
+		for _, date := range *habit.DaysOfMonth {
+			days[date.Time().UTC().Day()] = true
+		}
+	}
+	return days
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func startOfWeek(t time.Time) time.Time {
+	return startOfDay(t).AddDate(0, 0, -WeekdayIndex(t))
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,287 @@
+package habitstats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newHabit(frequency string, start time.Time) *models.Habit {
+	name := "Habit"
+	startDate := primitive.NewDateTimeFromTime(start)
+	return &models.Habit{
+		ID:        primitive.NewObjectID(),
+		Name:      &name,
+		Frequency: &frequency,
+		StartDate: &startDate,
+	}
+}
+
+func entriesAt(dates ...time.Time) []models.HabitEntry {
+	entries := make([]models.HabitEntry, 0, len(dates))
+	for _, date := range dates {
+		entries = append(entries, models.HabitEntry{EntryDate: primitive.NewDateTimeFromTime(date)})
+	}
+	return entries
+}
+
+func TestCompute(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	// Monday 2025-06-02
+	monday := time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		habit          func() *models.Habit
+		entries        []models.HabitEntry
+		now            time.Time
+		loc            *time.Location
+		currentStreak  int
+		longestStreak  int
+		completionRate float64
+		currentCount   int
+		currentTarget  int
+	}{
+		{
+			name:  "daily streak with today in progress",
+			habit: func() *models.Habit { return newHabit(models.FrequencyDaily, monday) },
+			// done on monday, tuesday, thursday and friday, not yet on saturday
+			entries:        entriesAt(monday, monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 3), monday.AddDate(0, 0, 4)),
+			now:            monday.AddDate(0, 0, 5),
+			loc:            time.UTC,
+			currentStreak:  2,
+			longestStreak:  2,
+			completionRate: 0.8,
+			currentCount:   0,
+			currentTarget:  1,
+		},
+		{
+			name: "daily restricted to days of week",
+			habit: func() *models.Habit {
+				habit := newHabit(models.FrequencyDaily, monday)
+				days := []int{0, 2, 4} // monday, wednesday, friday
+				habit.DaysOfWeek = &days
+				return habit
+			},
+			entries:        entriesAt(monday, monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 4), monday.AddDate(0, 0, 7)),
+			now:            monday.AddDate(0, 0, 8),
+			loc:            time.UTC,
+			currentStreak:  4,
+			longestStreak:  4,
+			completionRate: 1,
+			currentCount:   1,
+			currentTarget:  1,
+		},
+		{
+			name: "number of times per day",
+			habit: func() *models.Habit {
+				habit := newHabit(models.FrequencyDaily, monday)
+				times := 2
+				habit.NumberOfTimes = &times
+				return habit
+			},
+			entries:        entriesAt(monday, monday.Add(time.Hour), monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 2)),
+			now:            monday.AddDate(0, 0, 2).Add(2 * time.Hour),
+			loc:            time.UTC,
+			currentStreak:  0,
+			longestStreak:  1,
+			completionRate: 0.5,
+			currentCount:   1,
+			currentTarget:  2,
+		},
+		{
+			name:  "weekly without days",
+			habit: func() *models.Habit { return newHabit(models.FrequencyWeekly, monday) },
+			// done in the first and third week
+			entries:        entriesAt(monday.AddDate(0, 0, 3), monday.AddDate(0, 0, 15)),
+			now:            monday.AddDate(0, 0, 16),
+			loc:            time.UTC,
+			currentStreak:  1,
+			longestStreak:  1,
+			completionRate: 2.0 / 3.0,
+			currentCount:   1,
+			currentTarget:  1,
+		},
+		{
+			name: "monthly on days of month",
+			habit: func() *models.Habit {
+				habit := newHabit(models.FrequencyMonthly, monday)
+				days := []primitive.DateTime{
+					primitive.NewDateTimeFromTime(time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)),
+					primitive.NewDateTimeFromTime(time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)),
+				}
+				habit.DaysOfMonth = &days
+				return habit
+			},
+			entries: entriesAt(
+				time.Date(2025, 6, 5, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 6, 20, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 7, 5, 9, 0, 0, 0, time.UTC),
+			),
+			now:            time.Date(2025, 7, 25, 9, 0, 0, 0, time.UTC),
+			loc:            time.UTC,
+			currentStreak:  0,
+			longestStreak:  3,
+			completionRate: 0.75,
+			currentCount:   0,
+			currentTarget:  1,
+		},
+		{
+			name: "repeating every two days",
+			habit: func() *models.Habit {
+				habit := newHabit(models.FrequencyRepeating, monday)
+				duration := int((48 * time.Hour).Milliseconds())
+				habit.Duration = &duration
+				return habit
+			},
+			// done on monday, then only on thursday after being due on wednesday
+			entries:        entriesAt(monday.Add(time.Hour), monday.AddDate(0, 0, 3)),
+			now:            monday.AddDate(0, 0, 5),
+			loc:            time.UTC,
+			currentStreak:  1,
+			longestStreak:  1,
+			completionRate: 2.0 / 3.0,
+			currentCount:   0,
+			currentTarget:  1,
+		},
+		{
+			name: "repeating from the last entry",
+			habit: func() *models.Habit {
+				habit := newHabit(models.FrequencyRepeating, monday)
+				duration := int((48 * time.Hour).Milliseconds())
+				habit.Duration = &duration
+				return habit
+			},
+			// each entry is logged before the habit is due again, two days after the previous one
+			entries:        entriesAt(monday.Add(time.Hour), monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 4).Add(-time.Hour)),
+			now:            monday.AddDate(0, 0, 5).Add(4 * time.Hour),
+			loc:            time.UTC,
+			currentStreak:  3,
+			longestStreak:  3,
+			completionRate: 1,
+			currentCount:   0,
+			currentTarget:  1,
+		},
+		{
+			name: "weekly on days of week counts the entries of the week",
+			habit: func() *models.Habit {
+				habit := newHabit(models.FrequencyWeekly, monday)
+				days := []int{0, 2, 4} // monday, wednesday, friday
+				habit.DaysOfWeek = &days
+				times := 3
+				habit.NumberOfTimes = &times
+				return habit
+			},
+			// three times in the first week, twice in the second
+			entries: entriesAt(
+				monday, monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 4),
+				monday.AddDate(0, 0, 7), monday.AddDate(0, 0, 8),
+				monday.AddDate(0, 0, 14),
+			),
+			now:            monday.AddDate(0, 0, 16),
+			loc:            time.UTC,
+			currentStreak:  0,
+			longestStreak:  1,
+			completionRate: 0.5,
+			currentCount:   1,
+			currentTarget:  3,
+		},
+		{
+			name: "days follow the timezone of the user",
+			habit: func() *models.Habit {
+				return newHabit(models.FrequencyDaily, time.Date(2025, 6, 1, 22, 30, 0, 0, time.UTC))
+			},
+			// 23:30 UTC on june 1st and 2nd are already june 2nd and 3rd in Paris
+			entries:        entriesAt(time.Date(2025, 6, 1, 23, 30, 0, 0, time.UTC), time.Date(2025, 6, 2, 23, 30, 0, 0, time.UTC)),
+			now:            time.Date(2025, 6, 3, 12, 0, 0, 0, time.UTC),
+			loc:            paris,
+			currentStreak:  2,
+			longestStreak:  2,
+			completionRate: 1,
+			currentCount:   1,
+			currentTarget:  1,
+		},
+		{
+			name: "days keep their length across daylight saving time",
+			habit: func() *models.Habit {
+				return newHabit(models.FrequencyDaily, time.Date(2025, 3, 29, 10, 0, 0, 0, paris))
+			},
+			// march 30th 2025 only lasts 23 hours in Paris
+			entries: entriesAt(
+				time.Date(2025, 3, 29, 23, 30, 0, 0, paris),
+				time.Date(2025, 3, 30, 23, 30, 0, 0, paris),
+				time.Date(2025, 3, 31, 0, 30, 0, 0, paris),
+			),
+			now:            time.Date(2025, 3, 31, 12, 0, 0, 0, paris),
+			loc:            paris,
+			currentStreak:  3,
+			longestStreak:  3,
+			completionRate: 1,
+			currentCount:   1,
+			currentTarget:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := Compute(tt.habit(), tt.entries, tt.now, tt.loc)
+
+			assert.Equal(t, tt.currentStreak, stats.CurrentStreak, "current streak")
+			assert.Equal(t, tt.longestStreak, stats.LongestStreak, "longest streak")
+			assert.InDelta(t, tt.completionRate, stats.CompletionRate, 0.001, "completion rate")
+			require.NotNil(t, stats.CurrentPeriod)
+			assert.Equal(t, tt.currentCount, stats.CurrentPeriod.Count, "current count")
+			assert.Equal(t, tt.currentTarget, stats.CurrentPeriod.Target, "current target")
+			assert.Equal(t, tt.loc.String(), stats.Timezone)
+		})
+	}
+}
+
+func TestComputeRates(t *testing.T) {
+	// Monday 2025-06-02
+	monday := time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC)
+	habit := newHabit(models.FrequencyDaily, monday)
+
+	// every day of the first week, only monday of the second
+	entries := []models.HabitEntry{}
+	for i := 0; i < 8; i++ {
+		entries = append(entries, entriesAt(monday.AddDate(0, 0, i))...)
+	}
+
+	stats := Compute(habit, entries, monday.AddDate(0, 0, 13).Add(time.Hour), time.UTC)
+
+	require.Len(t, stats.Weekly, 2)
+	assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), stats.Weekly[0].Start)
+	assert.Equal(t, 7, stats.Weekly[0].Completed)
+	assert.Equal(t, 1.0, stats.Weekly[0].Rate)
+	// sunday is in progress and not counted yet
+	assert.Equal(t, 6, stats.Weekly[1].Scheduled)
+	assert.Equal(t, 1, stats.Weekly[1].Completed)
+
+	require.Len(t, stats.Monthly, 1)
+	assert.Equal(t, 13, stats.Monthly[0].Scheduled)
+	assert.Equal(t, 8, stats.Monthly[0].Completed)
+	assert.Equal(t, 8, stats.TotalEntries)
+}
+
+func TestComputeNotStarted(t *testing.T) {
+	habit := newHabit(models.FrequencyDaily, time.Now().AddDate(0, 0, 2))
+
+	stats := Compute(habit, nil, time.Now(), time.UTC)
+
+	assert.Nil(t, stats.CurrentPeriod)
+	assert.Equal(t, 0, stats.CurrentStreak)
+	assert.Empty(t, stats.Weekly)
+}
+
+func TestWeekdayIndex(t *testing.T) {
+	assert.Equal(t, 0, WeekdayIndex(time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 6, WeekdayIndex(time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,354 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/rrule"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	productID = "-//Atomic Blend//Productivity//EN"
+	uidDomain = "atomic-blend"
+
+	utcFormat      = "20060102T150405Z"
+	floatingFormat = "20060102T150405"
+	dateFormat     = "20060102"
+
+	// maxLineLength is the maximum length of a content line in octets, longer lines are folded
+	maxLineLength = 75
+)
+
+var weekdayCodes = []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+// Feed returns the iCalendar document of the dated tasks and the habits of a user.
+//
+// A task with both a start date and an end date is a VEVENT, a task with a single date is a VTODO.
+// The reminders of the tasks are VALARMs relative to the start date, or to the due date when the task
+// has no start date, so that they repeat along with recurring tasks.
+//
+// A habit is a recurring VEVENT following its frequency. Habit reminders are wall clock times of the user,
+// the event is therefore set at the floating time of the first reminder, each reminder being a VALARM.
+// A habit without reminders is an all-day event.
+func Feed(name string, tasks []*models.TaskEntity, habits []*models.Habit, now time.Time) string {
+	w := &writer{}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + productID)
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.line("X-WR-CALNAME:" + escape(name))
+	w.line("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	w.line("X-PUBLISHED-TTL:PT1H")
+
+	for _, task := range tasks {
+		writeTask(w, task, now)
+	}
+	for _, habit := range habits {
+		writeHabit(w, habit, now)
+	}
+
+	w.line("END:VCALENDAR")
+	return w.String()
+}
+
+func writeTask(w *writer, task *models.TaskEntity, now time.Time) {
+	if task == nil || (task.StartDate == nil && task.EndDate == nil) {
+		return
+	}
+
+	component := "VTODO"
+	if task.StartDate != nil && task.EndDate != nil && task.EndDate.Time().After(task.StartDate.Time()) {
+		component = "VEVENT"
+	}
+
+	w.line("BEGIN:" + component)
+	w.line("UID:task-" + task.ID + "@" + uidDomain)
+	w.line("DTSTAMP:" + formatUTC(stamp(task.UpdatedAt, now)))
+	w.line("SUMMARY:" + escape(task.Title))
+	if task.Description != nil && *task.Description != "" {
+		w.line("DESCRIPTION:" + escape(*task.Description))
+	}
+
+	anchor, related := task.StartDate, ""
+	if task.StartDate != nil {
+		w.line("DTSTART:" + formatUTC(task.StartDate.Time()))
+	}
+	if component == "VEVENT" {
+		w.line("DTEND:" + formatUTC(task.EndDate.Time()))
+	} else {
+		if task.EndDate != nil {
+			w.line("DUE:" + formatUTC(task.EndDate.Time()))
+			if anchor == nil {
+				anchor, related = task.EndDate, ";RELATED=END"
+			}
+		}
+		status := "NEEDS-ACTION"
+		if task.Completed != nil && *task.Completed {
+			status = "COMPLETED"
+		}
+		w.line("STATUS:" + status)
+	}
+
+	if task.Recurrence != nil && *task.Recurrence != "" {
+		if rule, err := rrule.Parse(*task.Recurrence); err == nil {
+			w.line("RRULE:" + rule.String())
+			for _, date := range task.ExcludedDates {
+				if date != nil {
+					w.line("EXDATE:" + formatUTC(date.Time()))
+				}
+			}
+		}
+	}
+
+	for _, reminder := range task.Reminders {
+		if reminder == nil {
+			continue
+		}
+		offset := reminder.Time().Sub(anchor.Time())
+		writeAlarm(w, task.Title, "TRIGGER"+related+":"+formatDuration(offset))
+	}
+
+	w.line("END:" + component)
+}
+
+func writeHabit(w *writer, habit *models.Habit, now time.Time) {
+	if habit == nil || habit.StartDate == nil || habit.Frequency == nil {
+		return
+	}
+
+	summary := ""
+	if habit.Name != nil {
+		summary = *habit.Name
+	}
+	if habit.Emoji != nil && *habit.Emoji != "" {
+		summary = *habit.Emoji + " " + summary
+	}
+
+	start := habit.StartDate.Time().UTC()
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	reminders := reminderOffsets(habit.Reminders)
+
+	w.line("BEGIN:VEVENT")
+	w.line("UID:habit-" + habit.ID.Hex() + "@" + uidDomain)
+	w.line("DTSTAMP:" + formatUTC(now))
+	w.line("SUMMARY:" + escape(summary))
+	if habit.Citation != nil && *habit.Citation != "" {
+		w.line("DESCRIPTION:" + escape(*habit.Citation))
+	}
+
+	var until string
+	if len(reminders) == 0 {
+		w.line("DTSTART;VALUE=DATE:" + day.Format(dateFormat))
+		if habit.EndDate != nil {
+			until = habit.EndDate.Time().UTC().Format(dateFormat)
+		}
+	} else {
+		w.line("DTSTART:" + day.Add(reminders[0]).Format(floatingFormat))
+		if habit.EndDate != nil {
+			end := habit.EndDate.Time().UTC()
+			until = time.Date(end.Year(), end.Month(), end.Day(), 23, 59, 59, 0, time.UTC).Format(floatingFormat)
+		}
+	}
+
+	rule := habitRule(habit)
+	if until != "" {
+		rule += ";UNTIL=" + until
+	}
+	w.line("RRULE:" + rule)
+
+	for _, offset := range reminders {
+		writeAlarm(w, summary, "TRIGGER:"+formatDuration(offset-reminders[0]))
+	}
+
+	w.line("END:VEVENT")
+}
+
+func writeAlarm(w *writer, description, trigger string) {
+	w.line("BEGIN:VALARM")
+	w.line("ACTION:DISPLAY")
+	w.line("DESCRIPTION:" + escape(description))
+	w.line(trigger)
+	w.line("END:VALARM")
+}
+
+// habitRule returns the recurrence rule following the frequency of a habit, as scheduled by the habit statistics
+func habitRule(habit *models.Habit) string {
+	days := []string{}
+	if habit.DaysOfWeek != nil {
+		seen := map[int]bool{}
+		sorted := append([]int{}, *habit.DaysOfWeek...)
+		sort.Ints(sorted)
+		for _, day := range sorted {
+			if day >= 0 && day <= 6 && !seen[day] {
+				seen[day] = true
+				days = append(days, weekdayCodes[day])
+			}
+		}
+	}
+
+	switch *habit.Frequency {
+	case models.FrequencyWeekly:
+		if len(days) == 0 {
+			return "FREQ=WEEKLY"
+		}
+		return "FREQ=WEEKLY;BYDAY=" + strings.Join(days, ",")
+	case models.FrequencyMonthly:
+		monthDays := []int{}
+		if habit.DaysOfMonth != nil {
+			seen := map[int]bool{}
+			for _, date := range *habit.DaysOfMonth {
+				if day := date.Time().UTC().Day(); !seen[day] {
+					seen[day] = true
+					monthDays = append(monthDays, day)
+				}
+			}
+		}
+		if len(monthDays) == 0 {
+			return "FREQ=MONTHLY"
+		}
+		sort.Ints(monthDays)
+		values := make([]string, 0, len(monthDays))
+		for _, day := range monthDays {
+			values = append(values, strconv.Itoa(day))
+		}
+		return "FREQ=MONTHLY;BYMONTHDAY=" + strings.Join(values, ",")
+	case models.FrequencyRepeating:
+		if habit.Duration == nil || *habit.Duration <= 0 {
+			return "FREQ=DAILY"
+		}
+		length := time.Duration(*habit.Duration) * time.Millisecond
+		if length >= 24*time.Hour {
+			return interval("DAILY", int((length+12*time.Hour)/(24*time.Hour)))
+		}
+		return interval("HOURLY", max(int((length+30*time.Minute)/time.Hour), 1))
+	default:
+		if len(days) == 0 {
+			return "FREQ=DAILY"
+		}
+		return "FREQ=DAILY;BYDAY=" + strings.Join(days, ",")
+	}
+}
+
+func interval(freq string, n int) string {
+	if n <= 1 {
+		return "FREQ=" + freq
+	}
+	return "FREQ=" + freq + ";INTERVAL=" + strconv.Itoa(n)
+}
+
+// reminderOffsets parses the "15:04" reminders of a habit into offsets from midnight, sorted and without duplicates.
+// Reminders which cannot be parsed are ignored.
+func reminderOffsets(reminders []string) []time.Duration {
+	offsets := []time.Duration{}
+	seen := map[time.Duration]bool{}
+	for _, reminder := range reminders {
+		at, err := time.Parse("15:04", reminder)
+		if err != nil {
+			continue
+		}
+		offset := time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute
+		if !seen[offset] {
+			seen[offset] = true
+			offsets = append(offsets, offset)
+		}
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	return offsets
+}
+
+// formatDuration formats a duration as an iCalendar DURATION value, such as -PT15M or P1DT2H
+func formatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	d = d.Truncate(time.Second)
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+
+	value := sign + "P"
+	if days > 0 {
+		value += fmt.Sprintf("%dD", days)
+		if d == 0 {
+			return value
+		}
+	}
+
+	hours := d / time.Hour
+	minutes := (d % time.Hour) / time.Minute
+	seconds := (d % time.Minute) / time.Second
+	value += "T"
+	if hours > 0 {
+		value += fmt.Sprintf("%dH", hours)
+	}
+	if minutes > 0 {
+		value += fmt.Sprintf("%dM", minutes)
+	}
+	if seconds > 0 || (hours == 0 && minutes == 0) {
+		value += fmt.Sprintf("%dS", seconds)
+	}
+	return value
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format(utcFormat)
+}
+
+// stamp returns the date of the last modification of an item, or now for items which never recorded one
+func stamp(updatedAt primitive.DateTime, now time.Time) time.Time {
+	if updatedAt == 0 {
+		return now
+	}
+	return updatedAt.Time()
+}
+
+// escape escapes a TEXT value
+func escape(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(value)
+}
+
+// writer accumulates content lines, folding them at maxLineLength octets without splitting UTF-8 characters
+type writer struct {
+	builder strings.Builder
+}
+
+func (w *writer) line(content string) {
+	limit := maxLineLength
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(content[cut]) {
+			cut--
+		}
+		w.builder.WriteString(content[:cut])
+		w.builder.WriteString("\r\n ")
+		content = content[cut:]
+		// the leading space of a continuation line counts towards its length
+		limit = maxLineLength - 1
+	}
+	w.builder.WriteString(content)
+	w.builder.WriteString("\r\n")
+}
+
+func (w *writer) String() string {
+	return w.builder.String()
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
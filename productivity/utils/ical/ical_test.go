@@ -0,0 +1,191 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var now = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func dateTime(t time.Time) *primitive.DateTime {
+	d := primitive.NewDateTimeFromTime(t)
+	return &d
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+// component returns the lines of the event or todo of the feed with the given UID
+func component(t *testing.T, feed, uid string) []string {
+	lines := strings.Split(strings.ReplaceAll(feed, "\r\n ", ""), "\r\n")
+	start := -1
+	for i, line := range lines {
+		if line == "BEGIN:VEVENT" || line == "BEGIN:VTODO" {
+			start = i
+		}
+		if line == "UID:"+uid {
+			for j := i; j < len(lines); j++ {
+				if lines[j] == "END:VEVENT" || lines[j] == "END:VTODO" {
+					return lines[start : j+1]
+				}
+			}
+		}
+	}
+	require.Fail(t, "component not found", uid)
+	return nil
+}
+
+func TestFeed(t *testing.T) {
+	t.Run("calendar envelope", func(t *testing.T) {
+		feed := Feed("My tasks", nil, nil, now)
+		assert.True(t, strings.HasPrefix(feed, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+		assert.True(t, strings.HasSuffix(feed, "END:VCALENDAR\r\n"))
+		assert.Contains(t, feed, "X-WR-CALNAME:My tasks\r\n")
+	})
+
+	t.Run("undated tasks are left out", func(t *testing.T) {
+		feed := Feed("", []*models.TaskEntity{{ID: "undated", Title: "Someday"}}, nil, now)
+		assert.NotContains(t, feed, "Someday")
+	})
+
+	t.Run("task with start and end is an event", func(t *testing.T) {
+		start := time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC)
+		task := &models.TaskEntity{
+			ID:          "t1",
+			Title:       "Review, plan; ship",
+			Description: stringPtr("line one\nline two"),
+			StartDate:   dateTime(start),
+			EndDate:     dateTime(start.Add(time.Hour)),
+			Reminders:   []*primitive.DateTime{dateTime(start.Add(-15 * time.Minute))},
+			UpdatedAt:   primitive.NewDateTimeFromTime(now),
+		}
+
+		lines := component(t, Feed("", []*models.TaskEntity{task}, nil, now), "task-t1@atomic-blend")
+		assert.Equal(t, "BEGIN:VEVENT", lines[0])
+		assert.Contains(t, lines, `SUMMARY:Review\, plan\; ship`)
+		assert.Contains(t, lines, `DESCRIPTION:line one\nline two`)
+		assert.Contains(t, lines, "DTSTART:20260312T090000Z")
+		assert.Contains(t, lines, "DTEND:20260312T100000Z")
+		assert.Contains(t, lines, "BEGIN:VALARM")
+		assert.Contains(t, lines, "TRIGGER:-PT15M")
+		assert.NotContains(t, lines, "STATUS:NEEDS-ACTION")
+	})
+
+	t.Run("task with a due date is a todo", func(t *testing.T) {
+		due := time.Date(2026, 3, 15, 17, 0, 0, 0, time.UTC)
+		completed := true
+		recurrence := "RRULE:FREQ=WEEKLY;BYDAY=MO"
+		task := &models.TaskEntity{
+			ID:            "t2",
+			Title:         "Report",
+			EndDate:       dateTime(due),
+			Completed:     &completed,
+			Recurrence:    &recurrence,
+			ExcludedDates: []*primitive.DateTime{dateTime(due.AddDate(0, 0, 7))},
+			Reminders:     []*primitive.DateTime{dateTime(due.Add(-24 * time.Hour))},
+		}
+
+		lines := component(t, Feed("", []*models.TaskEntity{task}, nil, now), "task-t2@atomic-blend")
+		assert.Equal(t, "BEGIN:VTODO", lines[0])
+		assert.Contains(t, lines, "DUE:20260315T170000Z")
+		assert.Contains(t, lines, "STATUS:COMPLETED")
+		assert.Contains(t, lines, "RRULE:FREQ=WEEKLY;BYDAY=MO")
+		assert.Contains(t, lines, "EXDATE:20260322T170000Z")
+		assert.Contains(t, lines, "TRIGGER;RELATED=END:-P1D")
+		assert.Contains(t, lines, "DTSTAMP:20260310T120000Z")
+	})
+
+	t.Run("habit with reminders", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		frequency := models.FrequencyWeekly
+		habit := &models.Habit{
+			ID:         id,
+			Name:       stringPtr("Run"),
+			Emoji:      stringPtr("🏃"),
+			Frequency:  &frequency,
+			DaysOfWeek: &[]int{4, 0, 2, 0},
+			StartDate:  dateTime(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)),
+			EndDate:    dateTime(time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)),
+			Reminders:  []string{"18:30", "07:00", "invalid"},
+		}
+
+		lines := component(t, Feed("", nil, []*models.Habit{habit}, now), "habit-"+id.Hex()+"@atomic-blend")
+		assert.Contains(t, lines, "SUMMARY:🏃 Run")
+		assert.Contains(t, lines, "DTSTART:20260302T070000")
+		assert.Contains(t, lines, "RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR;UNTIL=20260630T235959")
+		assert.Contains(t, lines, "TRIGGER:PT0S")
+		assert.Contains(t, lines, "TRIGGER:PT11H30M")
+	})
+
+	t.Run("habit without reminders is an all-day event", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		frequency := models.FrequencyMonthly
+		habit := &models.Habit{
+			ID:        id,
+			Name:      stringPtr("Budget"),
+			Frequency: &frequency,
+			DaysOfMonth: &[]primitive.DateTime{
+				primitive.NewDateTimeFromTime(time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)),
+				primitive.NewDateTimeFromTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			StartDate: dateTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+		}
+
+		lines := component(t, Feed("", nil, []*models.Habit{habit}, now), "habit-"+id.Hex()+"@atomic-blend")
+		assert.Contains(t, lines, "DTSTART;VALUE=DATE:20260101")
+		assert.Contains(t, lines, "RRULE:FREQ=MONTHLY;BYMONTHDAY=1,15")
+		assert.NotContains(t, lines, "BEGIN:VALARM")
+	})
+}
+
+func TestHabitRule(t *testing.T) {
+	daily := models.FrequencyDaily
+	repeating := models.FrequencyRepeating
+	threeDays := int((72 * time.Hour).Milliseconds())
+	sixHours := int((6 * time.Hour).Milliseconds())
+
+	tests := []struct {
+		name     string
+		habit    *models.Habit
+		expected string
+	}{
+		{"daily", &models.Habit{Frequency: &daily}, "FREQ=DAILY"},
+		{"daily on some days", &models.Habit{Frequency: &daily, DaysOfWeek: &[]int{5, 6}}, "FREQ=DAILY;BYDAY=SA,SU"},
+		{"repeating every few days", &models.Habit{Frequency: &repeating, Duration: &threeDays}, "FREQ=DAILY;INTERVAL=3"},
+		{"repeating every few hours", &models.Habit{Frequency: &repeating, Duration: &sixHours}, "FREQ=HOURLY;INTERVAL=6"},
+		{"repeating without duration", &models.Habit{Frequency: &repeating}, "FREQ=DAILY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, habitRule(tt.habit))
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "PT0S", formatDuration(0))
+	assert.Equal(t, "-PT15M", formatDuration(-15*time.Minute))
+	assert.Equal(t, "P1D", formatDuration(24*time.Hour))
+	assert.Equal(t, "P1DT2H30M", formatDuration(26*time.Hour+30*time.Minute))
+	assert.Equal(t, "PT45S", formatDuration(45*time.Second))
+}
+
+func TestLineFolding(t *testing.T) {
+	w := &writer{}
+	w.line("DESCRIPTION:" + strings.Repeat("é", 100))
+
+	for _, line := range strings.Split(strings.TrimSuffix(w.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineLength)
+		assert.True(t, strings.ToValidUTF8(line, "?") == line)
+	}
+	assert.Equal(t, "DESCRIPTION:"+strings.Repeat("é", 100)+"\r\n", strings.ReplaceAll(w.String(), "\r\n ", ""))
+}
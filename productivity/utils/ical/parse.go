@@ -0,0 +1,302 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Component is a component of an iCalendar document, such as a VCALENDAR, a VTODO or a VALARM
+type Component struct {
+	Name string
+	// Line is the line of the document the component begins at
+	Line       int
+	Properties []*Property
+	Components []*Component
+}
+
+// Property is a content line of a component
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// Parse reads the components of an iCalendar document, property and component names are upper-cased
+func Parse(r io.Reader) ([]*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []*Component
+	var stack []*Component
+	for _, l := range lines {
+		if strings.TrimSpace(l.content) == "" {
+			continue
+		}
+		property, err := parseProperty(l.content)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", l.number, err)
+		}
+
+		switch property.Name {
+		case "BEGIN":
+			component := &Component{Name: strings.ToUpper(property.Value), Line: l.number}
+			if len(stack) == 0 {
+				roots = append(roots, component)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, component)
+			}
+			stack = append(stack, component)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(property.Value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", l.number, property.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: property %s outside of a component", l.number, property.Name)
+			}
+			current := stack[len(stack)-1]
+			current.Properties = append(current.Properties, property)
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("line %d: %s is not closed", stack[len(stack)-1].Line, stack[len(stack)-1].Name)
+	}
+	if len(roots) == 0 || roots[0].Name != "VCALENDAR" {
+		return nil, errors.New("not an iCalendar document")
+	}
+	return roots, nil
+}
+
+// Property returns the first property of the component with the given name, nil when there is none
+func (c *Component) Property(name string) *Property {
+	for _, property := range c.Properties {
+		if property.Name == name {
+			return property
+		}
+	}
+	return nil
+}
+
+// All returns the properties of the component with the given name
+func (c *Component) All(name string) []*Property {
+	properties := []*Property{}
+	for _, property := range c.Properties {
+		if property.Name == name {
+			properties = append(properties, property)
+		}
+	}
+	return properties
+}
+
+// Children returns the subcomponents of the component with the given name
+func (c *Component) Children(name string) []*Component {
+	components := []*Component{}
+	for _, component := range c.Components {
+		if component.Name == name {
+			components = append(components, component)
+		}
+	}
+	return components
+}
+
+// Text returns the unescaped value of a TEXT property
+func (p *Property) Text() string {
+	return unescape(p.Value)
+}
+
+// Texts returns the unescaped values of a TEXT property holding a comma separated list, such as CATEGORIES
+func (p *Property) Texts() []string {
+	values := []string{}
+	start := 0
+	for i := 0; i < len(p.Value); i++ {
+		switch p.Value[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, unescape(p.Value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, unescape(p.Value[start:]))
+}
+
+// Time returns the value of a DATE or DATE-TIME property. Floating times are read in the time zone of the
+// TZID parameter, or in UTC when the property has none or the time zone is unknown.
+func (p *Property) Time() (time.Time, error) {
+	times, err := p.Times()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(times) != 1 {
+		return time.Time{}, fmt.Errorf("%s must hold a single date", p.Name)
+	}
+	return times[0], nil
+}
+
+// Times returns the values of a DATE or DATE-TIME property holding a comma separated list, such as EXDATE
+func (p *Property) Times() ([]time.Time, error) {
+	loc := time.UTC
+	if tzid := p.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			loc = l
+		}
+	}
+
+	times := []time.Time{}
+	for _, value := range strings.Split(p.Value, ",") {
+		value = strings.TrimSpace(value)
+		var t time.Time
+		var err error
+		switch {
+		case len(value) == len(dateFormat):
+			t, err = time.ParseInLocation(dateFormat, value, time.UTC)
+		case strings.HasSuffix(value, "Z"):
+			t, err = time.Parse(utcFormat, value)
+		default:
+			t, err = time.ParseInLocation(floatingFormat, value, loc)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s date %q", p.Name, value)
+		}
+		times = append(times, t.UTC())
+	}
+	return times, nil
+}
+
+// ParseDuration parses an iCalendar DURATION value, such as -PT15M or P1DT2H
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	match := durationPattern.FindStringSubmatch(value)
+	if match == nil || strings.Join(match[2:], "") == "" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var d time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if match[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		d += time.Duration(n) * unit
+	}
+	if match[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+type contentLine struct {
+	number  int
+	content string
+}
+
+// unfold reads the content lines of a document, joining folded lines
+func unfold(r io.Reader) ([]contentLine, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	lines := []contentLine{}
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if number == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if len(lines) > 0 && (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) {
+			lines[len(lines)-1].content += text[1:]
+			continue
+		}
+		lines = append(lines, contentLine{number: number, content: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// parseProperty parses a content line, NAME;PARAM=VALUE;PARAM="QUOTED VALUE":VALUE
+func parseProperty(line string) (*Property, error) {
+	colon := -1
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		if line[i] == '"' {
+			quoted = !quoted
+		} else if line[i] == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return nil, fmt.Errorf("invalid content line %q", line)
+	}
+
+	parts := splitParams(line[:colon])
+	property := &Property{
+		Name:   strings.ToUpper(parts[0]),
+		Params: map[string]string{},
+		Value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		name, value, found := strings.Cut(param, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid parameter %q", param)
+		}
+		property.Params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	return property, nil
+}
+
+func splitParams(value string) []string {
+	parts := []string{}
+	start := 0
+	quoted := false
+	for i := 0; i < len(value); i++ {
+		if value[i] == '"' {
+			quoted = !quoted
+		} else if value[i] == ';' && !quoted {
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// unescape unescapes a TEXT value
+func unescape(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			builder.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			builder.WriteByte('\n')
+		default:
+			builder.WriteByte(value[i])
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,90 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("components and folded lines", func(t *testing.T) {
+		document := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\nSUMMARY:Long\r\n  title\r\nDTSTART;TZID=\"Europe/Paris\":20260312T090000\r\nBEGIN:VALARM\r\nTRIGGER:-PT10M\r\nEND:VALARM\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+		calendars, err := Parse(strings.NewReader(document))
+		require.NoError(t, err)
+		require.Len(t, calendars, 1)
+
+		todos := calendars[0].Children("VTODO")
+		require.Len(t, todos, 1)
+		assert.Equal(t, 3, todos[0].Line)
+		assert.Equal(t, "Long title", todos[0].Property("SUMMARY").Text())
+
+		start, err := todos[0].Property("DTSTART").Time()
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 3, 12, 8, 0, 0, 0, time.UTC), start)
+		assert.Len(t, todos[0].Children("VALARM"), 1)
+	})
+
+	t.Run("feed written by Feed can be read back", func(t *testing.T) {
+		calendars, err := Parse(strings.NewReader(Feed("Tasks", nil, nil, now)))
+		require.NoError(t, err)
+		assert.Equal(t, "Tasks", calendars[0].Property("X-WR-CALNAME").Text())
+	})
+
+	t.Run("unclosed component", func(t *testing.T) {
+		_, err := Parse(strings.NewReader("BEGIN:VCALENDAR\nBEGIN:VTODO\nEND:VCALENDAR\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("not a calendar", func(t *testing.T) {
+		_, err := Parse(strings.NewReader("title,due\nReport,2026-03-12\n"))
+		assert.Error(t, err)
+	})
+}
+
+func TestPropertyValues(t *testing.T) {
+	property, err := parseProperty(`CATEGORIES;LANGUAGE=en:Work,Home\, garden,a\;b`)
+	require.NoError(t, err)
+	assert.Equal(t, "en", property.Params["LANGUAGE"])
+	assert.Equal(t, []string{"Work", "Home, garden", "a;b"}, property.Texts())
+
+	property, err = parseProperty(`EXDATE:20260312T090000Z,20260319`)
+	require.NoError(t, err)
+	dates, err := property.Times()
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 19, 0, 0, 0, 0, time.UTC),
+	}, dates)
+
+	assert.Equal(t, "line one\nline two, done", unescape(`line one\nline two\, done`))
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"PT0S":      0,
+		"-PT15M":    -15 * time.Minute,
+		"P1D":       24 * time.Hour,
+		"+P1DT2H":   26 * time.Hour,
+		"P2W":       14 * 24 * time.Hour,
+		"PT1H30M5S": time.Hour + 30*time.Minute + 5*time.Second,
+	}
+	for value, expected := range tests {
+		d, err := ParseDuration(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, d, value)
+		if expected > 0 && expected%(7*24*time.Hour) != 0 {
+			back, err := ParseDuration(formatDuration(expected))
+			require.NoError(t, err)
+			assert.Equal(t, expected, back)
+		}
+	}
+
+	for _, value := range []string{"", "P", "PT", "15M", "P1H"} {
+		_, err := ParseDuration(value)
+		assert.Error(t, err, value)
+	}
+}
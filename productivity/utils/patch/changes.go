@@ -0,0 +1,46 @@
+package patch
+
+import (
+	"encoding/json"
+
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// immutableKeys are never changed by a patch: the identity, the owner and the timestamps of an
+// item are managed by the server
+var immutableKeys = []string{"id", "user", "userId", "createdAt", "updatedAt"}
+
+// ApplyChanges sets the changes of a patch on the entity and validates the result.
+// Keys are the JSON field names of the entity.
+func ApplyChanges(object interface{}, changes []patchmodels.PatchChange) error {
+	jsonStr, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(jsonStr, &fields); err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		if containString(immutableKeys, change.Key) {
+			continue
+		}
+		fields[change.Key] = change.Value
+	}
+
+	jsonStr, err = json.Marshal(fields)
+	if err != nil {
+		return ErrInvalidData
+	}
+	if err := json.Unmarshal(jsonStr, object); err != nil {
+		return ErrInvalidData
+	}
+	if err := binding.Validator.ValidateStruct(object); err != nil {
+		return ErrInvalidData
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestApplyChanges(t *testing.T) {
+	t.Run("sets and clears fields", func(t *testing.T) {
+		color := "#ff0000"
+		folder := &models.Folder{Name: "Work", Color: &color}
+
+		err := ApplyChanges(folder, []patchmodels.PatchChange{
+			{Key: "name", Value: "Home"},
+			{Key: "color", Value: nil},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Home", folder.Name)
+		assert.Nil(t, folder.Color)
+	})
+
+	t.Run("ignores immutable fields", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		userID := primitive.NewObjectID()
+		folder := &models.Folder{ID: &id, UserID: userID, Name: "Work"}
+
+		err := ApplyChanges(folder, []patchmodels.PatchChange{
+			{Key: "id", Value: primitive.NewObjectID().Hex()},
+			{Key: "userId", Value: primitive.NewObjectID().Hex()},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, id, *folder.ID)
+		assert.Equal(t, userID, folder.UserID)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		folder := &models.Folder{Name: "Work"}
+
+		err := ApplyChanges(folder, []patchmodels.PatchChange{{Key: "parentId", Value: "not an id"}})
+
+		assert.ErrorIs(t, err, ErrInvalidData)
+	})
+
+	t.Run("result must be valid", func(t *testing.T) {
+		folder := &models.Folder{Name: "Work"}
+
+		err := ApplyChanges(folder, []patchmodels.PatchChange{{Key: "name", Value: ""}})
+
+		assert.ErrorIs(t, err, ErrInvalidData)
+	})
+}
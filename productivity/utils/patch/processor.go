@@ -0,0 +1,231 @@
+package patch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	patchmodels "github.com/atomic-blend/backend/productivity/models/patch_models"
+
+	"github.com/gin-gonic/gin/binding"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidData is returned when the data carried by a patch cannot be decoded or is not valid
+var ErrInvalidData = errors.New("invalid item data")
+
+// Error lets an adapter report a specific error code for a patch
+type Error struct {
+	Code string
+}
+
+func (e *Error) Error() string {
+	return e.Code
+}
+
+// NewError creates an error reported to the client with the given error code
+func NewError(code string) error {
+	return &Error{Code: code}
+}
+
+// Item is an item loaded by an adapter
+type Item struct {
+	ID primitive.ObjectID
+	// Object is the entity itself, it is sent back to the client on conflicts
+	Object    interface{}
+	Owner     primitive.ObjectID
+	UpdatedAt time.Time
+	// FieldUpdatedAt returns when a field was last modified, it is nil for items without per-field tracking
+	FieldUpdatedAt func(key string) time.Time
+}
+
+// Adapter gives the processor access to one type of item
+type Adapter interface {
+	// Get loads an item, it returns a nil item when the item does not exist
+	Get(ctx context.Context, id primitive.ObjectID) (*Item, error)
+	// New returns an empty entity the data of a create patch is decoded into
+	New() interface{}
+	// Create stores a new entity owned by the user
+	Create(ctx context.Context, userID primitive.ObjectID, object interface{}) error
+	// Update applies the changes of the patch to the item
+	Update(ctx context.Context, patch *patchmodels.Patch, item *Item) error
+	// Delete removes the item
+	Delete(ctx context.Context, item *Item) error
+}
+
+// Processor applies patches through the adapter registered for their item type
+type Processor struct {
+	adapters map[string]Adapter
+}
+
+// NewProcessor creates a processor without any adapter
+func NewProcessor() *Processor {
+	return &Processor{
+		adapters: make(map[string]Adapter),
+	}
+}
+
+// Register sets the adapter used for the given item type
+func (p *Processor) Register(itemType string, adapter Adapter) {
+	p.adapters[itemType] = adapter
+}
+
+// Apply applies the patches in order on behalf of the user. Patches never fail as a whole:
+// each one ends up either in the success list, the error list or the conflict list.
+func (p *Processor) Apply(ctx context.Context, userID primitive.ObjectID, patches []patchmodels.Patch) *patchmodels.PatchResponse {
+	response := &patchmodels.PatchResponse{
+		Success:   make([]string, 0),
+		Errors:    make([]patchmodels.PatchError, 0),
+		Conflicts: make([]patchmodels.ConflictedItem, 0),
+	}
+
+	for i := range patches {
+		patch := &patches[i]
+		conflict, err := p.apply(ctx, userID, patch)
+		switch {
+		case err != nil:
+			response.Errors = append(response.Errors, patchmodels.PatchError{PatchID: patch.ID.Hex(), ErrorCode: errorCode(patch, err)})
+		case conflict != nil:
+			response.Conflicts = append(response.Conflicts, *conflict)
+		default:
+			response.Success = append(response.Success, patch.ID.Hex())
+		}
+	}
+
+	response.Date = time.Now()
+	return response
+}
+
+func (p *Processor) apply(ctx context.Context, userID primitive.ObjectID, patch *patchmodels.Patch) (*patchmodels.ConflictedItem, error) {
+	adapter, ok := p.adapters[patch.ItemType]
+	if !ok || !containString(patchmodels.ValidItemTypes, patch.ItemType) {
+		return nil, NewError("item_type_not_supported")
+	}
+
+	if !containString(patchmodels.ValidPatchActions, patch.Action) {
+		return nil, NewError("invalid_action")
+	}
+
+	if patch.Action == patchmodels.PatchActionCreate {
+		if len(patch.Changes) == 0 {
+			return nil, ErrInvalidData
+		}
+		// the content of the item is under the value of the first change
+		object := adapter.New()
+		if err := Decode(patch.Changes[0].Value, object); err != nil {
+			return nil, err
+		}
+		return nil, adapter.Create(ctx, userID, object)
+	}
+
+	if patch.ItemID == nil {
+		return nil, NewError("item_id_required")
+	}
+
+	item, err := adapter.Get(ctx, *patch.ItemID)
+	if err != nil || item == nil {
+		return nil, NewError(patch.ItemType + "_not_found")
+	}
+
+	if item.Owner != userID {
+		return nil, NewError("not_authorized")
+	}
+
+	// the patch must be dated after the last update of the item unless it is forced
+	forced := patch.Force != nil && *patch.Force
+	if !forced && patch.PatchDate != nil && patch.PatchDate.Time().Before(item.UpdatedAt) {
+		if patch.Action == patchmodels.PatchActionDelete || item.FieldUpdatedAt == nil {
+			return &patchmodels.ConflictedItem{Type: patch.ItemType, PatchID: patch.ID.Hex(), RemoteObject: item.Object}, nil
+		}
+		return p.merge(ctx, adapter, patch, item)
+	}
+
+	if patch.Action == patchmodels.PatchActionDelete {
+		return nil, adapter.Delete(ctx, item)
+	}
+	return nil, adapter.Update(ctx, patch, item)
+}
+
+// merge applies the changes of an outdated patch that do not collide with a remote modification
+// of the same field. Colliding keys are reported as a conflict holding the merged remote object.
+func (p *Processor) merge(ctx context.Context, adapter Adapter, patch *patchmodels.Patch, item *Item) (*patchmodels.ConflictedItem, error) {
+	merged := make([]patchmodels.PatchChange, 0, len(patch.Changes))
+	conflictingKeys := make([]string, 0)
+	for _, change := range patch.Changes {
+		if patch.PatchDate.Time().Before(item.FieldUpdatedAt(change.Key)) {
+			conflictingKeys = append(conflictingKeys, change.Key)
+		} else {
+			merged = append(merged, change)
+		}
+	}
+
+	if len(conflictingKeys) == 0 {
+		return nil, adapter.Update(ctx, patch, item)
+	}
+
+	remote := item
+	if len(merged) > 0 {
+		partial := *patch
+		partial.Changes = merged
+		if err := adapter.Update(ctx, &partial, item); err != nil {
+			return nil, err
+		}
+		if updated, err := adapter.Get(ctx, item.ID); err == nil && updated != nil {
+			remote = updated
+		}
+	}
+
+	return &patchmodels.ConflictedItem{
+		Type:            patch.ItemType,
+		PatchID:         patch.ID.Hex(),
+		RemoteObject:    remote.Object,
+		ConflictingKeys: conflictingKeys,
+	}, nil
+}
+
+// Decode decodes the data of a patch into the entity and validates it
+func Decode(value interface{}, object interface{}) error {
+	jsonStr, err := json.Marshal(value)
+	if err != nil {
+		return ErrInvalidData
+	}
+	if err := json.Unmarshal(jsonStr, object); err != nil {
+		return ErrInvalidData
+	}
+	if err := binding.Validator.ValidateStruct(object); err != nil {
+		return ErrInvalidData
+	}
+	return nil
+}
+
+// ParseTime parses an RFC3339 timestamp as stored by the repositories using string dates.
+// The zero time is returned when the value is empty or invalid.
+func ParseTime(value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
+
+// errorCode returns the code reported to the client for an error raised while applying a patch
+func errorCode(patch *patchmodels.Patch, err error) string {
+	var patchErr *Error
+	if errors.As(err, &patchErr) {
+		return patchErr.Code
+	}
+	if errors.Is(err, ErrInvalidData) {
+		return "invalid_" + patch.ItemType + "_data"
+	}
+	return patch.Action + "_failed"
+}
+
+func containString(slice []string, item string) bool {
+	for _, v := range slice {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package pomodoro
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrStopped is returned when changing a session that was already stopped
+var ErrStopped = errors.New("pomodoro session is already stopped")
+
+// Transition is the end of a phase of a session, and the time entry tracking it
+type Transition struct {
+	From  string
+	To    string
+	At    time.Time
+	Entry *models.TimeEntry
+}
+
+// ApplyDefaults replaces the settings which are not set with the defaults
+func ApplyDefaults(settings *models.PomodoroSettings) {
+	if settings.WorkMinutes <= 0 {
+		settings.WorkMinutes = models.DefaultPomodoroWorkMinutes
+	}
+	if settings.ShortBreakMinutes <= 0 {
+		settings.ShortBreakMinutes = models.DefaultPomodoroShortBreakMinutes
+	}
+	if settings.LongBreakMinutes <= 0 {
+		settings.LongBreakMinutes = models.DefaultPomodoroLongBreakMinutes
+	}
+	if settings.CyclesBeforeLongBreak <= 0 {
+		settings.CyclesBeforeLongBreak = models.DefaultPomodoroCyclesBeforeLongBreak
+	}
+}
+
+// Start returns a new session for the user, starting with a work phase at now
+func Start(userID primitive.ObjectID, request *models.PomodoroStartRequest, now time.Time) *models.PomodoroSession {
+	session := &models.PomodoroSession{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Status:    models.PomodoroStatusActive,
+		CreatedAt: primitive.NewDateTimeFromTime(now),
+		UpdatedAt: primitive.NewDateTimeFromTime(now),
+	}
+	if request != nil {
+		session.TaskID = request.TaskID
+		session.PomodoroSettings = request.PomodoroSettings
+	}
+	ApplyDefaults(&session.PomodoroSettings)
+	startPhase(session, models.PomodoroPhaseWork, now)
+	return session
+}
+
+// Advance moves the session through the phases which ended before now, each following phase starting
+// when the previous one ended. It returns the transitions, in order.
+func Advance(session *models.PomodoroSession, now time.Time) []Transition {
+	if session.Status != models.PomodoroStatusActive {
+		return nil
+	}
+
+	transitions := []Transition{}
+	for !session.PhaseEndsAt.Time().After(now) {
+		transitions = append(transitions, endPhase(session, session.PhaseEndsAt.Time()))
+	}
+	return transitions
+}
+
+// CatchUp brings the session up to date at now like Advance, forgotten sessions being stopped like the timers:
+// a session which ran for longer than limit is stopped at CreatedAt + limit. It returns the transitions, the
+// time entries to save with the session, and whether the session was stopped.
+func CatchUp(session *models.PomodoroSession, now time.Time, limit time.Duration) ([]Transition, []*models.TimeEntry, bool) {
+	deadline := session.CreatedAt.Time().Add(limit)
+	if session.Status != models.PomodoroStatusActive || now.Before(deadline) {
+		transitions := Advance(session, now)
+		return transitions, Entries(transitions), false
+	}
+
+	transitions := Advance(session, deadline)
+	entries := Entries(transitions)
+	// the session is active, it can be stopped
+	if last, _ := Stop(session, deadline); last != nil {
+		entries = append(entries, last)
+	}
+	return transitions, entries, true
+}
+
+// Skip ends the current phase at now and starts the next one
+func Skip(session *models.PomodoroSession, now time.Time) (Transition, error) {
+	if session.Status != models.PomodoroStatusActive {
+		return Transition{}, ErrStopped
+	}
+	return endPhase(session, now), nil
+}
+
+// Stop stops the session at now. It returns the time entry of the current phase, nil when it had not started.
+func Stop(session *models.PomodoroSession, now time.Time) (*models.TimeEntry, error) {
+	if session.Status != models.PomodoroStatusActive {
+		return nil, ErrStopped
+	}
+
+	entry := phaseEntry(session, session.PhaseStartedAt.Time(), now)
+	stoppedAt := primitive.NewDateTimeFromTime(now)
+	session.Status = models.PomodoroStatusStopped
+	session.StoppedAt = &stoppedAt
+	session.PhaseEndsAt = stoppedAt
+	session.UpdatedAt = stoppedAt
+	return entry, nil
+}
+
+// NextPhase returns the phase following the current phase of the session. A work phase is followed by
+// a long break every CyclesBeforeLongBreak cycles, by a short break otherwise.
+func NextPhase(session *models.PomodoroSession) string {
+	if session.Phase != models.PomodoroPhaseWork {
+		return models.PomodoroPhaseWork
+	}
+	if (session.Cycle+1)%session.CyclesBeforeLongBreak == 0 {
+		return models.PomodoroPhaseLongBreak
+	}
+	return models.PomodoroPhaseShortBreak
+}
+
+// PhaseDuration returns the duration of a phase with the settings of the session
+func PhaseDuration(settings models.PomodoroSettings, phase string) time.Duration {
+	switch phase {
+	case models.PomodoroPhaseShortBreak:
+		return time.Duration(settings.ShortBreakMinutes) * time.Minute
+	case models.PomodoroPhaseLongBreak:
+		return time.Duration(settings.LongBreakMinutes) * time.Minute
+	default:
+		return time.Duration(settings.WorkMinutes) * time.Minute
+	}
+}
+
+// endPhase ends the current phase at the given time and starts the next one
+func endPhase(session *models.PomodoroSession, at time.Time) Transition {
+	transition := Transition{
+		From:  session.Phase,
+		To:    NextPhase(session),
+		At:    at,
+		Entry: phaseEntry(session, session.PhaseStartedAt.Time(), at),
+	}
+	if session.Phase == models.PomodoroPhaseWork {
+		session.Cycle++
+	}
+	startPhase(session, transition.To, at)
+	return transition
+}
+
+func startPhase(session *models.PomodoroSession, phase string, at time.Time) {
+	session.Phase = phase
+	session.PhaseStartedAt = primitive.NewDateTimeFromTime(at)
+	session.PhaseEndsAt = primitive.NewDateTimeFromTime(at.Add(PhaseDuration(session.PomodoroSettings, phase)))
+	session.UpdatedAt = primitive.NewDateTimeFromTime(at)
+}
+
+// phaseEntry returns the time entry of the current phase of the session between start and end,
+// nil when the phase lasted less than a second
+func phaseEntry(session *models.PomodoroSession, start, end time.Time) *models.TimeEntry {
+	duration := end.Sub(start).Truncate(time.Second)
+	if duration <= 0 {
+		return nil
+	}
+
+	userID := session.UserID
+	seconds := strconv.FormatInt(int64(duration/time.Second), 10)
+	isPomodoro := true
+	isBreak := session.Phase != models.PomodoroPhaseWork
+	isTimer := false
+	return &models.TimeEntry{
+		User:      &userID,
+		TaskID:    session.TaskID,
+		StartDate: start.UTC().Format(time.RFC3339),
+		EndDate:   end.UTC().Format(time.RFC3339),
+		Duration:  &seconds,
+		Timer:     &isTimer,
+		Pomodoro:  &isPomodoro,
+		PomoBreak: &isBreak,
+	}
+}
@@ -0,0 +1,151 @@
+package pomodoro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestStart(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	taskID := primitive.NewObjectID()
+
+	session := Start(primitive.NewObjectID(), &models.PomodoroStartRequest{
+		TaskID:           &taskID,
+		PomodoroSettings: models.PomodoroSettings{WorkMinutes: 50},
+	}, now)
+
+	assert.Equal(t, models.PomodoroStatusActive, session.Status)
+	assert.Equal(t, models.PomodoroPhaseWork, session.Phase)
+	assert.Equal(t, 50, session.WorkMinutes)
+	assert.Equal(t, models.DefaultPomodoroShortBreakMinutes, session.ShortBreakMinutes)
+	assert.Equal(t, models.DefaultPomodoroLongBreakMinutes, session.LongBreakMinutes)
+	assert.Equal(t, models.DefaultPomodoroCyclesBeforeLongBreak, session.CyclesBeforeLongBreak)
+	assert.Equal(t, now.Add(50*time.Minute), session.PhaseEndsAt.Time().UTC())
+}
+
+func TestAdvance(t *testing.T) {
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	settings := models.PomodoroSettings{WorkMinutes: 25, ShortBreakMinutes: 5, LongBreakMinutes: 15, CyclesBeforeLongBreak: 2}
+
+	tests := []struct {
+		name   string
+		after  time.Duration
+		phases []string
+		phase  string
+		cycle  int
+		ends   time.Duration
+	}{
+		{name: "phase not ended", after: 10 * time.Minute, phases: []string{}, phase: models.PomodoroPhaseWork, cycle: 0, ends: 25 * time.Minute},
+		{name: "first break", after: 25 * time.Minute, phases: []string{models.PomodoroPhaseShortBreak}, phase: models.PomodoroPhaseShortBreak, cycle: 1, ends: 30 * time.Minute},
+		{
+			name:   "long break after the cycles",
+			after:  61 * time.Minute,
+			phases: []string{models.PomodoroPhaseShortBreak, models.PomodoroPhaseWork, models.PomodoroPhaseLongBreak},
+			phase:  models.PomodoroPhaseLongBreak,
+			cycle:  2,
+			ends:   70 * time.Minute,
+		},
+		{
+			name:   "back to work after the long break",
+			after:  70 * time.Minute,
+			phases: []string{models.PomodoroPhaseShortBreak, models.PomodoroPhaseWork, models.PomodoroPhaseLongBreak, models.PomodoroPhaseWork},
+			phase:  models.PomodoroPhaseWork,
+			cycle:  2,
+			ends:   95 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := Start(primitive.NewObjectID(), &models.PomodoroStartRequest{PomodoroSettings: settings}, start)
+
+			transitions := Advance(session, start.Add(tt.after))
+
+			phases := []string{}
+			for _, transition := range transitions {
+				phases = append(phases, transition.To)
+			}
+			assert.Equal(t, tt.phases, phases)
+			assert.Equal(t, tt.phase, session.Phase)
+			assert.Equal(t, tt.cycle, session.Cycle)
+			assert.Equal(t, start.Add(tt.ends), session.PhaseEndsAt.Time().UTC())
+		})
+	}
+
+	t.Run("time entries of the ended phases", func(t *testing.T) {
+		taskID := primitive.NewObjectID()
+		session := Start(primitive.NewObjectID(), &models.PomodoroStartRequest{TaskID: &taskID, PomodoroSettings: settings}, start)
+
+		entries := Entries(Advance(session, start.Add(31*time.Minute)))
+
+		require.Len(t, entries, 2)
+		assert.Equal(t, "2025-06-02T09:00:00Z", entries[0].StartDate)
+		assert.Equal(t, "2025-06-02T09:25:00Z", entries[0].EndDate)
+		assert.Equal(t, "1500", *entries[0].Duration)
+		assert.True(t, *entries[0].Pomodoro)
+		assert.False(t, *entries[0].PomoBreak)
+		assert.Equal(t, &taskID, entries[0].TaskID)
+		assert.Equal(t, "300", *entries[1].Duration)
+		assert.True(t, *entries[1].PomoBreak)
+	})
+}
+
+func TestSkipAndStop(t *testing.T) {
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	session := Start(primitive.NewObjectID(), nil, start)
+
+	transition, err := Skip(session, start.Add(10*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, models.PomodoroPhaseWork, transition.From)
+	assert.Equal(t, models.PomodoroPhaseShortBreak, transition.To)
+	assert.Equal(t, "600", *transition.Entry.Duration)
+	assert.Equal(t, 1, session.Cycle)
+	assert.Equal(t, start.Add(15*time.Minute), session.PhaseEndsAt.Time().UTC())
+
+	entry, err := Stop(session, start.Add(12*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, models.PomodoroStatusStopped, session.Status)
+	assert.NotNil(t, session.StoppedAt)
+	assert.Equal(t, "120", *entry.Duration)
+	assert.True(t, *entry.PomoBreak)
+
+	_, err = Stop(session, start.Add(13*time.Minute))
+	assert.ErrorIs(t, err, ErrStopped)
+	_, err = Skip(session, start.Add(13*time.Minute))
+	assert.ErrorIs(t, err, ErrStopped)
+	assert.Empty(t, Advance(session, start.Add(time.Hour)))
+}
+
+func TestCatchUp(t *testing.T) {
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	t.Run("advances a session within the limit", func(t *testing.T) {
+		session := Start(primitive.NewObjectID(), nil, start)
+
+		transitions, entries, stopped := CatchUp(session, start.Add(26*time.Minute), time.Hour)
+
+		assert.False(t, stopped)
+		assert.Len(t, transitions, 1)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, models.PomodoroStatusActive, session.Status)
+	})
+
+	t.Run("stops a session at the limit", func(t *testing.T) {
+		session := Start(primitive.NewObjectID(), nil, start)
+
+		_, entries, stopped := CatchUp(session, start.Add(5*time.Hour), time.Hour)
+
+		assert.True(t, stopped)
+		assert.Equal(t, models.PomodoroStatusStopped, session.Status)
+		assert.Equal(t, start.Add(time.Hour), session.StoppedAt.Time().UTC())
+		// work, short break, work and short break phases, the last one ending at the limit
+		require.Len(t, entries, 4)
+		assert.Equal(t, "2025-06-02T10:00:00Z", entries[3].EndDate)
+	})
+}
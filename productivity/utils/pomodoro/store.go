@@ -0,0 +1,59 @@
+package pomodoro
+
+import (
+	"context"
+	"errors"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Save creates the time entries of the phases that ended, then stores the session changed since it was read with
+// phaseStartedAt as the start of its phase. The created entries are deleted again when the session cannot be stored,
+// e.g. when it was changed by someone else in the meantime, the error is then mongo.ErrNoDocuments.
+func Save(ctx context.Context, sessionRepo repositories.PomodoroRepositoryInterface, timeEntryRepo repositories.TimeEntryRepositoryInterface, session *models.PomodoroSession, phaseStartedAt primitive.DateTime, entries []*models.TimeEntry) error {
+	created := make([]*models.TimeEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry == nil {
+			continue
+		}
+		// the entry is given its ID first, since an entry which failed to be created may still have been inserted
+		if entry.ID == nil {
+			id := primitive.NewObjectID()
+			entry.ID = &id
+		}
+		created = append(created, entry)
+		if _, err := timeEntryRepo.Create(ctx, entry); err != nil {
+			return errors.Join(err, deleteEntries(ctx, timeEntryRepo, created))
+		}
+	}
+
+	if err := sessionRepo.Save(ctx, session, phaseStartedAt); err != nil {
+		return errors.Join(err, deleteEntries(ctx, timeEntryRepo, created))
+	}
+	return nil
+}
+
+// deleteEntries deletes the time entries created for a session which could not be stored
+func deleteEntries(ctx context.Context, timeEntryRepo repositories.TimeEntryRepositoryInterface, entries []*models.TimeEntry) error {
+	var errs []error
+	for _, entry := range entries {
+		if err := timeEntryRepo.Delete(ctx, entry.ID.Hex()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Entries returns the time entries of the transitions
+func Entries(transitions []Transition) []*models.TimeEntry {
+	entries := make([]*models.TimeEntry, 0, len(transitions))
+	for _, transition := range transitions {
+		if transition.Entry != nil {
+			entries = append(entries, transition.Entry)
+		}
+	}
+	return entries
+}
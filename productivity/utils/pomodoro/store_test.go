@@ -0,0 +1,69 @@
+package pomodoro
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestSave(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	t.Run("creates the entries and stores the session", func(t *testing.T) {
+		sessionRepo := new(mocks.MockPomodoroRepository)
+		timeEntryRepo := new(mocks.MockTimeEntryRepository)
+		session := Start(primitive.NewObjectID(), nil, start)
+		entries := []*models.TimeEntry{{}, nil, {}}
+		timeEntryRepo.On("Create", ctx, mock.Anything).Return(&models.TimeEntry{}, nil)
+		sessionRepo.On("Save", ctx, session, session.PhaseStartedAt).Return(nil)
+
+		err := Save(ctx, sessionRepo, timeEntryRepo, session, session.PhaseStartedAt, entries)
+
+		assert.NoError(t, err)
+		timeEntryRepo.AssertNumberOfCalls(t, "Create", 2)
+		timeEntryRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		sessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("deletes the entries when an entry cannot be created", func(t *testing.T) {
+		sessionRepo := new(mocks.MockPomodoroRepository)
+		timeEntryRepo := new(mocks.MockTimeEntryRepository)
+		session := Start(primitive.NewObjectID(), nil, start)
+		first, second := &models.TimeEntry{}, &models.TimeEntry{}
+		timeEntryRepo.On("Create", ctx, first).Return(first, nil)
+		timeEntryRepo.On("Create", ctx, second).Return(nil, errors.New("database error"))
+		timeEntryRepo.On("Delete", ctx, mock.Anything).Return(nil)
+
+		err := Save(ctx, sessionRepo, timeEntryRepo, session, session.PhaseStartedAt, []*models.TimeEntry{first, second})
+
+		assert.Error(t, err)
+		timeEntryRepo.AssertCalled(t, "Delete", ctx, first.ID.Hex())
+		timeEntryRepo.AssertCalled(t, "Delete", ctx, second.ID.Hex())
+		sessionRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("deletes the entries when the session was changed", func(t *testing.T) {
+		sessionRepo := new(mocks.MockPomodoroRepository)
+		timeEntryRepo := new(mocks.MockTimeEntryRepository)
+		session := Start(primitive.NewObjectID(), nil, start)
+		entry := &models.TimeEntry{}
+		timeEntryRepo.On("Create", ctx, entry).Return(entry, nil)
+		timeEntryRepo.On("Delete", ctx, mock.Anything).Return(errors.New("database error"))
+		sessionRepo.On("Save", ctx, session, session.PhaseStartedAt).Return(mongo.ErrNoDocuments)
+
+		err := Save(ctx, sessionRepo, timeEntryRepo, session, session.PhaseStartedAt, []*models.TimeEntry{entry})
+
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+		timeEntryRepo.AssertCalled(t, "Delete", ctx, entry.ID.Hex())
+	})
+}
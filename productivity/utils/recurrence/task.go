@@ -0,0 +1,147 @@
+package recurrence
+
+import (
+	"errors"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/rrule"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IsRecurring returns true if the task has a recurrence rule
+func IsRecurring(task *models.TaskEntity) bool {
+	return task != nil && task.Recurrence != nil && *task.Recurrence != ""
+}
+
+// ValidateTask checks that the recurrence rule of the task, if any, can be parsed and is anchored on a date
+func ValidateTask(task *models.TaskEntity) error {
+	if !IsRecurring(task) {
+		return nil
+	}
+	if _, err := rrule.Parse(*task.Recurrence); err != nil {
+		return err
+	}
+	if anchor(task) == nil {
+		return errors.New("a recurring task requires a start date or an end date")
+	}
+	return nil
+}
+
+// ExpandTask returns the occurrences of a recurring task whose anchor date falls within [from, to].
+// Start date, end date and reminders of each occurrence keep the same offsets as on the task itself.
+func ExpandTask(task *models.TaskEntity, from, to time.Time) ([]*models.TaskOccurrence, error) {
+	return ExpandTaskIn(task, from, to, time.UTC)
+}
+
+// ExpandTaskIn works like ExpandTask with the recurrence evaluated in the given location, so that
+// occurrences keep the same wall clock time across daylight saving time transitions.
+func ExpandTaskIn(task *models.TaskEntity, from, to time.Time, loc *time.Location) ([]*models.TaskOccurrence, error) {
+	if !IsRecurring(task) {
+		return nil, nil
+	}
+	rule, err := rrule.Parse(*task.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	dtstart := anchor(task)
+	if dtstart == nil {
+		return nil, nil
+	}
+
+	start := dtstart.Time().In(loc)
+	occurrences := []*models.TaskOccurrence{}
+	for _, date := range rule.Between(start, from, to, excludedDates(task)) {
+		delta := date.Sub(start)
+		occurrences = append(occurrences, &models.TaskOccurrence{
+			TaskID:    task.ID,
+			StartDate: shift(task.StartDate, delta),
+			EndDate:   shift(task.EndDate, delta),
+			Reminders: shiftAll(task.Reminders, delta),
+		})
+	}
+	return occurrences, nil
+}
+
+// AdvanceTask moves a recurring task to its next occurrence: dates and reminders are shifted,
+// the task is marked as not completed and a COUNT limit is decremented by the consumed occurrences.
+// The recurrence is evaluated in the given location, the timezone of the user, so that the task keeps
+// the same wall clock time across daylight saving time transitions.
+// It returns false when the recurrence is exhausted, in which case the task is left untouched.
+func AdvanceTask(task *models.TaskEntity, loc *time.Location) (bool, error) {
+	if !IsRecurring(task) {
+		return false, nil
+	}
+	rule, err := rrule.Parse(*task.Recurrence)
+	if err != nil {
+		return false, err
+	}
+	dtstart := anchor(task)
+	if dtstart == nil {
+		return false, nil
+	}
+
+	start := dtstart.Time().In(loc)
+	next, ok := rule.After(start, start, excludedDates(task))
+	if !ok {
+		return false, nil
+	}
+
+	if rule.Count > 0 {
+		// occurrences before the next one are consumed, excluded dates included
+		consumed := len(rule.Between(start, start, next.Add(-time.Nanosecond), nil))
+		rule.Count -= consumed
+		if rule.Count < 1 {
+			return false, nil
+		}
+		recurrenceRule := rule.String()
+		task.Recurrence = &recurrenceRule
+	}
+
+	delta := next.Sub(start)
+	task.StartDate = shift(task.StartDate, delta)
+	task.EndDate = shift(task.EndDate, delta)
+	task.Reminders = shiftAll(task.Reminders, delta)
+	completed := false
+	task.Completed = &completed
+
+	return true, nil
+}
+
+// anchor returns the date the recurrence of the task is anchored on
+func anchor(task *models.TaskEntity) *primitive.DateTime {
+	if task.StartDate != nil {
+		return task.StartDate
+	}
+	return task.EndDate
+}
+
+func excludedDates(task *models.TaskEntity) []time.Time {
+	dates := make([]time.Time, 0, len(task.ExcludedDates))
+	for _, date := range task.ExcludedDates {
+		if date != nil {
+			dates = append(dates, date.Time())
+		}
+	}
+	return dates
+}
+
+func shift(date *primitive.DateTime, delta time.Duration) *primitive.DateTime {
+	if date == nil {
+		return nil
+	}
+	shifted := primitive.NewDateTimeFromTime(date.Time().Add(delta))
+	return &shifted
+}
+
+func shiftAll(dates []*primitive.DateTime, delta time.Duration) []*primitive.DateTime {
+	if dates == nil {
+		return nil
+	}
+	shifted := make([]*primitive.DateTime, 0, len(dates))
+	for _, date := range dates {
+		shifted = append(shifted, shift(date, delta))
+	}
+	return shifted
+}
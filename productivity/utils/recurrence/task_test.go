@@ -0,0 +1,145 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func dateTime(t time.Time) *primitive.DateTime {
+	dt := primitive.NewDateTimeFromTime(t)
+	return &dt
+}
+
+func createRecurringTask(rule string) *models.TaskEntity {
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	completed := true
+	return &models.TaskEntity{
+		ID:         primitive.NewObjectID().Hex(),
+		Title:      "Weekly chore",
+		StartDate:  dateTime(start),
+		EndDate:    dateTime(start.Add(time.Hour)),
+		Reminders:  []*primitive.DateTime{dateTime(start.Add(-15 * time.Minute))},
+		Completed:  &completed,
+		Recurrence: &rule,
+	}
+}
+
+func TestValidateTask(t *testing.T) {
+	t.Run("non recurring task", func(t *testing.T) {
+		assert.NoError(t, ValidateTask(&models.TaskEntity{Title: "Task"}))
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		assert.Error(t, ValidateTask(createRecurringTask("FREQ=SOMETIMES")))
+	})
+
+	t.Run("missing anchor date", func(t *testing.T) {
+		task := createRecurringTask("FREQ=DAILY")
+		task.StartDate = nil
+		task.EndDate = nil
+		assert.Error(t, ValidateTask(task))
+	})
+}
+
+func TestExpandTask(t *testing.T) {
+	task := createRecurringTask("FREQ=WEEKLY")
+	task.ExcludedDates = []*primitive.DateTime{dateTime(time.Date(2025, 1, 13, 9, 0, 0, 0, time.UTC))}
+
+	occurrences, err := ExpandTask(task, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, occurrences, 3)
+
+	assert.Equal(t, task.ID, occurrences[1].TaskID)
+	assert.Equal(t, time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC), occurrences[1].StartDate.Time().UTC())
+	assert.Equal(t, time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC), occurrences[1].EndDate.Time().UTC())
+	assert.Equal(t, time.Date(2025, 1, 20, 8, 45, 0, 0, time.UTC), occurrences[1].Reminders[0].Time().UTC())
+}
+
+func TestExpandTaskIn(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	// every day at 09:00 in Paris, across the switch to summer time on march 30th 2025
+	task := createRecurringTask("FREQ=DAILY")
+	start := time.Date(2025, 3, 28, 9, 0, 0, 0, paris)
+	task.StartDate = dateTime(start)
+	task.EndDate = dateTime(start.Add(time.Hour))
+	task.Reminders = []*primitive.DateTime{dateTime(start.Add(-15 * time.Minute))}
+
+	from := time.Date(2025, 3, 28, 0, 0, 0, 0, paris)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, paris)
+
+	occurrences, err := ExpandTaskIn(task, from, to, paris)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 4)
+	for _, occurrence := range occurrences {
+		local := occurrence.StartDate.Time().In(paris)
+		assert.Equal(t, 9, local.Hour())
+		assert.Equal(t, 0, local.Minute())
+		assert.Equal(t, time.Hour, occurrence.EndDate.Time().Sub(occurrence.StartDate.Time()))
+		assert.Equal(t, 8, occurrence.Reminders[0].Time().In(paris).Hour())
+		assert.Equal(t, 45, occurrence.Reminders[0].Time().In(paris).Minute())
+	}
+
+	// evaluated in UTC the occurrences after the switch drift by one hour
+	occurrences, err = ExpandTask(task, from, to)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 4)
+	assert.Equal(t, 10, occurrences[3].StartDate.Time().In(paris).Hour())
+}
+
+func TestAdvanceTask(t *testing.T) {
+	t.Run("moves to the next occurrence", func(t *testing.T) {
+		task := createRecurringTask("FREQ=WEEKLY;BYDAY=MO,TH")
+
+		advanced, err := AdvanceTask(task, time.UTC)
+		require.NoError(t, err)
+		assert.True(t, advanced)
+		assert.False(t, *task.Completed)
+		assert.Equal(t, time.Date(2025, 1, 9, 9, 0, 0, 0, time.UTC), task.StartDate.Time().UTC())
+		assert.Equal(t, time.Date(2025, 1, 9, 10, 0, 0, 0, time.UTC), task.EndDate.Time().UTC())
+		assert.Equal(t, time.Date(2025, 1, 9, 8, 45, 0, 0, time.UTC), task.Reminders[0].Time().UTC())
+	})
+
+	t.Run("decrements count", func(t *testing.T) {
+		task := createRecurringTask("FREQ=DAILY;COUNT=2")
+
+		advanced, err := AdvanceTask(task, time.UTC)
+		require.NoError(t, err)
+		assert.True(t, advanced)
+		assert.Equal(t, "FREQ=DAILY;COUNT=1", *task.Recurrence)
+
+		completed := true
+		task.Completed = &completed
+		advanced, err = AdvanceTask(task, time.UTC)
+		require.NoError(t, err)
+		assert.False(t, advanced)
+		assert.True(t, *task.Completed)
+	})
+
+	t.Run("keeps the wall clock time across daylight saving time", func(t *testing.T) {
+		paris, err := time.LoadLocation("Europe/Paris")
+		require.NoError(t, err)
+		recurrence := "FREQ=WEEKLY"
+		// 09:00 in Paris on the Monday before the switch to summer time
+		startDate := primitive.NewDateTimeFromTime(time.Date(2025, 3, 24, 9, 0, 0, 0, paris))
+		task := &models.TaskEntity{Title: "Task", Recurrence: &recurrence, StartDate: &startDate}
+
+		advanced, err := AdvanceTask(task, paris)
+		require.NoError(t, err)
+		assert.True(t, advanced)
+		assert.Equal(t, time.Date(2025, 3, 31, 9, 0, 0, 0, paris), task.StartDate.Time().In(paris))
+	})
+
+	t.Run("non recurring task", func(t *testing.T) {
+		advanced, err := AdvanceTask(&models.TaskEntity{Title: "Task"}, time.UTC)
+		require.NoError(t, err)
+		assert.False(t, advanced)
+	})
+}
@@ -0,0 +1,442 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ part of a recurrence rule
+type Frequency string
+
+// Supported recurrence frequencies
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+	FrequencyYearly  Frequency = "YEARLY"
+)
+
+// maxIterations bounds the number of periods walked when expanding a rule,
+// so that a rule which never matches cannot loop forever
+const maxIterations = 100000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// WeekdayNum is a BYDAY entry, optionally prefixed by an ordinal (e.g. 1MO, -1FR)
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// Rule is a parsed RFC 5545 recurrence rule.
+// Supported parts are FREQ, INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY and BYMONTH.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10".
+// An optional "RRULE:" prefix is accepted.
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "RRULE:")
+	if value == "" {
+		return nil, errors.New("empty recurrence rule")
+	}
+
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid rule part: %s", part)
+		}
+		key, val := strings.ToUpper(kv[0]), kv[1]
+
+		switch key {
+		case "FREQ":
+			switch Frequency(strings.ToUpper(val)) {
+			case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+				rule.Freq = Frequency(strings.ToUpper(val))
+			default:
+				return nil, fmt.Errorf("unsupported frequency: %s", val)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid interval: %s", val)
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid count: %s", val)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekdayNum, err := parseWeekdayNum(day)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, weekdayNum)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				monthDay, err := strconv.Atoi(day)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return nil, fmt.Errorf("invalid month day: %s", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, monthDay)
+			}
+		case "BYMONTH":
+			for _, month := range strings.Split(val, ",") {
+				m, err := strconv.Atoi(month)
+				if err != nil || m < 1 || m > 12 {
+					return nil, fmt.Errorf("invalid month: %s", month)
+				}
+				rule.ByMonth = append(rule.ByMonth, time.Month(m))
+			}
+		case "WKST":
+			// weeks always start on monday
+		default:
+			return nil, fmt.Errorf("unsupported rule part: %s", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, errors.New("FREQ is required")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, errors.New("COUNT and UNTIL cannot be used together")
+	}
+
+	return rule, nil
+}
+
+// String serializes the rule back to its RRULE value (without the "RRULE:" prefix)
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			days = append(days, formatWeekdayNum(day))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, day := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, 0, len(r.ByMonth))
+		for _, month := range r.ByMonth {
+			months = append(months, strconv.Itoa(int(month)))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between returns the occurrences of the rule starting at dtstart that fall within [from, to].
+// Occurrences listed in exdates are excluded from the result but still count towards COUNT.
+func (r *Rule) Between(dtstart, from, to time.Time, exdates []time.Time) []time.Time {
+	occurrences := []time.Time{}
+	r.iterate(dtstart, func(occurrence time.Time) bool {
+		if occurrence.After(to) {
+			return false
+		}
+		if !occurrence.Before(from) && !containsTime(exdates, occurrence) {
+			occurrences = append(occurrences, occurrence)
+		}
+		return true
+	})
+	return occurrences
+}
+
+// After returns the first occurrence of the rule starting at dtstart that is strictly after the given time.
+// The second return value is false when the recurrence has no more occurrences.
+func (r *Rule) After(dtstart, after time.Time, exdates []time.Time) (time.Time, bool) {
+	var next time.Time
+	found := false
+	r.iterate(dtstart, func(occurrence time.Time) bool {
+		if occurrence.After(after) && !containsTime(exdates, occurrence) {
+			next = occurrence
+			found = true
+			return false
+		}
+		return true
+	})
+	return next, found
+}
+
+// iterate walks the occurrences of the rule in chronological order, calling yield
+// for each of them until yield returns false or the rule is exhausted
+func (r *Rule) iterate(dtstart time.Time, yield func(time.Time) bool) {
+	emitted := 0
+	for period := 0; period < maxIterations; period++ {
+		candidates := r.candidates(dtstart, period)
+		if candidates == nil {
+			return
+		}
+		for _, candidate := range candidates {
+			if candidate.Before(dtstart) {
+				continue
+			}
+			if r.Until != nil && candidate.After(*r.Until) {
+				return
+			}
+			if r.Count > 0 && emitted >= r.Count {
+				return
+			}
+			emitted++
+			if !yield(candidate) {
+				return
+			}
+		}
+	}
+}
+
+// candidates returns the sorted occurrence candidates of the n-th period of the rule.
+// It returns nil once the period is past the UNTIL bound.
+func (r *Rule) candidates(dtstart time.Time, n int) []time.Time {
+	step := n * r.Interval
+	y, m, d := dtstart.Date()
+	hour, minute, sec := dtstart.Clock()
+	loc := dtstart.Location()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, sec, dtstart.Nanosecond(), loc)
+	}
+
+	var candidates []time.Time
+	var periodStart time.Time
+
+	switch r.Freq {
+	case FrequencyDaily:
+		day := at(y, m, d+step)
+		periodStart = day
+		if r.matchesMonth(day.Month()) && r.matchesMonthDay(day) && r.matchesWeekday(day.Weekday()) {
+			candidates = append(candidates, day)
+		}
+	case FrequencyWeekly:
+		offset := (int(dtstart.Weekday()) + 6) % 7 // days since monday
+		monday := at(y, m, d-offset+7*step)
+		periodStart = monday
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []WeekdayNum{{Weekday: dtstart.Weekday()}}
+		}
+		for _, day := range days {
+			candidate := monday.AddDate(0, 0, (int(day.Weekday)+6)%7)
+			if r.matchesMonth(candidate.Month()) {
+				candidates = append(candidates, candidate)
+			}
+		}
+	case FrequencyMonthly:
+		first := at(y, m+time.Month(step), 1)
+		periodStart = first
+		if r.matchesMonth(first.Month()) {
+			candidates = r.monthCandidates(first, d, at)
+		}
+	case FrequencyYearly:
+		periodStart = at(y+step, time.January, 1)
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{m}
+		}
+		for _, month := range months {
+			candidates = append(candidates, r.monthCandidates(at(y+step, month, 1), d, at)...)
+		}
+	default:
+		return nil
+	}
+
+	if r.Until != nil && periodStart.After(*r.Until) {
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	if candidates == nil {
+		candidates = []time.Time{}
+	}
+	return candidates
+}
+
+// monthCandidates returns the candidates within the month starting at first
+func (r *Rule) monthCandidates(first time.Time, defaultDay int, at func(int, time.Month, int) time.Time) []time.Time {
+	year, month := first.Year(), first.Month()
+	daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	candidates := []time.Time{}
+
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, monthDay := range r.ByMonthDay {
+			day := monthDay
+			if day < 0 {
+				day = daysInMonth + day + 1
+			}
+			if day < 1 || day > daysInMonth {
+				continue
+			}
+			candidate := at(year, month, day)
+			if r.matchesWeekday(candidate.Weekday()) {
+				candidates = append(candidates, candidate)
+			}
+		}
+	case len(r.ByDay) > 0:
+		for _, weekdayNum := range r.ByDay {
+			var matching []int
+			for day := 1; day <= daysInMonth; day++ {
+				if time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() == weekdayNum.Weekday {
+					matching = append(matching, day)
+				}
+			}
+			switch {
+			case weekdayNum.N == 0:
+				for _, day := range matching {
+					candidates = append(candidates, at(year, month, day))
+				}
+			case weekdayNum.N > 0 && weekdayNum.N <= len(matching):
+				candidates = append(candidates, at(year, month, matching[weekdayNum.N-1]))
+			case weekdayNum.N < 0 && -weekdayNum.N <= len(matching):
+				candidates = append(candidates, at(year, month, matching[len(matching)+weekdayNum.N]))
+			}
+		}
+	default:
+		// months without the start day (e.g. the 31st) are skipped, as required by RFC 5545
+		if defaultDay <= daysInMonth {
+			candidates = append(candidates, at(year, month, defaultDay))
+		}
+	}
+
+	return candidates
+}
+
+func (r *Rule) matchesMonth(month time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, monthDay := range r.ByMonthDay {
+		if monthDay == t.Day() || (monthDay < 0 && daysInMonth+monthDay+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesWeekday(weekday time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, day := range r.ByDay {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func parseUntil(value string) (time.Time, error) {
+	formats := []string{"20060102T150405Z", "20060102T150405", "20060102", time.RFC3339}
+	for _, format := range formats {
+		if until, err := time.Parse(format, value); err == nil {
+			if format == "20060102" {
+				// a date-only UNTIL is inclusive of the whole day
+				until = until.Add(24*time.Hour - time.Second)
+			}
+			return until, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid until: %s", value)
+}
+
+func parseWeekdayNum(value string) (WeekdayNum, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid weekday: %s", value)
+	}
+	weekday, ok := weekdays[value[len(value)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid weekday: %s", value)
+	}
+	n := 0
+	if prefix := value[:len(value)-2]; prefix != "" {
+		parsed, err := strconv.Atoi(prefix)
+		if err != nil || parsed == 0 || parsed < -53 || parsed > 53 {
+			return WeekdayNum{}, fmt.Errorf("invalid weekday: %s", value)
+		}
+		n = parsed
+	}
+	return WeekdayNum{Weekday: weekday, N: n}, nil
+}
+
+func formatWeekdayNum(day WeekdayNum) string {
+	for code, weekday := range weekdays {
+		if weekday == day.Weekday {
+			if day.N != 0 {
+				return strconv.Itoa(day.N) + code
+			}
+			return code
+		}
+	}
+	return ""
+}
+
+func containsTime(slice []time.Time, item time.Time) bool {
+	for _, t := range slice {
+		if t.Equal(item) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	t.Run("valid rule with prefix", func(t *testing.T) {
+		rule, err := Parse("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,-1FR;COUNT=4")
+		require.NoError(t, err)
+		assert.Equal(t, FrequencyWeekly, rule.Freq)
+		assert.Equal(t, 2, rule.Interval)
+		assert.Equal(t, 4, rule.Count)
+		assert.Equal(t, []WeekdayNum{{Weekday: time.Monday}, {Weekday: time.Friday, N: -1}}, rule.ByDay)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		rule, err := Parse("FREQ=MONTHLY;BYMONTHDAY=1,-1;UNTIL=20250101T000000Z")
+		require.NoError(t, err)
+		assert.Equal(t, "FREQ=MONTHLY;UNTIL=20250101T000000Z;BYMONTHDAY=1,-1", rule.String())
+	})
+
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;BYSETPOS=1",
+	}
+	for _, value := range invalid {
+		t.Run("invalid "+value, func(t *testing.T) {
+			_, err := Parse(value)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		dtstart  time.Time
+		from     time.Time
+		to       time.Time
+		exdates  []time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "daily with count",
+			rule:     "FREQ=DAILY;COUNT=3",
+			dtstart:  date(2025, 1, 1, 9, 0),
+			from:     date(2025, 1, 1, 0, 0),
+			to:       date(2025, 2, 1, 0, 0),
+			expected: []time.Time{date(2025, 1, 1, 9, 0), date(2025, 1, 2, 9, 0), date(2025, 1, 3, 9, 0)},
+		},
+		{
+			name:     "weekly on several days",
+			rule:     "FREQ=WEEKLY;BYDAY=MO,WE",
+			dtstart:  date(2025, 1, 6, 8, 30), // monday
+			from:     date(2025, 1, 6, 0, 0),
+			to:       date(2025, 1, 14, 0, 0),
+			expected: []time.Time{date(2025, 1, 6, 8, 30), date(2025, 1, 8, 8, 30), date(2025, 1, 13, 8, 30)},
+		},
+		{
+			name:     "every other week",
+			rule:     "FREQ=WEEKLY;INTERVAL=2",
+			dtstart:  date(2025, 1, 1, 10, 0),
+			from:     date(2025, 1, 1, 0, 0),
+			to:       date(2025, 2, 1, 0, 0),
+			expected: []time.Time{date(2025, 1, 1, 10, 0), date(2025, 1, 15, 10, 0), date(2025, 1, 29, 10, 0)},
+		},
+		{
+			name:     "monthly skips short months",
+			rule:     "FREQ=MONTHLY;COUNT=3",
+			dtstart:  date(2025, 1, 31, 12, 0),
+			from:     date(2025, 1, 1, 0, 0),
+			to:       date(2026, 1, 1, 0, 0),
+			expected: []time.Time{date(2025, 1, 31, 12, 0), date(2025, 3, 31, 12, 0), date(2025, 5, 31, 12, 0)},
+		},
+		{
+			name:     "monthly on last friday",
+			rule:     "FREQ=MONTHLY;BYDAY=-1FR;COUNT=2",
+			dtstart:  date(2025, 1, 1, 17, 0),
+			from:     date(2025, 1, 1, 0, 0),
+			to:       date(2026, 1, 1, 0, 0),
+			expected: []time.Time{date(2025, 1, 31, 17, 0), date(2025, 2, 28, 17, 0)},
+		},
+		{
+			name:     "yearly with until",
+			rule:     "FREQ=YEARLY;UNTIL=20270101",
+			dtstart:  date(2025, 3, 15, 0, 0),
+			from:     date(2020, 1, 1, 0, 0),
+			to:       date(2030, 1, 1, 0, 0),
+			expected: []time.Time{date(2025, 3, 15, 0, 0), date(2026, 3, 15, 0, 0)},
+		},
+		{
+			name:     "excluded dates are skipped",
+			rule:     "FREQ=DAILY;COUNT=3",
+			dtstart:  date(2025, 1, 1, 9, 0),
+			from:     date(2025, 1, 1, 0, 0),
+			to:       date(2025, 2, 1, 0, 0),
+			exdates:  []time.Time{date(2025, 1, 2, 9, 0)},
+			expected: []time.Time{date(2025, 1, 1, 9, 0), date(2025, 1, 3, 9, 0)},
+		},
+		{
+			name:     "window in the middle of the series",
+			rule:     "FREQ=DAILY",
+			dtstart:  date(2025, 1, 1, 9, 0),
+			from:     date(2025, 6, 10, 0, 0),
+			to:       date(2025, 6, 11, 23, 59),
+			expected: []time.Time{date(2025, 6, 10, 9, 0), date(2025, 6, 11, 9, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rule.Between(tt.dtstart, tt.from, tt.to, tt.exdates))
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	rule, err := Parse("FREQ=WEEKLY;BYDAY=FR;COUNT=2")
+	require.NoError(t, err)
+	dtstart := date(2025, 1, 3, 18, 0) // friday
+
+	next, ok := rule.After(dtstart, dtstart, nil)
+	assert.True(t, ok)
+	assert.Equal(t, date(2025, 1, 10, 18, 0), next)
+
+	_, ok = rule.After(dtstart, next, nil)
+	assert.False(t, ok)
+}
@@ -0,0 +1,217 @@
+package taskimport
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/rrule"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// columns of a CSV file
+const (
+	columnTitle       = "title"
+	columnDescription = "description"
+	columnStartDate   = "start_date"
+	columnEndDate     = "end_date"
+	columnReminders   = "reminders"
+	columnCompleted   = "completed"
+	columnPriority    = "priority"
+	columnTags        = "tags"
+	columnFolder      = "folder"
+	columnRecurrence  = "recurrence"
+)
+
+var csvColumns = []string{
+	columnTitle, columnDescription, columnStartDate, columnEndDate, columnReminders,
+	columnCompleted, columnPriority, columnTags, columnFolder, columnRecurrence,
+}
+
+// csvDateFormats are the formats accepted for the dates of a CSV file, dates without time zone are in UTC
+var csvDateFormats = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+
+// ParseCSV reads the rows of a CSV file following the layout of the package documentation. Rows which
+// cannot be read are reported as row errors, an error is returned when the header is invalid.
+func ParseCSV(r io.Reader) ([]*models.TaskImportRow, []*models.TaskImportError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("the file is empty")
+		}
+		return nil, nil, err
+	}
+	columns, err := csvHeader(header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := []*models.TaskImportRow{}
+	rowErrors := []*models.TaskImportError{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, err
+			}
+			rowErrors = append(rowErrors, &models.TaskImportError{Row: parseErr.StartLine, Error: parseErr.Err.Error()})
+			continue
+		}
+		if isBlank(record) {
+			continue
+		}
+		if len(rows)+len(rowErrors) >= MaxRows {
+			return nil, nil, ErrTooManyRows
+		}
+
+		row, err := readRecord(record, columns, line)
+		if err == nil {
+			err = validate(row)
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, &models.TaskImportError{Row: line, Error: err.Error()})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, rowErrors, nil
+}
+
+// csvHeader returns the index of the columns of a CSV file
+func csvHeader(header []string) (map[string]int, error) {
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !isColumn(name) {
+			return nil, fmt.Errorf("unknown column %q, columns are %s", name, strings.Join(csvColumns, ", "))
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("duplicate column %q", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns[columnTitle]; !ok {
+		return nil, errors.New("the title column is required")
+	}
+	return columns, nil
+}
+
+func readRecord(record []string, columns map[string]int, line int) (*models.TaskImportRow, error) {
+	value := func(column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	task := &models.TaskEntity{Title: value(columnTitle)}
+	row := &models.TaskImportRow{Row: line, Task: task, Tags: splitList(value(columnTags)), Folder: value(columnFolder)}
+
+	if description := value(columnDescription); description != "" {
+		task.Description = &description
+	}
+
+	var err error
+	if task.StartDate, err = csvDate(columnStartDate, value(columnStartDate)); err != nil {
+		return nil, err
+	}
+	if task.EndDate, err = csvDate(columnEndDate, value(columnEndDate)); err != nil {
+		return nil, err
+	}
+	for _, reminder := range splitList(value(columnReminders)) {
+		date, err := csvDate(columnReminders, reminder)
+		if err != nil {
+			return nil, err
+		}
+		task.Reminders = append(task.Reminders, date)
+	}
+
+	if completed := value(columnCompleted); completed != "" {
+		done, err := parseBool(completed)
+		if err != nil {
+			return nil, err
+		}
+		task.Completed = &done
+	}
+
+	if priority := value(columnPriority); priority != "" {
+		p, err := strconv.Atoi(priority)
+		if err != nil || p < 0 || p > priorityHigh {
+			return nil, fmt.Errorf("invalid priority %q, expected 0 to %d", priority, priorityHigh)
+		}
+		task.Priority = &p
+	}
+
+	if rule := value(columnRecurrence); rule != "" {
+		parsed, err := rrule.Parse(rule)
+		if err != nil {
+			return nil, errors.New("invalid recurrence: " + err.Error())
+		}
+		recurrence := "RRULE:" + parsed.String()
+		task.Recurrence = &recurrence
+	}
+
+	return row, nil
+}
+
+func csvDate(column, value string) (*primitive.DateTime, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, format := range csvDateFormats {
+		if t, err := time.ParseInLocation(format, value, time.UTC); err == nil {
+			return dateTime(t), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid %s %q", column, value)
+}
+
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "1", "x":
+		return true, nil
+	case "false", "no", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid completed value %q", value)
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ";")
+}
+
+func isColumn(name string) bool {
+	for _, column := range csvColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package taskimport
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	file := "Title,Due_Date,start_date,tags,folder,completed,priority,reminders,recurrence,description\n"
+	_, _, err := ParseCSV(strings.NewReader(file))
+	assert.EqualError(t, err, `unknown column "due_date", columns are title, description, start_date, end_date, reminders, completed, priority, tags, folder, recurrence`)
+
+	file = "Title,End_Date,start_date,tags,folder,completed,priority,reminders,recurrence,description\n" +
+		`Write report,2026-03-12T17:00:00Z,2026-03-10,"Work; Reports;work", Work / Clients ,yes,3,2026-03-12T16:00:00Z,FREQ=WEEKLY,"Quarterly, with charts"` + "\n" +
+		"\n" +
+		"Call the bank,,,,,,,,,\n" +
+		",2026-03-12,,,,,,,,\n" +
+		"Late,2026-03-01,2026-03-10,,,,,,,\n" +
+		"Urgent,,,,,,5,,,\n" +
+		"Repeat,,,,,,,,FREQ=DAILY,\n" +
+		"Odd,,,,,maybe,,,,\n"
+
+	rows, rowErrors, err := ParseCSV(strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	report := rows[0]
+	assert.Equal(t, 2, report.Row)
+	assert.Equal(t, "Write report", report.Task.Title)
+	assert.Equal(t, "Quarterly, with charts", *report.Task.Description)
+	assert.Equal(t, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), report.Task.StartDate.Time().UTC())
+	assert.Equal(t, time.Date(2026, 3, 12, 17, 0, 0, 0, time.UTC), report.Task.EndDate.Time().UTC())
+	assert.Equal(t, []string{"Work", "Reports"}, report.Tags)
+	assert.Equal(t, "Work/Clients", report.Folder)
+	assert.True(t, *report.Task.Completed)
+	assert.Equal(t, 3, *report.Task.Priority)
+	assert.Len(t, report.Task.Reminders, 1)
+	assert.Equal(t, "RRULE:FREQ=WEEKLY", *report.Task.Recurrence)
+
+	bank := rows[1]
+	assert.Equal(t, 4, bank.Row)
+	assert.False(t, *bank.Task.Completed)
+	assert.Nil(t, bank.Task.EndDate)
+	assert.Empty(t, bank.Tags)
+
+	require.Len(t, rowErrors, 5)
+	assert.Equal(t, 5, rowErrors[0].Row)
+	assert.Equal(t, "title is required", rowErrors[0].Error)
+	assert.Equal(t, "end date is before start date", rowErrors[1].Error)
+	assert.Contains(t, rowErrors[2].Error, "invalid priority")
+	assert.Contains(t, rowErrors[3].Error, "requires a start date or an end date")
+	assert.Contains(t, rowErrors[4].Error, "invalid completed value")
+}
+
+func TestParseCSV_Header(t *testing.T) {
+	_, _, err := ParseCSV(strings.NewReader(""))
+	assert.Error(t, err)
+
+	_, _, err = ParseCSV(strings.NewReader("description\nSomething\n"))
+	assert.EqualError(t, err, "the title column is required")
+
+	_, _, err = ParseCSV(strings.NewReader("title,TITLE\nA,B\n"))
+	assert.Error(t, err)
+
+	rows, _, err := ParseCSV(strings.NewReader("\ufefftitle\nTask\n"))
+	require.NoError(t, err)
+	assert.Len(t, rows, 1)
+}
+
+func TestFolderPath(t *testing.T) {
+	assert.Equal(t, []string{"Work", "Clients"}, FolderPath(" /Work// Clients /"))
+	assert.Empty(t, FolderPath(""))
+}
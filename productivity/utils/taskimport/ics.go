@@ -0,0 +1,175 @@
+package taskimport
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/ical"
+	"github.com/atomic-blend/backend/productivity/utils/rrule"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ParseICS reads the VTODO components of an iCalendar file. Tasks which cannot be read are reported as row
+// errors, an error is returned when the file itself cannot be read.
+func ParseICS(r io.Reader) ([]*models.TaskImportRow, []*models.TaskImportError, error) {
+	calendars, err := ical.Parse(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := []*models.TaskImportRow{}
+	rowErrors := []*models.TaskImportError{}
+	for _, calendar := range calendars {
+		for _, todo := range calendar.Children("VTODO") {
+			if len(rows)+len(rowErrors) >= MaxRows {
+				return nil, nil, ErrTooManyRows
+			}
+			row, err := readTodo(todo)
+			if err == nil {
+				err = validate(row)
+			}
+			if err != nil {
+				rowErrors = append(rowErrors, &models.TaskImportError{Row: todo.Line, Error: err.Error()})
+				continue
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, rowErrors, nil
+}
+
+func readTodo(todo *ical.Component) (*models.TaskImportRow, error) {
+	task := &models.TaskEntity{}
+	row := &models.TaskImportRow{Row: todo.Line, Task: task, Tags: []string{}}
+
+	if summary := todo.Property("SUMMARY"); summary != nil {
+		task.Title = summary.Text()
+	}
+	if description := todo.Property("DESCRIPTION"); description != nil && description.Text() != "" {
+		text := description.Text()
+		task.Description = &text
+	}
+
+	if start := todo.Property("DTSTART"); start != nil {
+		t, err := start.Time()
+		if err != nil {
+			return nil, err
+		}
+		task.StartDate = dateTime(t)
+	}
+	if due := todo.Property("DUE"); due != nil {
+		t, err := due.Time()
+		if err != nil {
+			return nil, err
+		}
+		task.EndDate = dateTime(t)
+	} else if duration := todo.Property("DURATION"); duration != nil && task.StartDate != nil {
+		d, err := ical.ParseDuration(duration.Value)
+		if err != nil {
+			return nil, err
+		}
+		task.EndDate = dateTime(task.StartDate.Time().Add(d))
+	}
+
+	completed := todo.Property("COMPLETED") != nil
+	if status := todo.Property("STATUS"); status != nil && strings.EqualFold(status.Value, "COMPLETED") {
+		completed = true
+	}
+	task.Completed = &completed
+
+	if priority := todo.Property("PRIORITY"); priority != nil {
+		value, err := strconv.Atoi(strings.TrimSpace(priority.Value))
+		if err != nil || value < 0 || value > 9 {
+			return nil, errors.New("invalid priority " + priority.Value)
+		}
+		task.Priority = icsPriority(value)
+	}
+
+	for _, categories := range todo.All("CATEGORIES") {
+		row.Tags = append(row.Tags, categories.Texts()...)
+	}
+
+	if rule := todo.Property("RRULE"); rule != nil {
+		parsed, err := rrule.Parse(rule.Value)
+		if err != nil {
+			return nil, errors.New("invalid recurrence: " + err.Error())
+		}
+		value := "RRULE:" + parsed.String()
+		task.Recurrence = &value
+		for _, exdate := range todo.All("EXDATE") {
+			dates, err := exdate.Times()
+			if err != nil {
+				return nil, err
+			}
+			for _, date := range dates {
+				task.ExcludedDates = append(task.ExcludedDates, dateTime(date))
+			}
+		}
+	}
+
+	for _, alarm := range todo.Children("VALARM") {
+		reminder, err := alarmTime(alarm, task)
+		if err != nil {
+			return nil, err
+		}
+		if reminder != nil {
+			task.Reminders = append(task.Reminders, reminder)
+		}
+	}
+
+	return row, nil
+}
+
+// alarmTime returns the date of the reminder of an alarm, nil when the alarm is relative to a date the task does not have
+func alarmTime(alarm *ical.Component, task *models.TaskEntity) (*primitive.DateTime, error) {
+	trigger := alarm.Property("TRIGGER")
+	if trigger == nil {
+		return nil, errors.New("alarm without trigger")
+	}
+	if strings.EqualFold(trigger.Params["VALUE"], "DATE-TIME") {
+		t, err := trigger.Time()
+		if err != nil {
+			return nil, err
+		}
+		return dateTime(t), nil
+	}
+
+	offset, err := ical.ParseDuration(trigger.Value)
+	if err != nil {
+		return nil, err
+	}
+	anchor := task.StartDate
+	if strings.EqualFold(trigger.Params["RELATED"], "END") || anchor == nil {
+		anchor = task.EndDate
+	}
+	if anchor == nil {
+		return nil, nil
+	}
+	return dateTime(anchor.Time().Add(offset)), nil
+}
+
+// icsPriority maps an iCalendar priority to the priority of a task, nil for undefined
+func icsPriority(value int) *int {
+	var priority int
+	switch {
+	case value == 0:
+		return nil
+	case value <= 4:
+		priority = priorityHigh
+	case value == 5:
+		priority = priorityMedium
+	default:
+		priority = priorityLow
+	}
+	return &priority
+}
+
+func dateTime(t time.Time) *primitive.DateTime {
+	d := primitive.NewDateTimeFromTime(t)
+	return &d
+}
@@ -0,0 +1,99 @@
+package taskimport
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const icsFile = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Other tool//EN
+BEGIN:VTODO
+UID:1
+SUMMARY:Write report
+DESCRIPTION:Quarterly\, with charts
+DTSTART:20260310T090000Z
+DUE:20260312T170000Z
+PRIORITY:1
+CATEGORIES:Work,Reports
+CATEGORIES:work
+RRULE:FREQ=WEEKLY;BYDAY=TU
+EXDATE:20260317T090000Z
+BEGIN:VALARM
+ACTION:DISPLAY
+TRIGGER;RELATED=END:-PT1H
+END:VALARM
+BEGIN:VALARM
+ACTION:DISPLAY
+TRIGGER;VALUE=DATE-TIME:20260311T080000Z
+END:VALARM
+END:VTODO
+BEGIN:VEVENT
+UID:2
+SUMMARY:Meeting
+DTSTART:20260310T100000Z
+END:VEVENT
+BEGIN:VTODO
+UID:3
+SUMMARY:Done already
+STATUS:COMPLETED
+PRIORITY:9
+END:VTODO
+BEGIN:VTODO
+UID:4
+DUE:20260312T170000Z
+END:VTODO
+BEGIN:VTODO
+UID:5
+SUMMARY:Bad date
+DUE:tomorrow
+END:VTODO
+END:VCALENDAR
+`
+
+func TestParseICS(t *testing.T) {
+	rows, rowErrors, err := ParseICS(strings.NewReader(icsFile))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	report := rows[0]
+	assert.Equal(t, 4, report.Row)
+	assert.Equal(t, "Write report", report.Task.Title)
+	assert.Equal(t, "Quarterly, with charts", *report.Task.Description)
+	assert.Equal(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC), report.Task.StartDate.Time().UTC())
+	assert.Equal(t, time.Date(2026, 3, 12, 17, 0, 0, 0, time.UTC), report.Task.EndDate.Time().UTC())
+	assert.Equal(t, priorityHigh, *report.Task.Priority)
+	assert.False(t, *report.Task.Completed)
+	assert.Equal(t, []string{"Work", "Reports"}, report.Tags)
+	assert.Equal(t, "RRULE:FREQ=WEEKLY;BYDAY=TU", *report.Task.Recurrence)
+	require.Len(t, report.Task.ExcludedDates, 1)
+	require.Len(t, report.Task.Reminders, 2)
+	assert.Equal(t, time.Date(2026, 3, 12, 16, 0, 0, 0, time.UTC), report.Task.Reminders[0].Time().UTC())
+	assert.Equal(t, time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC), report.Task.Reminders[1].Time().UTC())
+
+	done := rows[1]
+	assert.True(t, *done.Task.Completed)
+	assert.Equal(t, priorityLow, *done.Task.Priority)
+
+	require.Len(t, rowErrors, 2)
+	assert.Equal(t, 35, rowErrors[0].Row)
+	assert.Equal(t, "title is required", rowErrors[0].Error)
+	assert.Equal(t, 39, rowErrors[1].Row)
+	assert.Contains(t, rowErrors[1].Error, "invalid DUE date")
+}
+
+func TestParseICS_InvalidFile(t *testing.T) {
+	_, _, err := ParseICS(strings.NewReader("title\nTask\n"))
+	assert.Error(t, err)
+}
+
+func TestICSPriority(t *testing.T) {
+	assert.Nil(t, icsPriority(0))
+	assert.Equal(t, priorityHigh, *icsPriority(4))
+	assert.Equal(t, priorityMedium, *icsPriority(5))
+	assert.Equal(t, priorityLow, *icsPriority(6))
+}
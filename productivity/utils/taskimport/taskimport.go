@@ -0,0 +1,86 @@
+// Package taskimport reads the tasks of the files imported from other tools.
+//
+// iCalendar files are read from their VTODO components: SUMMARY is the title, DESCRIPTION the description,
+// DTSTART the start date, DUE (or DTSTART plus DURATION) the end date, CATEGORIES the tags, STATUS:COMPLETED
+// or COMPLETED marks the task as completed, RRULE and EXDATE its recurrence and each VALARM a reminder.
+// The PRIORITY of iCalendar (1 to 9, 1 being the highest) is mapped to high (3), medium (2) and low (1).
+//
+// CSV files start with a header row naming their columns, in any order and case:
+//
+//	title        required
+//	description
+//	start_date   RFC 3339 date-time, such as 2026-03-12T09:00:00Z, or date, such as 2026-03-12 (UTC)
+//	end_date     same as start_date
+//	reminders    dates separated by semicolons
+//	completed    true/false, yes/no or 1/0, false when empty
+//	priority     0 to 3, 3 being the highest
+//	tags         tag names separated by semicolons
+//	folder       folder path, such as Work/Clients
+//	recurrence   RRULE, such as FREQ=WEEKLY;BYDAY=MO
+package taskimport
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/recurrence"
+)
+
+// MaxRows is the maximum number of tasks of an imported file
+const MaxRows = 5000
+
+// priorities of the tasks
+const (
+	priorityLow    = 1
+	priorityMedium = 2
+	priorityHigh   = 3
+)
+
+// ErrTooManyRows is returned when a file holds more than MaxRows tasks
+var ErrTooManyRows = errors.New("the file holds too many tasks")
+
+// validate checks a task read from a file, tags and folder names are trimmed and deduplicated
+func validate(row *models.TaskImportRow) error {
+	task := row.Task
+	task.Title = strings.TrimSpace(task.Title)
+	if task.Title == "" {
+		return errors.New("title is required")
+	}
+	if task.StartDate != nil && task.EndDate != nil && task.EndDate.Time().Before(task.StartDate.Time()) {
+		return errors.New("end date is before start date")
+	}
+	if err := recurrence.ValidateTask(task); err != nil {
+		return errors.New("invalid recurrence: " + err.Error())
+	}
+	if task.Completed == nil {
+		completed := false
+		task.Completed = &completed
+	}
+
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, tag := range row.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+	}
+	row.Tags = tags
+
+	row.Folder = strings.Join(FolderPath(row.Folder), "/")
+	return nil
+}
+
+// FolderPath splits a folder path into the names of its folders, from the root
+func FolderPath(path string) []string {
+	names := []string{}
+	for _, name := range strings.Split(path, "/") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
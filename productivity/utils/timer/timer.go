@@ -0,0 +1,186 @@
+package timer
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultMaxHours is the longest a timer can run when TIMER_MAX_HOURS is not set
+const defaultMaxHours = 24
+
+// ErrNotRunning is returned when pausing a timer that is not running
+var ErrNotRunning = errors.New("timer is not running")
+
+// ErrNotPaused is returned when resuming a timer that is not paused
+var ErrNotPaused = errors.New("timer is not paused")
+
+// ErrStopped is returned when changing a timer that was already stopped
+var ErrStopped = errors.New("timer is already stopped")
+
+// Start returns a new running time entry for the user, started at now
+func Start(userID primitive.ObjectID, request *models.TimerStartRequest, now time.Time) *models.TimeEntry {
+	running := models.TimerStateRunning
+	active := true
+	isTimer := true
+	var pausedSeconds int64
+
+	entry := &models.TimeEntry{
+		User:          &userID,
+		StartDate:     now.UTC().Format(time.RFC3339),
+		Timer:         &isTimer,
+		TimerState:    &running,
+		PausedSeconds: &pausedSeconds,
+		ActiveTimer:   &active,
+	}
+	if request != nil {
+		entry.TaskID = request.TaskID
+		entry.Pomodoro = request.Pomodoro
+		entry.PomoBreak = request.PomoBreak
+		entry.Note = request.Note
+	}
+	SetDuration(entry, now)
+	return entry
+}
+
+// Pause pauses a running timer at now
+func Pause(entry *models.TimeEntry, now time.Time) error {
+	if state(entry) != models.TimerStateRunning {
+		if state(entry) == models.TimerStateStopped {
+			return ErrStopped
+		}
+		return ErrNotRunning
+	}
+
+	paused := models.TimerStatePaused
+	pausedAt := now.UTC().Format(time.RFC3339)
+	entry.TimerState = &paused
+	entry.PausedAt = &pausedAt
+	SetDuration(entry, now)
+	return nil
+}
+
+// Resume resumes a paused timer at now, the time spent paused is excluded from its duration
+func Resume(entry *models.TimeEntry, now time.Time) error {
+	if state(entry) != models.TimerStatePaused {
+		if state(entry) == models.TimerStateStopped {
+			return ErrStopped
+		}
+		return ErrNotPaused
+	}
+
+	endPause(entry, now)
+	running := models.TimerStateRunning
+	entry.TimerState = &running
+	SetDuration(entry, now)
+	return nil
+}
+
+// Stop stops a running or paused timer at now and sets its end date and final duration
+func Stop(entry *models.TimeEntry, now time.Time) error {
+	if state(entry) == models.TimerStateStopped {
+		return ErrStopped
+	}
+
+	endPause(entry, now)
+	stopped := models.TimerStateStopped
+	entry.TimerState = &stopped
+	entry.ActiveTimer = nil
+	entry.EndDate = now.UTC().Format(time.RFC3339)
+	SetDuration(entry, now)
+	return nil
+}
+
+// StopAfter stops a timer which tracked more than limit, its duration is capped to limit.
+// It returns false when the timer did not reach limit yet.
+func StopAfter(entry *models.TimeEntry, now time.Time, limit time.Duration) (bool, error) {
+	if state(entry) == models.TimerStateStopped {
+		return false, ErrStopped
+	}
+	if Elapsed(entry, now) <= limit {
+		return false, nil
+	}
+
+	if err := Stop(entry, now); err != nil {
+		return false, err
+	}
+	start, _ := time.Parse(time.RFC3339, entry.StartDate)
+	end := start.Add(time.Duration(pausedSeconds(entry))*time.Second + limit)
+	entry.EndDate = end.UTC().Format(time.RFC3339)
+	SetDuration(entry, end)
+	return true, nil
+}
+
+// Elapsed returns the time tracked by a timer at now, pauses excluded
+func Elapsed(entry *models.TimeEntry, now time.Time) time.Duration {
+	start, err := time.Parse(time.RFC3339, entry.StartDate)
+	if err != nil {
+		return 0
+	}
+
+	end := now
+	switch state(entry) {
+	case models.TimerStatePaused:
+		if entry.PausedAt != nil {
+			if pausedAt, err := time.Parse(time.RFC3339, *entry.PausedAt); err == nil {
+				end = pausedAt
+			}
+		}
+	case models.TimerStateStopped:
+		if stoppedAt, err := time.Parse(time.RFC3339, entry.EndDate); err == nil {
+			end = stoppedAt
+		}
+	}
+
+	elapsed := end.Sub(start) - time.Duration(pausedSeconds(entry))*time.Second
+	if elapsed < 0 {
+		return 0
+	}
+	return elapsed
+}
+
+// endPause adds the current pause, if any, to the time spent paused
+func endPause(entry *models.TimeEntry, now time.Time) {
+	if state(entry) != models.TimerStatePaused || entry.PausedAt == nil {
+		return
+	}
+	if pausedAt, err := time.Parse(time.RFC3339, *entry.PausedAt); err == nil && now.After(pausedAt) {
+		total := pausedSeconds(entry) + int64(now.Sub(pausedAt)/time.Second)
+		entry.PausedSeconds = &total
+	}
+	entry.PausedAt = nil
+}
+
+// SetDuration stores the time tracked by the timer at now in seconds, as the duration of the entry
+func SetDuration(entry *models.TimeEntry, now time.Time) {
+	duration := strconv.FormatInt(int64(Elapsed(entry, now)/time.Second), 10)
+	entry.Duration = &duration
+}
+
+func state(entry *models.TimeEntry) string {
+	if entry.TimerState == nil {
+		return ""
+	}
+	return *entry.TimerState
+}
+
+func pausedSeconds(entry *models.TimeEntry) int64 {
+	if entry.PausedSeconds == nil {
+		return 0
+	}
+	return *entry.PausedSeconds
+}
+
+// MaxDuration returns how long a timer can track time before it is stopped, read from TIMER_MAX_HOURS
+func MaxDuration() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("TIMER_MAX_HOURS"))
+	if err != nil || hours < 1 {
+		return defaultMaxHours * time.Hour
+	}
+	return time.Duration(hours) * time.Hour
+}
@@ -0,0 +1,105 @@
+package timer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTimerLifecycle(t *testing.T) {
+	userID := primitive.NewObjectID()
+	taskID := primitive.NewObjectID()
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	entry := Start(userID, &models.TimerStartRequest{TaskID: &taskID}, start)
+	assert.Equal(t, models.TimerStateRunning, *entry.TimerState)
+	assert.Equal(t, "2025-06-02T09:00:00Z", entry.StartDate)
+	assert.Equal(t, &taskID, entry.TaskID)
+	assert.True(t, *entry.ActiveTimer)
+	assert.Empty(t, entry.EndDate)
+
+	// running for 20 minutes, then paused for 10
+	require.NoError(t, Pause(entry, start.Add(20*time.Minute)))
+	assert.Equal(t, models.TimerStatePaused, *entry.TimerState)
+	assert.Equal(t, "1200", *entry.Duration)
+	assert.Equal(t, 20*time.Minute, Elapsed(entry, start.Add(25*time.Minute)))
+	assert.ErrorIs(t, Pause(entry, start.Add(25*time.Minute)), ErrNotRunning)
+
+	require.NoError(t, Resume(entry, start.Add(30*time.Minute)))
+	assert.Equal(t, models.TimerStateRunning, *entry.TimerState)
+	assert.Nil(t, entry.PausedAt)
+	assert.Equal(t, int64(600), *entry.PausedSeconds)
+	assert.ErrorIs(t, Resume(entry, start.Add(31*time.Minute)), ErrNotPaused)
+
+	require.NoError(t, Stop(entry, start.Add(time.Hour)))
+	assert.Equal(t, models.TimerStateStopped, *entry.TimerState)
+	assert.Nil(t, entry.ActiveTimer)
+	assert.Equal(t, "2025-06-02T10:00:00Z", entry.EndDate)
+	assert.Equal(t, "3000", *entry.Duration)
+
+	assert.ErrorIs(t, Stop(entry, start.Add(2*time.Hour)), ErrStopped)
+	assert.ErrorIs(t, Pause(entry, start.Add(2*time.Hour)), ErrStopped)
+	assert.ErrorIs(t, Resume(entry, start.Add(2*time.Hour)), ErrStopped)
+}
+
+func TestStopWhilePaused(t *testing.T) {
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	entry := Start(primitive.NewObjectID(), nil, start)
+
+	require.NoError(t, Pause(entry, start.Add(15*time.Minute)))
+	require.NoError(t, Stop(entry, start.Add(time.Hour)))
+
+	assert.Equal(t, "900", *entry.Duration)
+	assert.Equal(t, int64(2700), *entry.PausedSeconds)
+}
+
+func TestStopAfter(t *testing.T) {
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		pause    bool
+		now      time.Time
+		stopped  bool
+		endDate  string
+		duration string
+	}{
+		{name: "under the limit", now: start.Add(3 * time.Hour), stopped: false},
+		{name: "over the limit", now: start.Add(30 * time.Hour), stopped: true, endDate: "2025-06-03T09:00:00Z", duration: "86400"},
+		{name: "paused before the limit", pause: true, now: start.Add(30 * time.Hour), stopped: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := Start(primitive.NewObjectID(), nil, start)
+			if tt.pause {
+				require.NoError(t, Pause(entry, start.Add(time.Hour)))
+			}
+
+			stopped, err := StopAfter(entry, tt.now, 24*time.Hour)
+			require.NoError(t, err)
+			assert.Equal(t, tt.stopped, stopped)
+			if tt.stopped {
+				assert.Equal(t, models.TimerStateStopped, *entry.TimerState)
+				assert.Equal(t, tt.endDate, entry.EndDate)
+				assert.Equal(t, tt.duration, *entry.Duration)
+			}
+		})
+	}
+}
+
+func TestMaxDuration(t *testing.T) {
+	t.Setenv("TIMER_MAX_HOURS", "")
+	assert.Equal(t, 24*time.Hour, MaxDuration())
+
+	t.Setenv("TIMER_MAX_HOURS", "8")
+	assert.Equal(t, 8*time.Hour, MaxDuration())
+
+	t.Setenv("TIMER_MAX_HOURS", "-1")
+	assert.Equal(t, 24*time.Hour, MaxDuration())
+}
@@ -0,0 +1,304 @@
+package timereport
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+	"github.com/atomic-blend/backend/productivity/utils/habitstats"
+)
+
+// Labels holds the names of the tasks, folders and tags of a user, keyed by ID
+type Labels struct {
+	Tasks   map[string]*models.TaskEntity
+	Folders map[string]string
+	Tags    map[string]string
+}
+
+// totals accumulates durations before they are rounded to seconds
+type totals struct {
+	entries  int
+	tracked  time.Duration
+	pomodoro time.Duration
+	breaks   time.Duration
+}
+
+func (t *totals) add(entry *models.TimeEntry, duration time.Duration) {
+	switch {
+	case entry.PomoBreak != nil && *entry.PomoBreak:
+		t.breaks += duration
+	case entry.Pomodoro != nil && *entry.Pomodoro:
+		t.tracked += duration
+		t.pomodoro += duration
+	default:
+		t.tracked += duration
+	}
+}
+
+func (t *totals) result() models.TimeTotals {
+	return models.TimeTotals{
+		Entries:         t.entries,
+		TrackedSeconds:  int64(t.tracked.Round(time.Second) / time.Second),
+		PomodoroSeconds: int64(t.pomodoro.Round(time.Second) / time.Second),
+		BreakSeconds:    int64(t.breaks.Round(time.Second) / time.Second),
+	}
+}
+
+// Build aggregates the time entries between from and to, grouped by task, folder, tag or period.
+// Entries are clipped to the range, and split between the periods they span when grouping by day,
+// week or month in the given location, weeks starting on Monday. When grouping by tag, the time of a
+// task with several tags is counted in each of them.
+func Build(entries []*models.TimeEntry, labels Labels, groupBy string, from, to time.Time, loc *time.Location) *models.TimeReport {
+	report := &models.TimeReport{
+		From:     from.In(loc),
+		To:       to.In(loc),
+		Timezone: loc.String(),
+		GroupBy:  groupBy,
+		Groups:   []models.TimeReportGroup{},
+	}
+
+	overall := &totals{}
+	groups := map[string]*totals{}
+	group := func(key string) *totals {
+		if groups[key] == nil {
+			groups[key] = &totals{}
+		}
+		return groups[key]
+	}
+
+	for _, entry := range entries {
+		start, end, ok := Bounds(entry)
+		if !ok {
+			continue
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		overall.entries++
+		overall.add(entry, end.Sub(start))
+
+		if isPeriod(groupBy) {
+			for periodStart := periodOf(groupBy, start.In(loc)); periodStart.Before(end); periodStart = nextPeriod(groupBy, periodStart) {
+				pieceStart, pieceEnd := maxTime(start, periodStart), minTime(end, nextPeriod(groupBy, periodStart))
+				if !pieceEnd.After(pieceStart) {
+					continue
+				}
+				g := group(periodStart.Format(time.RFC3339))
+				g.entries++
+				g.add(entry, pieceEnd.Sub(pieceStart))
+			}
+			continue
+		}
+
+		for _, key := range groupKeys(entry, labels, groupBy) {
+			g := group(key)
+			g.entries++
+			g.add(entry, end.Sub(start))
+		}
+	}
+	report.Totals = overall.result()
+
+	if isPeriod(groupBy) {
+		// every period of the range is listed, empty ones included
+		for periodStart := periodOf(groupBy, from.In(loc)); periodStart.Before(to); periodStart = nextPeriod(groupBy, periodStart) {
+			key := periodStart.Format(time.RFC3339)
+			g := group(key)
+			start := periodStart
+			report.Groups = append(report.Groups, models.TimeReportGroup{
+				Key:        key,
+				Label:      periodLabel(groupBy, periodStart),
+				Start:      &start,
+				TimeTotals: g.result(),
+			})
+		}
+		return report
+	}
+
+	for key, g := range groups {
+		report.Groups = append(report.Groups, models.TimeReportGroup{
+			Key:        key,
+			Label:      label(labels, groupBy, key),
+			TimeTotals: g.result(),
+		})
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.TrackedSeconds != b.TrackedSeconds {
+			return a.TrackedSeconds > b.TrackedSeconds
+		}
+		return a.Label < b.Label
+	})
+	return report
+}
+
+// WriteCSV writes the groups of the report followed by its totals as CSV
+func WriteCSV(w io.Writer, report *models.TimeReport) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{{"key", "label", "start", "entries", "tracked_seconds", "pomodoro_seconds", "break_seconds"}}
+	for _, group := range report.Groups {
+		start := ""
+		if group.Start != nil {
+			start = group.Start.Format(time.RFC3339)
+		}
+		rows = append(rows, csvRow(group.Key, group.Label, start, group.TimeTotals))
+	}
+	rows = append(rows, csvRow("total", "Total", report.From.Format(time.RFC3339), report.Totals))
+
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// Bounds returns when a time entry started and ended. The end is computed from the duration,
+// in seconds or as a Go duration, when the end date is missing or invalid.
+func Bounds(entry *models.TimeEntry) (time.Time, time.Time, bool) {
+	start, err := time.Parse(time.RFC3339, entry.StartDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	if end, err := time.Parse(time.RFC3339, entry.EndDate); err == nil && end.After(start) {
+		// the pauses of a timer are not tracked, they are removed from the end of the entry
+		if entry.PausedSeconds != nil && *entry.PausedSeconds > 0 {
+			end = end.Add(-time.Duration(*entry.PausedSeconds) * time.Second)
+		}
+		if end.After(start) {
+			return start, end, true
+		}
+		return time.Time{}, time.Time{}, false
+	}
+
+	if entry.Duration != nil {
+		if seconds, err := strconv.ParseFloat(*entry.Duration, 64); err == nil && seconds > 0 {
+			return start, start.Add(time.Duration(seconds * float64(time.Second))), true
+		}
+		if duration, err := time.ParseDuration(*entry.Duration); err == nil && duration > 0 {
+			return start, start.Add(duration), true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+func csvRow(key, label, start string, t models.TimeTotals) []string {
+	return []string{
+		key,
+		label,
+		start,
+		strconv.Itoa(t.Entries),
+		strconv.FormatInt(t.TrackedSeconds, 10),
+		strconv.FormatInt(t.PomodoroSeconds, 10),
+		strconv.FormatInt(t.BreakSeconds, 10),
+	}
+}
+
+// groupKeys returns the keys of the groups a time entry belongs to
+func groupKeys(entry *models.TimeEntry, labels Labels, groupBy string) []string {
+	var task *models.TaskEntity
+	if entry.TaskID != nil {
+		task = labels.Tasks[entry.TaskID.Hex()]
+	}
+
+	switch groupBy {
+	case models.ReportGroupFolder:
+		if task == nil || task.FolderID == nil {
+			return []string{""}
+		}
+		return []string{task.FolderID.Hex()}
+	case models.ReportGroupTag:
+		if task == nil || task.Tags == nil {
+			return []string{""}
+		}
+		keys := []string{}
+		seen := map[string]bool{}
+		for _, tag := range *task.Tags {
+			if tag == nil || tag.ID == nil || seen[tag.ID.Hex()] {
+				continue
+			}
+			seen[tag.ID.Hex()] = true
+			keys = append(keys, tag.ID.Hex())
+		}
+		if len(keys) == 0 {
+			return []string{""}
+		}
+		return keys
+	default:
+		if entry.TaskID == nil {
+			return []string{""}
+		}
+		return []string{entry.TaskID.Hex()}
+	}
+}
+
+func label(labels Labels, groupBy string, key string) string {
+	switch groupBy {
+	case models.ReportGroupFolder:
+		return labels.Folders[key]
+	case models.ReportGroupTag:
+		return labels.Tags[key]
+	default:
+		if task := labels.Tasks[key]; task != nil {
+			return task.Title
+		}
+		return ""
+	}
+}
+
+func isPeriod(groupBy string) bool {
+	return groupBy == models.ReportGroupDay || groupBy == models.ReportGroupWeek || groupBy == models.ReportGroupMonth
+}
+
+// periodOf returns the beginning of the period containing t, in the location of t
+func periodOf(groupBy string, t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch groupBy {
+	case models.ReportGroupWeek:
+		return day.AddDate(0, 0, -habitstats.WeekdayIndex(t))
+	case models.ReportGroupMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+func nextPeriod(groupBy string, start time.Time) time.Time {
+	switch groupBy {
+	case models.ReportGroupWeek:
+		return start.AddDate(0, 0, 7)
+	case models.ReportGroupMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+func periodLabel(groupBy string, start time.Time) string {
+	if groupBy == models.ReportGroupMonth {
+		return start.Format("2006-01")
+	}
+	return start.Format("2006-01-02")
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
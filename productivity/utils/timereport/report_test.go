@@ -0,0 +1,186 @@
+package timereport
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atomic-blend/backend/productivity/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func entry(taskID *primitive.ObjectID, start, end string, pomodoro, pomoBreak bool) *models.TimeEntry {
+	return &models.TimeEntry{
+		TaskID:    taskID,
+		StartDate: start,
+		EndDate:   end,
+		Pomodoro:  &pomodoro,
+		PomoBreak: &pomoBreak,
+	}
+}
+
+func TestBounds(t *testing.T) {
+	seconds := "1800"
+	goDuration := "1h30m"
+	invalid := "soon"
+	paused := int64(600)
+
+	tests := []struct {
+		name     string
+		entry    *models.TimeEntry
+		ok       bool
+		expected time.Duration
+	}{
+		{name: "start and end dates", entry: &models.TimeEntry{StartDate: "2025-06-02T10:00:00Z", EndDate: "2025-06-02T11:00:00Z"}, ok: true, expected: time.Hour},
+		{name: "duration in seconds", entry: &models.TimeEntry{StartDate: "2025-06-02T10:00:00Z", Duration: &seconds}, ok: true, expected: 30 * time.Minute},
+		{name: "go duration", entry: &models.TimeEntry{StartDate: "2025-06-02T10:00:00Z", Duration: &goDuration}, ok: true, expected: 90 * time.Minute},
+		{name: "timer with pauses", entry: &models.TimeEntry{StartDate: "2025-06-02T10:00:00Z", EndDate: "2025-06-02T11:00:00Z", PausedSeconds: &paused}, ok: true, expected: 50 * time.Minute},
+		{name: "invalid duration", entry: &models.TimeEntry{StartDate: "2025-06-02T10:00:00Z", Duration: &invalid}, ok: false},
+		{name: "invalid start", entry: &models.TimeEntry{StartDate: "yesterday", EndDate: "2025-06-02T11:00:00Z"}, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok := Bounds(tt.entry)
+			assert.Equal(t, tt.ok, ok)
+			if ok {
+				assert.Equal(t, tt.expected, end.Sub(start))
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	folderID := primitive.NewObjectID()
+	tagA := primitive.NewObjectID()
+	tagB := primitive.NewObjectID()
+	taskA := primitive.NewObjectID()
+	taskB := primitive.NewObjectID()
+	labels := Labels{
+		Tasks: map[string]*models.TaskEntity{
+			taskA.Hex(): {ID: taskA.Hex(), Title: "Write", FolderID: &folderID, Tags: &[]*models.Tag{{ID: &tagA}, {ID: &tagB}}},
+			taskB.Hex(): {ID: taskB.Hex(), Title: "Read"},
+		},
+		Folders: map[string]string{folderID.Hex(): "Work"},
+		Tags:    map[string]string{tagA.Hex(): "Deep", tagB.Hex(): "Writing"},
+	}
+
+	entries := []*models.TimeEntry{
+		// one hour of pomodoro and a 5 minutes break on task A
+		entry(&taskA, "2025-06-02T08:00:00Z", "2025-06-02T09:00:00Z", true, false),
+		entry(&taskA, "2025-06-02T09:00:00Z", "2025-06-02T09:05:00Z", true, true),
+		// 30 minutes on task B, across midnight in paris
+		entry(&taskB, "2025-06-02T21:45:00Z", "2025-06-02T22:15:00Z", false, false),
+		// 15 minutes without task
+		entry(nil, "2025-06-03T10:00:00Z", "2025-06-03T10:15:00Z", false, false),
+		// outside of the range
+		entry(&taskB, "2025-05-01T10:00:00Z", "2025-05-01T11:00:00Z", false, false),
+	}
+
+	from := time.Date(2025, 6, 2, 0, 0, 0, 0, paris)
+	to := time.Date(2025, 6, 4, 0, 0, 0, 0, paris)
+
+	tests := []struct {
+		name     string
+		groupBy  string
+		expected map[string]models.TimeTotals
+	}{
+		{
+			name:    "per task",
+			groupBy: models.ReportGroupTask,
+			expected: map[string]models.TimeTotals{
+				taskA.Hex(): {Entries: 2, TrackedSeconds: 3600, PomodoroSeconds: 3600, BreakSeconds: 300},
+				taskB.Hex(): {Entries: 1, TrackedSeconds: 1800},
+				"":          {Entries: 1, TrackedSeconds: 900},
+			},
+		},
+		{
+			name:    "per folder",
+			groupBy: models.ReportGroupFolder,
+			expected: map[string]models.TimeTotals{
+				folderID.Hex(): {Entries: 2, TrackedSeconds: 3600, PomodoroSeconds: 3600, BreakSeconds: 300},
+				"":             {Entries: 2, TrackedSeconds: 2700},
+			},
+		},
+		{
+			name:    "per tag",
+			groupBy: models.ReportGroupTag,
+			expected: map[string]models.TimeTotals{
+				tagA.Hex(): {Entries: 2, TrackedSeconds: 3600, PomodoroSeconds: 3600, BreakSeconds: 300},
+				tagB.Hex(): {Entries: 2, TrackedSeconds: 3600, PomodoroSeconds: 3600, BreakSeconds: 300},
+				"":         {Entries: 2, TrackedSeconds: 2700},
+			},
+		},
+		{
+			name:    "per day in paris",
+			groupBy: models.ReportGroupDay,
+			expected: map[string]models.TimeTotals{
+				"2025-06-02T00:00:00+02:00": {Entries: 3, TrackedSeconds: 3600 + 900, PomodoroSeconds: 3600, BreakSeconds: 300},
+				"2025-06-03T00:00:00+02:00": {Entries: 2, TrackedSeconds: 900 + 900},
+			},
+		},
+		{
+			name:    "per week",
+			groupBy: models.ReportGroupWeek,
+			expected: map[string]models.TimeTotals{
+				"2025-06-02T00:00:00+02:00": {Entries: 4, TrackedSeconds: 6300, PomodoroSeconds: 3600, BreakSeconds: 300},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Build(entries, labels, tt.groupBy, from, to, paris)
+
+			assert.Equal(t, models.TimeTotals{Entries: 4, TrackedSeconds: 6300, PomodoroSeconds: 3600, BreakSeconds: 300}, report.Totals)
+			assert.Equal(t, "Europe/Paris", report.Timezone)
+			require.Len(t, report.Groups, len(tt.expected))
+			for _, group := range report.Groups {
+				assert.Equal(t, tt.expected[group.Key], group.TimeTotals, group.Key)
+			}
+		})
+	}
+
+	t.Run("labels and order", func(t *testing.T) {
+		report := Build(entries, labels, models.ReportGroupTask, from, to, paris)
+		assert.Equal(t, "Write", report.Groups[0].Label)
+		assert.Equal(t, "Read", report.Groups[1].Label)
+		assert.Equal(t, "", report.Groups[2].Label)
+	})
+
+	t.Run("empty periods are listed", func(t *testing.T) {
+		report := Build(nil, labels, models.ReportGroupMonth, from, time.Date(2025, 9, 1, 0, 0, 0, 0, paris), paris)
+		require.Len(t, report.Groups, 3)
+		assert.Equal(t, "2025-06", report.Groups[0].Label)
+		assert.Equal(t, "2025-08", report.Groups[2].Label)
+		assert.Zero(t, report.Groups[1].Entries)
+	})
+}
+
+func TestWriteCSV(t *testing.T) {
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	report := &models.TimeReport{
+		From: start,
+		To:   start.AddDate(0, 0, 1),
+		Groups: []models.TimeReportGroup{
+			{Key: "2025-06-02T00:00:00Z", Label: "2025-06-02", Start: &start, TimeTotals: models.TimeTotals{Entries: 2, TrackedSeconds: 3600, PomodoroSeconds: 1500, BreakSeconds: 300}},
+		},
+		Totals: models.TimeTotals{Entries: 2, TrackedSeconds: 3600, PomodoroSeconds: 1500, BreakSeconds: 300},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, report))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "key,label,start,entries,tracked_seconds,pomodoro_seconds,break_seconds", lines[0])
+	assert.Equal(t, "2025-06-02T00:00:00Z,2025-06-02,2025-06-02T00:00:00Z,2,3600,1500,300", lines[1])
+	assert.Equal(t, "total,Total,2025-06-02T00:00:00Z,2,3600,1500,300", lines[2])
+}
@@ -0,0 +1,47 @@
+package timezone
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	authv1 "github.com/atomic-blend/backend/grpc/gen/auth/v1"
+	userv1 "github.com/atomic-blend/backend/grpc/gen/user/v1"
+	userclient "github.com/atomic-blend/backend/shared/grpc/user"
+
+	"github.com/rs/zerolog/log"
+)
+
+// FromDevices returns the timezone of a user from the timezones reported by their devices.
+// The first valid timezone is used, UTC is returned when no device reports one.
+func FromDevices(devices []*userv1.UserDevice) *time.Location {
+	for _, device := range devices {
+		name := device.GetDeviceTimezone()
+		if name == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// OfUser returns the timezone reported by the devices of a user, UTC if it is unknown or cannot be loaded
+func OfUser(ctx context.Context, userClient userclient.Interface, userID string) *time.Location {
+	if userClient == nil {
+		return time.UTC
+	}
+
+	resp, err := userClient.GetUserDevices(ctx, &connect.Request[userv1.GetUserDevicesRequest]{
+		Msg: &userv1.GetUserDevicesRequest{
+			User: &authv1.User{Id: userID},
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to get user devices for user: %s", userID)
+		return time.UTC
+	}
+
+	return FromDevices(resp.Msg.Devices)
+}
@@ -0,0 +1,41 @@
+package timezone
+
+import (
+	"testing"
+	"time"
+
+	userv1 "github.com/atomic-blend/backend/grpc/gen/user/v1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func device(timezone *string) *userv1.UserDevice {
+	return &userv1.UserDevice{DeviceId: "device", FcmToken: "token", DeviceTimezone: timezone}
+}
+
+func TestFromDevices(t *testing.T) {
+	paris := "Europe/Paris"
+	tokyo := "Asia/Tokyo"
+	invalid := "Not/AZone"
+	empty := ""
+
+	tests := []struct {
+		name     string
+		devices  []*userv1.UserDevice
+		expected string
+	}{
+		{"no devices", nil, "UTC"},
+		{"device without timezone", []*userv1.UserDevice{device(nil)}, "UTC"},
+		{"first device with a timezone", []*userv1.UserDevice{device(nil), device(&empty), device(&paris), device(&tokyo)}, "Europe/Paris"},
+		{"invalid timezone is skipped", []*userv1.UserDevice{device(&invalid), device(&tokyo)}, "Asia/Tokyo"},
+		{"only invalid timezones", []*userv1.UserDevice{device(&invalid)}, "UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FromDevices(tt.devices).String())
+		})
+	}
+
+	assert.Equal(t, time.UTC, FromDevices(nil))
+}
@@ -14,10 +14,11 @@ import (
 // DefaultConfig returns default configuration with environment variable support
 func DefaultConfig() *Config {
 	config := &Config{
-		BaseURL:    "http://localhost:11333",
-		Password:   "",
-		Timeout:    30 * time.Second,
-		MaxRetries: 3,
+		BaseURL:       "http://localhost:11333",
+		ControllerURL: "http://localhost:11334",
+		Password:      "",
+		Timeout:       30 * time.Second,
+		MaxRetries:    3,
 	}
 
 	// Override with environment variables if set
@@ -25,6 +26,10 @@ func DefaultConfig() *Config {
 		config.BaseURL = baseURL
 	}
 
+	if controllerURL := os.Getenv("RSPAMD_CONTROLLER_URL"); controllerURL != "" {
+		config.ControllerURL = controllerURL
+	}
+
 	if password := os.Getenv("RSPAMD_PASSWORD"); password != "" {
 		config.Password = password
 	}
@@ -55,9 +60,10 @@ func NewClient(config *Config) *Client {
 	}
 
 	return &Client{
-		httpClient: httpClient,
-		baseURL:    strings.TrimSuffix(config.BaseURL, "/"),
-		password:   config.Password,
+		httpClient:    httpClient,
+		baseURL:       strings.TrimSuffix(config.BaseURL, "/"),
+		controllerURL: strings.TrimSuffix(config.ControllerURL, "/"),
+		password:      config.Password,
 	}
 }
 
@@ -139,6 +145,51 @@ func (c *Client) CheckMessage(req *CheckRequest) (*CheckResponse, error) {
 	return &checkResp, nil
 }
 
+// LearnSpam trains the Rspamd classifier with a spam message
+func (c *Client) LearnSpam(message []byte) error {
+	return c.learn("/learnspam", message)
+}
+
+// LearnHam trains the Rspamd classifier with a legitimate message
+func (c *Client) LearnHam(message []byte) error {
+	return c.learn("/learnham", message)
+}
+
+// learn sends a message to a learning endpoint of the Rspamd controller
+func (c *Client) learn(endpoint string, message []byte) error {
+	if len(message) == 0 {
+		return fmt.Errorf("message cannot be empty")
+	}
+
+	httpReq, err := http.NewRequest("POST", c.controllerURL+endpoint, bytes.NewReader(message))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/octet-stream")
+	if c.password != "" {
+		httpReq.Header.Set("Password", c.password)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// a message already learnt is answered with 208 Already Reported
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusAlreadyReported {
+		return nil
+	}
+
+	var learnResp struct {
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&learnResp); err == nil && learnResp.Error != "" {
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, learnResp.Error)
+	}
+	return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+}
+
 // Ping sends a ping request to check if Rspamd is available
 func (c *Client) Ping() error {
 	resp, err := c.httpClient.Get(c.baseURL + "/ping")
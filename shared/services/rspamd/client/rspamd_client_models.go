@@ -16,6 +16,10 @@ type Client struct {
 	// Used to construct full URLs for API endpoints.
 	baseURL string
 
+	// controllerURL is the base URL of the Rspamd controller worker.
+	// Used to construct full URLs for the learning endpoints.
+	controllerURL string
+
 	// password is the authentication password for the Rspamd server.
 	// Sent as the "Password" header in requests when configured.
 	password string
@@ -233,6 +237,10 @@ type Config struct {
 	// Should include protocol (http/https) and port if not default.
 	BaseURL string
 
+	// ControllerURL is the base URL of the Rspamd controller worker, serving the learning endpoints.
+	// Should include protocol (http/https) and port if not default.
+	ControllerURL string
+
 	// Password is the authentication password for the Rspamd server.
 	// Required if Rspamd is configured with password authentication.
 	Password string
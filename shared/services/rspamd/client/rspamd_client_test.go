@@ -1,6 +1,9 @@
 package rspamdclient
 
 import (
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -132,3 +135,50 @@ func TestCheckRequestValidation(t *testing.T) {
 		t.Errorf("Expected error message '%s', got '%s'", expectedErr, err.Error())
 	}
 }
+
+func TestLearn(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.Header.Get("Password") != "testpass" {
+			t.Errorf("Expected the Password header to be 'testpass', got '%s'", r.Header.Get("Password"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		switch string(body) {
+		case "learnt":
+			w.Write([]byte(`{"success":true}`))
+		case "already learnt":
+			w.WriteHeader(http.StatusAlreadyReported)
+			w.Write([]byte(`{"error":"<id> has been already learned as spam, ignore it"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"learn failed"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: "http://localhost:11333", ControllerURL: server.URL + "/", Password: "testpass"})
+
+	if err := client.LearnSpam([]byte("learnt")); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := client.LearnHam([]byte("already learnt")); err != nil {
+		t.Errorf("Expected no error for a message already learnt, got %v", err)
+	}
+	if err := client.LearnHam([]byte("broken")); err == nil || err.Error() != "unexpected status code: 500: learn failed" {
+		t.Errorf("Expected the error of Rspamd, got %v", err)
+	}
+	if err := client.LearnSpam(nil); err == nil {
+		t.Error("Expected error when passing an empty message")
+	}
+
+	expectedPaths := []string{"/learnspam", "/learnham", "/learnham"}
+	if len(paths) != len(expectedPaths) {
+		t.Fatalf("Expected %d requests, got %d", len(expectedPaths), len(paths))
+	}
+	for i, path := range expectedPaths {
+		if paths[i] != path {
+			t.Errorf("Expected request %d to be sent to '%s', got '%s'", i, path, paths[i])
+		}
+	}
+}
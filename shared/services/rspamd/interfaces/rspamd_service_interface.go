@@ -6,6 +6,8 @@ import rspamdclient "github.com/atomic-blend/backend/shared/services/rspamd/clie
 // RspamdServiceInterface defines the interface for rspamd operations
 type RspamdServiceInterface interface {
 	CheckMessage(req *rspamdclient.CheckRequest) (*rspamdclient.CheckResponse, error)
+	LearnSpam(message []byte) error
+	LearnHam(message []byte) error
 	Ping() error
 	NewClient(config *rspamdclient.Config) *rspamdclient.Client
 	DefaultConfig() *rspamdclient.Config
@@ -24,6 +24,18 @@ func (m *MockRspamdService) CheckMessage(req *rspamdclient.CheckRequest) (*rspam
 	return args.Get(0).(*rspamdclient.CheckResponse), args.Error(1)
 }
 
+// LearnSpam trains Rspamd with a spam message
+func (m *MockRspamdService) LearnSpam(message []byte) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
+
+// LearnHam trains Rspamd with a legitimate message
+func (m *MockRspamdService) LearnHam(message []byte) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
+
 // Ping sends a ping request to check if Rspamd is available
 func (m *MockRspamdService) Ping() error {
 	args := m.Called()
@@ -32,6 +32,16 @@ func (r *Wrapper) CheckMessage(req *rspamdclient.CheckRequest) (*rspamdclient.Ch
 	return r.client.CheckMessage(req)
 }
 
+// LearnSpam trains Rspamd with a spam message
+func (r *Wrapper) LearnSpam(message []byte) error {
+	return r.client.LearnSpam(message)
+}
+
+// LearnHam trains Rspamd with a legitimate message
+func (r *Wrapper) LearnHam(message []byte) error {
+	return r.client.LearnHam(message)
+}
+
 // Ping sends a ping request to check if Rspamd is available
 func (r *Wrapper) Ping() error {
 	return r.client.Ping()
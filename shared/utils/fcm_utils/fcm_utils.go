@@ -9,7 +9,8 @@ import (
 )
 
 // SendMulticast sends a multicast message to multiple device tokens.
-func SendMulticast(ctx context.Context, client *fcm.Client, data map[string]string, deviceTokens []string) {
+// The response holds the result of each device token, in the same order.
+func SendMulticast(ctx context.Context, client *fcm.Client, data map[string]string, deviceTokens []string) (*messaging.BatchResponse, error) {
 	res, err := client.SendMulticast(
 		ctx,
 		&messaging.MulticastMessage{
@@ -33,6 +34,7 @@ func SendMulticast(ctx context.Context, client *fcm.Client, data map[string]stri
 	)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to send message to FCM")
+		return nil, err
 	}
 	log.Debug().Msgf("Sent message to %d devices, %d errors", res.SuccessCount, res.FailureCount)
 	for i, respo := range res.Responses {
@@ -42,4 +44,5 @@ func SendMulticast(ctx context.Context, client *fcm.Client, data map[string]stri
 			log.Debug().Msgf("Successfully sent message to device %s", deviceTokens[i])
 		}
 	}
+	return res, nil
 }